@@ -14,8 +14,10 @@ require (
 	github.com/rogpeppe/go-internal v1.9.0
 	github.com/sandertv/gophertunnel v1.33.0
 	github.com/sirupsen/logrus v1.9.0
+	github.com/yuin/gopher-lua v1.1.1
 	go.uber.org/atomic v1.10.0
 	golang.org/x/exp v0.0.0-20230206171751-46f607a40771
+	golang.org/x/sys v0.5.0
 	golang.org/x/text v0.7.0
 )
 
@@ -29,7 +31,6 @@ require (
 	golang.org/x/image v0.5.0 // indirect
 	golang.org/x/net v0.7.0 // indirect
 	golang.org/x/oauth2 v0.4.0 // indirect
-	golang.org/x/sys v0.5.0 // indirect
 	google.golang.org/appengine v1.6.7 // indirect
 	google.golang.org/protobuf v1.28.1 // indirect
 	gopkg.in/square/go-jose.v2 v2.6.0 // indirect
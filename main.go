@@ -3,37 +3,384 @@ package main
 import (
 	"fmt"
 	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/channel"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/console"
+	"github.com/df-mc/dragonfly/server/i18n"
+	"github.com/df-mc/dragonfly/server/logging"
+	"github.com/df-mc/dragonfly/server/metrics"
+	"github.com/df-mc/dragonfly/server/moderation"
+	"github.com/df-mc/dragonfly/server/permission"
+	"github.com/df-mc/dragonfly/server/player"
 	"github.com/df-mc/dragonfly/server/player/chat"
+	"github.com/df-mc/dragonfly/server/plugin"
+	"github.com/df-mc/dragonfly/server/query"
+	"github.com/df-mc/dragonfly/server/rcon"
+	"github.com/df-mc/dragonfly/server/reload"
+	"github.com/df-mc/dragonfly/server/script"
+	"github.com/df-mc/dragonfly/server/spawn"
+	"github.com/df-mc/dragonfly/server/timings"
+	"github.com/df-mc/dragonfly/server/webhook"
+	"github.com/df-mc/dragonfly/server/worldtime"
 	"github.com/pelletier/go-toml"
+	"github.com/sandertv/gophertunnel/minecraft/resource"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+	"net"
 	"os"
+	"reflect"
+	"strconv"
+	"time"
 )
 
 func main() {
 	log := logrus.New()
-	log.Formatter = &logrus.TextFormatter{ForceColors: true}
+	log.Formatter = console.Formatter{Formatter: &logrus.TextFormatter{ForceColors: true}}
 	log.Level = logrus.DebugLevel
 
-	chat.Global.Subscribe(chat.StdoutSubscriber{})
-
-	conf, err := readConfig(log)
+	uc, err := readConfig()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	logger, err := loadLogger(uc, log)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	conf, err := uc.Config(logger)
 	if err != nil {
 		log.Fatalln(err)
 	}
 
+	var mod *moderation.Manager
+	if uc.Moderation.Folder != "" {
+		if mod, err = loadModeration(uc.Moderation.Folder, uc.Moderation.Whitelist, logger.Subsystem("moderation")); err != nil {
+			log.Fatalln(err)
+		}
+		defer mod.Close()
+		conf.Allower = mod
+		chat.SetMuter(mod)
+	}
+
+	var m *metrics.Metrics
+	if uc.Metrics.Enabled {
+		if m, err = (metrics.Config{Address: uc.Metrics.Address, Log: logger.Subsystem("metrics")}).New(); err != nil {
+			log.Fatalln(err)
+		}
+		defer m.Close()
+		if conf.WorldProvider != nil {
+			conf.WorldProvider = m.Provider(conf.WorldProvider)
+		}
+	}
+
 	srv := conf.New()
 	srv.CloseOnProgramEnd()
+	if m != nil {
+		m.SetServer(srv)
+	}
+
+	c, err := console.Config{Server: srv, HistoryFile: "console_history"}.New()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer c.Close()
+	log.Out = c
+	chat.Global.Subscribe(c)
+	go func() {
+		if err := c.Run(); err != nil {
+			log.Errorf("console: %v", err)
+		}
+	}()
+
+	if uc.Translations.Folder != "" {
+		if err := i18n.Global.LoadDir(uc.Translations.Folder); err != nil {
+			log.Errorf("%v", err)
+		}
+	}
+
+	var perms *permission.Manager
+	if uc.Permissions.Folder != "" {
+		if perms, err = loadPermissions(uc.Permissions.Folder); err != nil {
+			log.Fatalln(err)
+		}
+		defer perms.Close()
+		cmd.SetPermissions(perms)
+	}
+
+	if mod != nil {
+		for _, command := range moderation.Commands(mod, srv, nil) {
+			cmd.Register(command)
+		}
+	}
+	for _, command := range logging.Commands(logger, nil) {
+		cmd.Register(command)
+	}
+	for _, command := range timings.Commands(srv, nil) {
+		cmd.Register(command)
+	}
+	for _, command := range spawn.Commands(nil) {
+		cmd.Register(command)
+	}
+	for _, command := range worldtime.Commands(nil) {
+		cmd.Register(command)
+	}
+	for _, command := range reload.Commands(loadReloads(uc, conf.Resources, srv, perms, logger), nil) {
+		cmd.Register(command)
+	}
+
+	channels, err := channel.Config{Server: srv, Log: logger.Subsystem("chat")}.New()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer channels.Close()
+	for _, command := range channel.Commands(channels, nil) {
+		cmd.Register(command)
+	}
+
+	plugins := plugin.Config{Log: logger.Subsystem("plugin"), Folder: uc.Plugins.Folder}.New(srv)
+	if err := plugins.Load(); err != nil {
+		log.Errorf("%v", err)
+	}
+	// The status function is set before enabling plugins, so that plugins may
+	// replace it with their own.
+	srv.SetStatusFunc(func(s *server.Status) {
+		for _, p := range plugins.Plugins() {
+			s.Plugins = append(s.Plugins, p.Name())
+		}
+	})
+	plugins.Enable()
+	defer plugins.Disable()
+
+	scripts := script.Config{Log: logger.Subsystem("script"), Folder: uc.Scripts.Folder}.New(srv)
+	if err := scripts.Load(); err != nil {
+		log.Errorf("%v", err)
+	}
+	defer scripts.Close()
+
+	var exp *webhook.Exporter
+	if uc.Webhook.URL != "" {
+		if exp, err = loadWebhook(uc, logger.Subsystem("webhook")); err != nil {
+			log.Fatalln(err)
+		}
+		defer exp.Close()
+	}
+
+	if uc.RCON.Enabled {
+		r, err := rcon.Config{
+			Server:         srv,
+			Address:        uc.RCON.Address,
+			Password:       uc.RCON.Password,
+			MaxConnections: uc.RCON.MaxConnections,
+			Groups:         uc.RCON.Groups,
+			Log:            logger.Subsystem("rcon"),
+		}.New()
+		if err != nil {
+			log.Fatalln(err)
+		}
+		defer r.Close()
+	}
+
+	if uc.Query.Enabled {
+		q, err := loadQuery(uc, srv, logger.Subsystem("query"))
+		if err != nil {
+			log.Fatalln(err)
+		}
+		defer q.Close()
+	}
 
 	srv.Listen()
-	for srv.Accept(nil) {
+	for srv.Accept(func(p *player.Player) {
+		scripts.HandleJoin(p)
+		channels.HandleJoin(p)
+		if exp != nil {
+			exp.HandleJoin(p)
+		}
+	}) {
+	}
+}
+
+// loadLogger creates a logging.Logger that writes to the logrus.Logger passed,
+// using the log levels in the config passed.
+func loadLogger(uc server.UserConfig, base *logrus.Logger) (*logging.Logger, error) {
+	if uc.Log.Level != "" {
+		level, err := logrus.ParseLevel(uc.Log.Level)
+		if err != nil {
+			return nil, fmt.Errorf("parse log level: %w", err)
+		}
+		base.SetLevel(level)
+	}
+	levels := make(map[string]logrus.Level, len(uc.Log.Subsystems))
+	for name, l := range uc.Log.Subsystems {
+		level, err := logrus.ParseLevel(l)
+		if err != nil {
+			return nil, fmt.Errorf("parse log level of %v: %w", name, err)
+		}
+		levels[name] = level
+	}
+	return logging.Config{Base: base, Levels: levels}.New(), nil
+}
+
+// loadReloads creates a reload.Manager with targets to reload the resource
+// packs, recipes, permissions, translations and config of the server. packs
+// are the resource packs that were loaded from the resources folder when the
+// server was started. perms may be nil if permissions are not loaded.
+func loadReloads(uc server.UserConfig, packs []*resource.Pack, srv *server.Server, perms *permission.Manager, logger *logging.Logger) *reload.Manager {
+	m := reload.New()
+	m.Register("resources", func() (reload.Report, error) {
+		loaded, err := uc.LoadResources()
+		if err != nil {
+			return reload.Report{}, err
+		}
+		// Only the packs from the resources folder are replaced: Packs added
+		// in other ways, such as by plugins, are kept.
+		var resources []*resource.Pack
+		for _, existing := range srv.Resources() {
+			if !slices.ContainsFunc(packs, func(pack *resource.Pack) bool {
+				return pack.UUID() == existing.UUID()
+			}) {
+				resources = append(resources, existing)
+			}
+		}
+		report := srv.ReloadResources(append(resources, loaded...)...)
+		if len(report.RestartRequired) == 0 {
+			packs = loaded
+		}
+		return report, nil
+	})
+	m.Register("recipes", func() (reload.Report, error) {
+		return srv.ReloadRecipes(), nil
+	})
+	if perms != nil {
+		m.Register("permissions", perms.Reload)
+	}
+	if uc.Translations.Folder != "" {
+		m.Register("translations", func() (reload.Report, error) {
+			if err := i18n.Global.LoadDir(uc.Translations.Folder); err != nil {
+				return reload.Report{}, err
+			}
+			return reload.Report{Applied: []string{"loaded translations from " + uc.Translations.Folder}}, nil
+		})
+	}
+	m.Register("config", func() (reload.Report, error) {
+		conf, err := readConfig()
+		if err != nil {
+			return reload.Report{}, err
+		}
+		report, err := reloadLogLevels(uc, conf, logger)
+		if err != nil {
+			return reload.Report{}, err
+		}
+		// Other sections of the config are only used when the server is
+		// started, so changes to them require a restart.
+		previous, current := reflect.ValueOf(uc), reflect.ValueOf(conf)
+		for i := 0; i < current.NumField(); i++ {
+			name := current.Type().Field(i).Name
+			if name != "Log" && !reflect.DeepEqual(previous.Field(i).Interface(), current.Field(i).Interface()) {
+				report.RestartRequired = append(report.RestartRequired, fmt.Sprintf("changed section %v", name))
+			}
+		}
+		uc.Log = conf.Log
+		return report, nil
+	})
+	return m
+}
+
+// reloadLogLevels applies the log levels of the config current to the
+// logging.Logger passed, if they changed since the config previous.
+func reloadLogLevels(previous, current server.UserConfig, logger *logging.Logger) (reload.Report, error) {
+	var report reload.Report
+	if current.Log.Level != previous.Log.Level {
+		// The default level of the logger is debug if no level is set.
+		level := logrus.DebugLevel
+		if current.Log.Level != "" {
+			var err error
+			if level, err = logrus.ParseLevel(current.Log.Level); err != nil {
+				return report, fmt.Errorf("parse log level: %w", err)
+			}
+		}
+		logger.SetDefaultLevel(level)
+		report.Applied = append(report.Applied, fmt.Sprintf("set log level to %v", level))
+	}
+	for name, l := range current.Log.Subsystems {
+		if previous.Log.Subsystems[name] == l {
+			continue
+		}
+		level, err := logrus.ParseLevel(l)
+		if err != nil {
+			return report, fmt.Errorf("parse log level of %v: %w", name, err)
+		}
+		logger.SetLevel(name, level)
+		report.Applied = append(report.Applied, fmt.Sprintf("set log level of %v to %v", name, level))
+	}
+	for name := range previous.Log.Subsystems {
+		if _, ok := current.Log.Subsystems[name]; !ok {
+			logger.ResetLevel(name)
+			report.Applied = append(report.Applied, fmt.Sprintf("reset log level of %v", name))
+		}
+	}
+	return report, nil
+}
+
+// loadPermissions loads the permission groups and player permissions stored in
+// the folder passed.
+func loadPermissions(folder string) (*permission.Manager, error) {
+	prov, err := permission.NewJSONProvider(folder)
+	if err != nil {
+		return nil, err
+	}
+	return permission.New(prov)
+}
+
+// loadModeration loads the punishments, bans and whitelist stored in the folder
+// passed. If whitelist is true, the whitelist is enabled.
+func loadModeration(folder string, whitelist bool, log server.Logger) (*moderation.Manager, error) {
+	prov, err := moderation.NewJSONProvider(folder)
+	if err != nil {
+		return nil, err
+	}
+	m, err := moderation.New(prov, log)
+	if err != nil {
+		return nil, err
+	}
+	if whitelist && !m.WhitelistEnabled() {
+		if err := m.SetWhitelistEnabled(true); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// loadWebhook creates a webhook.Exporter that posts the events selected in the
+// config passed to its webhook URL.
+func loadWebhook(uc server.UserConfig, log server.Logger) (*webhook.Exporter, error) {
+	kinds := make([]webhook.Kind, 0, len(uc.Webhook.Events))
+	for _, k := range uc.Webhook.Events {
+		kinds = append(kinds, webhook.Kind(k))
+	}
+	return webhook.Config{
+		Sink:          webhook.HTTPSink{URL: uc.Webhook.URL},
+		Log:           log,
+		Kinds:         kinds,
+		BatchSize:     uc.Webhook.BatchSize,
+		FlushInterval: time.Duration(uc.Webhook.FlushInterval) * time.Second,
+	}.New()
+}
+
+// loadQuery creates a query.Listener that answers queries on the query
+// address in the config passed, advertising the network address of the server.
+func loadQuery(uc server.UserConfig, srv *server.Server, log server.Logger) (*query.Listener, error) {
+	host, port, err := net.SplitHostPort(uc.Network.Address)
+	if err != nil {
+		return nil, fmt.Errorf("parse network address: %w", err)
 	}
+	p, _ := strconv.Atoi(port)
+	return query.Config{Server: srv, Address: uc.Query.Address, HostIP: host, HostPort: p, Log: log}.New()
 }
 
 // readConfig reads the configuration from the config.toml file, or creates the
 // file if it does not yet exist.
-func readConfig(log server.Logger) (server.Config, error) {
+func readConfig() (server.UserConfig, error) {
 	c := server.DefaultConfig()
-	var zero server.Config
+	var zero server.UserConfig
 	if _, err := os.Stat("config.toml"); os.IsNotExist(err) {
 		data, err := toml.Marshal(c)
 		if err != nil {
@@ -42,7 +389,7 @@ func readConfig(log server.Logger) (server.Config, error) {
 		if err := os.WriteFile("config.toml", data, 0644); err != nil {
 			return zero, fmt.Errorf("create default config: %v", err)
 		}
-		return c.Config(log)
+		return c, nil
 	}
 	data, err := os.ReadFile("config.toml")
 	if err != nil {
@@ -51,5 +398,5 @@ func readConfig(log server.Logger) (server.Config, error) {
 	if err := toml.Unmarshal(data, &c); err != nil {
 		return zero, fmt.Errorf("decode config: %v", err)
 	}
-	return c.Config(log)
+	return c, nil
 }
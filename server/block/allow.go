@@ -0,0 +1,18 @@
+package block
+
+// Allow is a block used to mark regions in which players may build. Players that are not in a game mode with a
+// creative inventory may only place and break blocks above an Allow block if no Deny block is closer to the
+// position below it.
+type Allow struct {
+	solid
+}
+
+// EncodeItem ...
+func (Allow) EncodeItem() (name string, meta int16) {
+	return "minecraft:allow", 0
+}
+
+// EncodeBlock ...
+func (Allow) EncodeBlock() (string, map[string]any) {
+	return "minecraft:allow", nil
+}
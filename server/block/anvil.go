@@ -52,7 +52,7 @@ func (a Anvil) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.W
 
 // NeighbourUpdateTick ...
 func (a Anvil) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
-	a.fall(a, pos, w)
+	Fall(a, pos, w)
 }
 
 // Damage returns the damage per block fallen of the anvil and the maximum damage the anvil can deal.
@@ -0,0 +1,196 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/model"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// Bed is a block that allows players to sleep through the night and sets the position they respawn at. A bed is two
+// blocks long and consists of a foot and a head part. Beds explode when used outside the overworld.
+type Bed struct {
+	transparent
+	sourceWaterDisplacer
+
+	// Colour is the colour of the bed.
+	Colour item.Colour
+	// Facing is the direction from the foot of the bed towards its head.
+	Facing cube.Direction
+	// Head is true if the block is the head part of the bed.
+	Head bool
+	// Occupied is true if a player is sleeping in the bed. It is only set for the head part of the bed.
+	Occupied bool
+}
+
+// BedUser is an item.User that is able to sleep in a Bed and have its respawn position set by it, such as a player.
+type BedUser interface {
+	item.User
+	world.Sleeper
+	// SetBedSpawn sets the position of the bed that the BedUser respawns at.
+	SetBedSpawn(pos cube.Pos)
+	// BedSpawn returns the position of the bed that the BedUser respawns at and true, if it has one.
+	BedSpawn() (cube.Pos, bool)
+	// Messaget sends a translated message to the BedUser.
+	Messaget(key string, a ...any)
+}
+
+// MaxCount always returns 1.
+func (Bed) MaxCount() int {
+	return 1
+}
+
+// Model ...
+func (Bed) Model() world.BlockModel {
+	return model.Bed{}
+}
+
+// SideClosed ...
+func (Bed) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
+// BreakInfo ...
+func (b Bed) BreakInfo() BreakInfo {
+	return newBreakInfo(0.2, alwaysHarvestable, nothingEffective, oneOf(Bed{Colour: b.Colour}))
+}
+
+// UseOnBlock places the bed with its foot at the position clicked and its head in the direction that the user is
+// facing.
+func (b Bed) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, _, used := firstReplaceable(w, pos, face, b)
+	if !used {
+		return false
+	}
+	b.Facing = user.Rotation().Direction()
+	headPos := pos.Side(b.Facing.Face())
+	if !replaceableWith(w, headPos, b) || !bedSupported(pos, w) || !bedSupported(headPos, w) {
+		return false
+	}
+
+	place(w, pos, b, user, ctx)
+	if _, ok := w.Block(pos).(Bed); ok {
+		// Only place the head if the foot was placed, so that only one bed is subtracted from the count.
+		b.Head = true
+		w.SetBlock(headPos, b, nil)
+	}
+	return placed(ctx)
+}
+
+// bedSupported checks if a bed part may be placed at the position passed, which requires a solid block below it.
+func bedSupported(pos cube.Pos, w *world.World) bool {
+	below := pos.Side(cube.FaceDown)
+	return w.Block(below).Model().FaceSolid(below, cube.FaceUp, w)
+}
+
+// NeighbourUpdateTick removes the bed part if the other part of the bed was removed.
+func (b Bed) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if _, _, ok := b.other(pos, w); !ok {
+		w.SetBlock(pos, nil, nil)
+		w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: b})
+	}
+}
+
+// Activate sets the respawn position of the user to the bed and makes it sleep in the bed if it is night or if it
+// is thundering. Beds explode when activated outside the overworld.
+func (b Bed) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, _ *item.UseContext) bool {
+	s, ok := u.(BedUser)
+	if !ok {
+		return false
+	}
+	if w.Dimension() != world.Overworld {
+		w.SetBlock(pos, nil, nil)
+		ExplosionConfig{Size: 5, SpawnFire: true}.Explode(w, pos.Vec3Centre())
+		return true
+	}
+	head, headPos, ok := b.head(pos, w)
+	if !ok {
+		return false
+	}
+	_, footPos, _ := head.other(headPos, w)
+	userPos := s.Position()
+	if userPos.Sub(headPos.Vec3Centre()).Len() > 3 && userPos.Sub(footPos.Vec3Centre()).Len() > 3 {
+		s.Messaget("tile.bed.tooFar")
+		return true
+	}
+	if spawn, ok := s.BedSpawn(); !ok || spawn != headPos {
+		s.SetBedSpawn(headPos)
+		s.Messaget("tile.bed.respawnSet")
+	}
+	if t := w.DayTime(); (t < 12542 || t > 23459) && !w.ThunderingAt(headPos) {
+		s.Messaget("tile.bed.noSleep")
+		return true
+	}
+	if head.Occupied && bedOccupied(headPos, w) {
+		s.Messaget("tile.bed.occupied")
+		return true
+	}
+	s.Sleep(headPos)
+	return true
+}
+
+// bedOccupied checks if any world.Sleeper in the world is sleeping in the bed with its head at the position passed.
+func bedOccupied(headPos cube.Pos, w *world.World) bool {
+	for _, e := range w.Entities() {
+		if s, ok := e.(world.Sleeper); ok {
+			if pos, sleeping := s.Sleeping(); sleeping && pos == headPos {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// other returns the other part of the bed at the position passed and its position. False is returned if the other
+// part is missing.
+func (b Bed) other(pos cube.Pos, w *world.World) (Bed, cube.Pos, bool) {
+	face := b.Facing.Face()
+	if b.Head {
+		face = face.Opposite()
+	}
+	otherPos := pos.Side(face)
+	other, ok := w.Block(otherPos).(Bed)
+	return other, otherPos, ok && other.Head != b.Head && other.Facing == b.Facing
+}
+
+// head returns the head part of the bed at the position passed and its position. False is returned if the bed has
+// no head part.
+func (b Bed) head(pos cube.Pos, w *world.World) (Bed, cube.Pos, bool) {
+	if b.Head {
+		return b, pos, true
+	}
+	return b.other(pos, w)
+}
+
+// EncodeItem ...
+func (b Bed) EncodeItem() (name string, meta int16) {
+	return "minecraft:bed", int16(b.Colour.Uint8())
+}
+
+// EncodeBlock ...
+func (b Bed) EncodeBlock() (name string, properties map[string]any) {
+	return "minecraft:bed", map[string]any{"direction": int32(horizontalDirection(b.Facing)), "head_piece_bit": b.Head, "occupied_bit": b.Occupied}
+}
+
+// EncodeNBT ...
+func (b Bed) EncodeNBT() map[string]any {
+	return map[string]any{"id": "Bed", "color": b.Colour.Uint8()}
+}
+
+// DecodeNBT ...
+func (b Bed) DecodeNBT(data map[string]any) any {
+	b.Colour = item.Colours()[nbtconv.Uint8(data, "color")&0xf]
+	return b
+}
+
+// allBeds returns all possible bed states.
+func allBeds() (beds []world.Block) {
+	for _, d := range cube.Directions() {
+		beds = append(beds, Bed{Facing: d}, Bed{Facing: d, Occupied: true})
+		beds = append(beds, Bed{Facing: d, Head: true}, Bed{Facing: d, Head: true, Occupied: true})
+	}
+	return
+}
@@ -10,6 +10,11 @@ type Bedrock struct {
 	InfiniteBurning bool
 }
 
+// InfinitelyBurning ...
+func (b Bedrock) InfinitelyBurning() bool {
+	return b.InfiniteBurning
+}
+
 // EncodeItem ...
 func (Bedrock) EncodeItem() (name string, meta int16) {
 	return "minecraft:bedrock", 0
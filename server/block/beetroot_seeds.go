@@ -25,7 +25,7 @@ func (b BeetrootSeeds) BoneMeal(pos cube.Pos, w *world.World) bool {
 	if b.Growth == 7 {
 		return false
 	}
-	if rand.Float64() < 0.75 {
+	if w.Rand().Float64() < 0.75 {
 		b.Growth++
 		w.SetBlock(pos, b, nil)
 		return true
@@ -50,11 +50,11 @@ func (b BeetrootSeeds) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w
 
 // BreakInfo ...
 func (b BeetrootSeeds) BreakInfo() BreakInfo {
-	return newBreakInfo(0, alwaysHarvestable, nothingEffective, func(item.Tool, []item.Enchantment) []item.Stack {
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, func(_ item.Tool, _ []item.Enchantment, r *rand.Rand) []item.Stack {
 		if b.Growth < 7 {
 			return []item.Stack{item.NewStack(b, 1)}
 		}
-		return []item.Stack{item.NewStack(item.Beetroot{}, 1), item.NewStack(b, rand.Intn(4)+1)}
+		return []item.Stack{item.NewStack(item.Beetroot{}, 1), item.NewStack(b, r.Intn(4)+1)}
 	})
 }
 
@@ -20,9 +20,9 @@ type Blackstone struct {
 func (b Blackstone) BreakInfo() BreakInfo {
 	drops := oneOf(b)
 	if b.Type == GildedBlackstone() {
-		drops = func(item.Tool, []item.Enchantment) []item.Stack {
-			if rand.Float64() < 0.1 {
-				return []item.Stack{item.NewStack(item.GoldNugget{}, rand.Intn(4)+2)}
+		drops = func(_ item.Tool, _ []item.Enchantment, r *rand.Rand) []item.Stack {
+			if r.Float64() < 0.1 {
+				return []item.Stack{item.NewStack(item.GoldNugget{}, r.Intn(4)+2)}
 			}
 			return []item.Stack{item.NewStack(b, 1)}
 		}
@@ -7,7 +7,6 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
-	"math/rand"
 	"time"
 )
 
@@ -35,10 +34,10 @@ func NewBlastFurnace(face cube.Face) BlastFurnace {
 
 // Tick is called to check if the blast furnace should update and start or stop smelting.
 func (b BlastFurnace) Tick(_ int64, pos cube.Pos, w *world.World) {
-	if b.Lit && rand.Float64() <= 0.016 { // Every three or so seconds.
+	if b.Lit && w.Rand().Float64() <= 0.016 { // Every three or so seconds.
 		w.PlaySound(pos.Vec3Centre(), sound.BlastFurnaceCrackle{})
 	}
-	if lit := b.smelter.tickSmelting(time.Second*5, time.Millisecond*200, b.Lit, func(i item.SmeltInfo) bool {
+	if lit := b.smelter.tickSmelting(time.Second*5, time.Millisecond*200, b.Lit, w.Rand(), func(i item.SmeltInfo) bool {
 		return i.Ores
 	}); b.Lit != lit {
 		b.Lit = lit
@@ -5,9 +5,9 @@ import (
 	"github.com/df-mc/dragonfly/server/block/model"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
-	"math/rand"
 	"time"
 )
 
@@ -73,6 +73,24 @@ type EntityInsider interface {
 	EntityInside(pos cube.Pos, w *world.World, e world.Entity)
 }
 
+// Solidity represents a block that specifies if it is solid, instead of its solidity being derived from its model.
+// Blocks that do not implement Solidity are solid if their model is model.Solid. Blocks such as grindstones may only
+// be attached to solid blocks.
+type Solidity interface {
+	// Solid returns true if the block is solid.
+	Solid() bool
+}
+
+// solidBlock checks if the block passed is solid. If the block implements Solidity, its Solid method is used.
+// Otherwise, the block is solid if its model is model.Solid.
+func solidBlock(b world.Block) bool {
+	if s, ok := b.(Solidity); ok {
+		return s.Solid()
+	}
+	_, ok := b.Model().(model.Solid)
+	return ok
+}
+
 // Frictional represents a block that may have a custom friction value, friction is used for entity drag when the
 // entity is on ground. If a block does not implement this interface, it should be assumed that its friction is 0.6.
 type Frictional interface {
@@ -140,8 +158,34 @@ func place(w *world.World, pos cube.Pos, b world.Block, user item.User, ctx *ite
 		placer.PlaceBlock(pos, b, ctx)
 		return
 	}
+	PlaceWithFeedback(w, pos, b, nil)
+}
+
+// PlaceWithFeedback places the block passed at a position in the world and plays the block place sound to all
+// viewers of the position. If src is non-nil and is able to swing its arm, such as a player, its arm is swung
+// as well, providing the same feedback as a block placed by a player.
+func PlaceWithFeedback(w *world.World, pos cube.Pos, b world.Block, src world.Entity) {
 	w.SetBlock(pos, b, nil)
 	w.PlaySound(pos.Vec3(), sound.BlockPlace{Block: b})
+	swingArm(src)
+}
+
+// BreakWithFeedback breaks the block at a position in the world, replacing it with air and showing the break
+// particles of the block to all viewers of the position. If src is non-nil and is able to swing its arm, such as a
+// player, its arm is swung as well, providing the same feedback as a block broken by a player.
+// BreakWithFeedback does not drop any items or experience.
+func BreakWithFeedback(w *world.World, pos cube.Pos, src world.Entity) {
+	b := w.Block(pos)
+	swingArm(src)
+	w.SetBlock(pos, nil, nil)
+	w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: b})
+}
+
+// swingArm swings the arm of the entity passed if it is able to do so.
+func swingArm(src world.Entity) {
+	if s, ok := src.(interface{ SwingArm() }); ok {
+		s.SwingArm()
+	}
 }
 
 // horizontalDirection returns the horizontal direction of the given direction. This is a legacy type still used in
@@ -190,6 +234,16 @@ func (transparent) LightDiffusionLevel() uint8 {
 	return 0
 }
 
+// GravityAffected represents a block that is affected by gravity, such as sand or gravel. Blocks implementing
+// GravityAffected may call Fall, typically in their NeighbourUpdateTick method, to turn into a falling block
+// entity when the block below them is removed.
+type GravityAffected interface {
+	world.Block
+	// Solidifies returns whether the falling block can solidify at the position it is currently in. If so,
+	// the block will immediately stop falling.
+	Solidifies(pos cube.Pos, w *world.World) bool
+}
+
 // gravityAffected is a struct that may be embedded for blocks affected by gravity.
 type gravityAffected struct{}
 
@@ -198,8 +252,10 @@ func (g gravityAffected) Solidifies(cube.Pos, *world.World) bool {
 	return false
 }
 
-// fall spawns a falling block entity at the given position.
-func (g gravityAffected) fall(b world.Block, pos cube.Pos, w *world.World) {
+// Fall makes the GravityAffected block passed fall from the position passed if the block below it is air or a
+// liquid. The block is removed and a falling block entity is spawned in its place, which is placed again as a
+// block once it lands.
+func Fall(b GravityAffected, pos cube.Pos, w *world.World) {
 	_, air := w.Block(pos.Side(cube.FaceDown)).Model().(model.Empty)
 	_, liquid := w.Liquid(pos.Side(cube.FaceDown))
 	if air || liquid {
@@ -214,6 +270,19 @@ type Flammable interface {
 	FlammabilityInfo() FlammabilityInfo
 }
 
+// Ignitable represents a block that reacts to being set on fire, such as TNT. Ignitable blocks are ignited instead of
+// burning away when fire spreads to them or when they are hit by a burning projectile.
+type Ignitable interface {
+	// Ignite ignites the block at the position passed. Ignite returns true if the block was ignited.
+	Ignite(pos cube.Pos, w *world.World) bool
+}
+
+// InfiniteBurner represents a block on top of which fire burns forever, such as netherrack.
+type InfiniteBurner interface {
+	// InfinitelyBurning returns true if fire on top of the block burns forever.
+	InfinitelyBurning() bool
+}
+
 // FlammabilityInfo contains values related to block behaviors involving fire.
 type FlammabilityInfo struct {
 	// Encouragement is the chance a block will catch on fire during attempted fire spread.
@@ -259,7 +328,7 @@ type flammableEntity interface {
 // dropItem ...
 func dropItem(w *world.World, it item.Stack, pos mgl64.Vec3) {
 	create := w.EntityRegistry().Config().Item
-	w.AddEntity(create(it, pos, mgl64.Vec3{rand.Float64()*0.2 - 0.1, 0.2, rand.Float64()*0.2 - 0.1}))
+	w.AddEntity(create(it, pos, mgl64.Vec3{w.Rand().Float64()*0.2 - 0.1, 0.2, w.Rand().Float64()*0.2 - 0.1}))
 }
 
 // bass is a struct that may be embedded for blocks that create a bass sound.
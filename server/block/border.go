@@ -0,0 +1,128 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/model"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// Border is an indestructible, wall-like block used to mark the boundaries of a region. It connects to other
+// borders, walls and solid faces similarly to a Wall.
+type Border struct {
+	transparent
+	sourceWaterDisplacer
+
+	// NorthConnection is the type of connection in the north direction of the post.
+	NorthConnection WallConnectionType
+	// EastConnection is the type of connection in the east direction of the post.
+	EastConnection WallConnectionType
+	// SouthConnection is the type of connection in the south direction of the post.
+	SouthConnection WallConnectionType
+	// WestConnection is the type of connection in the west direction of the post.
+	WestConnection WallConnectionType
+	// Post is if the border is extended to the full height of a block or not.
+	Post bool
+}
+
+// EncodeItem ...
+func (Border) EncodeItem() (string, int16) {
+	return "minecraft:border_block", 0
+}
+
+// EncodeBlock ...
+func (b Border) EncodeBlock() (string, map[string]any) {
+	return "minecraft:border_block", map[string]any{
+		"wall_connection_type_north": b.NorthConnection.String(),
+		"wall_connection_type_east":  b.EastConnection.String(),
+		"wall_connection_type_south": b.SouthConnection.String(),
+		"wall_connection_type_west":  b.WestConnection.String(),
+		"wall_post_bit":              boolByte(b.Post),
+	}
+}
+
+// Model ...
+func (b Border) Model() world.BlockModel {
+	return model.Wall{
+		NorthConnection: b.NorthConnection.Height(),
+		EastConnection:  b.EastConnection.Height(),
+		SouthConnection: b.SouthConnection.Height(),
+		WestConnection:  b.WestConnection.Height(),
+		Post:            b.Post,
+	}
+}
+
+// NeighbourUpdateTick ...
+func (b Border) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if updated, ok := b.calculateConnections(w, pos); ok {
+		w.SetBlock(pos, updated, nil)
+	}
+}
+
+// UseOnBlock ...
+func (b Border) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	pos, _, used = firstReplaceable(w, pos, face, b)
+	if !used {
+		return
+	}
+	b, _ = b.calculateConnections(w, pos)
+	place(w, pos, b, user, ctx)
+	return placed(ctx)
+}
+
+// SideClosed ...
+func (Border) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
+// calculateConnections calculates the connections and post bit of the border at a given position in a world. The
+// updated border is returned, together with a bool that is true if any changes were made.
+func (b Border) calculateConnections(w *world.World, pos cube.Pos) (Border, bool) {
+	updated := b
+	connections := 0
+	for _, face := range cube.HorizontalFaces() {
+		sidePos := pos.Side(face)
+		side := w.Block(sidePos)
+
+		connected := side.Model().FaceSolid(sidePos, face.Opposite(), w)
+		switch side.(type) {
+		case Border, Wall:
+			connected = true
+		}
+		connection := NoWallConnection()
+		if connected {
+			connection = ShortWallConnection()
+			connections++
+		}
+		switch face {
+		case cube.FaceNorth:
+			updated.NorthConnection = connection
+		case cube.FaceEast:
+			updated.EastConnection = connection
+		case cube.FaceSouth:
+			updated.SouthConnection = connection
+		case cube.FaceWest:
+			updated.WestConnection = connection
+		}
+	}
+	straight := (updated.NorthConnection != NoWallConnection() && updated.SouthConnection != NoWallConnection() && connections == 2) ||
+		(updated.EastConnection != NoWallConnection() && updated.WestConnection != NoWallConnection() && connections == 2)
+	updated.Post = !straight
+	return updated, updated != b
+}
+
+// allBorders returns a list of all border block states.
+func allBorders() (borders []world.Block) {
+	for _, north := range WallConnectionTypes() {
+		for _, east := range WallConnectionTypes() {
+			for _, south := range WallConnectionTypes() {
+				for _, west := range WallConnectionTypes() {
+					borders = append(borders, Border{NorthConnection: north, EastConnection: east, SouthConnection: south, WestConnection: west})
+					borders = append(borders, Border{NorthConnection: north, EastConnection: east, SouthConnection: south, WestConnection: west, Post: true})
+				}
+			}
+		}
+	}
+	return
+}
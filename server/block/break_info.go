@@ -37,11 +37,10 @@ func BreakDuration(b world.Block, i item.Stack) time.Duration {
 	if info.Effective(t) {
 		eff := t.BaseMiningEfficiency(b)
 		if e, ok := i.Enchantment(enchantment.Efficiency{}); ok {
-			breakTime += (enchantment.Efficiency{}).Addend(e.Level())
+			eff += (enchantment.Efficiency{}).Addend(e.Level())
 		}
 		breakTime /= eff
 	}
-	// TODO: Account for haste etc here.
 	timeInTicksAccurate := math.Round(breakTime/0.05) * 0.05
 
 	return (time.Duration(math.Round(timeInTicksAccurate*20)) * time.Second) / 20
@@ -63,13 +62,11 @@ func BreaksInstantly(b world.Block, i item.Stack) bool {
 		return false
 	}
 
-	// TODO: Account for haste etc here.
 	efficiencyVal := 0.0
 	if e, ok := i.Enchantment(enchantment.Efficiency{}); ok {
 		efficiencyVal += (enchantment.Efficiency{}).Addend(e.Level())
 	}
-	hasteVal := 0.0
-	return (t.BaseMiningEfficiency(b)+efficiencyVal)*hasteVal >= hardness*30
+	return t.BaseMiningEfficiency(b)+efficiencyVal >= hardness*30
 }
 
 // BreakInfo is a struct returned by every block. It holds information on block breaking related data, such as
@@ -84,7 +81,7 @@ type BreakInfo struct {
 	// than with an empty hand.
 	Effective func(t item.Tool) bool
 	// Drops is a function called to get the drops of the block if it is broken using the item passed.
-	Drops func(t item.Tool, enchantments []item.Enchantment) []item.Stack
+	Drops func(t item.Tool, enchantments []item.Enchantment, r *rand.Rand) []item.Stack
 	// BreakHandler is called after the block has broken.
 	BreakHandler func(pos cube.Pos, w *world.World, u item.User)
 	// XPDrops is the range of XP a block can drop when broken.
@@ -96,7 +93,7 @@ type BreakInfo struct {
 
 // newBreakInfo creates a BreakInfo struct with the properties passed. The XPDrops field is 0 by default. The blast
 // resistance is set to the block's hardness*5 by default.
-func newBreakInfo(hardness float64, harvestable func(item.Tool) bool, effective func(item.Tool) bool, drops func(item.Tool, []item.Enchantment) []item.Stack) BreakInfo {
+func newBreakInfo(hardness float64, harvestable func(item.Tool) bool, effective func(item.Tool) bool, drops func(item.Tool, []item.Enchantment, *rand.Rand) []item.Stack) BreakInfo {
 	return BreakInfo{
 		Hardness:        hardness,
 		BlastResistance: hardness * 5,
@@ -127,11 +124,11 @@ func (b BreakInfo) withBreakHandler(handler func(pos cube.Pos, w *world.World, u
 // XPDropRange holds the min & max XP drop amounts of blocks.
 type XPDropRange [2]int
 
-// RandomValue returns a random XP value that falls within the drop range.
-func (r XPDropRange) RandomValue() int {
+// RandomValue returns a random XP value that falls within the drop range, using the rand.Rand passed.
+func (r XPDropRange) RandomValue(rnd *rand.Rand) int {
 	diff := r[1] - r[0]
 	// Add one because it's a [r[0], r[1]] interval.
-	return rand.Intn(diff+1) + r[0]
+	return rnd.Intn(diff+1) + r[0]
 }
 
 // pickaxeEffective is a convenience function for blocks that are effectively mined with a pickaxe.
@@ -177,16 +174,24 @@ var neverHarvestable = func(t item.Tool) bool {
 // pickaxeHarvestable is a convenience function for blocks that are harvestable using any kind of pickaxe.
 var pickaxeHarvestable = pickaxeEffective
 
+// pickaxeTierHarvestable returns a function for blocks that are harvestable using a pickaxe of the tier passed or
+// higher, such as diamond ore, which requires at least an iron pickaxe.
+func pickaxeTierHarvestable(tier item.ToolTier) func(t item.Tool) bool {
+	return func(t item.Tool) bool {
+		return t.ToolType() == item.TypePickaxe && t.HarvestLevel() >= tier.HarvestLevel
+	}
+}
+
 // simpleDrops returns a drops function that returns the items passed.
-func simpleDrops(s ...item.Stack) func(item.Tool, []item.Enchantment) []item.Stack {
-	return func(item.Tool, []item.Enchantment) []item.Stack {
+func simpleDrops(s ...item.Stack) func(item.Tool, []item.Enchantment, *rand.Rand) []item.Stack {
+	return func(item.Tool, []item.Enchantment, *rand.Rand) []item.Stack {
 		return s
 	}
 }
 
 // oneOf returns a drops function that returns one of each of the item types passed.
-func oneOf(i ...world.Item) func(item.Tool, []item.Enchantment) []item.Stack {
-	return func(item.Tool, []item.Enchantment) []item.Stack {
+func oneOf(i ...world.Item) func(item.Tool, []item.Enchantment, *rand.Rand) []item.Stack {
+	return func(item.Tool, []item.Enchantment, *rand.Rand) []item.Stack {
 		var s []item.Stack
 		for _, it := range i {
 			s = append(s, item.NewStack(it, 1))
@@ -207,8 +212,8 @@ func hasSilkTouch(enchantments []item.Enchantment) bool {
 
 // silkTouchOneOf returns a drop function that returns 1x of the silk touch drop when silk touch exists, or 1x of the
 // normal drop when it does not.
-func silkTouchOneOf(normal, silkTouch world.Item) func(item.Tool, []item.Enchantment) []item.Stack {
-	return func(t item.Tool, enchantments []item.Enchantment) []item.Stack {
+func silkTouchOneOf(normal, silkTouch world.Item) func(item.Tool, []item.Enchantment, *rand.Rand) []item.Stack {
+	return func(t item.Tool, enchantments []item.Enchantment, r *rand.Rand) []item.Stack {
 		if hasSilkTouch(enchantments) {
 			return []item.Stack{item.NewStack(silkTouch, 1)}
 		}
@@ -218,8 +223,8 @@ func silkTouchOneOf(normal, silkTouch world.Item) func(item.Tool, []item.Enchant
 
 // silkTouchDrop returns a drop function that returns the silk touch drop when silk touch exists, or the
 // normal drop when it does not.
-func silkTouchDrop(normal, silkTouch item.Stack) func(item.Tool, []item.Enchantment) []item.Stack {
-	return func(t item.Tool, enchantments []item.Enchantment) []item.Stack {
+func silkTouchDrop(normal, silkTouch item.Stack) func(item.Tool, []item.Enchantment, *rand.Rand) []item.Stack {
+	return func(t item.Tool, enchantments []item.Enchantment, r *rand.Rand) []item.Stack {
 		if hasSilkTouch(enchantments) {
 			return []item.Stack{silkTouch}
 		}
@@ -227,9 +232,42 @@ func silkTouchDrop(normal, silkTouch item.Stack) func(item.Tool, []item.Enchantm
 	}
 }
 
+// fortuneLevel returns the level of the fortune enchantment in the enchantments passed, or 0 if not present.
+func fortuneLevel(enchantments []item.Enchantment) int {
+	for _, enchant := range enchantments {
+		if _, ok := enchant.Type().(enchantment.Fortune); ok {
+			return enchant.Level()
+		}
+	}
+	return 0
+}
+
+// silkTouchRandomDrop returns a drop function that returns the silk touch drop when silk touch exists, or between
+// min and max of the normal drop when it does not.
+func silkTouchRandomDrop(normal world.Item, min, max int, silkTouch item.Stack) func(item.Tool, []item.Enchantment, *rand.Rand) []item.Stack {
+	return func(t item.Tool, enchantments []item.Enchantment, r *rand.Rand) []item.Stack {
+		if hasSilkTouch(enchantments) {
+			return []item.Stack{silkTouch}
+		}
+		return []item.Stack{item.NewStack(normal, min+r.Intn(max-min+1))}
+	}
+}
+
+// oreDrops returns a drop function for ores. With silk touch, the ore itself is dropped. Otherwise, between min and
+// max of the drop are dropped, multiplied according to the level of fortune on the tool.
+func oreDrops(drop, ore world.Item, min, max int) func(item.Tool, []item.Enchantment, *rand.Rand) []item.Stack {
+	return func(t item.Tool, enchantments []item.Enchantment, r *rand.Rand) []item.Stack {
+		if hasSilkTouch(enchantments) {
+			return []item.Stack{item.NewStack(ore, 1)}
+		}
+		count := min + r.Intn(max-min+1)
+		return []item.Stack{item.NewStack(drop, (enchantment.Fortune{}).DropCount(fortuneLevel(enchantments), count, r))}
+	}
+}
+
 // silkTouchOnlyDrop returns a drop function that returns the drop when silk touch exists.
-func silkTouchOnlyDrop(it world.Item) func(t item.Tool, enchantments []item.Enchantment) []item.Stack {
-	return func(t item.Tool, enchantments []item.Enchantment) []item.Stack {
+func silkTouchOnlyDrop(it world.Item) func(t item.Tool, enchantments []item.Enchantment, r *rand.Rand) []item.Stack {
+	return func(t item.Tool, enchantments []item.Enchantment, r *rand.Rand) []item.Stack {
 		if hasSilkTouch(enchantments) {
 			return []item.Stack{item.NewStack(it, 1)}
 		}
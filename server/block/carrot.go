@@ -42,7 +42,7 @@ func (c Carrot) BoneMeal(pos cube.Pos, w *world.World) bool {
 	if c.Growth == 7 {
 		return false
 	}
-	c.Growth = min(c.Growth+rand.Intn(4)+2, 7)
+	c.Growth = min(c.Growth+w.Rand().Intn(4)+2, 7)
 	w.SetBlock(pos, c, nil)
 	return true
 }
@@ -64,11 +64,11 @@ func (c Carrot) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.
 
 // BreakInfo ...
 func (c Carrot) BreakInfo() BreakInfo {
-	return newBreakInfo(0, alwaysHarvestable, nothingEffective, func(item.Tool, []item.Enchantment) []item.Stack {
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, func(_ item.Tool, _ []item.Enchantment, r *rand.Rand) []item.Stack {
 		if c.Growth < 7 {
 			return []item.Stack{item.NewStack(c, 1)}
 		}
-		return []item.Stack{item.NewStack(c, rand.Intn(4)+2)}
+		return []item.Stack{item.NewStack(c, r.Intn(4)+2)}
 	})
 }
 
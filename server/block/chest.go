@@ -32,6 +32,21 @@ type Chest struct {
 	inventory *inventory.Inventory
 	viewerMu  *sync.RWMutex
 	viewers   map[ContainerViewer]struct{}
+
+	// paired specifies if the chest is paired with the chest at pairX, pairZ. pairLead is true if the inventory
+	// of the chest makes up the first half of the inventory of the double chest. pair is nil if the chest was
+	// loaded paired, but was not yet linked with the other chest.
+	paired, pairLead bool
+	pairX, pairZ     int
+	pair             *chestPair
+}
+
+// chestPair holds the inventory and viewers shared by two chests paired into a double chest. Changes to the
+// inventory are written to the inventories of both chests, so that each chest may be saved separately.
+type chestPair struct {
+	inventory *inventory.Inventory
+	viewerMu  sync.RWMutex
+	viewers   map[ContainerViewer]struct{}
 }
 
 // NewChest creates a new initialised chest. The inventory is properly initialised.
@@ -54,9 +69,29 @@ func NewChest() Chest {
 // Inventory returns the inventory of the chest. The size of the inventory will be 27 or 54, depending on
 // whether the chest is single or double.
 func (c Chest) Inventory() *inventory.Inventory {
+	if c.pair != nil {
+		return c.pair.inventory
+	}
 	return c.inventory
 }
 
+// SingleInventory returns the inventory of only this chest, which always has a size of 27, even if the chest is
+// paired with another chest. SingleInventory is, for example, used to find the items dropped when one half of a
+// double chest is broken.
+func (c Chest) SingleInventory() *inventory.Inventory {
+	return c.inventory
+}
+
+// Paired checks if the chest is paired with another chest to form a double chest.
+func (c Chest) Paired() bool {
+	return c.paired
+}
+
+// pairPos returns the position of the chest that the chest is paired with.
+func (c Chest) pairPos(pos cube.Pos) cube.Pos {
+	return cube.Pos{c.pairX, pos[1], c.pairZ}
+}
+
 // WithName returns the chest after applying a specific name to the block.
 func (c Chest) WithName(a ...any) world.Item {
 	c.CustomName = strings.TrimSuffix(fmt.Sprintln(a...), "\n")
@@ -70,40 +105,63 @@ func (Chest) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
 
 // open opens the chest, displaying the animation and playing a sound.
 func (c Chest) open(w *world.World, pos cube.Pos) {
-	for _, v := range w.Viewers(pos.Vec3()) {
-		v.ViewBlockAction(pos, OpenAction{})
+	for _, p := range c.positions(pos) {
+		for _, v := range w.Viewers(p.Vec3()) {
+			v.ViewBlockAction(p, OpenAction{})
+		}
 	}
 	w.PlaySound(pos.Vec3Centre(), sound.ChestOpen{})
 }
 
 // close closes the chest, displaying the animation and playing a sound.
 func (c Chest) close(w *world.World, pos cube.Pos) {
-	for _, v := range w.Viewers(pos.Vec3()) {
-		v.ViewBlockAction(pos, CloseAction{})
+	for _, p := range c.positions(pos) {
+		for _, v := range w.Viewers(p.Vec3()) {
+			v.ViewBlockAction(p, CloseAction{})
+		}
 	}
 	w.PlaySound(pos.Vec3Centre(), sound.ChestClose{})
 }
 
+// positions returns the position of the chest and, if it is paired, the position of the chest it is paired with.
+func (c Chest) positions(pos cube.Pos) []cube.Pos {
+	if c.pair != nil {
+		return []cube.Pos{pos, c.pairPos(pos)}
+	}
+	return []cube.Pos{pos}
+}
+
+// viewerSet returns the mutex and the set of viewers of the chest, which are shared with the chest it is paired
+// with, if any.
+func (c Chest) viewerSet() (*sync.RWMutex, map[ContainerViewer]struct{}) {
+	if c.pair != nil {
+		return &c.pair.viewerMu, c.pair.viewers
+	}
+	return c.viewerMu, c.viewers
+}
+
 // AddViewer adds a viewer to the chest, so that it is updated whenever the inventory of the chest is changed.
 func (c Chest) AddViewer(v ContainerViewer, w *world.World, pos cube.Pos) {
-	c.viewerMu.Lock()
-	defer c.viewerMu.Unlock()
-	if len(c.viewers) == 0 {
+	mu, viewers := c.viewerSet()
+	mu.Lock()
+	defer mu.Unlock()
+	if len(viewers) == 0 {
 		c.open(w, pos)
 	}
-	c.viewers[v] = struct{}{}
+	viewers[v] = struct{}{}
 }
 
 // RemoveViewer removes a viewer from the chest, so that slot updates in the inventory are no longer sent to
 // it.
 func (c Chest) RemoveViewer(v ContainerViewer, w *world.World, pos cube.Pos) {
-	c.viewerMu.Lock()
-	defer c.viewerMu.Unlock()
-	if len(c.viewers) == 0 {
+	mu, viewers := c.viewerSet()
+	mu.Lock()
+	defer mu.Unlock()
+	if len(viewers) == 0 {
 		return
 	}
-	delete(c.viewers, v)
-	if len(c.viewers) == 0 {
+	delete(viewers, v)
+	if len(viewers) == 0 {
 		c.close(w, pos)
 	}
 }
@@ -111,9 +169,14 @@ func (c Chest) RemoveViewer(v ContainerViewer, w *world.World, pos cube.Pos) {
 // Activate ...
 func (c Chest) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, _ *item.UseContext) bool {
 	if opener, ok := u.(ContainerOpener); ok {
-		if d, ok := w.Block(pos.Side(cube.FaceUp)).(LightDiffuser); ok && d.LightDiffusionLevel() <= 2 {
-			opener.OpenBlockContainer(pos)
+		//noinspection GoAssignmentToReceiver
+		c = c.link(w, pos)
+		for _, p := range c.positions(pos) {
+			if d, ok := w.Block(p.Side(cube.FaceUp)).(LightDiffuser); !ok || d.LightDiffusionLevel() > 2 {
+				return true
+			}
 		}
+		opener.OpenBlockContainer(pos)
 		return true
 	}
 	return false
@@ -129,13 +192,117 @@ func (c Chest) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.W
 	c = NewChest()
 	c.Facing = user.Rotation().Direction().Opposite()
 
+	// A chest placed next to a chest facing the same direction that is not yet paired is paired with it, with the
+	// chest on the left being preferred.
+	var pair Chest
+	var paired bool
+	for _, dir := range []cube.Direction{c.Facing.RotateLeft(), c.Facing.RotateRight()} {
+		if ch, p, ok := c.pairWith(w, pos, pos.Side(dir.Face())); ok {
+			//noinspection GoAssignmentToReceiver
+			c, pair, paired = ch, p, true
+			break
+		}
+	}
 	place(w, pos, c, user, ctx)
+	if paired && placed(ctx) {
+		w.SetBlock(c.pairPos(pos), pair, nil)
+	}
 	return placed(ctx)
 }
 
+// pairWith pairs the chest at the position passed with the chest at pairPos, returning both chests after pairing
+// them. The chests are not changed in the world. False is returned if there is no chest at pairPos that the chest
+// may be paired with: It must face the same direction, must not be paired with another chest and must not be
+// viewed by anyone.
+func (c Chest) pairWith(w *world.World, pos, pairPos cube.Pos) (Chest, Chest, bool) {
+	pair, ok := w.Block(pairPos).(Chest)
+	if !ok || pair.Facing != c.Facing || pair.pair != nil || (pair.paired && pair.pairPos(pairPos) != pos) {
+		return c, pair, false
+	}
+	if pos.Side(c.Facing.RotateLeft().Face()) != pairPos && pos.Side(c.Facing.RotateRight().Face()) != pairPos {
+		return c, pair, false
+	}
+	if c.viewed() || pair.viewed() {
+		return c, pair, false
+	}
+	// The chest on the right, seen from the front, makes up the first half of the double chest.
+	left, right := c.inventory, pair.inventory
+	c.pairLead = pos.Side(c.Facing.RotateRight().Face()) != pairPos
+	if !c.pairLead {
+		left, right = right, left
+	}
+	pair.pairLead = !c.pairLead
+
+	p := &chestPair{viewers: make(map[ContainerViewer]struct{}, 1)}
+	p.inventory = inventory.New(54, func(slot int, _, it item.Stack) {
+		if slot < 27 {
+			_ = left.SetItem(slot, it)
+		} else {
+			_ = right.SetItem(slot-27, it)
+		}
+		p.viewerMu.RLock()
+		defer p.viewerMu.RUnlock()
+		for viewer := range p.viewers {
+			viewer.ViewSlotChange(slot, it)
+		}
+	})
+	for slot, it := range append(left.Slots(), right.Slots()...) {
+		_ = p.inventory.SetItem(slot, it)
+	}
+	c.paired, c.pairX, c.pairZ, c.pair = true, pairPos[0], pairPos[2], p
+	pair.paired, pair.pairX, pair.pairZ, pair.pair = true, pos[0], pos[2], p
+	return c, pair, true
+}
+
+// viewed checks if the chest currently has any viewers.
+func (c Chest) viewed() bool {
+	mu, viewers := c.viewerSet()
+	mu.RLock()
+	defer mu.RUnlock()
+	return len(viewers) != 0
+}
+
+// link makes sure the pairing of the chest at the position passed is valid. A chest loaded from NBT is only linked
+// with the chest it is paired with once it is used, and a chest of which the pair was removed without it being
+// broken, for example by using World.SetBlock, is unpaired. The chest returned is the chest as stored in the world
+// after linking.
+func (c Chest) link(w *world.World, pos cube.Pos) Chest {
+	if !c.paired {
+		return c
+	}
+	pairPos := c.pairPos(pos)
+	if c.pair != nil {
+		if pair, ok := w.Block(pairPos).(Chest); ok && pair.pair == c.pair {
+			return c
+		}
+	} else if ch, pair, ok := c.pairWith(w, pos, pairPos); ok {
+		w.SetBlock(pos, ch, nil)
+		w.SetBlock(pairPos, pair, nil)
+		return ch
+	}
+	c.paired, c.pair = false, nil
+	w.SetBlock(pos, c, nil)
+	return c
+}
+
+// unpair unpairs the chest that the chest at the position passed is paired with, if any, so that it becomes a
+// single chest again. It is called when the chest is broken.
+func (c Chest) unpair(w *world.World, pos cube.Pos) {
+	if !c.paired {
+		return
+	}
+	pairPos := c.pairPos(pos)
+	if pair, ok := w.Block(pairPos).(Chest); ok && pair.paired && pair.pairPos(pairPos) == pos {
+		pair.paired, pair.pair = false, nil
+		w.SetBlock(pairPos, pair, nil)
+	}
+}
+
 // BreakInfo ...
 func (c Chest) BreakInfo() BreakInfo {
-	return newBreakInfo(2.5, alwaysHarvestable, axeEffective, oneOf(c))
+	return newBreakInfo(2.5, alwaysHarvestable, axeEffective, oneOf(c)).withBreakHandler(func(pos cube.Pos, w *world.World, _ item.User) {
+		c.unpair(w, pos)
+	})
 }
 
 // FuelInfo ...
@@ -155,6 +322,10 @@ func (c Chest) DecodeNBT(data map[string]any) any {
 	c = NewChest()
 	c.Facing = facing
 	c.CustomName = nbtconv.String(data, "CustomName")
+	if _, ok := data["pairx"]; ok {
+		c.paired, c.pairLead = true, nbtconv.Bool(data, "pairlead")
+		c.pairX, c.pairZ = int(nbtconv.Int32(data, "pairx")), int(nbtconv.Int32(data, "pairz"))
+	}
 	nbtconv.InvFromNBT(c.inventory, nbtconv.Slice(data, "Items"))
 	return c
 }
@@ -174,6 +345,10 @@ func (c Chest) EncodeNBT() map[string]any {
 	if c.CustomName != "" {
 		m["CustomName"] = c.CustomName
 	}
+	if c.paired {
+		m["pairlead"] = boolByte(c.pairLead)
+		m["pairx"], m["pairz"] = int32(c.pairX), int32(c.pairZ)
+	}
 	return m
 }
 
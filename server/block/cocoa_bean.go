@@ -87,9 +87,9 @@ func (c CocoaBean) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
 
 // BreakInfo ...
 func (c CocoaBean) BreakInfo() BreakInfo {
-	return newBreakInfo(0.2, alwaysHarvestable, axeEffective, func(item.Tool, []item.Enchantment) []item.Stack {
+	return newBreakInfo(0.2, alwaysHarvestable, axeEffective, func(_ item.Tool, _ []item.Enchantment, r *rand.Rand) []item.Stack {
 		if c.Age == 2 {
-			return []item.Stack{item.NewStack(c, rand.Intn(2)+2)}
+			return []item.Stack{item.NewStack(c, r.Intn(2)+2)}
 		}
 		return []item.Stack{item.NewStack(c, 1)}
 	}).withBlastResistance(15)
@@ -31,7 +31,7 @@ func (c ConcretePowder) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
 			return
 		}
 	}
-	c.fall(c, pos, w)
+	Fall(c, pos, w)
 }
 
 // BreakInfo ...
@@ -2,7 +2,6 @@ package block
 
 import (
 	"github.com/df-mc/dragonfly/server/item"
-	"math/rand"
 )
 
 // CopperOre is a rare mineral block found underground.
@@ -16,9 +15,7 @@ type CopperOre struct {
 
 // BreakInfo ...
 func (c CopperOre) BreakInfo() BreakInfo {
-	return newBreakInfo(c.Type.Hardness(), func(t item.Tool) bool {
-		return t.ToolType() == item.TypePickaxe && t.HarvestLevel() >= item.ToolTierStone.HarvestLevel
-	}, pickaxeEffective, silkTouchDrop(item.NewStack(item.RawCopper{}, rand.Intn(4)+2), item.NewStack(c, 1))).withBlastResistance(9)
+	return newBreakInfo(c.Type.Hardness(), pickaxeTierHarvestable(item.ToolTierStone), pickaxeEffective, oreDrops(item.RawCopper{}, c, 2, 5)).withBlastResistance(9)
 }
 
 // SmeltInfo ...
@@ -29,7 +29,7 @@ func (c crop) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
 		b := w.Block(pos)
 		w.SetBlock(pos, nil, nil)
 		if breakable, ok := b.(Breakable); ok {
-			for _, drop := range breakable.BreakInfo().Drops(item.ToolNone{}, []item.Enchantment{}) {
+			for _, drop := range breakable.BreakInfo().Drops(item.ToolNone{}, nil, w.Rand()) {
 				dropItem(w, drop, pos.Vec3Centre())
 			}
 		}
@@ -0,0 +1,104 @@
+// Package customblock holds the client-side properties of custom blocks: blocks that are not part of vanilla
+// Minecraft, but are registered by the server using world.RegisterBlock and sent to clients when they join.
+package customblock
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item/category"
+	"github.com/go-gl/mathgl/mgl64"
+	"image"
+)
+
+// Properties holds the properties of a custom block that are sent to the client, such as its geometry, textures and
+// collision box. Zero values of fields are not sent, in which case the client uses the default of a full block.
+type Properties struct {
+	// Category is the category of the creative inventory that the block is listed under. If left empty, the block
+	// is listed under category.Construction.
+	Category category.Category
+	// Geometry is the identifier of the geometry of the block, such as 'geometry.pedestal'. If left empty, the
+	// block is rendered as a full cube ('minecraft:geometry.full_block').
+	Geometry string
+	// GeometryData holds the JSON geometry file that defines Geometry. If not empty, it is added to the resource
+	// pack that the server builds automatically. It may be left empty if the geometry is part of another pack.
+	GeometryData []byte
+	// Textures holds the Material of every face or bone of the geometry of the block, by name of the face or
+	// bone. The Material under '*' applies to all faces of the block that have no Material of their own. Faces of
+	// full blocks are 'up', 'down', 'north', 'east', 'south' and 'west'.
+	Textures map[string]Material
+	// CollisionBox is the box that entities collide with on the client side, relative to the block's position. It
+	// should match the BBox returned by the Model of the block. If zero, the block has a full collision box.
+	CollisionBox cube.BBox
+	// NoCollision specifies if entities pass through the block on the client side. If true, CollisionBox is not
+	// used.
+	NoCollision bool
+	// SelectionBox is the box that players aim at to interact with the block, relative to the block's position. If
+	// zero, the block has a full selection box.
+	SelectionBox cube.BBox
+	// Rotation is the rotation of the geometry of the block around the X, Y and Z axes in degrees. Rotations must
+	// be multiples of 90 degrees.
+	Rotation cube.Pos
+	// Translation is the translation of the geometry of the block in blocks.
+	Translation mgl64.Vec3
+	// Scale is the scale of the geometry of the block. If zero, the geometry is not scaled.
+	Scale mgl64.Vec3
+	// Permutations holds properties that only apply to some states of the block, such as a different rotation
+	// for every direction that the block may face.
+	Permutations []Permutation
+}
+
+// Permutation holds Properties of a custom block that apply only if its Condition holds. The Category and
+// GeometryData of the Properties are not used.
+type Permutation struct {
+	Properties
+	// Condition is the Molang expression that must evaluate to true for the Properties to apply, such as
+	// "q.block_state('direction') == 1".
+	Condition string
+}
+
+// Material holds the texture of a face or bone of a custom block and the way that it is rendered.
+type Material struct {
+	// Texture is the image used as texture. It is added to the resource pack that the server builds automatically.
+	Texture image.Image
+	// RenderMethod is the way that the Texture is rendered.
+	RenderMethod RenderMethod
+	// FaceDimming specifies if the texture is shaded based on the direction that the face is facing.
+	FaceDimming bool
+	// AmbientOcclusion specifies if the texture is shaded by the blocks around it.
+	AmbientOcclusion bool
+}
+
+// NewMaterial returns a Material with the texture and RenderMethod passed, which has face dimming and ambient
+// occlusion enabled, like most vanilla blocks.
+func NewMaterial(texture image.Image, method RenderMethod) Material {
+	return Material{Texture: texture, RenderMethod: method, FaceDimming: true, AmbientOcclusion: true}
+}
+
+// RenderMethod is the way that the texture of a Material is rendered.
+type RenderMethod uint8
+
+const (
+	// RenderMethodOpaque renders textures without transparency, like stone.
+	RenderMethodOpaque RenderMethod = iota
+	// RenderMethodAlphaTest renders pixels of textures as either fully transparent or fully opaque, like leaves.
+	// Faces are rendered on both sides.
+	RenderMethodAlphaTest
+	// RenderMethodBlend renders textures with semi-transparent pixels, like stained glass.
+	RenderMethodBlend
+	// RenderMethodDoubleSided renders textures without transparency on both sides of a face.
+	RenderMethodDoubleSided
+)
+
+// String returns the name of the RenderMethod as used by the client.
+func (m RenderMethod) String() string {
+	switch m {
+	case RenderMethodOpaque:
+		return "opaque"
+	case RenderMethodAlphaTest:
+		return "alpha_test"
+	case RenderMethodBlend:
+		return "blend"
+	case RenderMethodDoubleSided:
+		return "double_sided"
+	}
+	panic("should never happen")
+}
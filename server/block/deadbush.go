@@ -22,7 +22,7 @@ func (d DeadBush) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
 	if !supportsVegetation(d, w.Block(pos.Side(cube.FaceDown))) {
 		w.SetBlock(pos, nil, nil)
 		w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: d})
-		if amount := rand.Intn(3); amount != 0 {
+		if amount := w.Rand().Intn(3); amount != 0 {
 			dropItem(w, item.NewStack(item.Stick{}, amount), pos.Vec3Centre())
 		}
 	}
@@ -59,11 +59,11 @@ func (d DeadBush) FlammabilityInfo() FlammabilityInfo {
 
 // BreakInfo ...
 func (d DeadBush) BreakInfo() BreakInfo {
-	return newBreakInfo(0, alwaysHarvestable, nothingEffective, func(t item.Tool, enchantments []item.Enchantment) []item.Stack {
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, func(t item.Tool, enchantments []item.Enchantment, r *rand.Rand) []item.Stack {
 		if t.ToolType() == item.TypeShears {
 			return []item.Stack{item.NewStack(d, 1)}
 		}
-		if amount := rand.Intn(3); amount != 0 {
+		if amount := r.Intn(3); amount != 0 {
 			return []item.Stack{item.NewStack(item.Stick{}, amount)}
 		}
 		return nil
@@ -0,0 +1,38 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Deny is a block used to mark regions in which players may not build. Players that are not in a game mode with
+// a creative inventory are unable to place and break blocks above a Deny block, unless an Allow block is closer
+// to the position below it.
+type Deny struct {
+	solid
+}
+
+// EncodeItem ...
+func (Deny) EncodeItem() (name string, meta int16) {
+	return "minecraft:deny", 0
+}
+
+// EncodeBlock ...
+func (Deny) EncodeBlock() (string, map[string]any) {
+	return "minecraft:deny", nil
+}
+
+// BuildAllowed checks if a block may be placed or broken at the position passed by a player without a creative
+// inventory. The column below the position is searched for the closest Allow or Deny block: Building is
+// disallowed if a Deny block is found first and allowed otherwise.
+func BuildAllowed(pos cube.Pos, w *world.World) bool {
+	for y := pos[1] - 1; y >= w.Range()[0]; y-- {
+		switch w.Block(cube.Pos{pos[0], y, pos[2]}).(type) {
+		case Allow:
+			return true
+		case Deny:
+			return false
+		}
+	}
+	return true
+}
@@ -1,8 +1,6 @@
 package block
 
 import (
-	"math/rand"
-
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/world"
@@ -19,7 +17,7 @@ type DragonEgg struct {
 
 // NeighbourUpdateTick ...
 func (d DragonEgg) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
-	d.fall(d, pos, w)
+	Fall(d, pos, w)
 }
 
 // SideClosed ...
@@ -30,7 +28,7 @@ func (d DragonEgg) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
 // teleport ...
 func (d DragonEgg) teleport(pos cube.Pos, w *world.World) {
 	for i := 0; i < 1000; i++ {
-		newPos := pos.Add(cube.Pos{rand.Intn(31) - 15, max(w.Range()[0]-pos.Y(), min(w.Range()[1]-pos.Y(), rand.Intn(15)-7)), rand.Intn(31) - 15})
+		newPos := pos.Add(cube.Pos{w.Rand().Intn(31) - 15, max(w.Range()[0]-pos.Y(), min(w.Range()[1]-pos.Y(), w.Rand().Intn(15)-7)), w.Rand().Intn(31) - 15})
 
 		if _, ok := w.Block(newPos).(Air); ok {
 			w.SetBlock(newPos, d, nil)
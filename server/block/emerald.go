@@ -17,9 +17,7 @@ func (e Emerald) Instrument() sound.Instrument {
 
 // BreakInfo ...
 func (e Emerald) BreakInfo() BreakInfo {
-	return newBreakInfo(5, func(t item.Tool) bool {
-		return t.ToolType() == item.TypePickaxe && t.HarvestLevel() >= item.ToolTierIron.HarvestLevel
-	}, pickaxeEffective, oneOf(e)).withBlastResistance(30)
+	return newBreakInfo(5, pickaxeTierHarvestable(item.ToolTierIron), pickaxeEffective, oneOf(e)).withBlastResistance(30)
 }
 
 // PowersBeacon ...
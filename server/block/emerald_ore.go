@@ -15,9 +15,7 @@ type EmeraldOre struct {
 
 // BreakInfo ...
 func (e EmeraldOre) BreakInfo() BreakInfo {
-	i := newBreakInfo(e.Type.Hardness(), func(t item.Tool) bool {
-		return t.ToolType() == item.TypePickaxe && t.HarvestLevel() >= item.ToolTierIron.HarvestLevel
-	}, pickaxeEffective, silkTouchOneOf(item.Emerald{}, e)).withXPDropRange(3, 7)
+	i := newBreakInfo(e.Type.Hardness(), pickaxeTierHarvestable(item.ToolTierIron), pickaxeEffective, oreDrops(item.Emerald{}, e, 1, 1)).withXPDropRange(3, 7)
 	if e.Type == DeepslateOre() {
 		i = i.withBlastResistance(15)
 	}
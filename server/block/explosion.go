@@ -3,6 +3,7 @@ package block
 import (
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/block/cube/trace"
+	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/particle"
@@ -10,7 +11,6 @@ import (
 	"github.com/go-gl/mathgl/mgl64"
 	"math"
 	"math/rand"
-	"time"
 )
 
 // ExplosionConfig is the configuration for an explosion. The world, position, size, sound, particle, and more can all
@@ -18,7 +18,7 @@ import (
 type ExplosionConfig struct {
 	// Size is the size of the explosion, it is effectively the radius which entities/blocks will be affected within.
 	Size float64
-	// Rand is the source to use for the explosion "randomness".
+	// Rand is the source to use for the explosion "randomness". If nil, the rand.Rand of the world is used.
 	Rand rand.Source
 	// SpawnFire will cause the explosion to randomly start fires in 1/3 of all destroyed air blocks that are
 	// above opaque blocks.
@@ -26,6 +26,9 @@ type ExplosionConfig struct {
 	// DisableItemDrops, when set to true, will prevent any item entities from dropping as a result of blocks being
 	// destroyed.
 	DisableItemDrops bool
+	// DisableBlockDamage, when set to true, will prevent the explosion from destroying any blocks. Entities are still
+	// affected by the explosion.
+	DisableBlockDamage bool
 
 	// Sound is the sound to play when the explosion is created. If set to nil, this will default to the sound of a
 	// regular explosion.
@@ -73,14 +76,14 @@ func (c ExplosionConfig) Explode(w *world.World, explosionPos mgl64.Vec3) {
 	if c.Particle == nil {
 		c.Particle = particle.HugeExplosion{}
 	}
-	if c.Rand == nil {
-		c.Rand = rand.NewSource(time.Now().UnixNano())
-	}
 	if c.Size == 0 {
 		c.Size = 4
 	}
 
-	r, d := rand.New(c.Rand), c.Size*2
+	r, d := w.Rand(), c.Size*2
+	if c.Rand != nil {
+		r = rand.New(c.Rand)
+	}
 	box := cube.Box(
 		math.Floor(explosionPos[0]-d-1),
 		math.Floor(explosionPos[1]-d-1),
@@ -90,23 +93,26 @@ func (c ExplosionConfig) Explode(w *world.World, explosionPos mgl64.Vec3) {
 		math.Ceil(explosionPos[2]+d+1),
 	)
 
+	affectedEntities := make([]world.Entity, 0, 32)
 	for _, e := range w.EntitiesWithin(box.Grow(2), nil) {
 		pos := e.Position()
 		if !e.Type().BBox(e).Translate(pos).IntersectsWith(box) {
 			continue
 		}
-		dist := pos.Sub(pos).Len()
-		if dist >= d {
+		if pos.Sub(explosionPos).Len() >= d {
 			continue
 		}
-		if explodable, ok := e.(ExplodableEntity); ok {
-			impact := (1 - dist/d) * exposure(pos, e)
-			explodable.Explode(explosionPos, impact, c)
-		}
+		affectedEntities = append(affectedEntities, e)
 	}
 
 	affectedBlocks := make([]cube.Pos, 0, 32)
-	for _, ray := range rays {
+	visited := make(map[cube.Pos]struct{}, 32)
+	explosionRays := rays
+	if c.DisableBlockDamage {
+		// No blocks are destroyed, so there is no need to find the blocks affected by the explosion.
+		explosionRays = nil
+	}
+	for _, ray := range explosionRays {
 		pos := explosionPos
 		for blastForce := c.Size * (0.7 + r.Float64()*0.6); blastForce > 0.0; blastForce -= 0.225 {
 			current := cube.PosFromVec3(pos)
@@ -124,24 +130,44 @@ func (c ExplosionConfig) Explode(w *world.World, explosionPos mgl64.Vec3) {
 
 			pos = pos.Add(ray)
 			if blastForce -= (resistance/5 + 0.3) * 0.3; blastForce > 0 {
-				affectedBlocks = append(affectedBlocks, current)
+				if _, ok := visited[current]; !ok {
+					visited[current] = struct{}{}
+					affectedBlocks = append(affectedBlocks, current)
+				}
 			}
 		}
 	}
+
+	itemDropChance, spawnFire := 1/c.Size, c.SpawnFire
+	if c.DisableItemDrops {
+		itemDropChance = 0
+	}
+	ctx := event.C()
+	if w.Events().HandleExplosion(ctx, explosionPos, &affectedEntities, &affectedBlocks, &itemDropChance, &spawnFire); ctx.Cancelled() {
+		return
+	}
+
+	for _, e := range affectedEntities {
+		if explodable, ok := e.(ExplodableEntity); ok {
+			pos := e.Position()
+			impact := (1 - pos.Sub(explosionPos).Len()/d) * exposure(explosionPos, e)
+			explodable.Explode(explosionPos, impact, c)
+		}
+	}
 	for _, pos := range affectedBlocks {
 		bl := w.Block(pos)
 		if explodable, ok := bl.(Explodable); ok {
 			explodable.Explode(explosionPos, pos, w, c)
 		} else if breakable, ok := bl.(Breakable); ok {
 			w.SetBlock(pos, nil, nil)
-			if !c.DisableItemDrops && 1/c.Size > r.Float64() {
-				for _, drop := range breakable.BreakInfo().Drops(item.ToolNone{}, nil) {
+			if itemDropChance > r.Float64() {
+				for _, drop := range breakable.BreakInfo().Drops(item.ToolNone{}, nil, r) {
 					dropItem(w, drop, pos.Vec3Centre())
 				}
 			}
 		}
 	}
-	if c.SpawnFire {
+	if spawnFire {
 		for _, pos := range affectedBlocks {
 			if r.Intn(3) == 0 {
 				if _, ok := w.Block(pos).(Air); ok && w.Block(pos.Side(cube.FaceDown)).Model().FaceSolid(pos, cube.FaceUp, w) {
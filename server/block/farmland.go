@@ -71,7 +71,7 @@ func (f Farmland) hydrated(pos cube.Pos, w *world.World) bool {
 // EntityLand ...
 func (f Farmland) EntityLand(pos cube.Pos, w *world.World, e world.Entity, distance *float64) {
 	if living, ok := e.(livingEntity); ok {
-		if fall, ok := living.(fallDistanceEntity); ok && rand.Float64() < fall.FallDistance()-0.5 {
+		if fall, ok := living.(fallDistanceEntity); ok && w.Rand().Float64() < fall.FallDistance()-0.5 {
 			w.SetBlock(pos, Dirt{}, nil)
 		}
 	}
@@ -51,14 +51,8 @@ func max(a, b int) int {
 
 // infinitelyBurning returns true if fire can infinitely burn at the specified position.
 func infinitelyBurning(pos cube.Pos, w *world.World) bool {
-	switch block := w.Block(pos.Side(cube.FaceDown)).(type) {
-	// TODO: Magma Block
-	case Netherrack:
-		return true
-	case Bedrock:
-		return block.InfiniteBurning
-	}
-	return false
+	b, ok := w.Block(pos.Side(cube.FaceDown)).(InfiniteBurner)
+	return ok && b.InfinitelyBurning()
 }
 
 // burn attempts to burn a block.
@@ -68,8 +62,7 @@ func (f Fire) burn(from, to cube.Pos, w *world.World, r *rand.Rand, chanceBound
 			f.spread(from, to, w, r)
 			return
 		}
-		if t, ok := flammable.(TNT); ok {
-			t.Ignite(to, w)
+		if i, ok := flammable.(Ignitable); ok && i.Ignite(to, w) {
 			return
 		}
 		w.SetBlock(to, nil, nil)
@@ -90,7 +83,7 @@ func rainingAround(pos cube.Pos, w *world.World) bool {
 
 // tick ...
 func (f Fire) tick(pos cube.Pos, w *world.World, r *rand.Rand) {
-	if f.Type == SoulFire() {
+	if f.Type == SoulFire() || !w.BoolGameRule(world.GameRuleDoFireTick) {
 		return
 	}
 	infinitelyBurns := infinitelyBurning(pos, w)
@@ -184,12 +177,12 @@ func (f Fire) tick(pos cube.Pos, w *world.World, r *rand.Rand) {
 func (f Fire) spread(from, to cube.Pos, w *world.World, r *rand.Rand) {
 	if _, air := w.Block(to).(Air); !air {
 		ctx := event.C()
-		if w.Handler().HandleBlockBurn(ctx, to); ctx.Cancelled() {
+		if w.Events().HandleBlockBurn(ctx, to); ctx.Cancelled() {
 			return
 		}
 	}
 	ctx := event.C()
-	if w.Handler().HandleFireSpread(ctx, from, to); ctx.Cancelled() {
+	if w.Events().HandleFireSpread(ctx, from, to); ctx.Cancelled() {
 		return
 	}
 	w.SetBlock(to, Fire{Type: f.Type, Age: min(15, f.Age+r.Intn(5)/4)}, nil)
@@ -220,6 +213,10 @@ func (f Fire) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
 
 // NeighbourUpdateTick ...
 func (f Fire) NeighbourUpdateTick(pos, neighbour cube.Pos, w *world.World) {
+	if neighbour == pos && lightNetherPortal(pos, w) {
+		// The fire was lit inside an obsidian frame, so it was turned into a nether portal.
+		return
+	}
 	below := w.Block(pos.Side(cube.FaceDown))
 	if diffuser, ok := below.(LightDiffuser); (ok && diffuser.LightDiffusionLevel() != 15) && (!neighboursFlammable(pos, w) || f.Type == SoulFire()) {
 		w.SetBlock(pos, nil, nil)
@@ -7,7 +7,6 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/particle"
 	"github.com/go-gl/mathgl/mgl64"
-	"math/rand"
 	"time"
 )
 
@@ -39,7 +38,7 @@ func (f Flower) BoneMeal(pos cube.Pos, w *world.World) (success bool) {
 	}
 
 	for i := 0; i < 8; i++ {
-		p := pos.Add(cube.Pos{rand.Intn(7) - 3, rand.Intn(3) - 1, rand.Intn(7) - 3})
+		p := pos.Add(cube.Pos{w.Rand().Intn(7) - 3, w.Rand().Intn(3) - 1, w.Rand().Intn(7) - 3})
 		if _, ok := w.Block(p).(Air); !ok {
 			continue
 		}
@@ -47,7 +46,7 @@ func (f Flower) BoneMeal(pos cube.Pos, w *world.World) (success bool) {
 			continue
 		}
 		flowerType := f.Type
-		if rand.Float64() < 0.1 {
+		if w.Rand().Float64() < 0.1 {
 			if f.Type == Dandelion() {
 				flowerType = Poppy()
 			} else if f.Type == Poppy() {
@@ -7,7 +7,6 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
-	"math/rand"
 	"time"
 )
 
@@ -34,10 +33,10 @@ func NewFurnace(face cube.Face) Furnace {
 
 // Tick is called to check if the furnace should update and start or stop smelting.
 func (f Furnace) Tick(_ int64, pos cube.Pos, w *world.World) {
-	if f.Lit && rand.Float64() <= 0.016 { // Every three or so seconds.
+	if f.Lit && w.Rand().Float64() <= 0.016 { // Every three or so seconds.
 		w.PlaySound(pos.Vec3Centre(), sound.FurnaceCrackle{})
 	}
-	if lit := f.smelter.tickSmelting(time.Second*10, time.Millisecond*100, f.Lit, func(item.SmeltInfo) bool {
+	if lit := f.smelter.tickSmelting(time.Second*10, time.Millisecond*100, f.Lit, w.Rand(), func(item.SmeltInfo) bool {
 		return true
 	}); f.Lit != lit {
 		f.Lit = lit
@@ -3,7 +3,6 @@ package block
 import (
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/world/sound"
-	"math/rand"
 )
 
 // Glowstone is commonly found on the ceiling of the nether dimension.
@@ -18,7 +17,7 @@ func (g Glowstone) Instrument() sound.Instrument {
 
 // BreakInfo ...
 func (g Glowstone) BreakInfo() BreakInfo {
-	return newBreakInfo(0.3, alwaysHarvestable, nothingEffective, silkTouchDrop(item.NewStack(item.GlowstoneDust{}, rand.Intn(3)+2), item.NewStack(g, 1)))
+	return newBreakInfo(0.3, alwaysHarvestable, nothingEffective, silkTouchRandomDrop(item.GlowstoneDust{}, 2, 4, item.NewStack(g, 1)))
 }
 
 // EncodeItem ...
@@ -80,12 +80,12 @@ func (g Grass) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
 // BoneMeal ...
 func (g Grass) BoneMeal(pos cube.Pos, w *world.World) bool {
 	for i := 0; i < 14; i++ {
-		c := pos.Add(cube.Pos{rand.Intn(6) - 3, 0, rand.Intn(6) - 3})
+		c := pos.Add(cube.Pos{w.Rand().Intn(6) - 3, 0, w.Rand().Intn(6) - 3})
 		above := c.Side(cube.FaceUp)
 		_, air := w.Block(above).(Air)
 		_, grass := w.Block(c).(Grass)
 		if air && grass {
-			w.SetBlock(above, plantSelection[rand.Intn(len(plantSelection))], nil)
+			w.SetBlock(above, plantSelection[w.Rand().Intn(len(plantSelection))], nil)
 		}
 	}
 
@@ -16,13 +16,13 @@ type Gravel struct {
 
 // NeighbourUpdateTick ...
 func (g Gravel) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
-	g.fall(g, pos, w)
+	Fall(g, pos, w)
 }
 
 // BreakInfo ...
 func (g Gravel) BreakInfo() BreakInfo {
-	return newBreakInfo(0.6, alwaysHarvestable, shovelEffective, func(t item.Tool, enchantments []item.Enchantment) []item.Stack {
-		if !hasSilkTouch(enchantments) && rand.Float64() < 0.1 {
+	return newBreakInfo(0.6, alwaysHarvestable, shovelEffective, func(t item.Tool, enchantments []item.Enchantment, r *rand.Rand) []item.Stack {
+		if !hasSilkTouch(enchantments) && r.Float64() < 0.1 {
 			return []item.Stack{item.NewStack(item.Flint{}, 1)}
 		}
 		return []item.Stack{item.NewStack(g, 1)}
@@ -4,6 +4,7 @@ package block
 
 const (
 	hashAir = iota
+	hashAllow
 	hashAmethyst
 	hashAncientDebris
 	hashAndesite
@@ -13,6 +14,7 @@ const (
 	hashBarrier
 	hashBasalt
 	hashBeacon
+	hashBed
 	hashBedrock
 	hashBeetrootSeeds
 	hashBlackstone
@@ -20,6 +22,7 @@ const (
 	hashBlueIce
 	hashBone
 	hashBookshelf
+	hashBorder
 	hashBricks
 	hashCactus
 	hashCake
@@ -46,6 +49,7 @@ const (
 	hashDeepslate
 	hashDeepslateBricks
 	hashDeepslateTiles
+	hashDeny
 	hashDiamond
 	hashDiamondOre
 	hashDiorite
@@ -80,6 +84,7 @@ const (
 	hashGrindstone
 	hashHayBale
 	hashHoneycomb
+	hashHopper
 	hashInvisibleBedrock
 	hashIron
 	hashIronBars
@@ -101,12 +106,14 @@ const (
 	hashMelon
 	hashMelonSeeds
 	hashMossCarpet
+	hashMoving
 	hashMud
 	hashMudBricks
 	hashMuddyMangroveRoots
 	hashNetherBrickFence
 	hashNetherBricks
 	hashNetherGoldOre
+	hashNetherPortal
 	hashNetherQuartzOre
 	hashNetherSprouts
 	hashNetherWart
@@ -117,6 +124,8 @@ const (
 	hashObsidian
 	hashPackedIce
 	hashPackedMud
+	hashPiston
+	hashPistonArmCollision
 	hashPlanks
 	hashPodzol
 	hashPolishedBlackstoneBrick
@@ -132,6 +141,7 @@ const (
 	hashRawCopper
 	hashRawGold
 	hashRawIron
+	hashRedstone
 	hashReinforcedDeepslate
 	hashSand
 	hashSandstone
@@ -176,6 +186,10 @@ func (Air) Hash() uint64 {
 	return hashAir
 }
 
+func (Allow) Hash() uint64 {
+	return hashAllow
+}
+
 func (Amethyst) Hash() uint64 {
 	return hashAmethyst
 }
@@ -212,6 +226,10 @@ func (Beacon) Hash() uint64 {
 	return hashBeacon
 }
 
+func (b Bed) Hash() uint64 {
+	return hashBed | uint64(b.Facing)<<8 | uint64(boolByte(b.Head))<<10 | uint64(boolByte(b.Occupied))<<11
+}
+
 func (b Bedrock) Hash() uint64 {
 	return hashBedrock | uint64(boolByte(b.InfiniteBurning))<<8
 }
@@ -240,6 +258,10 @@ func (Bookshelf) Hash() uint64 {
 	return hashBookshelf
 }
 
+func (b Border) Hash() uint64 {
+	return hashBorder | uint64(b.NorthConnection.Uint8())<<8 | uint64(b.EastConnection.Uint8())<<10 | uint64(b.SouthConnection.Uint8())<<12 | uint64(b.WestConnection.Uint8())<<14 | uint64(boolByte(b.Post))<<16
+}
+
 func (Bricks) Hash() uint64 {
 	return hashBricks
 }
@@ -344,6 +366,10 @@ func (d DeepslateTiles) Hash() uint64 {
 	return hashDeepslateTiles | uint64(boolByte(d.Cracked))<<8
 }
 
+func (Deny) Hash() uint64 {
+	return hashDeny
+}
+
 func (Diamond) Hash() uint64 {
 	return hashDiamond
 }
@@ -480,6 +506,10 @@ func (Honeycomb) Hash() uint64 {
 	return hashHoneycomb
 }
 
+func (h Hopper) Hash() uint64 {
+	return hashHopper | uint64(h.Facing)<<8
+}
+
 func (InvisibleBedrock) Hash() uint64 {
 	return hashInvisibleBedrock
 }
@@ -564,6 +594,10 @@ func (MossCarpet) Hash() uint64 {
 	return hashMossCarpet
 }
 
+func (Moving) Hash() uint64 {
+	return hashMoving
+}
+
 func (Mud) Hash() uint64 {
 	return hashMud
 }
@@ -588,6 +622,10 @@ func (NetherGoldOre) Hash() uint64 {
 	return hashNetherGoldOre
 }
 
+func (p NetherPortal) Hash() uint64 {
+	return hashNetherPortal | uint64(p.Axis)<<8
+}
+
 func (NetherQuartzOre) Hash() uint64 {
 	return hashNetherQuartzOre
 }
@@ -628,6 +666,14 @@ func (PackedMud) Hash() uint64 {
 	return hashPackedMud
 }
 
+func (p Piston) Hash() uint64 {
+	return hashPiston | uint64(p.Facing)<<8 | uint64(boolByte(p.Sticky))<<11
+}
+
+func (p PistonArmCollision) Hash() uint64 {
+	return hashPistonArmCollision | uint64(p.Facing)<<8 | uint64(boolByte(p.Sticky))<<11
+}
+
 func (p Planks) Hash() uint64 {
 	return hashPlanks | uint64(p.Wood.Uint8())<<8
 }
@@ -688,6 +734,10 @@ func (RawIron) Hash() uint64 {
 	return hashRawIron
 }
 
+func (Redstone) Hash() uint64 {
+	return hashRedstone
+}
+
 func (ReinforcedDeepslate) Hash() uint64 {
 	return hashReinforcedDeepslate
 }
@@ -0,0 +1,304 @@
+package block
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/model"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/item/inventory"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"strings"
+	"sync"
+)
+
+// Hopper is a container block used to move items between containers. Every eight ticks, a hopper moves a single
+// item into the container it is facing and pulls a single item from the container above it.
+// The empty value of Hopper is not valid. It must be created using block.NewHopper().
+type Hopper struct {
+	transparent
+	sourceWaterDisplacer
+
+	// Facing is the direction the hopper is facing: This is the face of the hopper that items are moved out of.
+	// Facing is either cube.FaceDown or one of the horizontal faces.
+	Facing cube.Face
+	// CustomName is the custom name of the hopper. This name is displayed when the hopper is opened, and may
+	// include colour codes.
+	CustomName string
+
+	inventory *inventory.Inventory
+	viewerMu  *sync.RWMutex
+	viewers   map[ContainerViewer]struct{}
+	cooldown  *hopperCooldown
+}
+
+// hopperCooldown holds the transfer cooldown of a hopper, which is the number of ticks remaining until the hopper
+// moves items again.
+type hopperCooldown struct {
+	mu    sync.Mutex
+	ticks int
+}
+
+// hopperTransferCooldown is the number of ticks that a hopper waits after moving an item before moving items again.
+const hopperTransferCooldown = 8
+
+// NewHopper creates a new initialised hopper. The inventory is properly initialised.
+func NewHopper() Hopper {
+	m := new(sync.RWMutex)
+	v := make(map[ContainerViewer]struct{}, 1)
+	return Hopper{
+		inventory: inventory.New(5, func(slot int, _, item item.Stack) {
+			m.RLock()
+			defer m.RUnlock()
+			for viewer := range v {
+				viewer.ViewSlotChange(slot, item)
+			}
+		}),
+		viewerMu: m,
+		viewers:  v,
+		cooldown: &hopperCooldown{},
+	}
+}
+
+// Inventory returns the inventory of the hopper. The size of the inventory will be 5.
+func (h Hopper) Inventory() *inventory.Inventory {
+	return h.inventory
+}
+
+// WithName returns the hopper after applying a specific name to the block.
+func (h Hopper) WithName(a ...any) world.Item {
+	h.CustomName = strings.TrimSuffix(fmt.Sprintln(a...), "\n")
+	return h
+}
+
+// Model ...
+func (Hopper) Model() world.BlockModel {
+	return model.Hopper{}
+}
+
+// SideClosed ...
+func (Hopper) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
+// AddViewer adds a viewer to the hopper, so that it is updated whenever the inventory of the hopper is changed.
+func (h Hopper) AddViewer(v ContainerViewer, _ *world.World, _ cube.Pos) {
+	h.viewerMu.Lock()
+	defer h.viewerMu.Unlock()
+	h.viewers[v] = struct{}{}
+}
+
+// RemoveViewer removes a viewer from the hopper, so that slot updates in the inventory are no longer sent to
+// it.
+func (h Hopper) RemoveViewer(v ContainerViewer, _ *world.World, _ cube.Pos) {
+	h.viewerMu.Lock()
+	defer h.viewerMu.Unlock()
+	delete(h.viewers, v)
+}
+
+// Activate ...
+func (h Hopper) Activate(pos cube.Pos, _ cube.Face, _ *world.World, u item.User, _ *item.UseContext) bool {
+	if opener, ok := u.(ContainerOpener); ok {
+		opener.OpenBlockContainer(pos)
+		return true
+	}
+	return false
+}
+
+// UseOnBlock ...
+func (h Hopper) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	pos, _, used = firstReplaceable(w, pos, face, h)
+	if !used {
+		return
+	}
+	//noinspection GoAssignmentToReceiver
+	h = NewHopper()
+	h.Facing = cube.FaceDown
+	if face != cube.FaceDown && face != cube.FaceUp {
+		h.Facing = face.Opposite()
+	}
+
+	place(w, pos, h, user, ctx)
+	return placed(ctx)
+}
+
+// Tick moves items out of and into the hopper once its transfer cooldown has passed.
+func (h Hopper) Tick(_ int64, pos cube.Pos, w *world.World) {
+	if h.cooldown == nil {
+		return
+	}
+	h.cooldown.mu.Lock()
+	defer h.cooldown.mu.Unlock()
+	if h.cooldown.ticks--; h.cooldown.ticks > 0 {
+		return
+	}
+	h.cooldown.ticks = 0
+
+	pushed := h.push(pos, w)
+	pulled := h.pull(pos, w)
+	if pushed || pulled {
+		h.cooldown.ticks = hopperTransferCooldown
+	}
+}
+
+// push moves a single item from the hopper into the container that the hopper is facing. True is returned if an
+// item was moved.
+func (h Hopper) push(pos cube.Pos, w *world.World) bool {
+	destPos := pos.Side(h.Facing)
+	dest, ok := hopperContainerAt(w, destPos)
+	if !ok {
+		return false
+	}
+	for slot, it := range h.inventory.Slots() {
+		if it.Empty() {
+			continue
+		}
+		if hopperInsert(dest, it, h.Facing.Opposite()) {
+			_ = h.inventory.SetItem(slot, it.Grow(-1))
+			return true
+		}
+	}
+	return false
+}
+
+// pull moves a single item from the container above the hopper into the hopper. True is returned if an item was
+// moved.
+func (h Hopper) pull(pos cube.Pos, w *world.World) bool {
+	src, ok := hopperContainerAt(w, pos.Side(cube.FaceUp))
+	if !ok {
+		return false
+	}
+	inv := src.Inventory()
+	for _, slot := range hopperExtractSlots(src) {
+		it, _ := inv.Item(slot)
+		if it.Empty() {
+			continue
+		}
+		if hopperInsert(h, it, cube.FaceUp) {
+			_ = inv.SetItem(slot, it.Grow(-1))
+			return true
+		}
+	}
+	return false
+}
+
+// hopperSlotter is implemented by containers that restrict the slots of their inventory that hoppers may insert
+// items into and extract items from.
+type hopperSlotter interface {
+	// hopperInsertSlots returns the slots that a hopper may insert the item passed into, given the face of the
+	// container that the hopper is attached to.
+	hopperInsertSlots(it item.Stack, face cube.Face) []int
+	// hopperExtractSlots returns the slots that a hopper may extract items from.
+	hopperExtractSlots() []int
+}
+
+// hopperContainerAt returns the container at the position passed that a hopper may move items into or out of.
+// Chests are linked with the chest they are paired with first, so that the full inventory of a double chest is
+// used.
+func hopperContainerAt(w *world.World, pos cube.Pos) (Container, bool) {
+	switch b := w.Block(pos).(type) {
+	case Chest:
+		return b.link(w, pos), true
+	case Container:
+		return b, true
+	}
+	return nil, false
+}
+
+// hopperExtractSlots returns the slots of the container passed that a hopper may extract items from.
+func hopperExtractSlots(c Container) []int {
+	if s, ok := c.(hopperSlotter); ok {
+		return s.hopperExtractSlots()
+	}
+	slots := make([]int, c.Inventory().Size())
+	for i := range slots {
+		slots[i] = i
+	}
+	return slots
+}
+
+// hopperInsert inserts a single item of the stack passed into the container c, coming in through the face of the
+// container passed. True is returned if the item was inserted.
+func hopperInsert(c Container, it item.Stack, face cube.Face) bool {
+	inv := c.Inventory()
+	var slots []int
+	if s, ok := c.(hopperSlotter); ok {
+		slots = s.hopperInsertSlots(it, face)
+	} else {
+		slots = hopperExtractSlots(c)
+	}
+	single := it.Grow(1 - it.Count())
+	for _, slot := range slots {
+		existing, _ := inv.Item(slot)
+		if existing.Empty() {
+			_ = inv.SetItem(slot, single)
+			return true
+		}
+		if existing.Comparable(single) && existing.Count() < existing.MaxCount() {
+			_ = inv.SetItem(slot, existing.Grow(1))
+			return true
+		}
+	}
+	return false
+}
+
+// BreakInfo ...
+func (h Hopper) BreakInfo() BreakInfo {
+	return newBreakInfo(3, pickaxeHarvestable, pickaxeEffective, oneOf(h)).withBlastResistance(24)
+}
+
+// DecodeNBT ...
+func (h Hopper) DecodeNBT(data map[string]any) any {
+	facing := h.Facing
+	//noinspection GoAssignmentToReceiver
+	h = NewHopper()
+	h.Facing = facing
+	h.CustomName = nbtconv.String(data, "CustomName")
+	h.cooldown.ticks = int(nbtconv.Int32(data, "TransferCooldown"))
+	nbtconv.InvFromNBT(h.inventory, nbtconv.Slice(data, "Items"))
+	return h
+}
+
+// EncodeNBT ...
+func (h Hopper) EncodeNBT() map[string]any {
+	if h.inventory == nil {
+		facing, customName := h.Facing, h.CustomName
+		//noinspection GoAssignmentToReceiver
+		h = NewHopper()
+		h.Facing, h.CustomName = facing, customName
+	}
+	h.cooldown.mu.Lock()
+	cooldown := h.cooldown.ticks
+	h.cooldown.mu.Unlock()
+
+	m := map[string]any{
+		"Items":            nbtconv.InvToNBT(h.inventory),
+		"TransferCooldown": int32(cooldown),
+		"id":               "Hopper",
+	}
+	if h.CustomName != "" {
+		m["CustomName"] = h.CustomName
+	}
+	return m
+}
+
+// EncodeBlock ...
+func (h Hopper) EncodeBlock() (string, map[string]any) {
+	return "minecraft:hopper", map[string]any{"facing_direction": int32(h.Facing), "toggle_bit": uint8(0)}
+}
+
+// EncodeItem ...
+func (Hopper) EncodeItem() (name string, meta int16) {
+	return "minecraft:hopper", 0
+}
+
+// allHoppers ...
+func allHoppers() (b []world.Block) {
+	for _, f := range cube.Faces() {
+		if f != cube.FaceUp {
+			b = append(b, Hopper{Facing: f})
+		}
+	}
+	return
+}
@@ -17,9 +17,7 @@ func (i Iron) Instrument() sound.Instrument {
 
 // BreakInfo ...
 func (i Iron) BreakInfo() BreakInfo {
-	return newBreakInfo(5, func(t item.Tool) bool {
-		return t.ToolType() == item.TypePickaxe && t.HarvestLevel() >= item.ToolTierStone.HarvestLevel
-	}, pickaxeEffective, oneOf(i)).withBlastResistance(30)
+	return newBreakInfo(5, pickaxeTierHarvestable(item.ToolTierStone), pickaxeEffective, oneOf(i)).withBlastResistance(30)
 }
 
 // PowersBeacon ...
@@ -9,7 +9,6 @@ import (
 	"github.com/df-mc/dragonfly/server/world/particle"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
-	"math/rand"
 )
 
 // ItemFrame is a block entity that displays the item or block that is inside it.
@@ -59,7 +58,7 @@ func (i ItemFrame) Punch(pos cube.Pos, _ cube.Face, w *world.World, u item.User)
 	if g, ok := u.(interface {
 		GameMode() world.GameMode
 	}); ok {
-		if rand.Float64() <= i.DropChance && !g.GameMode().CreativeInventory() {
+		if w.Rand().Float64() <= i.DropChance && !g.GameMode().CreativeInventory() {
 			dropItem(w, i.Item, pos.Vec3Centre())
 		}
 	}
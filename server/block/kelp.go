@@ -68,9 +68,9 @@ func (Kelp) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
 	return false
 }
 
-// withRandomAge returns a new Kelp block with its age value randomized between 0 and 24.
-func (k Kelp) withRandomAge() Kelp {
-	k.Age = rand.Intn(25)
+// withRandomAge returns a new Kelp block with its age value randomized between 0 and 24 using r.
+func (k Kelp) withRandomAge(r *rand.Rand) Kelp {
+	k.Age = r.Intn(25)
 	return k
 }
 
@@ -97,7 +97,7 @@ func (k Kelp) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.Wo
 	}
 
 	// When first placed, kelp gets a random age between 0 and 24.
-	place(w, pos, k.withRandomAge(), user, ctx)
+	place(w, pos, k.withRandomAge(w.Rand()), user, ctx)
 	return placed(ctx)
 }
 
@@ -109,7 +109,7 @@ func (k Kelp) NeighbourUpdateTick(pos, changed cube.Pos, w *world.World) {
 	}
 	if changed.Y()-1 == pos.Y() {
 		// When a kelp block is broken above, the kelp block underneath it gets a new random age.
-		w.SetBlock(pos, k.withRandomAge(), nil)
+		w.SetBlock(pos, k.withRandomAge(w.Rand()), nil)
 	}
 
 	below := pos.Side(cube.FaceDown)
@@ -2,7 +2,6 @@ package block
 
 import (
 	"github.com/df-mc/dragonfly/server/item"
-	"math/rand"
 )
 
 // LapisOre is an ore block from which lapis lazuli is obtained.
@@ -16,9 +15,7 @@ type LapisOre struct {
 
 // BreakInfo ...
 func (l LapisOre) BreakInfo() BreakInfo {
-	i := newBreakInfo(l.Type.Hardness(), func(t item.Tool) bool {
-		return t.ToolType() == item.TypePickaxe && t.HarvestLevel() >= item.ToolTierStone.HarvestLevel
-	}, pickaxeEffective, silkTouchDrop(item.NewStack(item.LapisLazuli{}, rand.Intn(5)+4), item.NewStack(l, 1))).withXPDropRange(2, 5)
+	i := newBreakInfo(l.Type.Hardness(), pickaxeTierHarvestable(item.ToolTierStone), pickaxeEffective, oreDrops(item.LapisLazuli{}, l, 4, 8)).withXPDropRange(2, 5)
 	if l.Type == DeepslateOre() {
 		i = i.withBlastResistance(9)
 	}
@@ -163,7 +163,7 @@ func (l Lava) Harden(pos cube.Pos, w *world.World, flownIntoBy *cube.Pos) bool {
 		}, w.Range())
 		if b != nil {
 			ctx := event.C()
-			if w.Handler().HandleLiquidHarden(ctx, pos, l, water, b); ctx.Cancelled() {
+			if w.Events().HandleLiquidHarden(ctx, pos, l, water, b); ctx.Cancelled() {
 				return false
 			}
 			w.PlaySound(pos.Vec3Centre(), sound.Fizz{})
@@ -183,7 +183,7 @@ func (l Lava) Harden(pos cube.Pos, w *world.World, flownIntoBy *cube.Pos) bool {
 		b = Cobblestone{}
 	}
 	ctx := event.C()
-	if w.Handler().HandleLiquidHarden(ctx, pos, l, water, b); ctx.Cancelled() {
+	if w.Events().HandleLiquidHarden(ctx, pos, l, water, b); ctx.Cancelled() {
 		return false
 	}
 	w.SetBlock(pos, b, nil)
@@ -88,12 +88,12 @@ func (l Leaves) FlammabilityInfo() FlammabilityInfo {
 func (l Leaves) BreakInfo() BreakInfo {
 	return newBreakInfo(0.2, alwaysHarvestable, func(t item.Tool) bool {
 		return t.ToolType() == item.TypeShears || t.ToolType() == item.TypeHoe
-	}, func(t item.Tool, enchantments []item.Enchantment) []item.Stack {
+	}, func(t item.Tool, enchantments []item.Enchantment, r *rand.Rand) []item.Stack {
 		if t.ToolType() == item.TypeShears || hasSilkTouch(enchantments) {
 			return []item.Stack{item.NewStack(l, 1)}
 		}
 		var drops []item.Stack
-		if (l.Wood == OakWood() || l.Wood == DarkOakWood()) && rand.Float64() < 0.005 {
+		if (l.Wood == OakWood() || l.Wood == DarkOakWood()) && r.Float64() < 0.005 {
 			drops = append(drops, item.NewStack(item.Apple{}, 1))
 		}
 		// TODO: Saplings and sticks can drop along with apples
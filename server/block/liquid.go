@@ -43,7 +43,7 @@ func tickLiquid(b world.Liquid, pos cube.Pos, w *world.World) {
 			res = b.WithDepth(b.LiquidDepth()-2*b.SpreadDecay(), false)
 		}
 		ctx := event.C()
-		if w.Handler().HandleLiquidDecay(ctx, pos, b, res); ctx.Cancelled() {
+		if w.Events().HandleLiquidDecay(ctx, pos, b, res); ctx.Cancelled() {
 			return
 		}
 		w.SetLiquid(pos, res)
@@ -139,7 +139,7 @@ func flowInto(b world.Liquid, src, pos cube.Pos, w *world.World, falling bool) b
 			return true
 		}
 		ctx := event.C()
-		if w.Handler().HandleLiquidFlow(ctx, src, pos, b.WithDepth(newDepth, falling), existing); ctx.Cancelled() {
+		if w.Events().HandleLiquidFlow(ctx, src, pos, b.WithDepth(newDepth, falling), existing); ctx.Cancelled() {
 			return false
 		}
 		w.SetLiquid(pos, b.WithDepth(newDepth, falling))
@@ -161,7 +161,7 @@ func flowInto(b world.Liquid, src, pos cube.Pos, w *world.World, falling bool) b
 		return false
 	}
 	ctx := event.C()
-	if w.Handler().HandleLiquidFlow(ctx, src, pos, b.WithDepth(newDepth, falling), existing); ctx.Cancelled() {
+	if w.Events().HandleLiquidFlow(ctx, src, pos, b.WithDepth(newDepth, falling), existing); ctx.Cancelled() {
 		return false
 	}
 
@@ -171,7 +171,7 @@ func flowInto(b world.Liquid, src, pos cube.Pos, w *world.World, falling bool) b
 		}
 		if removable.HasLiquidDrops() {
 			if b, ok := existing.(Breakable); ok {
-				for _, d := range b.BreakInfo().Drops(item.ToolNone{}, nil) {
+				for _, d := range b.BreakInfo().Drops(item.ToolNone{}, nil, w.Rand()) {
 					dropItem(w, d, pos.Vec3Centre())
 				}
 			} else {
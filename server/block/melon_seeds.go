@@ -68,7 +68,7 @@ func (m MelonSeeds) BoneMeal(pos cube.Pos, w *world.World) bool {
 	if m.Growth == 7 {
 		return false
 	}
-	m.Growth = min(m.Growth+rand.Intn(4)+2, 7)
+	m.Growth = min(m.Growth+w.Rand().Intn(4)+2, 7)
 	w.SetBlock(pos, m, nil)
 	return true
 }
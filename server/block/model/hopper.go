@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Hopper is a model used by hoppers. It consists of a bowl with an open top and a smaller part below it, which
+// leads to the spout of the hopper.
+type Hopper struct{}
+
+// BBox ...
+func (Hopper) BBox(cube.Pos, *world.World) []cube.BBox {
+	return []cube.BBox{
+		cube.Box(0, 0.625, 0, 1, 0.6875, 1),
+		cube.Box(0, 0.6875, 0, 1, 1, 0.125),
+		cube.Box(0, 0.6875, 0.875, 1, 1, 1),
+		cube.Box(0, 0.6875, 0, 0.125, 1, 1),
+		cube.Box(0.875, 0.6875, 0, 1, 1, 1),
+		cube.Box(0.25, 0.25, 0.25, 0.75, 0.625, 0.75),
+	}
+}
+
+// FaceSolid returns true for all faces other than the top and the bottom.
+func (Hopper) FaceSolid(_ cube.Pos, face cube.Face, _ *world.World) bool {
+	return face != cube.FaceUp && face != cube.FaceDown
+}
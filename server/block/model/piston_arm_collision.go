@@ -0,0 +1,36 @@
+package model
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// PistonArmCollision is a model used by the arm of an extended piston. It consists of the head of the piston and the rod
+// connecting the head to the base of the piston.
+type PistonArmCollision struct {
+	// Facing is the face that the piston, and therefore its head, is facing.
+	Facing cube.Face
+}
+
+// BBox ...
+func (p PistonArmCollision) BBox(cube.Pos, *world.World) []cube.BBox {
+	switch p.Facing {
+	case cube.FaceDown:
+		return []cube.BBox{cube.Box(0, 0, 0, 1, 0.25, 1), cube.Box(0.375, 0.25, 0.375, 0.625, 1, 0.625)}
+	case cube.FaceUp:
+		return []cube.BBox{cube.Box(0, 0.75, 0, 1, 1, 1), cube.Box(0.375, 0, 0.375, 0.625, 0.75, 0.625)}
+	case cube.FaceNorth:
+		return []cube.BBox{cube.Box(0, 0, 0, 1, 1, 0.25), cube.Box(0.375, 0.375, 0.25, 0.625, 0.625, 1)}
+	case cube.FaceSouth:
+		return []cube.BBox{cube.Box(0, 0, 0.75, 1, 1, 1), cube.Box(0.375, 0.375, 0, 0.625, 0.625, 0.75)}
+	case cube.FaceWest:
+		return []cube.BBox{cube.Box(0, 0, 0, 0.25, 1, 1), cube.Box(0.25, 0.375, 0.375, 1, 0.625, 0.625)}
+	default:
+		return []cube.BBox{cube.Box(0.75, 0, 0, 1, 1, 1), cube.Box(0, 0.375, 0.375, 0.75, 0.625, 0.625)}
+	}
+}
+
+// FaceSolid only returns true for the face of the head of the piston.
+func (p PistonArmCollision) FaceSolid(_ cube.Pos, face cube.Face, _ *world.World) bool {
+	return face == p.Facing
+}
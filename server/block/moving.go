@@ -0,0 +1,91 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Moving is a block that is being moved by a piston. It holds the block that is moved, which is placed again once
+// the piston finishes moving. Clients use Moving blocks to animate the block moving along with the arm of the
+// piston.
+type Moving struct {
+	empty
+	transparent
+
+	// block is the block that is being moved. piston is the position of the piston that is moving the block.
+	block  world.Block
+	piston cube.Pos
+}
+
+// Block returns the block that is being moved.
+func (m Moving) Block() world.Block {
+	return m.block
+}
+
+// Piston returns the position of the piston that is moving the block.
+func (m Moving) Piston() cube.Pos {
+	return m.piston
+}
+
+// NeighbourUpdateTick places the block that is being moved if the piston moving it is no longer moving, for
+// example because it was removed.
+func (m Moving) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if p, ok := w.Block(m.piston).(Piston); !ok || p.state == pistonExtended || p.state == pistonRetracted {
+		m.place(pos, w)
+	}
+}
+
+// Tick places the block that is being moved if the piston moving it is no longer moving. This happens if the world
+// was saved while the block was being moved.
+func (m Moving) Tick(_ int64, pos cube.Pos, w *world.World) {
+	m.NeighbourUpdateTick(pos, pos, w)
+}
+
+// place places the block that is being moved at the position passed, or removes the Moving block if it did not
+// hold a block.
+func (m Moving) place(pos cube.Pos, w *world.World) {
+	if m.block == nil {
+		w.SetBlock(pos, nil, nil)
+		return
+	}
+	w.SetBlock(pos, m.block, nil)
+}
+
+// EncodeBlock ...
+func (Moving) EncodeBlock() (string, map[string]any) {
+	return "minecraft:moving_block", nil
+}
+
+// DecodeNBT ...
+func (m Moving) DecodeNBT(data map[string]any) any {
+	m.block = nbtconv.Block(data, "movingBlock")
+	if nbter, ok := m.block.(world.NBTer); ok {
+		if entity, ok := data["movingEntity"].(map[string]any); ok {
+			m.block = nbter.DecodeNBT(entity).(world.Block)
+		}
+	}
+	m.piston = cube.Pos{int(nbtconv.Int32(data, "pistonPosX")), int(nbtconv.Int32(data, "pistonPosY")), int(nbtconv.Int32(data, "pistonPosZ"))}
+	return m
+}
+
+// EncodeNBT ...
+func (m Moving) EncodeNBT() map[string]any {
+	b := m.block
+	if b == nil {
+		b = Air{}
+	}
+	data := map[string]any{
+		"id":               "MovingBlock",
+		"movingBlock":      nbtconv.WriteBlock(b),
+		"movingBlockExtra": nbtconv.WriteBlock(Air{}),
+		"pistonPosX":       int32(m.piston[0]),
+		"pistonPosY":       int32(m.piston[1]),
+		"pistonPosZ":       int32(m.piston[2]),
+		"isMovable":        uint8(1),
+	}
+	if nbter, ok := b.(world.NBTer); ok {
+		data["movingEntity"] = nbter.EncodeNBT()
+	}
+	return data
+}
@@ -0,0 +1,322 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"math"
+)
+
+// NetherPortal is the translucent block that fills an activated obsidian frame. Players standing inside a nether
+// portal are transported to the Nether, or back to the Overworld if they are already in the Nether.
+type NetherPortal struct {
+	transparent
+	empty
+
+	// Axis is the horizontal axis along which the portal is aligned. It is either cube.X or cube.Z.
+	Axis cube.Axis
+}
+
+// portalTraveller is an entity that may travel between worlds by standing inside a portal.
+type portalTraveller interface {
+	// EnterPortal is called every tick that the entity is inside a portal that leads to the dimension passed.
+	EnterPortal(dim world.Dimension)
+}
+
+// EntityInside ...
+func (NetherPortal) EntityInside(_ cube.Pos, _ *world.World, e world.Entity) {
+	if t, ok := e.(portalTraveller); ok {
+		t.EnterPortal(world.Nether)
+	}
+}
+
+// NeighbourUpdateTick ...
+func (p NetherPortal) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if _, ok := netherPortalShape(pos, p.Axis, w, true); !ok {
+		// The frame of the portal was broken, so the portal can no longer exist.
+		w.SetBlock(pos, nil, nil)
+	}
+}
+
+// LightEmissionLevel ...
+func (NetherPortal) LightEmissionLevel() uint8 {
+	return 11
+}
+
+// HasLiquidDrops ...
+func (NetherPortal) HasLiquidDrops() bool {
+	return false
+}
+
+// EncodeBlock ...
+func (p NetherPortal) EncodeBlock() (string, map[string]any) {
+	return "minecraft:portal", map[string]any{"portal_axis": p.Axis.String()}
+}
+
+// allNetherPortals ...
+func allNetherPortals() []world.Block {
+	return []world.Block{NetherPortal{Axis: cube.X}, NetherPortal{Axis: cube.Z}}
+}
+
+const (
+	// minPortalWidth and minPortalHeight are the minimum width and height of the inside of a nether portal frame.
+	minPortalWidth, minPortalHeight = 2, 3
+	// maxPortalSize is the maximum width and height of the inside of a nether portal frame.
+	maxPortalSize = 21
+)
+
+// portalShape describes the inside of a nether portal frame. It spans width blocks along axis and height blocks
+// upwards, starting at origin.
+type portalShape struct {
+	origin        cube.Pos
+	axis          cube.Axis
+	width, height int
+}
+
+// positions returns all positions inside the frame of the portalShape.
+func (s portalShape) positions() []cube.Pos {
+	positions := make([]cube.Pos, 0, s.width*s.height)
+	for y := 0; y < s.height; y++ {
+		for i := 0; i < s.width; i++ {
+			positions = append(positions, s.origin.Add(portalOffset(s.axis, i, y)))
+		}
+	}
+	return positions
+}
+
+// lightNetherPortal attempts to activate a nether portal using an obsidian frame surrounding the position passed,
+// checking both horizontal axes. If a valid frame is found, its inside is filled with portal blocks and true is
+// returned.
+func lightNetherPortal(pos cube.Pos, w *world.World) bool {
+	for _, axis := range []cube.Axis{cube.X, cube.Z} {
+		if shape, ok := netherPortalShape(pos, axis, w, false); ok {
+			for _, p := range shape.positions() {
+				w.SetBlock(p, NetherPortal{Axis: axis}, nil)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// netherPortalShape finds the inside of the obsidian frame of a nether portal along the axis passed that contains
+// the position passed. If active is true, the inside of the frame must consist of nether portal blocks aligned with
+// the axis. Otherwise, it must consist of air or fire, so that the portal may be activated.
+func netherPortalShape(pos cube.Pos, axis cube.Axis, w *world.World, active bool) (portalShape, bool) {
+	inside := func(pos cube.Pos) bool {
+		switch b := w.Block(pos).(type) {
+		case NetherPortal:
+			return active && b.Axis == axis
+		case Air, Fire:
+			return !active
+		}
+		return false
+	}
+	if !inside(pos) {
+		return portalShape{}, false
+	}
+	r := w.Range()
+	for i := 0; i < maxPortalSize && pos[1] > r[0] && inside(pos.Side(cube.FaceDown)); i++ {
+		pos = pos.Side(cube.FaceDown)
+	}
+	for i := 0; i < maxPortalSize && inside(pos.Sub(portalOffset(axis, 1, 0))); i++ {
+		pos = pos.Sub(portalOffset(axis, 1, 0))
+	}
+	if !portalFrame(pos.Sub(portalOffset(axis, 1, 0)), w) {
+		return portalShape{}, false
+	}
+
+	width := 0
+	for ; width < maxPortalSize; width++ {
+		p := pos.Add(portalOffset(axis, width, 0))
+		if !inside(p) {
+			break
+		}
+		if !portalFrame(p.Side(cube.FaceDown), w) {
+			return portalShape{}, false
+		}
+	}
+	if width < minPortalWidth || !portalFrame(pos.Add(portalOffset(axis, width, 0)), w) {
+		return portalShape{}, false
+	}
+
+	height := 0
+rows:
+	for ; height < maxPortalSize; height++ {
+		if !portalFrame(pos.Add(portalOffset(axis, -1, height)), w) || !portalFrame(pos.Add(portalOffset(axis, width, height)), w) {
+			break
+		}
+		for i := 0; i < width; i++ {
+			if !inside(pos.Add(portalOffset(axis, i, height))) {
+				break rows
+			}
+		}
+	}
+	if height < minPortalHeight {
+		return portalShape{}, false
+	}
+	for i := 0; i < width; i++ {
+		if !portalFrame(pos.Add(portalOffset(axis, i, height)), w) {
+			return portalShape{}, false
+		}
+	}
+	return portalShape{origin: pos, axis: axis, width: width, height: height}, true
+}
+
+// portalFrame checks if the block at the position passed may be part of the frame of a nether portal.
+func portalFrame(pos cube.Pos, w *world.World) bool {
+	o, ok := w.Block(pos).(Obsidian)
+	return ok && !o.Crying
+}
+
+// portalOffset returns the offset of a position i blocks along the axis passed and y blocks upwards.
+func portalOffset(axis cube.Axis, i, y int) cube.Pos {
+	if axis == cube.X {
+		return cube.Pos{i, y, 0}
+	}
+	return cube.Pos{0, y, i}
+}
+
+// FindNetherPortal searches for an active nether portal within a horizontal radius around the position passed. If
+// found, the position of the bottom portal block closest to pos is returned. Chunks within the radius that are not
+// yet loaded are loaded or generated, so searching a large radius may take a while: FindNetherPortal should not be
+// called on the goroutine that ticks the world.
+func FindNetherPortal(w *world.World, pos cube.Pos, radius int) (cube.Pos, bool) {
+	r := w.Range()
+	var (
+		found cube.Pos
+		dist  = math.MaxInt
+	)
+	for x := pos[0] - radius; x <= pos[0]+radius; x++ {
+		for z := pos[2] - radius; z <= pos[2]+radius; z++ {
+			if x&1 != 0 && z&1 != 0 {
+				// A portal is at least two blocks wide along either the X or the Z axis, so it always covers a
+				// column with an even X or Z coordinate.
+				continue
+			}
+			// Portals are at least three blocks high, so we only need to check every third block.
+			for y := r[0]; y <= r[1]; y += 3 {
+				p := cube.Pos{x, y, z}
+				if _, ok := w.Block(p).(NetherPortal); !ok {
+					continue
+				}
+				for p[1] > r[0] {
+					if _, ok := w.Block(p.Side(cube.FaceDown)).(NetherPortal); !ok {
+						break
+					}
+					p = p.Side(cube.FaceDown)
+				}
+				diff := p.Sub(pos)
+				if d := diff[0]*diff[0] + diff[1]*diff[1] + diff[2]*diff[2]; d < dist {
+					found, dist = p, d
+				}
+			}
+		}
+	}
+	return found, dist != math.MaxInt
+}
+
+// CreateNetherPortal creates a new, active nether portal along the axis passed close to the position passed. A
+// suitable location on solid ground is searched for within a radius of 16 blocks using NetherPortalSite. If none
+// could be found, the portal is created at pos on top of a small obsidian platform. The position of the bottom
+// portal block of the new portal is returned.
+func CreateNetherPortal(w *world.World, pos cube.Pos, axis cube.Axis) cube.Pos {
+	origin, found := NetherPortalSite(w, pos, axis)
+	BuildNetherPortal(w, origin, axis, !found)
+	return origin
+}
+
+// NetherPortalSite searches for a location on solid ground within a radius of 16 blocks around pos at which a nether
+// portal along the axis passed may be built without replacing any blocks. The origin of the closest location found
+// and true are returned. If none could be found, pos, moved within the height range of the world, and false are
+// returned. Because NetherPortalSite reads many blocks, it should not be called on the goroutine that ticks the
+// world.
+func NetherPortalSite(w *world.World, pos cube.Pos, axis cube.Axis) (cube.Pos, bool) {
+	r := w.Range()
+	pos[1] = int(math.Max(float64(r[0]+1), math.Min(float64(pos[1]), float64(r[1]-minPortalHeight-1))))
+	if origin, ok := portalSite(w, pos, axis, 16); ok {
+		return origin, true
+	}
+	return pos, false
+}
+
+// BuildNetherPortal builds an active nether portal along the axis passed with its bottom portal block at origin. If
+// platform is true, a small obsidian platform is built below the portal and the space around it is cleared first,
+// such as when no site could be found using NetherPortalSite.
+func BuildNetherPortal(w *world.World, origin cube.Pos, axis cube.Axis, platform bool) {
+	if platform {
+		side := portalOffset(axis.RotateLeft(), 1, 0)
+		for i := -1; i <= minPortalWidth; i++ {
+			for _, s := range []cube.Pos{side, {}, {-side[0], 0, -side[2]}} {
+				base := origin.Add(portalOffset(axis, i, 0)).Add(s)
+				w.SetBlock(base.Side(cube.FaceDown), Obsidian{}, nil)
+				for y := 0; y <= minPortalHeight; y++ {
+					w.SetBlock(base.Add(cube.Pos{0, y}), nil, nil)
+				}
+			}
+		}
+	}
+	for i := -1; i <= minPortalWidth; i++ {
+		for y := -1; y <= minPortalHeight; y++ {
+			p := origin.Add(portalOffset(axis, i, y))
+			if i == -1 || i == minPortalWidth || y == -1 || y == minPortalHeight {
+				w.SetBlock(p, Obsidian{}, nil)
+				continue
+			}
+			w.SetBlock(p, NetherPortal{Axis: axis}, nil)
+		}
+	}
+}
+
+// portalSite searches for a location close to pos at which a nether portal along the axis passed may be created
+// without replacing any blocks other than the ground below it. The origin of the closest location found is
+// returned.
+func portalSite(w *world.World, pos cube.Pos, axis cube.Axis, radius int) (cube.Pos, bool) {
+	r := w.Range()
+	var (
+		found cube.Pos
+		dist  = math.MaxInt
+	)
+	for x := pos[0] - radius; x <= pos[0]+radius; x++ {
+		for z := pos[2] - radius; z <= pos[2]+radius; z++ {
+			for y := r[0] + 1; y < r[1]-minPortalHeight; y++ {
+				origin := cube.Pos{x, y, z}
+				diff := origin.Sub(pos)
+				d := diff[0]*diff[0] + diff[1]*diff[1] + diff[2]*diff[2]
+				if d >= dist {
+					continue
+				}
+				if _, air := w.Block(origin).(Air); !air || !portalGround(origin.Side(cube.FaceDown), w) {
+					continue
+				}
+				if portalSiteClear(origin, axis, w) {
+					found, dist = origin, d
+				}
+			}
+		}
+	}
+	return found, dist != math.MaxInt
+}
+
+// portalSiteClear checks if a nether portal with its origin at the position passed may be placed along the axis
+// passed: The frame must be on top of solid ground and the space it occupies must be empty.
+func portalSiteClear(origin cube.Pos, axis cube.Axis, w *world.World) bool {
+	for i := -1; i <= minPortalWidth; i++ {
+		if !portalGround(origin.Add(portalOffset(axis, i, -1)), w) {
+			return false
+		}
+		for y := 0; y <= minPortalHeight; y++ {
+			if _, air := w.Block(origin.Add(portalOffset(axis, i, y))).(Air); !air {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// portalGround checks if the block at the position passed is solid enough for a nether portal to be built on.
+func portalGround(pos cube.Pos, w *world.World) bool {
+	if _, ok := w.Liquid(pos); ok {
+		return false
+	}
+	return w.Block(pos).Model().FaceSolid(pos, cube.FaceUp, w)
+}
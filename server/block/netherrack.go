@@ -17,6 +17,11 @@ func (n Netherrack) SoilFor(block world.Block) bool {
 	return ok && flower.Type == WitherRose()
 }
 
+// InfinitelyBurning ...
+func (Netherrack) InfinitelyBurning() bool {
+	return true
+}
+
 // BreakInfo ...
 func (n Netherrack) BreakInfo() BreakInfo {
 	return newBreakInfo(0.4, pickaxeHarvestable, pickaxeEffective, oneOf(n))
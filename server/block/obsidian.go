@@ -39,7 +39,5 @@ func (o Obsidian) EncodeBlock() (string, map[string]any) {
 
 // BreakInfo ...
 func (o Obsidian) BreakInfo() BreakInfo {
-	return newBreakInfo(35, func(t item.Tool) bool {
-		return t.ToolType() == item.TypePickaxe && t.HarvestLevel() >= item.ToolTierDiamond.HarvestLevel
-	}, pickaxeEffective, oneOf(o)).withBlastResistance(6000)
+	return newBreakInfo(35, pickaxeTierHarvestable(item.ToolTierDiamond), pickaxeEffective, oneOf(o)).withBlastResistance(6000)
 }
@@ -0,0 +1,31 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Openable represents a block that may be opened and closed, either by a player interacting with it or by redstone
+// power, such as a door, trapdoor or fence gate.
+type Openable interface {
+	world.Block
+	// Opened returns whether the block is currently open.
+	Opened() bool
+	// SetOpen opens or closes the block at the position passed. Any other blocks that are part of the same
+	// openable block are updated too, and the sound that belongs to opening or closing the block is played.
+	SetOpen(pos cube.Pos, w *world.World, open bool)
+}
+
+// updateOpenable opens or closes the Openable block at the position passed so that it matches the redstone power
+// it receives. The state is only changed if the neighbour that changed could have been a source of redstone power,
+// so that blocks opened by hand aren't closed by unrelated changes around them.
+func updateOpenable(o Openable, pos, changedNeighbour cube.Pos, w *world.World, powered bool) {
+	switch w.Block(changedNeighbour).(type) {
+	case RedstoneSource, Air, Moving:
+	default:
+		return
+	}
+	if o.Opened() != powered {
+		o.SetOpen(pos, w, powered)
+	}
+}
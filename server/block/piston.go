@@ -0,0 +1,340 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"github.com/df-mc/dragonfly/server/world/sound"
+	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
+	"time"
+)
+
+// Piston is a block that extends when it receives redstone power, pushing up to 12 blocks in front of it. Sticky
+// pistons additionally pull back the block in front of them when they lose power and retract.
+type Piston struct {
+	solid
+	bassDrum
+
+	// Facing is the face that the piston is facing. This is the direction in which the piston extends and pushes
+	// blocks.
+	Facing cube.Face
+	// Sticky specifies if the piston is a sticky piston. Sticky pistons pull back the block in front of their arm
+	// when retracting.
+	Sticky bool
+
+	// state is the current state of the piston. progress and lastProgress are the progress of the arm of the
+	// piston, ranging from 0 (retracted) to 1 (extended), during the current and the previous tick.
+	state                  pistonState
+	progress, lastProgress float64
+	// attached holds the positions of the blocks that are currently being moved by the piston, before they were
+	// moved. broken holds the positions of the blocks that were destroyed by the piston while extending.
+	attached, broken []cube.Pos
+}
+
+// pistonState is the state of a piston.
+type pistonState uint8
+
+const (
+	pistonRetracted pistonState = iota
+	pistonExtending
+	pistonExtended
+	pistonRetracting
+)
+
+// pistonPushLimit is the maximum number of blocks that a piston can push at once.
+const pistonPushLimit = 12
+
+// Extended checks if the piston is currently extended or extending.
+func (p Piston) Extended() bool {
+	return p.state == pistonExtending || p.state == pistonExtended
+}
+
+// UseOnBlock ...
+func (p Piston) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, _, used := firstReplaceable(w, pos, face, p)
+	if !used {
+		return false
+	}
+	p.Facing = calculateFace(user, pos)
+
+	place(w, pos, p, user, ctx)
+	if placed(ctx) {
+		w.ScheduleBlockUpdate(pos, time.Millisecond*50)
+		return true
+	}
+	return false
+}
+
+// NeighbourUpdateTick ...
+func (p Piston) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	w.ScheduleBlockUpdate(pos, time.Millisecond*50)
+}
+
+// ScheduledTick extends or retracts the piston if its redstone power changed and moves the arm of the piston if
+// it is currently extending or retracting.
+func (p Piston) ScheduledTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	switch p.state {
+	case pistonRetracted:
+		if p.powered(pos, w) {
+			p.extend(pos, w)
+		}
+	case pistonExtended:
+		if !p.powered(pos, w) {
+			p.retract(pos, w)
+		}
+	default:
+		p.move(pos, w)
+	}
+}
+
+// powered checks if the piston at the position passed receives redstone power. Pistons are not powered through
+// the face that they are facing.
+func (p Piston) powered(pos cube.Pos, w *world.World) bool {
+	return receivedRedstonePower(pos, w, p.Facing) > 0
+}
+
+// extend starts extending the piston at the position passed, pushing the blocks in front of it. Nothing happens
+// if the blocks in front of the piston cannot be pushed.
+func (p Piston) extend(pos cube.Pos, w *world.World) {
+	attached, broken, ok := pistonPushable(pos, p.Facing, w)
+	if !ok {
+		return
+	}
+	for _, b := range broken {
+		bl := w.Block(b)
+		w.SetBlock(b, nil, nil)
+		w.AddParticle(b.Vec3Centre(), particle.BlockBreak{Block: bl})
+		if breakable, ok := bl.(Breakable); ok {
+			for _, drop := range breakable.BreakInfo().Drops(item.ToolNone{}, nil, w.Rand()) {
+				dropItem(w, drop, b.Vec3Centre())
+			}
+		}
+	}
+	p.moveBlocks(pos, attached, p.Facing, w)
+	w.SetBlock(pos.Side(p.Facing), PistonArmCollision{Facing: p.Facing, Sticky: p.Sticky}, nil)
+
+	p.state, p.progress, p.lastProgress, p.attached, p.broken = pistonExtending, 0, 0, attached, broken
+	w.SetBlock(pos, p, nil)
+	w.PlaySound(pos.Vec3Centre(), sound.PistonExtend{})
+	w.ScheduleBlockUpdate(pos, time.Millisecond*50)
+}
+
+// retract starts retracting the piston at the position passed, removing its arm. Sticky pistons pull back the
+// block in front of their arm if it can be moved.
+func (p Piston) retract(pos cube.Pos, w *world.World) {
+	armPos := pos.Side(p.Facing)
+	if _, ok := w.Block(armPos).(PistonArmCollision); ok {
+		w.SetBlock(armPos, nil, nil)
+	}
+	var attached []cube.Pos
+	if front := armPos.Side(p.Facing); p.Sticky && !front.OutOfBounds(w.Range()) {
+		if b := w.Block(front); !pistonReplaces(b) && !pistonBreaks(front, b, w) && pistonMovable(b) {
+			attached = []cube.Pos{front}
+			p.moveBlocks(pos, attached, p.Facing.Opposite(), w)
+		}
+	}
+	p.state, p.progress, p.lastProgress, p.attached, p.broken = pistonRetracting, 1, 1, attached, nil
+	w.SetBlock(pos, p, nil)
+	w.PlaySound(pos.Vec3Centre(), sound.PistonRetract{})
+	w.ScheduleBlockUpdate(pos, time.Millisecond*50)
+}
+
+// move moves the arm of the piston at the position passed a step further. Once the arm is fully extended or
+// retracted, the blocks moved by the piston are placed at their new positions.
+func (p Piston) move(pos cube.Pos, w *world.World) {
+	p.lastProgress = p.progress
+	if p.state == pistonExtending {
+		p.progress += 0.5
+	} else {
+		p.progress -= 0.5
+	}
+	if p.progress > 0 && p.progress < 1 {
+		w.SetBlock(pos, p, nil)
+		w.ScheduleBlockUpdate(pos, time.Millisecond*50)
+		return
+	}
+	p.finish(pos, w)
+	// The redstone power of the piston might have changed while it was moving, so we check it again.
+	w.ScheduleBlockUpdate(pos, time.Millisecond*50)
+}
+
+// finish finishes the movement of the piston at the position passed, placing all blocks that it moved at their
+// new positions.
+func (p Piston) finish(pos cube.Pos, w *world.World) {
+	p.placeMoved(pos, w)
+	if p.state == pistonExtending {
+		p.state, p.progress, p.lastProgress = pistonExtended, 1, 1
+	} else {
+		p.state, p.progress, p.lastProgress = pistonRetracted, 0, 0
+	}
+	p.attached, p.broken = nil, nil
+	w.SetBlock(pos, p, nil)
+}
+
+// placeMoved places the blocks currently being moved by the piston at the position passed at their new positions.
+func (p Piston) placeMoved(pos cube.Pos, w *world.World) {
+	dir := p.Facing
+	if p.state == pistonRetracting {
+		dir = dir.Opposite()
+	}
+	for _, a := range p.attached {
+		if m, ok := w.Block(a.Side(dir)).(Moving); ok && m.piston == pos {
+			w.SetBlock(a.Side(dir), m.block, nil)
+		}
+	}
+}
+
+// moveBlocks replaces the blocks at the positions passed with Moving blocks one block further in the direction
+// passed. The blocks are placed again when the piston at the position passed finishes moving.
+func (p Piston) moveBlocks(pos cube.Pos, blocks []cube.Pos, dir cube.Face, w *world.World) {
+	moved := make([]world.Block, len(blocks))
+	for i, b := range blocks {
+		moved[i] = w.Block(b)
+		w.SetBlock(b, nil, nil)
+	}
+	for i, b := range blocks {
+		w.SetBlock(b.Side(dir), Moving{block: moved[i], piston: pos}, nil)
+	}
+}
+
+// pistonPushable finds the blocks that are pushed by a piston at the position passed, facing the face passed. The
+// blocks that are moved and the blocks that are destroyed are returned. If the blocks in front of the piston cannot
+// be pushed, false is returned.
+func pistonPushable(pos cube.Pos, face cube.Face, w *world.World) (attached, broken []cube.Pos, ok bool) {
+	for cur := pos.Side(face); !cur.OutOfBounds(w.Range()); cur = cur.Side(face) {
+		b := w.Block(cur)
+		if pistonReplaces(b) {
+			return attached, broken, true
+		}
+		if pistonBreaks(cur, b, w) {
+			return attached, append(broken, cur), true
+		}
+		if !pistonMovable(b) || len(attached) == pistonPushLimit {
+			return nil, nil, false
+		}
+		attached = append(attached, cur)
+	}
+	return nil, nil, false
+}
+
+// pistonReplaces checks if a piston moving a block into the block passed replaces the block without destroying it.
+func pistonReplaces(b world.Block) bool {
+	switch b.(type) {
+	case Air, world.Liquid:
+		return true
+	}
+	return false
+}
+
+// pistonBreaks checks if the block passed at the position passed is destroyed when a piston pushes a block into
+// it. This is the case for blocks without a collision box, such as flowers, and blocks that may be replaced.
+func pistonBreaks(pos cube.Pos, b world.Block, w *world.World) bool {
+	if _, ok := b.(Replaceable); ok {
+		return true
+	}
+	return len(b.Model().BBox(pos, w)) == 0
+}
+
+// pistonMovable checks if the block passed may be moved by a piston.
+func pistonMovable(b world.Block) bool {
+	switch b := b.(type) {
+	case Piston:
+		return !b.Extended() && b.state != pistonRetracting
+	case PistonArmCollision, Moving, Obsidian, EnderChest, EnchantingTable:
+		return false
+	}
+	// Blocks that cannot be broken, such as bedrock, cannot be moved either.
+	_, ok := b.(Breakable)
+	return ok
+}
+
+// BreakInfo ...
+func (p Piston) BreakInfo() BreakInfo {
+	return newBreakInfo(1.5, alwaysHarvestable, pickaxeEffective, oneOf(Piston{Sticky: p.Sticky})).withBreakHandler(func(pos cube.Pos, w *world.World, _ item.User) {
+		p.placeMoved(pos, w)
+		if _, ok := w.Block(pos.Side(p.Facing)).(PistonArmCollision); ok {
+			w.SetBlock(pos.Side(p.Facing), nil, nil)
+		}
+	})
+}
+
+// EncodeItem ...
+func (p Piston) EncodeItem() (name string, meta int16) {
+	if p.Sticky {
+		return "minecraft:sticky_piston", 0
+	}
+	return "minecraft:piston", 0
+}
+
+// EncodeBlock ...
+func (p Piston) EncodeBlock() (string, map[string]any) {
+	if p.Sticky {
+		return "minecraft:sticky_piston", map[string]any{"facing_direction": pistonFacing(p.Facing)}
+	}
+	return "minecraft:piston", map[string]any{"facing_direction": pistonFacing(p.Facing)}
+}
+
+// DecodeNBT ...
+func (p Piston) DecodeNBT(data map[string]any) any {
+	p.state = pistonState(nbtconv.Uint8(data, "State"))
+	p.progress, p.lastProgress = float64(nbtconv.Float32(data, "Progress")), float64(nbtconv.Float32(data, "LastProgress"))
+	switch p.state {
+	case pistonExtending:
+		// Movement of the piston is not continued after loading it. Blocks that were being moved are placed by
+		// themselves.
+		p.state, p.progress, p.lastProgress = pistonExtended, 1, 1
+	case pistonRetracting:
+		p.state, p.progress, p.lastProgress = pistonRetracted, 0, 0
+	}
+	return p
+}
+
+// EncodeNBT ...
+func (p Piston) EncodeNBT() map[string]any {
+	newState := p.state
+	switch p.state {
+	case pistonExtending:
+		newState = pistonExtended
+	case pistonRetracting:
+		newState = pistonRetracted
+	}
+	return map[string]any{
+		"id":             "PistonArm",
+		"State":          uint8(p.state),
+		"NewState":       uint8(newState),
+		"Progress":       float32(p.progress),
+		"LastProgress":   float32(p.lastProgress),
+		"Sticky":         boolByte(p.Sticky),
+		"AttachedBlocks": pistonPositions(p.attached),
+		"BreakBlocks":    pistonPositions(p.broken),
+	}
+}
+
+// pistonPositions encodes the positions passed to a list of coordinates, as used in the NBT of a piston.
+func pistonPositions(positions []cube.Pos) []any {
+	l := make([]any, 0, len(positions)*3)
+	for _, pos := range positions {
+		l = append(l, int32(pos[0]), int32(pos[1]), int32(pos[2]))
+	}
+	return l
+}
+
+// pistonFacing returns the value of the facing_direction block property of pistons and piston arms facing the
+// face passed. Unlike other blocks, the horizontal faces of pistons are inverted.
+func pistonFacing(face cube.Face) int32 {
+	if face.Axis() == cube.Y {
+		return int32(face)
+	}
+	return int32(face.Opposite())
+}
+
+// allPistons ...
+func allPistons() (b []world.Block) {
+	for _, f := range cube.Faces() {
+		b = append(b, Piston{Facing: f}, Piston{Facing: f, Sticky: true})
+	}
+	return
+}
@@ -0,0 +1,67 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/model"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// PistonArmCollision is the block placed in front of an extended piston, holding the arm and head of the piston.
+// It is removed when the piston retracts.
+type PistonArmCollision struct {
+	transparent
+	sourceWaterDisplacer
+
+	// Facing is the face that the piston that the arm belongs to is facing.
+	Facing cube.Face
+	// Sticky specifies if the arm belongs to a sticky piston.
+	Sticky bool
+}
+
+// Model ...
+func (p PistonArmCollision) Model() world.BlockModel {
+	return model.PistonArmCollision{Facing: p.Facing}
+}
+
+// SideClosed ...
+func (PistonArmCollision) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
+// NeighbourUpdateTick removes the arm if the piston that it belongs to was removed.
+func (p PistonArmCollision) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if piston, ok := w.Block(pos.Side(p.Facing.Opposite())).(Piston); !ok || piston.Facing != p.Facing || !piston.Extended() {
+		w.SetBlock(pos, nil, nil)
+	}
+}
+
+// BreakInfo ...
+func (p PistonArmCollision) BreakInfo() BreakInfo {
+	return newBreakInfo(1.5, alwaysHarvestable, pickaxeEffective, simpleDrops()).withBreakHandler(func(pos cube.Pos, w *world.World, u item.User) {
+		// Breaking the arm of a piston breaks the piston itself.
+		basePos := pos.Side(p.Facing.Opposite())
+		if piston, ok := w.Block(basePos).(Piston); ok && piston.Facing == p.Facing {
+			BreakWithFeedback(w, basePos, nil)
+			if g, ok := u.(interface{ GameMode() world.GameMode }); !ok || !g.GameMode().CreativeInventory() {
+				dropItem(w, item.NewStack(Piston{Sticky: piston.Sticky}, 1), basePos.Vec3Centre())
+			}
+		}
+	})
+}
+
+// EncodeBlock ...
+func (p PistonArmCollision) EncodeBlock() (string, map[string]any) {
+	if p.Sticky {
+		return "minecraft:sticky_piston_arm_collision", map[string]any{"facing_direction": pistonFacing(p.Facing)}
+	}
+	return "minecraft:piston_arm_collision", map[string]any{"facing_direction": pistonFacing(p.Facing)}
+}
+
+// allPistonArmCollisions ...
+func allPistonArmCollisions() (b []world.Block) {
+	for _, f := range cube.Faces() {
+		b = append(b, PistonArmCollision{Facing: f}, PistonArmCollision{Facing: f, Sticky: true})
+	}
+	return
+}
@@ -47,7 +47,7 @@ func (p Potato) BoneMeal(pos cube.Pos, w *world.World) bool {
 	if p.Growth == 7 {
 		return false
 	}
-	p.Growth = min(p.Growth+rand.Intn(4)+2, 7)
+	p.Growth = min(p.Growth+w.Rand().Intn(4)+2, 7)
 	w.SetBlock(pos, p, nil)
 	return true
 }
@@ -69,11 +69,11 @@ func (p Potato) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.
 
 // BreakInfo ...
 func (p Potato) BreakInfo() BreakInfo {
-	return newBreakInfo(0, alwaysHarvestable, nothingEffective, func(item.Tool, []item.Enchantment) []item.Stack {
-		if rand.Float64() < 0.02 {
-			return []item.Stack{item.NewStack(p, rand.Intn(5)+1), item.NewStack(item.PoisonousPotato{}, 1)}
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, func(_ item.Tool, _ []item.Enchantment, r *rand.Rand) []item.Stack {
+		if r.Float64() < 0.02 {
+			return []item.Stack{item.NewStack(p, r.Intn(5)+1), item.NewStack(item.PoisonousPotato{}, 1)}
 		}
-		return []item.Stack{item.NewStack(p, rand.Intn(5)+1)}
+		return []item.Stack{item.NewStack(p, r.Intn(5)+1)}
 	})
 }
 
@@ -68,7 +68,7 @@ func (p PumpkinSeeds) BoneMeal(pos cube.Pos, w *world.World) bool {
 	if p.Growth == 7 {
 		return false
 	}
-	p.Growth = min(p.Growth+rand.Intn(4)+2, 7)
+	p.Growth = min(p.Growth+w.Rand().Intn(4)+2, 7)
 	w.SetBlock(pos, p, nil)
 	return true
 }
@@ -0,0 +1,57 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"golang.org/x/exp/slices"
+)
+
+// RedstoneSource represents a block that emits redstone power to the blocks around it, such as a block of redstone.
+type RedstoneSource interface {
+	// RedstonePower returns the redstone power, ranging from 0 to 15, that the block at the position passed emits
+	// towards the face passed.
+	RedstonePower(pos cube.Pos, face cube.Face, w *world.World) int
+}
+
+// Redstone is a mineral block made from 9 redstone dust. A block of redstone is a permanent source of redstone
+// power.
+type Redstone struct {
+	solid
+	bassDrum
+}
+
+// RedstonePower always returns 15: A block of redstone powers all blocks around it.
+func (Redstone) RedstonePower(cube.Pos, cube.Face, *world.World) int {
+	return 15
+}
+
+// BreakInfo ...
+func (r Redstone) BreakInfo() BreakInfo {
+	return newBreakInfo(5, pickaxeHarvestable, pickaxeEffective, oneOf(r)).withBlastResistance(30)
+}
+
+// EncodeItem ...
+func (Redstone) EncodeItem() (name string, meta int16) {
+	return "minecraft:redstone_block", 0
+}
+
+// EncodeBlock ...
+func (Redstone) EncodeBlock() (name string, properties map[string]any) {
+	return "minecraft:redstone_block", nil
+}
+
+// receivedRedstonePower returns the highest redstone power that the block at the position passed receives from the
+// blocks around it, ignoring the faces passed.
+func receivedRedstonePower(pos cube.Pos, w *world.World, ignored ...cube.Face) (power int) {
+	for _, face := range cube.Faces() {
+		if slices.Contains(ignored, face) {
+			continue
+		}
+		if src, ok := w.Block(pos.Side(face)).(RedstoneSource); ok {
+			if p := src.RedstonePower(pos.Side(face), face.Opposite(), w); p > power {
+				power = p
+			}
+		}
+	}
+	return power
+}
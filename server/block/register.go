@@ -10,6 +10,7 @@ import (
 // init registers all blocks implemented by Dragonfly.
 func init() {
 	world.RegisterBlock(Air{})
+	world.RegisterBlock(Allow{})
 	world.RegisterBlock(Amethyst{})
 	world.RegisterBlock(AncientDebris{})
 	world.RegisterBlock(Andesite{Polished: true})
@@ -28,6 +29,7 @@ func init() {
 	world.RegisterBlock(Cobblestone{})
 	world.RegisterBlock(CraftingTable{})
 	world.RegisterBlock(DeadBush{})
+	world.RegisterBlock(Deny{})
 	world.RegisterBlock(DeepslateBricks{Cracked: true})
 	world.RegisterBlock(DeepslateBricks{})
 	world.RegisterBlock(DeepslateTiles{Cracked: true})
@@ -62,6 +64,7 @@ func init() {
 	world.RegisterBlock(Lapis{})
 	world.RegisterBlock(Melon{})
 	world.RegisterBlock(MossCarpet{})
+	world.RegisterBlock(Moving{})
 	world.RegisterBlock(MudBricks{})
 	world.RegisterBlock(Mud{})
 	world.RegisterBlock(NetherBrickFence{})
@@ -84,6 +87,7 @@ func init() {
 	world.RegisterBlock(RawCopper{})
 	world.RegisterBlock(RawGold{})
 	world.RegisterBlock(RawIron{})
+	world.RegisterBlock(Redstone{})
 	world.RegisterBlock(ReinforcedDeepslate{})
 	world.RegisterBlock(Sand{Red: true})
 	world.RegisterBlock(Sand{})
@@ -116,10 +120,12 @@ func init() {
 	registerAll(allBanners())
 	registerAll(allBarrels())
 	registerAll(allBasalt())
+	registerAll(allBeds())
 	registerAll(allBeetroot())
 	registerAll(allBlackstone())
 	registerAll(allBlastFurnaces())
 	registerAll(allBoneBlock())
+	registerAll(allBorders())
 	registerAll(allCactus())
 	registerAll(allCake())
 	registerAll(allCarpet())
@@ -147,6 +153,7 @@ func init() {
 	registerAll(allGlazedTerracotta())
 	registerAll(allGrindstones())
 	registerAll(allHayBales())
+	registerAll(allHoppers())
 	registerAll(allItemFrames())
 	registerAll(allKelp())
 	registerAll(allLadders())
@@ -161,7 +168,10 @@ func init() {
 	registerAll(allMelonStems())
 	registerAll(allMuddyMangroveRoots())
 	registerAll(allNetherBricks())
+	registerAll(allNetherPortals())
 	registerAll(allNetherWart())
+	registerAll(allPistonArmCollisions())
+	registerAll(allPistons())
 	registerAll(allPlanks())
 	registerAll(allPotato())
 	registerAll(allPrismarine())
@@ -195,6 +205,7 @@ func init() {
 
 func init() {
 	world.RegisterItem(Air{})
+	world.RegisterItem(Allow{})
 	world.RegisterItem(Amethyst{})
 	world.RegisterItem(AncientDebris{})
 	world.RegisterItem(Andesite{Polished: true})
@@ -210,6 +221,7 @@ func init() {
 	world.RegisterItem(BlueIce{})
 	world.RegisterItem(Bone{})
 	world.RegisterItem(Bookshelf{})
+	world.RegisterItem(Border{})
 	world.RegisterItem(Bricks{})
 	world.RegisterItem(Cactus{})
 	world.RegisterItem(Cake{})
@@ -226,6 +238,7 @@ func init() {
 	world.RegisterItem(Composter{})
 	world.RegisterItem(CraftingTable{})
 	world.RegisterItem(DeadBush{})
+	world.RegisterItem(Deny{})
 	world.RegisterItem(DeepslateBricks{Cracked: true})
 	world.RegisterItem(DeepslateBricks{})
 	world.RegisterItem(DeepslateTiles{Cracked: true})
@@ -257,6 +270,7 @@ func init() {
 	world.RegisterItem(Grindstone{})
 	world.RegisterItem(HayBale{})
 	world.RegisterItem(Honeycomb{})
+	world.RegisterItem(Hopper{})
 	world.RegisterItem(InvisibleBedrock{})
 	world.RegisterItem(IronBars{})
 	world.RegisterItem(Iron{})
@@ -289,6 +303,8 @@ func init() {
 	world.RegisterItem(Obsidian{})
 	world.RegisterItem(PackedIce{})
 	world.RegisterItem(PackedMud{})
+	world.RegisterItem(Piston{Sticky: true})
+	world.RegisterItem(Piston{})
 	world.RegisterItem(Podzol{})
 	world.RegisterItem(PolishedBlackstoneBrick{Cracked: true})
 	world.RegisterItem(PolishedBlackstoneBrick{})
@@ -305,6 +321,7 @@ func init() {
 	world.RegisterItem(RawCopper{})
 	world.RegisterItem(RawGold{})
 	world.RegisterItem(RawIron{})
+	world.RegisterItem(Redstone{})
 	world.RegisterItem(ReinforcedDeepslate{})
 	world.RegisterItem(Sand{Red: true})
 	world.RegisterItem(Sand{})
@@ -353,6 +370,7 @@ func init() {
 	}
 	for _, c := range item.Colours() {
 		world.RegisterItem(Banner{Colour: c})
+		world.RegisterItem(Bed{Colour: c})
 		world.RegisterItem(Carpet{Colour: c})
 		world.RegisterItem(ConcretePowder{Colour: c})
 		world.RegisterItem(Concrete{Colour: c})
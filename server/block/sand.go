@@ -27,7 +27,7 @@ func (s Sand) SoilFor(block world.Block) bool {
 
 // NeighbourUpdateTick ...
 func (s Sand) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
-	s.fall(s, pos, w)
+	Fall(s, pos, w)
 }
 
 // BreakInfo ...
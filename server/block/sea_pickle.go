@@ -7,7 +7,6 @@ import (
 	"github.com/df-mc/dragonfly/server/world/particle"
 	"github.com/go-gl/mathgl/mgl64"
 	"math"
-	"math/rand"
 )
 
 // SeaPickle is a small stationary underwater block that emits light, and is typically found in colonies of up to
@@ -54,7 +53,7 @@ func (s SeaPickle) BoneMeal(pos cube.Pos, w *world.World) bool {
 		distance := -int(math.Abs(float64(x))) + 2
 		for z := -distance; z <= distance; z++ {
 			for y := -1; y < 1; y++ {
-				if (x == 0 && y == 0 && z == 0) || rand.Intn(6) != 0 {
+				if (x == 0 && y == 0 && z == 0) || w.Rand().Intn(6) != 0 {
 					continue
 				}
 				newPos := pos.Add(cube.Pos{x, y, z})
@@ -65,7 +64,7 @@ func (s SeaPickle) BoneMeal(pos cube.Pos, w *world.World) bool {
 				if coral, ok := w.Block(newPos.Side(cube.FaceDown)).(CoralBlock); !ok || coral.Dead {
 					continue
 				}
-				w.SetBlock(newPos, SeaPickle{AdditionalCount: rand.Intn(3) + 1}, nil)
+				w.SetBlock(newPos, SeaPickle{AdditionalCount: w.Rand().Intn(3) + 1}, nil)
 			}
 		}
 	}
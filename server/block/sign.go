@@ -150,7 +150,7 @@ func (s Sign) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.Wo
 		s.Attach = WallAttachment(face.Direction())
 	}
 	place(w, pos, s, user, ctx)
-	if editor, ok := user.(SignEditor); ok {
+	if editor, ok := user.(SignEditor); ok && placed(ctx) {
 		editor.OpenSign(pos, true)
 	}
 	return placed(ctx)
@@ -187,6 +187,7 @@ func (s Sign) EncodeBlock() (name string, properties map[string]any) {
 
 // DecodeNBT ...
 func (s Sign) DecodeNBT(data map[string]any) any {
+	s.Waxed = nbtconv.Bool(data, "IsWaxed")
 	if nbtconv.String(data, "Text") != "" {
 		// The NBT format changed in 1.19.80 to have separate data for each side of the sign. The old format must still
 		// be supported for backwards compatibility.
@@ -195,45 +196,45 @@ func (s Sign) DecodeNBT(data map[string]any) any {
 		s.Front.Glowing = nbtconv.Bool(data, "IgnoreLighting") && nbtconv.Bool(data, "TextIgnoreLegacyBugResolved")
 		return s
 	}
-
-	front, ok := data["FrontText"].(map[string]any)
-	if ok {
-		s.Front.BaseColour = nbtconv.RGBAFromInt32(nbtconv.Int32(front, "Color"))
-		s.Front.Glowing = nbtconv.Bool(front, "GlowingText")
-		s.Front.Text = nbtconv.String(front, "Text")
-		s.Front.Owner = nbtconv.String(front, "Owner")
+	if front, ok := data["FrontText"].(map[string]any); ok {
+		s.Front = decodeSignText(front)
 	}
-
-	back, ok := data["BackText"].(map[string]any)
-	if ok {
-		s.Back.BaseColour = nbtconv.RGBAFromInt32(nbtconv.Int32(back, "Color"))
-		s.Back.Glowing = nbtconv.Bool(back, "GlowingText")
-		s.Back.Text = nbtconv.String(back, "Text")
-		s.Back.Owner = nbtconv.String(back, "Owner")
+	if back, ok := data["BackText"].(map[string]any); ok {
+		s.Back = decodeSignText(back)
 	}
-
 	return s
 }
 
+// decodeSignText decodes the SignText of a single side of a sign from the NBT data passed.
+func decodeSignText(data map[string]any) SignText {
+	return SignText{
+		Text:       nbtconv.String(data, "Text"),
+		BaseColour: nbtconv.RGBAFromInt32(nbtconv.Int32(data, "SignTextColor")),
+		Glowing:    nbtconv.Bool(data, "IgnoreLighting"),
+		Owner:      nbtconv.String(data, "TextOwner"),
+	}
+}
+
 // EncodeNBT ...
 func (s Sign) EncodeNBT() map[string]any {
-	m := map[string]any{
-		"id":      "Sign",
-		"IsWaxed": boolByte(s.Waxed),
-		"FrontText": map[string]any{
-			"SignTextColor":  nbtconv.Int32FromRGBA(s.Front.BaseColour),
-			"IgnoreLighting": boolByte(s.Front.Glowing),
-			"Text":           s.Front.Text,
-			"TextOwner":      s.Front.Owner,
-		},
-		"BackText": map[string]any{
-			"SignTextColor":  nbtconv.Int32FromRGBA(s.Back.BaseColour),
-			"IgnoreLighting": boolByte(s.Back.Glowing),
-			"Text":           s.Back.Text,
-			"TextOwner":      s.Back.Owner,
-		},
-	}
-	return m
+	return map[string]any{
+		"id":        "Sign",
+		"IsWaxed":   boolByte(s.Waxed),
+		"FrontText": encodeSignText(s.Front),
+		"BackText":  encodeSignText(s.Back),
+	}
+}
+
+// encodeSignText encodes a SignText of a single side of a sign to NBT data.
+func encodeSignText(t SignText) map[string]any {
+	return map[string]any{
+		"SignTextColor":     nbtconv.Int32FromRGBA(t.BaseColour),
+		"IgnoreLighting":    boolByte(t.Glowing),
+		"HideGlowOutline":   uint8(0),
+		"PersistFormatting": uint8(1),
+		"Text":              t.Text,
+		"TextOwner":         t.Owner,
+	}
 }
 
 // allSigns ...
@@ -7,6 +7,7 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
 	"time"
 )
 
@@ -136,7 +137,7 @@ func (s Slab) BreakInfo() BreakInfo {
 		effective = axeEffective
 		blastResistance = 15.0
 	}
-	return newBreakInfo(hardness, harvestable, effective, func(tool item.Tool, enchantments []item.Enchantment) []item.Stack {
+	return newBreakInfo(hardness, harvestable, effective, func(tool item.Tool, enchantments []item.Enchantment, r *rand.Rand) []item.Stack {
 		if s.Double {
 			return []item.Stack{item.NewStack(s, 2)}
 		}
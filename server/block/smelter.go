@@ -100,8 +100,8 @@ func (s *smelter) setDurations(remaining, max, cook time.Duration) {
 }
 
 // tickSmelting ticks the smelter, ensuring the necessary items exist in the furnace, and then processing all inputted
-// items for the necessary duration.
-func (s *smelter) tickSmelting(requirement, decrement time.Duration, lit bool, supported func(item.SmeltInfo) bool) bool {
+// items for the necessary duration. r is used to grant fractional experience.
+func (s *smelter) tickSmelting(requirement, decrement time.Duration, lit bool, r *rand.Rand, supported func(item.SmeltInfo) bool) bool {
 	s.mu.Lock()
 
 	// First keep track of our past durations, since if any of them change, we need to be able to tell they did and then
@@ -161,7 +161,7 @@ func (s *smelter) tickSmelting(requirement, decrement time.Duration, lit bool, s
 				// The remaining XP is a chance to be granted an additional experience point.
 				xp := inputInfo.Experience * float64(inputInfo.Product.Count())
 				earned := math.Floor(inputInfo.Experience)
-				if chance := xp - earned; chance > 0 && rand.Float64() < chance {
+				if chance := xp - earned; chance > 0 && r.Float64() < chance {
 					earned++
 				}
 
@@ -195,3 +195,20 @@ func (s *smelter) tickSmelting(requirement, decrement time.Duration, lit bool, s
 	s.mu.Unlock()
 	return lit
 }
+
+// hopperInsertSlots returns the input slot for items inserted by a hopper above the smelter and the fuel slot for
+// fuel inserted by a hopper at any of the sides.
+func (s *smelter) hopperInsertSlots(it item.Stack, face cube.Face) []int {
+	if face == cube.FaceUp {
+		return []int{0}
+	}
+	if _, ok := it.Item().(item.Fuel); ok && face != cube.FaceDown {
+		return []int{1}
+	}
+	return nil
+}
+
+// hopperExtractSlots returns the product slot of the smelter, which is the only slot hoppers extract items from.
+func (s *smelter) hopperExtractSlots() []int {
+	return []int{2}
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
-	"math/rand"
 	"time"
 )
 
@@ -35,10 +34,10 @@ func NewSmoker(face cube.Face) Smoker {
 
 // Tick is called to check if the smoker should update and start or stop smelting.
 func (s Smoker) Tick(_ int64, pos cube.Pos, w *world.World) {
-	if s.Lit && rand.Float64() <= 0.016 { // Every three or so seconds.
+	if s.Lit && w.Rand().Float64() <= 0.016 { // Every three or so seconds.
 		w.PlaySound(pos.Vec3Centre(), sound.SmokerCrackle{})
 	}
-	if lit := s.smelter.tickSmelting(time.Second*5, time.Millisecond*200, s.Lit, func(i item.SmeltInfo) bool {
+	if lit := s.smelter.tickSmelting(time.Second*5, time.Millisecond*200, s.Lit, w.Rand(), func(i item.SmeltInfo) bool {
 		return i.Food
 	}); s.Lit != lit {
 		s.Lit = lit
@@ -26,11 +26,11 @@ func (g TallGrass) FlammabilityInfo() FlammabilityInfo {
 
 // BreakInfo ...
 func (g TallGrass) BreakInfo() BreakInfo {
-	return newBreakInfo(0, alwaysHarvestable, nothingEffective, func(t item.Tool, enchantments []item.Enchantment) []item.Stack {
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, func(t item.Tool, enchantments []item.Enchantment, r *rand.Rand) []item.Stack {
 		if t.ToolType() == item.TypeShears || hasSilkTouch(enchantments) {
 			return []item.Stack{item.NewStack(g, 1)}
 		}
-		if rand.Float32() > 0.57 {
+		if r.Float32() > 0.57 {
 			return []item.Stack{item.NewStack(WheatSeeds{}, 1)}
 		}
 		return nil
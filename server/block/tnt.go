@@ -7,7 +7,6 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
-	"math/rand"
 	"time"
 )
 
@@ -35,7 +34,7 @@ func (t TNT) Ignite(pos cube.Pos, w *world.World) bool {
 
 // Explode ...
 func (t TNT) Explode(_ mgl64.Vec3, pos cube.Pos, w *world.World, _ ExplosionConfig) {
-	spawnTnt(pos, w, time.Second/2+time.Duration(rand.Intn(int(time.Second+time.Second/2))))
+	spawnTnt(pos, w, time.Second/2+time.Duration(w.Rand().Intn(int(time.Second+time.Second/2))))
 }
 
 // BreakInfo ...
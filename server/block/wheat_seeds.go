@@ -25,7 +25,7 @@ func (s WheatSeeds) BoneMeal(pos cube.Pos, w *world.World) bool {
 	if s.Growth == 7 {
 		return false
 	}
-	s.Growth = min(s.Growth+rand.Intn(4)+2, 7)
+	s.Growth = min(s.Growth+w.Rand().Intn(4)+2, 7)
 	w.SetBlock(pos, s, nil)
 	return true
 }
@@ -47,11 +47,11 @@ func (s WheatSeeds) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *wo
 
 // BreakInfo ...
 func (s WheatSeeds) BreakInfo() BreakInfo {
-	return newBreakInfo(0, alwaysHarvestable, nothingEffective, func(item.Tool, []item.Enchantment) []item.Stack {
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, func(_ item.Tool, _ []item.Enchantment, r *rand.Rand) []item.Stack {
 		if s.Growth < 7 {
 			return []item.Stack{item.NewStack(s, 1)}
 		}
-		return []item.Stack{item.NewStack(item.Wheat{}, 1), item.NewStack(s, rand.Intn(4)+1)}
+		return []item.Stack{item.NewStack(item.Wheat{}, 1), item.NewStack(s, r.Intn(4)+1)}
 	})
 }
 
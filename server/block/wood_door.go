@@ -49,21 +49,36 @@ func (d WoodDoor) Model() world.BlockModel {
 }
 
 // NeighbourUpdateTick ...
-func (d WoodDoor) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+func (d WoodDoor) NeighbourUpdateTick(pos, changedNeighbour cube.Pos, w *world.World) {
 	if d.Top {
 		if _, ok := w.Block(pos.Side(cube.FaceDown)).(WoodDoor); !ok {
 			w.SetBlock(pos, nil, nil)
 			w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: d})
+			return
 		}
-		return
-	}
-	if solid := w.Block(pos.Side(cube.FaceDown)).Model().FaceSolid(pos.Side(cube.FaceDown), cube.FaceUp, w); !solid {
+	} else if solid := w.Block(pos.Side(cube.FaceDown)).Model().FaceSolid(pos.Side(cube.FaceDown), cube.FaceUp, w); !solid {
 		w.SetBlock(pos, nil, nil)
 		w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: d})
+		return
 	} else if _, ok := w.Block(pos.Side(cube.FaceUp)).(WoodDoor); !ok {
 		w.SetBlock(pos, nil, nil)
 		w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: d})
+		return
 	}
+	updateOpenable(d, pos, changedNeighbour, w, d.powered(pos, w))
+}
+
+// powered checks if either half of the door at the position passed receives redstone power.
+func (d WoodDoor) powered(pos cube.Pos, w *world.World) bool {
+	return receivedRedstonePower(pos, w) > 0 || receivedRedstonePower(d.otherHalf(pos), w) > 0
+}
+
+// otherHalf returns the position of the other half of the door at the position passed.
+func (d WoodDoor) otherHalf(pos cube.Pos) cube.Pos {
+	if d.Top {
+		return pos.Side(cube.FaceDown)
+	}
+	return pos.Side(cube.FaceUp)
 }
 
 // UseOnBlock handles the directional placing of doors
@@ -81,51 +96,84 @@ func (d WoodDoor) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *worl
 		return false
 	}
 	d.Facing = user.Rotation().Direction()
-	left := w.Block(pos.Side(d.Facing.RotateLeft().Face()))
-	right := w.Block(pos.Side(d.Facing.RotateRight().Face()))
-	if door, ok := left.(WoodDoor); ok {
-		if door.Wood == d.Wood {
-			d.Right = true
-		}
-	}
-	// The side the door hinge is on can be affected by the blocks to the left and right of the door. In particular,
-	// opaque blocks on the right side of the door with transparent blocks on the left side result in a right sided
-	// door hinge.
-	if diffuser, ok := right.(LightDiffuser); !ok || diffuser.LightDiffusionLevel() != 0 {
-		if diffuser, ok := left.(LightDiffuser); ok && diffuser.LightDiffusionLevel() == 0 {
-			d.Right = true
-		}
-	}
+	d.Right = d.hinge(pos, w)
+	d.Open = d.powered(pos, w)
 
 	ctx.IgnoreBBox = true
+	before, count := w.Block(pos), ctx.CountSub
 	place(w, pos, d, user, ctx)
-	place(w, pos.Side(cube.FaceUp), WoodDoor{Wood: d.Wood, Facing: d.Facing, Top: true, Right: d.Right}, user, ctx)
-	ctx.SubtractFromCount(1)
+	if _, ok := w.Block(pos).(WoodDoor); !ok {
+		return placed(ctx)
+	}
+	// Both halves are placed through the place path, so that the user may prevent either of them from being placed.
+	// Only one door is subtracted from the count, and the lower half is removed again if the upper half could not be
+	// placed.
+	top := pos.Side(cube.FaceUp)
+	place(w, top, WoodDoor{Wood: d.Wood, Facing: d.Facing, Open: d.Open, Top: true, Right: d.Right}, user, ctx)
+	if _, ok := w.Block(top).(WoodDoor); !ok {
+		w.SetBlock(pos, before, nil)
+		ctx.CountSub = count
+		return false
+	}
+	if ctx.CountSub > count {
+		ctx.CountSub = count + 1
+	}
 	return placed(ctx)
 }
 
+// hinge returns true if a door placed at the position passed should have its hinge on the right side. A door placed
+// next to another door with its hinge on the left side gets its hinge on the right, so that the two doors form a
+// double door. Otherwise, the hinge is placed on the side with the most solid blocks next to the door.
+func (d WoodDoor) hinge(pos cube.Pos, w *world.World) bool {
+	leftFace, rightFace := d.Facing.RotateLeft().Face(), d.Facing.RotateRight().Face()
+	if door, ok := w.Block(pos.Side(leftFace)).(WoodDoor); ok && door.Facing == d.Facing && !door.Top && !door.Right {
+		return true
+	}
+	if door, ok := w.Block(pos.Side(rightFace)).(WoodDoor); ok && door.Facing == d.Facing && !door.Top && door.Right {
+		return false
+	}
+	solid := func(pos cube.Pos) int {
+		if w.Block(pos).Model().FaceSolid(pos, cube.FaceUp, w) {
+			return 1
+		}
+		return 0
+	}
+	left := solid(pos.Side(leftFace)) + solid(pos.Side(leftFace).Side(cube.FaceUp))
+	right := solid(pos.Side(rightFace)) + solid(pos.Side(rightFace).Side(cube.FaceUp))
+	return right > left
+}
+
 // Activate ...
 func (d WoodDoor) Activate(pos cube.Pos, _ cube.Face, w *world.World, _ item.User, _ *item.UseContext) bool {
-	d.Open = !d.Open
+	d.SetOpen(pos, w, !d.Open)
+	return true
+}
+
+// Opened ...
+func (d WoodDoor) Opened() bool {
+	return d.Open
+}
+
+// SetOpen opens or closes both halves of the door.
+func (d WoodDoor) SetOpen(pos cube.Pos, w *world.World, open bool) {
+	d.Open = open
 	w.SetBlock(pos, d, nil)
 
-	otherPos := pos.Side(cube.Face(boolByte(!d.Top)))
-	other := w.Block(otherPos)
-	if door, ok := other.(WoodDoor); ok {
-		door.Open = d.Open
+	otherPos := d.otherHalf(pos)
+	if door, ok := w.Block(otherPos).(WoodDoor); ok {
+		door.Open = open
 		w.SetBlock(otherPos, door, nil)
 	}
-	if d.Open {
+	if open {
 		w.PlaySound(pos.Vec3Centre(), sound.DoorOpen{Block: d})
-		return true
+		return
 	}
 	w.PlaySound(pos.Vec3Centre(), sound.DoorClose{Block: d})
-	return true
 }
 
 // BreakInfo ...
 func (d WoodDoor) BreakInfo() BreakInfo {
-	return newBreakInfo(3, alwaysHarvestable, axeEffective, oneOf(d))
+	return newBreakInfo(3, alwaysHarvestable, axeEffective, oneOf(WoodDoor{Wood: d.Wood}))
 }
 
 // SideClosed ...
@@ -29,7 +29,7 @@ type WoodFenceGate struct {
 
 // BreakInfo ...
 func (f WoodFenceGate) BreakInfo() BreakInfo {
-	return newBreakInfo(2, alwaysHarvestable, axeEffective, oneOf(f)).withBlastResistance(15)
+	return newBreakInfo(2, alwaysHarvestable, axeEffective, oneOf(WoodFenceGate{Wood: f.Wood})).withBlastResistance(15)
 }
 
 // FlammabilityInfo ...
@@ -53,17 +53,19 @@ func (f WoodFenceGate) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w
 	}
 	f.Facing = user.Rotation().Direction()
 	f.Lowered = f.shouldBeLowered(pos, w)
+	f.Open = receivedRedstonePower(pos, w) > 0
 
 	place(w, pos, f, user, ctx)
 	return placed(ctx)
 }
 
 // NeighbourUpdateTick ...
-func (f WoodFenceGate) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+func (f WoodFenceGate) NeighbourUpdateTick(pos, changedNeighbour cube.Pos, w *world.World) {
 	if f.shouldBeLowered(pos, w) != f.Lowered {
 		f.Lowered = !f.Lowered
 		w.SetBlock(pos, f, nil)
 	}
+	updateOpenable(f, pos, changedNeighbour, w, receivedRedstonePower(pos, w) > 0)
 }
 
 // shouldBeLowered returns if the fence gate should be lowered or not, based on the neighbouring walls.
@@ -76,17 +78,27 @@ func (f WoodFenceGate) shouldBeLowered(pos cube.Pos, w *world.World) bool {
 
 // Activate ...
 func (f WoodFenceGate) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, _ *item.UseContext) bool {
-	f.Open = !f.Open
-	if f.Open && f.Facing.Opposite() == u.Rotation().Direction() {
+	if !f.Open && f.Facing.Opposite() == u.Rotation().Direction() {
 		f.Facing = f.Facing.Opposite()
 	}
+	f.SetOpen(pos, w, !f.Open)
+	return true
+}
+
+// Opened ...
+func (f WoodFenceGate) Opened() bool {
+	return f.Open
+}
+
+// SetOpen ...
+func (f WoodFenceGate) SetOpen(pos cube.Pos, w *world.World, open bool) {
+	f.Open = open
 	w.SetBlock(pos, f, nil)
-	if f.Open {
+	if open {
 		w.PlaySound(pos.Vec3Centre(), sound.FenceGateOpen{Block: f})
-		return true
+		return
 	}
 	w.PlaySound(pos.Vec3Centre(), sound.FenceGateClose{Block: f})
-	return true
 }
 
 // SideClosed ...
@@ -50,26 +50,42 @@ func (t WoodTrapdoor) UseOnBlock(pos cube.Pos, face cube.Face, clickPos mgl64.Ve
 	}
 	t.Facing = user.Rotation().Direction().Opposite()
 	t.Top = (clickPos.Y() > 0.5 && face != cube.FaceUp) || face == cube.FaceDown
+	t.Open = receivedRedstonePower(pos, w) > 0
 
 	place(w, pos, t, user, ctx)
 	return placed(ctx)
 }
 
+// NeighbourUpdateTick opens or closes the trapdoor if the redstone power it receives changed.
+func (t WoodTrapdoor) NeighbourUpdateTick(pos, changedNeighbour cube.Pos, w *world.World) {
+	updateOpenable(t, pos, changedNeighbour, w, receivedRedstonePower(pos, w) > 0)
+}
+
 // Activate ...
 func (t WoodTrapdoor) Activate(pos cube.Pos, _ cube.Face, w *world.World, _ item.User, _ *item.UseContext) bool {
-	t.Open = !t.Open
+	t.SetOpen(pos, w, !t.Open)
+	return true
+}
+
+// Opened ...
+func (t WoodTrapdoor) Opened() bool {
+	return t.Open
+}
+
+// SetOpen ...
+func (t WoodTrapdoor) SetOpen(pos cube.Pos, w *world.World, open bool) {
+	t.Open = open
 	w.SetBlock(pos, t, nil)
-	if t.Open {
+	if open {
 		w.PlaySound(pos.Vec3Centre(), sound.TrapdoorOpen{Block: t})
-		return true
+		return
 	}
 	w.PlaySound(pos.Vec3Centre(), sound.TrapdoorClose{Block: t})
-	return true
 }
 
 // BreakInfo ...
 func (t WoodTrapdoor) BreakInfo() BreakInfo {
-	return newBreakInfo(3, alwaysHarvestable, axeEffective, oneOf(t))
+	return newBreakInfo(3, alwaysHarvestable, axeEffective, oneOf(WoodTrapdoor{Wood: t.Wood}))
 }
 
 // FuelInfo ...
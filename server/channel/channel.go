@@ -0,0 +1,329 @@
+// Package channel implements chat channels that players may join and leave, such as the global, local, staff and
+// party channels, as well as private messages between players. If the server is part of a cluster, messages written
+// in the global and staff channels and private messages are relayed to the other instances of the cluster using a
+// cluster.PubSub, so that players may chat across the network.
+package channel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/cluster"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/player/chat"
+	"github.com/df-mc/dragonfly/server/text"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"strings"
+	"sync"
+)
+
+// Logger is used to report errors that occur while relaying messages.
+type Logger interface {
+	Errorf(format string, a ...any)
+}
+
+// Config holds the settings of a Manager. Calling Config.New() creates a Manager.
+type Config struct {
+	// Server is the Server that players chat on. Server must not be nil.
+	Server *server.Server
+	// StaffPermission is the permission node that players must have to join the staff channel. If left empty,
+	// StaffPermission is set to 'chat.staff'.
+	StaffPermission string
+	// PubSub is the cluster.PubSub used to relay messages to the other instances of a cluster. If nil, messages are
+	// not relayed.
+	PubSub cluster.PubSub
+	// NodeID is the ID of the instance within the cluster. It is used to ignore messages relayed by the instance
+	// itself and must not be empty if PubSub is set.
+	NodeID string
+	// Log is the Logger used to report errors. If nil, errors are not reported.
+	Log Logger
+}
+
+// Manager manages the chat channels of a server. By default, it holds the global channel (chat.Global), the world
+// channel (player.WorldChat), the local channel (player.LocalChat) and a staff channel. Additional channels may be
+// added using Register. Manager.HandleJoin must be called for every player that joins the server.
+type Manager struct {
+	conf  Config
+	staff *chat.Chat
+
+	mu       sync.Mutex
+	channels map[string]*channel
+	parties  map[*player.Player]*Party
+	replies  map[string]string
+
+	unsubscribe []func()
+}
+
+// channel is a chat channel registered with a Manager.
+type channel struct {
+	c          *chat.Chat
+	permission string
+	relay      *relay
+}
+
+// New creates a Manager using the settings in the Config. An error is returned if subscribing to the PubSub failed.
+func (conf Config) New() (*Manager, error) {
+	if conf.Server == nil {
+		return nil, errors.New("new channel manager: server must not be nil")
+	}
+	if conf.PubSub != nil && conf.NodeID == "" {
+		return nil, errors.New("new channel manager: node id must not be empty if pubsub is set")
+	}
+	if conf.StaffPermission == "" {
+		conf.StaffPermission = "chat.staff"
+	}
+	m := &Manager{
+		conf:     conf,
+		staff:    chat.NewChannel("staff", nil),
+		channels: map[string]*channel{},
+		parties:  map[*player.Player]*Party{},
+		replies:  map[string]string{},
+	}
+	m.staff.SetFormatter(chat.FormatterFunc(func(sender, msg string) string {
+		return text.Colourf("<aqua>[Staff]</aqua> ") + chat.DefaultFormatter{}.Format(sender, msg)
+	}))
+	for _, c := range []struct {
+		c          *chat.Chat
+		permission string
+		relay      bool
+	}{{chat.Global, "", true}, {player.WorldChat, "", false}, {player.LocalChat, "", false}, {m.staff, conf.StaffPermission, true}} {
+		if err := m.Register(c.c, c.permission, c.relay); err != nil {
+			_ = m.Close()
+			return nil, fmt.Errorf("new channel manager: %w", err)
+		}
+	}
+	if conf.PubSub != nil {
+		unsubscribe, err := conf.PubSub.Subscribe(privateTopic, m.receivePrivate)
+		if err != nil {
+			_ = m.Close()
+			return nil, fmt.Errorf("new channel manager: %w", err)
+		}
+		m.unsubscribe = append(m.unsubscribe, unsubscribe)
+	}
+	return m, nil
+}
+
+// Register registers a chat channel with the Manager, so that players may join it by its name. If permission is not
+// empty, only players with that permission node may join it. If relay is true and the Manager has a cluster.PubSub,
+// messages written in the channel are relayed to the channels with the same name on other instances of the cluster.
+// An error is returned if a channel with the same name was already registered.
+func (m *Manager) Register(c *chat.Chat, permission string, relay bool) error {
+	name := strings.ToLower(c.Name())
+	if name == "" || name == partyChannel {
+		return fmt.Errorf("register channel: invalid name '%v'", c.Name())
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.channels[name]; ok {
+		return fmt.Errorf("register channel: channel %v already registered", name)
+	}
+	ch := &channel{c: c, permission: permission}
+	if relay && m.conf.PubSub != nil {
+		r, err := m.newRelay(c)
+		if err != nil {
+			return fmt.Errorf("register channel: %w", err)
+		}
+		ch.relay = r
+	}
+	m.channels[name] = ch
+	return nil
+}
+
+// Channel returns the channel registered with the name passed. If a player is passed and the name is 'party', the
+// chat of the party of the player is returned. False is returned if no such channel exists.
+func (m *Manager) Channel(name string, p *player.Player) (*chat.Chat, bool) {
+	name = strings.ToLower(name)
+	if name == partyChannel {
+		if party, ok := m.Party(p); ok {
+			return party.Chat(), true
+		}
+		return nil, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch, ok := m.channels[name]
+	if !ok {
+		return nil, false
+	}
+	return ch.c, true
+}
+
+// Channels returns the names of all channels registered with the Manager, sorted alphabetically.
+func (m *Manager) Channels() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := maps.Keys(m.channels)
+	slices.Sort(names)
+	return names
+}
+
+// Staff returns the staff channel, which only players with the StaffPermission of the Manager may join.
+func (m *Manager) Staff() *chat.Chat {
+	return m.staff
+}
+
+// Join makes the player passed join the channel with the name passed, so that it receives the messages written in
+// it, and makes it the channel that the player writes messages in. Joining 'party' joins the chat of the party of
+// the player. An error is returned if the channel does not exist or the player may not join it.
+func (m *Manager) Join(p *player.Player, name string) error {
+	c, ok := m.Channel(name, p)
+	if !ok {
+		return fmt.Errorf("channel %v does not exist", name)
+	}
+	m.mu.Lock()
+	ch, registered := m.channels[strings.ToLower(name)]
+	m.mu.Unlock()
+	if registered && ch.permission != "" && !cmd.Permitted(p, ch.permission) {
+		return fmt.Errorf("you may not join channel %v", name)
+	}
+	if _, local := player.LocalRadius(c); !local && c != player.WorldChat {
+		// Messages in the world and local channels are sent to players depending on where the sender is, so players
+		// must not be subscribed to them.
+		c.Subscribe(p)
+	}
+	p.SetChatChannel(c)
+	return nil
+}
+
+// Leave makes the player passed leave the channel with the name passed, so that it no longer receives the messages
+// written in it. If the player was writing messages in the channel, it writes in the global channel again. The
+// global channel cannot be left.
+func (m *Manager) Leave(p *player.Player, name string) error {
+	c, ok := m.Channel(name, p)
+	if !ok {
+		return fmt.Errorf("channel %v does not exist", name)
+	}
+	if c == chat.Global {
+		return errors.New("the global channel cannot be left")
+	}
+	if strings.ToLower(name) != partyChannel {
+		c.Unsubscribe(p)
+	}
+	if p.ChatChannel() == c {
+		p.SetChatChannel(nil)
+	}
+	return nil
+}
+
+// HandleJoin must be called for every player that joins the server, so that its parties, replies and channels are
+// cleaned up when it quits.
+func (m *Manager) HandleJoin(p *player.Player) {
+	p.Subscribe(quitHandler{m: m, p: p}, event.PriorityNormal)
+}
+
+// Close stops relaying messages to and from other instances of the cluster.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, ch := range m.channels {
+		if ch.relay != nil {
+			ch.relay.close()
+		}
+	}
+	for _, f := range m.unsubscribe {
+		f()
+	}
+	m.unsubscribe = nil
+	return nil
+}
+
+// quit removes the player passed from all channels and its party.
+func (m *Manager) quit(p *player.Player) {
+	if party, ok := m.Party(p); ok {
+		party.Leave(p)
+	}
+	m.mu.Lock()
+	channels := maps.Values(m.channels)
+	delete(m.replies, strings.ToLower(p.Name()))
+	m.mu.Unlock()
+	for _, ch := range channels {
+		ch.c.Unsubscribe(p)
+	}
+}
+
+// quitHandler is a player.Handler that cleans up after a player that quits.
+type quitHandler struct {
+	player.NopHandler
+	m *Manager
+	p *player.Player
+}
+
+// HandleQuit ...
+func (h quitHandler) HandleQuit() {
+	h.m.quit(h.p)
+}
+
+// relayTopic returns the cluster.PubSub topic that messages written in the channel with the name passed are relayed
+// on.
+func relayTopic(name string) string {
+	return "chat.channel." + strings.ToLower(name)
+}
+
+// relayed is a message written in a channel that is relayed to other instances of a cluster.
+type relayed struct {
+	Node string `json:"node"`
+	Line string `json:"line"`
+}
+
+// relay is a chat.Subscriber that relays every message written in a channel to the other instances of a cluster,
+// and writes messages relayed by other instances to the recipients of the channel.
+type relay struct {
+	m           *Manager
+	c           *chat.Chat
+	unsubscribe func()
+}
+
+// newRelay subscribes a relay to the channel passed.
+func (m *Manager) newRelay(c *chat.Chat) (*relay, error) {
+	r := &relay{m: m, c: c}
+	unsubscribe, err := m.conf.PubSub.Subscribe(relayTopic(c.Name()), r.receive)
+	if err != nil {
+		return nil, err
+	}
+	r.unsubscribe = unsubscribe
+	c.Subscribe(r)
+	return r, nil
+}
+
+// Message publishes a message written in the channel of the relay to the other instances of the cluster.
+func (r *relay) Message(a ...any) {
+	data, _ := json.Marshal(relayed{Node: r.m.conf.NodeID, Line: fmt.Sprint(a...)})
+	if err := r.m.conf.PubSub.Publish(relayTopic(r.c.Name()), data); err != nil {
+		r.m.errorf("relay channel %v: %v", r.c.Name(), err)
+	}
+}
+
+// receive writes a message relayed by another instance of the cluster to the recipients of the channel.
+func (r *relay) receive(payload []byte) {
+	var msg relayed
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		r.m.errorf("relay channel %v: decode message: %v", r.c.Name(), err)
+		return
+	}
+	if msg.Node == r.m.conf.NodeID {
+		return
+	}
+	for _, recipient := range r.c.Recipients() {
+		if _, ok := recipient.(*relay); !ok {
+			// Relays never receive relayed messages, so that messages are not relayed back and forth.
+			recipient.Message(msg.Line)
+		}
+	}
+}
+
+// close stops relaying messages of the channel.
+func (r *relay) close() {
+	r.c.Unsubscribe(r)
+	r.unsubscribe()
+}
+
+// errorf reports an error using the Logger of the Manager, if set.
+func (m *Manager) errorf(format string, a ...any) {
+	if m.conf.Log != nil {
+		m.conf.Log.Errorf(format, a...)
+	}
+}
@@ -0,0 +1,301 @@
+package channel
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"strings"
+)
+
+// Commands returns the /channel, /msg, /r and /party commands, which players may use to join and leave the channels
+// of the Manager passed, send private messages and manage their party. The commands may be registered using
+// cmd.Register. allow is called to check if a cmd.Source may execute the commands. If nil, the commands may be
+// executed by all players. If cmd.Permissions were set using cmd.SetPermissions, the permission node of each command
+// (such as 'command.msg') additionally decides which players may execute it.
+func Commands(m *Manager, allow func(src cmd.Source) bool) []cmd.Command {
+	if allow == nil {
+		allow = func(src cmd.Source) bool {
+			_, ok := src.(*player.Player)
+			return ok
+		}
+	}
+	c := command{m: m, allow: allow}
+	return []cmd.Command{
+		cmd.New("channel", "Joins and leaves chat channels.", []string{"ch"},
+			channelListCommand{command: c},
+			channelJoinCommand{command: c},
+			channelLeaveCommand{command: c},
+		),
+		cmd.New("msg", "Sends a private message to a player.", []string{"tell", "w", "whisper"}, msgCommand{command: c}),
+		cmd.New("r", "Replies to the last private message.", []string{"reply"}, replyCommand{command: c}),
+		cmd.New("party", "Manages your party.", nil,
+			partyCreateCommand{command: c},
+			partyInviteCommand{command: c},
+			partyAcceptCommand{command: c},
+			partyLeaveCommand{command: c},
+			partyDisbandCommand{command: c},
+			partyListCommand{command: c},
+		),
+	}
+}
+
+// command holds the fields shared by all commands returned by Commands.
+type command struct {
+	m     *Manager
+	allow func(src cmd.Source) bool
+}
+
+// Allow ...
+func (c command) Allow(src cmd.Source) bool {
+	return c.allow(src)
+}
+
+// sourcePlayer returns the source passed as a player. If it is not a player, an error is added to the output.
+func sourcePlayer(src cmd.Source, o *cmd.Output) (*player.Player, bool) {
+	p, ok := src.(*player.Player)
+	if !ok {
+		o.Error("This command can only be executed by a player.")
+	}
+	return p, ok
+}
+
+// channelListCommand implements the /channel list command.
+type channelListCommand struct {
+	command
+	List cmd.SubCommand `cmd:"list"`
+}
+
+// Run ...
+func (c channelListCommand) Run(src cmd.Source, o *cmd.Output) {
+	p, ok := sourcePlayer(src, o)
+	if !ok {
+		return
+	}
+	names := c.m.Channels()
+	if _, ok := c.m.Party(p); ok {
+		names = append(names, partyChannel)
+	}
+	o.Printf("Channels: %v", strings.Join(names, ", "))
+	o.Printf("You are chatting in %v.", p.ChatChannel().Name())
+}
+
+// channelJoinCommand implements the /channel join <name> command.
+type channelJoinCommand struct {
+	command
+	Join cmd.SubCommand `cmd:"join"`
+	Name string         `cmd:"name"`
+}
+
+// Run ...
+func (c channelJoinCommand) Run(src cmd.Source, o *cmd.Output) {
+	p, ok := sourcePlayer(src, o)
+	if !ok {
+		return
+	}
+	if err := c.m.Join(p, c.Name); err != nil {
+		o.Errorf("Could not join channel: %v.", err)
+		return
+	}
+	o.Printf("You are now chatting in %v.", strings.ToLower(c.Name))
+}
+
+// channelLeaveCommand implements the /channel leave <name> command.
+type channelLeaveCommand struct {
+	command
+	Leave cmd.SubCommand `cmd:"leave"`
+	Name  string         `cmd:"name"`
+}
+
+// Run ...
+func (c channelLeaveCommand) Run(src cmd.Source, o *cmd.Output) {
+	p, ok := sourcePlayer(src, o)
+	if !ok {
+		return
+	}
+	if err := c.m.Leave(p, c.Name); err != nil {
+		o.Errorf("Could not leave channel: %v.", err)
+		return
+	}
+	o.Printf("You left %v.", strings.ToLower(c.Name))
+}
+
+// msgCommand implements the /msg <player> <message> command.
+type msgCommand struct {
+	command
+	Target  string      `cmd:"player"`
+	Message cmd.Varargs `cmd:"message"`
+}
+
+// Run ...
+func (c msgCommand) Run(src cmd.Source, o *cmd.Output) {
+	p, ok := sourcePlayer(src, o)
+	if !ok {
+		return
+	}
+	if err := c.m.Message(p, c.Target, string(c.Message)); err != nil {
+		o.Errorf("Could not send message: %v.", err)
+	}
+}
+
+// replyCommand implements the /r <message> command.
+type replyCommand struct {
+	command
+	Message cmd.Varargs `cmd:"message"`
+}
+
+// Run ...
+func (c replyCommand) Run(src cmd.Source, o *cmd.Output) {
+	p, ok := sourcePlayer(src, o)
+	if !ok {
+		return
+	}
+	if err := c.m.Reply(p, string(c.Message)); err != nil {
+		o.Errorf("Could not send message: %v.", err)
+	}
+}
+
+// partyCreateCommand implements the /party create command.
+type partyCreateCommand struct {
+	command
+	Create cmd.SubCommand `cmd:"create"`
+}
+
+// Run ...
+func (c partyCreateCommand) Run(src cmd.Source, o *cmd.Output) {
+	p, ok := sourcePlayer(src, o)
+	if !ok {
+		return
+	}
+	if _, err := c.m.CreateParty(p); err != nil {
+		o.Errorf("Could not create party: %v.", err)
+		return
+	}
+	o.Printf("Created a party. Invite players using /party invite.")
+}
+
+// partyInviteCommand implements the /party invite <player> command.
+type partyInviteCommand struct {
+	command
+	Invite  cmd.SubCommand `cmd:"invite"`
+	Targets []cmd.Target   `cmd:"player"`
+}
+
+// Run ...
+func (c partyInviteCommand) Run(src cmd.Source, o *cmd.Output) {
+	p, ok := sourcePlayer(src, o)
+	if !ok {
+		return
+	}
+	party, ok := c.m.Party(p)
+	if !ok || party.Owner() != p {
+		o.Error("You must own a party to invite players.")
+		return
+	}
+	for _, t := range c.Targets {
+		target, ok := t.(*player.Player)
+		if !ok {
+			continue
+		}
+		if err := party.Invite(target); err != nil {
+			o.Errorf("Could not invite %v: %v.", target.Name(), err)
+			continue
+		}
+		target.Messagef("%v invited you to their party. Join it using /party accept %v.", p.Name(), p.Name())
+		o.Printf("Invited %v to the party.", target.Name())
+	}
+}
+
+// partyAcceptCommand implements the /party accept <player> command.
+type partyAcceptCommand struct {
+	command
+	Accept cmd.SubCommand `cmd:"accept"`
+	Owner  []cmd.Target   `cmd:"owner"`
+}
+
+// Run ...
+func (c partyAcceptCommand) Run(src cmd.Source, o *cmd.Output) {
+	p, ok := sourcePlayer(src, o)
+	if !ok {
+		return
+	}
+	for _, t := range c.Owner {
+		owner, ok := t.(*player.Player)
+		if !ok {
+			continue
+		}
+		party, ok := c.m.Party(owner)
+		if !ok || !party.Invited(p) {
+			continue
+		}
+		if err := party.Join(p); err != nil {
+			o.Errorf("Could not join the party: %v.", err)
+		}
+		return
+	}
+	o.Error("You were not invited to a party of that player.")
+}
+
+// partyLeaveCommand implements the /party leave command.
+type partyLeaveCommand struct {
+	command
+	Leave cmd.SubCommand `cmd:"leave"`
+}
+
+// Run ...
+func (c partyLeaveCommand) Run(src cmd.Source, o *cmd.Output) {
+	p, ok := sourcePlayer(src, o)
+	if !ok {
+		return
+	}
+	party, ok := c.m.Party(p)
+	if !ok {
+		o.Error("You are not in a party.")
+		return
+	}
+	party.Leave(p)
+	o.Printf("You left the party.")
+}
+
+// partyDisbandCommand implements the /party disband command.
+type partyDisbandCommand struct {
+	command
+	Disband cmd.SubCommand `cmd:"disband"`
+}
+
+// Run ...
+func (c partyDisbandCommand) Run(src cmd.Source, o *cmd.Output) {
+	p, ok := sourcePlayer(src, o)
+	if !ok {
+		return
+	}
+	party, ok := c.m.Party(p)
+	if !ok || party.Owner() != p {
+		o.Error("You must own a party to disband it.")
+		return
+	}
+	party.Disband()
+}
+
+// partyListCommand implements the /party list command.
+type partyListCommand struct {
+	command
+	List cmd.SubCommand `cmd:"list"`
+}
+
+// Run ...
+func (c partyListCommand) Run(src cmd.Source, o *cmd.Output) {
+	p, ok := sourcePlayer(src, o)
+	if !ok {
+		return
+	}
+	party, ok := c.m.Party(p)
+	if !ok {
+		o.Error("You are not in a party.")
+		return
+	}
+	members := party.Members()
+	names := make([]string, 0, len(members))
+	for _, member := range members {
+		names = append(names, member.Name())
+	}
+	o.Printf("Party of %v: %v", party.Owner().Name(), strings.Join(names, ", "))
+}
@@ -0,0 +1,96 @@
+package channel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/player/chat"
+	"github.com/df-mc/dragonfly/server/text"
+	"strings"
+)
+
+// privateTopic is the cluster.PubSub topic that private messages to players on other instances are published on.
+const privateTopic = "chat.private"
+
+// private is a private message relayed to another instance of a cluster.
+type private struct {
+	Node    string `json:"node"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+// Message sends a private message from the player passed to the player with the name passed. If no player with
+// that name is online on the server and the Manager has a cluster.PubSub, the message is relayed to the other
+// instances of the cluster. Messages are passed through the filters of chat.Global, and players muted by the
+// chat.Muter set using chat.SetMuter cannot send private messages. An error is returned if the message could not be
+// sent.
+func (m *Manager) Message(from *player.Player, to, msg string) error {
+	if _, muted := chat.Muted(from.UUID()); muted {
+		return errors.New("you are muted")
+	}
+	if err := chat.Global.Filter(from.Name(), &msg); err != nil {
+		return err
+	}
+	if target, ok := m.conf.Server.PlayerByName(to); ok {
+		if target == from {
+			return errors.New("you cannot message yourself")
+		}
+		to = target.Name()
+		target.Message(privateLine(from.Name(), "me", msg))
+	} else if m.conf.PubSub != nil {
+		data, _ := json.Marshal(private{Node: m.conf.NodeID, From: from.Name(), To: to, Message: msg})
+		if err := m.conf.PubSub.Publish(privateTopic, data); err != nil {
+			return fmt.Errorf("relay message: %w", err)
+		}
+	} else {
+		return fmt.Errorf("%v is not online", to)
+	}
+	from.Message(privateLine("me", to, msg))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replies[strings.ToLower(from.Name())] = to
+	m.replies[strings.ToLower(to)] = from.Name()
+	return nil
+}
+
+// Reply sends a private message from the player passed to the player that it last sent a private message to or
+// received one from. An error is returned if the player has nobody to reply to.
+func (m *Manager) Reply(from *player.Player, msg string) error {
+	m.mu.Lock()
+	to, ok := m.replies[strings.ToLower(from.Name())]
+	m.mu.Unlock()
+	if !ok {
+		return errors.New("you have nobody to reply to")
+	}
+	return m.Message(from, to, msg)
+}
+
+// receivePrivate delivers a private message relayed by another instance of the cluster if its recipient is online
+// on the server.
+func (m *Manager) receivePrivate(payload []byte) {
+	var msg private
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		m.errorf("relay private message: decode message: %v", err)
+		return
+	}
+	if msg.Node == m.conf.NodeID {
+		return
+	}
+	target, ok := m.conf.Server.PlayerByName(msg.To)
+	if !ok {
+		return
+	}
+	target.Message(privateLine(msg.From, "me", msg.Message))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replies[strings.ToLower(target.Name())] = msg.From
+}
+
+// privateLine formats a private message sent from one name to another.
+func privateLine(from, to, msg string) string {
+	return text.Colourf("<grey>[%v -> %v]</grey> %v", from, to, msg)
+}
@@ -0,0 +1,166 @@
+package channel
+
+import (
+	"errors"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/player/chat"
+	"github.com/df-mc/dragonfly/server/text"
+	"golang.org/x/exp/maps"
+	"sync"
+)
+
+// partyChannel is the name by which players may join the chat of their party.
+const partyChannel = "party"
+
+// Party is a group of players on the same server with its own chat channel. Parties are created using
+// Manager.CreateParty and are disbanded once their owner leaves.
+type Party struct {
+	m *Manager
+	c *chat.Chat
+
+	mu        sync.Mutex
+	owner     *player.Player
+	members   map[*player.Player]struct{}
+	invited   map[*player.Player]struct{}
+	disbanded bool
+}
+
+// CreateParty creates a Party owned by the player passed. An error is returned if the player is already in a Party.
+func (m *Manager) CreateParty(owner *player.Player) (*Party, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.parties[owner]; ok {
+		return nil, errors.New("you are already in a party")
+	}
+	party := &Party{
+		m:       m,
+		c:       chat.NewChannel(partyChannel, nil),
+		owner:   owner,
+		members: map[*player.Player]struct{}{owner: {}},
+		invited: map[*player.Player]struct{}{},
+	}
+	party.c.SetFormatter(chat.FormatterFunc(func(sender, msg string) string {
+		return text.Colourf("<light-purple>[Party]</light-purple> ") + chat.DefaultFormatter{}.Format(sender, msg)
+	}))
+	party.c.Subscribe(owner)
+	m.parties[owner] = party
+	return party, nil
+}
+
+// Party returns the Party that the player passed is a member of. False is returned if the player is not in a Party.
+func (m *Manager) Party(p *player.Player) (*Party, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	party, ok := m.parties[p]
+	return party, ok
+}
+
+// Chat returns the chat channel of the Party, which all its members are subscribed to.
+func (party *Party) Chat() *chat.Chat {
+	return party.c
+}
+
+// Owner returns the player that owns the Party.
+func (party *Party) Owner() *player.Player {
+	party.mu.Lock()
+	defer party.mu.Unlock()
+	return party.owner
+}
+
+// Members returns all members of the Party, including its owner.
+func (party *Party) Members() []*player.Player {
+	party.mu.Lock()
+	defer party.mu.Unlock()
+	return maps.Keys(party.members)
+}
+
+// Invite invites the player passed to the Party, so that it may join it using Join.
+func (party *Party) Invite(p *player.Player) error {
+	party.mu.Lock()
+	defer party.mu.Unlock()
+	if party.disbanded {
+		return errors.New("the party was disbanded")
+	}
+	if _, ok := party.members[p]; ok {
+		return errors.New(p.Name() + " is already in the party")
+	}
+	party.invited[p] = struct{}{}
+	return nil
+}
+
+// Invited checks if the player passed was invited to the Party and has not yet joined it.
+func (party *Party) Invited(p *player.Player) bool {
+	party.mu.Lock()
+	defer party.mu.Unlock()
+	_, ok := party.invited[p]
+	return ok
+}
+
+// Join makes the player passed join the Party. An error is returned if the player was not invited or is already in
+// a Party.
+func (party *Party) Join(p *player.Player) error {
+	party.m.mu.Lock()
+	defer party.m.mu.Unlock()
+	if _, ok := party.m.parties[p]; ok {
+		return errors.New("you are already in a party")
+	}
+	party.mu.Lock()
+	defer party.mu.Unlock()
+	if party.disbanded {
+		return errors.New("the party was disbanded")
+	}
+	if _, ok := party.invited[p]; !ok {
+		return errors.New("you were not invited to the party")
+	}
+	delete(party.invited, p)
+	party.members[p] = struct{}{}
+	party.m.parties[p] = party
+	party.c.Subscribe(p)
+	_, _ = party.c.WriteString(text.Colourf("<light-purple>[Party]</light-purple> <yellow>%v joined the party.</yellow>", p.Name()))
+	return nil
+}
+
+// Leave removes the player passed from the Party. If the player owns the Party, the Party is disbanded.
+func (party *Party) Leave(p *player.Player) {
+	if party.Owner() == p {
+		party.Disband()
+		return
+	}
+	party.m.mu.Lock()
+	defer party.m.mu.Unlock()
+	party.mu.Lock()
+	defer party.mu.Unlock()
+	if _, ok := party.members[p]; !ok {
+		return
+	}
+	party.remove(p)
+	_, _ = party.c.WriteString(text.Colourf("<light-purple>[Party]</light-purple> <yellow>%v left the party.</yellow>", p.Name()))
+}
+
+// Disband disbands the Party, removing all its members.
+func (party *Party) Disband() {
+	party.m.mu.Lock()
+	defer party.m.mu.Unlock()
+	party.mu.Lock()
+	defer party.mu.Unlock()
+	if party.disbanded {
+		return
+	}
+	_, _ = party.c.WriteString(text.Colourf("<light-purple>[Party]</light-purple> <yellow>The party was disbanded.</yellow>"))
+	for p := range party.members {
+		party.remove(p)
+	}
+	party.invited, party.disbanded = nil, true
+	_ = party.c.Close()
+}
+
+// remove removes the player passed from the Party and its chat. The mutexes of both the Manager and the Party must
+// be held when calling remove.
+func (party *Party) remove(p *player.Player) {
+	delete(party.members, p)
+	delete(party.m.parties, p)
+	party.c.Unsubscribe(p)
+	if p.ChatChannel() == party.c {
+		p.SetChatChannel(nil)
+	}
+}
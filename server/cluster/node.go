@@ -0,0 +1,194 @@
+// Package cluster implements a lightweight membership module for running multiple server instances as one network.
+// Instances register themselves with a shared Store and keep track of the players online on them, so that it may be
+// queried where a player is online network-wide and players may be transferred between instances.
+//
+// The package is agnostic of the database used to share data between instances: Store and PubSub are interfaces
+// that must be implemented for the database of choice, such as Redis, to run a cluster over multiple machines. The
+// MemoryStore and MemoryPubSub implementations only share data between instances running in the same process.
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/google/uuid"
+	"sync"
+	"time"
+)
+
+// Logger is used to report errors that occur in the background while a Node is running.
+type Logger interface {
+	Errorf(format string, a ...any)
+}
+
+// Config holds the settings of a Node. Calling Config.New() creates a Node and registers it with the Store.
+type Config struct {
+	// ID is the unique ID of the instance within the cluster. ID must not be empty.
+	ID string
+	// Address is the address that players may be transferred to in order to join the instance.
+	Address string
+	// Store is the Store shared by all instances in the cluster. Store must not be nil.
+	Store Store
+	// Log is the Logger used to report errors during heartbeats. If nil, errors are not reported.
+	Log Logger
+	// HeartbeatInterval is the interval at which the Node re-registers itself with the Store. Members that have not
+	// sent a heartbeat within three times this interval are considered offline. If left as 0, HeartbeatInterval is
+	// set to 5 seconds.
+	HeartbeatInterval time.Duration
+}
+
+// Node is a server instance that is a member of a cluster.
+type Node struct {
+	conf Config
+
+	once  sync.Once
+	close chan struct{}
+}
+
+// New creates a Node using the settings in the Config, registering it with the Store and starting its heartbeats.
+// An error is returned if the Node could not be registered.
+func (conf Config) New() (*Node, error) {
+	if conf.ID == "" {
+		return nil, errors.New("new node: id must not be empty")
+	}
+	if conf.Store == nil {
+		return nil, errors.New("new node: store must not be nil")
+	}
+	if conf.HeartbeatInterval <= 0 {
+		conf.HeartbeatInterval = time.Second * 5
+	}
+	n := &Node{conf: conf, close: make(chan struct{})}
+	if err := n.register(); err != nil {
+		return nil, fmt.Errorf("new node: %w", err)
+	}
+	go n.heartbeat()
+	return n, nil
+}
+
+// ID returns the ID of the Node within the cluster.
+func (n *Node) ID() string {
+	return n.conf.ID
+}
+
+// HandleJoin registers the player passed as online on the Node and registers it as offline again once it quits.
+// HandleJoin may be passed to server.Server.Accept, or be called from the function passed to it. Errors are
+// reported using the Logger of the Node.
+func (n *Node) HandleJoin(p *player.Player) {
+	if err := n.Join(p); err != nil && n.conf.Log != nil {
+		n.conf.Log.Errorf("cluster join %v: %v", p.Name(), err)
+	}
+	p.Subscribe(quitHandler{n: n, p: p}, event.PriorityMonitor)
+}
+
+// Join registers the player passed as online on the Node. Join is called by HandleJoin and only needs to be called
+// directly if HandleJoin is not used.
+func (n *Node) Join(p *player.Player) error {
+	return n.conf.Store.SetPlayer(p.UUID(), n.conf.ID)
+}
+
+// Quit registers the player passed as no longer being online on the Node. Quit is called when a player passed to
+// HandleJoin quits and only needs to be called directly if HandleJoin is not used.
+func (n *Node) Quit(p *player.Player) error {
+	return n.conf.Store.RemovePlayer(p.UUID(), n.conf.ID)
+}
+
+// Members returns all Members of the cluster that are currently online, including the Node itself.
+func (n *Node) Members() ([]Member, error) {
+	members, err := n.conf.Store.Members()
+	if err != nil {
+		return nil, err
+	}
+	online := members[:0]
+	for _, m := range members {
+		if n.alive(m) {
+			online = append(online, m)
+		}
+	}
+	return online, nil
+}
+
+// Member returns the online Member of the cluster with the ID passed. If no such Member exists, false is returned.
+func (n *Node) Member(id string) (Member, bool, error) {
+	members, err := n.Members()
+	if err != nil {
+		return Member{}, false, err
+	}
+	for _, m := range members {
+		if m.ID == id {
+			return m, true, nil
+		}
+	}
+	return Member{}, false, nil
+}
+
+// Locate returns the Member of the cluster that the player with the UUID passed is online on. If the player is not
+// online anywhere in the cluster, false is returned.
+func (n *Node) Locate(id uuid.UUID) (Member, bool, error) {
+	memberID, ok, err := n.conf.Store.Player(id)
+	if err != nil || !ok {
+		return Member{}, false, err
+	}
+	return n.Member(memberID)
+}
+
+// Transfer transfers the player passed to the Member of the cluster with the ID passed. An error is returned if the
+// Member is not online.
+func (n *Node) Transfer(p *player.Player, id string) error {
+	m, ok, err := n.Member(id)
+	if err != nil {
+		return fmt.Errorf("transfer: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("transfer: member %v is not online", id)
+	}
+	return p.Transfer(m.Address)
+}
+
+// Close unregisters the Node from the Store and stops its heartbeats.
+func (n *Node) Close() error {
+	n.once.Do(func() {
+		close(n.close)
+	})
+	return n.conf.Store.Unregister(n.conf.ID)
+}
+
+// heartbeat re-registers the Node with the Store every HeartbeatInterval until the Node is closed.
+func (n *Node) heartbeat() {
+	t := time.NewTicker(n.conf.HeartbeatInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := n.register(); err != nil && n.conf.Log != nil {
+				n.conf.Log.Errorf("cluster heartbeat: %v", err)
+			}
+		case <-n.close:
+			return
+		}
+	}
+}
+
+// register registers the Node with the Store.
+func (n *Node) register() error {
+	return n.conf.Store.Register(Member{ID: n.conf.ID, Address: n.conf.Address, LastSeen: time.Now()})
+}
+
+// alive checks if a Member has sent a heartbeat recently enough to be considered online.
+func (n *Node) alive(m Member) bool {
+	return time.Since(m.LastSeen) < n.conf.HeartbeatInterval*3
+}
+
+// quitHandler is a player.Handler that registers a player as offline once it quits.
+type quitHandler struct {
+	player.NopHandler
+	n *Node
+	p *player.Player
+}
+
+// HandleQuit ...
+func (h quitHandler) HandleQuit() {
+	if err := h.n.Quit(h.p); err != nil && h.n.conf.Log != nil {
+		h.n.conf.Log.Errorf("cluster quit %v: %v", h.p.Name(), err)
+	}
+}
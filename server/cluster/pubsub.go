@@ -0,0 +1,59 @@
+package cluster
+
+import (
+	"sync"
+)
+
+// PubSub is a publish/subscribe message bus shared by all instances in a cluster. Payloads published to a topic
+// are delivered to all subscribers of that topic on every instance, including the instance that published them.
+// PubSub implementations must be safe for concurrent use.
+type PubSub interface {
+	// Publish publishes the payload passed to all subscribers of the topic passed.
+	Publish(topic string, payload []byte) error
+	// Subscribe calls f for every payload published to the topic passed until the function returned is called.
+	// f may be called from any goroutine and must not block for long.
+	Subscribe(topic string, f func(payload []byte)) (unsubscribe func(), err error)
+}
+
+// MemoryPubSub is a PubSub implementation that delivers payloads in memory. It may be used to connect multiple
+// instances running in the same process, or as a reference for implementing a networked PubSub.
+type MemoryPubSub struct {
+	mu   sync.Mutex
+	subs map[string]map[*func(payload []byte)]struct{}
+}
+
+// NewMemoryPubSub creates a MemoryPubSub without subscribers.
+func NewMemoryPubSub() *MemoryPubSub {
+	return &MemoryPubSub{subs: map[string]map[*func(payload []byte)]struct{}{}}
+}
+
+// Publish ...
+func (ps *MemoryPubSub) Publish(topic string, payload []byte) error {
+	ps.mu.Lock()
+	subs := make([]func(payload []byte), 0, len(ps.subs[topic]))
+	for f := range ps.subs[topic] {
+		subs = append(subs, *f)
+	}
+	ps.mu.Unlock()
+
+	for _, f := range subs {
+		f(append([]byte(nil), payload...))
+	}
+	return nil
+}
+
+// Subscribe ...
+func (ps *MemoryPubSub) Subscribe(topic string, f func(payload []byte)) (func(), error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.subs[topic] == nil {
+		ps.subs[topic] = map[*func(payload []byte)]struct{}{}
+	}
+	ptr := &f
+	ps.subs[topic][ptr] = struct{}{}
+	return func() {
+		ps.mu.Lock()
+		defer ps.mu.Unlock()
+		delete(ps.subs[topic], ptr)
+	}, nil
+}
@@ -0,0 +1,110 @@
+package cluster
+
+import (
+	"github.com/google/uuid"
+	"sync"
+	"time"
+)
+
+// Member is a server instance that is part of a cluster.
+type Member struct {
+	// ID is the unique ID of the instance within the cluster.
+	ID string
+	// Address is the address that players may be transferred to in order to join the instance.
+	Address string
+	// LastSeen is the time at which the instance last registered itself with the Store.
+	LastSeen time.Time
+}
+
+// Store is a store shared by all instances in a cluster. Instances register themselves and the players online on
+// them with the Store, so that other instances may find out where a player is online. Store implementations must be
+// safe for concurrent use.
+type Store interface {
+	// Register registers a Member with the Store or updates it if a Member with the same ID was already registered.
+	Register(m Member) error
+	// Unregister removes the Member with the ID passed from the Store, together with all players registered as
+	// online on it.
+	Unregister(id string) error
+	// Members returns all Members currently registered with the Store.
+	Members() ([]Member, error)
+	// SetPlayer registers the player with the UUID passed as online on the Member with the ID passed.
+	SetPlayer(player uuid.UUID, id string) error
+	// RemovePlayer removes the player with the UUID passed from the Member with the ID passed. If the player is
+	// registered on a different Member, RemovePlayer does nothing.
+	RemovePlayer(player uuid.UUID, id string) error
+	// Player returns the ID of the Member that the player with the UUID passed is online on. If the player is not
+	// online anywhere in the cluster, false is returned.
+	Player(player uuid.UUID) (string, bool, error)
+}
+
+// MemoryStore is a Store implementation that keeps all data in memory. It can only share a cluster between multiple
+// instances running in the same process: Running instances on multiple machines requires a Store implementation for
+// a networked database. MemoryStore may be used as a reference for implementing one.
+type MemoryStore struct {
+	mu      sync.Mutex
+	members map[string]Member
+	players map[uuid.UUID]string
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{members: map[string]Member{}, players: map[uuid.UUID]string{}}
+}
+
+// Register ...
+func (s *MemoryStore) Register(m Member) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.members[m.ID] = m
+	return nil
+}
+
+// Unregister ...
+func (s *MemoryStore) Unregister(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.members, id)
+	for p, member := range s.players {
+		if member == id {
+			delete(s.players, p)
+		}
+	}
+	return nil
+}
+
+// Members ...
+func (s *MemoryStore) Members() ([]Member, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := make([]Member, 0, len(s.members))
+	for _, member := range s.members {
+		m = append(m, member)
+	}
+	return m, nil
+}
+
+// SetPlayer ...
+func (s *MemoryStore) SetPlayer(player uuid.UUID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.players[player] = id
+	return nil
+}
+
+// RemovePlayer ...
+func (s *MemoryStore) RemovePlayer(player uuid.UUID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.players[player] == id {
+		delete(s.players, player)
+	}
+	return nil
+}
+
+// Player ...
+func (s *MemoryStore) Player(player uuid.UUID) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.players[player]
+	return id, ok, nil
+}
@@ -48,6 +48,7 @@ type Command struct {
 	description string
 	usage       string
 	aliases     []string
+	permission  string
 }
 
 // New returns a new Command using the name and description passed. The Runnable passed must be a
@@ -97,6 +98,22 @@ func (cmd Command) Name() string {
 	return cmd.name
 }
 
+// Permission returns the permission node that a Source must have to execute the command, if Permissions were set
+// using SetPermissions. Unless changed using WithPermission, the permission node is 'command.' followed by the name
+// of the command.
+func (cmd Command) Permission() string {
+	if cmd.permission == "" {
+		return "command." + cmd.name
+	}
+	return cmd.permission
+}
+
+// WithPermission returns a copy of the command with its permission node changed to the node passed.
+func (cmd Command) WithPermission(node string) Command {
+	cmd.permission = node
+	return cmd
+}
+
 // Description returns the description of the command. The description is shown in the /help list, and
 // provides information on the functionality of a command.
 func (cmd Command) Description() string {
@@ -174,6 +191,9 @@ type ParamInfo struct {
 // they hold: Only the types are guaranteed to be consistent.
 func (cmd Command) Params(src Source) [][]ParamInfo {
 	params := make([][]ParamInfo, 0, len(cmd.v))
+	if !Permitted(src, cmd.Permission()) {
+		return params
+	}
 	for _, runnable := range cmd.v {
 		elem := reflect.New(runnable.Type()).Elem()
 		elem.Set(runnable)
@@ -201,6 +221,9 @@ func (cmd Command) Params(src Source) [][]ParamInfo {
 // Runnables returns a map of all Runnable implementations of the Command that a Source can execute.
 func (cmd Command) Runnables(src Source) map[int]Runnable {
 	m := make(map[int]Runnable, len(cmd.v))
+	if !Permitted(src, cmd.Permission()) {
+		return m
+	}
 	for i, runnable := range cmd.v {
 		v := runnable.Interface().(Runnable)
 		if allower, ok := v.(Allower); !ok || allower.Allow(src) {
@@ -220,7 +243,7 @@ func (cmd Command) String() string {
 // parsing was not successful or the Runnable could not be run by this source, an error is returned, and the
 // leftover command line.
 func (cmd Command) executeRunnable(v reflect.Value, args string, source Source, output *Output) (*Line, error) {
-	if a, ok := v.Interface().(Allower); ok && !a.Allow(source) {
+	if a, ok := v.Interface().(Allower); (ok && !a.Allow(source)) || !Permitted(source, cmd.Permission()) {
 		//lint:ignore ST1005 Error string is capitalised because it is shown to the player.
 		//goland:noinspection GoErrorStringFormat
 		return nil, fmt.Errorf("You cannot execute this command.")
@@ -0,0 +1,33 @@
+package cmd
+
+import "github.com/df-mc/atomic"
+
+// Permissions checks if Sources have permission nodes. Once set using SetPermissions, every Source must have the
+// permission node of a Command, as returned by Command.Permission, to be able to execute it.
+type Permissions interface {
+	// Permitted checks if the Source passed has the permission node passed.
+	Permitted(src Source, node string) bool
+}
+
+// permissions holds the Permissions set using SetPermissions.
+var permissions atomic.Value[Permissions]
+
+// SetPermissions sets the Permissions used to check if a Source may execute a Command. Passing nil removes the
+// Permissions, so that commands are only limited by their Allower implementations.
+func SetPermissions(p Permissions) {
+	permissions.Store(p)
+}
+
+// Permitted checks if the Source passed has the permission node passed using the Permissions set using
+// SetPermissions. If no Permissions are set, Permitted always returns true.
+func Permitted(src Source, node string) bool {
+	if p := permissions.Load(); p != nil {
+		return p.Permitted(src, node)
+	}
+	return true
+}
+
+// HasPermissions checks if Permissions were set using SetPermissions.
+func HasPermissions() bool {
+	return permissions.Load() != nil
+}
@@ -1,23 +1,29 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/crash"
 	"github.com/df-mc/dragonfly/server/entity"
 	"github.com/df-mc/dragonfly/server/internal/packbuilder"
 	"github.com/df-mc/dragonfly/server/player"
 	"github.com/df-mc/dragonfly/server/player/playerdb"
+	"github.com/df-mc/dragonfly/server/ratelimit"
 	"github.com/df-mc/dragonfly/server/session"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/biome"
 	"github.com/df-mc/dragonfly/server/world/generator"
 	"github.com/df-mc/dragonfly/server/world/mcdb"
 	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
 	"github.com/sandertv/gophertunnel/minecraft/resource"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/exp/slices"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 // Config contains options for starting a Minecraft server.
@@ -37,6 +43,9 @@ type Config struct {
 	// Resources is a slice of resource packs to use on the server. When joining
 	// the server, the player will then first be requested to download these
 	// resource packs.
+	// Encrypted packs must have their content key set using
+	// resource.Pack.WithContentKey. More packs may be added using
+	// Server.AddResources before calling Server.Listen.
 	Resources []*resource.Pack
 	// ResourcesRequires specifies if the downloading of resource packs is
 	// required to join the server. If set to true, players will not be able to
@@ -56,12 +65,71 @@ type Config struct {
 	// local games. Allowing players to join without authentication is generally
 	// a security hazard.
 	AuthDisabled bool
+	// Login holds options to validate the login requests of players joining
+	// more strictly, such as the tolerance for the expiry of the tokens in the
+	// login chain and the keys that the chain must be signed by.
+	Login LoginConfig
+	// LoginHandler is the LoginHandler called for every player logging in,
+	// which may be used to validate the claims of the login request further
+	// before the player spawns. If left nil, LoginHandler is set to
+	// NopLoginHandler.
+	LoginHandler LoginHandler
+	// RateLimits holds the limits of the rates at which clients may log in,
+	// chat, execute commands and interact. Limits with a rate of 0 are not
+	// enforced.
+	RateLimits ratelimit.Config
 	// MaxPlayers is the maximum amount of players allowed to join the server at
 	// once.
 	MaxPlayers int
+	// JoinQueueSize is the maximum amount of players that may wait in the
+	// join queue when the server is full. Players in the queue are admitted
+	// in order as soon as other players leave. If left as 0, or if MaxPlayers
+	// is 0, players joining a full server are disconnected immediately.
+	JoinQueueSize int
+	// QueueBypass is called for every player joining while the server is full.
+	// If it returns true, the player joins right away, bypassing both the
+	// maximum player count and the join queue. QueueBypass may be left nil.
+	QueueBypass func(d login.IdentityData) bool
+	// QueueHandler is the QueueHandler used to handle players joining and
+	// leaving the join queue. If left nil, QueueHandler is set to
+	// NopQueueHandler.
+	QueueHandler QueueHandler
 	// MaxChunkRadius is the maximum view distance that each player may have,
 	// measured in chunks. A chunk radius generally leads to more memory usage.
 	MaxChunkRadius int
+	// ChunksPerTick is the maximum amount of chunks sent to each player every
+	// tick. Chunks closest to the player are sent first. If left as 0, 4
+	// chunks are sent every tick.
+	ChunksPerTick int
+	// EntitiesPerTick is the maximum amount of entities spawned to each
+	// player every tick when they enter the view of the player. Entities
+	// closest to the player are spawned first, so that many entities
+	// entering the view at once, for example after teleporting, do not
+	// cause a burst of packets. If left as 0, 32 entities are spawned
+	// every tick. Setting it to -1 or lower spawns entities immediately.
+	EntitiesPerTick int
+	// AntiXray is the session.AntiXrayMode used to obfuscate ores in chunks
+	// sent to players. By default, the anti-xray engine is disabled.
+	AntiXray session.AntiXrayMode
+	// MovementMode is the session.MovementMode in which the server authorises
+	// the movement of players. It decides how movement rejected by the server
+	// is corrected. By default, players are teleported back.
+	MovementMode session.MovementMode
+	// MovementPolicy is the player.MovementPolicy used to validate the
+	// movement of players. Players moving faster than their speed allows,
+	// flying or moving through blocks are either moved back or kicked. By
+	// default, movement is not validated.
+	MovementPolicy player.MovementPolicy
+	// MaxBandwidth is the amount of bytes per second that may be sent to a
+	// player before low priority traffic, such as chunks and particles, is
+	// held back until the next second. If left as 0, no limit is applied.
+	MaxBandwidth int
+	// FlushRate is the interval at which packets sent to a player are written
+	// to its connection in a single batch. While MaxBandwidth is exceeded,
+	// chunks and particles are held back in favour of packets related to
+	// movement and combat. If left as 0, time.Second/20 is used. If negative,
+	// packets are written immediately.
+	FlushRate time.Duration
 	// JoinMessage, QuitMessage and ShutdownMessage are the messages to send for
 	// when a player joins or quits the server and when the server shuts down,
 	// kicking all online players. JoinMessage and QuitMessage may have a '%v'
@@ -80,6 +148,10 @@ type Config struct {
 	// ReadOnlyWorld specifies if the standard worlds should be read only. If
 	// set to true, the WorldProvider won't be saved to at all.
 	ReadOnlyWorld bool
+	// RegenerateCorruptChunks specifies if chunks of the standard worlds that
+	// cannot be loaded from the WorldProvider because their data is corrupt
+	// should be generated again. See world.Config.RegenerateCorruptChunks.
+	RegenerateCorruptChunks bool
 	// Generator should return a function that specifies the world.Generator to
 	// use for every world.Dimension (world.Overworld, world.Nether and
 	// world.End). If left empty, Generator will be set to a flat world for each
@@ -92,10 +164,52 @@ type Config struct {
 	// left as 0, the RandomTickSpeed will default to a speed of 3 blocks per
 	// sub chunk per tick (normal ticking speed).
 	RandomTickSpeed int
+	// RandSeed, if not 0, is the seed used for all randomness in the default
+	// worlds, such as random ticks, drops and the AI of mobs. Setting it makes
+	// the worlds behave deterministically, which is useful for reproducible
+	// tests and replays. Each dimension derives its own seed from RandSeed.
+	RandSeed int64
+	// TickBudget is the amount of time that each world may spend on a single
+	// tick, including loading and generating new chunks for players. Once it
+	// is used up, new chunks are only loaded again during the next tick, so
+	// that players exploring new areas do not slow down the server for
+	// everyone. If left as 0, the budget is one tick (50ms). Setting it to -1
+	// or lower disables the throttling of chunk loading.
+	TickBudget time.Duration
+	// SpawnProtection is the radius in blocks of the area around the spawn
+	// of the overworld in which players may not build or interact with
+	// blocks. Only players with the player.SpawnProtectionExemptPermission
+	// node are exempted. If left as 0, the spawn is not protected.
+	SpawnProtection int
+	// SpawnRadius is the radius in blocks around the spawn of the overworld
+	// within which players joining the server for the first time are
+	// spawned, at a random position on solid ground. If left as 0, new
+	// players spawn exactly at the spawn of the world.
+	SpawnRadius int
+	// SaveInterval is the interval at which changed chunks of the default
+	// worlds are saved to the world provider. If left as 0, changed chunks
+	// are saved every 5 minutes. Setting it to -1 or lower disables saving
+	// chunks periodically.
+	SaveInterval time.Duration
+	// Rules holds the world.Rules of the default worlds, by their
+	// world.Dimension. The Rules hold expressions that tune gameplay, such as
+	// the damage dealt by falling or by mobs, in each world. Worlds of which
+	// the dimension is not present have no Rules.
+	Rules map[world.Dimension]world.Rules
 	// Entities is a world.EntityRegistry with all entity types registered that
 	// may be added to the Server's worlds. If no entity types are registered,
 	// Entities will be set to entity.DefaultRegistry.
 	Entities world.EntityRegistry
+	// CrashReporter is the crash.Reporter used to recover panics in the
+	// worlds, sessions and providers of the Server. A panic in a session only
+	// disconnects the player of that session. If left nil, a crash.Reporter
+	// that logs to Log but does not write reports to disk is used.
+	CrashReporter *crash.Reporter
+
+	// status returns the Status of the Server created using the Config. It
+	// is set by Config.New, so that the listeners created using Listeners
+	// may advertise the Status of the Server.
+	status func() Status
 }
 
 // Logger is used to report information and errors from a dragonfly Server. Any
@@ -108,6 +222,31 @@ type Logger interface {
 	Warnf(format string, v ...any)
 }
 
+// subsystemLog returns the Logger that the subsystem of a Server passed, such
+// as 'world' or 'session', logs to. If the Logger passed supports subsystems,
+// like a logging.Logger, the Logger returned logs at the level set for the
+// subsystem. Otherwise, the Logger passed is returned with a field holding the
+// name of the subsystem, if it supports fields.
+func subsystemLog(l Logger, name string) Logger {
+	if v, ok := l.(interface {
+		Subsystem(name string) *logrus.Entry
+	}); ok {
+		return v.Subsystem(name)
+	}
+	return withField(l, "subsystem", name)
+}
+
+// withField returns the Logger passed with a field added to all entries it
+// logs, if the Logger supports fields, such as a logrus.Logger.
+func withField(l Logger, key string, value any) Logger {
+	if v, ok := l.(interface {
+		WithField(key string, value any) *logrus.Entry
+	}); ok {
+		return v.WithField(key, value)
+	}
+	return l
+}
+
 // New creates a Server using fields of conf. The Server's worlds are created
 // and connections from the Server's listeners may be accepted by calling
 // Server.Listen() and Server.Accept() afterwards.
@@ -118,6 +257,10 @@ func (conf Config) New() *Server {
 	if len(conf.Listeners) == 0 {
 		conf.Log.Warnf("config: no listeners set, no connections will be accepted")
 	}
+	// All custom blocks must have been registered by now, so their states can
+	// be sorted into the block palette before any runtime IDs are used.
+	world_finaliseBlockRegistry()
+	registerCreativeItems()
 	if conf.Name == "" {
 		conf.Name = "Dragonfly Server"
 	}
@@ -133,9 +276,21 @@ func (conf Config) New() *Server {
 	if conf.Generator == nil {
 		conf.Generator = loadGenerator
 	}
+	if conf.FlushRate == 0 {
+		conf.FlushRate = time.Second / 20
+	}
 	if conf.MaxChunkRadius == 0 {
 		conf.MaxChunkRadius = 12
 	}
+	if conf.QueueHandler == nil {
+		conf.QueueHandler = NopQueueHandler{}
+	}
+	if conf.LoginHandler == nil {
+		conf.LoginHandler = NopLoginHandler{}
+	}
+	if conf.CrashReporter == nil {
+		conf.CrashReporter = &crash.Reporter{Log: conf.Log}
+	}
 	if len(conf.Entities.Types()) == 0 {
 		conf.Entities = entity.DefaultRegistry
 	}
@@ -149,10 +304,16 @@ func (conf Config) New() *Server {
 
 	srv := &Server{
 		conf:     conf,
+		limits:   conf.RateLimits.New(),
 		incoming: make(chan *session.Session),
+		closed:   make(chan struct{}),
 		p:        make(map[uuid.UUID]*player.Player),
 		world:    &world.World{}, nether: &world.World{}, end: &world.World{},
 	}
+	if conf.JoinQueueSize > 0 && conf.MaxPlayers > 0 {
+		srv.queue = &joinQueue{max: conf.MaxPlayers, size: conf.JoinQueueSize}
+	}
+	srv.conf.status = srv.Status
 	srv.world = srv.createWorld(world.Overworld, &srv.nether, &srv.end)
 	srv.nether = srv.createWorld(world.Nether, &srv.world, &srv.end)
 	srv.end = srv.createWorld(world.End, &srv.nether, &srv.world)
@@ -192,6 +353,10 @@ type UserConfig struct {
 		// server. Leave this empty to disable it. %v is the placeholder for the
 		// username of the player
 		QuitMessage string
+		// CrashReportFolder is the folder that crash reports are written to
+		// when a panic is recovered. Leave this empty to disable writing crash
+		// reports.
+		CrashReportFolder string
 	}
 	World struct {
 		// SaveData controls whether a world's data will be saved and loaded.
@@ -202,16 +367,67 @@ type UserConfig struct {
 		SaveData bool
 		// Folder is the folder that the data of the world resides in.
 		Folder string
+		// AntiXray is the mode of the anti-xray engine used to obfuscate ores
+		// in chunks sent to players. 0 disables the engine, 1 hides ores that
+		// are not exposed and 2 replaces them with random ores.
+		AntiXray int
+		// SpawnProtection is the radius in blocks around the spawn of the
+		// world in which only players with the 'spawnprotection.exempt'
+		// permission may build. If set to 0, the spawn is not protected.
+		SpawnProtection int
+		// SpawnRadius is the radius in blocks around the spawn of the world
+		// within which new players are spawned at a random position. If set
+		// to 0, new players spawn exactly at the spawn of the world.
+		SpawnRadius int
+		// SaveInterval is the interval in minutes at which changed chunks of
+		// the world are saved. If set to 0, changed chunks are saved every 5
+		// minutes. If set to -1, chunks are only saved when unloaded.
+		SaveInterval int
+	}
+	Rules struct {
+		// Overworld, Nether and End hold expressions that tune gameplay in
+		// each of the worlds. FallDamage is the damage dealt by falling and
+		// may use the variables 'damage' and 'distance', Experience is the
+		// experience gained from experience orbs and may use 'amount', and
+		// MobDamage is the damage dealt by mobs and may use 'damage' and
+		// 'difficulty'. For example, 'damage * 0.5' halves the damage dealt.
+		// Leave an expression empty to keep the default behaviour.
+		Overworld, Nether, End world.RuleSource
 	}
 	Players struct {
 		// MaxCount is the maximum amount of players allowed to join the server
 		// at the same time. If set to 0, the amount of maximum players will
 		// grow every time a player joins.
 		MaxCount int
+		// QueueSize is the maximum amount of players that may wait in the join
+		// queue when the server is full. If set to 0, players joining a full
+		// server are disconnected immediately.
+		QueueSize int
 		// MaximumChunkRadius is the maximum chunk radius that players may set
 		// in their settings. If they try to set it above this number, it will
 		// be capped and set to the max.
 		MaximumChunkRadius int
+		// ChunksPerTick is the maximum amount of chunks sent to each player
+		// every tick. If set to 0, 4 chunks are sent every tick.
+		ChunksPerTick int
+		// EntitiesPerTick is the maximum amount of entities spawned to each
+		// player every tick, closest first. If set to 0, 32 entities are
+		// spawned every tick and if set to -1, entities are spawned
+		// immediately.
+		EntitiesPerTick int
+		// MovementMode is the mode in which the movement of players is
+		// authorised. 0 teleports players back when their movement is
+		// rejected and 1 makes their client rewind and replay its movement,
+		// which results in smoother corrections.
+		MovementMode int
+		// MovementPolicy is the policy used to validate the movement of
+		// players. 0 disables the validation, 1 moves players back when they
+		// move in a way that is not possible in the game and 2 kicks them.
+		MovementPolicy int
+		// MaximumBandwidth is the amount of bytes per second that may be sent
+		// to a player before chunks and particles are held back. If set to 0,
+		// no limit is applied.
+		MaximumBandwidth int
 		// SaveData controls whether a player's data will be saved and loaded.
 		// If true, the server will use the default LevelDB data provider and if
 		// false, an empty provider will be used. To use your own provider, turn
@@ -232,6 +448,110 @@ type UserConfig struct {
 		// Required is a boolean to force the client to load the resource pack
 		// on join. If they do not accept, they'll have to leave the server.
 		Required bool
+		// ContentKeys holds the content keys of encrypted resource packs in
+		// Folder, by the UUID of the pack. Alternatively, the content key of
+		// a pack may be stored in a file next to it with the same name and the
+		// .key extension, such as 'pack.mcpack.key'.
+		ContentKeys map[string]string
+	}
+	Plugins struct {
+		// Folder is the folder that plugins, compiled as Go plugins with the
+		// .so extension, are loaded from when the server starts. Leave this
+		// empty to disable loading plugins.
+		Folder string
+	}
+	Scripts struct {
+		// Folder is the folder that Lua scripts, with the .lua extension, are
+		// loaded from. Scripts are reloaded automatically when they are
+		// changed. Leave this empty to disable loading scripts.
+		Folder string
+	}
+	Permissions struct {
+		// Folder is the folder that permission groups and the permissions of
+		// players are stored in. When set, players may only execute commands
+		// of which they have the permission node, such as 'command.mute'.
+		// Leave this empty to disable permissions.
+		Folder string
+	}
+	Translations struct {
+		// Folder is the folder that server-side translations are loaded from.
+		// Every file holds the translations of one locale and is named after
+		// it, such as 'en_US.json' or 'de_DE.yaml'.
+		Folder string
+	}
+	RateLimits struct {
+		// Login, Chat, Command and Interaction are the limits of the rates at
+		// which clients may log in (per IP address), chat and execute
+		// commands (per player) and interact with blocks, items and entities.
+		// Rate is the amount of actions allowed per second and Burst the
+		// amount allowed in quick succession. Set Rate to 0 to disable a
+		// limit.
+		Login, Chat, Command, Interaction ratelimit.Limit
+	}
+	Moderation struct {
+		// Folder is the folder that bans, mutes, warnings and the whitelist
+		// are stored in. Leave this empty to disable moderation and the
+		// whitelist.
+		Folder string
+		// Whitelist specifies if only players on the whitelist may join. The
+		// whitelist may also be enabled using '/whitelist on'.
+		Whitelist bool
+	}
+	Webhook struct {
+		// URL is the HTTP endpoint that events of players, such as block
+		// changes, chat messages and joins, are posted to as JSON. Leave this
+		// empty to disable exporting events.
+		URL string
+		// Events holds the kinds of events exported: join, quit, chat,
+		// block_break and block_place. If empty, all events are exported.
+		Events []string
+		// BatchSize is the maximum amount of events posted in one request.
+		BatchSize int
+		// FlushInterval is the maximum time in seconds that an event waits
+		// before being posted.
+		FlushInterval int
+	}
+	RCON struct {
+		// Enabled specifies if admin tools may run commands on the server
+		// using the RCON remote console protocol.
+		Enabled bool
+		// Address is the address on which the server listens for RCON
+		// clients.
+		Address string
+		// Password is the password that RCON clients must authenticate
+		// with. RCON cannot be enabled without a password.
+		Password string
+		// MaxConnections is the maximum amount of RCON clients connected at
+		// the same time.
+		MaxConnections int
+		// Groups holds the permission groups that RCON clients are a member
+		// of. If empty, RCON clients may execute all commands.
+		Groups []string
+	}
+	Query struct {
+		// Enabled specifies if server list sites and launchers may fetch the
+		// MOTD, player count, players and plugins of the server using the
+		// query protocol.
+		Enabled bool
+		// Address is the UDP address on which the server answers queries.
+		Address string
+	}
+	Metrics struct {
+		// Enabled specifies if metrics about the performance of the server
+		// are served in the Prometheus format.
+		Enabled bool
+		// Address is the address of the HTTP server serving metrics on the
+		// /metrics endpoint.
+		Address string
+	}
+	Log struct {
+		// Level is the default level of messages logged: trace, debug, info,
+		// warning, error, fatal or panic.
+		Level string
+		// Subsystems holds the levels of subsystems, such as world, session,
+		// network or provider, that log at a level other than Level. Levels
+		// may also be changed while the server is running using /loglevel.
+		Subsystems map[string]string
 	}
 }
 
@@ -241,26 +561,51 @@ type UserConfig struct {
 func (uc UserConfig) Config(log Logger) (Config, error) {
 	var err error
 	conf := Config{
-		Log:                     log,
-		Name:                    uc.Server.Name,
-		ResourcesRequired:       uc.Resources.Required,
-		AuthDisabled:            !uc.Server.AuthEnabled,
+		Log:               log,
+		Name:              uc.Server.Name,
+		ResourcesRequired: uc.Resources.Required,
+		AuthDisabled:      !uc.Server.AuthEnabled,
+		RateLimits: ratelimit.Config{
+			Login:       uc.RateLimits.Login,
+			Chat:        uc.RateLimits.Chat,
+			Command:     uc.RateLimits.Command,
+			Interaction: uc.RateLimits.Interaction,
+		},
 		MaxPlayers:              uc.Players.MaxCount,
+		JoinQueueSize:           uc.Players.QueueSize,
 		MaxChunkRadius:          uc.Players.MaximumChunkRadius,
+		ChunksPerTick:           uc.Players.ChunksPerTick,
+		EntitiesPerTick:         uc.Players.EntitiesPerTick,
+		AntiXray:                session.AntiXrayMode(uc.World.AntiXray),
+		SpawnProtection:         uc.World.SpawnProtection,
+		SpawnRadius:             uc.World.SpawnRadius,
+		SaveInterval:            time.Duration(uc.World.SaveInterval) * time.Minute,
+		MovementMode:            session.MovementMode(uc.Players.MovementMode),
+		MovementPolicy:          player.MovementPolicy(uc.Players.MovementPolicy),
+		MaxBandwidth:            uc.Players.MaximumBandwidth,
 		JoinMessage:             uc.Server.JoinMessage,
 		QuitMessage:             uc.Server.QuitMessage,
 		ShutdownMessage:         uc.Server.ShutdownMessage,
 		DisableResourceBuilding: !uc.Resources.AutoBuildPack,
+		CrashReporter:           &crash.Reporter{Dir: uc.Server.CrashReportFolder, Log: log},
+	}
+	if !conf.AntiXray.Valid() {
+		return conf, fmt.Errorf("invalid anti-xray mode %v: must be 0, 1 or 2", uc.World.AntiXray)
+	}
+	conf.Rules = make(map[world.Dimension]world.Rules, 3)
+	for dim, src := range map[world.Dimension]world.RuleSource{world.Overworld: uc.Rules.Overworld, world.Nether: uc.Rules.Nether, world.End: uc.Rules.End} {
+		if conf.Rules[dim], err = src.Rules(); err != nil {
+			return conf, fmt.Errorf("%v rules: %w", strings.ToLower(fmt.Sprint(dim)), err)
+		}
 	}
 	if uc.World.SaveData {
-		conf.WorldProvider, err = mcdb.Config{Log: log}.Open(uc.World.Folder)
+		conf.WorldProvider, err = mcdb.Config{Log: subsystemLog(log, "provider")}.Open(uc.World.Folder)
 		if err != nil {
 			return conf, fmt.Errorf("create world provider: %w", err)
 		}
 	}
-	conf.Resources, err = loadResources(uc.Resources.Folder)
-	if err != nil {
-		return conf, fmt.Errorf("load resources: %w", err)
+	if conf.Resources, err = uc.LoadResources(); err != nil {
+		return conf, err
 	}
 	if uc.Players.SaveData {
 		conf.PlayerProvider, err = playerdb.NewProvider(uc.Players.Folder)
@@ -272,20 +617,45 @@ func (uc UserConfig) Config(log Logger) (Config, error) {
 	return conf, nil
 }
 
-// loadResources loads all resource packs found in a directory passed.
-func loadResources(dir string) ([]*resource.Pack, error) {
+// LoadResources loads the resource packs in the resources folder of the
+// UserConfig, in the same way as UserConfig.Config. It may be used to read the
+// folder again after packs were added or removed.
+func (uc UserConfig) LoadResources() ([]*resource.Pack, error) {
+	packs, err := loadResources(uc.Resources.Folder, uc.Resources.ContentKeys)
+	if err != nil {
+		return nil, fmt.Errorf("load resources: %w", err)
+	}
+	return packs, nil
+}
+
+// loadResources loads all resource packs found in a directory passed. The
+// content keys of encrypted packs are taken from the map passed, by UUID, or
+// from a .key file next to the pack.
+func loadResources(dir string, keys map[string]string) ([]*resource.Pack, error) {
 	_ = os.MkdirAll(dir, 0777)
 
 	resources, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, fmt.Errorf("read dir: %w", err)
 	}
-	packs := make([]*resource.Pack, len(resources))
-	for i, entry := range resources {
-		packs[i], err = resource.ReadPath(filepath.Join(dir, entry.Name()))
+	packs := make([]*resource.Pack, 0, len(resources))
+	for _, entry := range resources {
+		if filepath.Ext(entry.Name()) == ".key" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		pack, err := resource.ReadPath(path)
 		if err != nil {
 			return nil, fmt.Errorf("compile resource (%v): %w", entry.Name(), err)
 		}
+		if key, ok := keys[pack.UUID()]; ok {
+			pack = pack.WithContentKey(key)
+		} else if key, err := os.ReadFile(path + ".key"); err == nil {
+			pack = pack.WithContentKey(strings.TrimSpace(string(key)))
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("read content key (%v): %w", entry.Name(), err)
+		}
+		packs = append(packs, pack)
 	}
 	return packs, nil
 }
@@ -312,6 +682,8 @@ func DefaultConfig() UserConfig {
 	c.Server.AuthEnabled = true
 	c.Server.JoinMessage = "%v has joined the game"
 	c.Server.QuitMessage = "%v has left the game"
+	c.Server.CrashReportFolder = "crash-reports"
+	c.Log.Level = "debug"
 	c.World.SaveData = true
 	c.World.Folder = "world"
 	c.Players.MaximumChunkRadius = 32
@@ -319,6 +691,19 @@ func DefaultConfig() UserConfig {
 	c.Players.Folder = "players"
 	c.Resources.AutoBuildPack = true
 	c.Resources.Folder = "resources"
+	c.Plugins.Folder = "plugins"
+	c.Scripts.Folder = "scripts"
+	c.Permissions.Folder = "permissions"
+	c.Translations.Folder = "translations"
+	c.RateLimits.Login = ratelimit.Limit{Rate: 0.2, Burst: 5}
+	c.RateLimits.Chat = ratelimit.Limit{Rate: 1, Burst: 10}
+	c.RateLimits.Command = ratelimit.Limit{Rate: 2, Burst: 10}
+	c.RateLimits.Interaction = ratelimit.Limit{Rate: 20, Burst: 40}
+	c.Moderation.Folder = "moderation"
+	c.RCON.Address = ":25575"
+	c.RCON.MaxConnections = 5
+	c.Query.Address = ":19133"
+	c.Metrics.Address = ":9100"
 	c.Resources.Required = false
 	return c
 }
@@ -0,0 +1,83 @@
+package console
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"golang.org/x/exp/slices"
+	"strings"
+)
+
+// Complete returns the possible completions of the last word of the line passed. The first word is completed as
+// the name of a command. The words after it are completed using the parameters of the command, completing enum
+// options, sub commands, booleans and the names of online players.
+func (c *Console) Complete(line string) []string {
+	args := strings.Split(strings.TrimPrefix(strings.TrimLeft(line, " "), "/"), " ")
+	prefix := args[len(args)-1]
+
+	var options []string
+	if len(args) == 1 {
+		for alias, command := range cmd.Commands() {
+			if len(command.Runnables(c)) != 0 {
+				options = append(options, alias)
+			}
+		}
+		return filterOptions(options, prefix)
+	}
+	command, ok := cmd.ByAlias(args[0])
+	if !ok {
+		return nil
+	}
+	index := len(args) - 2
+	for _, params := range command.Params(c) {
+		if index >= len(params) || !matchesSubCommands(params, args[1:index+1]) {
+			continue
+		}
+		switch v := params[index].Value.(type) {
+		case cmd.Enum:
+			options = append(options, v.Options(c)...)
+		case cmd.SubCommand:
+			options = append(options, params[index].Name)
+		case bool:
+			options = append(options, "true", "false")
+		case []cmd.Target:
+			for _, p := range c.conf.Server.Players() {
+				options = append(options, p.Name())
+			}
+		}
+	}
+	return filterOptions(options, prefix)
+}
+
+// matchesSubCommands checks if the arguments passed match the sub commands in the parameters passed, so that only
+// parameters of runnables that the arguments could belong to are completed.
+func matchesSubCommands(params []cmd.ParamInfo, args []string) bool {
+	for i, arg := range args {
+		if _, ok := params[i].Value.(cmd.SubCommand); ok && !strings.EqualFold(params[i].Name, arg) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterOptions returns the sorted, unique options that start with the prefix passed. Options are matched
+// case-insensitively.
+func filterOptions(options []string, prefix string) []string {
+	filtered := make([]string, 0, len(options))
+	for _, o := range options {
+		if strings.HasPrefix(strings.ToLower(o), strings.ToLower(prefix)) {
+			filtered = append(filtered, o)
+		}
+	}
+	slices.Sort(filtered)
+	return slices.Compact(filtered)
+}
+
+// commonPrefix returns the longest prefix shared by all options passed.
+func commonPrefix(options []string) string {
+	prefix := options[0]
+	for _, o := range options[1:] {
+		for !strings.HasPrefix(o, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
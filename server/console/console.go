@@ -0,0 +1,285 @@
+// Package console implements an interactive console that may be used to run commands on a server from the terminal
+// that it was started in. The console supports tab completion of commands and their arguments, keeps a history of
+// commands executed that persists across restarts and renders the colour codes of messages written to it.
+package console
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/text"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Config holds the settings of a Console. Calling Config.New() creates a Console.
+type Config struct {
+	// Server is the Server that commands are run on. Server must not be nil.
+	Server *server.Server
+	// HistoryFile is the path of the file that the command history is stored in. If left empty, the history is not
+	// persisted.
+	HistoryFile string
+	// HistorySize is the maximum amount of commands kept in the history. If left as 0, HistorySize is set to 500.
+	HistorySize int
+	// In is the io.Reader that input is read from. If nil, In is set to os.Stdin.
+	In io.Reader
+	// Out is the io.Writer that output is written to. If nil, Out is set to os.Stdout.
+	Out io.Writer
+}
+
+// Console is an interactive console reading commands from an io.Reader. Console implements cmd.Source, so that
+// commands run through it are executed by the Console itself.
+// Console implements io.Writer and chat.Subscriber. Writing output through the Console, rather than to the
+// io.Writer directly, ensures the line currently being typed is not interrupted by the output.
+type Console struct {
+	conf Config
+	hist *history
+
+	mu      sync.Mutex
+	prompt  bool
+	line    []rune
+	restore func()
+}
+
+// New creates a Console using the settings in the Config. The history is loaded from Config.HistoryFile if it
+// exists. Console.Run must be called to start reading input.
+func (conf Config) New() (*Console, error) {
+	if conf.Server == nil {
+		panic("new console: server must not be nil")
+	}
+	if conf.HistorySize <= 0 {
+		conf.HistorySize = 500
+	}
+	if conf.In == nil {
+		conf.In = os.Stdin
+	}
+	if conf.Out == nil {
+		conf.Out = os.Stdout
+	}
+	hist, err := loadHistory(conf.HistoryFile, conf.HistorySize)
+	if err != nil {
+		return nil, fmt.Errorf("new console: %w", err)
+	}
+	return &Console{conf: conf, hist: hist}, nil
+}
+
+// Run reads input from the Console until the io.Reader is closed or returns an error. Each line read is executed
+// as a command. If the Console reads from a terminal, the terminal is put in a mode in which the line can be edited
+// and completed, and the previous mode is restored when Run returns.
+func (c *Console) Run() error {
+	if f, ok := c.conf.In.(*os.File); ok {
+		if restore, ok := makeRaw(int(f.Fd())); ok {
+			c.mu.Lock()
+			c.restore = restore
+			c.mu.Unlock()
+
+			defer c.Close()
+			return c.runInteractive(bufio.NewReader(f))
+		}
+	}
+	s := bufio.NewScanner(c.conf.In)
+	for s.Scan() {
+		c.execute(s.Text())
+	}
+	return s.Err()
+}
+
+// Close restores the previous mode of the terminal if it was changed by Console.Run. Close should be called before
+// the program ends, as Run does not return until the next input is read.
+func (c *Console) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.restore != nil {
+		c.restore()
+		c.restore = nil
+		_, _ = io.WriteString(c.conf.Out, "\r\x1b[K")
+	}
+	c.prompt = false
+	return nil
+}
+
+// runInteractive reads input from the terminal rune by rune, editing the current line until a line is submitted.
+func (c *Console) runInteractive(r *bufio.Reader) error {
+	c.mu.Lock()
+	c.prompt = true
+	c.redraw()
+	c.mu.Unlock()
+
+	cursor := c.hist.cursor()
+	for {
+		ch, _, err := r.ReadRune()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		c.mu.Lock()
+		switch ch {
+		case '\r', '\n':
+			line := string(c.line)
+			c.line = c.line[:0]
+			_, _ = io.WriteString(c.conf.Out, "\n")
+			c.prompt = false
+			c.mu.Unlock()
+
+			c.execute(line)
+			cursor = c.hist.cursor()
+
+			c.mu.Lock()
+			c.prompt = true
+			c.redraw()
+		case '\t':
+			c.complete()
+		case 0x7f, '\b':
+			if len(c.line) > 0 {
+				c.line = c.line[:len(c.line)-1]
+				c.redraw()
+			}
+		case 0x04:
+			// Ctrl+D: Stop reading input if the line is empty, similarly to an EOF.
+			if len(c.line) == 0 {
+				c.mu.Unlock()
+				return nil
+			}
+		case 0x15:
+			// Ctrl+U: Clear the line.
+			c.line = c.line[:0]
+			c.redraw()
+		case 0x1b:
+			c.escape(r, &cursor)
+		default:
+			if ch >= ' ' {
+				c.line = append(c.line, ch)
+				_, _ = io.WriteString(c.conf.Out, string(ch))
+			}
+		}
+		c.mu.Unlock()
+	}
+}
+
+// escape handles an escape sequence read from the terminal. The up and down arrow keys are used to browse the
+// history. Other escape sequences are ignored.
+func (c *Console) escape(r *bufio.Reader, cursor *int) {
+	if b, err := r.ReadByte(); err != nil || (b != '[' && b != 'O') {
+		return
+	}
+	b, err := r.ReadByte()
+	if err != nil {
+		return
+	}
+	switch b {
+	case 'A':
+		if line, ok := c.hist.at(*cursor - 1); ok {
+			*cursor--
+			c.line = []rune(line)
+			c.redraw()
+		}
+	case 'B':
+		if line, ok := c.hist.at(*cursor + 1); ok {
+			*cursor++
+			c.line = []rune(line)
+		} else {
+			*cursor = c.hist.cursor()
+			c.line = c.line[:0]
+		}
+		c.redraw()
+	}
+}
+
+// complete completes the last word of the line currently being typed. If multiple completions are possible, the
+// longest prefix shared by all of them is completed and the options are listed if no progress could be made.
+func (c *Console) complete() {
+	line := string(c.line)
+	options := c.Complete(line)
+	if len(options) == 0 {
+		return
+	}
+	word := line[strings.LastIndexByte(line, ' ')+1:]
+	if len(options) == 1 {
+		c.line = []rune(line[:len(line)-len(word)] + options[0] + " ")
+		c.redraw()
+		return
+	}
+	if prefix := commonPrefix(options); len(prefix) > len(word) {
+		c.line = []rune(line[:len(line)-len(word)] + prefix)
+		c.redraw()
+		return
+	}
+	_, _ = io.WriteString(c.conf.Out, "\r\x1b[K"+strings.Join(options, "  ")+"\n")
+	c.redraw()
+}
+
+// redraw clears the current line of the terminal and writes the prompt and the line currently being typed.
+func (c *Console) redraw() {
+	_, _ = io.WriteString(c.conf.Out, "\r\x1b[K> "+string(c.line))
+}
+
+// execute executes a line as a command and adds it to the history.
+func (c *Console) execute(line string) {
+	line = strings.TrimPrefix(strings.TrimSpace(line), "/")
+	if line == "" {
+		return
+	}
+	if err := c.hist.add(line); err != nil {
+		c.Message(text.Colourf("<red>Error saving command history: %v</red>", err))
+	}
+	name, args, _ := strings.Cut(line, " ")
+	command, ok := cmd.ByAlias(name)
+	if !ok {
+		c.Message(text.Colourf("<red>Unknown command: %v. Please check that the command exists and that you have permission to use it.</red>", name))
+		return
+	}
+	command.Execute(args, c)
+}
+
+// Write writes p to the output of the Console. If a line is currently being typed, it is cleared before writing p
+// and written again afterwards, so that output does not interrupt it.
+func (c *Console) Write(p []byte) (n int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.prompt {
+		return c.conf.Out.Write(p)
+	}
+	if _, err := io.WriteString(c.conf.Out, "\r\x1b[K"+strings.TrimSuffix(string(p), "\n")+"\n"); err != nil {
+		return 0, err
+	}
+	c.redraw()
+	return len(p), nil
+}
+
+// Message writes a message to the Console, rendering its colour codes as ANSI escape codes. It is formatted
+// following the rules of fmt.Sprintln.
+func (c *Console) Message(a ...any) {
+	_, _ = c.Write([]byte(text.ANSI(strings.TrimSuffix(fmt.Sprintln(a...), "\n") + "\n")))
+}
+
+// Name returns the name of the Console, which is always 'Console'.
+func (c *Console) Name() string {
+	return "Console"
+}
+
+// Position returns the position of the Console, which is always the origin.
+func (c *Console) Position() mgl64.Vec3 {
+	return mgl64.Vec3{}
+}
+
+// World returns the default world of the Server of the Console.
+func (c *Console) World() *world.World {
+	return c.conf.Server.World()
+}
+
+// SendCommandOutput writes the messages and errors of the cmd.Output passed to the Console.
+func (c *Console) SendCommandOutput(o *cmd.Output) {
+	for _, m := range o.Messages() {
+		c.Message(m)
+	}
+	for _, e := range o.Errors() {
+		c.Message(text.Colourf("<red>%v</red>", e))
+	}
+}
@@ -0,0 +1,19 @@
+package console
+
+import (
+	"github.com/df-mc/dragonfly/server/text"
+	"github.com/sirupsen/logrus"
+)
+
+// Formatter is a logrus.Formatter that renders the colour codes in log messages as ANSI escape codes before
+// formatting them using the logrus.Formatter it wraps.
+type Formatter struct {
+	logrus.Formatter
+}
+
+// Format ...
+func (f Formatter) Format(e *logrus.Entry) ([]byte, error) {
+	cp := *e
+	cp.Message = text.ANSI(e.Message)
+	return f.Formatter.Format(&cp)
+}
@@ -0,0 +1,80 @@
+package console
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+)
+
+// history holds the commands executed through a Console, optionally persisting them to a file.
+type history struct {
+	file string
+	size int
+
+	mu    sync.Mutex
+	lines []string
+}
+
+// loadHistory loads the history from the file passed. If the file is empty or does not exist, an empty history is
+// returned.
+func loadHistory(file string, size int) (*history, error) {
+	h := &history{file: file, size: size}
+	if file == "" {
+		return h, nil
+	}
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); line != "" {
+			h.lines = append(h.lines, line)
+		}
+	}
+	if len(h.lines) > size {
+		h.lines = h.lines[len(h.lines)-size:]
+	}
+	return h, s.Err()
+}
+
+// add adds a line to the history and writes the history to its file. Lines equal to the previous line are not
+// added again.
+func (h *history) add(line string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.lines) > 0 && h.lines[len(h.lines)-1] == line {
+		return nil
+	}
+	h.lines = append(h.lines, line)
+	if len(h.lines) > h.size {
+		h.lines = h.lines[len(h.lines)-h.size:]
+	}
+	if h.file == "" {
+		return nil
+	}
+	return os.WriteFile(h.file, []byte(strings.Join(h.lines, "\n")+"\n"), 0644)
+}
+
+// cursor returns the index directly after the last line in the history.
+func (h *history) cursor() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.lines)
+}
+
+// at returns the line at index i in the history. If no line exists at that index, false is returned.
+func (h *history) at(i int) (string, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if i < 0 || i >= len(h.lines) {
+		return "", false
+	}
+	return h.lines[i], true
+}
@@ -0,0 +1,22 @@
+package console
+
+import "golang.org/x/sys/unix"
+
+// makeRaw disables line buffering and echoing of input of the terminal with the file descriptor passed. Signals,
+// such as those sent by pressing Ctrl+C, are still handled by the terminal. A function that restores the previous
+// mode of the terminal is returned. If the file descriptor is not a terminal, makeRaw returns false.
+func makeRaw(fd int) (func(), bool) {
+	old, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		return nil, false
+	}
+	t := *old
+	t.Lflag &^= unix.ICANON | unix.ECHO
+	t.Cc[unix.VMIN], t.Cc[unix.VTIME] = 1, 0
+	if err := unix.IoctlSetTermios(fd, unix.TCSETS, &t); err != nil {
+		return nil, false
+	}
+	return func() {
+		_ = unix.IoctlSetTermios(fd, unix.TCSETS, old)
+	}, true
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package console
+
+// makeRaw is not supported on this platform, so the Console always reads input line by line.
+func makeRaw(int) (func(), bool) {
+	return nil, false
+}
@@ -0,0 +1,152 @@
+// Package crash implements recovery of panics in the subsystems of a server, such as world ticking and the handling
+// of sessions. Panics recovered are turned into a structured Report, which is written to disk and passed to a Handler,
+// so that a single failure does not bring down the entire server.
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/df-mc/atomic"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Report is a structured report of a panic that was recovered in a subsystem of the server.
+type Report struct {
+	// Time is the time at which the panic was recovered.
+	Time time.Time `json:"time"`
+	// Subsystem is the name of the subsystem in which the panic occurred, such as 'world tick' or 'session'.
+	Subsystem string `json:"subsystem"`
+	// Panic is the value passed to panic, formatted as a string.
+	Panic string `json:"panic"`
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack string `json:"stack"`
+	// Context holds additional information on the state of the subsystem at the time of the panic, such as the
+	// name of the world or player involved.
+	Context map[string]any `json:"context,omitempty"`
+	// File is the path of the file that the Report was written to. File is empty if the Report was not written to
+	// disk.
+	File string `json:"-"`
+}
+
+// Handler handles crashes recovered by a Reporter. It may be implemented to forward reports to alerting
+// integrations.
+type Handler interface {
+	// HandleCrash handles a panic recovered by a Reporter. The Report of the panic is passed. HandleCrash is called
+	// after the Report is written to disk.
+	HandleCrash(r Report)
+}
+
+// NopHandler implements the Handler interface but does not execute any code when a crash is handled.
+type NopHandler struct{}
+
+// Compile time check to make sure NopHandler implements Handler.
+var _ Handler = NopHandler{}
+
+// HandleCrash ...
+func (NopHandler) HandleCrash(Report) {}
+
+// Logger is a logger that a Reporter logs recovered panics to.
+type Logger interface {
+	Errorf(format string, a ...any)
+}
+
+// Reporter recovers panics in subsystems of a server, writing a Report of each panic to disk and passing it to its
+// Handler. The zero value of Reporter is ready for use, but does not write reports or log them. A nil *Reporter may be
+// used, in which case panics are not recovered at all.
+type Reporter struct {
+	// Dir is the directory that reports are written to. If empty, reports are not written to disk.
+	Dir string
+	// Log is the Logger that recovered panics are logged to. If nil, panics are not logged.
+	Log Logger
+
+	h atomic.Value[Handler]
+}
+
+// Handle changes the Handler of the Reporter. Passing nil resets it to NopHandler.
+func (r *Reporter) Handle(h Handler) {
+	if h == nil {
+		h = NopHandler{}
+	}
+	r.h.Store(h)
+}
+
+// Handler returns the Handler of the Reporter.
+func (r *Reporter) Handler() Handler {
+	if h := r.h.Load(); h != nil {
+		return h
+	}
+	return NopHandler{}
+}
+
+// Catch calls f, recovering any panic that occurs while doing so. If a panic is recovered, a Report is created for
+// the subsystem passed and Catch returns true. context is called to provide additional information for the Report and
+// may be nil. If the Reporter is nil, panics are not recovered and Catch always returns false.
+func (r *Reporter) Catch(subsystem string, context func() map[string]any, f func()) (crashed bool) {
+	if r == nil {
+		f()
+		return false
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			crashed = true
+			r.report(subsystem, v, debug.Stack(), context)
+		}
+	}()
+	f()
+	return false
+}
+
+// report creates a Report of a recovered panic, writes it to disk, logs it and passes it to the Handler.
+func (r *Reporter) report(subsystem string, v any, stack []byte, context func() map[string]any) {
+	rep := Report{Time: time.Now(), Subsystem: subsystem, Panic: fmt.Sprint(v), Stack: string(stack)}
+	if context != nil {
+		// The state of the subsystem may itself be broken, so we must be careful not to panic again.
+		func() {
+			defer func() {
+				if v := recover(); v != nil {
+					rep.Context = map[string]any{"context_error": fmt.Sprint(v)}
+				}
+			}()
+			rep.Context = context()
+		}()
+	}
+	if r.Dir != "" {
+		if err := rep.write(r.Dir); err != nil && r.Log != nil {
+			r.Log.Errorf("write crash report: %v", err)
+		}
+	}
+	if r.Log != nil {
+		if rep.File != "" {
+			r.Log.Errorf("Recovered panic in %v: %v (report written to %v)", subsystem, rep.Panic, rep.File)
+		} else {
+			r.Log.Errorf("Recovered panic in %v: %v\n%s", subsystem, rep.Panic, stack)
+		}
+	}
+	r.Handler().HandleCrash(rep)
+}
+
+// unsafeChars matches all characters that should not be used in the file name of a report.
+var unsafeChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// write writes the Report as JSON to a new file in the directory passed and sets the File field of the Report.
+func (rep *Report) write(dir string) error {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("crash-%v-%v.json", rep.Time.Format("2006-01-02_15.04.05.000"), strings.Trim(unsafeChars.ReplaceAllString(strings.ToLower(rep.Subsystem), "-"), "-"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	rep.File = path
+	return nil
+}
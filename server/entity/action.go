@@ -44,6 +44,13 @@ type PickedUpAction struct {
 // FireworkExplosionAction is a world.EntityAction that makes a Firework rocket display an explosion particle.
 type FireworkExplosionAction struct{ action }
 
+// TotemUseAction is a world.EntityAction that makes an entity display the animation of a totem being used, which
+// is shown when the totem saves the entity from death.
+type TotemUseAction struct{ action }
+
+// WakeUpAction is a world.EntityAction that makes a sleeping player wake up and leave its bed.
+type WakeUpAction struct{ action }
+
 // action implements the Action interface. Structures in this package may embed it to gets its functionality
 // out of the box.
 type action struct{}
@@ -0,0 +1,269 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// AnimalBehaviourConfig holds optional parameters for an AnimalBehaviour.
+type AnimalBehaviourConfig struct {
+	// Food checks if an item.Stack may be fed to the animal to make it breed or to make it grow up faster.
+	Food func(s item.Stack) bool
+	// Baby creates a baby of the animal at a position. Baby is called when two animals breed.
+	Baby func(pos mgl64.Vec3) *Mob
+}
+
+// New creates an AnimalBehaviour using the parameters in conf. If baby is true, the animal starts out as a baby.
+func (conf AnimalBehaviourConfig) New(baby bool) *AnimalBehaviour {
+	b := &AnimalBehaviour{conf: conf}
+	if baby {
+		b.growUp = babyTicks
+	}
+	return b
+}
+
+const (
+	// babyTicks is the amount of ticks that it takes for a baby animal to grow up.
+	babyTicks = 24000
+	// loveTicks is the amount of ticks that an animal remains in love after being fed.
+	loveTicks = 600
+	// breedCooldownTicks is the amount of ticks that an animal must wait after breeding before it can breed again.
+	breedCooldownTicks = 6000
+)
+
+// AnimalBehaviour implements the behaviour of animals. Animals may be fed food to make them fall in love, after
+// which two animals of the same type close to each other will produce a baby.
+type AnimalBehaviour struct {
+	conf AnimalBehaviourConfig
+
+	mu       sync.Mutex
+	love     int
+	cooldown int
+	growUp   int
+}
+
+// Tick counts down the love, breeding cooldown and growing up timers of the animal.
+func (b *AnimalBehaviour) Tick(m *Mob) {
+	b.mu.Lock()
+	wasBaby := b.growUp > 0
+	if b.love > 0 {
+		b.love--
+	}
+	if b.cooldown > 0 {
+		b.cooldown--
+	}
+	if b.growUp > 0 {
+		b.growUp--
+	}
+	grewUp := wasBaby && b.growUp == 0
+	b.mu.Unlock()
+
+	if grewUp {
+		m.updateState()
+	}
+}
+
+// Interact feeds the animal if the item.Stack held by the user is its food. Adult animals fall in love when fed, while
+// babies grow up faster.
+func (b *AnimalBehaviour) Interact(m *Mob, _ item.User, held item.Stack, ctx *item.UseContext) bool {
+	if held.Empty() || b.conf.Food == nil || !b.conf.Food(held) {
+		return false
+	}
+	b.mu.Lock()
+	switch {
+	case b.growUp > 0:
+		b.growUp -= b.growUp / 10
+	case b.love == 0 && b.cooldown == 0:
+		b.love = loveTicks
+	default:
+		b.mu.Unlock()
+		return false
+	}
+	b.mu.Unlock()
+
+	ctx.SubtractFromCount(1)
+	for _, v := range m.World().Viewers(m.Position()) {
+		v.ViewEntityAction(m, EatAction{})
+	}
+	m.updateState()
+	return true
+}
+
+// Baby checks if the animal is a baby.
+func (b *AnimalBehaviour) Baby() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.growUp > 0
+}
+
+// InLove checks if the animal is in love and is looking for a partner to breed with.
+func (b *AnimalBehaviour) InLove() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.love > 0
+}
+
+// Scale returns the scale of the animal, which is 0.5 for babies and 1 for adults.
+func (b *AnimalBehaviour) Scale() float64 {
+	if b.Baby() {
+		return 0.5
+	}
+	return 1
+}
+
+// Food checks if the item.Stack passed is food of the animal.
+func (b *AnimalBehaviour) Food(s item.Stack) bool {
+	return b.conf.Food != nil && !s.Empty() && b.conf.Food(s)
+}
+
+// animal returns the AnimalBehaviour itself. It allows types embedding an AnimalBehaviour to be used as one.
+func (b *AnimalBehaviour) animal() *AnimalBehaviour {
+	return b
+}
+
+// breed resets the love of the animal and starts its breeding cooldown.
+func (b *AnimalBehaviour) breed() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.love, b.cooldown = 0, breedCooldownTicks
+}
+
+// animalOf returns the AnimalBehaviour of the Mob passed, if it has one.
+func animalOf(m *Mob) (*AnimalBehaviour, bool) {
+	if a, ok := m.Behaviour().(interface{ animal() *AnimalBehaviour }); ok {
+		return a.animal(), true
+	}
+	return nil, false
+}
+
+// BreedGoal is a Goal that makes an animal in love walk towards another animal of the same type in love, after which
+// the two produce a baby.
+type BreedGoal struct {
+	// Speed is the multiplier of the speed of the Mob while walking to its partner. If 0, Speed is 1.
+	Speed float64
+}
+
+// Tick ...
+func (g *BreedGoal) Tick(m *Mob) bool {
+	b, ok := animalOf(m)
+	if !ok || !b.InLove() {
+		return false
+	}
+	pos := m.Position()
+	var (
+		partner *Mob
+		dist    = math.MaxFloat64
+	)
+	for _, e := range m.World().EntitiesWithin(cube.Box(pos[0]-8, pos[1]-8, pos[2]-8, pos[0]+8, pos[1]+8, pos[2]+8), nil) {
+		other, ok := e.(*Mob)
+		if !ok || other == m || other.Type() != m.Type() || other.Dead() {
+			continue
+		}
+		if ob, ok := animalOf(other); !ok || !ob.InLove() {
+			continue
+		}
+		if d := other.Position().Sub(pos).Len(); d < dist {
+			partner, dist = other, d
+		}
+	}
+	if partner == nil {
+		return false
+	}
+	m.LookAt(partner.Position())
+	if dist > 2 {
+		m.MoveTo(partner.Position(), speedOr(g.Speed, 1))
+		return true
+	}
+	m.StopMoving()
+	pb, _ := animalOf(partner)
+	b.breed()
+	pb.breed()
+
+	if b.conf.Baby == nil {
+		return true
+	}
+	w, babyPos := m.World(), pos.Add(partner.Position()).Mul(0.5)
+	w.AddEntity(b.conf.Baby(babyPos))
+	for _, orb := range NewExperienceOrbs(babyPos, w.Rand().Intn(7)+1) {
+		w.AddEntity(orb)
+	}
+	m.updateState()
+	partner.updateState()
+	return true
+}
+
+// animalBBox returns the bounding box of an animal with the width and height passed, scaled down if the animal is a
+// baby.
+func animalBBox(e world.Entity, width, height float64) cube.BBox {
+	if m, ok := e.(*Mob); ok {
+		if b, ok := animalOf(m); ok && b.Baby() {
+			width, height = width/2, height/2
+		}
+	}
+	return cube.Box(-width/2, 0, -width/2, width/2, height, width/2)
+}
+
+// animalDrops returns a function that returns the drops of an animal using the function passed, unless the animal is
+// a baby, in which case it drops nothing.
+func animalDrops(f func(m *Mob) []item.Stack) func(m *Mob) []item.Stack {
+	return func(m *Mob) []item.Stack {
+		if b, ok := animalOf(m); ok && b.Baby() {
+			return nil
+		}
+		return f(m)
+	}
+}
+
+// animalGoals returns the default goals of an animal that eats the food passed.
+func animalGoals(food func(s item.Stack) bool) []Goal {
+	return []Goal{&PanicGoal{}, &BreedGoal{}, &TemptGoal{Tempts: food}, &WanderGoal{}}
+}
+
+// decodeAnimalNBT decodes the properties shared by all animals from the map passed to the Mob passed.
+func decodeAnimalNBT(m *Mob, data map[string]any) {
+	decodeMobNBT(m, data)
+	if b, ok := animalOf(m); ok {
+		b.growUp = int(nbtconv.Int32(data, "GrowUpTicks"))
+	}
+}
+
+// encodeAnimalNBT encodes the properties shared by all animals of the Mob passed to a map.
+func encodeAnimalNBT(m *Mob) map[string]any {
+	data := encodeMobNBT(m)
+	if b, ok := animalOf(m); ok {
+		b.mu.Lock()
+		data["GrowUpTicks"] = int32(b.growUp)
+		b.mu.Unlock()
+	}
+	return data
+}
+
+// AnimalSpawnEntries returns world.SpawnEntry values for cows, pigs, sheep and chickens, which spawn in groups on
+// grass. The entries may be added to the spawn pools of a world.SpawnRules to make animals spawn naturally.
+func AnimalSpawnEntries() []world.SpawnEntry {
+	onGrass := func(pos cube.Pos, w *world.World) bool {
+		_, ok := w.Block(pos.Side(cube.FaceDown)).(block.Grass)
+		return ok
+	}
+	entry := func(weight int, f func(pos mgl64.Vec3, r *rand.Rand) *Mob) world.SpawnEntry {
+		return world.SpawnEntry{
+			Category:     world.SpawnCategoryPassive,
+			Weight:       weight,
+			MinGroupSize: 4,
+			MaxGroupSize: 4,
+			New:          func(pos mgl64.Vec3, w *world.World) world.Entity { return spawnMob(f(pos, w.Rand()), w.Rand()) },
+			Condition:    onGrass,
+		}
+	}
+	withoutRand := func(f func(pos mgl64.Vec3) *Mob) func(mgl64.Vec3, *rand.Rand) *Mob {
+		return func(pos mgl64.Vec3, _ *rand.Rand) *Mob { return f(pos) }
+	}
+	return []world.SpawnEntry{entry(12, NewSheep), entry(10, withoutRand(NewPig)), entry(10, withoutRand(NewChicken)), entry(8, withoutRand(NewCow))}
+}
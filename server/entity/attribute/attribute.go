@@ -0,0 +1,57 @@
+package attribute
+
+import (
+	"math"
+)
+
+// Attribute is a property of an entity, such as its maximum health or its movement speed. Every entity has a base
+// value for each of its attributes, which may be changed by Modifiers added by, for example, effects. Attributes
+// are comparable and may be used as map keys.
+type Attribute struct {
+	name          string
+	min, max, def float64
+}
+
+// New creates a new Attribute with the name passed. The value of the Attribute is always between min and max, and
+// the base value of the Attribute is def unless changed. The name of attributes that the client knows, such as
+// 'minecraft:luck', is used to sync the value of the Attribute with the client.
+func New(name string, min, max, def float64) Attribute {
+	return Attribute{name: name, min: min, max: max, def: def}
+}
+
+var (
+	// MaxHealth is the maximum health of an entity. Every point of health is half a heart.
+	MaxHealth = New("minecraft:health", 1, 1024, 20)
+	// MovementSpeed is the speed of an entity in blocks per tick.
+	MovementSpeed = New("minecraft:movement", 0, math.MaxFloat32, 0.1)
+	// AttackDamage is the damage dealt by an entity when attacking another entity without holding a weapon.
+	AttackDamage = New("minecraft:attack_damage", 0, 2048, 1)
+	// KnockBackResistance is the resistance of an entity to knock back. A value of 0 means normal knock back, while
+	// a value of 1 means that the entity is not knocked back at all.
+	KnockBackResistance = New("minecraft:knockback_resistance", 0, 1, 0)
+)
+
+// Name returns the name of the Attribute, such as 'minecraft:movement'.
+func (a Attribute) Name() string {
+	return a.name
+}
+
+// Min returns the minimum value of the Attribute.
+func (a Attribute) Min() float64 {
+	return a.min
+}
+
+// Max returns the maximum value of the Attribute.
+func (a Attribute) Max() float64 {
+	return a.max
+}
+
+// Default returns the default base value of the Attribute.
+func (a Attribute) Default() float64 {
+	return a.def
+}
+
+// clamp clamps the value passed between the minimum and maximum value of the Attribute.
+func (a Attribute) clamp(v float64) float64 {
+	return math.Max(a.min, math.Min(a.max, v))
+}
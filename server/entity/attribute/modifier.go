@@ -0,0 +1,52 @@
+package attribute
+
+// Operation is an operation that a Modifier performs on the value of an Attribute.
+type Operation uint8
+
+const (
+	// OperationAddition adds the amount of the Modifier to the base value of the Attribute.
+	OperationAddition Operation = iota
+	// OperationMultiplyBase adds the base value of the Attribute, after all additions, multiplied by the amount of
+	// the Modifier to the value of the Attribute. An amount of 0.5 therefore increases the value by half of the
+	// base value.
+	OperationMultiplyBase
+	// OperationMultiplyTotal multiplies the value of the Attribute, after all other operations, by one plus the
+	// amount of the Modifier. An amount of -0.15 therefore decreases the value by 15%.
+	OperationMultiplyTotal
+)
+
+// Modifier modifies the value of an Attribute. Modifiers are added by effects, such as Speed, but may also be added
+// by plugins.
+type Modifier struct {
+	// ID is the unique identifier of the Modifier, such as 'effect.speed'. Adding a Modifier with the same ID as a
+	// Modifier already present on an Attribute replaces the existing Modifier.
+	ID string
+	// Amount is the amount by which the Modifier changes the value of the Attribute. How it is applied depends on
+	// the Operation of the Modifier.
+	Amount float64
+	// Operation is the Operation that the Modifier performs on the value of the Attribute.
+	Operation Operation
+}
+
+// apply applies the Modifiers passed to the base value passed and returns the resulting value, clamped between the
+// minimum and maximum of the Attribute passed.
+func apply(a Attribute, base float64, modifiers []Modifier) float64 {
+	v := base
+	for _, m := range modifiers {
+		if m.Operation == OperationAddition {
+			v += m.Amount
+		}
+	}
+	b := v
+	for _, m := range modifiers {
+		if m.Operation == OperationMultiplyBase {
+			v += b * m.Amount
+		}
+	}
+	for _, m := range modifiers {
+		if m.Operation == OperationMultiplyTotal {
+			v *= 1 + m.Amount
+		}
+	}
+	return a.clamp(v)
+}
@@ -0,0 +1,138 @@
+package attribute
+
+import (
+	"sync"
+)
+
+// Set holds the base values and Modifiers of the attributes of an entity. Attributes that were never changed have
+// their default base value and no Modifiers. Set is safe for concurrent use. A Set may be created using NewSet.
+type Set struct {
+	mu         sync.Mutex
+	attributes map[Attribute]*instance
+	f          func(a Attribute, value float64)
+}
+
+// instance holds the base value and the Modifiers of an Attribute in a Set.
+type instance struct {
+	base      float64
+	modifiers []Modifier
+}
+
+// NewSet creates a new Set. The function passed is called every time the value of an Attribute in the Set changes,
+// for example to sync the value with a client. The function may be nil if nothing needs to be done.
+func NewSet(f func(a Attribute, value float64)) *Set {
+	if f == nil {
+		f = func(Attribute, float64) {}
+	}
+	return &Set{attributes: make(map[Attribute]*instance), f: f}
+}
+
+// Base returns the base value of the Attribute passed, which is its value without any Modifiers applied.
+func (s *Set) Base(a Attribute) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i, ok := s.attributes[a]; ok {
+		return i.base
+	}
+	return a.def
+}
+
+// SetBase sets the base value of the Attribute passed. The base value is clamped between the minimum and maximum
+// value of the Attribute.
+func (s *Set) SetBase(a Attribute, base float64) {
+	s.update(a, func(i *instance) {
+		i.base = a.clamp(base)
+	})
+}
+
+// Value returns the value of the Attribute passed, which is its base value with all of its Modifiers applied.
+func (s *Set) Value(a Attribute) float64 {
+	return s.ValueWith(a)
+}
+
+// ValueWith returns the value that the Attribute passed would have if the extra Modifiers passed were added to it.
+// The Modifiers are not actually added to the Attribute. ValueWith is useful for values that depend on the context,
+// such as the attack damage of an entity with the weapon that it is holding.
+func (s *Set) ValueWith(a Attribute, extra ...Modifier) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, ok := s.attributes[a]
+	if !ok {
+		return apply(a, a.def, extra)
+	}
+	if len(extra) == 0 {
+		return apply(a, i.base, i.modifiers)
+	}
+	return apply(a, i.base, append(append(make([]Modifier, 0, len(i.modifiers)+len(extra)), i.modifiers...), extra...))
+}
+
+// AddModifier adds a Modifier to the Attribute passed. If a Modifier with the same ID is already present on the
+// Attribute, it is replaced.
+func (s *Set) AddModifier(a Attribute, m Modifier) {
+	s.update(a, func(i *instance) {
+		for j, existing := range i.modifiers {
+			if existing.ID == m.ID {
+				i.modifiers[j] = m
+				return
+			}
+		}
+		i.modifiers = append(i.modifiers, m)
+	})
+}
+
+// RemoveModifier removes the Modifier with the ID passed from the Attribute passed. Nothing happens if the Attribute
+// has no Modifier with that ID.
+func (s *Set) RemoveModifier(a Attribute, id string) {
+	s.update(a, func(i *instance) {
+		for j, existing := range i.modifiers {
+			if existing.ID == id {
+				i.modifiers = append(i.modifiers[:j], i.modifiers[j+1:]...)
+				return
+			}
+		}
+	})
+}
+
+// Modifier returns the Modifier with the ID passed on the Attribute passed. If no such Modifier is present, false
+// is returned.
+func (s *Set) Modifier(a Attribute, id string) (Modifier, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i, ok := s.attributes[a]; ok {
+		for _, m := range i.modifiers {
+			if m.ID == id {
+				return m, true
+			}
+		}
+	}
+	return Modifier{}, false
+}
+
+// Modifiers returns all Modifiers currently present on the Attribute passed.
+func (s *Set) Modifiers(a Attribute) []Modifier {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i, ok := s.attributes[a]; ok {
+		return append([]Modifier(nil), i.modifiers...)
+	}
+	return nil
+}
+
+// update calls f with the instance of the Attribute passed, creating it if it did not yet exist, and calls the
+// function of the Set if the value of the Attribute changed as a result.
+func (s *Set) update(a Attribute, f func(i *instance)) {
+	s.mu.Lock()
+	i, ok := s.attributes[a]
+	if !ok {
+		i = &instance{base: a.def}
+		s.attributes[a] = i
+	}
+	before := apply(a, i.base, i.modifiers)
+	f(i)
+	after := apply(a, i.base, i.modifiers)
+	s.mu.Unlock()
+
+	if before != after {
+		s.f(a, after)
+	}
+}
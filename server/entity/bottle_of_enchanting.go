@@ -8,7 +8,6 @@ import (
 	"github.com/df-mc/dragonfly/server/world/particle"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
-	"math/rand"
 )
 
 // NewBottleOfEnchanting ...
@@ -28,8 +27,8 @@ var bottleOfEnchantingConf = ProjectileBehaviourConfig{
 // spawnExperience spawns experience orbs with a value of 3-11 at the target of
 // a trace.Result.
 func spawnExperience(e *Ent, target trace.Result) {
-	for _, orb := range NewExperienceOrbs(target.Position(), rand.Intn(9)+3) {
-		orb.SetVelocity(mgl64.Vec3{(rand.Float64()*0.2 - 0.1) * 2, rand.Float64() * 0.4, (rand.Float64()*0.2 - 0.1) * 2})
+	for _, orb := range NewExperienceOrbs(target.Position(), e.World().Rand().Intn(9)+3) {
+		orb.SetVelocity(mgl64.Vec3{(e.World().Rand().Float64()*0.2 - 0.1) * 2, e.World().Rand().Float64() * 0.4, (e.World().Rand().Float64()*0.2 - 0.1) * 2})
 		e.World().AddEntity(orb)
 	}
 }
@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
+)
+
+// NewChicken creates a new adult chicken at the position passed. Chickens lay eggs every 5 to 10 minutes, fall
+// slowly and are bred using seeds.
+func NewChicken(pos mgl64.Vec3) *Mob {
+	return newChicken(pos, false)
+}
+
+// newChicken creates a new chicken at the position passed. If baby is true, the chicken starts out as a baby.
+func newChicken(pos mgl64.Vec3, baby bool) *Mob {
+	return MobConfig{
+		MaxHealth: 4,
+		Speed:     0.1,
+		Goals:     animalGoals(chickenFood),
+		Behaviour: &chickenBehaviour{AnimalBehaviour: AnimalBehaviourConfig{
+			Food: chickenFood,
+			Baby: func(pos mgl64.Vec3) *Mob { return newChicken(pos, true) },
+		}.New(baby)},
+		Drops: animalDrops(func(m *Mob) []item.Stack {
+			drops := []item.Stack{item.NewStack(item.Chicken{Cooked: m.OnFireDuration() > 0}, 1)}
+			if n := m.World().Rand().Intn(3); n > 0 {
+				drops = append(drops, item.NewStack(item.Feather{}, n))
+			}
+			return drops
+		}),
+		MinExperience:    1,
+		MaxExperience:    3,
+		FallDamageImmune: true,
+	}.New(ChickenType{}, pos)
+}
+
+// chickenBehaviour implements the behaviour of chickens, which lay eggs and fall slowly in addition to being bred.
+type chickenBehaviour struct {
+	*AnimalBehaviour
+	// eggTicks is the amount of ticks until the chicken lays its next egg. If 0, the timer has not been started yet
+	// and is started the next time the chicken is ticked.
+	eggTicks int
+}
+
+// Tick slows down the fall of the chicken and lays an egg when its timer runs out.
+func (b *chickenBehaviour) Tick(m *Mob) {
+	b.AnimalBehaviour.Tick(m)
+	if vel := m.Velocity(); vel[1] < 0 && !m.OnGround() {
+		vel[1] *= 0.6
+		m.SetVelocity(vel)
+	}
+	if b.Baby() {
+		return
+	}
+	if b.eggTicks == 0 {
+		b.eggTicks = nextEggTicks(m.World().Rand())
+	}
+	if b.eggTicks--; b.eggTicks <= 0 {
+		b.eggTicks = nextEggTicks(m.World().Rand())
+		m.World().AddEntity(NewItem(item.NewStack(item.Egg{}, 1), m.Position()))
+	}
+}
+
+// nextEggTicks returns the amount of ticks until a chicken lays its next egg: A random duration between 5 and 10
+// minutes.
+func nextEggTicks(r *rand.Rand) int {
+	return 6000 + r.Intn(6000)
+}
+
+// chickenFood checks if an item.Stack is food of chickens.
+func chickenFood(s item.Stack) bool {
+	switch s.Item().(type) {
+	case block.WheatSeeds, block.BeetrootSeeds, block.MelonSeeds, block.PumpkinSeeds:
+		return true
+	}
+	return false
+}
+
+// ChickenType is a world.EntityType implementation for chickens.
+type ChickenType struct{}
+
+func (ChickenType) EncodeEntity() string { return "minecraft:chicken" }
+func (ChickenType) BBox(e world.Entity) cube.BBox {
+	return animalBBox(e, 0.4, 0.7)
+}
+
+func (ChickenType) DecodeNBT(m map[string]any) world.Entity {
+	chicken := NewChicken(nbtconv.Vec3(m, "Pos"))
+	decodeAnimalNBT(chicken, m)
+	if t := int(nbtconv.Int32(m, "EggTicks")); t > 0 {
+		chicken.Behaviour().(*chickenBehaviour).eggTicks = t
+	}
+	return chicken
+}
+
+func (ChickenType) EncodeNBT(e world.Entity) map[string]any {
+	chicken := e.(*Mob)
+	data := encodeAnimalNBT(chicken)
+	data["EggTicks"] = int32(chicken.Behaviour().(*chickenBehaviour).eggTicks)
+	return data
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewCow creates a new adult cow at the position passed. Cows may be milked using an empty bucket and are bred using
+// wheat.
+func NewCow(pos mgl64.Vec3) *Mob {
+	return newCow(pos, false)
+}
+
+// newCow creates a new cow at the position passed. If baby is true, the cow starts out as a baby.
+func newCow(pos mgl64.Vec3, baby bool) *Mob {
+	return MobConfig{
+		MaxHealth: 10,
+		Speed:     0.1,
+		Goals:     animalGoals(wheatFood),
+		Behaviour: &cowBehaviour{AnimalBehaviour: AnimalBehaviourConfig{
+			Food: wheatFood,
+			Baby: func(pos mgl64.Vec3) *Mob { return newCow(pos, true) },
+		}.New(baby)},
+		Drops: animalDrops(func(m *Mob) []item.Stack {
+			drops := []item.Stack{item.NewStack(item.Beef{Cooked: m.OnFireDuration() > 0}, m.World().Rand().Intn(3)+1)}
+			if n := m.World().Rand().Intn(3); n > 0 {
+				drops = append(drops, item.NewStack(item.Leather{}, n))
+			}
+			return drops
+		}),
+		MinExperience: 1,
+		MaxExperience: 3,
+	}.New(CowType{}, pos)
+}
+
+// cowBehaviour implements the behaviour of cows, which may be milked in addition to being bred.
+type cowBehaviour struct {
+	*AnimalBehaviour
+}
+
+// Interact milks the cow if the user holds an empty bucket, or feeds it otherwise.
+func (b *cowBehaviour) Interact(m *Mob, user item.User, held item.Stack, ctx *item.UseContext) bool {
+	if bucket, ok := held.Item().(item.Bucket); ok && bucket.Empty() && !b.Baby() {
+		ctx.SubtractFromCount(1)
+		ctx.NewItem = item.NewStack(item.Bucket{Content: item.MilkBucketContent()}, 1)
+		return true
+	}
+	return b.AnimalBehaviour.Interact(m, user, held, ctx)
+}
+
+// wheatFood checks if an item.Stack is wheat, the food of cows and sheep.
+func wheatFood(s item.Stack) bool {
+	_, ok := s.Item().(item.Wheat)
+	return ok
+}
+
+// CowType is a world.EntityType implementation for cows.
+type CowType struct{}
+
+func (CowType) EncodeEntity() string { return "minecraft:cow" }
+func (CowType) BBox(e world.Entity) cube.BBox {
+	return animalBBox(e, 0.9, 1.4)
+}
+
+func (CowType) DecodeNBT(m map[string]any) world.Entity {
+	cow := NewCow(nbtconv.Vec3(m, "Pos"))
+	decodeAnimalNBT(cow, m)
+	return cow
+}
+
+func (CowType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeAnimalNBT(e.(*Mob))
+}
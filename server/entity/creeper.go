@@ -0,0 +1,150 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/sound"
+	"github.com/go-gl/mathgl/mgl64"
+	"sync"
+)
+
+// NewCreeper creates a new creeper at the position passed. Creepers approach players and explode once they are
+// close enough. They may also be ignited using flint and steel.
+func NewCreeper(pos mgl64.Vec3) *Mob {
+	b := &CreeperBehaviour{}
+	return MobConfig{
+		MaxHealth: 20,
+		Speed:     0.125,
+		Goals:     []Goal{&TargetGoal{}, &creeperSwellGoal{b: b}, &WanderGoal{}},
+		Behaviour: b,
+		Drops: func(m *Mob) []item.Stack {
+			if n := m.World().Rand().Intn(3); n > 0 {
+				return []item.Stack{item.NewStack(item.Gunpowder{}, n)}
+			}
+			return nil
+		},
+		MinExperience: 5,
+		MaxExperience: 5,
+	}.New(CreeperType{}, pos)
+}
+
+// creeperFuseTicks is the amount of ticks between a creeper being ignited and it exploding.
+const creeperFuseTicks = 30
+
+// CreeperBehaviour implements the behaviour of creepers, which explode after being ignited.
+type CreeperBehaviour struct {
+	mu      sync.Mutex
+	ignited bool
+	// forced is true if the creeper was ignited using flint and steel, in which case it cannot be defused.
+	forced bool
+	fuse   int
+}
+
+// Ignited checks if the creeper is currently ignited and about to explode.
+func (b *CreeperBehaviour) Ignited() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.ignited
+}
+
+// Tick counts down the fuse of the creeper if it is ignited and makes it explode once the fuse runs out.
+func (b *CreeperBehaviour) Tick(m *Mob) {
+	b.mu.Lock()
+	if !b.ignited {
+		b.mu.Unlock()
+		return
+	}
+	b.fuse++
+	explode := b.fuse >= creeperFuseTicks
+	b.mu.Unlock()
+
+	if explode {
+		w, pos := m.World(), m.Position()
+		_ = m.Close()
+		block.ExplosionConfig{Size: 3, DisableBlockDamage: !w.BoolGameRule(world.GameRuleMobGriefing)}.Explode(w, pos)
+	}
+}
+
+// Interact ignites the creeper if the user holds flint and steel.
+func (b *CreeperBehaviour) Interact(m *Mob, _ item.User, held item.Stack, ctx *item.UseContext) bool {
+	if _, ok := held.Item().(item.FlintAndSteel); !ok || b.Ignited() {
+		return false
+	}
+	b.mu.Lock()
+	b.forced = true
+	b.mu.Unlock()
+
+	b.ignite(m, true)
+	ctx.DamageItem(1)
+	return true
+}
+
+// ignite ignites or defuses the creeper. A creeper ignited using flint and steel cannot be defused.
+func (b *CreeperBehaviour) ignite(m *Mob, ignited bool) {
+	b.mu.Lock()
+	if b.ignited == ignited || (!ignited && b.forced) {
+		b.mu.Unlock()
+		return
+	}
+	b.ignited = ignited
+	if !ignited {
+		b.fuse = 0
+	}
+	b.mu.Unlock()
+
+	if ignited {
+		m.World().PlaySound(m.Position(), sound.Ignite{})
+	}
+	m.updateState()
+}
+
+// creeperSwellGoal is a Goal that makes a creeper walk towards its target and ignite once it is close enough.
+type creeperSwellGoal struct {
+	b *CreeperBehaviour
+}
+
+// Tick ...
+func (g *creeperSwellGoal) Tick(m *Mob) bool {
+	target := m.Target()
+	if target == nil {
+		g.b.ignite(m, false)
+		return g.b.Ignited()
+	}
+	m.LookAt(EyePosition(target))
+	switch dist := target.Position().Sub(m.Position()).Len(); {
+	case dist < 3 && lineOfSight(m.World(), EyePosition(m), EyePosition(target)):
+		m.StopMoving()
+		g.b.ignite(m, true)
+	case dist > 7:
+		g.b.ignite(m, false)
+		fallthrough
+	default:
+		if g.b.Ignited() {
+			m.StopMoving()
+		} else {
+			m.MoveTo(target.Position(), 1)
+		}
+	}
+	return true
+}
+
+// CreeperType is a world.EntityType implementation for creepers.
+type CreeperType struct{}
+
+func (CreeperType) EncodeEntity() string { return "minecraft:creeper" }
+func (CreeperType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.3, 0, -0.3, 0.3, 1.7, 0.3)
+}
+
+func (CreeperType) DecodeNBT(m map[string]any) world.Entity {
+	creeper := NewCreeper(nbtconv.Vec3(m, "Pos"))
+	decodeMobNBT(creeper, m)
+	return creeper
+}
+
+func (CreeperType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeMobNBT(e.(*Mob))
+}
@@ -1,6 +1,7 @@
 package effect
 
 import (
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/world"
 	"image/color"
 	"time"
@@ -166,4 +167,22 @@ type living interface {
 	Speed() float64
 	// SetSpeed sets the speed of an entity to a new value.
 	SetSpeed(float64)
+	// Attributes returns the attribute.Set of the entity, to which lasting effects may add modifiers.
+	Attributes() *attribute.Set
+}
+
+// addModifier adds an attribute.Modifier with the ID, amount and operation passed to the attribute of the entity
+// passed, if it is a living entity.
+func addModifier(e world.Entity, a attribute.Attribute, id string, amount float64, op attribute.Operation) {
+	if l, ok := e.(living); ok {
+		l.Attributes().AddModifier(a, attribute.Modifier{ID: id, Amount: amount, Operation: op})
+	}
+}
+
+// removeModifier removes the attribute.Modifier with the ID passed from the attribute of the entity passed, if it
+// is a living entity.
+func removeModifier(e world.Entity, a attribute.Attribute, id string) {
+	if l, ok := e.(living); ok {
+		l.Attributes().RemoveModifier(a, id)
+	}
 }
@@ -1,6 +1,7 @@
 package effect
 
 import (
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/world"
 	"image/color"
 )
@@ -12,16 +13,12 @@ type HealthBoost struct {
 
 // Start ...
 func (HealthBoost) Start(e world.Entity, lvl int) {
-	if l, ok := e.(living); ok {
-		l.SetMaxHealth(l.MaxHealth() + 4*float64(lvl))
-	}
+	addModifier(e, attribute.MaxHealth, "effect.health_boost", 4*float64(lvl), attribute.OperationAddition)
 }
 
 // End ...
-func (HealthBoost) End(e world.Entity, lvl int) {
-	if l, ok := e.(living); ok {
-		l.SetMaxHealth(l.MaxHealth() - 4*float64(lvl))
-	}
+func (HealthBoost) End(e world.Entity, _ int) {
+	removeModifier(e, attribute.MaxHealth, "effect.health_boost")
 }
 
 // RGBA ...
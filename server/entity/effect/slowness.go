@@ -1,6 +1,7 @@
 package effect
 
 import (
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/world"
 	"image/color"
 )
@@ -13,24 +14,12 @@ type Slowness struct {
 
 // Start ...
 func (Slowness) Start(e world.Entity, lvl int) {
-	slowness := 1 - float64(lvl)*0.15
-	if slowness <= 0 {
-		slowness = 0.00001
-	}
-	if l, ok := e.(living); ok {
-		l.SetSpeed(l.Speed() * slowness)
-	}
+	addModifier(e, attribute.MovementSpeed, "effect.slowness", -0.15*float64(lvl), attribute.OperationMultiplyTotal)
 }
 
 // End ...
-func (Slowness) End(e world.Entity, lvl int) {
-	slowness := 1 - float64(lvl)*0.15
-	if slowness <= 0 {
-		slowness = 0.00001
-	}
-	if l, ok := e.(living); ok {
-		l.SetSpeed(l.Speed() / slowness)
-	}
+func (Slowness) End(e world.Entity, _ int) {
+	removeModifier(e, attribute.MovementSpeed, "effect.slowness")
 }
 
 // RGBA ...
@@ -1,6 +1,7 @@
 package effect
 
 import (
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/world"
 	"image/color"
 )
@@ -12,18 +13,12 @@ type Speed struct {
 
 // Start ...
 func (Speed) Start(e world.Entity, lvl int) {
-	speed := 1 + float64(lvl)*0.2
-	if l, ok := e.(living); ok {
-		l.SetSpeed(l.Speed() * speed)
-	}
+	addModifier(e, attribute.MovementSpeed, "effect.speed", 0.2*float64(lvl), attribute.OperationMultiplyTotal)
 }
 
 // End ...
-func (Speed) End(e world.Entity, lvl int) {
-	speed := 1 + float64(lvl)*0.2
-	if l, ok := e.(living); ok {
-		l.SetSpeed(l.Speed() / speed)
-	}
+func (Speed) End(e world.Entity, _ int) {
+	removeModifier(e, attribute.MovementSpeed, "effect.speed")
 }
 
 // RGBA ...
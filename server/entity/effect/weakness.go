@@ -1,6 +1,8 @@
 package effect
 
 import (
+	"github.com/df-mc/dragonfly/server/entity/attribute"
+	"github.com/df-mc/dragonfly/server/world"
 	"image/color"
 )
 
@@ -18,6 +20,16 @@ func (Weakness) Multiplier(lvl int) float64 {
 	return v
 }
 
+// Start ...
+func (w Weakness) Start(e world.Entity, lvl int) {
+	addModifier(e, attribute.AttackDamage, "effect.weakness", -w.Multiplier(lvl), attribute.OperationMultiplyTotal)
+}
+
+// End ...
+func (Weakness) End(e world.Entity, _ int) {
+	removeModifier(e, attribute.AttackDamage, "effect.weakness")
+}
+
 // RGBA ...
 func (Weakness) RGBA() color.RGBA {
 	return color.RGBA{R: 0x48, G: 0x4d, B: 0x48, A: 0xff}
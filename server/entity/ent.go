@@ -43,6 +43,8 @@ type Ent struct {
 
 	fireDuration time.Duration
 	age          time.Duration
+
+	metadata world.Metadata
 }
 
 // Explode propagates the explosion behaviour of the underlying Behaviour.
@@ -158,6 +160,12 @@ func (e *Ent) SetNameTag(s string) {
 	}
 }
 
+// Metadata returns the Metadata of the entity, which may be used to store
+// values with the entity. Persistent values are saved with the entity.
+func (e *Ent) Metadata() *world.Metadata {
+	return &e.metadata
+}
+
 // Tick ticks Ent, progressing its lifetime and closing the entity if it is
 // in the void.
 func (e *Ent) Tick(w *world.World, current int64) {
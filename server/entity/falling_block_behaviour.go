@@ -7,7 +7,6 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
 	"math"
-	"math/rand"
 )
 
 // FallingBlockBehaviourConfig holds optional parameters for
@@ -97,7 +96,7 @@ func (f *FallingBlockBehaviour) damageEntities(e *Ent, d damager, pos mgl64.Vec3
 	for _, e := range targets {
 		e.(Living).Hurt(dmg, src)
 	}
-	if b, ok := f.block.(breakable); ok && dmg > 0.0 && rand.Float64() < (dist+1)*0.05 {
+	if b, ok := f.block.(breakable); ok && dmg > 0.0 && w.Rand().Float64() < (dist+1)*0.05 {
 		f.block = b.Break()
 	}
 }
@@ -0,0 +1,144 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"time"
+)
+
+// WanderGoal is a Goal that makes a Mob walk to random positions around it every now and then.
+type WanderGoal struct {
+	// Chance is the chance, 1/Chance, that the Mob starts walking to a new position every tick that it is idle. If
+	// 0, Chance is 120.
+	Chance int
+	// Speed is the multiplier of the speed of the Mob while wandering. If 0, Speed is 1.
+	Speed float64
+
+	ticks int
+}
+
+// Tick ...
+func (g *WanderGoal) Tick(m *Mob) bool {
+	if g.ticks > 0 {
+		if g.ticks++; !m.Moving() || g.ticks > 200 {
+			g.ticks = 0
+			m.StopMoving()
+			return false
+		}
+		return true
+	}
+	chance := g.Chance
+	if chance <= 0 {
+		chance = 120
+	}
+	if m.World().Rand().Intn(chance) != 0 {
+		return false
+	}
+	target, ok := randomPositionAround(m, m.Position(), 10)
+	if !ok {
+		return false
+	}
+	m.MoveTo(target, speedOr(g.Speed, 1))
+	g.ticks = 1
+	return true
+}
+
+// PanicGoal is a Goal that makes a Mob run around in random directions after being hurt.
+type PanicGoal struct {
+	// Speed is the multiplier of the speed of the Mob while panicking. If 0, Speed is 1.5.
+	Speed float64
+}
+
+// Tick ...
+func (g *PanicGoal) Tick(m *Mob) bool {
+	if !m.RecentlyHurt(time.Second * 4) {
+		return false
+	}
+	if !m.Moving() {
+		centre := m.Position()
+		if attacker, _ := m.LastAttacker(); attacker != nil {
+			// Run away from the attacker rather than in a completely random direction.
+			if away := centre.Sub(attacker.Position()); away.Len() > 0 {
+				away[1] = 0
+				centre = centre.Add(away.Normalize().Mul(5))
+			}
+		}
+		if target, ok := randomPositionAround(m, centre, 5); ok {
+			m.MoveTo(target, speedOr(g.Speed, 1.5))
+		}
+	}
+	return true
+}
+
+// TemptGoal is a Goal that makes a Mob follow nearby entities holding an item that tempts it, such as wheat for
+// cows.
+type TemptGoal struct {
+	// Tempts checks if an item.Stack tempts the Mob.
+	Tempts func(s item.Stack) bool
+	// Speed is the multiplier of the speed of the Mob while following an entity. If 0, Speed is 1.2.
+	Speed float64
+}
+
+// Tick ...
+func (g *TemptGoal) Tick(m *Mob) bool {
+	pos := m.Position()
+	var (
+		nearest world.Entity
+		dist    = math.MaxFloat64
+	)
+	for _, e := range m.World().EntitiesWithin(cube.Box(pos[0]-10, pos[1]-10, pos[2]-10, pos[0]+10, pos[1]+10, pos[2]+10), nil) {
+		h, ok := e.(interface {
+			HeldItems() (mainHand, offHand item.Stack)
+		})
+		if !ok {
+			continue
+		}
+		main, off := h.HeldItems()
+		if !g.Tempts(main) && !g.Tempts(off) {
+			continue
+		}
+		if d := e.Position().Sub(pos).Len(); d < dist {
+			nearest, dist = e, d
+		}
+	}
+	if nearest == nil {
+		return false
+	}
+	m.LookAt(EyePosition(nearest))
+	if dist < 2.5 {
+		m.StopMoving()
+	} else {
+		m.MoveTo(nearest.Position(), speedOr(g.Speed, 1.2))
+	}
+	return true
+}
+
+// randomPositionAround returns a random position that a Mob could walk to within r blocks of the centre passed. If
+// no suitable position was found, false is returned.
+func randomPositionAround(m *Mob, centre mgl64.Vec3, r int) (mgl64.Vec3, bool) {
+	w := m.World()
+	for i := 0; i < 10; i++ {
+		x, z := int(math.Floor(centre[0]))+m.World().Rand().Intn(r*2+1)-r, int(math.Floor(centre[2]))+m.World().Rand().Intn(r*2+1)-r
+		y := w.HighestBlock(x, z) + 1
+		if math.Abs(float64(y)-centre[1]) > 4 {
+			// Too far up or down to walk to.
+			continue
+		}
+		if _, ok := w.Liquid(cube.Pos{x, y - 1, z}); ok {
+			continue
+		}
+		return mgl64.Vec3{float64(x) + 0.5, float64(y), float64(z) + 0.5}, true
+	}
+	return mgl64.Vec3{}, false
+}
+
+// speedOr returns speed if it is not 0, or def otherwise.
+func speedOr(speed, def float64) float64 {
+	if speed == 0 {
+		return def
+	}
+	return speed
+}
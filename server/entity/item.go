@@ -27,8 +27,11 @@ func NewItemPickupDelay(i item.Stack, pos mgl64.Vec3, delay time.Duration) *Ent
 }
 
 var itemConf = ItemBehaviourConfig{
-	Gravity: 0.04,
-	Drag:    0.02,
+	Gravity:   0.04,
+	Drag:      0.02,
+	Buoyancy:  0.045,
+	WaterDrag: 0.2,
+	LavaDrag:  0.5,
 }
 
 // ItemType is a world.EntityType implementation for Item.
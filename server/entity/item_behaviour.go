@@ -4,6 +4,7 @@ import (
 	"github.com/df-mc/dragonfly/server/internal/nbtconv"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
 	"math"
 	"time"
@@ -16,6 +17,12 @@ type ItemBehaviourConfig struct {
 	// Drag is used to reduce all axes of the velocity every tick. Velocity is
 	// multiplied with (1-Drag) every tick.
 	Drag float64
+	// Buoyancy is the amount of Y velocity added every tick while the item is
+	// in water. Items with a Buoyancy higher than their Gravity float.
+	Buoyancy float64
+	// WaterDrag and LavaDrag are used instead of Drag while the item is in
+	// water or lava respectively. If 0, Drag is used.
+	WaterDrag, LavaDrag float64
 	// ExistenceDuration specifies how long the item stack should last. The
 	// default is time.Minute * 5.
 	ExistenceDuration time.Duration
@@ -42,6 +49,9 @@ func (conf ItemBehaviourConfig) New(i item.Stack) *ItemBehaviour {
 	b.passive = PassiveBehaviourConfig{
 		Gravity:           conf.Gravity,
 		Drag:              conf.Drag,
+		Buoyancy:          conf.Buoyancy,
+		WaterDrag:         conf.WaterDrag,
+		LavaDrag:          conf.LavaDrag,
 		ExistenceDuration: conf.ExistenceDuration,
 		Tick:              b.tick,
 	}.New()
@@ -69,19 +79,25 @@ func (i *ItemBehaviour) Tick(e *Ent) *Movement {
 }
 
 // tick checks if the item can be picked up or merged with nearby item stacks.
+// Items may be merged even if their pickup delay has not yet expired. Items
+// in lava burn up.
 func (i *ItemBehaviour) tick(e *Ent) {
-	if i.pickupDelay == 0 {
-		i.checkNearby(e)
-	} else if i.pickupDelay < math.MaxInt16*(time.Second/20) {
+	if i.passive.mc.InLava() {
+		e.World().PlaySound(e.Position(), sound.Fizz{})
+		_ = e.Close()
+		return
+	}
+	i.checkNearby(e, i.pickupDelay == 0)
+	if i.pickupDelay != 0 && i.pickupDelay < math.MaxInt16*(time.Second/20) {
 		i.pickupDelay -= time.Second / 20
 	}
 }
 
 // checkNearby checks the nearby entities for item collectors and other item
-// stacks. If a collector is found in range, the item will be picked up. If
-// another item stack with the same item type is found in range, the item
-// stacks will merge.
-func (i *ItemBehaviour) checkNearby(e *Ent) {
+// stacks. If a collector is found in range and collect is true, the item will
+// be picked up. If another item stack with the same item type is found in
+// range, the item stacks will merge.
+func (i *ItemBehaviour) checkNearby(e *Ent, collect bool) {
 	w, pos := e.World(), e.Position()
 	bbox := e.Type().BBox(e)
 	grown := bbox.GrowVec3(mgl64.Vec3{1, 0.5, 1}).Translate(pos)
@@ -93,6 +109,9 @@ func (i *ItemBehaviour) checkNearby(e *Ent) {
 			continue
 		}
 		if collector, ok := other.(Collector); ok {
+			if !collect {
+				continue
+			}
 			// A collector was within range to pick up the entity.
 			i.collect(e, collector)
 			return
@@ -116,12 +135,18 @@ func (i *ItemBehaviour) merge(e *Ent, other *Ent) bool {
 	}
 	a, b := otherBehaviour.i.AddStack(i.i)
 
-	newA := NewItem(a, other.Position())
+	// Keep the longest pickup delay of the two stacks, so that merging can't be
+	// used to pick up items earlier than intended.
+	delay := otherBehaviour.pickupDelay
+	if i.pickupDelay > delay {
+		delay = i.pickupDelay
+	}
+	newA := NewItemPickupDelay(a, other.Position(), delay)
 	newA.SetVelocity(other.Velocity())
 	w.AddEntity(newA)
 
 	if !b.Empty() {
-		newB := NewItem(b, pos)
+		newB := NewItemPickupDelay(b, pos, i.pickupDelay)
 		newB.SetVelocity(e.Velocity())
 		w.AddEntity(newB)
 	}
@@ -5,7 +5,6 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
-	"math/rand"
 	"time"
 )
 
@@ -25,7 +24,7 @@ func NewLightningWithDamage(pos mgl64.Vec3, dmg float64, blockFire bool, entityF
 		EntityFireDuration: entityFireDuration,
 		BlockFire:          blockFire,
 		state:              2,
-		lifetime:           rand.Intn(4) + 1,
+		lifetime:           -1,
 	}
 	conf := lightningConf
 	conf.Tick = state.tick
@@ -46,11 +45,15 @@ type lightningState struct {
 // on fire when appropriate.
 func (s *lightningState) tick(e *Ent) {
 	w, pos := e.World(), e.Position()
+	if s.lifetime < 0 {
+		// The lifetime is only known once the lightning is in a world, so that it can use its random source.
+		s.lifetime = w.Rand().Intn(4) + 1
+	}
 
 	if s.state--; s.state < 0 {
 		if s.lifetime == 0 {
 			_ = e.Close()
-		} else if s.state < -rand.Intn(10) {
+		} else if s.state < -w.Rand().Intn(10) {
 			s.lifetime--
 			s.state = 1
 
@@ -87,7 +90,7 @@ func (s *lightningState) dealDamage(e *Ent) {
 func (s *lightningState) spreadFire(w *world.World, pos cube.Pos) {
 	s.fire().Start(w, pos)
 	for i := 0; i < 4; i++ {
-		pos.Add(cube.Pos{rand.Intn(3) - 1, rand.Intn(3) - 1, rand.Intn(3) - 1})
+		pos.Add(cube.Pos{w.Rand().Intn(3) - 1, w.Rand().Intn(3) - 1, w.Rand().Intn(3) - 1})
 		s.fire().Start(w, pos)
 	}
 }
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+const (
+	// waterFlowSpeed is the velocity per tick that flowing water pushes entities with.
+	waterFlowSpeed = 0.014
+	// lavaFlowSpeed and netherLavaFlowSpeed are the velocities per tick that flowing lava pushes entities with in
+	// the Overworld and in the Nether respectively.
+	lavaFlowSpeed, netherLavaFlowSpeed = 0.0023, 0.007
+	// waterSwimSpeed and lavaSwimSpeed are the multipliers applied to the speed of mobs moving through water and lava.
+	waterSwimSpeed, lavaSwimSpeed = 0.4, 0.2
+)
+
+// drag returns the drag applied to the entity, taking into account the liquid that it is in.
+func (c *MovementComputer) drag() float64 {
+	switch {
+	case c.InWater() && c.WaterDrag != 0:
+		return c.WaterDrag
+	case c.InLava() && c.LavaDrag != 0:
+		return c.LavaDrag
+	}
+	return c.Drag
+}
+
+// swimSpeed returns the multiplier applied to the speed of a mob moving by itself, taking into account the liquid
+// that it is in.
+func (c *MovementComputer) swimSpeed() float64 {
+	switch {
+	case c.InWater():
+		return waterSwimSpeed
+	case c.InLava():
+		return lavaSwimSpeed
+	}
+	return 1
+}
+
+// applyLiquidForces applies the buoyancy of water and the current of flowing liquids to the velocity of an entity
+// at the position passed.
+func (c *MovementComputer) applyLiquidForces(w *world.World, pos, vel mgl64.Vec3) mgl64.Vec3 {
+	if c.liquid == nil {
+		return vel
+	}
+	speed := waterFlowSpeed
+	if c.InWater() {
+		vel[1] += c.Buoyancy
+	} else if speed = lavaFlowSpeed; w.Dimension() == world.Nether {
+		speed = netherLavaFlowSpeed
+	}
+	return vel.Add(liquidFlow(w, cube.PosFromVec3(pos), c.liquid).Mul(speed))
+}
+
+// liquidFlow returns the normalised direction in which the liquid passed at a position flows. The liquid flows
+// towards neighbouring liquid of the same type with a lower depth, towards air next to it that it may flow down into,
+// and down if it is falling. If the liquid does not flow, an empty vector is returned.
+func liquidFlow(w *world.World, pos cube.Pos, l world.Liquid) mgl64.Vec3 {
+	var flow mgl64.Vec3
+	depth := l.LiquidDepth()
+	for _, face := range cube.HorizontalFaces() {
+		side := pos.Side(face)
+		dir := cube.Pos{}.Side(face).Vec3()
+		if other, ok := w.Liquid(side); ok {
+			if other.LiquidType() == l.LiquidType() {
+				flow = flow.Add(dir.Mul(float64(depth - other.LiquidDepth())))
+			}
+			continue
+		}
+		if len(w.Block(side).Model().BBox(side, w)) != 0 {
+			continue
+		}
+		if below, ok := w.Liquid(side.Side(cube.FaceDown)); ok && below.LiquidType() == l.LiquidType() {
+			// The liquid may flow down next to it, so it flows towards the side more strongly.
+			flow = flow.Add(dir.Mul(float64(depth - (below.LiquidDepth() - 8))))
+		}
+	}
+	if l.LiquidFalling() {
+		flow[1] -= 6
+	}
+	if flow.ApproxEqual(mgl64.Vec3{}) {
+		return mgl64.Vec3{}
+	}
+	return flow.Normalize()
+}
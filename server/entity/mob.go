@@ -0,0 +1,667 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity/attribute"
+	"github.com/df-mc/dragonfly/server/entity/effect"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// MobBehaviour implements behaviour specific to a type of Mob, such as breeding for animals. A MobBehaviour may
+// additionally implement an `Interact(m *Mob, user item.User, held item.Stack, ctx *item.UseContext) bool` method
+// to handle users interacting with the Mob.
+type MobBehaviour interface {
+	// Tick is called every tick that the Mob is alive, before its goals are ticked.
+	Tick(m *Mob)
+}
+
+// Goal is a goal that a Mob may pursue, such as wandering around or following a player holding food.
+type Goal interface {
+	// Tick ticks the Goal for the Mob passed. Tick returns true if the Goal is currently being pursued, in which case
+	// goals with a lower priority are not ticked.
+	Tick(m *Mob) bool
+}
+
+// MobConfig holds the settings of a Mob. Calling MobConfig.New() creates a Mob.
+type MobConfig struct {
+	// MaxHealth is the maximum health of the Mob. The Mob is created with this amount of health.
+	MaxHealth float64
+	// Speed is the movement speed of the Mob in blocks per tick.
+	Speed float64
+	// Goals are the goals of the Mob, in order of priority. Every tick, goals are ticked in this order until one of
+	// them returns true.
+	Goals []Goal
+	// Behaviour is the MobBehaviour of the Mob. Behaviour may be nil.
+	Behaviour MobBehaviour
+	// Drops returns the items that the Mob drops when it dies. Drops may be nil.
+	Drops func(m *Mob) []item.Stack
+	// MinExperience and MaxExperience are the minimum and maximum amount of experience dropped by the Mob when it is
+	// killed after being attacked.
+	MinExperience, MaxExperience int
+	// FallDamageImmune specifies if the Mob does not take damage from falling.
+	FallDamageImmune bool
+	// MainHand is the item held in the main hand of the Mob, such as a bow for skeletons.
+	MainHand item.Stack
+}
+
+// New creates a Mob of the world.EntityType passed at a position using the settings in the MobConfig.
+func (conf MobConfig) New(t world.EntityType, pos mgl64.Vec3) *Mob {
+	if conf.MaxHealth <= 0 {
+		conf.MaxHealth = 10
+	}
+	m := &Mob{
+		t:         t,
+		conf:      conf,
+		pos:       pos,
+		health:    NewHealthManager(conf.MaxHealth, conf.MaxHealth),
+		effects:   NewEffectManager(),
+		scheduler: NewScheduler(),
+		mc:        &MovementComputer{Gravity: 0.08, Drag: 0.02, WaterDrag: 0.2, LavaDrag: 0.5},
+	}
+	m.attributes = attribute.NewSet(m.updateAttribute)
+	m.attributes.SetBase(attribute.MaxHealth, conf.MaxHealth)
+	m.attributes.SetBase(attribute.MovementSpeed, conf.Speed)
+	return m
+}
+
+// spawnMob gives a naturally spawned Mob a random yaw using the rand.Rand passed and returns it.
+func spawnMob(m *Mob, r *rand.Rand) *Mob {
+	m.rot = cube.Rotation{r.Float64()*360 - 180, 0}
+	return m
+}
+
+// Mob is a Living entity that is controlled by the server through a set of goals, such as animals and monsters.
+type Mob struct {
+	t    world.EntityType
+	conf MobConfig
+
+	mu           sync.Mutex
+	pos, vel     mgl64.Vec3
+	rot          cube.Rotation
+	name         string
+	age          time.Duration
+	fireDuration time.Duration
+	fallDistance float64
+
+	immunity   int
+	dead       bool
+	deathTicks int
+
+	hurt     bool
+	attacker world.Entity
+	lastHurt time.Duration
+
+	navigating bool
+	target     mgl64.Vec3
+	navSpeed   float64
+	victim     world.Entity
+
+	mc         *MovementComputer
+	health     *HealthManager
+	attributes *attribute.Set
+	effects    *EffectManager
+	scheduler  *Scheduler
+	metadata   world.Metadata
+}
+
+// Type returns the world.EntityType passed to MobConfig.New.
+func (m *Mob) Type() world.EntityType {
+	return m.t
+}
+
+// Behaviour returns the MobBehaviour of the Mob, as set in the MobConfig. Nil is returned if no MobBehaviour was set.
+func (m *Mob) Behaviour() MobBehaviour {
+	return m.conf.Behaviour
+}
+
+// Position returns the current position of the Mob.
+func (m *Mob) Position() mgl64.Vec3 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pos
+}
+
+// EyeHeight returns the height of the eyes of the Mob relative to its position.
+func (m *Mob) EyeHeight() float64 {
+	return m.t.BBox(m).Height() * 0.85
+}
+
+// Velocity returns the current velocity of the Mob.
+func (m *Mob) Velocity() mgl64.Vec3 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.vel
+}
+
+// SetVelocity sets the velocity of the Mob.
+func (m *Mob) SetVelocity(v mgl64.Vec3) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.vel = v
+}
+
+// Rotation returns the rotation of the Mob.
+func (m *Mob) Rotation() cube.Rotation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rot
+}
+
+// World returns the world that the Mob is in.
+func (m *Mob) World() *world.World {
+	w, _ := world.OfEntity(m)
+	return w
+}
+
+// Age returns the total time lived of the Mob.
+func (m *Mob) Age() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.age
+}
+
+// OnGround checks if the Mob is currently standing on the ground.
+func (m *Mob) OnGround() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mc.OnGround()
+}
+
+// OnFireDuration ...
+func (m *Mob) OnFireDuration() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.fireDuration
+}
+
+// SetOnFire ...
+func (m *Mob) SetOnFire(duration time.Duration) {
+	if duration < 0 {
+		duration = 0
+	}
+	m.mu.Lock()
+	before, after := m.fireDuration > 0, duration > 0
+	m.fireDuration = duration
+	m.mu.Unlock()
+
+	if before != after {
+		m.updateState()
+	}
+}
+
+// Extinguish ...
+func (m *Mob) Extinguish() {
+	m.SetOnFire(0)
+}
+
+// NameTag returns the name tag of the Mob. An empty string is returned if no name tag was set.
+func (m *Mob) NameTag() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.name
+}
+
+// SetNameTag changes the name tag of the Mob. The name tag is removed if an empty string is passed.
+func (m *Mob) SetNameTag(s string) {
+	m.mu.Lock()
+	m.name = s
+	m.mu.Unlock()
+	m.updateState()
+}
+
+// Metadata returns the Metadata of the Mob, which may be used to store values with the Mob. Persistent values are
+// saved with the Mob.
+func (m *Mob) Metadata() *world.Metadata {
+	return &m.metadata
+}
+
+// Health returns the current health of the Mob.
+func (m *Mob) Health() float64 {
+	return m.health.Health()
+}
+
+// MaxHealth returns the maximum health of the Mob.
+func (m *Mob) MaxHealth() float64 {
+	return m.health.MaxHealth()
+}
+
+// SetMaxHealth changes the base value of the maximum health of the Mob.
+func (m *Mob) SetMaxHealth(v float64) {
+	m.attributes.SetBase(attribute.MaxHealth, v)
+}
+
+// Attributes returns the attribute.Set of the Mob, which holds attributes such as its maximum health and movement
+// speed.
+func (m *Mob) Attributes() *attribute.Set {
+	return m.attributes
+}
+
+// updateAttribute is called when the value of an attribute of the Mob changes.
+func (m *Mob) updateAttribute(a attribute.Attribute, value float64) {
+	if a == attribute.MaxHealth {
+		m.health.SetMaxHealth(value)
+	}
+}
+
+// Dead checks if the Mob is dead.
+func (m *Mob) Dead() bool {
+	return m.health.Health() <= mgl64.Epsilon
+}
+
+// AttackImmune checks if the Mob is currently immune to attacks because it was attacked recently.
+func (m *Mob) AttackImmune() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.immunity > 0
+}
+
+// Hurt hurts the Mob for a given amount of damage. If the Mob's health drops to 0, it is killed and drops its
+// items and experience.
+func (m *Mob) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
+	if _, ok := m.effects.Effect(effect.FireResistance{}); (ok && src.Fire()) || m.Dead() {
+		return 0, false
+	}
+	w := m.World()
+	ctx := event.C()
+	if w.Events().HandleEntityHurt(ctx, m, &dmg, src); ctx.Cancelled() {
+		return 0, false
+	}
+	if dmg < 0 {
+		return 0, true
+	}
+	if res, ok := m.effects.Effect(effect.Resistance{}); ok {
+		dmg *= effect.Resistance{}.Multiplier(src, res.Level())
+	}
+	m.health.AddHealth(-dmg)
+
+	for _, v := range w.Viewers(m.Position()) {
+		v.ViewEntityAction(m, HurtAction{})
+	}
+	m.mu.Lock()
+	m.immunity, m.lastHurt, m.hurt = 10, m.age, true
+	if s, ok := src.(AttackDamageSource); ok {
+		m.attacker = s.Attacker
+	} else if s, ok := src.(ProjectileDamageSource); ok && s.Owner != nil {
+		m.attacker = s.Owner
+	}
+	m.mu.Unlock()
+
+	if m.Dead() {
+		m.kill()
+	}
+	return dmg, true
+}
+
+// LastAttacker returns the entity that last attacked the Mob and the time passed since the Mob was last hurt. If the
+// Mob was never attacked by an entity, the world.Entity returned is nil.
+func (m *Mob) LastAttacker() (world.Entity, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.attacker, m.age - m.lastHurt
+}
+
+// RecentlyHurt checks if the Mob was hurt within the time.Duration passed.
+func (m *Mob) RecentlyHurt(d time.Duration) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.hurt && m.age-m.lastHurt < d
+}
+
+// kill kills the Mob, dropping its items and experience. The Mob is removed from the world after its death
+// animation.
+func (m *Mob) kill() {
+	m.scheduler.Clear()
+	w, pos := m.World(), m.Position()
+	for _, v := range w.Viewers(pos) {
+		v.ViewEntityAction(m, DeathAction{})
+	}
+	m.mu.Lock()
+	m.dead, m.navigating, m.victim = true, false, nil
+	attacker, recent := m.attacker, m.hurt && m.age-m.lastHurt < time.Second*5
+	m.mu.Unlock()
+
+	if m.conf.Drops != nil {
+		for _, s := range m.conf.Drops(m) {
+			it := NewItem(s, pos)
+			it.SetVelocity(mgl64.Vec3{w.Rand().Float64()*0.2 - 0.1, 0.2, w.Rand().Float64()*0.2 - 0.1})
+			w.AddEntity(it)
+		}
+	}
+	if attacker != nil && recent && m.conf.MaxExperience > 0 {
+		amount := m.conf.MinExperience + w.Rand().Intn(m.conf.MaxExperience-m.conf.MinExperience+1)
+		for _, orb := range NewExperienceOrbs(pos, amount) {
+			w.AddEntity(orb)
+		}
+	}
+}
+
+// Heal heals the Mob for a given amount of health.
+func (m *Mob) Heal(health float64, _ world.HealingSource) {
+	if health < 0 || m.Dead() {
+		return
+	}
+	m.health.AddHealth(health)
+}
+
+// KnockBack knocks the Mob back with a given force and height, away from the source position passed.
+func (m *Mob) KnockBack(src mgl64.Vec3, force, height float64) {
+	if m.Dead() {
+		return
+	}
+	velocity := m.Position().Sub(src)
+	velocity[1] = 0
+	if velocity.Len() != 0 {
+		velocity = velocity.Normalize().Mul(force)
+	}
+	velocity[1] = height
+	m.SetVelocity(velocity.Mul(1 - m.attributes.Value(attribute.KnockBackResistance)))
+}
+
+// Explode ...
+func (m *Mob) Explode(explosionPos mgl64.Vec3, impact float64, c block.ExplosionConfig) {
+	diff := m.Position().Sub(explosionPos)
+	m.Hurt(math.Floor((impact*impact+impact)*3.5*c.Size+1), ExplosionDamageSource{})
+	m.KnockBack(explosionPos, impact, diff[1]/diff.Len()*impact)
+}
+
+// AddEffect adds an effect.Effect to the Mob.
+func (m *Mob) AddEffect(e effect.Effect) {
+	m.effects.Add(e, m)
+	m.updateState()
+}
+
+// RemoveEffect removes any effect of the effect.Type passed from the Mob.
+func (m *Mob) RemoveEffect(e effect.Type) {
+	m.effects.Remove(e, m)
+	m.updateState()
+}
+
+// Effects returns all effects currently applied to the Mob.
+func (m *Mob) Effects() []effect.Effect {
+	return m.effects.Effects()
+}
+
+// Scheduler returns the Scheduler of the Mob, which may be used to run Tasks such as damage over time on the Mob.
+// All Tasks are cancelled when the Mob dies.
+func (m *Mob) Scheduler() *Scheduler {
+	return m.scheduler
+}
+
+// Speed returns the movement speed of the Mob in blocks per tick.
+func (m *Mob) Speed() float64 {
+	return m.attributes.Value(attribute.MovementSpeed)
+}
+
+// SetSpeed sets the base movement speed of the Mob in blocks per tick.
+func (m *Mob) SetSpeed(v float64) {
+	m.attributes.SetBase(attribute.MovementSpeed, v)
+}
+
+// MoveTo makes the Mob walk towards the position passed at its speed multiplied by the multiplier passed. The Mob
+// jumps when a block is in its way. The Mob stops once it reaches the position or when StopMoving is called.
+func (m *Mob) MoveTo(pos mgl64.Vec3, multiplier float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.navigating, m.target, m.navSpeed = true, pos, multiplier
+}
+
+// StopMoving stops the Mob from walking towards the position passed to MoveTo.
+func (m *Mob) StopMoving() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.navigating = false
+}
+
+// Moving checks if the Mob is currently walking towards a position passed to MoveTo.
+func (m *Mob) Moving() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.navigating
+}
+
+// Teleport teleports the Mob to the position passed and changes its rotation, resetting its velocity and fall
+// distance. Teleport does not stop the Mob from walking towards a position passed to MoveTo.
+func (m *Mob) Teleport(pos mgl64.Vec3, rot cube.Rotation) {
+	m.mu.Lock()
+	m.pos, m.vel, m.rot, m.fallDistance = pos, mgl64.Vec3{}, rot, 0
+	m.mu.Unlock()
+	for _, v := range m.World().Viewers(pos) {
+		v.ViewEntityTeleport(m, pos)
+	}
+}
+
+// LookAt rotates the Mob so that it faces the position passed.
+func (m *Mob) LookAt(pos mgl64.Vec3) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rot = rotationTowards(m.pos, pos)
+}
+
+// Target returns the entity that the Mob is currently targeting, such as a player that a zombie is chasing. Nil is
+// returned if the Mob has no target.
+func (m *Mob) Target() world.Entity {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.victim
+}
+
+// SetTarget changes the entity that the Mob is targeting. Passing nil clears the target of the Mob.
+func (m *Mob) SetTarget(e world.Entity) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.victim = e
+}
+
+// HeldItems returns the item held in the main hand of the Mob, as set in the MobConfig. Mobs never hold an item in
+// their off-hand.
+func (m *Mob) HeldItems() (mainHand, offHand item.Stack) {
+	return m.conf.MainHand, item.Stack{}
+}
+
+// swingArm makes the Mob swing its arm for all viewers of the Mob.
+func (m *Mob) swingArm() {
+	for _, v := range m.World().Viewers(m.Position()) {
+		v.ViewEntityAction(m, SwingArmAction{})
+	}
+}
+
+// Interact handles a user interacting with the Mob while holding the item.Stack passed. If the MobBehaviour of the
+// Mob handles the interaction, true is returned.
+func (m *Mob) Interact(user item.User, held item.Stack, ctx *item.UseContext) bool {
+	if in, ok := m.conf.Behaviour.(interface {
+		Interact(m *Mob, user item.User, held item.Stack, ctx *item.UseContext) bool
+	}); ok && !m.Dead() {
+		return in.Interact(m, user, held, ctx)
+	}
+	return false
+}
+
+// Tick ticks the Mob, moving it and pursuing its goals.
+func (m *Mob) Tick(w *world.World, current int64) {
+	m.mu.Lock()
+	if m.dead {
+		m.deathTicks++
+		done := m.deathTicks >= 20
+		m.mu.Unlock()
+		if done {
+			_ = m.Close()
+		}
+		return
+	}
+	y := m.pos[1]
+	if m.immunity > 0 {
+		m.immunity--
+	}
+	m.age += time.Second / 20
+	m.mu.Unlock()
+
+	if y < float64(w.Range()[0]) && current%10 == 0 {
+		m.Hurt(4, VoidDamageSource{})
+		if m.Dead() {
+			return
+		}
+	}
+	m.effects.Tick(m)
+	m.scheduler.Tick(m)
+	if d := m.OnFireDuration(); d > 0 {
+		m.SetOnFire(d - time.Second/20)
+		if w.RainingAt(cube.PosFromVec3(m.Position())) {
+			m.Extinguish()
+		} else if d%time.Second == 0 && !m.AttackImmune() {
+			m.Hurt(1, block.FireDamageSource{})
+		}
+	}
+	m.checkEntityInsiders(w)
+	if m.Dead() {
+		return
+	}
+
+	if m.conf.Behaviour != nil {
+		if m.conf.Behaviour.Tick(m); m.World() == nil {
+			// The Mob was removed by its behaviour, for example because a creeper exploded.
+			return
+		}
+	}
+	for _, g := range m.conf.Goals {
+		if g.Tick(m) {
+			break
+		}
+	}
+	if mv := m.tickMovement(w); mv != nil {
+		mv.Send()
+	}
+}
+
+// tickMovement moves the Mob according to its velocity and the position it is walking towards, if any.
+func (m *Mob) tickMovement(w *world.World) *Movement {
+	_, inWater := w.Liquid(cube.PosFromVec3(m.Position()))
+	speed := m.Speed()
+
+	m.mu.Lock()
+	var wanted mgl64.Vec3
+	if m.navigating {
+		diff := m.target.Sub(m.pos)
+		diff[1] = 0
+		if diff.Len() < 0.5 {
+			m.navigating = false
+		} else {
+			wanted = diff.Normalize().Mul(speed * m.navSpeed * m.mc.swimSpeed())
+			if m.mc.OnGround() || inWater {
+				m.vel[0], m.vel[2] = wanted[0]/0.6, wanted[2]/0.6
+			}
+			m.rot = rotationTowards(m.pos, m.target)
+		}
+	}
+	if inWater {
+		// Mobs swim upwards to keep their head above water.
+		m.vel[1] = math.Max(m.vel[1], 0.04)
+	}
+	mv := m.mc.TickMovement(m, m.pos, m.vel, m.rot)
+	m.pos, m.vel = mv.pos, mv.vel
+	if m.navigating && m.mc.OnGround() && ((wanted[0] != 0 && mv.vel[0] == 0) || (wanted[2] != 0 && mv.vel[2] == 0)) {
+		// Something is blocking the mob's way, so we try to jump over it.
+		m.vel[1] = 0.42
+	}
+
+	var fallDamage, fallDistance float64
+	if mv.dpos[1] < 0 && !inWater {
+		m.fallDistance -= mv.dpos[1]
+	} else if inWater {
+		m.fallDistance = 0
+	}
+	if m.mc.OnGround() {
+		if m.fallDistance > 3 && !m.conf.FallDamageImmune {
+			fallDamage, fallDistance = math.Ceil(m.fallDistance-3), m.fallDistance
+		}
+		m.fallDistance = 0
+	}
+	m.mu.Unlock()
+
+	if fallDamage > 0 {
+		if fallDamage = w.FallDamage(fallDamage, fallDistance); fallDamage > 0 {
+			m.Hurt(fallDamage, FallDamageSource{})
+		}
+	}
+	return mv
+}
+
+// checkEntityInsiders calls the EntityInside method of all blocks that the Mob is inside of.
+func (m *Mob) checkEntityInsiders(w *world.World) {
+	box := m.t.BBox(m).Translate(m.Position()).Grow(-0.0001)
+	min, max := cube.PosFromVec3(box.Min()), cube.PosFromVec3(box.Max())
+	for y := min[1]; y <= max[1]; y++ {
+		for x := min[0]; x <= max[0]; x++ {
+			for z := min[2]; z <= max[2]; z++ {
+				pos := cube.Pos{x, y, z}
+				b := w.Block(pos)
+				if in, ok := b.(block.EntityInsider); ok {
+					in.EntityInside(pos, w, m)
+					if _, liquid := b.(world.Liquid); liquid {
+						continue
+					}
+				}
+				if l, ok := w.Liquid(pos); ok {
+					if in, ok := l.(block.EntityInsider); ok {
+						in.EntityInside(pos, w, m)
+					}
+				}
+			}
+		}
+	}
+}
+
+// updateState sends the state of the Mob to all viewers of the Mob.
+func (m *Mob) updateState() {
+	for _, v := range m.World().Viewers(m.Position()) {
+		v.ViewEntityState(m)
+	}
+}
+
+// Close removes the Mob from the world that it is in.
+func (m *Mob) Close() error {
+	m.World().RemoveEntity(m)
+	return nil
+}
+
+// decodeMobNBT decodes the velocity, rotation and health of a Mob from the map passed.
+func decodeMobNBT(m *Mob, data map[string]any) {
+	m.SetVelocity(nbtconv.Vec3(data, "Motion"))
+	m.mu.Lock()
+	m.rot = nbtconv.Rotation(data)
+	m.mu.Unlock()
+	if health, ok := data["Health"].(float32); ok && float64(health) < m.MaxHealth() {
+		m.health.AddHealth(float64(health) - m.MaxHealth())
+	}
+}
+
+// encodeMobNBT encodes the position, velocity, rotation and health of a Mob to a map.
+func encodeMobNBT(m *Mob) map[string]any {
+	yaw, pitch := m.Rotation().Elem()
+	return map[string]any{
+		"UniqueID": -rand.Int63(),
+		"Pos":      nbtconv.Vec3ToFloat32Slice(m.Position()),
+		"Motion":   nbtconv.Vec3ToFloat32Slice(m.Velocity()),
+		"Yaw":      float32(yaw),
+		"Pitch":    float32(pitch),
+		"Health":   float32(m.Health()),
+	}
+}
+
+// rotationTowards returns the rotation that an entity at the position from should have to face the position to.
+func rotationTowards(from, to mgl64.Vec3) cube.Rotation {
+	diff := to.Sub(from)
+	horizontal := math.Sqrt(diff[0]*diff[0] + diff[2]*diff[2])
+	return cube.Rotation{
+		-math.Atan2(diff[0], diff[2]) * 180 / math.Pi,
+		-math.Atan2(diff[1], horizontal) * 180 / math.Pi,
+	}
+}
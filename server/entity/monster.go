@@ -0,0 +1,231 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/cube/trace"
+	"github.com/df-mc/dragonfly/server/entity/attribute"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/sound"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"time"
+)
+
+// TargetGoal is a Goal that makes a Mob target the nearest player that it can attack. A Mob that is attacked by an
+// entity also targets that entity. TargetGoal never prevents other goals from being ticked, so it should be the first
+// goal of a Mob.
+type TargetGoal struct {
+	// Range is the distance in blocks within which the Mob looks for targets. If 0, Range is 16.
+	Range float64
+	// Condition is an optional function that is called to check if the Mob should currently look for new targets. If
+	// it returns false, the Mob only targets entities that attack it.
+	Condition func(m *Mob) bool
+}
+
+// Tick ...
+func (g *TargetGoal) Tick(m *Mob) bool {
+	r := speedOr(g.Range, 16)
+	if attacker, _ := m.LastAttacker(); attacker != nil && m.RecentlyHurt(time.Second*5) && attackable(m, attacker, r) {
+		m.SetTarget(attacker)
+		return false
+	}
+	if target := m.Target(); target != nil {
+		if attackable(m, target, r) {
+			return false
+		}
+		m.SetTarget(nil)
+		m.StopMoving()
+	}
+	if g.Condition != nil && !g.Condition(m) {
+		return false
+	}
+	pos := m.Position()
+	var (
+		nearest world.Entity
+		dist    = math.MaxFloat64
+	)
+	for _, e := range m.World().EntitiesWithin(cube.Box(pos[0]-r, pos[1]-r, pos[2]-r, pos[0]+r, pos[1]+r, pos[2]+r), nil) {
+		if _, ok := e.(interface{ GameMode() world.GameMode }); !ok || !attackable(m, e, r) {
+			// Only players are targeted unless they attack the mob first.
+			continue
+		}
+		if d := e.Position().Sub(pos).Len(); d < dist {
+			nearest, dist = e, d
+		}
+	}
+	if nearest != nil {
+		m.SetTarget(nearest)
+	}
+	return false
+}
+
+// MeleeAttackGoal is a Goal that makes a Mob chase its target and attack it once it is within reach.
+type MeleeAttackGoal struct {
+	// Damage is the damage dealt to the target by every attack.
+	Damage float64
+	// Speed is the multiplier of the speed of the Mob while chasing its target. If 0, Speed is 1.
+	Speed float64
+
+	cooldown int
+}
+
+// Tick ...
+func (g *MeleeAttackGoal) Tick(m *Mob) bool {
+	if g.cooldown > 0 {
+		g.cooldown--
+	}
+	target, ok := m.Target().(Living)
+	if !ok {
+		return false
+	}
+	m.LookAt(EyePosition(target))
+	if m.Position().Sub(target.Position()).Len() > meleeReach(m) {
+		m.MoveTo(target.Position(), speedOr(g.Speed, 1))
+		return true
+	}
+	if g.cooldown == 0 && !target.AttackImmune() {
+		g.cooldown = 20
+		m.swingArm()
+		dmg := m.World().MobDamage(m.attributes.ValueWith(attribute.AttackDamage, attribute.Modifier{ID: "melee_attack", Amount: g.Damage - attribute.AttackDamage.Default()}))
+		if _, vulnerable := target.Hurt(dmg, AttackDamageSource{Attacker: m}); vulnerable {
+			target.KnockBack(m.Position(), 0.4, 0.4)
+		}
+	}
+	return true
+}
+
+// RangedAttackGoal is a Goal that makes a Mob shoot arrows at its target while keeping its distance.
+type RangedAttackGoal struct {
+	// Range is the distance in blocks from which the Mob starts shooting its target. If 0, Range is 15.
+	Range float64
+	// Speed is the multiplier of the speed of the Mob while approaching its target. If 0, Speed is 1.
+	Speed float64
+	// Interval is the minimum time between two shots. A random duration of up to one second is added to it. If 0,
+	// Interval is one second.
+	Interval time.Duration
+
+	cooldown int
+}
+
+// Tick ...
+func (g *RangedAttackGoal) Tick(m *Mob) bool {
+	if g.cooldown > 0 {
+		g.cooldown--
+	}
+	target := m.Target()
+	if target == nil {
+		return false
+	}
+	eye, targetEye := EyePosition(m), EyePosition(target)
+	m.LookAt(targetEye)
+
+	visible := lineOfSight(m.World(), eye, targetEye)
+	if dist := eye.Sub(targetEye).Len(); dist > speedOr(g.Range, 15) || !visible {
+		m.MoveTo(target.Position(), speedOr(g.Speed, 1))
+		return true
+	}
+	m.StopMoving()
+	if g.cooldown > 0 {
+		return true
+	}
+	interval := g.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	g.cooldown = int(interval.Milliseconds()/50) + m.World().Rand().Intn(20)
+
+	// Aim slightly above the target to make up for the gravity affecting the arrow.
+	diff := targetEye.Sub(eye)
+	diff[1] += math.Hypot(diff[0], diff[2]) * 0.2
+	vel := diff.Normalize().Mul(1.6)
+
+	w := m.World()
+	arrow := NewArrowWithDamage(eye, rotationTowards(eye, eye.Add(vel)), w.MobDamage(2), m)
+	arrow.Behaviour().(*ProjectileBehaviour).conf.DisablePickup = true
+	arrow.SetVelocity(vel)
+	w.AddEntity(arrow)
+	w.PlaySound(eye, sound.BowShoot{})
+	return true
+}
+
+// attackable checks if the entity passed may be targeted by the Mob passed. Entities that are dead, in another
+// world, too far away or in a game mode in which they cannot take damage cannot be targeted. No entities can be
+// targeted if the difficulty of the world is peaceful.
+func attackable(m *Mob, e world.Entity, r float64) bool {
+	l, ok := e.(Living)
+	if !ok || m.World().Difficulty() == world.DifficultyPeaceful || l == m || l.Dead() || l.World() != m.World() || l.Position().Sub(m.Position()).Len() > r {
+		return false
+	}
+	if g, ok := e.(interface{ GameMode() world.GameMode }); ok {
+		return g.GameMode().AllowsTakingDamage() && g.GameMode().Visible()
+	}
+	return true
+}
+
+// meleeReach returns the distance from which the Mob passed can hit its target.
+func meleeReach(m *Mob) float64 {
+	return m.Type().BBox(m).Width() + 0.8
+}
+
+// lineOfSight checks if no blocks are between the start and end positions passed.
+func lineOfSight(w *world.World, start, end mgl64.Vec3) bool {
+	visible := true
+	trace.TraverseBlocks(start, end, func(pos cube.Pos) bool {
+		if _, air := w.Block(pos).(block.Air); !air {
+			if _, ok := trace.BlockIntercept(pos, w, w.Block(pos), start, end); ok {
+				visible = false
+			}
+		}
+		return visible
+	})
+	return visible
+}
+
+// burnInDaylight sets the Mob passed on fire if it is standing in direct sunlight during the day, which is the case
+// for undead mobs such as zombies and skeletons.
+func burnInDaylight(m *Mob) {
+	w := m.World()
+	if w.Dimension() != world.Overworld {
+		return
+	}
+	if t := w.DayTime(); t > 12000 && t < 23500 {
+		return
+	}
+	pos := cube.PosFromVec3(EyePosition(m))
+	if _, ok := w.Liquid(pos); ok || w.RainingAt(pos) || w.SkyLight(pos) < 15 {
+		return
+	}
+	if m.OnFireDuration() <= 0 {
+		m.SetOnFire(time.Second * 8)
+	}
+}
+
+// monsterBehaviour implements behaviour shared by monsters.
+type monsterBehaviour struct {
+	// undead specifies if the monster burns in daylight.
+	undead bool
+}
+
+// Tick sets the monster on fire if it is undead and standing in sunlight.
+func (b monsterBehaviour) Tick(m *Mob) {
+	if b.undead {
+		burnInDaylight(m)
+	}
+}
+
+// MonsterSpawnEntries returns world.SpawnEntry values for zombies, skeletons, creepers and spiders, which spawn in
+// groups in the dark. The entries may be added to the spawn pools of a world.SpawnRules to make monsters spawn
+// naturally.
+func MonsterSpawnEntries() []world.SpawnEntry {
+	entry := func(weight int, f func(pos mgl64.Vec3) *Mob) world.SpawnEntry {
+		return world.SpawnEntry{
+			Category:     world.SpawnCategoryHostile,
+			Weight:       weight,
+			MinGroupSize: 4,
+			MaxGroupSize: 4,
+			New:          func(pos mgl64.Vec3, w *world.World) world.Entity { return spawnMob(f(pos), w.Rand()) },
+		}
+	}
+	return []world.SpawnEntry{entry(95, NewZombie), entry(100, NewSkeleton), entry(100, NewCreeper), entry(100, NewSpider)}
+}
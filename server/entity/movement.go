@@ -4,7 +4,6 @@ import (
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
-	"math"
 )
 
 // MovementComputer is used to compute movement of an entity. When constructed, the Gravity of the entity
@@ -12,8 +11,15 @@ import (
 type MovementComputer struct {
 	Gravity, Drag     float64
 	DragBeforeGravity bool
+	// Buoyancy is the Y velocity added every tick while the entity is in water. Entities with a Buoyancy higher
+	// than their Gravity float up to the surface of the water.
+	Buoyancy float64
+	// WaterDrag and LavaDrag are used instead of Drag while the entity is in water or lava respectively. If 0, Drag
+	// is used.
+	WaterDrag, LavaDrag float64
 
 	onGround bool
+	liquid   world.Liquid
 }
 
 // Movement represents the movement of a world.Entity as a result of a call to MovementComputer.TickMovement. The
@@ -67,6 +73,8 @@ func (c *MovementComputer) TickMovement(e world.Entity, pos, vel mgl64.Vec3, rot
 	viewers := w.Viewers(pos)
 
 	velBefore := vel
+	c.liquid, _ = w.Liquid(cube.PosFromVec3(pos))
+	vel = c.applyLiquidForces(w, pos, vel)
 	vel = c.applyHorizontalForces(w, pos, c.applyVerticalForces(vel))
 	dPos, vel := c.checkCollision(e, pos, vel)
 
@@ -81,6 +89,16 @@ func (c *MovementComputer) OnGround() bool {
 	return c.onGround
 }
 
+// InWater checks if the entity that this computer calculates was in water during the last movement tick.
+func (c *MovementComputer) InWater() bool {
+	return c.liquid != nil && c.liquid.LiquidType() == "water"
+}
+
+// InLava checks if the entity that this computer calculates was in lava during the last movement tick.
+func (c *MovementComputer) InLava() bool {
+	return c.liquid != nil && c.liquid.LiquidType() == "lava"
+}
+
 // zeroVec3 is a mgl64.Vec3 with zero values.
 var zeroVec3 mgl64.Vec3
 
@@ -89,19 +107,20 @@ const epsilon = 0.001
 
 // applyVerticalForces applies gravity and drag on the Y axis, based on the Gravity and Drag values set.
 func (c *MovementComputer) applyVerticalForces(vel mgl64.Vec3) mgl64.Vec3 {
+	drag := c.drag()
 	if c.DragBeforeGravity {
-		vel[1] *= 1 - c.Drag
+		vel[1] *= 1 - drag
 	}
 	vel[1] -= c.Gravity
 	if !c.DragBeforeGravity {
-		vel[1] *= 1 - c.Drag
+		vel[1] *= 1 - drag
 	}
 	return vel
 }
 
 // applyHorizontalForces applies friction to the velocity based on the Drag value, reducing it on the X and Z axes.
 func (c *MovementComputer) applyHorizontalForces(w *world.World, pos, vel mgl64.Vec3) mgl64.Vec3 {
-	friction := 1 - c.Drag
+	friction := 1 - c.drag()
 	if c.onGround {
 		if f, ok := w.Block(cube.PosFromVec3(pos).Side(cube.FaceDown)).(interface {
 			Friction() float64
@@ -172,24 +191,5 @@ func (c *MovementComputer) checkCollision(e world.Entity, pos, vel mgl64.Vec3) (
 // blockBBoxsAround returns all blocks around the entity passed, using the BBox passed to make a prediction of
 // what blocks need to have their BBox returned.
 func blockBBoxsAround(e world.Entity, box cube.BBox) []cube.BBox {
-	w := e.World()
-	grown := box.Grow(0.25)
-	min, max := grown.Min(), grown.Max()
-	minX, minY, minZ := int(math.Floor(min[0])), int(math.Floor(min[1])), int(math.Floor(min[2]))
-	maxX, maxY, maxZ := int(math.Ceil(max[0])), int(math.Ceil(max[1])), int(math.Ceil(max[2]))
-
-	// A prediction of one BBox per block, plus an additional 2, in case
-	blockBBoxs := make([]cube.BBox, 0, (maxX-minX)*(maxY-minY)*(maxZ-minZ)+2)
-	for y := minY; y <= maxY; y++ {
-		for x := minX; x <= maxX; x++ {
-			for z := minZ; z <= maxZ; z++ {
-				pos := cube.Pos{x, y, z}
-				boxes := w.Block(pos).Model().BBox(pos, w)
-				for _, box := range boxes {
-					blockBBoxs = append(blockBBoxs, box.Translate(mgl64.Vec3{float64(x), float64(y), float64(z)}))
-				}
-			}
-		}
-	}
-	return blockBBoxs
+	return e.World().BlockBBoxes(box.Grow(0.25))
 }
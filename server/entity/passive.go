@@ -14,6 +14,12 @@ type PassiveBehaviourConfig struct {
 	// Drag is used to reduce all axes of the velocity every tick. Velocity is
 	// multiplied with (1-Drag) every tick.
 	Drag float64
+	// Buoyancy is the amount of Y velocity added every tick while the entity
+	// is in water. Entities with a Buoyancy higher than their Gravity float.
+	Buoyancy float64
+	// WaterDrag and LavaDrag are used instead of Drag while the entity is in
+	// water or lava respectively. If 0, Drag is used.
+	WaterDrag, LavaDrag float64
 	// ExistenceDuration is the duration that an entity with this behaviour
 	// should last. Once this time expires, the entity is closed. If
 	// ExistenceDuration is 0, the entity will never expire automatically.
@@ -35,6 +41,9 @@ func (conf PassiveBehaviourConfig) New() *PassiveBehaviour {
 		Gravity:           conf.Gravity,
 		Drag:              conf.Drag,
 		DragBeforeGravity: true,
+		Buoyancy:          conf.Buoyancy,
+		WaterDrag:         conf.WaterDrag,
+		LavaDrag:          conf.LavaDrag,
 	}}
 }
 
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewPig creates a new adult pig at the position passed. Pigs are bred using carrots, potatoes and beetroots.
+func NewPig(pos mgl64.Vec3) *Mob {
+	return newPig(pos, false)
+}
+
+// newPig creates a new pig at the position passed. If baby is true, the pig starts out as a baby.
+func newPig(pos mgl64.Vec3, baby bool) *Mob {
+	return MobConfig{
+		MaxHealth: 10,
+		Speed:     0.1,
+		Goals:     animalGoals(pigFood),
+		Behaviour: AnimalBehaviourConfig{
+			Food: pigFood,
+			Baby: func(pos mgl64.Vec3) *Mob { return newPig(pos, true) },
+		}.New(baby),
+		Drops: animalDrops(func(m *Mob) []item.Stack {
+			return []item.Stack{item.NewStack(item.Porkchop{Cooked: m.OnFireDuration() > 0}, m.World().Rand().Intn(3)+1)}
+		}),
+		MinExperience: 1,
+		MaxExperience: 3,
+	}.New(PigType{}, pos)
+}
+
+// pigFood checks if an item.Stack is food of pigs.
+func pigFood(s item.Stack) bool {
+	switch s.Item().(type) {
+	case block.Carrot, block.Potato, item.Beetroot:
+		return true
+	}
+	return false
+}
+
+// PigType is a world.EntityType implementation for pigs.
+type PigType struct{}
+
+func (PigType) EncodeEntity() string { return "minecraft:pig" }
+func (PigType) BBox(e world.Entity) cube.BBox {
+	return animalBBox(e, 0.9, 0.9)
+}
+
+func (PigType) DecodeNBT(m map[string]any) world.Entity {
+	pig := NewPig(nbtconv.Vec3(m, "Pos"))
+	decodeAnimalNBT(pig, m)
+	return pig
+}
+
+func (PigType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeAnimalNBT(e.(*Mob))
+}
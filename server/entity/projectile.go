@@ -4,12 +4,12 @@ import (
 	"github.com/df-mc/dragonfly/server/block"
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/block/cube/trace"
+	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/item/potion"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
 	"math"
-	"math/rand"
 	"time"
 )
 
@@ -155,6 +155,26 @@ func (lt *ProjectileBehaviour) Tick(e *Ent) *Movement {
 		return m
 	}
 
+	var (
+		target   world.Entity
+		blockPos cube.Pos
+	)
+	switch r := result.(type) {
+	case trace.EntityResult:
+		target = r.Entity()
+	case trace.BlockResult:
+		blockPos = r.BlockPosition()
+	}
+	ctx := event.C()
+	if w.Events().HandleProjectileHit(ctx, e, result.Position(), target, blockPos); ctx.Cancelled() {
+		if r, ok := result.(trace.BlockResult); ok && lt.conf.SurviveBlockCollision {
+			lt.hitBlockSurviving(e, r, m)
+			return m
+		}
+		lt.close = true
+		return m
+	}
+
 	for i := 0; i < lt.conf.ParticleCount; i++ {
 		w.AddParticle(result.Position(), lt.conf.Particle)
 	}
@@ -169,8 +189,8 @@ func (lt *ProjectileBehaviour) Tick(e *Ent) *Movement {
 		}
 	case trace.BlockResult:
 		bpos := r.BlockPosition()
-		if t, ok := w.Block(bpos).(block.TNT); ok && e.OnFireDuration() > 0 {
-			t.Ignite(bpos, w)
+		if i, ok := w.Block(bpos).(block.Ignitable); ok && e.OnFireDuration() > 0 {
+			i.Ignite(bpos, w)
 		}
 		if lt.conf.SurviveBlockCollision {
 			lt.hitBlockSurviving(e, r, m)
@@ -265,7 +285,7 @@ func (lt *ProjectileBehaviour) hitEntity(l Living, e *Ent, origin, vel mgl64.Vec
 	src := ProjectileDamageSource{Projectile: e, Owner: lt.owner}
 	dmg := math.Ceil(lt.conf.Damage * vel.Len())
 	if lt.conf.Critical {
-		dmg += rand.Float64() * dmg / 2
+		dmg += e.World().Rand().Float64() * dmg / 2
 	}
 	if _, vulnerable := l.Hurt(lt.conf.Damage, src); vulnerable {
 		l.KnockBack(origin, 0.45+lt.conf.KnockBackForceAddend, 0.3608+lt.conf.KnockBackHeightAddend)
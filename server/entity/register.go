@@ -16,6 +16,9 @@ var DefaultRegistry = conf.New([]world.EntityType{
 	AreaEffectCloudType{},
 	ArrowType{},
 	BottleOfEnchantingType{},
+	ChickenType{},
+	CowType{},
+	CreeperType{},
 	EggType{},
 	EnderPearlType{},
 	ExperienceOrbType{},
@@ -24,10 +27,15 @@ var DefaultRegistry = conf.New([]world.EntityType{
 	ItemType{},
 	LightningType{},
 	LingeringPotionType{},
+	PigType{},
+	SheepType{},
+	SkeletonType{},
 	SnowballType{},
+	SpiderType{},
 	SplashPotionType{},
 	TNTType{},
 	TextType{},
+	ZombieType{},
 })
 
 var conf = world.EntityRegistryConfig{
@@ -0,0 +1,175 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/world"
+	"sync"
+	"time"
+)
+
+// Stacking specifies what happens if a Task is scheduled on a Scheduler that already has a Task with the same name.
+type Stacking uint8
+
+const (
+	// StackReplace replaces the existing Task with the new Task.
+	StackReplace Stacking = iota
+	// StackKeep keeps the existing Task and discards the new Task.
+	StackKeep
+	// StackExtend extends the duration of the existing Task by the Duration of the new Task. If either Task runs
+	// until cancelled, the existing Task will run until cancelled.
+	StackExtend
+	// StackIndependent runs the new Task alongside the existing Tasks, up to the MaxStacks of the new Task. If
+	// MaxStacks is reached, the Task with the least time left is replaced.
+	StackIndependent
+)
+
+// Task is a function that is run periodically on an entity by a Scheduler, such as damage or healing over time.
+type Task struct {
+	// Name identifies the Task. Tasks scheduled with the same name are stacked according to Stacking.
+	Name string
+	// Interval is the interval at which Run is called. The first call happens an Interval after the Task was
+	// scheduled. Interval is rounded down to ticks and is at least one tick.
+	Interval time.Duration
+	// Duration is the duration after which the Task is removed from the Scheduler. If 0, the Task runs until it
+	// is cancelled using Scheduler.Cancel.
+	Duration time.Duration
+	// Stacking specifies what happens if a Task with the same name is already scheduled.
+	Stacking Stacking
+	// MaxStacks is the maximum amount of Tasks with the same name that run at the same time if Stacking is
+	// StackIndependent. If 0 or lower, there is no maximum.
+	MaxStacks int
+	// Run is called every Interval with the entity that the Scheduler belongs to.
+	Run func(e Living)
+}
+
+// DamageOverTime returns a Task that hurts an entity for the damage passed every interval, until the duration
+// passed has elapsed. Tasks returned by DamageOverTime with the same name replace each other.
+func DamageOverTime(name string, damage float64, src world.DamageSource, interval, duration time.Duration) Task {
+	return Task{Name: name, Interval: interval, Duration: duration, Run: func(e Living) {
+		e.Hurt(damage, src)
+	}}
+}
+
+// HealOverTime returns a Task that heals an entity for the health passed every interval, until the duration passed
+// has elapsed. Tasks returned by HealOverTime with the same name replace each other.
+func HealOverTime(name string, health float64, src world.HealingSource, interval, duration time.Duration) Task {
+	return Task{Name: name, Interval: interval, Duration: duration, Run: func(e Living) {
+		e.Heal(health, src)
+	}}
+}
+
+// Scheduler runs Tasks periodically on an entity. Tasks are run when the Scheduler is ticked by the entity, so that
+// Tasks do not need to spawn goroutines of their own. Because entities are only ticked while the chunk they are in
+// is loaded, Tasks are paused while the entity is unloaded and continue once it is loaded again. Tasks are not
+// saved with the entity.
+type Scheduler struct {
+	mu    sync.Mutex
+	tasks map[string][]*scheduledTask
+}
+
+// scheduledTask is a Task scheduled on a Scheduler, along with the ticks that passed since it was scheduled and the
+// ticks it has left.
+type scheduledTask struct {
+	Task
+	interval, elapsed, left int
+}
+
+// NewScheduler creates and returns a new initialised Scheduler.
+func NewScheduler() *Scheduler {
+	return &Scheduler{tasks: map[string][]*scheduledTask{}}
+}
+
+// Schedule schedules the Task passed on the Scheduler. If a Task with the same name is already scheduled, the Task
+// is stacked according to its Stacking.
+func (s *Scheduler) Schedule(t Task) {
+	st := &scheduledTask{Task: t, interval: int(t.Interval / (time.Second / 20)), left: -1}
+	if st.interval < 1 {
+		st.interval = 1
+	}
+	if t.Duration > 0 {
+		st.left = int(t.Duration / (time.Second / 20))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing := s.tasks[t.Name]
+	if len(existing) == 0 {
+		s.tasks[t.Name] = []*scheduledTask{st}
+		return
+	}
+	switch t.Stacking {
+	case StackReplace:
+		s.tasks[t.Name] = []*scheduledTask{st}
+	case StackExtend:
+		if existing[0].left == -1 || st.left == -1 {
+			existing[0].left = -1
+			return
+		}
+		existing[0].left += st.left
+	case StackIndependent:
+		if t.MaxStacks > 0 && len(existing) >= t.MaxStacks {
+			least := 0
+			for i, other := range existing {
+				if other.left != -1 && (existing[least].left == -1 || other.left < existing[least].left) {
+					least = i
+				}
+			}
+			existing[least] = st
+			return
+		}
+		s.tasks[t.Name] = append(existing, st)
+	}
+}
+
+// Cancel removes all Tasks with the name passed from the Scheduler.
+func (s *Scheduler) Cancel(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tasks, name)
+}
+
+// Scheduled returns the amount of Tasks with the name passed that are currently scheduled.
+func (s *Scheduler) Scheduled(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.tasks[name])
+}
+
+// Clear removes all Tasks from the Scheduler.
+func (s *Scheduler) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks = map[string][]*scheduledTask{}
+}
+
+// Tick ticks the Scheduler, running all Tasks of which the Interval has passed with the Living entity passed and
+// removing Tasks of which the Duration has passed.
+func (s *Scheduler) Tick(e Living) {
+	s.mu.Lock()
+	var due []func(e Living)
+	for name, tasks := range s.tasks {
+		n := 0
+		for _, t := range tasks {
+			t.elapsed++
+			if t.elapsed%t.interval == 0 {
+				due = append(due, t.Run)
+			}
+			if t.left > 0 {
+				t.left--
+			}
+			if t.left != 0 {
+				tasks[n] = t
+				n++
+			}
+		}
+		if n == 0 {
+			delete(s.tasks, name)
+			continue
+		}
+		s.tasks[name] = tasks[:n]
+	}
+	s.mu.Unlock()
+
+	for _, run := range due {
+		run(e)
+	}
+}
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewSeat creates a new Seat entity at the position passed, with the rider passed seated on it. Seats are invisible
+// entities that are never saved and close themselves once their rider leaves their world. Seats are generally not
+// created directly, but through player.Player.Sit.
+func NewSeat(pos mgl64.Vec3, rider world.Entity) *Ent {
+	return Config{Behaviour: &SeatBehaviour{rider: rider}}.New(SeatType{}, pos)
+}
+
+// SeatBehaviour implements the behaviour of seats. Seats never move and hold the entity seated on them.
+type SeatBehaviour struct {
+	rider world.Entity
+}
+
+// Rider returns the entity seated on the seat.
+func (s *SeatBehaviour) Rider() world.Entity {
+	return s.rider
+}
+
+// Tick closes the seat if its rider is no longer in the same world.
+func (s *SeatBehaviour) Tick(e *Ent) *Movement {
+	if s.rider.World() != e.World() {
+		_ = e.Close()
+	}
+	return nil
+}
+
+// Immobile always returns true.
+func (s *SeatBehaviour) Immobile() bool {
+	return true
+}
+
+// SeatType is a world.EntityType implementation for Seat.
+type SeatType struct{}
+
+func (SeatType) EncodeEntity() string        { return "dragonfly:seat" }
+func (SeatType) BBox(world.Entity) cube.BBox { return cube.BBox{} }
+func (SeatType) NetworkEncodeEntity() string { return "minecraft:falling_block" }
@@ -0,0 +1,212 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
+	"sync"
+)
+
+// NewSheep creates a new adult sheep at the position passed with a random natural colour, picked using the rand.Rand
+// passed, such as the one returned by World.Rand. Sheep may be sheared using shears and regrow their wool by eating
+// grass. They are bred using wheat.
+func NewSheep(pos mgl64.Vec3, r *rand.Rand) *Mob {
+	return newSheep(pos, naturalSheepColour(r), false)
+}
+
+// NewSheepWithColour creates a new adult sheep at the position passed with wool of the item.Colour passed.
+func NewSheepWithColour(pos mgl64.Vec3, colour item.Colour) *Mob {
+	return newSheep(pos, colour, false)
+}
+
+// newSheep creates a new sheep with a colour at the position passed. If baby is true, the sheep starts out as a baby.
+func newSheep(pos mgl64.Vec3, colour item.Colour, baby bool) *Mob {
+	b := &SheepBehaviour{colour: colour}
+	b.AnimalBehaviour = AnimalBehaviourConfig{
+		Food: wheatFood,
+		Baby: func(pos mgl64.Vec3) *Mob {
+			// Babies take the colour of one of their parents.
+			return newSheep(pos, b.Colour(), true)
+		},
+	}.New(baby)
+
+	return MobConfig{
+		MaxHealth: 8,
+		Speed:     0.1,
+		Goals:     animalGoals(wheatFood),
+		Behaviour: b,
+		Drops: animalDrops(func(m *Mob) []item.Stack {
+			drops := []item.Stack{item.NewStack(item.Mutton{Cooked: m.OnFireDuration() > 0}, m.World().Rand().Intn(2)+1)}
+			if !b.Sheared() {
+				drops = append(drops, item.NewStack(block.Wool{Colour: b.Colour()}, 1))
+			}
+			return drops
+		}),
+		MinExperience: 1,
+		MaxExperience: 3,
+	}.New(SheepType{}, pos)
+}
+
+// SheepBehaviour implements the behaviour of sheep. In addition to being bred like other animals, sheep may be
+// sheared and dyed. Sheared sheep regrow their wool by eating grass.
+type SheepBehaviour struct {
+	*AnimalBehaviour
+
+	mu      sync.Mutex
+	colour  item.Colour
+	sheared bool
+}
+
+// Colour returns the colour of the wool of the sheep.
+func (b *SheepBehaviour) Colour() item.Colour {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.colour
+}
+
+// Sheared checks if the sheep has been sheared and has not yet regrown its wool.
+func (b *SheepBehaviour) Sheared() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sheared
+}
+
+// Tick makes the sheep eat grass every now and then, which regrows its wool if it was sheared. Grass is eaten more
+// often by babies, which grow up faster by doing so. The chance scales with the random tick speed of the world, so
+// that sheep do not eat grass at all if random ticking is disabled.
+func (b *SheepBehaviour) Tick(m *Mob) {
+	b.AnimalBehaviour.Tick(m)
+
+	w := m.World()
+	speed := w.RandomTickSpeed()
+	if speed <= 0 || !m.OnGround() || m.Moving() {
+		return
+	}
+	chance := 1000
+	if b.Baby() {
+		chance = 50
+	}
+	if m.World().Rand().Intn(chance*3) >= speed {
+		return
+	}
+	pos := cube.PosFromVec3(m.Position())
+	switch bl := w.Block(pos).(type) {
+	case block.TallGrass:
+		w.SetBlock(pos, nil, nil)
+		w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: bl})
+	default:
+		below := pos.Side(cube.FaceDown)
+		if _, ok := w.Block(below).(block.Grass); !ok {
+			return
+		}
+		w.SetBlock(below, block.Dirt{}, nil)
+		w.AddParticle(below.Vec3Centre(), particle.BlockBreak{Block: block.Grass{}})
+	}
+	for _, v := range w.Viewers(m.Position()) {
+		v.ViewEntityAction(m, EatAction{})
+	}
+	b.eatGrass(m)
+}
+
+// eatGrass regrows the wool of the sheep and makes it grow up faster if it is a baby.
+func (b *SheepBehaviour) eatGrass(m *Mob) {
+	b.mu.Lock()
+	b.sheared = false
+	b.mu.Unlock()
+
+	b.AnimalBehaviour.mu.Lock()
+	if b.growUp > 0 {
+		b.growUp -= b.growUp / 10
+	}
+	b.AnimalBehaviour.mu.Unlock()
+	m.updateState()
+}
+
+// Interact shears the sheep if the user holds shears and dyes its wool if the user holds dye. Otherwise, the sheep is
+// fed like other animals.
+func (b *SheepBehaviour) Interact(m *Mob, user item.User, held item.Stack, ctx *item.UseContext) bool {
+	switch it := held.Item().(type) {
+	case item.Shears:
+		if b.Baby() || b.Sheared() {
+			return false
+		}
+		b.mu.Lock()
+		b.sheared = true
+		colour := b.colour
+		b.mu.Unlock()
+
+		w, pos := m.World(), m.Position()
+		for i := w.Rand().Intn(3) + 1; i > 0; i-- {
+			wool := NewItem(item.NewStack(block.Wool{Colour: colour}, 1), pos.Add(mgl64.Vec3{0, 1}))
+			wool.SetVelocity(mgl64.Vec3{w.Rand().Float64()*0.2 - 0.1, 0.1 + w.Rand().Float64()*0.05, w.Rand().Float64()*0.2 - 0.1})
+			w.AddEntity(wool)
+		}
+		ctx.DamageItem(1)
+		m.updateState()
+		return true
+	case item.Dye:
+		b.mu.Lock()
+		if b.colour == it.Colour {
+			b.mu.Unlock()
+			return false
+		}
+		b.colour = it.Colour
+		b.mu.Unlock()
+
+		ctx.SubtractFromCount(1)
+		m.updateState()
+		return true
+	}
+	return b.AnimalBehaviour.Interact(m, user, held, ctx)
+}
+
+// naturalSheepColour returns a random colour for a naturally spawned sheep: Most sheep are white, while black, grey,
+// light grey, brown and pink sheep are increasingly rare.
+func naturalSheepColour(r *rand.Rand) item.Colour {
+	switch n := r.Intn(100000); {
+	case n < 5000:
+		return item.ColourBlack()
+	case n < 10000:
+		return item.ColourGrey()
+	case n < 15000:
+		return item.ColourLightGrey()
+	case n < 18000:
+		return item.ColourBrown()
+	case n < 18164:
+		return item.ColourPink()
+	}
+	return item.ColourWhite()
+}
+
+// SheepType is a world.EntityType implementation for sheep.
+type SheepType struct{}
+
+func (SheepType) EncodeEntity() string { return "minecraft:sheep" }
+func (SheepType) BBox(e world.Entity) cube.BBox {
+	return animalBBox(e, 0.9, 1.3)
+}
+
+func (SheepType) DecodeNBT(m map[string]any) world.Entity {
+	colour := item.ColourWhite()
+	if c := nbtconv.Uint8(m, "Color"); int(c) < len(item.Colours()) {
+		colour = item.Colours()[c]
+	}
+	sheep := newSheep(nbtconv.Vec3(m, "Pos"), colour, false)
+	decodeAnimalNBT(sheep, m)
+	sheep.Behaviour().(*SheepBehaviour).sheared = nbtconv.Bool(m, "Sheared")
+	return sheep
+}
+
+func (SheepType) EncodeNBT(e world.Entity) map[string]any {
+	sheep := e.(*Mob)
+	b := sheep.Behaviour().(*SheepBehaviour)
+	data := encodeAnimalNBT(sheep)
+	data["Color"] = b.Colour().Uint8()
+	data["Sheared"] = boolByte(b.Sheared())
+	return data
+}
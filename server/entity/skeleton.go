@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewSkeleton creates a new skeleton at the position passed. Skeletons shoot arrows at players from a distance using
+// their bow. They burn in daylight.
+func NewSkeleton(pos mgl64.Vec3) *Mob {
+	return MobConfig{
+		MaxHealth: 20,
+		Speed:     0.125,
+		Goals:     []Goal{&TargetGoal{}, &RangedAttackGoal{}, &WanderGoal{}},
+		Behaviour: monsterBehaviour{undead: true},
+		Drops: func(m *Mob) []item.Stack {
+			var drops []item.Stack
+			if n := m.World().Rand().Intn(3); n > 0 {
+				drops = append(drops, item.NewStack(item.Bone{}, n))
+			}
+			if n := m.World().Rand().Intn(3); n > 0 {
+				drops = append(drops, item.NewStack(item.Arrow{}, n))
+			}
+			return drops
+		},
+		MinExperience: 5,
+		MaxExperience: 5,
+		MainHand:      item.NewStack(item.Bow{}, 1),
+	}.New(SkeletonType{}, pos)
+}
+
+// SkeletonType is a world.EntityType implementation for skeletons.
+type SkeletonType struct{}
+
+func (SkeletonType) EncodeEntity() string { return "minecraft:skeleton" }
+func (SkeletonType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.3, 0, -0.3, 0.3, 1.99, 0.3)
+}
+
+func (SkeletonType) DecodeNBT(m map[string]any) world.Entity {
+	skeleton := NewSkeleton(nbtconv.Vec3(m, "Pos"))
+	decodeMobNBT(skeleton, m)
+	return skeleton
+}
+
+func (SkeletonType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeMobNBT(e.(*Mob))
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewSpider creates a new spider at the position passed. Spiders attack players in the dark, but only attack in
+// daylight when provoked. They are able to climb walls.
+func NewSpider(pos mgl64.Vec3) *Mob {
+	return MobConfig{
+		MaxHealth: 16,
+		Speed:     0.15,
+		Goals:     []Goal{&TargetGoal{Condition: spiderHostile}, &MeleeAttackGoal{Damage: 2}, &WanderGoal{}},
+		Behaviour: spiderBehaviour{},
+		Drops: func(m *Mob) []item.Stack {
+			var drops []item.Stack
+			if n := m.World().Rand().Intn(3); n > 0 {
+				drops = append(drops, item.NewStack(item.String{}, n))
+			}
+			if attacker, _ := m.LastAttacker(); attacker != nil && m.World().Rand().Intn(3) == 0 {
+				drops = append(drops, item.NewStack(item.SpiderEye{}, 1))
+			}
+			return drops
+		},
+		MinExperience: 5,
+		MaxExperience: 5,
+	}.New(SpiderType{}, pos)
+}
+
+// spiderHostile checks if a spider looks for targets by itself, which is the case when it is in the dark.
+func spiderHostile(m *Mob) bool {
+	return m.World().Light(cube.PosFromVec3(m.Position())) < 12
+}
+
+// spiderBehaviour implements the behaviour of spiders, which climb up walls that block their way.
+type spiderBehaviour struct{}
+
+// Tick makes the spider climb up if it is walking against a wall.
+func (spiderBehaviour) Tick(m *Mob) {
+	if !m.Moving() {
+		return
+	}
+	w, pos := m.World(), cube.PosFromVec3(m.Position())
+	face := m.Rotation().Direction().Face()
+	if front := pos.Side(face); w.Block(front).Model().FaceSolid(front, face.Opposite(), w) {
+		vel := m.Velocity()
+		vel[1] = 0.2
+		m.SetVelocity(vel)
+	}
+}
+
+// SpiderType is a world.EntityType implementation for spiders.
+type SpiderType struct{}
+
+func (SpiderType) EncodeEntity() string { return "minecraft:spider" }
+func (SpiderType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.7, 0, -0.7, 0.7, 0.9, 0.7)
+}
+
+func (SpiderType) DecodeNBT(m map[string]any) world.Entity {
+	spider := NewSpider(nbtconv.Vec3(m, "Pos"))
+	decodeMobNBT(spider, m)
+	return spider
+}
+
+func (SpiderType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeMobNBT(e.(*Mob))
+}
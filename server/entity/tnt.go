@@ -7,18 +7,33 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
 	"math"
-	"math/rand"
 	"time"
 )
 
-// NewTNT creates a new primed TNT entity.
+// NewTNT creates a new primed TNT entity. On its first tick, the TNT is launched in a random horizontal direction
+// using the random source of the world it is in.
 func NewTNT(pos mgl64.Vec3, fuse time.Duration) *Ent {
+	return newTNT(pos, fuse, true)
+}
+
+// newTNT creates a new primed TNT entity. If launch is true, the TNT is launched in a random horizontal direction
+// on its first tick.
+func newTNT(pos mgl64.Vec3, fuse time.Duration, launch bool) *Ent {
 	config := tntConf
 	config.ExistenceDuration = fuse
+	if launch {
+		launched := false
+		config.Tick = func(e *Ent) {
+			if launched {
+				return
+			}
+			launched = true
+			angle := e.World().Rand().Float64() * math.Pi * 2
+			e.SetVelocity(e.Velocity().Add(mgl64.Vec3{-math.Sin(angle) * 0.02, 0, -math.Cos(angle) * 0.02}))
+		}
+	}
 	ent := Config{Behaviour: config.New()}.New(TNTType{}, pos)
-
-	angle := rand.Float64() * math.Pi * 2
-	ent.vel = mgl64.Vec3{-math.Sin(angle) * 0.02, 0.1, -math.Cos(angle) * 0.02}
+	ent.vel = mgl64.Vec3{0, 0.1, 0}
 	return ent
 }
 
@@ -44,7 +59,7 @@ func (TNTType) BBox(world.Entity) cube.BBox {
 }
 
 func (TNTType) DecodeNBT(m map[string]any) world.Entity {
-	tnt := NewTNT(nbtconv.Vec3(m, "Pos"), nbtconv.TickDuration[uint8](m, "Fuse"))
+	tnt := newTNT(nbtconv.Vec3(m, "Pos"), nbtconv.TickDuration[uint8](m, "Fuse"), false)
 	tnt.vel = nbtconv.Vec3(m, "Motion")
 	return tnt
 }
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewZombie creates a new zombie at the position passed. Zombies chase players and attack them in melee. They burn
+// in daylight.
+func NewZombie(pos mgl64.Vec3) *Mob {
+	return MobConfig{
+		MaxHealth: 20,
+		Speed:     0.115,
+		Goals:     []Goal{&TargetGoal{Range: 35}, &MeleeAttackGoal{Damage: 3}, &WanderGoal{}},
+		Behaviour: monsterBehaviour{undead: true},
+		Drops: func(m *Mob) []item.Stack {
+			if n := m.World().Rand().Intn(3); n > 0 {
+				return []item.Stack{item.NewStack(item.RottenFlesh{}, n)}
+			}
+			return nil
+		},
+		MinExperience: 5,
+		MaxExperience: 5,
+	}.New(ZombieType{}, pos)
+}
+
+// ZombieType is a world.EntityType implementation for zombies.
+type ZombieType struct{}
+
+func (ZombieType) EncodeEntity() string { return "minecraft:zombie" }
+func (ZombieType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.3, 0, -0.3, 0.3, 1.95, 0.3)
+}
+
+func (ZombieType) DecodeNBT(m map[string]any) world.Entity {
+	zombie := NewZombie(nbtconv.Vec3(m, "Pos"))
+	decodeMobNBT(zombie, m)
+	return zombie
+}
+
+func (ZombieType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeMobNBT(e.(*Mob))
+}
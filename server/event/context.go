@@ -4,6 +4,7 @@ package event
 // the result of the event.
 type Context struct {
 	cancel bool
+	frozen bool
 }
 
 // C returns a new event context.
@@ -16,7 +17,18 @@ func (ctx *Context) Cancelled() bool {
 	return ctx.cancel
 }
 
-// Cancel cancels the context.
+// Cancel cancels the context. Cancel has no effect when called by a listener with PriorityMonitor.
 func (ctx *Context) Cancel() {
-	ctx.cancel = true
+	if !ctx.frozen {
+		ctx.cancel = true
+	}
+}
+
+// Uncancel reverts the cancellation of the context, for example when a listener with a higher Priority decides
+// that an event cancelled by another listener should happen after all. Uncancel has no effect when called by a
+// listener with PriorityMonitor.
+func (ctx *Context) Uncancel() {
+	if !ctx.frozen {
+		ctx.cancel = false
+	}
 }
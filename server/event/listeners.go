@@ -0,0 +1,110 @@
+package event
+
+import (
+	"sort"
+	"sync"
+)
+
+// Priority is the priority with which a listener is called. Listeners with a lower Priority are called first, so
+// that listeners with a higher Priority have the final say over the outcome of an event.
+type Priority int
+
+const (
+	// PriorityLowest is the Priority of listeners that should be called before any other listeners.
+	PriorityLowest Priority = iota
+	// PriorityLow is the Priority of listeners that should be called before most other listeners.
+	PriorityLow
+	// PriorityNormal is the default Priority of listeners.
+	PriorityNormal
+	// PriorityHigh is the Priority of listeners that should be called after most other listeners.
+	PriorityHigh
+	// PriorityHighest is the Priority of listeners that should have the final say over the outcome of an event.
+	PriorityHighest
+	// PriorityMonitor is the Priority of listeners that only observe the outcome of an event. Monitor listeners are
+	// called last and cannot cancel or uncancel the Context of the event. They should not change the event in any
+	// other way either.
+	PriorityMonitor
+)
+
+// Listeners holds a list of listeners of type H, ordered by their Priority. Listeners with the same Priority are
+// called in the order in which they were added. A zero Listeners is ready for use and is safe for concurrent use.
+type Listeners[H any] struct {
+	mu     sync.RWMutex
+	l      []listener[H]
+	nextID uint64
+}
+
+// listener is a single listener held by Listeners.
+type listener[H any] struct {
+	h  H
+	p  Priority
+	id uint64
+}
+
+// Add adds a listener h with the Priority p. The function returned removes the listener again. Calling it more than
+// once has no effect.
+func (l *Listeners[H]) Add(h H, p Priority) (remove func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	id := l.nextID
+	l.nextID++
+
+	// Insert the listener after all listeners with the same or a lower priority, so that the list remains sorted.
+	i := sort.Search(len(l.l), func(i int) bool { return l.l[i].p > p })
+	// Create a new slice so that calls currently iterating over the old one are not affected.
+	n := make([]listener[H], len(l.l)+1)
+	copy(n, l.l[:i])
+	n[i] = listener[H]{h: h, p: p, id: id}
+	copy(n[i+1:], l.l[i:])
+	l.l = n
+
+	return func() { l.remove(id) }
+}
+
+// remove removes the listener with the ID passed.
+func (l *Listeners[H]) remove(id uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, lis := range l.l {
+		if lis.id == id {
+			// Create a new slice so that calls currently iterating over the old one are not affected.
+			l.l = append(l.l[:i:i], l.l[i+1:]...)
+			return
+		}
+	}
+}
+
+// Clear removes all listeners.
+func (l *Listeners[H]) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.l = nil
+}
+
+// Len returns the amount of listeners currently added.
+func (l *Listeners[H]) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.l)
+}
+
+// Call calls f for every listener, ordered by Priority. The Context passed is shared by all listeners, so that a
+// listener may check if the event was cancelled by a listener with a lower Priority using ctx.Cancelled(), and may
+// cancel or uncancel the event itself. Before listeners with PriorityMonitor are called, the Context is frozen so
+// that its state can no longer be changed. ctx may be nil for events that cannot be cancelled.
+func (l *Listeners[H]) Call(ctx *Context, f func(h H)) {
+	l.mu.RLock()
+	listeners := l.l
+	l.mu.RUnlock()
+
+	for _, lis := range listeners {
+		if lis.p == PriorityMonitor && ctx != nil {
+			ctx.frozen = true
+		}
+		f(lis.h)
+	}
+	if ctx != nil {
+		ctx.frozen = false
+	}
+}
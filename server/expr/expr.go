@@ -0,0 +1,75 @@
+// Package expr implements a small expression language used to tune gameplay from configuration, such as
+// 'damage * 0.5' or 'min(amount * 2, 100)'. Expressions operate on numbers only: comparisons and logical operators
+// produce 1 for true and 0 for false, and any value other than 0 is considered true.
+//
+// The following are supported, in order of increasing precedence:
+//
+//	c ? a : b        conditional
+//	||               logical or
+//	&&               logical and
+//	== != < <= > >=  comparison
+//	+ -              addition and subtraction
+//	* / %            multiplication, division and remainder
+//	- + !            unary minus, plus and logical not
+//	^                exponentiation (right associative)
+//
+// The functions abs, ceil, floor, round, sqrt, min, max, pow and clamp(x, min, max) may be called as well.
+package expr
+
+import (
+	"math"
+)
+
+// Vars holds the values of the variables used to evaluate an Expr, by name.
+type Vars map[string]float64
+
+// Expr is a parsed expression. An Expr may be evaluated any number of times, also from multiple goroutines at the
+// same time. Expr values may be obtained by calling Parse or MustParse.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Parse parses the expression passed. If variable names are passed, the expression may only use those variables,
+// and an error is returned if it uses any other variable. If no names are passed, any variable may be used.
+func Parse(s string, vars ...string) (*Expr, error) {
+	p := &parser{lex: lexer{src: s}, vars: vars}
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Expr{src: s, root: root}, nil
+}
+
+// MustParse parses the expression passed like Parse, but panics if the expression could not be parsed.
+func MustParse(s string, vars ...string) *Expr {
+	e, err := Parse(s, vars...)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Eval evaluates the Expr using the variables passed. Variables used by the Expr that are not present in vars have
+// a value of 0.
+func (e *Expr) Eval(vars Vars) float64 {
+	return e.root.eval(vars)
+}
+
+// EvalOr evaluates the Expr using the variables passed and returns the result. If e is nil or if the result is not a
+// finite number, for example because of a division by zero, def is returned instead. EvalOr may therefore be used
+// to evaluate optional expressions, falling back to a default value.
+func (e *Expr) EvalOr(def float64, vars Vars) float64 {
+	if e == nil {
+		return def
+	}
+	if v := e.Eval(vars); !math.IsNaN(v) && !math.IsInf(v, 0) {
+		return v
+	}
+	return def
+}
+
+// String returns the source of the Expr, as it was passed to Parse.
+func (e *Expr) String() string {
+	return e.src
+}
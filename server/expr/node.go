@@ -0,0 +1,148 @@
+package expr
+
+import (
+	"math"
+)
+
+// node is a node in the tree of a parsed expression.
+type node interface {
+	eval(vars Vars) float64
+}
+
+// number is a numeric literal.
+type number float64
+
+func (n number) eval(Vars) float64 { return float64(n) }
+
+// variable is a reference to a variable, looked up by its name when evaluated.
+type variable string
+
+func (v variable) eval(vars Vars) float64 { return vars[string(v)] }
+
+// unary is an operator applied to a single operand.
+type unary struct {
+	op rune
+	x  node
+}
+
+func (u unary) eval(vars Vars) float64 {
+	x := u.x.eval(vars)
+	switch u.op {
+	case '-':
+		return -x
+	case '!':
+		return boolean(x == 0)
+	}
+	return x
+}
+
+// binary is an operator applied to two operands.
+type binary struct {
+	op   string
+	x, y node
+}
+
+func (b binary) eval(vars Vars) float64 {
+	x := b.x.eval(vars)
+	// The logical operators short-circuit, so that the right operand is only evaluated if needed.
+	switch b.op {
+	case "&&":
+		return boolean(x != 0 && b.y.eval(vars) != 0)
+	case "||":
+		return boolean(x != 0 || b.y.eval(vars) != 0)
+	}
+	y := b.y.eval(vars)
+	switch b.op {
+	case "+":
+		return x + y
+	case "-":
+		return x - y
+	case "*":
+		return x * y
+	case "/":
+		return x / y
+	case "%":
+		return math.Mod(x, y)
+	case "^":
+		return math.Pow(x, y)
+	case "==":
+		return boolean(x == y)
+	case "!=":
+		return boolean(x != y)
+	case "<":
+		return boolean(x < y)
+	case "<=":
+		return boolean(x <= y)
+	case ">":
+		return boolean(x > y)
+	case ">=":
+		return boolean(x >= y)
+	}
+	panic("should never happen")
+}
+
+// conditional evaluates to a if cond is true and to b otherwise.
+type conditional struct {
+	cond, a, b node
+}
+
+func (c conditional) eval(vars Vars) float64 {
+	if c.cond.eval(vars) != 0 {
+		return c.a.eval(vars)
+	}
+	return c.b.eval(vars)
+}
+
+// call is a call to one of the functions in funcs.
+type call struct {
+	f    function
+	args []node
+}
+
+func (c call) eval(vars Vars) float64 {
+	args := make([]float64, len(c.args))
+	for i, arg := range c.args {
+		args[i] = arg.eval(vars)
+	}
+	return c.f.f(args)
+}
+
+// function is a function that may be called in an expression. A function with a negative number of arguments is
+// variadic and must be called with at least one argument.
+type function struct {
+	args int
+	f    func(args []float64) float64
+}
+
+// funcs holds all functions that may be called in an expression, by name.
+var funcs = map[string]function{
+	"abs":   {args: 1, f: func(a []float64) float64 { return math.Abs(a[0]) }},
+	"ceil":  {args: 1, f: func(a []float64) float64 { return math.Ceil(a[0]) }},
+	"floor": {args: 1, f: func(a []float64) float64 { return math.Floor(a[0]) }},
+	"round": {args: 1, f: func(a []float64) float64 { return math.Round(a[0]) }},
+	"sqrt":  {args: 1, f: func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"pow":   {args: 2, f: func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"clamp": {args: 3, f: func(a []float64) float64 { return math.Max(a[1], math.Min(a[2], a[0])) }},
+	"min": {args: -1, f: func(a []float64) float64 {
+		v := a[0]
+		for _, x := range a[1:] {
+			v = math.Min(v, x)
+		}
+		return v
+	}},
+	"max": {args: -1, f: func(a []float64) float64 {
+		v := a[0]
+		for _, x := range a[1:] {
+			v = math.Max(v, x)
+		}
+		return v
+	}},
+}
+
+// boolean converts a bool to 1 if true and 0 if false.
+func boolean(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
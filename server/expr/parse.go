@@ -0,0 +1,296 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind is the kind of token produced by a lexer.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOp
+)
+
+// token is a single token in the source of an expression.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// String returns a description of the token used in errors.
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at position %v", t.text, t.pos+1)
+}
+
+// operators holds all operators recognised by the lexer. Operators of two characters come first, so that they are
+// matched before the operators of one character that they start with.
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "+", "-", "*", "/", "%", "^", "!", "<", ">", "?", ":", "(", ")", ","}
+
+// lexer splits the source of an expression into tokens.
+type lexer struct {
+	src string
+	pos int
+}
+
+// next returns the next token in the source, or an error if the source holds an invalid character.
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) && (l.src[l.pos] == ' ' || l.src[l.pos] == '\t') {
+		l.pos++
+	}
+	start := l.pos
+	if start == len(l.src) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+	r, size := utf8.DecodeRuneInString(l.src[start:])
+	switch {
+	case isDigit(l.src[start]) || l.src[start] == '.':
+		for l.pos < len(l.src) && (isDigit(l.src[l.pos]) || l.src[l.pos] == '.') {
+			l.pos++
+		}
+		return token{kind: tokenNumber, text: l.src[start:l.pos], pos: start}, nil
+	case unicode.IsLetter(r) || r == '_':
+		l.pos += size
+		for l.pos < len(l.src) {
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+				break
+			}
+			l.pos += size
+		}
+		return token{kind: tokenIdent, text: l.src[start:l.pos], pos: start}, nil
+	}
+	for _, op := range operators {
+		if len(l.src)-start >= len(op) && l.src[start:start+len(op)] == op {
+			l.pos += len(op)
+			return token{kind: tokenOp, text: op, pos: start}, nil
+		}
+	}
+	return token{}, fmt.Errorf("unexpected character %q at position %v", r, start+1)
+}
+
+// isDigit checks if the byte passed is an ASCII digit.
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// parser parses the tokens produced by a lexer into a tree of nodes.
+type parser struct {
+	lex  lexer
+	tok  token
+	vars []string
+}
+
+// parse parses the full source of the expression.
+func (p *parser) parse() (node, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenEOF {
+		return nil, fmt.Errorf("empty expression")
+	}
+	n, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %v", p.tok)
+	}
+	return n, nil
+}
+
+// advance moves on to the next token.
+func (p *parser) advance() (err error) {
+	p.tok, err = p.lex.next()
+	return err
+}
+
+// is checks if the current token is the operator passed.
+func (p *parser) is(op string) bool {
+	return p.tok.kind == tokenOp && p.tok.text == op
+}
+
+// expect advances past the current token if it is the operator passed, or returns an error if it is not.
+func (p *parser) expect(op string) error {
+	if !p.is(op) {
+		return fmt.Errorf("expected %q, got %v", op, p.tok)
+	}
+	return p.advance()
+}
+
+// conditional parses a conditional expression in the form 'c ? a : b', or any expression of higher precedence.
+func (p *parser) conditional() (node, error) {
+	cond, err := p.binary(0)
+	if err != nil || !p.is("?") {
+		return cond, err
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	a, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	b, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+	return conditional{cond: cond, a: a, b: b}, nil
+}
+
+// precedence holds the binary operators that are left associative, grouped by precedence from low to high.
+var precedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// binary parses a chain of left associative binary operators with the precedence level passed or higher.
+func (p *parser) binary(level int) (node, error) {
+	if level == len(precedence) {
+		return p.unary()
+	}
+	x, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.operator(precedence[level])
+		if !ok {
+			return x, nil
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		y, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = binary{op: op, x: x, y: y}
+	}
+}
+
+// operator returns the current token if it is one of the operators passed.
+func (p *parser) operator(ops []string) (string, bool) {
+	for _, op := range ops {
+		if p.is(op) {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// unary parses an expression prefixed with a unary operator, or an exponentiation.
+func (p *parser) unary() (node, error) {
+	if op, ok := p.operator([]string{"-", "+", "!"}); ok {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op: rune(op[0]), x: x}, nil
+	}
+	x, err := p.primary()
+	if err != nil || !p.is("^") {
+		return x, err
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	// Exponentiation is right associative and binds stronger than unary operators on its left, so that '-2^2'
+	// evaluates to -4, but not on its right, so that '2^-1' is valid.
+	y, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return binary{op: "^", x: x, y: y}, nil
+}
+
+// primary parses a number, variable, function call or parenthesised expression.
+func (p *parser) primary() (node, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokenNumber:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %v", tok)
+		}
+		return number(v), p.advance()
+	case tok.kind == tokenIdent:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.is("(") {
+			return p.call(tok)
+		}
+		return p.variable(tok)
+	case p.is("("):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		x, err := p.conditional()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expect(")")
+	}
+	return nil, fmt.Errorf("unexpected %v", tok)
+}
+
+// variable returns a variable node for the identifier passed, checking if the variable may be used.
+func (p *parser) variable(tok token) (node, error) {
+	if len(p.vars) == 0 {
+		return variable(tok.text), nil
+	}
+	for _, name := range p.vars {
+		if name == tok.text {
+			return variable(tok.text), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown variable %v: expected one of %v", tok, p.vars)
+}
+
+// call parses the arguments of a call to the function with the name passed. The current token is the opening
+// parenthesis.
+func (p *parser) call(name token) (node, error) {
+	f, ok := funcs[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %v", name)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	var args []node
+	for !p.is(")") {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.conditional()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if (f.args >= 0 && len(args) != f.args) || len(args) == 0 {
+		return nil, fmt.Errorf("wrong number of arguments to function %v: got %v", name, len(args))
+	}
+	return call{f: f, args: args}, p.advance()
+}
@@ -0,0 +1,151 @@
+// Package i18n implements the translation of messages sent by the server into the language of a player. Translations
+// are loaded from per-locale JSON or YAML files, such as 'en_US.json' or 'de_DE.yaml', that map translation keys to
+// messages. Messages may contain '%s' or positional '%1$s' placeholders, which are replaced with the arguments
+// passed when translating, in the same way as the client's own translations.
+//
+// Keys that are not found in the server-side translations are left to the client, which translates them using its
+// own language files if it knows them. See player.Player.Messaget for an example.
+package i18n
+
+import (
+	"fmt"
+	"golang.org/x/text/language"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Global holds the server-side translations used by default, such as by player.Player.Messaget. Translations may
+// be added to it using Bundle.Load, Bundle.LoadDir or Bundle.Add.
+var Global = NewBundle(language.AmericanEnglish)
+
+// Bundle holds translations for any number of locales. A Bundle is safe for concurrent use.
+type Bundle struct {
+	fallback language.Tag
+
+	mu           sync.RWMutex
+	translations map[language.Tag]map[string]string
+	tags         []language.Tag
+	matcher      language.Matcher
+}
+
+// NewBundle returns an empty Bundle. Messages that are not translated for the locale of a player are translated
+// using the fallback locale passed instead.
+func NewBundle(fallback language.Tag) *Bundle {
+	return &Bundle{fallback: fallback, translations: map[language.Tag]map[string]string{}}
+}
+
+// Add adds the translations passed, which map translation keys to messages, to the locale passed. Existing
+// translations of the same keys are overwritten.
+func (b *Bundle) Add(locale language.Tag, translations map[string]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m, ok := b.translations[locale]
+	if !ok {
+		m = make(map[string]string, len(translations))
+		b.translations[locale] = m
+		b.tags = append(b.tags, locale)
+		b.matcher = language.NewMatcher(b.tags)
+	}
+	for k, v := range translations {
+		m[k] = v
+	}
+}
+
+// Locales returns all locales that the Bundle holds translations for.
+func (b *Bundle) Locales() []language.Tag {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]language.Tag(nil), b.tags...)
+}
+
+// Has checks if the Bundle holds a translation of the key passed for any locale.
+func (b *Bundle) Has(key string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, m := range b.translations {
+		if _, ok := m[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Translate translates the key passed into the locale passed, replacing placeholders in the message with the
+// arguments passed. If the Bundle holds no translations for the locale, the closest locale, such as one of the same
+// language in a different region, is used. If the key is not translated in that locale either, the fallback locale
+// of the Bundle is used. Translate returns false if the key could not be translated at all.
+func (b *Bundle) Translate(locale language.Tag, key string, a ...any) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if len(b.tags) == 0 {
+		return "", false
+	}
+	if _, ok := b.translations[locale]; !ok {
+		_, i, confidence := b.matcher.Match(locale)
+		locale = b.fallback
+		if confidence != language.No {
+			locale = b.tags[i]
+		}
+	}
+	msg, ok := b.translations[locale][key]
+	if !ok {
+		if msg, ok = b.translations[b.fallback][key]; !ok {
+			return "", false
+		}
+	}
+	return Format(msg, Args(a...)...), true
+}
+
+// Args converts the arguments passed to the strings that they are formatted as in a translation.
+func Args(a ...any) []string {
+	s := make([]string, len(a))
+	for i, arg := range a {
+		s[i] = fmt.Sprint(arg)
+	}
+	return s
+}
+
+// Format replaces the placeholders in the message passed with the arguments passed. '%s' and '%d' are replaced with
+// the next argument and '%1$s' with the first argument. '%%' is replaced with a single '%'. Placeholders without a
+// matching argument are left unchanged.
+func Format(msg string, args ...string) string {
+	if !strings.Contains(msg, "%") {
+		return msg
+	}
+	var (
+		sb   strings.Builder
+		next int
+	)
+	for i := 0; i < len(msg); i++ {
+		if msg[i] != '%' || i+1 >= len(msg) {
+			sb.WriteByte(msg[i])
+			continue
+		}
+		rest := msg[i+1:]
+		switch {
+		case rest[0] == '%':
+			sb.WriteByte('%')
+			i++
+			continue
+		case rest[0] == 's' || rest[0] == 'd':
+			if next < len(args) {
+				sb.WriteString(args[next])
+				next++
+				i++
+				continue
+			}
+		default:
+			// Positional placeholders, such as %1$s.
+			if end := strings.IndexByte(rest, '$'); end > 0 && end+1 < len(rest) && (rest[end+1] == 's' || rest[end+1] == 'd') {
+				if n, err := strconv.Atoi(rest[:end]); err == nil && n >= 1 && n <= len(args) {
+					sb.WriteString(args[n-1])
+					i += end + 2
+					continue
+				}
+			}
+		}
+		sb.WriteByte('%')
+	}
+	return sb.String()
+}
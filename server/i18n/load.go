@@ -0,0 +1,177 @@
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/text/language"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LoadDir loads all translation files in the directory passed into the Bundle. Files must be named after the
+// locale they hold, such as 'en_US.json' or 'de_DE.yaml'. Files with other extensions are ignored. If the directory
+// does not exist, LoadDir does nothing.
+func (b *Bundle) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("read translation directory: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch filepath.Ext(e.Name()) {
+		case ".json", ".yaml", ".yml":
+			if err := b.Load(filepath.Join(dir, e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Load loads the translation file at the path passed into the Bundle. The name of the file, without extension, must
+// be the locale that it holds, such as 'en_US'. JSON files must hold an object of which the values are messages or
+// nested objects, and YAML files a mapping of the same form. Keys of nested objects are joined with a dot, so that
+// {"commands": {"ban": "..."}} holds the key 'commands.ban'.
+func (b *Bundle) Load(path string) error {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	locale, err := language.Parse(strings.ReplaceAll(name, "_", "-"))
+	if err != nil {
+		return fmt.Errorf("load translations %v: invalid locale %q: %w", path, name, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("load translations %v: %w", path, err)
+	}
+	translations := map[string]string{}
+	if filepath.Ext(path) == ".json" {
+		err = parseJSON(data, translations)
+	} else {
+		err = parseYAML(data, translations)
+	}
+	if err != nil {
+		return fmt.Errorf("load translations %v: %w", path, err)
+	}
+	b.Add(locale, translations)
+	return nil
+}
+
+// parseJSON parses a JSON object of translations into m.
+func parseJSON(data []byte, m map[string]string) error {
+	var v map[string]any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return flatten("", v, m)
+}
+
+// flatten adds all messages in the object passed to m, prefixing their keys with prefix.
+func flatten(prefix string, v map[string]any, m map[string]string) error {
+	for k, val := range v {
+		switch val := val.(type) {
+		case string:
+			m[prefix+k] = val
+		case map[string]any:
+			if err := flatten(prefix+k+".", val, m); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("translation %v: expected string or object, got %T", prefix+k, val)
+		}
+	}
+	return nil
+}
+
+// parseYAML parses a YAML mapping of translations into m. Only the subset of YAML needed for translation files is
+// supported: nested mappings, comments and plain, single-quoted or double-quoted scalars on a single line.
+func parseYAML(data []byte, m map[string]string) error {
+	type level struct {
+		indent int
+		prefix string
+	}
+	var (
+		stack = []level{{indent: -1}}
+		s     = bufio.NewScanner(bytes.NewReader(data))
+		line  int
+	)
+	for s.Scan() {
+		line++
+		text := strings.TrimRight(s.Text(), " \t\r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || trimmed[0] == '#' || trimmed == "---" {
+			continue
+		}
+		indent := len(text) - len(trimmed)
+		for indent <= stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		key, value, ok := splitYAML(trimmed)
+		if !ok {
+			return fmt.Errorf("line %v: expected 'key: value'", line)
+		}
+		k, err := unquoteYAML(key)
+		if err != nil {
+			return fmt.Errorf("line %v: %w", line, err)
+		}
+		prefix := stack[len(stack)-1].prefix + k
+		if value == "" {
+			// A nested mapping follows on the next lines.
+			stack = append(stack, level{indent: indent, prefix: prefix + "."})
+			continue
+		}
+		if m[prefix], err = unquoteYAML(value); err != nil {
+			return fmt.Errorf("line %v: %w", line, err)
+		}
+	}
+	return s.Err()
+}
+
+// splitYAML splits a 'key: value' line into its key and value. Only a colon followed by a space or at the end of
+// the line separates the key from the value, so that keys such as 'item.minecraft:stone' are supported. Colons
+// within a quoted key are never treated as the separator.
+func splitYAML(line string) (key, value string, ok bool) {
+	start := 0
+	if line[0] == '"' || line[0] == '\'' {
+		end := strings.IndexByte(line[1:], line[0])
+		if end == -1 {
+			return "", "", false
+		}
+		start = end + 2
+	}
+	if i := strings.Index(line[start:], ": "); i != -1 {
+		return line[:start+i], strings.TrimSpace(line[start+i+1:]), true
+	}
+	if strings.HasSuffix(line[start:], ":") {
+		return line[:len(line)-1], "", true
+	}
+	return "", "", false
+}
+
+// unquoteYAML returns the value of a YAML scalar, removing quotes and trailing comments.
+func unquoteYAML(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		end := strings.LastIndexByte(s, '"')
+		if end == 0 {
+			return "", fmt.Errorf("unterminated string %v", s)
+		}
+		return strconv.Unquote(s[:end+1])
+	case strings.HasPrefix(s, "'"):
+		end := strings.LastIndexByte(s, '\'')
+		if end == 0 {
+			return "", fmt.Errorf("unterminated string %v", s)
+		}
+		return strings.ReplaceAll(s[1:end], "''", "'"), nil
+	}
+	if i := strings.Index(s, " #"); i != -1 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s, nil
+}
@@ -0,0 +1,237 @@
+// Package instance implements lightweight copies of a template world, such as dungeons or parkour courses, that are
+// created for a single player or party. The template is shared by all instances and never changed: Every instance
+// keeps the changes made to it in memory, discarding them once the instance is closed. Instances are closed
+// automatically once no players have been in them for some time.
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"github.com/df-mc/atomic"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/diff"
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// Config holds the settings of a Manager. Calling Config.New() creates a Manager.
+type Config struct {
+	// Template is the world.Provider holding the template world that instances are copies of. Template is only ever
+	// read from and is not closed by the Manager. Template must not be nil.
+	Template world.Provider
+	// Dim is the world.Dimension of instances. If nil, Dim is set to world.Overworld.
+	Dim world.Dimension
+	// Generator is the world.Generator used to generate chunks that are not present in the Template. If nil, these
+	// chunks are left empty.
+	Generator world.Generator
+	// Log is the Logger used by the worlds of instances. If nil, a Logrus logger is used.
+	Log world.Logger
+	// EmptyTimeout is the time that no players may be in an instance before it is closed. If left as 0,
+	// EmptyTimeout is set to 30 seconds. If negative, instances are only closed by calling Instance.Close.
+	EmptyTimeout time.Duration
+	// PortalDestination is passed to the world.Config of instances. It may be used to return players to another
+	// world when respawning or entering a portal. If nil, players respawn in the instance itself.
+	PortalDestination func(dim world.Dimension) *world.World
+	// Entities is the world.EntityRegistry of the worlds of instances. Entities will default to
+	// entity.DefaultRegistry.
+	Entities world.EntityRegistry
+}
+
+// Manager creates instances of a template world and closes them once they are empty.
+type Manager struct {
+	conf Config
+	ids  atomic.Uint64
+
+	mu        sync.Mutex
+	instances map[*Instance]struct{}
+	closed    bool
+
+	closing chan struct{}
+	done    chan struct{}
+}
+
+// New creates a Manager using the settings in the Config. Close must be called to close all instances and stop the
+// Manager.
+func (conf Config) New() (*Manager, error) {
+	if conf.Template == nil {
+		return nil, errors.New("new instance manager: template must not be nil")
+	}
+	if conf.Dim == nil {
+		conf.Dim = world.Overworld
+	}
+	if conf.Log == nil {
+		conf.Log = logrus.New()
+	}
+	if len(conf.Entities.Types()) == 0 {
+		conf.Entities = entity.DefaultRegistry
+	}
+	if conf.EmptyTimeout == 0 {
+		conf.EmptyTimeout = time.Second * 30
+	}
+	m := &Manager{
+		conf:      conf,
+		instances: make(map[*Instance]struct{}),
+		closing:   make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if conf.EmptyTimeout > 0 {
+		go m.closeEmpty()
+	} else {
+		close(m.done)
+	}
+	return m, nil
+}
+
+// Create creates a new Instance of the template world. The Instance starts out empty: Players may be added to it
+// using Instance.Add.
+func (m *Manager) Create() (*Instance, error) {
+	prov, err := diff.Config{Base: m.conf.Template, Log: m.conf.Log, Entities: m.conf.Entities}.New()
+	if err != nil {
+		return nil, fmt.Errorf("create instance: %w", err)
+	}
+	i := &Instance{m: m, id: m.ids.Inc(), lastActive: time.Now(), closed: make(chan struct{})}
+	i.w = world.Config{
+		Log:               m.conf.Log,
+		Dim:               m.conf.Dim,
+		Provider:          prov,
+		Generator:         m.conf.Generator,
+		PortalDestination: m.conf.PortalDestination,
+		Entities:          m.conf.Entities,
+		// Changes are kept in memory by the provider and discarded once the instance is closed, so there is no
+		// use in saving them periodically.
+		SaveInterval: -1,
+	}.New()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		_ = i.w.Close()
+		return nil, errors.New("create instance: manager closed")
+	}
+	m.instances[i] = struct{}{}
+	return i, nil
+}
+
+// Instances returns all instances of the Manager that are not yet closed.
+func (m *Manager) Instances() []*Instance {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	instances := make([]*Instance, 0, len(m.instances))
+	for i := range m.instances {
+		instances = append(instances, i)
+	}
+	return instances
+}
+
+// Close closes all instances of the Manager and stops closing empty instances. Players still in an instance when
+// it is closed should be moved to another world first.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	close(m.closing)
+	m.mu.Unlock()
+	<-m.done
+
+	for _, i := range m.Instances() {
+		_ = i.Close()
+	}
+	return nil
+}
+
+// closeEmpty runs until the Manager is closed, closing instances that have been empty for Config.EmptyTimeout.
+func (m *Manager) closeEmpty() {
+	defer close(m.done)
+	interval := m.conf.EmptyTimeout / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			now := time.Now()
+			for _, i := range m.Instances() {
+				if len(i.Players()) != 0 {
+					i.lastActive = now
+				} else if now.Sub(i.lastActive) >= m.conf.EmptyTimeout {
+					_ = i.Close()
+				}
+			}
+		case <-m.closing:
+			return
+		}
+	}
+}
+
+// Instance is a copy of a template world created by a Manager. Changes made to the world of an Instance do not
+// affect the template or other instances.
+type Instance struct {
+	m  *Manager
+	id uint64
+	w  *world.World
+
+	// lastActive is the last time at which players were found in the Instance. It is only used by the goroutine
+	// closing empty instances.
+	lastActive time.Time
+
+	once   sync.Once
+	closed chan struct{}
+}
+
+// ID returns the ID of the Instance, which is unique among the instances created by the same Manager.
+func (i *Instance) ID() uint64 {
+	return i.id
+}
+
+// World returns the world of the Instance.
+func (i *Instance) World() *world.World {
+	return i.w
+}
+
+// Add moves the player passed to the Instance, teleporting it to the position passed.
+func (i *Instance) Add(p *player.Player, pos mgl64.Vec3) {
+	i.w.AddEntity(p)
+	p.Teleport(pos)
+}
+
+// Players returns all players currently in the Instance.
+func (i *Instance) Players() []*player.Player {
+	var players []*player.Player
+	for _, e := range i.w.Entities() {
+		if p, ok := e.(*player.Player); ok {
+			players = append(players, p)
+		}
+	}
+	return players
+}
+
+// Done returns a channel that is closed once the Instance is closed, either by calling Close or because it was
+// empty for too long.
+func (i *Instance) Done() <-chan struct{} {
+	return i.closed
+}
+
+// Close closes the world of the Instance, discarding all changes made to it. Players still in the Instance should
+// be moved to another world first.
+func (i *Instance) Close() error {
+	var err error
+	i.once.Do(func() {
+		i.m.mu.Lock()
+		delete(i.m.instances, i)
+		i.m.mu.Unlock()
+
+		err = i.w.Close()
+		close(i.closed)
+	})
+	return err
+}
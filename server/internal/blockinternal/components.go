@@ -0,0 +1,148 @@
+package blockinternal
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/customblock"
+	"github.com/df-mc/dragonfly/server/item/category"
+	"github.com/df-mc/dragonfly/server/world"
+	"strings"
+)
+
+// Properties returns the properties of the custom block passed as sent to the client in the block palette. These
+// hold the components of the block, its permutations, its properties and the creative inventory category that it
+// is listed under. id is the numerical ID of the block.
+func Properties(b world.CustomBlock, id int32) map[string]any {
+	identifier, _ := b.EncodeBlock()
+	props := b.Properties()
+
+	components := encodeComponents(identifier, props)
+	components["minecraft:display_name"] = map[string]any{"value": b.Name()}
+	if x, ok := b.(interface{ LightEmissionLevel() uint8 }); ok && x.LightEmissionLevel() > 0 {
+		components["minecraft:light_emission"] = map[string]any{"emission": x.LightEmissionLevel()}
+	}
+	if x, ok := b.(interface{ LightDiffusionLevel() uint8 }); ok {
+		components["minecraft:light_dampening"] = map[string]any{"lightLevel": x.LightDiffusionLevel()}
+	}
+	if x, ok := b.(block.Breakable); ok {
+		components["minecraft:destructible_by_mining"] = map[string]any{"value": float32(x.BreakInfo().Hardness)}
+	}
+
+	permutations := make([]map[string]any, 0, len(props.Permutations))
+	for _, p := range props.Permutations {
+		permutations = append(permutations, map[string]any{
+			"condition":  p.Condition,
+			"components": encodeComponents(identifier, p.Properties),
+		})
+	}
+	properties := make([]map[string]any, 0)
+	for _, p := range world.CustomBlockProperties(identifier) {
+		properties = append(properties, map[string]any{"name": p.Name, "enum": enum(p.Values)})
+	}
+
+	c := props.Category
+	if c == (category.Category{}) {
+		c = category.Construction()
+	}
+	return map[string]any{
+		"components":    components,
+		"menu_category": map[string]any{"category": c.String(), "group": c.Group()},
+		"molangVersion": int32(1),
+		"permutations":  permutations,
+		"properties":    properties,
+		"vanilla_block_data": map[string]any{
+			"block_id": id,
+		},
+	}
+}
+
+// TextureName returns the name of the texture of a Material of a custom block with the identifier passed, applied
+// to the face or bone passed.
+func TextureName(identifier, target string) string {
+	if target == "*" {
+		target = "all"
+	}
+	return strings.ReplaceAll(identifier, ":", "_") + "_" + target
+}
+
+// encodeComponents encodes the customblock.Properties passed into the components sent to the client. Components
+// of which the properties hold zero values are not encoded.
+func encodeComponents(identifier string, props customblock.Properties) map[string]any {
+	components := map[string]any{}
+	if props.Geometry != "" {
+		components["minecraft:geometry"] = map[string]any{"identifier": props.Geometry}
+	}
+	if len(props.Textures) > 0 {
+		materials := make(map[string]any, len(props.Textures))
+		for target, m := range props.Textures {
+			materials[target] = map[string]any{
+				"texture":           TextureName(identifier, target),
+				"render_method":     m.RenderMethod.String(),
+				"face_dimming":      m.FaceDimming,
+				"ambient_occlusion": m.AmbientOcclusion,
+			}
+		}
+		components["minecraft:material_instances"] = map[string]any{
+			"mappings":  map[string]any{},
+			"materials": materials,
+		}
+	}
+	if props.NoCollision {
+		components["minecraft:collision_box"] = map[string]any{"enabled": false, "origin": []float32{-8, 0, -8}, "size": []float32{16, 16, 16}}
+	} else if props.CollisionBox != (cube.BBox{}) {
+		components["minecraft:collision_box"] = encodeBox(props.CollisionBox)
+	}
+	if props.SelectionBox != (cube.BBox{}) {
+		components["minecraft:selection_box"] = encodeBox(props.SelectionBox)
+	}
+	if props.Rotation != (cube.Pos{}) || props.Translation.Len() != 0 || props.Scale.Len() != 0 {
+		scale := props.Scale
+		if scale.Len() == 0 {
+			scale[0], scale[1], scale[2] = 1, 1, 1
+		}
+		components["minecraft:transformation"] = map[string]any{
+			"RX": int32(props.Rotation[0] / 90), "RY": int32(props.Rotation[1] / 90), "RZ": int32(props.Rotation[2] / 90),
+			"SX": float32(scale[0]), "SY": float32(scale[1]), "SZ": float32(scale[2]),
+			"TX": float32(props.Translation[0]), "TY": float32(props.Translation[1]), "TZ": float32(props.Translation[2]),
+		}
+	}
+	return components
+}
+
+// encodeBox encodes a cube.BBox relative to the position of a block into a box as used by the collision and
+// selection box components, which is in pixels and relative to the bottom centre of the block.
+func encodeBox(box cube.BBox) map[string]any {
+	min, size := box.Min(), box.Max().Sub(box.Min())
+	return map[string]any{
+		"enabled": true,
+		"origin":  []float32{float32(min[0]*16 - 8), float32(min[1] * 16), float32(min[2]*16 - 8)},
+		"size":    []float32{float32(size[0] * 16), float32(size[1] * 16), float32(size[2] * 16)},
+	}
+}
+
+// enum returns the values of a property of a custom block as a slice of a single type, so that it may be encoded
+// as an NBT list. Boolean values are encoded as bytes.
+func enum(values []any) any {
+	switch values[0].(type) {
+	case bool:
+		s := make([]uint8, len(values))
+		for i, v := range values {
+			if v.(bool) {
+				s[i] = 1
+			}
+		}
+		return s
+	case int32:
+		s := make([]int32, len(values))
+		for i, v := range values {
+			s[i] = v.(int32)
+		}
+		return s
+	default:
+		s := make([]string, len(values))
+		for i, v := range values {
+			s[i] = v.(string)
+		}
+		return s
+	}
+}
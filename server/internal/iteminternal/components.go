@@ -3,6 +3,7 @@ package iteminternal
 import (
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
 	"strings"
 )
 
@@ -37,9 +38,18 @@ func Components(it world.CustomItem) map[string]any {
 	}
 	if x, ok := it.(item.Consumable); ok {
 		builder.AddProperty("use_duration", int32(x.ConsumeDuration().Seconds()*20))
-		builder.AddComponent("minecraft:food", map[string]any{
+		food := map[string]any{
 			"can_always_eat": x.AlwaysConsumable(),
-		})
+		}
+		if y, ok := it.(item.Nourishing); ok {
+			nutrition, saturation := y.Nutrition()
+			food["nutrition"] = int32(nutrition)
+			if nutrition > 0 {
+				// The client computes the saturation restored as nutrition * saturation_modifier * 2.
+				food["saturation_modifier"] = float32(saturation / float64(nutrition) / 2)
+			}
+		}
+		builder.AddComponent("minecraft:food", food)
 
 		if y, ok := it.(item.Drinkable); ok && y.Drinkable() {
 			builder.AddProperty("use_animation", int32(2))
@@ -78,29 +88,62 @@ func Components(it world.CustomItem) map[string]any {
 	if x, ok := it.(item.HandEquipped); ok {
 		builder.AddProperty("hand_equipped", x.HandEquipped())
 	}
-	itemScale := calculateItemScale(it)
-	builder.AddComponent("minecraft:render_offsets", map[string]any{
-		"main_hand": map[string]any{
-			"first_person": map[string]any{
-				"scale": itemScale,
+	if x, ok := it.(item.RenderOffsetter); ok {
+		o := x.RenderOffsets()
+		builder.AddComponent("minecraft:render_offsets", map[string]any{
+			"main_hand": map[string]any{
+				"first_person": encodeRenderOffset(o.MainHandFirstPerson),
+				"third_person": encodeRenderOffset(o.MainHandThirdPerson),
 			},
-			"third_person": map[string]any{
-				"scale": itemScale,
+			"off_hand": map[string]any{
+				"first_person": encodeRenderOffset(o.OffHandFirstPerson),
+				"third_person": encodeRenderOffset(o.OffHandThirdPerson),
 			},
-		},
-		"off_hand": map[string]any{
-			"first_person": map[string]any{
-				"scale": itemScale,
+		})
+	} else {
+		itemScale := calculateItemScale(it)
+		builder.AddComponent("minecraft:render_offsets", map[string]any{
+			"main_hand": map[string]any{
+				"first_person": map[string]any{
+					"scale": itemScale,
+				},
+				"third_person": map[string]any{
+					"scale": itemScale,
+				},
 			},
-			"third_person": map[string]any{
-				"scale": itemScale,
+			"off_hand": map[string]any{
+				"first_person": map[string]any{
+					"scale": itemScale,
+				},
+				"third_person": map[string]any{
+					"scale": itemScale,
+				},
 			},
-		},
-	})
+		})
+	}
 
 	return builder.Construct()
 }
 
+// encodeRenderOffset encodes an item.RenderOffset for the minecraft:render_offsets component. A zero scale is
+// encoded as a scale of 1.
+func encodeRenderOffset(o item.RenderOffset) map[string]any {
+	scale := o.Scale
+	if scale.Len() == 0 {
+		scale = mgl64.Vec3{1, 1, 1}
+	}
+	return map[string]any{
+		"position": vec3(o.Position),
+		"rotation": vec3(o.Rotation),
+		"scale":    vec3(scale),
+	}
+}
+
+// vec3 converts an mgl64.Vec3 to a slice of float32s, so that it is encoded as an NBT list.
+func vec3(v mgl64.Vec3) []float32 {
+	return []float32{float32(v[0]), float32(v[1]), float32(v[2])}
+}
+
 // calculateItemScale calculates the scale of the item to be rendered to the player according to the given size.
 func calculateItemScale(it world.CustomItem) []float32 {
 	width := float32(it.Texture().Bounds().Dx())
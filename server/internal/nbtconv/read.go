@@ -8,6 +8,7 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
 	"golang.org/x/exp/constraints"
+	"strconv"
 	"time"
 )
 
@@ -196,7 +197,12 @@ func readItemStack(m, t map[string]any) item.Stack {
 	if blockItem, ok := Block(m, "Block").(world.Item); ok {
 		it = blockItem
 	}
-	if v, ok := world.ItemByName(String(m, "Name"), Int16(m, "Damage")); ok {
+	name := String(m, "Name")
+	if _, ok := m["Name"]; !ok {
+		// Items in worlds of very old versions are stored using a numeric ID, which may be registered as an alias.
+		name = strconv.Itoa(int(Int16(m, "id")))
+	}
+	if v, ok := world.ItemByName(name, Int16(m, "Damage")); ok {
 		it = v
 	}
 	if it == nil {
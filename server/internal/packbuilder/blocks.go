@@ -0,0 +1,81 @@
+package packbuilder
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/customblock"
+	"github.com/df-mc/dragonfly/server/internal/blockinternal"
+	"github.com/df-mc/dragonfly/server/world"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// buildBlocks builds all the block-related files for the resource pack. This includes textures, geometries,
+// language entries and the terrain texture atlas.
+func buildBlocks(dir string) (count int, lang []string) {
+	if err := os.MkdirAll(filepath.Join(dir, "textures/blocks"), os.ModePerm); err != nil {
+		panic(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "models/blocks"), os.ModePerm); err != nil {
+		panic(err)
+	}
+
+	textureData := make(map[string]any)
+	built := make(map[string]struct{})
+	for _, b := range world.CustomBlocks() {
+		identifier, _ := b.EncodeBlock()
+		if _, ok := built[identifier]; ok {
+			// Only the first state of a block is used, the others hold the same properties.
+			continue
+		}
+		built[identifier] = struct{}{}
+		lang = append(lang, fmt.Sprintf("tile.%s.name=%s", identifier, b.Name()))
+
+		props := b.Properties()
+		textures := []map[string]customblock.Material{props.Textures}
+		for _, p := range props.Permutations {
+			textures = append(textures, p.Textures)
+		}
+		for _, m := range textures {
+			for target, material := range m {
+				if material.Texture == nil {
+					// The texture is part of a different resource pack.
+					continue
+				}
+				name := blockinternal.TextureName(identifier, target)
+				textureData[name] = map[string]string{"textures": "textures/blocks/" + name}
+				buildTexture(filepath.Join(dir, "textures/blocks", name+".png"), material.Texture)
+			}
+		}
+		if len(props.GeometryData) > 0 {
+			name := strings.ReplaceAll(identifier, ":", "_") + ".geo.json"
+			if err := os.WriteFile(filepath.Join(dir, "models/blocks", name), props.GeometryData, 0666); err != nil {
+				panic(err)
+			}
+		}
+		count++
+	}
+
+	if count > 0 {
+		buildTerrainAtlas(dir, map[string]any{
+			"resource_pack_name": "vanilla",
+			"texture_name":       "atlas.terrain",
+			"padding":            8,
+			"num_mip_levels":     4,
+			"texture_data":       textureData,
+		})
+	}
+	return
+}
+
+// buildTerrainAtlas creates the identifier to texture mapping of blocks and writes it to the pack.
+func buildTerrainAtlas(dir string, atlas map[string]any) {
+	b, err := json.Marshal(atlas)
+	if err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "textures/terrain_texture.json"), b, 0666); err != nil {
+		panic(err)
+	}
+}
@@ -45,7 +45,12 @@ func buildItems(dir string) (count int, lang []string) {
 
 // buildItemTexture creates a PNG file for the item from the provided image and name and writes it to the pack.
 func buildItemTexture(dir, name string, img image.Image) {
-	texture, err := os.Create(filepath.Join(dir, "textures/items", name+".png"))
+	buildTexture(filepath.Join(dir, "textures/items", name+".png"), img)
+}
+
+// buildTexture encodes the image passed as PNG and writes it to the path passed.
+func buildTexture(path string, img image.Image) {
+	texture, err := os.Create(path)
 	if err != nil {
 		panic(err)
 	}
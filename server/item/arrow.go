@@ -9,6 +9,11 @@ type Arrow struct {
 	Tip potion.Potion
 }
 
+// OffHand ...
+func (Arrow) OffHand() bool {
+	return true
+}
+
 // EncodeItem ...
 func (a Arrow) EncodeItem() (name string, meta int16) {
 	if tip := a.Tip.Uint8(); tip > 4 {
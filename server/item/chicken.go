@@ -3,7 +3,6 @@ package item
 import (
 	"github.com/df-mc/dragonfly/server/entity/effect"
 	"github.com/df-mc/dragonfly/server/world"
-	"math/rand"
 	"time"
 )
 
@@ -16,12 +15,12 @@ type Chicken struct {
 }
 
 // Consume ...
-func (c Chicken) Consume(_ *world.World, co Consumer) Stack {
+func (c Chicken) Consume(w *world.World, co Consumer) Stack {
 	if c.Cooked {
 		co.Saturate(6, 7.2)
 	} else {
 		co.Saturate(2, 1.2)
-		if rand.Float64() < 0.3 {
+		if w.Rand().Float64() < 0.3 {
 			co.AddEffect(effect.New(effect.Hunger{}, 1, 30*time.Second))
 		}
 	}
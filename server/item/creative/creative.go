@@ -12,23 +12,19 @@ import (
 )
 
 // Items returns a list with all items that have been registered as a creative item. These items will
-// be accessible by players in-game who have creative mode enabled.
+// be accessible by players in-game who have creative mode enabled, unless a different Menu is set for them.
 func Items() []item.Stack {
-	return creativeItemStacks
+	return defaultMenu.Items()
 }
 
-// RegisterItem registers an item as a creative item, exposing it in the creative inventory.
+// RegisterItem registers an item as a creative item, exposing it in the creative inventory. It is added to the
+// Default Menu after all other entries without a group.
 func RegisterItem(item item.Stack) {
-	creativeItemStacks = append(creativeItemStacks, item)
+	defaultMenu.Add(item, "")
 }
 
-var (
-	//go:embed creative_items.nbt
-	creativeItemData []byte
-	// creativeItemStacks holds a list of all item stacks that were registered to the creative inventory using
-	// RegisterItem.
-	creativeItemStacks []item.Stack
-)
+//go:embed creative_items.nbt
+var creativeItemData []byte
 
 // creativeItemEntry holds data of a creative item as present in the creative inventory.
 type creativeItemEntry struct {
@@ -0,0 +1,117 @@
+package creative
+
+import (
+	"github.com/df-mc/dragonfly/server/item"
+	"sync"
+)
+
+// Entry is an entry of a Menu: an item.Stack shown in the creative inventory and the group that it is part of.
+type Entry struct {
+	// Stack is the item.Stack shown in the creative inventory. Players taking it from the creative inventory
+	// receive a full stack of it.
+	Stack item.Stack
+	// Group is the name of the group that the entry is part of. Entries of the same group are listed next to each
+	// other, and groups may be removed from a Menu at once using RemoveGroup. Vanilla items are part of the group
+	// with an empty name.
+	Group string
+}
+
+// Menu holds the entries of a creative inventory, in the order that they are shown to players. Every player is
+// shown the Default Menu unless a different Menu is set for the player, so that servers may hide items from certain
+// players. Menus are safe for concurrent use. Changes to a Menu are not sent to players it was already shown to.
+type Menu struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// defaultMenu holds the entries of the Default Menu. RegisterItem adds entries to it.
+var defaultMenu = NewMenu()
+
+// Default returns the Menu shown to players by default. It holds all vanilla items that are registered, followed by
+// items added using RegisterItem.
+func Default() *Menu {
+	return defaultMenu
+}
+
+// NewMenu creates a Menu holding the entries passed.
+func NewMenu(entries ...Entry) *Menu {
+	return &Menu{entries: append([]Entry(nil), entries...)}
+}
+
+// Add adds an item.Stack to the Menu as part of the group passed. The stack is added after the last entry of the
+// same group, or at the end of the Menu if the group has no entries yet.
+func (m *Menu) Add(s item.Stack, group string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if m.entries[i].Group == group {
+			m.entries = append(m.entries[:i+1], append([]Entry{{Stack: s, Group: group}}, m.entries[i+1:]...)...)
+			return
+		}
+	}
+	m.entries = append(m.entries, Entry{Stack: s, Group: group})
+}
+
+// Remove removes all entries of which the item.Stack is comparable to the stack passed, as checked using
+// item.Stack.Comparable. Remove returns true if any entries were removed.
+func (m *Menu) Remove(s item.Stack) bool {
+	return m.removeFunc(func(e Entry) bool {
+		return e.Stack.Comparable(s)
+	})
+}
+
+// RemoveGroup removes all entries of the group passed from the Menu.
+func (m *Menu) RemoveGroup(group string) {
+	m.removeFunc(func(e Entry) bool {
+		return e.Group == group
+	})
+}
+
+// Entries returns all entries of the Menu in the order that they are shown.
+func (m *Menu) Entries() []Entry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Entry(nil), m.entries...)
+}
+
+// Items returns the item stacks of all entries of the Menu in the order that they are shown.
+func (m *Menu) Items() []item.Stack {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	stacks := make([]item.Stack, len(m.entries))
+	for i, e := range m.entries {
+		stacks[i] = e.Stack
+	}
+	return stacks
+}
+
+// Filter returns a new Menu holding the entries of the Menu for which the function passed returns true. Filter
+// may be used to create menus for players that should not have access to some items.
+func (m *Menu) Filter(f func(e Entry) bool) *Menu {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	filtered := make([]Entry, 0, len(m.entries))
+	for _, e := range m.entries {
+		if f(e) {
+			filtered = append(filtered, e)
+		}
+	}
+	return &Menu{entries: filtered}
+}
+
+// removeFunc removes all entries of the Menu for which the function passed returns true. It returns true if any
+// entries were removed.
+func (m *Menu) removeFunc(f func(e Entry) bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, e := range m.entries {
+		if !f(e) {
+			m.entries[n] = e
+			n++
+		}
+	}
+	removed := n != len(m.entries)
+	m.entries = m.entries[:n]
+	return removed
+}
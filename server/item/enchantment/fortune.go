@@ -0,0 +1,57 @@
+package enchantment
+
+import (
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"math/rand"
+)
+
+// Fortune is an enchantment that increases the amount of items dropped by blocks such as ores when mined.
+type Fortune struct{}
+
+// Name ...
+func (Fortune) Name() string {
+	return "Fortune"
+}
+
+// MaxLevel ...
+func (Fortune) MaxLevel() int {
+	return 3
+}
+
+// Cost ...
+func (Fortune) Cost(level int) (int, int) {
+	min := 15 + (level-1)*9
+	return min, min + 50
+}
+
+// Rarity ...
+func (Fortune) Rarity() item.EnchantmentRarity {
+	return item.EnchantmentRarityRare
+}
+
+// CompatibleWithEnchantment ...
+func (Fortune) CompatibleWithEnchantment(t item.EnchantmentType) bool {
+	_, silkTouch := t.(SilkTouch)
+	return !silkTouch
+}
+
+// CompatibleWithItem ...
+func (Fortune) CompatibleWithItem(i world.Item) bool {
+	t, ok := i.(item.Tool)
+	return ok && (t.ToolType() == item.TypePickaxe || t.ToolType() == item.TypeAxe || t.ToolType() == item.TypeShovel || t.ToolType() == item.TypeHoe)
+}
+
+// DropCount returns the amount of items dropped by an ore that normally drops count items, when mined using a tool
+// with Fortune of the level passed. The count is multiplied by a random number from 1 up to and including level+1,
+// with a multiplier of 1 being more likely than the others. The rand.Rand passed is used to pick the multiplier.
+func (Fortune) DropCount(level, count int, r *rand.Rand) int {
+	if level <= 0 {
+		return count
+	}
+	bonus := r.Intn(level+2) - 1
+	if bonus < 0 {
+		bonus = 0
+	}
+	return count * (bonus + 1)
+}
@@ -29,9 +29,9 @@ func (SilkTouch) Rarity() item.EnchantmentRarity {
 }
 
 // CompatibleWithEnchantment ...
-func (SilkTouch) CompatibleWithEnchantment(item.EnchantmentType) bool {
-	// TODO: Fortune.
-	return true
+func (SilkTouch) CompatibleWithEnchantment(t item.EnchantmentType) bool {
+	_, fortune := t.(Fortune)
+	return !fortune
 }
 
 // CompatibleWithItem ...
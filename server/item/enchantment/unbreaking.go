@@ -42,12 +42,13 @@ func (Unbreaking) CompatibleWithItem(i world.Item) bool {
 	return ok
 }
 
-// Reduce returns the amount of damage that should be reduced with unbreaking.
-func (Unbreaking) Reduce(it world.Item, level, amount int) int {
+// Reduce returns the amount of damage that should be reduced with unbreaking. r is used to decide whether each point
+// of damage is reduced.
+func (Unbreaking) Reduce(it world.Item, level, amount int, r *rand.Rand) int {
 	after := amount
 	_, ok := it.(item.Armour)
 	for i := 0; i < amount; i++ {
-		if (!ok || rand.Float64() >= 0.6) && rand.Intn(level+1) > 0 {
+		if (!ok || r.Float64() >= 0.6) && r.Intn(level+1) > 0 {
 			after--
 		}
 	}
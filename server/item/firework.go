@@ -40,13 +40,18 @@ func (f Firework) Use(w *world.World, user User, ctx *UseContext) bool {
 func (f Firework) UseOnBlock(blockPos cube.Pos, _ cube.Face, clickPos mgl64.Vec3, w *world.World, user User, ctx *UseContext) bool {
 	pos := blockPos.Vec3().Add(clickPos)
 	create := w.EntityRegistry().Config().Firework
-	w.AddEntity(create(pos, cube.Rotation{rand.Float64() * 360, 90}, false, f, user))
+	w.AddEntity(create(pos, cube.Rotation{w.Rand().Float64() * 360, 90}, false, f, user))
 	w.PlaySound(pos, sound.FireworkLaunch{})
 
 	ctx.SubtractFromCount(1)
 	return true
 }
 
+// OffHand ...
+func (Firework) OffHand() bool {
+	return true
+}
+
 // EncodeNBT ...
 func (f Firework) EncodeNBT() map[string]any {
 	explosions := make([]any, 0, len(f.Explosions))
@@ -5,7 +5,6 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
-	"math/rand"
 	"time"
 )
 
@@ -39,7 +38,7 @@ func (f FlintAndSteel) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w
 	} else if s := pos.Side(face); w.Block(s) == air() {
 		w.PlaySound(s.Vec3Centre(), sound.Ignite{})
 		w.SetBlock(s, fire(), nil)
-		w.ScheduleBlockUpdate(s, time.Duration(30+rand.Intn(10))*time.Second/20)
+		w.ScheduleBlockUpdate(s, time.Duration(30+w.Rand().Intn(10))*time.Second/20)
 		return true
 	}
 	return false
@@ -110,16 +110,11 @@ func (a *Armour) Boots() item.Stack {
 func (a *Armour) DamageReduction(dmg float64, src world.DamageSource) float64 {
 	var (
 		original                 = dmg
-		defencePoints, toughness float64
+		defencePoints, toughness = a.DefencePoints(), a.Toughness()
 		enchantments             []item.Enchantment
 	)
-
 	for _, it := range a.Items() {
 		enchantments = append(enchantments, it.Enchantments()...)
-		if armour, ok := it.Item().(item.Armour); ok {
-			defencePoints += armour.DefencePoints()
-			toughness += armour.Toughness()
-		}
 	}
 
 	dmg -= dmg * enchantment.ProtectionFactor(src, enchantments)
@@ -133,6 +128,30 @@ func (a *Armour) DamageReduction(dmg float64, src world.DamageSource) float64 {
 	return original - dmg
 }
 
+// DefencePoints returns the total amount of defence points of the Armour worn. Every defence point is shown as half
+// an armour icon above the hotbar and reduces damage taken by up to 4%.
+func (a *Armour) DefencePoints() float64 {
+	points := 0.0
+	for _, it := range a.Items() {
+		if armour, ok := it.Item().(item.Armour); ok {
+			points += armour.DefencePoints()
+		}
+	}
+	return points
+}
+
+// Toughness returns the total toughness of the Armour worn. Toughness decreases the loss of effective defence
+// points when taking large amounts of damage.
+func (a *Armour) Toughness() float64 {
+	toughness := 0.0
+	for _, it := range a.Items() {
+		if armour, ok := it.Item().(item.Armour); ok {
+			toughness += armour.Toughness()
+		}
+	}
+	return toughness
+}
+
 // HighestEnchantmentLevel looks up the highest level of an item.EnchantmentType
 // that any of the Armour items have and returns it, or 0 if none of the items
 // have the enchantment.
@@ -152,10 +171,15 @@ func (a *Armour) HighestEnchantmentLevel(t item.EnchantmentType) int {
 type DamageFunc func(s item.Stack, d int) item.Stack
 
 // Damage deals damage (hearts) to Armour. The resulting item damage depends on the
-// dmg passed and the DamageFunc used.
+// dmg passed and the DamageFunc used. Only pieces of armour that provide defence
+// points are damaged, so that items such as elytras and pumpkins worn are left
+// untouched.
 func (a *Armour) Damage(dmg float64, f DamageFunc) {
 	armourDamage := int(math.Max(math.Floor(dmg/4), 1))
 	for slot, it := range a.Slots() {
+		if armour, ok := it.Item().(item.Armour); !ok || armour.DefencePoints() <= 0 {
+			continue
+		}
 		_ = a.inv.SetItem(slot, f(it, armourDamage))
 	}
 }
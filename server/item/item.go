@@ -120,6 +120,33 @@ type HandEquipped interface {
 	HandEquipped() bool
 }
 
+// Nourishing represents a custom Consumable item that restores food points and saturation when consumed. The
+// nutrition is sent to the client so that it shows the item as food. It does not change the behaviour of Consume,
+// which should still call Consumer.Saturate.
+type Nourishing interface {
+	// Nutrition returns the food points and saturation that consuming the item restores.
+	Nutrition() (food int, saturation float64)
+}
+
+// RenderOffset is the offset of a custom item held by an entity in first or third person. Rotation is in degrees.
+type RenderOffset struct {
+	Position, Rotation, Scale mgl64.Vec3
+}
+
+// RenderOffsets holds the RenderOffset of a custom item held in the main hand and off hand, in first and third
+// person.
+type RenderOffsets struct {
+	MainHandFirstPerson, MainHandThirdPerson RenderOffset
+	OffHandFirstPerson, OffHandThirdPerson   RenderOffset
+}
+
+// RenderOffsetter represents a custom item that changes the way it is rendered when held. By default, custom items
+// are scaled based on the size of their texture so that they appear the same size as vanilla items.
+type RenderOffsetter interface {
+	// RenderOffsets returns the RenderOffsets of the item.
+	RenderOffsets() RenderOffsets
+}
+
 // Weapon is an item that may be used as a weapon. It has an attack damage which may be different to the 2
 // damage that attacking with an empty hand deals.
 type Weapon interface {
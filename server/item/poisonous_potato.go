@@ -3,7 +3,6 @@ package item
 import (
 	"github.com/df-mc/dragonfly/server/entity/effect"
 	"github.com/df-mc/dragonfly/server/world"
-	"math/rand"
 	"time"
 )
 
@@ -13,9 +12,9 @@ type PoisonousPotato struct {
 }
 
 // Consume ...
-func (p PoisonousPotato) Consume(_ *world.World, c Consumer) Stack {
+func (p PoisonousPotato) Consume(w *world.World, c Consumer) Stack {
 	c.Saturate(2, 1.2)
-	if rand.Float64() < 0.6 {
+	if w.Rand().Float64() < 0.6 {
 		c.AddEffect(effect.New(effect.Poison{}, 1, 5*time.Second))
 	}
 	return Stack{}
@@ -3,7 +3,6 @@ package item
 import (
 	"github.com/df-mc/dragonfly/server/entity/effect"
 	"github.com/df-mc/dragonfly/server/world"
-	"math/rand"
 	"time"
 )
 
@@ -13,9 +12,9 @@ type RottenFlesh struct {
 }
 
 // Consume ...
-func (RottenFlesh) Consume(_ *world.World, c Consumer) Stack {
+func (RottenFlesh) Consume(w *world.World, c Consumer) Stack {
 	c.Saturate(4, 0.8)
-	if rand.Float64() < 0.8 {
+	if w.Rand().Float64() < 0.8 {
 		c.AddEffect(effect.New(effect.Hunger{}, 1, 30*time.Second))
 	}
 	return Stack{}
@@ -242,6 +242,18 @@ func (s Stack) WithEnchantments(enchants ...Enchantment) Stack {
 	return s
 }
 
+// WithEnchantment returns the current stack with an enchantment of the EnchantmentType and level passed added to it.
+// Unlike WithEnchantments, the enchantment is not added if it is incompatible with any of the enchantments already
+// present on the Stack. WithEnchantment panics if the level passed is below 1.
+func (s Stack) WithEnchantment(t EnchantmentType, lvl int) Stack {
+	for other := range s.enchantments {
+		if other != t && (!t.CompatibleWithEnchantment(other) || !other.CompatibleWithEnchantment(t)) {
+			return s
+		}
+	}
+	return s.WithEnchantments(NewEnchantment(t, lvl))
+}
+
 // WithoutEnchantments returns the current stack but with the passed enchantments removed.
 func (s Stack) WithoutEnchantments(enchants ...EnchantmentType) Stack {
 	s.enchantments = copyEnchantments(s.enchantments)
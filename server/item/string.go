@@ -0,0 +1,9 @@
+package item
+
+// String is an item dropped by spiders and cobwebs. It is used to craft items such as bows and fishing rods.
+type String struct{}
+
+// EncodeItem ...
+func (String) EncodeItem() (name string, meta int16) {
+	return "minecraft:string", 0
+}
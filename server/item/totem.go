@@ -0,0 +1,21 @@
+package item
+
+// Totem is an item that saves the player holding it from death. When the player would otherwise die, the totem
+// is consumed, the player is left with a single health point and is given Regeneration, Absorption and Fire
+// Resistance.
+type Totem struct{}
+
+// MaxCount always returns 1.
+func (Totem) MaxCount() int {
+	return 1
+}
+
+// OffHand ...
+func (Totem) OffHand() bool {
+	return true
+}
+
+// EncodeItem ...
+func (Totem) EncodeItem() (name string, meta int16) {
+	return "minecraft:totem_of_undying", 0
+}
@@ -1,8 +1,42 @@
 package item
 
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// UseTarget specifies what an item was used on.
+type UseTarget int
+
+const (
+	// UseTargetAir is the UseTarget of an item used in the air, such as when throwing an egg.
+	UseTargetAir UseTarget = iota
+	// UseTargetBlock is the UseTarget of an item used on a block.
+	UseTargetBlock
+	// UseTargetEntity is the UseTarget of an item used on an entity.
+	UseTargetEntity
+)
+
 // UseContext is passed to every item Use methods. It may be used to subtract items or to deal damage to them
 // after the action is complete.
+// UseContext also describes the use itself: The User that used the item, the world it was used in, the item used
+// and the block or entity that it was used on, if any.
 type UseContext struct {
+	// User is the User that used the item. World is the world that the item was used in.
+	User  User
+	World *world.World
+	// Item is the item stack that was used.
+	Item Stack
+	// Target specifies what the item was used on. If Target is UseTargetBlock, Pos, Face and ClickPos hold the
+	// position of the block clicked, the face clicked and the position clicked relative to the corner of the
+	// block. If Target is UseTargetEntity, Entity holds the entity that the item was used on.
+	Target   UseTarget
+	Pos      cube.Pos
+	Face     cube.Face
+	ClickPos mgl64.Vec3
+	Entity   world.Entity
+
 	// Damage is the amount of damage that should be dealt to the item as a result of using it.
 	Damage int
 	// CountSub is how much of the count should be subtracted after using the item.
@@ -36,3 +70,16 @@ func (ctx *UseContext) DamageItem(d int) { ctx.Damage += d }
 
 // SubtractFromCount subtracts d from the count of the item stack used.
 func (ctx *UseContext) SubtractFromCount(d int) { ctx.CountSub += d }
+
+// OnBlock returns the UseContext with its Target set to the block at the position passed, clicked at the face and
+// relative position passed.
+func (ctx *UseContext) OnBlock(pos cube.Pos, face cube.Face, clickPos mgl64.Vec3) *UseContext {
+	ctx.Target, ctx.Pos, ctx.Face, ctx.ClickPos = UseTargetBlock, pos, face, clickPos
+	return ctx
+}
+
+// OnEntity returns the UseContext with its Target set to the entity passed.
+func (ctx *UseContext) OnEntity(e world.Entity) *UseContext {
+	ctx.Target, ctx.Entity = UseTargetEntity, e
+	return ctx
+}
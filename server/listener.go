@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/df-mc/dragonfly/server/session"
 	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
 	"github.com/sirupsen/logrus"
 	"io"
 	"log"
@@ -23,15 +24,22 @@ type Listener interface {
 // listenerFunc may be used to return a *minecraft.Listener using a Config. It
 // is the standard listener used when UserConfig.Config() is called.
 func (uc UserConfig) listenerFunc(conf Config) (Listener, error) {
+	maxPlayers := conf.MaxPlayers
+	if maxPlayers > 0 {
+		// Players waiting in the join queue are connected to the listener too, so make room for them.
+		maxPlayers += conf.JoinQueueSize
+	}
+	logins := &loginRequests{m: make(map[string]loginRequest)}
 	cfg := minecraft.ListenConfig{
-		MaximumPlayers:         conf.MaxPlayers,
-		StatusProvider:         statusProvider{name: conf.Name},
+		MaximumPlayers:         maxPlayers,
+		StatusProvider:         statusProvider{status: conf.status},
 		AuthenticationDisabled: conf.AuthDisabled,
 		ResourcePacks:          conf.Resources,
 		Biomes:                 biomes(),
 		TexturePacksRequired:   conf.ResourcesRequired,
+		PacketFunc:             logins.handlePacket,
 	}
-	if l, ok := conf.Log.(*logrus.Logger); ok {
+	if l, ok := subsystemLog(conf.Log, "network").(*logrus.Entry); ok {
 		cfg.ErrorLog = log.Default()
 		log.SetOutput(l.WithField("src", "gophertunnel").WriterLevel(logrus.DebugLevel))
 	}
@@ -40,13 +48,14 @@ func (uc UserConfig) listenerFunc(conf Config) (Listener, error) {
 		return nil, fmt.Errorf("create minecraft listener: %w", err)
 	}
 	conf.Log.Infof("Server running on %v.\n", l.Addr())
-	return listener{l}, nil
+	return listener{Listener: l, logins: logins}, nil
 }
 
 // listener is a Listener implementation that wraps around a minecraft.Listener so that it can be listened on by
 // Server.
 type listener struct {
 	*minecraft.Listener
+	logins *loginRequests
 }
 
 // Accept blocks until the next connection is established and returns it. An error is returned if the Listener was
@@ -63,3 +72,21 @@ func (l listener) Accept() (session.Conn, error) {
 func (l listener) Disconnect(conn session.Conn, reason string) error {
 	return l.Listener.Disconnect(conn.(*minecraft.Conn), reason)
 }
+
+// loginChain returns the login chain that the connection passed logged in with.
+func (l listener) loginChain(conn session.Conn) ([]loginToken, error) {
+	request, ok := l.logins.take(conn.RemoteAddr())
+	if !ok {
+		return nil, fmt.Errorf("no login request recorded for %v", conn.RemoteAddr())
+	}
+	// The request was recorded before the network library verified it, so its signatures are verified again here,
+	// making sure that the chain returned is the one that the connection was actually accepted with.
+	identity, _, _, err := login.Parse(request)
+	if err != nil {
+		return nil, fmt.Errorf("verify login request: %w", err)
+	}
+	if identity.Identity != conn.IdentityData().Identity || identity.XUID != conn.IdentityData().XUID {
+		return nil, fmt.Errorf("recorded login request does not match identity of connection")
+	}
+	return parseLoginChain(request)
+}
@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+// Commands returns the /loglevel command, which lists and changes the levels of the subsystems of the Logger passed
+// at runtime. The commands may be registered using cmd.Register. allow is called to check if a cmd.Source may
+// execute the commands. If nil and no cmd.Permissions were set using cmd.SetPermissions, the commands may only be
+// executed by sources that are not players, such as the console. If nil and cmd.Permissions were set, the
+// permission node of each command ('command.loglevel') decides which sources may execute it.
+func Commands(l *Logger, allow func(src cmd.Source) bool) []cmd.Command {
+	if allow == nil {
+		allow = func(src cmd.Source) bool {
+			_, ok := src.(*player.Player)
+			return !ok || cmd.HasPermissions()
+		}
+	}
+	c := command{l: l, allow: allow}
+	return []cmd.Command{
+		cmd.New("loglevel", "Lists and changes the log levels of subsystems.", nil,
+			logLevelListCommand{command: c},
+			logLevelSetCommand{command: c},
+			logLevelResetCommand{command: c},
+			logLevelDefaultCommand{command: c},
+		),
+	}
+}
+
+// command holds the fields shared by all commands returned by Commands.
+type command struct {
+	l     *Logger
+	allow func(src cmd.Source) bool
+}
+
+// Allow ...
+func (c command) Allow(src cmd.Source) bool {
+	return c.allow(src)
+}
+
+// logLevelListCommand implements the /loglevel list command.
+type logLevelListCommand struct {
+	command
+	List cmd.SubCommand `cmd:"list"`
+}
+
+// Run ...
+func (c logLevelListCommand) Run(_ cmd.Source, o *cmd.Output) {
+	o.Printf("Default level: %v", c.l.base.GetLevel())
+	for _, name := range c.l.Subsystems() {
+		o.Printf("%v: %v", name, c.l.Level(name))
+	}
+}
+
+// logLevelSetCommand implements the /loglevel set <subsystem> <level> command.
+type logLevelSetCommand struct {
+	command
+	Set       cmd.SubCommand `cmd:"set"`
+	Subsystem string         `cmd:"subsystem"`
+	Level     level          `cmd:"level"`
+}
+
+// Run ...
+func (c logLevelSetCommand) Run(_ cmd.Source, o *cmd.Output) {
+	c.l.SetLevel(c.Subsystem, c.Level.Level())
+	o.Printf("Set the log level of %v to %v.", c.Subsystem, c.Level.Level())
+}
+
+// logLevelResetCommand implements the /loglevel reset <subsystem> command.
+type logLevelResetCommand struct {
+	command
+	Reset     cmd.SubCommand `cmd:"reset"`
+	Subsystem string         `cmd:"subsystem"`
+}
+
+// Run ...
+func (c logLevelResetCommand) Run(_ cmd.Source, o *cmd.Output) {
+	c.l.ResetLevel(c.Subsystem)
+	o.Printf("Reset the log level of %v to the default level.", c.Subsystem)
+}
+
+// logLevelDefaultCommand implements the /loglevel default <level> command.
+type logLevelDefaultCommand struct {
+	command
+	Default cmd.SubCommand `cmd:"default"`
+	Level   level          `cmd:"level"`
+}
+
+// Run ...
+func (c logLevelDefaultCommand) Run(_ cmd.Source, o *cmd.Output) {
+	c.l.SetDefaultLevel(c.Level.Level())
+	o.Printf("Set the default log level to %v.", c.Level.Level())
+}
+
+// level is a cmd.Enum of the levels of logrus.
+type level string
+
+// Type ...
+func (level) Type() string {
+	return "LogLevel"
+}
+
+// Options ...
+func (level) Options(cmd.Source) []string {
+	options := make([]string, 0, len(logrus.AllLevels))
+	for _, l := range logrus.AllLevels {
+		options = append(options, l.String())
+	}
+	return options
+}
+
+// Level returns the logrus.Level of the level.
+func (l level) Level() logrus.Level {
+	lvl, _ := logrus.ParseLevel(strings.ToLower(string(l)))
+	return lvl
+}
@@ -0,0 +1,257 @@
+// Package logging implements a structured Logger built on Logrus. Entries are logged by subsystems of a server, such
+// as worlds and sessions, each of which has a level that may be changed separately at runtime. Entries carry fields
+// that provide context, such as the world, player or chunk position that an entry concerns, and may be captured by
+// hooks, for example to forward errors to a chat service.
+package logging
+
+import (
+	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"sync"
+)
+
+// Fields commonly set on entries logged by subsystems of a server.
+const (
+	// FieldSubsystem holds the name of the subsystem that logged an entry. It is set on all entries logged using a
+	// Logger.
+	FieldSubsystem = "subsystem"
+	// FieldWorld holds the name of the world that an entry concerns.
+	FieldWorld = "world"
+	// FieldDimension holds the dimension of the world that an entry concerns.
+	FieldDimension = "dimension"
+	// FieldPlayer holds the name of the player that an entry concerns.
+	FieldPlayer = "player"
+	// FieldChunk holds the position of the chunk that an entry concerns.
+	FieldChunk = "chunk"
+)
+
+// DefaultSubsystem is the subsystem that entries logged directly using a Logger, rather than through
+// Logger.Subsystem, are logged by.
+const DefaultSubsystem = "server"
+
+// Config holds the settings of a Logger. Calling Config.New() creates a Logger.
+type Config struct {
+	// Base is the logrus.Logger whose output and formatter are used to write entries. The level of Base is the
+	// default level of subsystems without a level in Levels. Changes to the output and formatter of Base also apply
+	// to the Logger. If nil, a logger created using logrus.New() is used.
+	Base *logrus.Logger
+	// Levels holds the levels of subsystems, such as 'world' or 'session', that should log at a level different
+	// from that of Base.
+	Levels map[string]logrus.Level
+}
+
+// Logger is a structured logger of which each subsystem has its own level. Logger implements server.Logger, so that
+// it may be passed to server.Config, in which case the Server logs entries of worlds and sessions using the 'world'
+// and 'session' subsystems respectively. A Logger is safe for concurrent use.
+type Logger struct {
+	base *logrus.Logger
+
+	mu      sync.Mutex
+	levels  map[string]logrus.Level
+	loggers map[string]*logrus.Logger
+	hooks   []*hook
+}
+
+// hook wraps a logrus.Hook added to a Logger, so that it may be removed again even if the logrus.Hook is not
+// comparable.
+type hook struct {
+	logrus.Hook
+}
+
+// New creates a Logger using the settings in the Config.
+func (conf Config) New() *Logger {
+	if conf.Base == nil {
+		conf.Base = logrus.New()
+	}
+	l := &Logger{base: conf.Base, levels: maps.Clone(conf.Levels), loggers: map[string]*logrus.Logger{}}
+	if l.levels == nil {
+		l.levels = map[string]logrus.Level{}
+	}
+	return l
+}
+
+// Subsystem returns a logrus.Entry that logs entries of the subsystem passed, at the level of that subsystem. The
+// entry has FieldSubsystem set to the name of the subsystem, and additional fields may be added using
+// logrus.Entry.WithField.
+func (l *Logger) Subsystem(name string) *logrus.Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	logger, ok := l.loggers[name]
+	if !ok {
+		level, ok := l.levels[name]
+		if !ok {
+			level = l.base.GetLevel()
+		}
+		logger = &logrus.Logger{
+			Out:          output{l: l.base},
+			Formatter:    formatter{l: l.base},
+			Hooks:        logrus.LevelHooks{},
+			Level:        level,
+			ExitFunc:     l.base.ExitFunc,
+			ReportCaller: l.base.ReportCaller,
+		}
+		logger.AddHook(dispatcher{l: l})
+		l.loggers[name] = logger
+	}
+	return logger.WithField(FieldSubsystem, name)
+}
+
+// SetLevel sets the level of the subsystem passed, so that only entries of that level or more severe are logged by
+// it. SetLevel may be called at any time and applies to entries logged afterwards.
+func (l *Logger) SetLevel(subsystem string, level logrus.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.levels[subsystem] = level
+	if logger, ok := l.loggers[subsystem]; ok {
+		logger.SetLevel(level)
+	}
+}
+
+// ResetLevel resets the level of the subsystem passed to the default level, which is the level of the Base of the
+// Logger.
+func (l *Logger) ResetLevel(subsystem string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.levels, subsystem)
+	if logger, ok := l.loggers[subsystem]; ok {
+		logger.SetLevel(l.base.GetLevel())
+	}
+}
+
+// SetDefaultLevel sets the level of the Base of the Logger, which is used by all subsystems of which the level was
+// not set using SetLevel.
+func (l *Logger) SetDefaultLevel(level logrus.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.base.SetLevel(level)
+	for name, logger := range l.loggers {
+		if _, ok := l.levels[name]; !ok {
+			logger.SetLevel(level)
+		}
+	}
+}
+
+// Level returns the level of the subsystem passed.
+func (l *Logger) Level(subsystem string) logrus.Level {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level, ok := l.levels[subsystem]; ok {
+		return level
+	}
+	return l.base.GetLevel()
+}
+
+// Subsystems returns the names of all subsystems that logged an entry or of which the level was set, sorted
+// alphabetically.
+func (l *Logger) Subsystems() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	names := maps.Keys(l.loggers)
+	for name := range l.levels {
+		if _, ok := l.loggers[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// AddHook adds a logrus.Hook that is fired for every entry logged by any subsystem with one of the levels returned
+// by its Levels method, for example to forward errors to a chat service. The Hook must not log to the Logger
+// itself. The function returned removes the Hook again.
+func (l *Logger) AddHook(h logrus.Hook) (remove func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	added := &hook{Hook: h}
+	l.hooks = append(l.hooks, added)
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if i := slices.Index(l.hooks, added); i != -1 {
+			l.hooks = slices.Delete(l.hooks, i, i+1)
+		}
+	}
+}
+
+// WithField returns an entry of the DefaultSubsystem with the field passed.
+func (l *Logger) WithField(key string, value any) *logrus.Entry {
+	return l.Subsystem(DefaultSubsystem).WithField(key, value)
+}
+
+// WithFields returns an entry of the DefaultSubsystem with the fields passed.
+func (l *Logger) WithFields(fields logrus.Fields) *logrus.Entry {
+	return l.Subsystem(DefaultSubsystem).WithFields(fields)
+}
+
+// Debugf logs a debug message using the DefaultSubsystem.
+func (l *Logger) Debugf(format string, a ...any) {
+	l.Subsystem(DefaultSubsystem).Debugf(format, a...)
+}
+
+// Infof logs an informational message using the DefaultSubsystem.
+func (l *Logger) Infof(format string, a ...any) {
+	l.Subsystem(DefaultSubsystem).Infof(format, a...)
+}
+
+// Warnf logs a warning using the DefaultSubsystem.
+func (l *Logger) Warnf(format string, a ...any) {
+	l.Subsystem(DefaultSubsystem).Warnf(format, a...)
+}
+
+// Errorf logs an error using the DefaultSubsystem.
+func (l *Logger) Errorf(format string, a ...any) {
+	l.Subsystem(DefaultSubsystem).Errorf(format, a...)
+}
+
+// Fatalf logs a fatal error using the DefaultSubsystem and exits the program.
+func (l *Logger) Fatalf(format string, a ...any) {
+	l.Subsystem(DefaultSubsystem).Fatalf(format, a...)
+}
+
+// dispatcher is a logrus.Hook that fires the hooks added to a Logger.
+type dispatcher struct {
+	l *Logger
+}
+
+// Levels ...
+func (dispatcher) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire ...
+func (d dispatcher) Fire(e *logrus.Entry) error {
+	d.l.mu.Lock()
+	hooks := slices.Clone(d.l.hooks)
+	d.l.mu.Unlock()
+
+	for _, h := range hooks {
+		if slices.Contains(h.Levels(), e.Level) {
+			if err := h.Fire(e); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// output is an io.Writer that writes to the output of a logrus.Logger, so that changes to it apply immediately.
+type output struct {
+	l *logrus.Logger
+}
+
+// Write ...
+func (o output) Write(b []byte) (int, error) {
+	return o.l.Out.Write(b)
+}
+
+// formatter is a logrus.Formatter that formats entries using the formatter of a logrus.Logger, so that changes to
+// it apply immediately.
+type formatter struct {
+	l *logrus.Logger
+}
+
+// Format ...
+func (f formatter) Format(e *logrus.Entry) ([]byte, error) {
+	return f.l.Formatter.Format(e)
+}
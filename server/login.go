@@ -0,0 +1,298 @@
+package server
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LoginConfig holds options to validate the login requests of players joining a Server more strictly. These checks
+// are done in addition to the validation done by the network library, which verifies the signatures of all tokens
+// in the login chain and checks their expiry with a tolerance of one minute.
+type LoginConfig struct {
+	// ClockSkew is the tolerance used to check the expiry, not-before and issued-at times of the tokens in the
+	// login chain. Because the network library already checks these times with a tolerance of one minute, ClockSkew
+	// can only be used to check them more strictly. If left as 0, the times are not checked again.
+	ClockSkew time.Duration
+	// RequiredClaims is a list of claims that must be present and not empty in the last token of the login chain,
+	// which holds the identity of the player. Claims nested in objects may be specified by separating the keys with
+	// a dot, such as 'extraData.XUID'.
+	RequiredClaims []string
+	// PinnedKeys is a list of base64 encoded public keys that the login chain of players must be signed by. If not
+	// empty, only players whose login chain is signed by one of these keys, which would normally only be the key
+	// of Mojang, may join. Players that are not logged in with XBOX Live are then always rejected.
+	PinnedKeys []string
+}
+
+// LoginClaims holds the claims of the login request of a player joining a Server.
+type LoginClaims struct {
+	// Identity is the login.IdentityData of the player, obtained from the last token in the login chain. It cannot
+	// be changed by the player.
+	Identity login.IdentityData
+	// Client is the login.ClientData of the player. WARNING: Use the client data at your own risk, it cannot be
+	// trusted because it can be freely changed by the player connecting.
+	Client login.ClientData
+	// Chain holds the claims of each token in the login chain, in the order in which they were sent by the client.
+	// The signatures of all tokens have been verified.
+	Chain []map[string]any
+}
+
+// IdentityClaims returns the claims of the last token in the login chain, which holds the identity of the player.
+// It includes the raw 'extraData' object that the Identity is parsed from.
+func (c LoginClaims) IdentityClaims() map[string]any {
+	if len(c.Chain) == 0 {
+		return nil
+	}
+	return c.Chain[len(c.Chain)-1]
+}
+
+// LoginHandler handles the login of players joining a Server. It may be used to validate the login of a player using
+// its LoginClaims before the player spawns.
+type LoginHandler interface {
+	// HandleLogin handles a player logging in from an address. ctx.Cancel() may be called to disconnect the player
+	// with the message that msg points to.
+	HandleLogin(ctx *event.Context, addr net.Addr, claims LoginClaims, msg *string)
+}
+
+// Compile time check to make sure NopLoginHandler implements LoginHandler.
+var _ LoginHandler = NopLoginHandler{}
+
+// NopLoginHandler implements the LoginHandler interface but does not execute any code when an event is called. The
+// default LoginHandler of a Server is set to NopLoginHandler.
+type NopLoginHandler struct{}
+
+func (NopLoginHandler) HandleLogin(*event.Context, net.Addr, LoginClaims, *string) {}
+
+// loginChainer is implemented by Listeners that keep the login chains of the connections they accept, such as the
+// standard listener. The logins of connections from other Listeners are not validated further.
+type loginChainer interface {
+	loginChain(conn session.Conn) ([]loginToken, error)
+}
+
+// verifyLogin validates the login chain of the connection passed using the LoginConfig of the Server and calls the
+// LoginHandler. If the connection may not join, a disconnect message and false are returned.
+func (srv *Server) verifyLogin(conn session.Conn, l Listener) (string, bool) {
+	chainer, ok := l.(loginChainer)
+	if !ok {
+		return "", true
+	}
+	chain, err := chainer.loginChain(conn)
+	if err == nil {
+		err = srv.conf.Login.validate(chain, time.Now())
+	}
+	if err != nil {
+		srv.conf.Log.Debugf("connection %v failed login validation: %v\n", conn.RemoteAddr(), err)
+		return "Your login could not be verified.", false
+	}
+	claims := LoginClaims{Identity: conn.IdentityData(), Client: conn.ClientData(), Chain: make([]map[string]any, len(chain))}
+	for i, tok := range chain {
+		claims.Chain[i] = tok.claims
+	}
+	ctx, msg := event.C(), "You are not allowed to join this server."
+	if srv.conf.LoginHandler.HandleLogin(ctx, conn.RemoteAddr(), claims, &msg); ctx.Cancelled() {
+		return msg, false
+	}
+	return "", true
+}
+
+// validate checks the login chain passed against the LoginConfig at the time passed.
+func (conf LoginConfig) validate(chain []loginToken, t time.Time) error {
+	if conf.ClockSkew > 0 {
+		for i, tok := range chain {
+			if err := tok.validateTime(t, conf.ClockSkew); err != nil {
+				return fmt.Errorf("validate token %v: %w", i, err)
+			}
+		}
+	}
+	identity := chain[len(chain)-1].claims
+	for _, name := range conf.RequiredClaims {
+		if !claimPresent(identity, name) {
+			return fmt.Errorf("identity token is missing required claim %v", name)
+		}
+	}
+	if len(conf.PinnedKeys) == 0 {
+		return nil
+	}
+	if len(chain) != 3 {
+		return fmt.Errorf("login chain of %v tokens is not signed by a pinned key", len(chain))
+	}
+	// The first token is signed by the client itself, but holds the key that the token issued by Mojang was signed
+	// with. The network library verified the token with this key, so it is the key that must be pinned.
+	raw, _ := chain[0].claims["identityPublicKey"].(string)
+	key := &ecdsa.PublicKey{}
+	if err := login.ParsePublicKey(raw, key); err != nil {
+		return fmt.Errorf("parse identity public key: %w", err)
+	}
+	for _, pinned := range conf.PinnedKeys {
+		pinnedKey := &ecdsa.PublicKey{}
+		if login.ParsePublicKey(pinned, pinnedKey) == nil && key.Equal(pinnedKey) {
+			return nil
+		}
+	}
+	return fmt.Errorf("login chain is not signed by a pinned key")
+}
+
+// claimPresent checks if the claim with the name passed is present and not empty in the claims passed. Keys of
+// nested objects are separated by a dot.
+func claimPresent(claims map[string]any, name string) bool {
+	keys := strings.Split(name, ".")
+	for _, key := range keys[:len(keys)-1] {
+		nested, ok := claims[key].(map[string]any)
+		if !ok {
+			return false
+		}
+		claims = nested
+	}
+	switch v := claims[keys[len(keys)-1]].(type) {
+	case nil:
+		return false
+	case string:
+		return v != ""
+	}
+	return true
+}
+
+// loginToken is a token from a login chain.
+type loginToken struct {
+	claims map[string]any
+}
+
+// validateTime checks the expiry, not-before and issued-at times of the loginToken at the time passed, allowing a
+// difference of up to leeway.
+func (tok loginToken) validateTime(t time.Time, leeway time.Duration) error {
+	if exp, ok := tok.timeClaim("exp"); ok && t.Add(-leeway).After(exp) {
+		return fmt.Errorf("token expired at %v", exp)
+	}
+	if nbf, ok := tok.timeClaim("nbf"); ok && t.Add(leeway).Before(nbf) {
+		return fmt.Errorf("token not valid before %v", nbf)
+	}
+	if iat, ok := tok.timeClaim("iat"); ok && t.Add(leeway).Before(iat) {
+		return fmt.Errorf("token issued in the future at %v", iat)
+	}
+	return nil
+}
+
+// timeClaim returns the time held by the numeric claim with the name passed, and false if the claim is not present.
+func (tok loginToken) timeClaim(name string) (time.Time, bool) {
+	v, ok := tok.claims[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(v), 0), true
+}
+
+// parseLoginChain parses the tokens of the login chain in the connection request of a Login packet. The signatures
+// of the tokens are not verified: parseLoginChain is only used for requests that were already verified using
+// login.Parse.
+func parseLoginChain(request []byte) ([]loginToken, error) {
+	buf := bytes.NewBuffer(request)
+	var chainLength int32
+	if err := binary.Read(buf, binary.LittleEndian, &chainLength); err != nil {
+		return nil, fmt.Errorf("read chain length: %w", err)
+	}
+	if chainLength < 0 || int(chainLength) > buf.Len() {
+		return nil, fmt.Errorf("invalid chain length %v", chainLength)
+	}
+	var req struct {
+		Chain []string `json:"chain"`
+	}
+	if err := json.Unmarshal(buf.Next(int(chainLength)), &req); err != nil {
+		return nil, fmt.Errorf("decode chain: %w", err)
+	}
+	if len(req.Chain) == 0 {
+		return nil, fmt.Errorf("login chain has no tokens")
+	}
+	chain := make([]loginToken, len(req.Chain))
+	for i, raw := range req.Chain {
+		parts := strings.Split(raw, ".")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("token %v: expected 3 parts, got %v", i, len(parts))
+		}
+		payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+		if err != nil {
+			return nil, fmt.Errorf("token %v: decode payload: %w", i, err)
+		}
+		if err := json.Unmarshal(payload, &chain[i].claims); err != nil {
+			return nil, fmt.Errorf("token %v: decode claims: %w", i, err)
+		}
+	}
+	return chain, nil
+}
+
+// loginRequestTimeout is the duration after which connection requests kept by loginRequests are discarded if
+// the connection was never accepted.
+const loginRequestTimeout = time.Minute
+
+// loginRequests keeps the connection requests of Login packets read by a minecraft.Listener, by the address of the
+// connection, until the connection is accepted.
+type loginRequests struct {
+	mu sync.Mutex
+	m  map[string]loginRequest
+}
+
+// loginRequest is a connection request kept by loginRequests, along with the time at which it was read.
+type loginRequest struct {
+	data []byte
+	t    time.Time
+}
+
+// handlePacket records the connection request of the packet passed if it is a Login packet. It is used as the
+// PacketFunc of a minecraft.ListenConfig, which is called for every packet read, so only the first Login packet of
+// a connection is recorded. Any Login packets sent after it are ignored by the network library and are dropped.
+func (r *loginRequests) handlePacket(header packet.Header, payload []byte, src, _ net.Addr) {
+	if header.PacketID != packet.IDLogin {
+		return
+	}
+	request, ok := decodeLogin(payload)
+	if !ok {
+		return
+	}
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for addr, req := range r.m {
+		if now.Sub(req.t) > loginRequestTimeout {
+			delete(r.m, addr)
+		}
+	}
+	if _, ok := r.m[src.String()]; ok {
+		return
+	}
+	r.m[src.String()] = loginRequest{data: request, t: now}
+}
+
+// take removes the connection request of the connection with the address passed and returns it.
+func (r *loginRequests) take(addr net.Addr) ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req, ok := r.m[addr.String()]
+	delete(r.m, addr.String())
+	return req.data, ok
+}
+
+// decodeLogin decodes the connection request of a Login packet from the payload passed. False is returned if the
+// payload is not valid.
+func decodeLogin(payload []byte) ([]byte, bool) {
+	// The payload starts with the protocol version of the client as a big endian int32, followed by the connection
+	// request prefixed by its length as a varuint32.
+	if len(payload) < 4 {
+		return nil, false
+	}
+	length, n := binary.Uvarint(payload[4:])
+	if n <= 0 || length > uint64(len(payload)-4-n) {
+		return nil, false
+	}
+	return append([]byte(nil), payload[4+n:4+n+int(length)]...), true
+}
@@ -0,0 +1,172 @@
+// Package metrics implements exporting metrics about the performance of a server, such as tick durations, loaded
+// chunks, entities, players, packet rates and the latency of the world provider, in the Prometheus text format over
+// an HTTP endpoint, so that they may be scraped by Prometheus and visualised in dashboards such as Grafana.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/df-mc/atomic"
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/df-mc/dragonfly/server/world"
+	"io"
+	"net"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Logger is used to report errors of the HTTP server serving metrics.
+type Logger interface {
+	Errorf(format string, a ...any)
+}
+
+// Config holds the settings of Metrics. Calling Config.New() creates Metrics and starts serving them.
+type Config struct {
+	// Address is the address that the HTTP server serving metrics listens on. If left empty, Address is set to
+	// ':9100'.
+	Address string
+	// Path is the path of the HTTP endpoint that metrics are served on. If left empty, Path is set to '/metrics'.
+	Path string
+	// Log is the Logger used to report errors. If nil, errors are not reported.
+	Log Logger
+}
+
+// Metrics collects metrics of a server and serves them over HTTP. Metrics of the server itself are only served once
+// the server is passed to SetServer, while the latency of a world.Provider is only collected if it is wrapped using
+// Metrics.Provider.
+type Metrics struct {
+	conf Config
+	srv  atomic.Value[*server.Server]
+	http *http.Server
+
+	load, store, settings operation
+}
+
+// New creates Metrics using the settings in the Config and starts serving them. Close must be called to stop
+// serving metrics.
+func (conf Config) New() (*Metrics, error) {
+	if conf.Address == "" {
+		conf.Address = ":9100"
+	}
+	if conf.Path == "" {
+		conf.Path = "/metrics"
+	}
+	l, err := net.Listen("tcp", conf.Address)
+	if err != nil {
+		return nil, fmt.Errorf("new metrics: %w", err)
+	}
+	m := &Metrics{conf: conf}
+	mux := http.NewServeMux()
+	mux.Handle(conf.Path, m)
+	m.http = &http.Server{Handler: mux, ReadHeaderTimeout: time.Second * 10}
+	go func() {
+		if err := m.http.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) && conf.Log != nil {
+			conf.Log.Errorf("metrics: %v", err)
+		}
+	}()
+	return m, nil
+}
+
+// SetServer sets the server that metrics such as players, tick durations, loaded chunks and entities are collected
+// from.
+func (m *Metrics) SetServer(srv *server.Server) {
+	m.srv.Store(srv)
+}
+
+// Close stops serving metrics.
+func (m *Metrics) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	return m.http.Shutdown(ctx)
+}
+
+// ServeHTTP writes all metrics in the Prometheus text format to the http.ResponseWriter passed. Metrics implements
+// http.Handler, so that it may also be served by an existing HTTP server.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.write(w)
+}
+
+// write writes all metrics in the Prometheus text format to the io.Writer passed.
+func (m *Metrics) write(w io.Writer) {
+	if srv := m.srv.Load(); srv != nil {
+		writeMetric(w, "dragonfly_players", "gauge", "Number of players online.", sample{value: float64(len(srv.Players()))})
+
+		var tickTime, ticks, lastTick, chunks, entities []sample
+		for _, wo := range []*world.World{srv.World(), srv.Nether(), srv.End()} {
+			labels := fmt.Sprintf(`world="%v",dimension="%v"`, escape(wo.Name()), strings.ToLower(fmt.Sprint(wo.Dimension())))
+			s := wo.Stats()
+			tickTime = append(tickTime, sample{suffix: "_sum", labels: labels, value: s.TickTime.Seconds()})
+			ticks = append(ticks, sample{suffix: "_count", labels: labels, value: float64(s.Ticks)})
+			lastTick = append(lastTick, sample{labels: labels, value: s.LastTick.Seconds()})
+			chunks = append(chunks, sample{labels: labels, value: float64(s.LoadedChunks)})
+			entities = append(entities, sample{labels: labels, value: float64(s.Entities)})
+		}
+		writeMetric(w, "dragonfly_world_tick_duration_seconds", "summary", "Time spent ticking worlds.", append(tickTime, ticks...)...)
+		writeMetric(w, "dragonfly_world_last_tick_duration_seconds", "gauge", "Time spent on the last tick of worlds.", lastTick...)
+		writeMetric(w, "dragonfly_world_loaded_chunks", "gauge", "Number of chunks loaded in worlds.", chunks...)
+		writeMetric(w, "dragonfly_world_entities", "gauge", "Number of entities in worlds.", entities...)
+
+		var allowed, rejected, buckets []sample
+		stats := srv.RateLimits().Stats()
+		for _, name := range []string{"login", "chat", "command", "interaction"} {
+			labels := fmt.Sprintf(`limit="%v"`, name)
+			allowed = append(allowed, sample{labels: labels, value: float64(stats[name].Allowed)})
+			rejected = append(rejected, sample{labels: labels, value: float64(stats[name].Rejected)})
+			buckets = append(buckets, sample{labels: labels, value: float64(stats[name].Buckets)})
+		}
+		writeMetric(w, "dragonfly_rate_limit_allowed_total", "counter", "Number of actions allowed by rate limits.", allowed...)
+		writeMetric(w, "dragonfly_rate_limit_rejected_total", "counter", "Number of actions rejected by rate limits.", rejected...)
+		writeMetric(w, "dragonfly_rate_limit_buckets", "gauge", "Number of keys that are currently rate limited.", buckets...)
+	}
+
+	received, sent := session.PacketCounts()
+	writeMetric(w, "dragonfly_packets_received_total", "counter", "Number of packets received from clients.", sample{value: float64(received)})
+	writeMetric(w, "dragonfly_packets_sent_total", "counter", "Number of packets sent to clients.", sample{value: float64(sent)})
+
+	var provider []sample
+	for _, op := range []struct {
+		name string
+		op   *operation
+	}{{"load_column", &m.load}, {"store_column", &m.store}, {"save_settings", &m.settings}} {
+		labels := fmt.Sprintf(`operation="%v"`, op.name)
+		provider = append(provider,
+			sample{suffix: "_sum", labels: labels, value: op.op.total.Load().Seconds()},
+			sample{suffix: "_count", labels: labels, value: float64(op.op.count.Load())},
+		)
+	}
+	writeMetric(w, "dragonfly_provider_duration_seconds", "summary", "Time spent on world provider operations.", provider...)
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	writeMetric(w, "go_goroutines", "gauge", "Number of goroutines that currently exist.", sample{value: float64(runtime.NumGoroutine())})
+	writeMetric(w, "go_memstats_heap_alloc_bytes", "gauge", "Number of heap bytes allocated and still in use.", sample{value: float64(mem.HeapAlloc)})
+}
+
+// sample is a single sample of a metric. suffix is appended to the name of the metric, such as '_sum' for
+// summaries.
+type sample struct {
+	suffix, labels string
+	value          float64
+}
+
+// writeMetric writes a metric with its samples in the Prometheus text format to the io.Writer passed.
+func writeMetric(w io.Writer, name, typ, help string, samples ...sample) {
+	_, _ = fmt.Fprintf(w, "# HELP %v %v\n# TYPE %v %v\n", name, help, name, typ)
+	for _, s := range samples {
+		if s.labels != "" {
+			_, _ = fmt.Fprintf(w, "%v%v{%v} %v\n", name, s.suffix, s.labels, s.value)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%v%v %v\n", name, s.suffix, s.value)
+	}
+}
+
+// escape escapes a label value for the Prometheus text format.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"github.com/df-mc/atomic"
+	"github.com/df-mc/dragonfly/server/world"
+	"time"
+)
+
+// operation records the number of times an operation was performed and the total time spent on it.
+type operation struct {
+	count atomic.Uint64
+	total atomic.Duration
+}
+
+// observe records an operation that started at the time passed.
+func (o *operation) observe(start time.Time) {
+	o.count.Inc()
+	o.total.Add(time.Since(start))
+}
+
+// Provider wraps the world.Provider passed, so that the time spent loading and storing columns and saving settings
+// using it is collected by the Metrics. The world.Provider returned should be used in place of the one passed.
+func (m *Metrics) Provider(p world.Provider) world.Provider {
+	return provider{Provider: p, m: m}
+}
+
+// provider is a world.Provider that records the latency of the world.Provider it wraps.
+type provider struct {
+	world.Provider
+	m *Metrics
+}
+
+// LoadColumn ...
+func (p provider) LoadColumn(pos world.ChunkPos, dim world.Dimension) (*world.Column, error) {
+	defer p.m.load.observe(time.Now())
+	return p.Provider.LoadColumn(pos, dim)
+}
+
+// StoreColumn ...
+func (p provider) StoreColumn(pos world.ChunkPos, dim world.Dimension, col *world.Column) error {
+	defer p.m.store.observe(time.Now())
+	return p.Provider.StoreColumn(pos, dim, col)
+}
+
+// SaveSettings ...
+func (p provider) SaveSettings(s *world.Settings) {
+	defer p.m.settings.observe(time.Now())
+	p.Provider.SaveSettings(s)
+}
@@ -0,0 +1,421 @@
+package moderation
+
+import (
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"net"
+	"strings"
+	"time"
+)
+
+// Commands returns the /mute, /warn, /history, /ban, /ban-ip, /unban, /unban-ip, /banlist and /whitelist commands
+// operating on the Manager passed. Players on the Server passed are disconnected when they are banned. The commands
+// may be registered using cmd.Register. allow is called to check if a cmd.Source may execute the commands. If nil
+// and no cmd.Permissions were set using cmd.SetPermissions, the commands may only be executed by sources that are
+// not players, such as the console. If nil and cmd.Permissions were set, the permission node of each command (such
+// as 'command.mute') decides which sources may execute it.
+func Commands(m *Manager, srv *server.Server, allow func(src cmd.Source) bool) []cmd.Command {
+	if allow == nil {
+		allow = func(src cmd.Source) bool {
+			_, ok := src.(*player.Player)
+			return !ok || cmd.HasPermissions()
+		}
+	}
+	c := command{m: m, srv: srv, allow: allow}
+	return []cmd.Command{
+		cmd.New("mute", "Mutes a player, preventing them from chatting.", nil, muteCommand{m: m, allow: allow}),
+		cmd.New("warn", "Warns a player.", nil, warnCommand{m: m, allow: allow}),
+		cmd.New("history", "Shows the punishment history of a player.", nil, historyCommand{m: m, allow: allow}),
+		cmd.New("ban", "Bans a player from the server.", nil, banCommand{command: c}),
+		cmd.New("ban-ip", "Bans an IP address from the server.", nil, banIPCommand{command: c}),
+		cmd.New("unban", "Unbans a player.", []string{"pardon"}, unbanCommand{command: c}),
+		cmd.New("unban-ip", "Unbans an IP address.", []string{"pardon-ip"}, unbanIPCommand{command: c}),
+		cmd.New("banlist", "Lists all banned players and IP addresses.", nil, banListCommand{command: c}),
+		cmd.New("whitelist", "Manages the whitelist of the server.", nil,
+			whitelistOnCommand{command: c},
+			whitelistOffCommand{command: c},
+			whitelistListCommand{command: c},
+			whitelistAddCommand{command: c},
+			whitelistRemoveCommand{command: c},
+		),
+	}
+}
+
+// muteCommand implements the /mute command.
+type muteCommand struct {
+	m     *Manager
+	allow func(src cmd.Source) bool
+
+	Targets  []cmd.Target              `cmd:"target"`
+	Duration cmd.Optional[string]      `cmd:"duration"`
+	Reason   cmd.Optional[cmd.Varargs] `cmd:"reason"`
+}
+
+// Run ...
+func (c muteCommand) Run(src cmd.Source, o *cmd.Output) {
+	var d time.Duration
+	if s, ok := c.Duration.Load(); ok && s != "0" {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			o.Errorf("Invalid duration '%v': use a duration such as 30m or 12h, or 0 for a permanent mute.", s)
+			return
+		}
+	}
+	reason, _ := c.Reason.Load()
+	punish(c.m, src, o, c.Targets, Mute(), string(reason), d)
+}
+
+// Allow ...
+func (c muteCommand) Allow(src cmd.Source) bool {
+	return c.allow(src)
+}
+
+// warnCommand implements the /warn command.
+type warnCommand struct {
+	m     *Manager
+	allow func(src cmd.Source) bool
+
+	Targets []cmd.Target              `cmd:"target"`
+	Reason  cmd.Optional[cmd.Varargs] `cmd:"reason"`
+}
+
+// Run ...
+func (c warnCommand) Run(src cmd.Source, o *cmd.Output) {
+	reason, _ := c.Reason.Load()
+	punish(c.m, src, o, c.Targets, Warning(), string(reason), 0)
+}
+
+// Allow ...
+func (c warnCommand) Allow(src cmd.Source) bool {
+	return c.allow(src)
+}
+
+// historyCommand implements the /history command.
+type historyCommand struct {
+	m     *Manager
+	allow func(src cmd.Source) bool
+
+	Targets []cmd.Target `cmd:"target"`
+}
+
+// Run ...
+func (c historyCommand) Run(_ cmd.Source, o *cmd.Output) {
+	for _, t := range c.Targets {
+		p, ok := t.(*player.Player)
+		if !ok {
+			continue
+		}
+		history, err := c.m.History(p.UUID())
+		if err != nil {
+			o.Errorf("Could not load the history of %v: %v", p.Name(), err)
+			continue
+		}
+		if len(history) == 0 {
+			o.Printf("%v has no punishment history.", p.Name())
+			continue
+		}
+		o.Printf("Punishment history of %v:", p.Name())
+		for _, h := range history {
+			line := []string{h.Issued.Format("2006-01-02 15:04"), h.Type.String(), "by " + h.Source}
+			if h.Reason != "" {
+				line = append(line, "("+h.Reason+")")
+			}
+			if h.Active() {
+				line = append(line, "[active]")
+			}
+			o.Print(strings.Join(line, " "))
+		}
+	}
+}
+
+// Allow ...
+func (c historyCommand) Allow(src cmd.Source) bool {
+	return c.allow(src)
+}
+
+// punish issues a Punishment of the PunishmentType passed to all players in the targets passed.
+func punish(m *Manager, src cmd.Source, o *cmd.Output, targets []cmd.Target, t PunishmentType, reason string, d time.Duration) {
+	source := sourceName(src)
+	for _, target := range targets {
+		p, ok := target.(*player.Player)
+		if !ok {
+			continue
+		}
+		punishment, issued, err := m.Punish(p.UUID(), t, reason, source, d)
+		if err != nil {
+			o.Errorf("Could not issue %v to %v: %v", t, p.Name(), err)
+			continue
+		} else if !issued {
+			continue
+		}
+		m.Notify(p, punishment)
+		o.Printf("Issued %v to %v.", t, p.Name())
+	}
+}
+
+// command holds the fields shared by the ban and whitelist commands returned by Commands.
+type command struct {
+	m     *Manager
+	srv   *server.Server
+	allow func(src cmd.Source) bool
+}
+
+// Allow ...
+func (c command) Allow(src cmd.Source) bool {
+	return c.allow(src)
+}
+
+// kick disconnects all players on the server that are banned.
+func (c command) kick() {
+	for _, p := range c.srv.Players() {
+		if b, ok := c.m.BannedPlayer(p); ok {
+			p.Disconnect(Message(b))
+		}
+	}
+}
+
+// banCommand implements the /ban command.
+type banCommand struct {
+	command
+	Player   string                    `cmd:"player"`
+	Duration cmd.Optional[string]      `cmd:"duration"`
+	Reason   cmd.Optional[cmd.Varargs] `cmd:"reason"`
+}
+
+// Run ...
+func (c banCommand) Run(src cmd.Source, o *cmd.Output) {
+	d, ok := parseDuration(c.Duration, o)
+	if !ok {
+		return
+	}
+	reason, _ := c.Reason.Load()
+
+	t, value, name := KeyName(), c.Player, c.Player
+	if p, ok := c.srv.PlayerByName(c.Player); ok && p.XUID() != "" {
+		// Ban the XUID of players that are online, so that the ban keeps applying if they change their name.
+		t, value, name = KeyXUID(), p.XUID(), p.Name()
+	}
+	if _, err := c.m.BanKey(t, value, name, string(reason), sourceName(src), d); err != nil {
+		o.Errorf("Could not ban %v: %v", name, err)
+		return
+	}
+	c.kick()
+	o.Printf("Banned %v.", name)
+}
+
+// banIPCommand implements the /ban-ip command.
+type banIPCommand struct {
+	command
+	Target   string                    `cmd:"address"`
+	Duration cmd.Optional[string]      `cmd:"duration"`
+	Reason   cmd.Optional[cmd.Varargs] `cmd:"reason"`
+}
+
+// Run ...
+func (c banIPCommand) Run(src cmd.Source, o *cmd.Output) {
+	d, ok := parseDuration(c.Duration, o)
+	if !ok {
+		return
+	}
+	reason, _ := c.Reason.Load()
+
+	addr, name := host(c.Target), ""
+	if p, ok := c.srv.PlayerByName(c.Target); ok {
+		addr, name = host(p.Addr().String()), p.Name()
+	} else if net.ParseIP(addr) == nil {
+		o.Errorf("'%v' is neither a valid IP address nor a player that is online.", c.Target)
+		return
+	}
+	if _, err := c.m.BanKey(KeyIP(), addr, name, string(reason), sourceName(src), d); err != nil {
+		o.Errorf("Could not ban %v: %v", addr, err)
+		return
+	}
+	c.kick()
+	o.Printf("Banned IP address %v.", addr)
+}
+
+// unbanCommand implements the /unban command.
+type unbanCommand struct {
+	command
+	Player string `cmd:"player"`
+}
+
+// Run ...
+func (c unbanCommand) Run(_ cmd.Source, o *cmd.Output) {
+	removed, err := c.m.UnbanName(c.Player)
+	if err != nil {
+		o.Errorf("Could not unban %v: %v", c.Player, err)
+		return
+	} else if len(removed) == 0 {
+		o.Errorf("%v is not banned.", c.Player)
+		return
+	}
+	o.Printf("Unbanned %v.", c.Player)
+}
+
+// unbanIPCommand implements the /unban-ip command.
+type unbanIPCommand struct {
+	command
+	Address string `cmd:"address"`
+}
+
+// Run ...
+func (c unbanIPCommand) Run(_ cmd.Source, o *cmd.Output) {
+	removed, err := c.m.UnbanKey(KeyIP(), c.Address)
+	if err != nil {
+		o.Errorf("Could not unban %v: %v", c.Address, err)
+		return
+	} else if !removed {
+		o.Errorf("%v is not banned.", c.Address)
+		return
+	}
+	o.Printf("Unbanned IP address %v.", c.Address)
+}
+
+// banListCommand implements the /banlist command.
+type banListCommand struct {
+	command
+}
+
+// Run ...
+func (c banListCommand) Run(_ cmd.Source, o *cmd.Output) {
+	bans := c.m.KeyBans()
+	if len(bans) == 0 {
+		o.Print("There are no bans.")
+		return
+	}
+	o.Printf("There are %v ban(s):", len(bans))
+	for _, b := range bans {
+		line := []string{b.Key.String(), b.Value}
+		if b.Name != "" && b.Name != b.Value {
+			line = append(line, "("+b.Name+")")
+		}
+		line = append(line, "by "+b.Source)
+		if b.Reason != "" {
+			line = append(line, "for "+b.Reason)
+		}
+		if !b.Expiry.IsZero() {
+			line = append(line, "until "+b.Expiry.Format("2006-01-02 15:04"))
+		}
+		o.Print(strings.Join(line, " "))
+	}
+}
+
+// whitelistOnCommand implements the /whitelist on command.
+type whitelistOnCommand struct {
+	command
+	On cmd.SubCommand `cmd:"on"`
+}
+
+// Run ...
+func (c whitelistOnCommand) Run(_ cmd.Source, o *cmd.Output) {
+	if err := c.m.SetWhitelistEnabled(true); err != nil {
+		o.Errorf("Could not enable the whitelist: %v", err)
+		return
+	}
+	o.Print("Enabled the whitelist.")
+}
+
+// whitelistOffCommand implements the /whitelist off command.
+type whitelistOffCommand struct {
+	command
+	Off cmd.SubCommand `cmd:"off"`
+}
+
+// Run ...
+func (c whitelistOffCommand) Run(_ cmd.Source, o *cmd.Output) {
+	if err := c.m.SetWhitelistEnabled(false); err != nil {
+		o.Errorf("Could not disable the whitelist: %v", err)
+		return
+	}
+	o.Print("Disabled the whitelist.")
+}
+
+// whitelistListCommand implements the /whitelist list command.
+type whitelistListCommand struct {
+	command
+	List cmd.SubCommand `cmd:"list"`
+}
+
+// Run ...
+func (c whitelistListCommand) Run(_ cmd.Source, o *cmd.Output) {
+	entries := c.m.Whitelist()
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name
+	}
+	state := "disabled"
+	if c.m.WhitelistEnabled() {
+		state = "enabled"
+	}
+	o.Printf("The whitelist is %v and holds %v player(s): %v", state, len(names), strings.Join(names, ", "))
+}
+
+// whitelistAddCommand implements the /whitelist add command.
+type whitelistAddCommand struct {
+	command
+	Add    cmd.SubCommand `cmd:"add"`
+	Player string         `cmd:"player"`
+}
+
+// Run ...
+func (c whitelistAddCommand) Run(_ cmd.Source, o *cmd.Output) {
+	name, xuid := c.Player, ""
+	if p, ok := c.srv.PlayerByName(c.Player); ok {
+		name, xuid = p.Name(), p.XUID()
+	}
+	added, err := c.m.AddToWhitelist(name, xuid)
+	if err != nil {
+		o.Errorf("Could not add %v to the whitelist: %v", name, err)
+		return
+	} else if !added {
+		o.Errorf("%v is already whitelisted.", name)
+		return
+	}
+	o.Printf("Added %v to the whitelist.", name)
+}
+
+// whitelistRemoveCommand implements the /whitelist remove command.
+type whitelistRemoveCommand struct {
+	command
+	Remove cmd.SubCommand `cmd:"remove"`
+	Player string         `cmd:"player"`
+}
+
+// Run ...
+func (c whitelistRemoveCommand) Run(_ cmd.Source, o *cmd.Output) {
+	removed, err := c.m.RemoveFromWhitelist(c.Player)
+	if err != nil {
+		o.Errorf("Could not remove %v from the whitelist: %v", c.Player, err)
+		return
+	} else if !removed {
+		o.Errorf("%v is not whitelisted.", c.Player)
+		return
+	}
+	o.Printf("Removed %v from the whitelist.", c.Player)
+}
+
+// parseDuration parses the optional duration passed. If it is not set or '0', a duration of 0 is returned, which
+// makes a ban permanent. False is returned if the duration was invalid, in which case an error is added to the
+// cmd.Output.
+func parseDuration(opt cmd.Optional[string], o *cmd.Output) (time.Duration, bool) {
+	s, ok := opt.Load()
+	if !ok || s == "0" {
+		return 0, true
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		o.Errorf("Invalid duration '%v': use a duration such as 30m or 12h, or 0 for a permanent ban.", s)
+		return 0, false
+	}
+	return d, true
+}
+
+// sourceName returns the name of the cmd.Source passed, or 'Server' if it has no name.
+func sourceName(src cmd.Source) string {
+	if n, ok := src.(cmd.NamedTarget); ok {
+		return n.Name()
+	}
+	return "Server"
+}
@@ -0,0 +1,23 @@
+package moderation
+
+import (
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/google/uuid"
+)
+
+// Handler handles events that are called by a Manager. Implementations of Handler may be used to listen to
+// punishments being issued.
+type Handler interface {
+	// HandlePunish handles a Punishment being issued to the player with the UUID passed. ctx.Cancel() may be called
+	// to cancel the Punishment. The Punishment may be changed by modifying *p.
+	HandlePunish(ctx *event.Context, id uuid.UUID, p *Punishment)
+}
+
+// Compile time check to make sure NopHandler implements Handler.
+var _ Handler = NopHandler{}
+
+// NopHandler implements the Handler interface but does not execute any code when an event is called. The default
+// Handler of a Manager is set to NopHandler. Users may embed NopHandler to avoid having to implement each method.
+type NopHandler struct{}
+
+func (NopHandler) HandlePunish(*event.Context, uuid.UUID, *Punishment) {}
@@ -0,0 +1,103 @@
+package moderation
+
+import (
+	"net"
+	"strings"
+)
+
+// KeyBan is a ban of an XUID, name or IP address, rather than of the UUID of a player. Unlike the bans issued using
+// Manager.Punish, a KeyBan may be issued for players that have never joined and may be lifted again.
+type KeyBan struct {
+	// Key is the KeyType of the KeyBan, which specifies what Value holds.
+	Key KeyType
+	// Value is the XUID, name or IP address that is banned.
+	Value string
+	// Name is the name of the player banned, if known. For bans of the type KeyName, Name is equal to Value.
+	Name string
+	// Punishment holds the reason, source and expiry of the KeyBan. Its Type is always Ban().
+	Punishment
+}
+
+// KeyType is a type of key that a KeyBan may be issued for.
+type KeyType struct {
+	key
+}
+
+// KeyXUID returns the KeyType for bans of the XUID of a player. XUID bans keep applying if the player changes its
+// name.
+func KeyXUID() KeyType {
+	return KeyType{0}
+}
+
+// KeyName returns the KeyType for bans of the name of a player. Names are matched case-insensitively.
+func KeyName() KeyType {
+	return KeyType{1}
+}
+
+// KeyIP returns the KeyType for bans of an IP address. All players connecting from the IP address are banned.
+func KeyIP() KeyType {
+	return KeyType{2}
+}
+
+// KeyTypes returns all KeyTypes.
+func KeyTypes() []KeyType {
+	return []KeyType{KeyXUID(), KeyName(), KeyIP()}
+}
+
+type key uint8
+
+// Uint8 returns the key as a uint8.
+func (k key) Uint8() uint8 {
+	return uint8(k)
+}
+
+// String ...
+func (k key) String() string {
+	switch k {
+	case 0:
+		return "xuid"
+	case 1:
+		return "name"
+	case 2:
+		return "ip"
+	}
+	panic("unknown key type")
+}
+
+// keyTypeByName returns the KeyType with the name passed, as returned by its String method.
+func keyTypeByName(name string) (KeyType, bool) {
+	for _, t := range KeyTypes() {
+		if t.String() == name {
+			return t, true
+		}
+	}
+	return KeyType{}, false
+}
+
+// WhitelistEntry is a player on the whitelist.
+type WhitelistEntry struct {
+	// Name is the name of the player. Names are matched case-insensitively.
+	Name string
+	// XUID is the XUID of the player. If empty, the player is matched by its Name only. The XUID of an entry is
+	// filled out the first time the player joins, so that the player remains whitelisted if it changes its name.
+	XUID string `json:",omitempty"`
+}
+
+// normalise returns the value of a KeyBan of the KeyType passed in the form that it is compared in.
+func normalise(t KeyType, value string) string {
+	switch t {
+	case KeyName():
+		return strings.ToLower(value)
+	case KeyIP():
+		return host(value)
+	}
+	return value
+}
+
+// host returns the IP address held by the address passed without its port, if it has one.
+func host(addr string) string {
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		return h
+	}
+	return addr
+}
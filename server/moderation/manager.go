@@ -0,0 +1,493 @@
+// Package moderation implements the punishment of players through bans, mutes and warnings, and a whitelist that
+// controls which players may join a server. Besides the bans issued to the UUID of a player, players may be banned
+// by their XUID, their name or their IP address using KeyBans. All data is stored using a Provider so that it is
+// kept across restarts.
+package moderation
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/player/chat"
+	"github.com/df-mc/dragonfly/server/text"
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Manager manages the Punishments of players, the KeyBans and the whitelist of a server. It may be used as the
+// Allower of a server to prevent banned and non-whitelisted players from joining, and as the chat.Muter to prevent
+// muted players from chatting. A Manager is safe for concurrent use.
+type Manager struct {
+	prov Provider
+	log  server.Logger
+
+	hMu sync.RWMutex
+	h   Handler
+
+	mu        sync.RWMutex
+	bans      map[banKey]KeyBan
+	whitelist []WhitelistEntry
+	enabled   bool
+
+	// mutes holds the active mutes of players that were looked up using Muted, so that the Provider does not have
+	// to be read every time a player chats.
+	mutesMu sync.Mutex
+	mutes   map[uuid.UUID]cachedMute
+}
+
+// cachedMute is an entry in the mute cache of a Manager. If muted is false, the player had no active mute.
+type cachedMute struct {
+	p     Punishment
+	muted bool
+}
+
+// banKey is the key under which a KeyBan is stored in a Manager.
+type banKey struct {
+	t     KeyType
+	value string
+}
+
+// Compile time check to make sure Manager implements server.Allower and chat.Muter.
+var (
+	_ server.Allower = (*Manager)(nil)
+	_ chat.Muter     = (*Manager)(nil)
+)
+
+// New creates a Manager that stores its data using the Provider passed. If nil is passed, NopProvider is used. The
+// KeyBans and whitelist are loaded from the Provider when the Manager is created. An error is returned if they could
+// not be loaded. log is used to report errors that cannot be returned, such as those that occur while looking up
+// mutes. If nil, a new logrus.Logger is used.
+func New(prov Provider, log server.Logger) (*Manager, error) {
+	if prov == nil {
+		prov = NopProvider{}
+	}
+	if log == nil {
+		log = logrus.New()
+	}
+	enabled, entries, err := prov.Whitelist()
+	if err != nil {
+		return nil, fmt.Errorf("load whitelist: %w", err)
+	}
+	bans, err := prov.KeyBans()
+	if err != nil {
+		return nil, fmt.Errorf("load bans: %w", err)
+	}
+	m := &Manager{prov: prov, log: log, h: NopHandler{}, mutes: map[uuid.UUID]cachedMute{}, bans: make(map[banKey]KeyBan, len(bans)), whitelist: entries, enabled: enabled}
+	for _, b := range bans {
+		if b.Active() {
+			m.bans[banKey{t: b.Key, value: normalise(b.Key, b.Value)}] = b
+		}
+	}
+	return m, nil
+}
+
+// Handle changes the Handler of the Manager. If nil is passed, NopHandler is used.
+func (m *Manager) Handle(h Handler) {
+	if h == nil {
+		h = NopHandler{}
+	}
+	m.hMu.Lock()
+	defer m.hMu.Unlock()
+	m.h = h
+}
+
+// Handler returns the Handler of the Manager.
+func (m *Manager) Handler() Handler {
+	m.hMu.RLock()
+	defer m.hMu.RUnlock()
+	return m.h
+}
+
+// Punish issues a Punishment of the PunishmentType passed to the player with the UUID passed and returns it. If the
+// duration passed is 0 or lower, the Punishment never expires. Punish returns false if the Punishment was cancelled
+// by the Handler of the Manager. Players that are online may be notified of the Punishment using Notify.
+func (m *Manager) Punish(id uuid.UUID, t PunishmentType, reason, source string, duration time.Duration) (Punishment, bool, error) {
+	p := Punishment{Type: t, Reason: reason, Source: source, Issued: time.Now()}
+	if duration > 0 && t != Warning() {
+		p.Expiry = p.Issued.Add(duration)
+	}
+	ctx := event.C()
+	if m.Handler().HandlePunish(ctx, id, &p); ctx.Cancelled() {
+		return p, false, nil
+	}
+	if err := m.prov.AddPunishment(id, p); err != nil {
+		return p, false, fmt.Errorf("punish: %w", err)
+	}
+	if p.Type == Mute() {
+		m.mutesMu.Lock()
+		// Players not yet in the cache have the mute loaded from the Provider once they are looked up.
+		if c, ok := m.mutes[id]; ok && (!c.muted || outlasts(p, c.p)) {
+			m.mutes[id] = cachedMute{p: p, muted: true}
+		}
+		m.mutesMu.Unlock()
+	}
+	return p, true, nil
+}
+
+// Notify notifies the player passed of a Punishment that was issued to it. A banned player is disconnected.
+func (m *Manager) Notify(pl *player.Player, p Punishment) {
+	switch p.Type {
+	case Ban():
+		pl.Disconnect(Message(p))
+	default:
+		pl.Message(Message(p))
+	}
+}
+
+// History returns all Punishments issued to the player with the UUID passed, in the order in which they were issued.
+func (m *Manager) History(id uuid.UUID) ([]Punishment, error) {
+	return m.prov.Punishments(id)
+}
+
+// Active returns the active Punishment of the PunishmentType passed of the player with the UUID passed. If multiple
+// Punishments of the type are active, the one expiring last is returned. If none are active, false is returned.
+func (m *Manager) Active(id uuid.UUID, t PunishmentType) (Punishment, bool, error) {
+	punishments, err := m.prov.Punishments(id)
+	if err != nil {
+		return Punishment{}, false, err
+	}
+	var (
+		active Punishment
+		found  bool
+	)
+	for _, p := range punishments {
+		if p.Type != t || !p.Active() {
+			continue
+		}
+		if !found || outlasts(p, active) {
+			active, found = p, true
+		}
+	}
+	return active, found, nil
+}
+
+// outlasts checks if the Punishment a expires after the Punishment b.
+func outlasts(a, b Punishment) bool {
+	return a.Expiry.IsZero() || (!b.Expiry.IsZero() && a.Expiry.After(b.Expiry))
+}
+
+// Allow prevents players with an active ban of their UUID, XUID, name or IP address from joining. If the whitelist
+// is enabled, players not on it are also prevented from joining. Allow implements the server.Allower interface, so
+// that the Manager may be set as the Allower of a server.
+func (m *Manager) Allow(addr net.Addr, d login.IdentityData, _ login.ClientData) (string, bool) {
+	if p, banned := m.BannedIdentity(addr, d); banned {
+		return Message(p), false
+	}
+	if !m.WhitelistEnabled() {
+		return "", true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.whitelistIndex(d.DisplayName, d.XUID)
+	if i == -1 {
+		return "You are not whitelisted on this server.", false
+	}
+	if e := m.whitelist[i]; e.XUID == "" && d.XUID != "" {
+		// Remember the XUID of the player, so that it remains whitelisted if it changes its name. Failing to store
+		// it is not a reason to prevent the player from joining.
+		m.whitelist[i].XUID = d.XUID
+		_ = m.saveWhitelist()
+	}
+	return "", true
+}
+
+// BannedIdentity looks up an active ban of the UUID, XUID or name in the login.IdentityData passed or of the IP
+// address passed. If found, the ban is returned as a Punishment and the bool returned is true.
+func (m *Manager) BannedIdentity(addr net.Addr, d login.IdentityData) (Punishment, bool) {
+	if id, err := uuid.Parse(d.Identity); err == nil {
+		if p, banned, _ := m.Active(id, Ban()); banned {
+			return p, true
+		}
+	}
+	if d.XUID != "" {
+		if b, ok := m.KeyBanned(KeyXUID(), d.XUID); ok {
+			return b.Punishment, true
+		}
+	}
+	if b, ok := m.KeyBanned(KeyName(), d.DisplayName); ok {
+		return b.Punishment, true
+	}
+	if addr != nil {
+		if b, ok := m.KeyBanned(KeyIP(), addr.String()); ok {
+			return b.Punishment, true
+		}
+	}
+	return Punishment{}, false
+}
+
+// BannedPlayer looks up an active ban of the player passed, as BannedIdentity does. It may be used to disconnect
+// players that are online when a KeyBan is issued.
+func (m *Manager) BannedPlayer(p *player.Player) (Punishment, bool) {
+	return m.BannedIdentity(p.Addr(), login.IdentityData{Identity: p.UUID().String(), XUID: p.XUID(), DisplayName: p.Name()})
+}
+
+// KeyBanned looks up an active KeyBan of the KeyType and value passed. If found, the KeyBan is returned and the bool
+// returned is true.
+func (m *Manager) KeyBanned(t KeyType, value string) (KeyBan, bool) {
+	m.mu.RLock()
+	b, ok := m.bans[banKey{t: t, value: normalise(t, value)}]
+	m.mu.RUnlock()
+	if !ok || !b.Active() {
+		return KeyBan{}, false
+	}
+	return b, true
+}
+
+// BanKey bans the XUID, name or IP address passed, replacing any existing KeyBan of it. name is the name of the
+// player banned, if known. If the duration passed is 0 or lower, the KeyBan never expires. The KeyBan is stored
+// using the Provider and returned. Players that are online are not disconnected by BanKey: BannedPlayer may be used
+// to find out which players should be.
+func (m *Manager) BanKey(t KeyType, value, name, reason, source string, duration time.Duration) (KeyBan, error) {
+	value = normalise(t, value)
+	if value == "" {
+		return KeyBan{}, fmt.Errorf("ban %v: value must not be empty", t)
+	}
+	if t == KeyName() {
+		name = value
+	}
+	b := KeyBan{Key: t, Value: value, Name: name, Punishment: Punishment{Type: Ban(), Reason: reason, Source: source, Issued: time.Now()}}
+	if duration > 0 {
+		b.Expiry = b.Issued.Add(duration)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := banKey{t: t, value: value}
+	prev, existed := m.bans[k]
+	m.bans[k] = b
+	if err := m.saveKeyBans(); err != nil {
+		if existed {
+			m.bans[k] = prev
+		} else {
+			delete(m.bans, k)
+		}
+		return KeyBan{}, err
+	}
+	return b, nil
+}
+
+// UnbanKey removes the KeyBan of the KeyType and value passed. UnbanKey returns false if no such KeyBan existed.
+func (m *Manager) UnbanKey(t KeyType, value string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := banKey{t: t, value: normalise(t, value)}
+	if _, ok := m.bans[k]; !ok {
+		return false, nil
+	}
+	delete(m.bans, k)
+	return true, m.saveKeyBans()
+}
+
+// UnbanName removes the KeyBan of the name passed and all XUID KeyBans of players with that name. The KeyBans
+// removed are returned.
+func (m *Manager) UnbanName(name string) ([]KeyBan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var removed []KeyBan
+	for k, b := range m.bans {
+		if k.t != KeyIP() && strings.EqualFold(b.Name, name) {
+			removed = append(removed, b)
+			delete(m.bans, k)
+		}
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	return removed, m.saveKeyBans()
+}
+
+// KeyBans returns all active KeyBans, sorted by the time at which they were issued. KeyBans that have expired are
+// removed.
+func (m *Manager) KeyBans() []KeyBan {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bans := make([]KeyBan, 0, len(m.bans))
+	expired := false
+	for k, b := range m.bans {
+		if !b.Active() {
+			delete(m.bans, k)
+			expired = true
+			continue
+		}
+		bans = append(bans, b)
+	}
+	if expired {
+		// Failing to remove expired bans from the Provider is harmless: They are ignored when loaded.
+		_ = m.saveKeyBans()
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Issued.Before(bans[j].Issued)
+	})
+	return bans
+}
+
+// saveKeyBans stores all active KeyBans of the Manager using its Provider. m.mu must be held when saveKeyBans is
+// called.
+func (m *Manager) saveKeyBans() error {
+	bans := make([]KeyBan, 0, len(m.bans))
+	for _, b := range m.bans {
+		if b.Active() {
+			bans = append(bans, b)
+		}
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Issued.Before(bans[j].Issued)
+	})
+	if err := m.prov.SaveKeyBans(bans); err != nil {
+		return fmt.Errorf("save bans: %w", err)
+	}
+	return nil
+}
+
+// WhitelistEnabled checks if the whitelist is enabled. If it is, only players on the whitelist may join.
+func (m *Manager) WhitelistEnabled() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled
+}
+
+// SetWhitelistEnabled enables or disables the whitelist. Players that are online are not disconnected when the
+// whitelist is enabled.
+func (m *Manager) SetWhitelistEnabled(enabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	prev := m.enabled
+	m.enabled = enabled
+	if err := m.saveWhitelist(); err != nil {
+		m.enabled = prev
+		return err
+	}
+	return nil
+}
+
+// Whitelist returns all entries on the whitelist, sorted by name.
+func (m *Manager) Whitelist() []WhitelistEntry {
+	m.mu.RLock()
+	entries := slices.Clone(m.whitelist)
+	m.mu.RUnlock()
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+	})
+	return entries
+}
+
+// Whitelisted checks if a player with the name or XUID passed is on the whitelist. xuid may be empty.
+func (m *Manager) Whitelisted(name, xuid string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.whitelistIndex(name, xuid) != -1
+}
+
+// AddToWhitelist adds a player with the name and XUID passed to the whitelist. xuid may be empty, in which case it
+// is filled out when the player first joins. AddToWhitelist returns false if the player was already on the
+// whitelist.
+func (m *Manager) AddToWhitelist(name, xuid string) (bool, error) {
+	if name == "" {
+		return false, fmt.Errorf("add to whitelist: name must not be empty")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.whitelistIndex(name, xuid) != -1 {
+		return false, nil
+	}
+	m.whitelist = append(m.whitelist, WhitelistEntry{Name: name, XUID: xuid})
+	if err := m.saveWhitelist(); err != nil {
+		m.whitelist = m.whitelist[:len(m.whitelist)-1]
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveFromWhitelist removes the player with the name passed from the whitelist. RemoveFromWhitelist returns false
+// if the player was not on the whitelist.
+func (m *Manager) RemoveFromWhitelist(name string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.whitelistIndex(name, "")
+	if i == -1 {
+		return false, nil
+	}
+	m.whitelist = slices.Delete(m.whitelist, i, i+1)
+	return true, m.saveWhitelist()
+}
+
+// whitelistIndex returns the index of the whitelist entry matching the XUID passed or, if none does, the name
+// passed. Entries that hold a different XUID than the one passed never match by name. -1 is returned if no entry
+// matches. m.mu must be held when whitelistIndex is called.
+func (m *Manager) whitelistIndex(name, xuid string) int {
+	if xuid != "" {
+		if i := slices.IndexFunc(m.whitelist, func(e WhitelistEntry) bool { return e.XUID == xuid }); i != -1 {
+			return i
+		}
+	}
+	return slices.IndexFunc(m.whitelist, func(e WhitelistEntry) bool {
+		return (e.XUID == "" || xuid == "") && strings.EqualFold(e.Name, name)
+	})
+}
+
+// saveWhitelist stores the whitelist using the Provider of the Manager. m.mu must be held when saveWhitelist is
+// called.
+func (m *Manager) saveWhitelist() error {
+	if err := m.prov.SaveWhitelist(m.enabled, slices.Clone(m.whitelist)); err != nil {
+		return fmt.Errorf("save whitelist: %w", err)
+	}
+	return nil
+}
+
+// Close closes the Provider of the Manager.
+func (m *Manager) Close() error {
+	return m.prov.Close()
+}
+
+// Muted checks if the player with the UUID passed has an active mute. If it does, a message describing the mute is
+// returned along with true. Muted implements the chat.Muter interface, so that the Manager may be passed to
+// chat.SetMuter to prevent muted players from chatting. Mutes are only loaded from the Provider the first time a
+// player is looked up. If they could not be loaded, the error is logged and the player is treated as muted.
+func (m *Manager) Muted(id uuid.UUID) (string, bool) {
+	m.mutesMu.Lock()
+	defer m.mutesMu.Unlock()
+	c, ok := m.mutes[id]
+	if !ok {
+		p, muted, err := m.Active(id, Mute())
+		if err != nil {
+			m.log.Errorf("look up mute of %v: %v", id, err)
+			return text.Colourf("<red>You cannot chat right now.</red>"), true
+		}
+		c = cachedMute{p: p, muted: muted}
+		m.mutes[id] = c
+	}
+	if !c.muted {
+		return "", false
+	} else if !c.p.Active() {
+		m.mutes[id] = cachedMute{}
+		return "", false
+	}
+	return Message(c.p), true
+}
+
+// Message returns a message describing the Punishment passed, as it may be shown to the player punished.
+func Message(p Punishment) string {
+	var msg string
+	switch p.Type {
+	case Ban():
+		msg = "You are banned from this server"
+	case Mute():
+		msg = "You are muted"
+	default:
+		msg = "You have received a warning"
+	}
+	if p.Reason != "" {
+		msg += ": " + p.Reason
+	}
+	if !p.Expiry.IsZero() && p.Type != Warning() {
+		msg += fmt.Sprintf(" (expires in %v)", time.Until(p.Expiry).Round(time.Second))
+	}
+	return text.Colourf("<red>%v</red>", msg)
+}
@@ -0,0 +1,389 @@
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/df-mc/goleveldb/leveldb/opt"
+	"github.com/df-mc/goleveldb/leveldb/util"
+	"github.com/google/uuid"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Provider is a provider for the Punishments of players, the KeyBans and the whitelist of a Manager.
+// Implementations must be safe for concurrent use.
+type Provider interface {
+	// Punishments returns all Punishments issued to the player with the UUID passed, in the order in which they
+	// were issued. Punishments returns an empty slice if none were issued.
+	Punishments(id uuid.UUID) ([]Punishment, error)
+	// AddPunishment stores a Punishment issued to the player with the UUID passed.
+	AddPunishment(id uuid.UUID, p Punishment) error
+	// KeyBans returns all KeyBans stored.
+	KeyBans() ([]KeyBan, error)
+	// SaveKeyBans stores the KeyBans passed, replacing all KeyBans stored previously.
+	SaveKeyBans(bans []KeyBan) error
+	// Whitelist returns if the whitelist is enabled and all entries on it.
+	Whitelist() (enabled bool, entries []WhitelistEntry, err error)
+	// SaveWhitelist stores if the whitelist is enabled and all entries on it, replacing the whitelist stored
+	// previously.
+	SaveWhitelist(enabled bool, entries []WhitelistEntry) error
+	// Close closes the Provider.
+	Close() error
+}
+
+// NopProvider is a Provider that does not store any data. Punishments added to it are lost immediately and the
+// KeyBans and whitelist are lost when the server stops.
+type NopProvider struct{}
+
+// Compile time check to make sure NopProvider implements Provider.
+var _ Provider = NopProvider{}
+
+func (NopProvider) Punishments(uuid.UUID) ([]Punishment, error) { return nil, nil }
+func (NopProvider) AddPunishment(uuid.UUID, Punishment) error   { return nil }
+func (NopProvider) KeyBans() ([]KeyBan, error)                  { return nil, nil }
+func (NopProvider) SaveKeyBans([]KeyBan) error                  { return nil }
+func (NopProvider) Whitelist() (bool, []WhitelistEntry, error)  { return false, nil, nil }
+func (NopProvider) SaveWhitelist(bool, []WhitelistEntry) error  { return nil }
+func (NopProvider) Close() error                                { return nil }
+
+// jsonPunishment is the JSON representation of a Punishment.
+type jsonPunishment struct {
+	Type   string
+	Reason string
+	Source string
+	Issued int64
+	Expiry int64 `json:",omitempty"`
+}
+
+// jsonKeyBan is the JSON representation of a KeyBan.
+type jsonKeyBan struct {
+	Key    string
+	Value  string
+	Name   string `json:",omitempty"`
+	Reason string `json:",omitempty"`
+	Source string
+	Issued int64
+	Expiry int64 `json:",omitempty"`
+}
+
+// jsonWhitelist is the JSON representation of the whitelist.
+type jsonWhitelist struct {
+	Enabled bool
+	Entries []WhitelistEntry
+}
+
+// encodePunishments converts Punishments to their JSON representation.
+func encodePunishments(punishments []Punishment) []jsonPunishment {
+	data := make([]jsonPunishment, len(punishments))
+	for i, p := range punishments {
+		data[i] = jsonPunishment{Type: p.Type.String(), Reason: p.Reason, Source: p.Source, Issued: p.Issued.Unix()}
+		if !p.Expiry.IsZero() {
+			data[i].Expiry = p.Expiry.Unix()
+		}
+	}
+	return data
+}
+
+// decodePunishments converts the JSON representation of Punishments back to Punishments.
+func decodePunishments(data []jsonPunishment) []Punishment {
+	punishments := make([]Punishment, 0, len(data))
+	for _, d := range data {
+		p := Punishment{Reason: d.Reason, Source: d.Source, Issued: time.Unix(d.Issued, 0)}
+		if d.Expiry != 0 {
+			p.Expiry = time.Unix(d.Expiry, 0)
+		}
+		for _, t := range PunishmentTypes() {
+			if t.String() == d.Type {
+				p.Type = t
+			}
+		}
+		punishments = append(punishments, p)
+	}
+	return punishments
+}
+
+// encodeKeyBan converts a KeyBan to its JSON representation.
+func encodeKeyBan(b KeyBan) jsonKeyBan {
+	d := jsonKeyBan{Key: b.Key.String(), Value: b.Value, Name: b.Name, Reason: b.Reason, Source: b.Source, Issued: b.Issued.Unix()}
+	if !b.Expiry.IsZero() {
+		d.Expiry = b.Expiry.Unix()
+	}
+	return d
+}
+
+// decodeKeyBan converts the JSON representation of a KeyBan back to a KeyBan. False is returned if the KeyType of
+// the KeyBan is unknown.
+func decodeKeyBan(d jsonKeyBan) (KeyBan, bool) {
+	t, ok := keyTypeByName(d.Key)
+	if !ok {
+		return KeyBan{}, false
+	}
+	b := KeyBan{Key: t, Value: d.Value, Name: d.Name, Punishment: Punishment{Type: Ban(), Reason: d.Reason, Source: d.Source, Issued: time.Unix(d.Issued, 0)}}
+	if d.Expiry != 0 {
+		b.Expiry = time.Unix(d.Expiry, 0)
+	}
+	return b, true
+}
+
+// JSONProvider is a Provider that stores the Punishments of every player in a separate JSON file in a directory.
+// The KeyBans are stored in a bans.json file and the whitelist in a whitelist.json file in the same directory.
+type JSONProvider struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewJSONProvider creates a JSONProvider that stores its data in the directory passed. The directory is created if
+// it does not yet exist.
+func NewJSONProvider(dir string) (*JSONProvider, error) {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, fmt.Errorf("create punishment directory: %w", err)
+	}
+	return &JSONProvider{dir: dir}, nil
+}
+
+// Punishments ...
+func (j *JSONProvider) Punishments(id uuid.UUID) ([]Punishment, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var data []jsonPunishment
+	if err := j.read(id.String()+".json", &data); err != nil {
+		return nil, fmt.Errorf("read punishments: %w", err)
+	}
+	return decodePunishments(data), nil
+}
+
+// AddPunishment ...
+func (j *JSONProvider) AddPunishment(id uuid.UUID, p Punishment) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var data []jsonPunishment
+	if err := j.read(id.String()+".json", &data); err != nil {
+		return fmt.Errorf("read punishments: %w", err)
+	}
+	data = append(data, encodePunishments([]Punishment{p})...)
+	if err := j.write(id.String()+".json", data); err != nil {
+		return fmt.Errorf("write punishments: %w", err)
+	}
+	return nil
+}
+
+// KeyBans ...
+func (j *JSONProvider) KeyBans() ([]KeyBan, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var data []jsonKeyBan
+	if err := j.read("bans.json", &data); err != nil {
+		return nil, fmt.Errorf("read bans: %w", err)
+	}
+	bans := make([]KeyBan, 0, len(data))
+	for _, d := range data {
+		if b, ok := decodeKeyBan(d); ok {
+			bans = append(bans, b)
+		}
+	}
+	return bans, nil
+}
+
+// SaveKeyBans ...
+func (j *JSONProvider) SaveKeyBans(bans []KeyBan) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	data := make([]jsonKeyBan, len(bans))
+	for i, b := range bans {
+		data[i] = encodeKeyBan(b)
+	}
+	if err := j.write("bans.json", data); err != nil {
+		return fmt.Errorf("write bans: %w", err)
+	}
+	return nil
+}
+
+// Whitelist ...
+func (j *JSONProvider) Whitelist() (bool, []WhitelistEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var d jsonWhitelist
+	if err := j.read("whitelist.json", &d); err != nil {
+		return false, nil, fmt.Errorf("read whitelist: %w", err)
+	}
+	return d.Enabled, d.Entries, nil
+}
+
+// SaveWhitelist ...
+func (j *JSONProvider) SaveWhitelist(enabled bool, entries []WhitelistEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.write("whitelist.json", jsonWhitelist{Enabled: enabled, Entries: entries}); err != nil {
+		return fmt.Errorf("write whitelist: %w", err)
+	}
+	return nil
+}
+
+// Close ...
+func (j *JSONProvider) Close() error {
+	return nil
+}
+
+// read decodes the JSON file with the name passed into v. If the file does not exist, v is left unchanged.
+func (j *JSONProvider) read(name string, v any) error {
+	b, err := os.ReadFile(filepath.Join(j.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// write encodes v and writes it to the JSON file with the name passed.
+func (j *JSONProvider) write(name string, v any) error {
+	b, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(j.dir, name), b, 0644)
+}
+
+// Keys used by the LevelDBProvider. The Punishments of every player are stored under punishmentPrefix followed by
+// the UUID of the player and every KeyBan under banPrefix followed by its type and value.
+var (
+	whitelistKey     = []byte("whitelist")
+	banPrefix        = []byte("ban/")
+	punishmentPrefix = []byte("punishments/")
+)
+
+// LevelDBProvider is a Provider that stores its data in a LevelDB database. Every KeyBan and the Punishments of
+// every player are stored under a separate key, so that the database remains efficient with many players.
+type LevelDBProvider struct {
+	db *leveldb.DB
+	mu sync.Mutex
+}
+
+// NewLevelDBProvider creates a LevelDBProvider that stores its data in a LevelDB database at the path passed. The
+// database is created if it does not yet exist.
+func NewLevelDBProvider(path string) (*LevelDBProvider, error) {
+	if err := os.MkdirAll(path, 0777); err != nil {
+		return nil, fmt.Errorf("create moderation database directory: %w", err)
+	}
+	db, err := leveldb.OpenFile(path, &opt.Options{Compression: opt.SnappyCompression})
+	if err != nil {
+		return nil, fmt.Errorf("open moderation database: %w", err)
+	}
+	return &LevelDBProvider{db: db}, nil
+}
+
+// Punishments ...
+func (l *LevelDBProvider) Punishments(id uuid.UUID) ([]Punishment, error) {
+	var data []jsonPunishment
+	if err := l.read(append(append([]byte(nil), punishmentPrefix...), id.String()...), &data); err != nil {
+		return nil, fmt.Errorf("read punishments: %w", err)
+	}
+	return decodePunishments(data), nil
+}
+
+// AddPunishment ...
+func (l *LevelDBProvider) AddPunishment(id uuid.UUID, p Punishment) error {
+	// The read and write below must not be interleaved with those of another AddPunishment call.
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	k := append(append([]byte(nil), punishmentPrefix...), id.String()...)
+	var data []jsonPunishment
+	if err := l.read(k, &data); err != nil {
+		return fmt.Errorf("read punishments: %w", err)
+	}
+	b, err := json.Marshal(append(data, encodePunishments([]Punishment{p})...))
+	if err != nil {
+		return fmt.Errorf("encode punishments: %w", err)
+	}
+	if err := l.db.Put(k, b, nil); err != nil {
+		return fmt.Errorf("write punishments: %w", err)
+	}
+	return nil
+}
+
+// KeyBans ...
+func (l *LevelDBProvider) KeyBans() ([]KeyBan, error) {
+	iter := l.db.NewIterator(util.BytesPrefix(banPrefix), nil)
+	defer iter.Release()
+
+	var bans []KeyBan
+	for iter.Next() {
+		var d jsonKeyBan
+		if err := json.Unmarshal(iter.Value(), &d); err != nil {
+			return nil, fmt.Errorf("decode ban %s: %w", iter.Key(), err)
+		}
+		if b, ok := decodeKeyBan(d); ok {
+			bans = append(bans, b)
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("read bans: %w", err)
+	}
+	return bans, nil
+}
+
+// SaveKeyBans ...
+func (l *LevelDBProvider) SaveKeyBans(bans []KeyBan) error {
+	batch := new(leveldb.Batch)
+	iter := l.db.NewIterator(util.BytesPrefix(banPrefix), nil)
+	for iter.Next() {
+		batch.Delete(append([]byte(nil), iter.Key()...))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return fmt.Errorf("read bans: %w", err)
+	}
+	for _, b := range bans {
+		data, err := json.Marshal(encodeKeyBan(b))
+		if err != nil {
+			return fmt.Errorf("encode ban: %w", err)
+		}
+		batch.Put(append(append([]byte(nil), banPrefix...), b.Key.String()+"/"+normalise(b.Key, b.Value)...), data)
+	}
+	if err := l.db.Write(batch, nil); err != nil {
+		return fmt.Errorf("write bans: %w", err)
+	}
+	return nil
+}
+
+// Whitelist ...
+func (l *LevelDBProvider) Whitelist() (bool, []WhitelistEntry, error) {
+	var d jsonWhitelist
+	if err := l.read(whitelistKey, &d); err != nil {
+		return false, nil, fmt.Errorf("read whitelist: %w", err)
+	}
+	return d.Enabled, d.Entries, nil
+}
+
+// SaveWhitelist ...
+func (l *LevelDBProvider) SaveWhitelist(enabled bool, entries []WhitelistEntry) error {
+	b, err := json.Marshal(jsonWhitelist{Enabled: enabled, Entries: entries})
+	if err != nil {
+		return fmt.Errorf("encode whitelist: %w", err)
+	}
+	if err := l.db.Put(whitelistKey, b, nil); err != nil {
+		return fmt.Errorf("write whitelist: %w", err)
+	}
+	return nil
+}
+
+// Close ...
+func (l *LevelDBProvider) Close() error {
+	return l.db.Close()
+}
+
+// read decodes the JSON value stored under the key passed into v. If the key does not exist, v is left unchanged.
+func (l *LevelDBProvider) read(k []byte, v any) error {
+	b, err := l.db.Get(k, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,74 @@
+package moderation
+
+import (
+	"time"
+)
+
+// Punishment is a punishment issued to a player, such as a ban, mute or warning.
+type Punishment struct {
+	// Type is the PunishmentType of the Punishment.
+	Type PunishmentType
+	// Reason is the reason the Punishment was issued for. Reason may be empty.
+	Reason string
+	// Source is the name of whoever issued the Punishment.
+	Source string
+	// Issued is the time at which the Punishment was issued.
+	Issued time.Time
+	// Expiry is the time at which the Punishment expires. If Expiry is the zero time.Time, the Punishment never
+	// expires.
+	Expiry time.Time
+}
+
+// Active checks if the Punishment is currently in effect. Warnings are never active, as they do not restrict the
+// player in any way.
+func (p Punishment) Active() bool {
+	if p.Type == Warning() {
+		return false
+	}
+	return p.Expiry.IsZero() || time.Now().Before(p.Expiry)
+}
+
+// PunishmentType is a type of Punishment.
+type PunishmentType struct {
+	punishment
+}
+
+// Ban returns the PunishmentType for bans. Banned players cannot join the server.
+func Ban() PunishmentType {
+	return PunishmentType{0}
+}
+
+// Mute returns the PunishmentType for mutes. Muted players cannot write in the chat.
+func Mute() PunishmentType {
+	return PunishmentType{1}
+}
+
+// Warning returns the PunishmentType for warnings. Warnings do not restrict a player, but are kept in its history.
+func Warning() PunishmentType {
+	return PunishmentType{2}
+}
+
+// PunishmentTypes returns all PunishmentTypes.
+func PunishmentTypes() []PunishmentType {
+	return []PunishmentType{Ban(), Mute(), Warning()}
+}
+
+type punishment uint8
+
+// Uint8 returns the punishment as a uint8.
+func (p punishment) Uint8() uint8 {
+	return uint8(p)
+}
+
+// String ...
+func (p punishment) String() string {
+	switch p {
+	case 0:
+		return "ban"
+	case 1:
+		return "mute"
+	case 2:
+		return "warning"
+	}
+	panic("unknown punishment type")
+}
@@ -0,0 +1,342 @@
+package permission
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/reload"
+	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Manager manages the permission groups and the PlayerData of players. Groups are loaded when the Manager is
+// created, while the PlayerData of a player is loaded the first time it is needed. A Manager is safe for concurrent
+// use.
+type Manager struct {
+	prov Provider
+
+	mu      sync.RWMutex
+	groups  map[string]Group
+	players map[uuid.UUID]PlayerData
+}
+
+// Compile time check to make sure Manager implements cmd.Permissions.
+var _ cmd.Permissions = (*Manager)(nil)
+
+// New creates a Manager that stores groups and player data using the Provider passed. If nil is passed,
+// NopProvider is used. An error is returned if the groups could not be loaded from the Provider.
+func New(prov Provider) (*Manager, error) {
+	if prov == nil {
+		prov = NopProvider{}
+	}
+	groups, err := prov.Groups()
+	if err != nil {
+		return nil, fmt.Errorf("load permission groups: %w", err)
+	}
+	m := &Manager{prov: prov, groups: make(map[string]Group, len(groups)), players: map[uuid.UUID]PlayerData{}}
+	for _, g := range groups {
+		m.groups[normalise(g.Name)] = cloneGroup(g)
+	}
+	return m, nil
+}
+
+// GroupHolder is implemented by cmd.Sources other than players that are limited to the permission nodes of a set
+// of groups, such as remote console clients.
+type GroupHolder interface {
+	// PermissionGroups returns the names of the groups that the Source is a member of. If no groups are returned,
+	// the Source has all permission nodes.
+	PermissionGroups() []string
+}
+
+// Permitted checks if the cmd.Source passed has the permission node passed. Sources that are not players, such as
+// the console, have all permission nodes, unless they implement GroupHolder.
+func (m *Manager) Permitted(src cmd.Source, node string) bool {
+	if p, ok := src.(*player.Player); ok {
+		return m.Has(p.UUID(), node)
+	}
+	if h, ok := src.(GroupHolder); ok {
+		if groups := h.PermissionGroups(); len(groups) != 0 {
+			return m.GroupsHave(groups, node)
+		}
+	}
+	return true
+}
+
+// Has checks if the player with the UUID passed has the permission node passed. The overrides of the player are
+// checked first, followed by its groups in order and finally the DefaultGroup. The first of these that sets the
+// node, or a wildcard matching it, decides if the player has the node. If none of them do, Has returns false.
+// If the PlayerData of the player could not be loaded, only the DefaultGroup is checked.
+func (m *Manager) Has(id uuid.UUID, node string) bool {
+	d, _ := m.PlayerData(id)
+	if granted, set := lookup(d.Nodes, node); set {
+		return granted
+	}
+
+	return m.GroupsHave(append(d.Groups, DefaultGroup), node)
+}
+
+// GroupsHave checks if any of the groups with the names passed, or their parents, grants the permission node passed.
+// Groups earlier in the slice take precedence over later ones. Unlike Has, the DefaultGroup is only checked if it is
+// passed.
+func (m *Manager) GroupsHave(groups []string, node string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	visited := map[string]struct{}{}
+	for _, g := range groups {
+		if granted, set := m.lookupGroup(g, node, visited); set {
+			return granted
+		}
+	}
+	return false
+}
+
+// lookupGroup checks if the group with the name passed, or any of its parents, sets the permission node passed.
+// Groups already in visited are skipped, so that cyclic inheritance does not cause infinite recursion.
+func (m *Manager) lookupGroup(name, node string, visited map[string]struct{}) (granted, set bool) {
+	name = normalise(name)
+	if _, ok := visited[name]; ok {
+		return false, false
+	}
+	visited[name] = struct{}{}
+
+	g, ok := m.groups[name]
+	if !ok {
+		return false, false
+	}
+	if granted, set = lookup(g.Nodes, node); set {
+		return granted, true
+	}
+	for _, parent := range g.Parents {
+		if granted, set = m.lookupGroup(parent, node, visited); set {
+			return granted, true
+		}
+	}
+	return false, false
+}
+
+// Groups returns all groups of the Manager, sorted by name.
+func (m *Manager) Groups() []Group {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	groups := make([]Group, 0, len(m.groups))
+	for _, g := range m.groups {
+		groups = append(groups, cloneGroup(g))
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return normalise(groups[i].Name) < normalise(groups[j].Name)
+	})
+	return groups
+}
+
+// Group looks up a group by its name, case-insensitively. If found, the group is returned and the bool returned is
+// true.
+func (m *Manager) Group(name string) (Group, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	g, ok := m.groups[normalise(name)]
+	return cloneGroup(g), ok
+}
+
+// SetGroup adds the group passed to the Manager, replacing any group with the same name, and stores all groups
+// using the Provider.
+func (m *Manager) SetGroup(g Group) error {
+	if g.Name == "" {
+		return fmt.Errorf("set permission group: name must not be empty")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.groups[normalise(g.Name)] = cloneGroup(g)
+	return m.saveGroups()
+}
+
+// RemoveGroup removes the group with the name passed from the Manager and stores all remaining groups using the
+// Provider. Players that are a member of the group keep it in their PlayerData, but it no longer grants them any
+// permission nodes.
+func (m *Manager) RemoveGroup(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.groups, normalise(name))
+	return m.saveGroups()
+}
+
+// saveGroups stores all groups of the Manager using its Provider. m.mu must be held when saveGroups is called.
+func (m *Manager) saveGroups() error {
+	groups := maps.Values(m.groups)
+	sort.Slice(groups, func(i, j int) bool {
+		return normalise(groups[i].Name) < normalise(groups[j].Name)
+	})
+	if err := m.prov.SaveGroups(groups); err != nil {
+		return fmt.Errorf("save permission groups: %w", err)
+	}
+	return nil
+}
+
+// PlayerData returns the PlayerData of the player with the UUID passed, loading it from the Provider if it was not
+// yet loaded.
+func (m *Manager) PlayerData(id uuid.UUID) (PlayerData, error) {
+	m.mu.RLock()
+	d, ok := m.players[id]
+	m.mu.RUnlock()
+	if ok {
+		return clonePlayerData(d), nil
+	}
+
+	d, err := m.prov.PlayerData(id)
+	if err != nil {
+		return PlayerData{}, fmt.Errorf("load permission data of %v: %w", id, err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.players[id]; ok {
+		// The data was loaded or changed concurrently, so that version is more recent.
+		return clonePlayerData(existing), nil
+	}
+	m.players[id] = d
+	return clonePlayerData(d), nil
+}
+
+// SetPlayerData changes the PlayerData of the player with the UUID passed and stores it using the Provider.
+func (m *Manager) SetPlayerData(id uuid.UUID, d PlayerData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.setPlayerData(id, clonePlayerData(d))
+}
+
+// AddPlayerGroup adds the player with the UUID passed to the group with the name passed. An error is returned if
+// no group with the name exists.
+func (m *Manager) AddPlayerGroup(id uuid.UUID, group string) error {
+	return m.updatePlayerData(id, func(d *PlayerData) error {
+		if _, ok := m.groups[normalise(group)]; !ok {
+			return fmt.Errorf("add player to permission group: group %v does not exist", group)
+		}
+		if !slices.ContainsFunc(d.Groups, func(g string) bool { return normalise(g) == normalise(group) }) {
+			d.Groups = append(d.Groups, group)
+		}
+		return nil
+	})
+}
+
+// RemovePlayerGroup removes the player with the UUID passed from the group with the name passed.
+func (m *Manager) RemovePlayerGroup(id uuid.UUID, group string) error {
+	return m.updatePlayerData(id, func(d *PlayerData) error {
+		if i := slices.IndexFunc(d.Groups, func(g string) bool { return normalise(g) == normalise(group) }); i != -1 {
+			d.Groups = slices.Delete(d.Groups, i, i+1)
+		}
+		return nil
+	})
+}
+
+// SetPlayerNode grants (granted is true) or denies (granted is false) the permission node passed for the player
+// with the UUID passed, regardless of the groups of the player.
+func (m *Manager) SetPlayerNode(id uuid.UUID, node string, granted bool) error {
+	return m.updatePlayerData(id, func(d *PlayerData) error {
+		if d.Nodes == nil {
+			d.Nodes = map[string]bool{}
+		}
+		d.Nodes[node] = granted
+		return nil
+	})
+}
+
+// UnsetPlayerNode removes the permission node passed from the overrides of the player with the UUID passed, so
+// that the groups of the player decide if it has the node.
+func (m *Manager) UnsetPlayerNode(id uuid.UUID, node string) error {
+	return m.updatePlayerData(id, func(d *PlayerData) error {
+		delete(d.Nodes, node)
+		return nil
+	})
+}
+
+// Unload removes the PlayerData of the player with the UUID passed from memory. It is loaded from the Provider
+// again the next time it is needed. Unload may be called when a player leaves the server.
+func (m *Manager) Unload(id uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.players, id)
+}
+
+// Reload loads the groups from the Provider again, replacing the groups currently held, and drops the PlayerData
+// of all players so that it is loaded from the Provider again the next time it is needed. Reload may be used after
+// the files of a JSONProvider were edited by hand. The reload.Report returned lists the groups that were added,
+// removed or changed. If the groups could not be loaded, an error is returned and nothing is changed.
+func (m *Manager) Reload() (reload.Report, error) {
+	groups, err := m.prov.Groups()
+	if err != nil {
+		return reload.Report{}, fmt.Errorf("load permission groups: %w", err)
+	}
+	loaded := make(map[string]Group, len(groups))
+	for _, g := range groups {
+		loaded[normalise(g.Name)] = cloneGroup(g)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var report reload.Report
+	for _, name := range sortedKeys(loaded) {
+		if existing, ok := m.groups[name]; !ok {
+			report.Applied = append(report.Applied, fmt.Sprintf("added group %v", name))
+		} else if !reflect.DeepEqual(existing, loaded[name]) {
+			report.Applied = append(report.Applied, fmt.Sprintf("changed group %v", name))
+		}
+	}
+	for _, name := range sortedKeys(m.groups) {
+		if _, ok := loaded[name]; !ok {
+			report.Applied = append(report.Applied, fmt.Sprintf("removed group %v", name))
+		}
+	}
+	m.groups, m.players = loaded, map[uuid.UUID]PlayerData{}
+	return report, nil
+}
+
+// sortedKeys returns the keys of the map of groups passed in sorted order.
+func sortedKeys(groups map[string]Group) []string {
+	keys := maps.Keys(groups)
+	sort.Strings(keys)
+	return keys
+}
+
+// Close closes the Provider of the Manager.
+func (m *Manager) Close() error {
+	return m.prov.Close()
+}
+
+// updatePlayerData loads the PlayerData of the player with the UUID passed, calls f to change it and stores the
+// result. m.mu is held while f is called.
+func (m *Manager) updatePlayerData(id uuid.UUID, f func(d *PlayerData) error) error {
+	if _, err := m.PlayerData(id); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d := clonePlayerData(m.players[id])
+	if err := f(&d); err != nil {
+		return err
+	}
+	return m.setPlayerData(id, d)
+}
+
+// setPlayerData changes and stores the PlayerData of the player with the UUID passed. m.mu must be held when
+// setPlayerData is called.
+func (m *Manager) setPlayerData(id uuid.UUID, d PlayerData) error {
+	if err := m.prov.SavePlayerData(id, d); err != nil {
+		return fmt.Errorf("save permission data of %v: %w", id, err)
+	}
+	m.players[id] = d
+	return nil
+}
+
+// cloneGroup returns a deep copy of the Group passed.
+func cloneGroup(g Group) Group {
+	g.Parents, g.Nodes = slices.Clone(g.Parents), maps.Clone(g.Nodes)
+	return g
+}
+
+// clonePlayerData returns a deep copy of the PlayerData passed.
+func clonePlayerData(d PlayerData) PlayerData {
+	d.Groups, d.Nodes = slices.Clone(d.Groups), maps.Clone(d.Nodes)
+	return d
+}
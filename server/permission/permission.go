@@ -0,0 +1,64 @@
+// Package permission implements permission nodes for players. Nodes are dot-separated strings such as
+// 'command.mute', which are granted to or denied for players through groups and per-player overrides. Groups may
+// inherit the nodes of other groups and every player is implicitly a member of the DefaultGroup. Groups and player
+// data are stored using a Provider so that they are kept across restarts.
+//
+// A Manager implements cmd.Permissions, so that passing it to cmd.SetPermissions makes every command require the
+// permission node returned by cmd.Command.Permission.
+package permission
+
+import (
+	"strings"
+)
+
+const (
+	// DefaultGroup is the name of the group that every player is implicitly a member of. Its nodes apply to all
+	// players that do not have the same nodes set through their own groups or overrides.
+	DefaultGroup = "default"
+	// Wildcard is the node that matches every other node. A node ending with '.*', such as 'command.*', matches
+	// all nodes that start with the part before the wildcard.
+	Wildcard = "*"
+)
+
+// Group is a named set of permission nodes that players may be a member of.
+type Group struct {
+	// Name is the name of the Group. Group names are case-insensitive.
+	Name string
+	// Parents holds the names of the groups that the Group inherits nodes from. Nodes set in the Group itself take
+	// precedence over those of its Parents, and Parents earlier in the slice take precedence over later ones.
+	Parents []string
+	// Nodes holds the permission nodes set in the Group. A node that maps to true is granted, while a node that
+	// maps to false is denied, even if it is granted by one of the Parents.
+	Nodes map[string]bool
+}
+
+// PlayerData holds the groups and overrides of a single player.
+type PlayerData struct {
+	// Groups holds the names of the groups that the player is a member of. Groups earlier in the slice take
+	// precedence over later ones.
+	Groups []string
+	// Nodes holds the permission nodes set for the player specifically. These take precedence over the nodes of
+	// all Groups of the player.
+	Nodes map[string]bool
+}
+
+// lookup checks if the nodes passed set the permission node passed. The most specific match is used: The node
+// itself, then any wildcard nodes from the longest to the shortest prefix. If none of the nodes match, set is
+// false.
+func lookup(nodes map[string]bool, node string) (granted, set bool) {
+	if granted, set = nodes[node]; set {
+		return granted, true
+	}
+	for i := strings.LastIndexByte(node, '.'); i != -1; i = strings.LastIndexByte(node[:i], '.') {
+		if granted, set = nodes[node[:i]+"."+Wildcard]; set {
+			return granted, true
+		}
+	}
+	granted, set = nodes[Wildcard]
+	return granted, set
+}
+
+// normalise returns the key used to index a group with the name passed.
+func normalise(name string) string {
+	return strings.ToLower(name)
+}
@@ -0,0 +1,127 @@
+package permission
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Provider is a provider for permission groups and player data. Implementations must be safe for concurrent use.
+type Provider interface {
+	// Groups returns all groups stored.
+	Groups() ([]Group, error)
+	// SaveGroups stores the groups passed, replacing all groups stored previously.
+	SaveGroups(groups []Group) error
+	// PlayerData returns the PlayerData of the player with the UUID passed. A zero PlayerData is returned if none
+	// was stored for the player.
+	PlayerData(id uuid.UUID) (PlayerData, error)
+	// SavePlayerData stores the PlayerData of the player with the UUID passed.
+	SavePlayerData(id uuid.UUID, d PlayerData) error
+	// Close closes the Provider.
+	Close() error
+}
+
+// NopProvider is a Provider that does not store any data. Groups and player data are lost when the server stops.
+type NopProvider struct{}
+
+// Compile time check to make sure NopProvider implements Provider.
+var _ Provider = NopProvider{}
+
+func (NopProvider) Groups() ([]Group, error)                   { return nil, nil }
+func (NopProvider) SaveGroups([]Group) error                   { return nil }
+func (NopProvider) PlayerData(uuid.UUID) (PlayerData, error)   { return PlayerData{}, nil }
+func (NopProvider) SavePlayerData(uuid.UUID, PlayerData) error { return nil }
+func (NopProvider) Close() error                               { return nil }
+
+// JSONProvider is a Provider that stores groups in a groups.json file and the data of every player in a separate
+// JSON file in a directory.
+type JSONProvider struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewJSONProvider creates a JSONProvider that stores its data in the directory passed. The directory is created if
+// it does not yet exist.
+func NewJSONProvider(dir string) (*JSONProvider, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "players"), 0777); err != nil {
+		return nil, fmt.Errorf("create permission directory: %w", err)
+	}
+	return &JSONProvider{dir: dir}, nil
+}
+
+// Groups ...
+func (j *JSONProvider) Groups() ([]Group, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var groups []Group
+	if err := j.read(filepath.Join(j.dir, "groups.json"), &groups); err != nil {
+		return nil, fmt.Errorf("read groups: %w", err)
+	}
+	return groups, nil
+}
+
+// SaveGroups ...
+func (j *JSONProvider) SaveGroups(groups []Group) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.write(filepath.Join(j.dir, "groups.json"), groups); err != nil {
+		return fmt.Errorf("write groups: %w", err)
+	}
+	return nil
+}
+
+// PlayerData ...
+func (j *JSONProvider) PlayerData(id uuid.UUID) (PlayerData, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var d PlayerData
+	if err := j.read(j.path(id), &d); err != nil {
+		return PlayerData{}, fmt.Errorf("read player data: %w", err)
+	}
+	return d, nil
+}
+
+// SavePlayerData ...
+func (j *JSONProvider) SavePlayerData(id uuid.UUID, d PlayerData) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.write(j.path(id), d); err != nil {
+		return fmt.Errorf("write player data: %w", err)
+	}
+	return nil
+}
+
+// Close ...
+func (j *JSONProvider) Close() error {
+	return nil
+}
+
+// read decodes the JSON file at the path passed into v. If the file does not exist, v is left unchanged.
+func (j *JSONProvider) read(path string, v any) error {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// write encodes v and writes it to the JSON file at the path passed.
+func (j *JSONProvider) write(path string, v any) error {
+	b, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+// path returns the path of the JSON file holding the PlayerData of the player with the UUID passed.
+func (j *JSONProvider) path(id uuid.UUID) string {
+	return filepath.Join(j.dir, "players", id.String()+".json")
+}
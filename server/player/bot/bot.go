@@ -0,0 +1,179 @@
+// Package bot implements fake players: players that are controlled by the server rather than by a client. Bots are
+// shown in the world and in the player list like regular players and may be moved around and animated directly or
+// through a list of Goals. They may be used for load testing or as opponents in minigames.
+package bot
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/player/skin"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"sync"
+)
+
+// Config holds the settings of a Bot.
+type Config struct {
+	// Name is the name of the Bot, which is shown in the player list and above its head.
+	Name string
+	// Skin is the skin of the Bot. If left empty, a blank 64x64 skin is used.
+	Skin skin.Skin
+	// World is the world that the Bot is spawned in.
+	World *world.World
+	// Position is the position that the Bot is spawned at. If left empty, the Bot is spawned at the spawn of World.
+	Position mgl64.Vec3
+	// Goals is a list of Goals that make up the AI of the Bot, in order of priority. Every tick, the Goals are ticked
+	// until one of them returns true. Goals may be left empty for a Bot that is only controlled directly.
+	Goals []Goal
+}
+
+// New creates a Bot using the settings in the Config and spawns it in the World of the Config. The Bot is added to the
+// player list of all players online.
+func (conf Config) New() *Bot {
+	if len(conf.Skin.Pix) == 0 {
+		conf.Skin = skin.New(64, 64)
+	}
+	pos := conf.Position
+	if pos == (mgl64.Vec3{}) {
+		pos = conf.World.Spawn().Vec3Middle()
+	}
+	b := &Bot{p: player.New(conf.Name, conf.Skin, pos), goals: conf.Goals}
+	b.p.Control(controller{b: b})
+
+	session.AddToPlayerList(b.p)
+	conf.World.AddEntity(b.p)
+	return b
+}
+
+// Bot is a fake player controlled by the server. The underlying *player.Player may be obtained using the Player
+// method to change its inventory, game mode or Handler, or to make it interact with the world.
+type Bot struct {
+	p     *player.Player
+	goals []Goal
+
+	mu        sync.Mutex
+	walking   bool
+	target    mgl64.Vec3
+	speed     float64
+	wantedVel mgl64.Vec3
+}
+
+// Player returns the *player.Player controlled by the Bot.
+func (b *Bot) Player() *player.Player {
+	return b.p
+}
+
+// WalkTo makes the Bot walk towards the position passed. The Bot jumps over blocks in its way. speed is a multiplier
+// of the normal walking speed of the Bot.
+func (b *Bot) WalkTo(pos mgl64.Vec3, speed float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.walking, b.target, b.speed = true, pos, speed
+}
+
+// StopWalking stops the Bot from walking towards the position passed to WalkTo.
+func (b *Bot) StopWalking() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.walking = false
+}
+
+// Walking checks if the Bot is currently walking towards a position passed to WalkTo.
+func (b *Bot) Walking() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.walking
+}
+
+// LookAt rotates the Bot so that it looks at the position passed.
+func (b *Bot) LookAt(pos mgl64.Vec3) {
+	eye := b.p.Position().Add(mgl64.Vec3{0, b.p.EyeHeight()})
+	diff := pos.Sub(eye)
+	yaw := mgl64.RadToDeg(math.Atan2(diff[2], diff[0])) - 90
+	pitch := -mgl64.RadToDeg(math.Atan2(diff[1], math.Hypot(diff[0], diff[2])))
+
+	// Moving the player overwrites its velocity, so we restore it after rotating.
+	rot, vel := b.p.Rotation(), b.p.Velocity()
+	b.p.Move(mgl64.Vec3{}, yaw-rot.Yaw(), pitch-rot.Pitch())
+	b.p.SetVelocity(vel)
+}
+
+// Close removes the Bot from the world and from the player list.
+func (b *Bot) Close() error {
+	return b.p.Close()
+}
+
+// tick ticks the Goals of the Bot and moves it towards the position it is walking to.
+func (b *Bot) tick() {
+	if b.p.Dead() {
+		return
+	}
+	for _, g := range b.goals {
+		if g.Tick(b) {
+			break
+		}
+	}
+	b.walk()
+}
+
+// walk moves the Bot towards its target if it is currently walking.
+func (b *Bot) walk() {
+	b.mu.Lock()
+	walking, target, speed, prev := b.walking, b.target, b.speed, b.wantedVel
+	b.wantedVel = mgl64.Vec3{}
+	b.mu.Unlock()
+	if !walking {
+		return
+	}
+	pos := b.p.Position()
+	diff := target.Sub(pos)
+	diff[1] = 0
+	if diff.Len() < 0.5 {
+		b.StopWalking()
+		return
+	}
+	vel := b.p.Velocity()
+	if b.p.OnGround() && ((prev[0] != 0 && vel[0] == 0) || (prev[2] != 0 && vel[2] == 0)) {
+		// The Bot did not move in the direction it wanted to last tick, so something is blocking its way. We try to
+		// jump over it.
+		b.p.Jump()
+		// Gravity is applied to the velocity of the player before it is moved, so we add it to the jump velocity to
+		// make sure the Bot can jump a full block high.
+		vel = b.p.Velocity().Add(mgl64.Vec3{0, 0.08})
+	}
+	wanted := diff.Normalize().Mul(b.p.Speed() * speed)
+	if b.p.OnGround() || b.inLiquid() {
+		// Friction slows the Bot down more on the ground than it does in the air, so we make up for it here.
+		wanted = wanted.Mul(1 / 0.6)
+
+		b.mu.Lock()
+		b.wantedVel = wanted
+		b.mu.Unlock()
+	}
+	b.p.SetVelocity(mgl64.Vec3{wanted[0], vel[1], wanted[2]})
+
+	b.LookAt(target.Add(mgl64.Vec3{0, b.p.EyeHeight()}))
+}
+
+// inLiquid checks if the Bot is currently in a liquid.
+func (b *Bot) inLiquid() bool {
+	_, ok := b.p.World().Liquid(cube.PosFromVec3(b.p.Position()))
+	return ok
+}
+
+// controller implements the player.Controller interface for a Bot.
+type controller struct {
+	b *Bot
+}
+
+// Tick ...
+func (c controller) Tick(*player.Player, int64) {
+	c.b.tick()
+}
+
+// Close ...
+func (c controller) Close(p *player.Player) {
+	session.RemoveFromPlayerList(p)
+}
@@ -0,0 +1,131 @@
+package bot
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+)
+
+// Goal is part of the AI of a Bot. Goals of a Bot are ticked every tick in order of priority until one of them
+// returns true.
+type Goal interface {
+	// Tick ticks the Goal for the Bot passed. If true is returned, Goals with a lower priority are not ticked
+	// during this tick.
+	Tick(b *Bot) bool
+}
+
+// WanderGoal is a Goal that makes a Bot walk to random positions close to it.
+type WanderGoal struct {
+	// Chance is the chance per tick, between 0 and 1, that the Bot starts walking to a new position while it is
+	// standing still. If 0, Chance is 0.02.
+	Chance float64
+	// Radius is the maximum horizontal distance of the positions that the Bot walks to. If 0, Radius is 10.
+	Radius float64
+	// Speed is the multiplier of the walking speed of the Bot. If 0, Speed is 1.
+	Speed float64
+}
+
+// Tick ...
+func (g *WanderGoal) Tick(b *Bot) bool {
+	if b.Walking() || b.p.World().Rand().Float64() >= or(g.Chance, 0.02) {
+		return false
+	}
+	r := or(g.Radius, 10)
+	pos := b.p.Position()
+	b.WalkTo(pos.Add(mgl64.Vec3{(b.p.World().Rand().Float64()*2 - 1) * r, 0, (b.p.World().Rand().Float64()*2 - 1) * r}), or(g.Speed, 1))
+	return false
+}
+
+// FollowGoal is a Goal that makes a Bot follow the nearest player.
+type FollowGoal struct {
+	// Range is the distance within which the Bot looks for players to follow. If 0, Range is 16.
+	Range float64
+	// Distance is the distance that the Bot keeps from the player it follows. If 0, Distance is 3.
+	Distance float64
+	// Speed is the multiplier of the walking speed of the Bot. If 0, Speed is 1.
+	Speed float64
+}
+
+// Tick ...
+func (g *FollowGoal) Tick(b *Bot) bool {
+	target, ok := nearestPlayer(b, or(g.Range, 16))
+	if !ok {
+		return false
+	}
+	b.LookAt(target.Position().Add(mgl64.Vec3{0, 1.62}))
+	if target.Position().Sub(b.p.Position()).Len() <= or(g.Distance, 3) {
+		b.StopWalking()
+		return true
+	}
+	b.WalkTo(target.Position(), or(g.Speed, 1))
+	return true
+}
+
+// AttackGoal is a Goal that makes a Bot chase and attack the nearest player that can be attacked.
+type AttackGoal struct {
+	// Range is the distance within which the Bot looks for players to attack. If 0, Range is 16.
+	Range float64
+	// Speed is the multiplier of the walking speed of the Bot while chasing its target. If 0, Speed is 1.3, which
+	// is the speed of sprinting.
+	Speed float64
+	// Interval is the amount of ticks between two attacks. If 0, Interval is 10.
+	Interval int
+
+	cooldown int
+}
+
+// Tick ...
+func (g *AttackGoal) Tick(b *Bot) bool {
+	if g.cooldown > 0 {
+		g.cooldown--
+	}
+	target, ok := nearestPlayer(b, or(g.Range, 16))
+	if !ok {
+		return false
+	}
+	b.LookAt(target.Position().Add(mgl64.Vec3{0, 1.62}))
+	if target.Position().Sub(b.p.Position()).Len() > 2.5 {
+		b.WalkTo(target.Position(), or(g.Speed, 1.3))
+		return true
+	}
+	b.StopWalking()
+	if g.cooldown == 0 {
+		g.cooldown = int(or(float64(g.Interval), 10))
+		if !b.p.AttackEntity(target) {
+			b.p.SwingArm()
+		}
+	}
+	return true
+}
+
+// nearestPlayer returns the nearest player within the range passed that is visible and may take damage, other than
+// the Bot itself.
+func nearestPlayer(b *Bot, r float64) (world.Entity, bool) {
+	pos := b.p.Position()
+	var (
+		nearest world.Entity
+		dist    = math.MaxFloat64
+	)
+	for _, e := range b.p.World().EntitiesWithin(cube.Box(pos[0]-r, pos[1]-r, pos[2]-r, pos[0]+r, pos[1]+r, pos[2]+r), nil) {
+		g, ok := e.(interface {
+			GameMode() world.GameMode
+			Dead() bool
+		})
+		if !ok || e == b.p || g.Dead() || !g.GameMode().Visible() || !g.GameMode().AllowsTakingDamage() {
+			continue
+		}
+		if d := e.Position().Sub(pos).Len(); d < dist && d <= r {
+			nearest, dist = e, d
+		}
+	}
+	return nearest, nearest != nil
+}
+
+// or returns v if it is not 0, or def otherwise.
+func or(v, def float64) float64 {
+	if v == 0 {
+		return def
+	}
+	return v
+}
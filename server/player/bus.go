@@ -0,0 +1,211 @@
+package player
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/player/skin"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"net"
+	"time"
+)
+
+// bus is a Handler that passes the events of a Player on to multiple Handlers, ordered by their event.Priority.
+type bus struct {
+	event.Listeners[Handler]
+}
+
+// Compile time check to make sure bus implements Handler.
+var _ Handler = (*bus)(nil)
+
+// HandleMove ...
+func (hs *bus) HandleMove(ctx *event.Context, newPos mgl64.Vec3, newYaw, newPitch float64) {
+	hs.Call(ctx, func(h Handler) { h.HandleMove(ctx, newPos, newYaw, newPitch) })
+}
+
+// HandleMovementViolation ...
+func (hs *bus) HandleMovementViolation(ctx *event.Context, v MovementViolation) {
+	hs.Call(ctx, func(h Handler) { h.HandleMovementViolation(ctx, v) })
+}
+
+// HandleJump ...
+func (hs *bus) HandleJump() {
+	hs.Call(nil, func(h Handler) { h.HandleJump() })
+}
+
+// HandleTeleport ...
+func (hs *bus) HandleTeleport(ctx *event.Context, w *world.World, pos mgl64.Vec3) {
+	hs.Call(ctx, func(h Handler) { h.HandleTeleport(ctx, w, pos) })
+}
+
+// HandleTeleported ...
+func (hs *bus) HandleTeleported(before *world.World, from mgl64.Vec3) {
+	hs.Call(nil, func(h Handler) { h.HandleTeleported(before, from) })
+}
+
+// HandleChangeWorld ...
+func (hs *bus) HandleChangeWorld(before, after *world.World) {
+	hs.Call(nil, func(h Handler) { h.HandleChangeWorld(before, after) })
+}
+
+// HandleChangeDifficulty ...
+func (hs *bus) HandleChangeDifficulty(ctx *event.Context, diff *world.Difficulty) {
+	hs.Call(ctx, func(h Handler) { h.HandleChangeDifficulty(ctx, diff) })
+}
+
+// HandleToggleSprint ...
+func (hs *bus) HandleToggleSprint(ctx *event.Context, after bool) {
+	hs.Call(ctx, func(h Handler) { h.HandleToggleSprint(ctx, after) })
+}
+
+// HandleToggleSneak ...
+func (hs *bus) HandleToggleSneak(ctx *event.Context, after bool) {
+	hs.Call(ctx, func(h Handler) { h.HandleToggleSneak(ctx, after) })
+}
+
+// HandleChat ...
+func (hs *bus) HandleChat(ctx *event.Context, e *ChatEvent) {
+	hs.Call(ctx, func(h Handler) { h.HandleChat(ctx, e) })
+}
+
+// HandleFoodLoss ...
+func (hs *bus) HandleFoodLoss(ctx *event.Context, from int, to *int) {
+	hs.Call(ctx, func(h Handler) { h.HandleFoodLoss(ctx, from, to) })
+}
+
+// HandleExhaust ...
+func (hs *bus) HandleExhaust(ctx *event.Context, points *float64) {
+	hs.Call(ctx, func(h Handler) { h.HandleExhaust(ctx, points) })
+}
+
+// HandleHeal ...
+func (hs *bus) HandleHeal(ctx *event.Context, health *float64, src world.HealingSource) {
+	hs.Call(ctx, func(h Handler) { h.HandleHeal(ctx, health, src) })
+}
+
+// HandleHurt ...
+func (hs *bus) HandleHurt(ctx *event.Context, damage *float64, attackImmunity *time.Duration, src world.DamageSource) {
+	hs.Call(ctx, func(h Handler) { h.HandleHurt(ctx, damage, attackImmunity, src) })
+}
+
+// HandleDeath ...
+func (hs *bus) HandleDeath(src world.DamageSource, keepInv *bool, msg *DeathMessage) {
+	hs.Call(nil, func(h Handler) { h.HandleDeath(src, keepInv, msg) })
+}
+
+// HandleRecordLocation ...
+func (hs *bus) HandleRecordLocation(ctx *event.Context, kind LocationKind, loc *Location) {
+	hs.Call(ctx, func(h Handler) { h.HandleRecordLocation(ctx, kind, loc) })
+}
+
+// HandleRespawn ...
+func (hs *bus) HandleRespawn(pos *mgl64.Vec3, w **world.World) {
+	hs.Call(nil, func(h Handler) { h.HandleRespawn(pos, w) })
+}
+
+// HandleSkinChange ...
+func (hs *bus) HandleSkinChange(ctx *event.Context, skin *skin.Skin) {
+	hs.Call(ctx, func(h Handler) { h.HandleSkinChange(ctx, skin) })
+}
+
+// HandleStartBreak ...
+func (hs *bus) HandleStartBreak(ctx *event.Context, pos cube.Pos) {
+	hs.Call(ctx, func(h Handler) { h.HandleStartBreak(ctx, pos) })
+}
+
+// HandleBlockBreak ...
+func (hs *bus) HandleBlockBreak(ctx *event.Context, pos cube.Pos, drops *[]item.Stack, xp *int) {
+	hs.Call(ctx, func(h Handler) { h.HandleBlockBreak(ctx, pos, drops, xp) })
+}
+
+// HandleBlockPlace ...
+func (hs *bus) HandleBlockPlace(ctx *event.Context, pos cube.Pos, b world.Block) {
+	hs.Call(ctx, func(h Handler) { h.HandleBlockPlace(ctx, pos, b) })
+}
+
+// HandleBlockPick ...
+func (hs *bus) HandleBlockPick(ctx *event.Context, pos cube.Pos, b world.Block) {
+	hs.Call(ctx, func(h Handler) { h.HandleBlockPick(ctx, pos, b) })
+}
+
+// HandleItemUse ...
+func (hs *bus) HandleItemUse(ctx *event.Context) {
+	hs.Call(ctx, func(h Handler) { h.HandleItemUse(ctx) })
+}
+
+// HandleItemUseOnBlock ...
+func (hs *bus) HandleItemUseOnBlock(ctx *event.Context, pos cube.Pos, face cube.Face, clickPos mgl64.Vec3) {
+	hs.Call(ctx, func(h Handler) { h.HandleItemUseOnBlock(ctx, pos, face, clickPos) })
+}
+
+// HandleItemUseOnEntity ...
+func (hs *bus) HandleItemUseOnEntity(ctx *event.Context, e world.Entity) {
+	hs.Call(ctx, func(h Handler) { h.HandleItemUseOnEntity(ctx, e) })
+}
+
+// HandleItemConsume ...
+func (hs *bus) HandleItemConsume(ctx *event.Context, item item.Stack) {
+	hs.Call(ctx, func(h Handler) { h.HandleItemConsume(ctx, item) })
+}
+
+// HandleAttackEntity ...
+func (hs *bus) HandleAttackEntity(ctx *event.Context, e world.Entity, force, height *float64, critical *bool) {
+	hs.Call(ctx, func(h Handler) { h.HandleAttackEntity(ctx, e, force, height, critical) })
+}
+
+// HandleExperienceGain ...
+func (hs *bus) HandleExperienceGain(ctx *event.Context, amount *int) {
+	hs.Call(ctx, func(h Handler) { h.HandleExperienceGain(ctx, amount) })
+}
+
+// HandleExperienceLevelChange ...
+func (hs *bus) HandleExperienceLevelChange(ctx *event.Context, from int, to *int) {
+	hs.Call(ctx, func(h Handler) { h.HandleExperienceLevelChange(ctx, from, to) })
+}
+
+// HandlePunchAir ...
+func (hs *bus) HandlePunchAir(ctx *event.Context) {
+	hs.Call(ctx, func(h Handler) { h.HandlePunchAir(ctx) })
+}
+
+// HandleSignEdit ...
+func (hs *bus) HandleSignEdit(ctx *event.Context, e *SignEditEvent) {
+	hs.Call(ctx, func(h Handler) { h.HandleSignEdit(ctx, e) })
+}
+
+// HandleLecternPageTurn ...
+func (hs *bus) HandleLecternPageTurn(ctx *event.Context, pos cube.Pos, oldPage int, newPage *int) {
+	hs.Call(ctx, func(h Handler) { h.HandleLecternPageTurn(ctx, pos, oldPage, newPage) })
+}
+
+// HandleItemDamage ...
+func (hs *bus) HandleItemDamage(ctx *event.Context, i item.Stack, damage int) {
+	hs.Call(ctx, func(h Handler) { h.HandleItemDamage(ctx, i, damage) })
+}
+
+// HandleItemPickup ...
+func (hs *bus) HandleItemPickup(ctx *event.Context, i *item.Stack) {
+	hs.Call(ctx, func(h Handler) { h.HandleItemPickup(ctx, i) })
+}
+
+// HandleItemDrop ...
+func (hs *bus) HandleItemDrop(ctx *event.Context, e world.Entity) {
+	hs.Call(ctx, func(h Handler) { h.HandleItemDrop(ctx, e) })
+}
+
+// HandleTransfer ...
+func (hs *bus) HandleTransfer(ctx *event.Context, addr *net.UDPAddr) {
+	hs.Call(ctx, func(h Handler) { h.HandleTransfer(ctx, addr) })
+}
+
+// HandleCommandExecution ...
+func (hs *bus) HandleCommandExecution(ctx *event.Context, command cmd.Command, args []string) {
+	hs.Call(ctx, func(h Handler) { h.HandleCommandExecution(ctx, command, args) })
+}
+
+// HandleQuit ...
+func (hs *bus) HandleQuit() {
+	hs.Call(nil, func(h Handler) { h.HandleQuit() })
+}
@@ -0,0 +1,51 @@
+package player
+
+import (
+	"github.com/df-mc/dragonfly/server/player/chat"
+	"sync"
+)
+
+// WorldChat is a chat channel of which messages written by a Player are only sent to the players in the same world
+// as the Player, in addition to the subscribers of WorldChat. Players may be moved to it using
+// Player.SetChatChannel. Filters and the Formatter of WorldChat apply to the chat of every world.
+var WorldChat = chat.NewChannel("world", nil)
+
+// LocalChat is a chat channel of which messages written by a Player are only sent to the players within 32 blocks
+// of the Player, in addition to the subscribers of LocalChat. Channels with a different radius may be created
+// using NewLocalChannel.
+var LocalChat = NewLocalChannel("local", 32)
+
+// localRadii holds the radius of every channel created using NewLocalChannel.
+var localRadii sync.Map
+
+// NewLocalChannel creates a chat channel with the name passed of which messages written by a Player are only sent
+// to the players in the same world within the radius passed of the Player, in addition to the subscribers of the
+// channel. Players may be moved to it using Player.SetChatChannel.
+func NewLocalChannel(name string, radius float64) *chat.Chat {
+	c := chat.NewChannel(name, nil)
+	localRadii.Store(c, radius)
+	return c
+}
+
+// LocalRadius returns the radius of a channel created using NewLocalChannel. False is returned if the channel
+// passed was not created using NewLocalChannel.
+func LocalRadius(c *chat.Chat) (float64, bool) {
+	r, ok := localRadii.Load(c)
+	if !ok {
+		return 0, false
+	}
+	return r.(float64), true
+}
+
+// ChatEvent is a message written in the chat by a Player. It is passed to Handler.HandleChat, which may change the
+// message and who receives it.
+type ChatEvent struct {
+	// Channel is the chat that the message is written in. The Formatter of Channel is used to format the message
+	// once all handlers have been called. Channel should not be changed.
+	Channel *chat.Chat
+	// Message is the message written by the Player. It may be changed to change the message sent.
+	Message string
+	// Recipients is the set of subscribers that the message is sent to. Subscribers may be added to or removed from
+	// Recipients to change who receives the message.
+	Recipients map[chat.Subscriber]struct{}
+}
@@ -5,17 +5,26 @@ import (
 )
 
 // Global represents a global chat. Players will write in this chat by default when they send any message in
-// the chat.
-var Global = New()
+// the chat. Filters added to Global apply to all other channels too.
+var Global = NewChannel("global", nil)
 
 // Chat represents the in-game chat. Messages may be written to it to send a message to all subscribers. The
 // zero value of Chat is a chat ready to use.
+// A Chat may also be used as a channel that players write in instead of Global, for example to limit who reads
+// their messages. Every channel may have its own filters and Formatter.
 // Methods on Chat may be called from multiple goroutines concurrently.
 // Chat implements the io.Writer and io.StringWriter interfaces. fmt.Fprintf and fmt.Fprint may be used to write
 // formatted messages to the chat.
 type Chat struct {
+	name       string
+	recipients func() []Subscriber
+
 	m           sync.Mutex
 	subscribers map[Subscriber]struct{}
+
+	fm        sync.RWMutex
+	filters   []Filter
+	formatter Formatter
 }
 
 // New returns a new chat.
@@ -23,6 +32,37 @@ func New() *Chat {
 	return &Chat{subscribers: map[Subscriber]struct{}{}}
 }
 
+// NewChannel returns a new chat with the name passed. If recipients is not nil, it is called every time a message
+// is written to the chat, and the message is sent to the Subscribers it returns in addition to the subscribers of
+// the chat. This may be used to create channels of which the readers change often, such as all players in a world.
+func NewChannel(name string, recipients func() []Subscriber) *Chat {
+	return &Chat{name: name, recipients: recipients, subscribers: map[Subscriber]struct{}{}}
+}
+
+// Name returns the name of the chat, as passed to NewChannel.
+func (chat *Chat) Name() string {
+	return chat.name
+}
+
+// Recipients returns all Subscribers that a message written to the chat is currently sent to.
+func (chat *Chat) Recipients() []Subscriber {
+	chat.m.Lock()
+	recipients := make([]Subscriber, 0, len(chat.subscribers))
+	for subscriber := range chat.subscribers {
+		recipients = append(recipients, subscriber)
+	}
+	chat.m.Unlock()
+	if chat.recipients == nil {
+		return recipients
+	}
+	for _, r := range chat.recipients() {
+		if !chat.Subscribed(r) {
+			recipients = append(recipients, r)
+		}
+	}
+	return recipients
+}
+
 // Write writes the byte slice p as a string to the chat. It is equivalent to calling
 // Chat.WriteString(string(p)).
 func (chat *Chat) Write(p []byte) (n int, err error) {
@@ -31,6 +71,12 @@ func (chat *Chat) Write(p []byte) (n int, err error) {
 
 // WriteString writes a string s to the chat.
 func (chat *Chat) WriteString(s string) (n int, err error) {
+	if chat.recipients != nil {
+		for _, r := range chat.Recipients() {
+			r.Message(s)
+		}
+		return len(s), nil
+	}
 	chat.m.Lock()
 	defer chat.m.Unlock()
 	for subscriber := range chat.subscribers {
@@ -63,6 +109,47 @@ func (chat *Chat) Unsubscribe(s Subscriber) {
 	delete(chat.subscribers, s)
 }
 
+// AddFilter adds a Filter to the chat. Messages written by players are passed through all filters added, in the
+// order that they were added in, before they are written to the chat.
+func (chat *Chat) AddFilter(f Filter) {
+	chat.fm.Lock()
+	defer chat.fm.Unlock()
+	chat.filters = append(chat.filters, f)
+}
+
+// Filter passes a message written by the sender with the name passed through all filters added to the chat. The
+// filters may change the message. If any of the filters rejects the message, the error returned by the Filter is
+// returned and the remaining filters are not called.
+func (chat *Chat) Filter(sender string, msg *string) error {
+	chat.fm.RLock()
+	defer chat.fm.RUnlock()
+	for _, f := range chat.filters {
+		if err := f.Filter(sender, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetFormatter changes the Formatter used to format messages written by players in the chat. If nil is passed,
+// DefaultFormatter is used.
+func (chat *Chat) SetFormatter(f Formatter) {
+	chat.fm.Lock()
+	defer chat.fm.Unlock()
+	chat.formatter = f
+}
+
+// Format formats a message written by the sender with the name passed using the Formatter of the chat.
+func (chat *Chat) Format(sender, msg string) string {
+	chat.fm.RLock()
+	f := chat.formatter
+	chat.fm.RUnlock()
+	if f == nil {
+		f = DefaultFormatter{}
+	}
+	return f.Format(sender, msg)
+}
+
 // Close closes the chat, removing all subscribers from it.
 func (chat *Chat) Close() error {
 	chat.m.Lock()
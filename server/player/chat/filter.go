@@ -0,0 +1,112 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/ratelimit"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Filter filters messages written by players before they are sent to a Chat. Filters may be added to a Chat using
+// Chat.AddFilter.
+type Filter interface {
+	// Filter filters a message written by the sender with the name passed. The message may be changed by assigning
+	// to *msg. If an error is returned, the message is rejected and the error is shown to the sender.
+	Filter(sender string, msg *string) error
+}
+
+// FilterFunc is a function that implements the Filter interface.
+type FilterFunc func(sender string, msg *string) error
+
+// Filter ...
+func (f FilterFunc) Filter(sender string, msg *string) error {
+	return f(sender, msg)
+}
+
+// RateLimit returns a Filter that rejects messages of senders that write more than n messages within the duration
+// passed. Senders may write up to n messages in quick succession, after which they may write messages at a rate of
+// n per duration passed. Messages are limited using a ratelimit.Limiter. Both n and per must be positive, otherwise
+// RateLimit panics.
+func RateLimit(n int, per time.Duration) Filter {
+	if n <= 0 || per <= 0 {
+		panic(fmt.Sprintf("chat: rate limit must allow a positive amount of messages per positive duration, got %v per %v", n, per))
+	}
+	l := ratelimit.Limit{Rate: float64(n) / per.Seconds(), Burst: n}.New()
+	return FilterFunc(func(sender string, _ *string) error {
+		if !l.Allow(sender) {
+			//lint:ignore ST1005 Error string is capitalised because it is shown to the player.
+			return errors.New("You are sending messages too quickly.")
+		}
+		return nil
+	})
+}
+
+// DuplicateFilter returns a Filter that rejects messages identical to the previous message of the sender if it was
+// written within the duration passed. Messages are compared case-insensitively. Messages older than the duration
+// passed are forgotten, so that senders that left do not take up memory.
+func DuplicateFilter(within time.Duration) Filter {
+	type entry struct {
+		msg string
+		t   time.Time
+	}
+	var (
+		mu    sync.Mutex
+		last  = map[string]entry{}
+		swept = time.Now()
+	)
+	return FilterFunc(func(sender string, msg *string) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now, normalised := time.Now(), strings.ToLower(strings.TrimSpace(*msg))
+		if now.Sub(swept) >= within {
+			for s, e := range last {
+				if now.Sub(e.t) >= within {
+					delete(last, s)
+				}
+			}
+			swept = now
+		}
+		if e, ok := last[sender]; ok && e.msg == normalised && now.Sub(e.t) < within {
+			//lint:ignore ST1005 Error string is capitalised because it is shown to the player.
+			return errors.New("You cannot send the same message twice.")
+		}
+		last[sender] = entry{msg: normalised, t: now}
+		return nil
+	})
+}
+
+// WordFilter returns a Filter that replaces all occurrences of the words passed in a message with asterisks. Words
+// are matched case-insensitively.
+func WordFilter(words ...string) Filter {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = regexp.QuoteMeta(w)
+	}
+	r := regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+	return FilterFunc(func(_ string, msg *string) error {
+		if len(words) != 0 {
+			*msg = r.ReplaceAllStringFunc(*msg, func(s string) string {
+				return strings.Repeat("*", len([]rune(s)))
+			})
+		}
+		return nil
+	})
+}
+
+// urlRegex matches URLs and domain names in messages.
+var urlRegex = regexp.MustCompile(`(?i)(https?://\S+|\b[a-z0-9-]+(\.[a-z0-9-]+)*\.(com|net|org|io|gg|me|co|xyz|tk)\b)`)
+
+// URLFilter returns a Filter that rejects messages containing URLs or domain names.
+func URLFilter() Filter {
+	return FilterFunc(func(_ string, msg *string) error {
+		if urlRegex.MatchString(*msg) {
+			//lint:ignore ST1005 Error string is capitalised because it is shown to the player.
+			return errors.New("You cannot send links in the chat.")
+		}
+		return nil
+	})
+}
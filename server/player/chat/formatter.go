@@ -0,0 +1,28 @@
+package chat
+
+import (
+	"github.com/df-mc/dragonfly/server/text"
+)
+
+// Formatter formats messages written by players into the line that is sent to the recipients of a Chat.
+// Formatters may be set using Chat.SetFormatter, for example to add ranks in front of the names of players.
+type Formatter interface {
+	// Format formats a message written by the sender with the name passed.
+	Format(sender, msg string) string
+}
+
+// FormatterFunc is a function that implements the Formatter interface.
+type FormatterFunc func(sender, msg string) string
+
+// Format ...
+func (f FormatterFunc) Format(sender, msg string) string {
+	return f(sender, msg)
+}
+
+// DefaultFormatter is the Formatter used by a Chat if none is set. It formats messages as '<sender> message'.
+type DefaultFormatter struct{}
+
+// Format ...
+func (DefaultFormatter) Format(sender, msg string) string {
+	return "<" + text.Terminate(sender) + "> " + msg
+}
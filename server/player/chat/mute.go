@@ -0,0 +1,36 @@
+package chat
+
+import (
+	"github.com/google/uuid"
+	"sync/atomic"
+)
+
+// Muter decides which players are muted in the chat. Muted players cannot write messages in any channel. The
+// moderation.Manager implements Muter, so that the mutes issued using it are enforced in the chat.
+type Muter interface {
+	// Muted checks if the player with the UUID passed is muted. If it is, a message that explains the mute to the
+	// player is returned along with true.
+	Muted(id uuid.UUID) (string, bool)
+}
+
+// muter holds the Muter set using SetMuter.
+var muter atomic.Pointer[Muter]
+
+// SetMuter sets the Muter that decides which players are muted in the chat. Passing nil removes the Muter, so that
+// no players are muted. By default, no Muter is set.
+func SetMuter(m Muter) {
+	if m == nil {
+		muter.Store(nil)
+		return
+	}
+	muter.Store(&m)
+}
+
+// Muted checks if the player with the UUID passed is muted using the Muter set using SetMuter. If it is, a message
+// that explains the mute to the player is returned along with true.
+func Muted(id uuid.UUID) (string, bool) {
+	if m := muter.Load(); m != nil {
+		return (*m).Muted(id)
+	}
+	return "", false
+}
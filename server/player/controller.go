@@ -0,0 +1,11 @@
+package player
+
+// Controller controls a Player that is not controlled by a client, such as a fake player. A Controller may be set
+// using Player.Control.
+type Controller interface {
+	// Tick is called every tick that the Player is in a world, before the Player itself is ticked. The current tick
+	// of the world is passed.
+	Tick(p *Player, current int64)
+	// Close is called when the Player is closed, for example after it died.
+	Close(p *Player)
+}
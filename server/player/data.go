@@ -1,6 +1,7 @@
 package player
 
 import (
+	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/entity/effect"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/world"
@@ -56,6 +57,16 @@ type Data struct {
 	FallDistance float64
 	// World is the world the player was last in.
 	World *world.World
+	// BedSpawn is the position of the head of the bed that the player respawns at. It is nil if the player has not
+	// set its spawn using a bed.
+	BedSpawn *cube.Pos
+	// LastDeath is the Location at which the player last died. It is nil if the player has never died.
+	LastDeath *Location
+	// LastTeleport is the Location that the player was at before it was last teleported. It is nil if the player has
+	// never been teleported.
+	LastTeleport *Location
+	// Metadata holds the persistent values of the Metadata of the player, by their keys.
+	Metadata map[string]any
 }
 
 // InventoryData is a struct that contains all data of the player inventories.
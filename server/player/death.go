@@ -0,0 +1,113 @@
+package player
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/entity/effect"
+	"github.com/df-mc/dragonfly/server/i18n"
+	"github.com/df-mc/dragonfly/server/item/enchantment"
+	"github.com/df-mc/dragonfly/server/player/chat"
+	"github.com/df-mc/dragonfly/server/world"
+	"golang.org/x/text/language"
+	"strings"
+	"time"
+)
+
+// DeathMessage is the message sent to all players in the chat when a player dies. Key is a translation key, such as
+// 'death.attack.fall', which is translated into the language of every player that the message is sent to. Args are
+// the arguments of the translation, the first of which is the name of the player that died. If Key is empty, no
+// message is sent.
+type DeathMessage struct {
+	Key  string
+	Args []any
+}
+
+// respawnImmunity is the duration for which players cannot be attacked after respawning.
+const respawnImmunity = time.Second * 3
+
+// deathMessage returns the DeathMessage sent by default when the player dies to the damage source passed.
+func (p *Player) deathMessage(src world.DamageSource) DeathMessage {
+	msg := func(key string, a ...any) DeathMessage {
+		return DeathMessage{Key: key, Args: append([]any{p.Name()}, a...)}
+	}
+	switch src := src.(type) {
+	case entity.AttackDamageSource:
+		if _, ok := src.Attacker.(*Player); ok {
+			return msg("death.attack.player", deathMessageName(src.Attacker))
+		}
+		return msg("death.attack.mob", deathMessageName(src.Attacker))
+	case entity.ProjectileDamageSource:
+		if src.Owner != nil {
+			return msg("death.attack.arrow", deathMessageName(src.Owner))
+		}
+	case enchantment.ThornsDamageSource:
+		return msg("death.attack.thorns", deathMessageName(src.Owner))
+	case entity.VoidDamageSource:
+		return msg("death.attack.outOfWorld")
+	case entity.SuffocationDamageSource:
+		return msg("death.attack.inWall")
+	case entity.DrowningDamageSource:
+		return msg("death.attack.drown")
+	case entity.FallDamageSource:
+		return msg("death.attack.fall")
+	case entity.GlideDamageSource:
+		return msg("death.attack.flyIntoWall")
+	case entity.LightningDamageSource:
+		return msg("death.attack.lightningBolt")
+	case entity.ExplosionDamageSource:
+		return msg("death.attack.explosion")
+	case block.LavaDamageSource:
+		return msg("death.attack.lava")
+	case block.FireDamageSource:
+		return msg("death.attack.onFire")
+	case block.DamageSource:
+		switch src.Block.(type) {
+		case block.Cactus:
+			return msg("death.attack.cactus")
+		case block.Anvil:
+			return msg("death.attack.anvil")
+		}
+	case effect.WitherDamageSource:
+		return msg("death.attack.wither")
+	case effect.InstantDamageSource, effect.PoisonDamageSource:
+		return msg("death.attack.magic")
+	case StarvationDamageSource:
+		return msg("death.attack.starve")
+	}
+	return msg("death.attack.generic")
+}
+
+// deathMessageName returns the name of the entity passed as used in a DeathMessage. Entities without a name are
+// referred to by the translation of the name of their type.
+func deathMessageName(e world.Entity) any {
+	if e == nil {
+		return ""
+	}
+	if n, ok := e.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+	if n, ok := e.(interface{ NameTag() string }); ok && n.NameTag() != "" {
+		return n.NameTag()
+	}
+	return "%entity." + strings.TrimPrefix(e.Type().EncodeEntity(), "minecraft:") + ".name"
+}
+
+// broadcastDeathMessage sends the DeathMessage passed to all subscribers of chat.Global. Subscribers that are not
+// able to translate the message, such as the console, receive it translated using i18n.Global if possible.
+func broadcastDeathMessage(msg DeathMessage) {
+	if msg.Key == "" {
+		return
+	}
+	for _, s := range chat.Global.Recipients() {
+		if t, ok := s.(interface{ Messaget(key string, a ...any) }); ok {
+			t.Messaget(msg.Key, msg.Args...)
+			continue
+		}
+		if translated, ok := i18n.Global.Translate(language.AmericanEnglish, msg.Key, msg.Args...); ok {
+			s.Message(translated)
+			continue
+		}
+		s.Message(fmt.Sprint(append([]any{msg.Key}, msg.Args...)...))
+	}
+}
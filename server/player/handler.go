@@ -18,12 +18,27 @@ type Handler interface {
 	// HandleMove handles the movement of a player. ctx.Cancel() may be called to cancel the movement event.
 	// The new position, yaw and pitch are passed.
 	HandleMove(ctx *event.Context, newPos mgl64.Vec3, newYaw, newPitch float64)
+	// HandleMovementViolation handles movement of a player that failed validation, because the player moved in a
+	// way that is not possible in the game. It is only called if the MovementPolicy of the player is not
+	// MovementPolicyNone. ctx.Cancel() may be called to accept the movement anyway, in which case the MovementPolicy
+	// is not applied.
+	HandleMovementViolation(ctx *event.Context, v MovementViolation)
 	// HandleJump handles the player jumping.
 	HandleJump()
-	// HandleTeleport handles the teleportation of a player. ctx.Cancel() may be called to cancel it.
-	HandleTeleport(ctx *event.Context, pos mgl64.Vec3)
+	// HandleTeleport handles the teleportation of a player to a position in the world passed. The world is the
+	// world that the player is currently in, unless it is teleported to another world using Player.TeleportTo.
+	// ctx.Cancel() may be called to cancel it.
+	HandleTeleport(ctx *event.Context, w *world.World, pos mgl64.Vec3)
+	// HandleTeleported handles a player after it was teleported using Player.Teleport or Player.TeleportTo. before
+	// and from are the world and the position that the player was teleported from.
+	HandleTeleported(before *world.World, from mgl64.Vec3)
 	// HandleChangeWorld handles when the player is added to a new world. before may be nil.
 	HandleChangeWorld(before, after *world.World)
+	// HandleChangeDifficulty handles the player changing the difficulty of its world from the settings screen of its
+	// client. The difficulty that the world is changed to may be changed by assigning to *diff. ctx is already
+	// cancelled if the player does not have the ChangeDifficultyPermission node, in which case ctx.Uncancel() may be
+	// called to allow the change anyway.
+	HandleChangeDifficulty(ctx *event.Context, diff *world.Difficulty)
 	// HandleToggleSprint handles when the player starts or stops sprinting.
 	// After is true if the player is sprinting after toggling (changing their sprinting state).
 	HandleToggleSprint(ctx *event.Context, after bool)
@@ -32,11 +47,15 @@ type Handler interface {
 	HandleToggleSneak(ctx *event.Context, after bool)
 	// HandleChat handles a message sent in the chat by a player. ctx.Cancel() may be called to cancel the
 	// message being sent in chat.
-	// The message may be changed by assigning to *message.
-	HandleChat(ctx *event.Context, message *string)
+	// The message and its recipients may be changed through the ChatEvent passed.
+	HandleChat(ctx *event.Context, e *ChatEvent)
 	// HandleFoodLoss handles the food bar of a player depleting naturally, for example because the player was
 	// sprinting and jumping. ctx.Cancel() may be called to cancel the food points being lost.
 	HandleFoodLoss(ctx *event.Context, from int, to *int)
+	// HandleExhaust handles the player being exhausted, for example because the player was sprinting, jumping or
+	// breaking blocks. ctx.Cancel() may be called to cancel the exhaustion.
+	// The exhaustion points added may be changed by assigning to *points.
+	HandleExhaust(ctx *event.Context, points *float64)
 	// HandleHeal handles the player being healed by a healing source. ctx.Cancel() may be called to cancel
 	// the healing.
 	// The health added may be changed by assigning to *health.
@@ -45,8 +64,15 @@ type Handler interface {
 	// damage being dealt to the player.
 	// The damage dealt to the player may be changed by assigning to *damage.
 	HandleHurt(ctx *event.Context, damage *float64, attackImmunity *time.Duration, src world.DamageSource)
-	// HandleDeath handles the player dying to a particular damage cause.
-	HandleDeath(src world.DamageSource, keepInv *bool)
+	// HandleDeath handles the player dying to a particular damage cause. *keepInv holds if the player keeps its
+	// items and experience, which is the value of world.GameRuleKeepInventory by default, and may be changed by
+	// assigning to it. The message sent to all players in the chat may be changed by assigning to *msg. Setting its
+	// Key to an empty string prevents the message from being sent.
+	HandleDeath(src world.DamageSource, keepInv *bool, msg *DeathMessage)
+	// HandleRecordLocation handles the recording of the Location at which the player died or the Location that it
+	// was at before being teleported, depending on the LocationKind passed. The Location recorded may be changed by
+	// assigning to *loc. ctx.Cancel() may be called to keep the previously recorded Location.
+	HandleRecordLocation(ctx *event.Context, kind LocationKind, loc *Location)
 	// HandleRespawn handles the respawning of the player in the world. The spawn position passed may be
 	// changed by assigning to *pos. The world.World in which the Player is respawned may be modifying by assigning to
 	// *w. This world may be the world the Player died in, but it might also point to a different world (the overworld)
@@ -102,11 +128,17 @@ type Handler interface {
 	// the gain.
 	// The amount is also provided which can be modified.
 	HandleExperienceGain(ctx *event.Context, amount *int)
+	// HandleExperienceLevelChange handles the experience level of the player being set through a call to
+	// Player.SetExperienceLevel. ctx.Cancel() may be called to cancel the change.
+	// The new level may be changed by assigning to *to. A negative level assigned to *to is set as 0.
+	HandleExperienceLevelChange(ctx *event.Context, from int, to *int)
 	// HandlePunchAir handles the player punching air.
 	HandlePunchAir(ctx *event.Context)
 	// HandleSignEdit handles the player editing a sign. It is called for every keystroke while editing a sign and
 	// has both the old text passed and the text after the edit. This typically only has a change of one character.
-	HandleSignEdit(ctx *event.Context, frontSide bool, oldText, newText string)
+	// ctx.Cancel() may be called to cancel the edit. The text written to the sign may be changed through the
+	// SignEditEvent passed.
+	HandleSignEdit(ctx *event.Context, e *SignEditEvent)
 	// HandleLecternPageTurn handles the player turning a page in a lectern. ctx.Cancel() may be called to cancel the
 	// page turn. The page number may be changed by assigning to *page.
 	HandleLecternPageTurn(ctx *event.Context, pos cube.Pos, oldPage int, newPage *int)
@@ -133,8 +165,7 @@ type Handler interface {
 	HandleQuit()
 }
 
-// NopHandler implements the Handler interface but does not execute any code when an event is called. The
-// default Handler of players is set to NopHandler.
+// NopHandler implements the Handler interface but does not execute any code when an event is called.
 // Users may embed NopHandler to avoid having to implement each method.
 type NopHandler struct{}
 
@@ -143,20 +174,23 @@ var _ Handler = NopHandler{}
 
 func (NopHandler) HandleItemDrop(*event.Context, world.Entity)                                {}
 func (NopHandler) HandleMove(*event.Context, mgl64.Vec3, float64, float64)                    {}
+func (NopHandler) HandleMovementViolation(*event.Context, MovementViolation)                  {}
 func (NopHandler) HandleJump()                                                                {}
-func (NopHandler) HandleTeleport(*event.Context, mgl64.Vec3)                                  {}
+func (NopHandler) HandleTeleport(*event.Context, *world.World, mgl64.Vec3)                    {}
+func (NopHandler) HandleTeleported(*world.World, mgl64.Vec3)                                  {}
 func (NopHandler) HandleChangeWorld(*world.World, *world.World)                               {}
+func (NopHandler) HandleChangeDifficulty(*event.Context, *world.Difficulty)                   {}
 func (NopHandler) HandleToggleSprint(*event.Context, bool)                                    {}
 func (NopHandler) HandleToggleSneak(*event.Context, bool)                                     {}
 func (NopHandler) HandleCommandExecution(*event.Context, cmd.Command, []string)               {}
 func (NopHandler) HandleTransfer(*event.Context, *net.UDPAddr)                                {}
-func (NopHandler) HandleChat(*event.Context, *string)                                         {}
+func (NopHandler) HandleChat(*event.Context, *ChatEvent)                                      {}
 func (NopHandler) HandleSkinChange(*event.Context, *skin.Skin)                                {}
 func (NopHandler) HandleStartBreak(*event.Context, cube.Pos)                                  {}
 func (NopHandler) HandleBlockBreak(*event.Context, cube.Pos, *[]item.Stack, *int)             {}
 func (NopHandler) HandleBlockPlace(*event.Context, cube.Pos, world.Block)                     {}
 func (NopHandler) HandleBlockPick(*event.Context, cube.Pos, world.Block)                      {}
-func (NopHandler) HandleSignEdit(*event.Context, bool, string, string)                        {}
+func (NopHandler) HandleSignEdit(*event.Context, *SignEditEvent)                              {}
 func (NopHandler) HandleLecternPageTurn(*event.Context, cube.Pos, int, *int)                  {}
 func (NopHandler) HandleItemPickup(*event.Context, *item.Stack)                               {}
 func (NopHandler) HandleItemUse(*event.Context)                                               {}
@@ -166,10 +200,13 @@ func (NopHandler) HandleItemConsume(*event.Context, item.Stack)
 func (NopHandler) HandleItemDamage(*event.Context, item.Stack, int)                           {}
 func (NopHandler) HandleAttackEntity(*event.Context, world.Entity, *float64, *float64, *bool) {}
 func (NopHandler) HandleExperienceGain(*event.Context, *int)                                  {}
+func (NopHandler) HandleExperienceLevelChange(*event.Context, int, *int)                      {}
 func (NopHandler) HandlePunchAir(*event.Context)                                              {}
 func (NopHandler) HandleHurt(*event.Context, *float64, *time.Duration, world.DamageSource)    {}
 func (NopHandler) HandleHeal(*event.Context, *float64, world.HealingSource)                   {}
 func (NopHandler) HandleFoodLoss(*event.Context, int, *int)                                   {}
-func (NopHandler) HandleDeath(world.DamageSource, *bool)                                      {}
+func (NopHandler) HandleExhaust(*event.Context, *float64)                                     {}
+func (NopHandler) HandleDeath(world.DamageSource, *bool, *DeathMessage)                       {}
+func (NopHandler) HandleRecordLocation(*event.Context, LocationKind, *Location)               {}
 func (NopHandler) HandleRespawn(*mgl64.Vec3, **world.World)                                   {}
 func (NopHandler) HandleQuit()                                                                {}
@@ -0,0 +1,81 @@
+package player
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// Location is a position and rotation in a world.Dimension, such as the position at which a player last died.
+type Location struct {
+	// Position is the position of the Location.
+	Position mgl64.Vec3
+	// Rotation is the rotation that the player had at the Location.
+	Rotation cube.Rotation
+	// Dimension is the world.Dimension of the world that the Location is in.
+	Dimension world.Dimension
+}
+
+// LocationKind is the kind of a Location recorded for a player, as passed to Handler.HandleRecordLocation.
+type LocationKind uint8
+
+const (
+	// LocationDeath is the kind of the Location at which a player died, as returned by Player.LastDeathPos.
+	LocationDeath LocationKind = iota
+	// LocationTeleport is the kind of the Location that a player was at before it was teleported, as returned by
+	// Player.LastTeleportPos.
+	LocationTeleport
+)
+
+// LastDeathPos returns the Location at which the player last died and true, or false if the player has never died.
+// The Location is kept in the Data of the player, so that it is remembered across sessions.
+func (p *Player) LastDeathPos() (Location, bool) {
+	p.locationMu.Lock()
+	defer p.locationMu.Unlock()
+	if p.lastDeath == nil {
+		return Location{}, false
+	}
+	return *p.lastDeath, true
+}
+
+// LastTeleportPos returns the Location that the player was at before it was last teleported using Teleport or
+// TeleportTo and true, or false if the player has never been teleported. Together with LastDeathPos, it may be
+// used to return players to where they came from, for example using a /back command. Like LastDeathPos, the Location
+// is kept in the Data of the player.
+func (p *Player) LastTeleportPos() (Location, bool) {
+	p.locationMu.Lock()
+	defer p.locationMu.Unlock()
+	if p.lastTeleport == nil {
+		return Location{}, false
+	}
+	return *p.lastTeleport, true
+}
+
+// DeathPosition returns the last position the player was at when they died. If the player has never died, the third
+// return value will be false.
+func (p *Player) DeathPosition() (mgl64.Vec3, world.Dimension, bool) {
+	loc, ok := p.LastDeathPos()
+	return loc.Position, loc.Dimension, ok
+}
+
+// currentLocation returns the current Location of the player in the world passed.
+func (p *Player) currentLocation(w *world.World) Location {
+	return Location{Position: p.Position(), Rotation: p.Rotation(), Dimension: w.Dimension()}
+}
+
+// recordLocation records the Location passed as the last Location of the kind passed, after calling
+// Handler.HandleRecordLocation. Nothing is recorded if the event is cancelled.
+func (p *Player) recordLocation(kind LocationKind, loc Location) {
+	ctx := event.C()
+	if p.h.HandleRecordLocation(ctx, kind, &loc); ctx.Cancelled() {
+		return
+	}
+	p.locationMu.Lock()
+	defer p.locationMu.Unlock()
+	if kind == LocationDeath {
+		p.lastDeath = &loc
+		return
+	}
+	p.lastTeleport = &loc
+}
@@ -0,0 +1,251 @@
+package player
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity/effect"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"sync"
+)
+
+// MovementPolicy specifies how movement of a Player that is not possible in the game, such as moving faster than
+// its speed allows, flying without being allowed to or moving through solid blocks, is dealt with. Only movement
+// sent by the client of a Player is validated, and never that of players in a game mode that allows flying.
+type MovementPolicy int
+
+const (
+	// MovementPolicyNone disables the validation of movement. All movement of the player is accepted.
+	MovementPolicyNone MovementPolicy = iota
+	// MovementPolicyCorrect rejects invalid movement and moves the player back to the position it had before
+	// moving, also known as rubber-banding.
+	MovementPolicyCorrect
+	// MovementPolicyKick rejects invalid movement and disconnects the player.
+	MovementPolicyKick
+)
+
+// MovementViolationType is the type of check that movement of a player failed.
+type MovementViolationType int
+
+const (
+	// MovementViolationSpeed is a horizontal movement that covered more distance than the speed of the player
+	// allows.
+	MovementViolationSpeed MovementViolationType = iota
+	// MovementViolationFly is a movement that brought the player higher above the position it left the ground at
+	// than a jump allows, or kept it in the air without falling for too long.
+	MovementViolationFly
+	// MovementViolationNoClip is a movement into a solid block that the player was not already inside of.
+	MovementViolationNoClip
+)
+
+// String ...
+func (t MovementViolationType) String() string {
+	switch t {
+	case MovementViolationSpeed:
+		return "speed"
+	case MovementViolationFly:
+		return "fly"
+	case MovementViolationNoClip:
+		return "noclip"
+	}
+	panic("unknown movement violation type")
+}
+
+// MovementViolation holds information on a movement of a Player that failed validation.
+type MovementViolation struct {
+	// Type is the type of check that the movement failed.
+	Type MovementViolationType
+	// From is the position of the player before moving and To the position that it tried to move to.
+	From, To mgl64.Vec3
+	// Value is the value measured for the movement and Limit the maximum value allowed. For MovementViolationSpeed,
+	// these are the horizontal distance moved in blocks. For MovementViolationFly, they are the height above the
+	// position the player left the ground at or, if that was not exceeded, the number of ticks spent in the air
+	// without falling. For MovementViolationNoClip, they are both 0.
+	Value, Limit float64
+}
+
+const (
+	// speedLimitMultiplier is the multiplier applied to the speed of a player to find the horizontal distance that
+	// it may move in a single movement. It is chosen so that sprint jumping does not exceed the limit.
+	speedLimitMultiplier = 6.5
+	// slipperySpeedMultiplier is the multiplier applied to the speed limit if the player is on a slippery block,
+	// such as ice.
+	slipperySpeedMultiplier = 2.5
+	// glideSpeedLimit is the horizontal distance a player may move in a single movement while gliding.
+	glideSpeedLimit = 4.0
+	// jumpHeightLimit is the height above the position that a player left the ground at that it may reach, without
+	// the jump boost effect.
+	jumpHeightLimit = 1.5
+	// airTickLimit is the number of ticks that a player may spend in the air without falling, without the jump
+	// boost effect.
+	airTickLimit = 20
+	// velocityDecay is the factor by which the velocity allowance of a player, given when its velocity is set, is
+	// reduced every tick.
+	velocityDecay = 0.91
+)
+
+// movementState holds the state used to validate the movement of a Player.
+type movementState struct {
+	mu     sync.Mutex
+	policy MovementPolicy
+	// airborne is true if the player is in the air. airStartY is then the Y position that the player left the
+	// ground at and airTicks the number of movements in the air that did not bring the player down.
+	airborne  bool
+	airStartY float64
+	airTicks  int
+	// velocity is the horizontal distance per tick that the player may move in addition to its normal speed,
+	// because its velocity was set, such as by knock-back.
+	velocity float64
+}
+
+// SetMovementPolicy changes the MovementPolicy that the movement sent by the client of the player is validated
+// with. By default, MovementPolicyNone is used and movement is not validated.
+func (p *Player) SetMovementPolicy(policy MovementPolicy) {
+	p.movement.mu.Lock()
+	defer p.movement.mu.Unlock()
+	p.movement.policy = policy
+}
+
+// MovementPolicy returns the MovementPolicy set using SetMovementPolicy.
+func (p *Player) MovementPolicy() MovementPolicy {
+	p.movement.mu.Lock()
+	defer p.movement.mu.Unlock()
+	return p.movement.policy
+}
+
+// validateMovement validates the movement of the player from one position to another. If the movement is invalid,
+// HandleMovementViolation is called and the MovementPolicy of the player is applied, after which false is returned.
+func (p *Player) validateMovement(w *world.World, from, to mgl64.Vec3) bool {
+	if p.session() == session.Nop || p.GameMode().AllowsFlying() || p.MovementPolicy() == MovementPolicyNone {
+		return true
+	}
+	v, ok := p.checkMovement(w, from, to)
+	if ok {
+		return true
+	}
+	ctx := event.C()
+	if p.h.HandleMovementViolation(ctx, v); ctx.Cancelled() {
+		return true
+	}
+	switch p.MovementPolicy() {
+	case MovementPolicyCorrect:
+		// Move the player back to the last valid position without resetting the state of the validation, so that
+		// the player cannot keep flying by being corrected.
+		p.session().CorrectMovement(from, p.OnGround())
+		p.vel.Store(mgl64.Vec3{})
+	case MovementPolicyKick:
+		p.Disconnect("Invalid movement.")
+	}
+	return false
+}
+
+// checkMovement checks if the movement of the player from one position to another is valid. If not, the
+// MovementViolation found is returned.
+func (p *Player) checkMovement(w *world.World, from, to mgl64.Vec3) (MovementViolation, bool) {
+	p.movement.mu.Lock()
+	defer p.movement.mu.Unlock()
+	m := &p.movement
+
+	delta := to.Sub(from)
+	jumpBoost := 0
+	if e, ok := p.Effect(effect.JumpBoost{}); ok {
+		jumpBoost = e.Level()
+	}
+
+	horizontal := math.Hypot(delta[0], delta[2])
+	limit := p.Speed()*speedLimitMultiplier + m.velocity
+	if p.Gliding() {
+		limit = glideSpeedLimit + m.velocity
+	} else if p.onSlipperyBlock(w, from) {
+		limit *= slipperySpeedMultiplier
+	}
+	if horizontal > limit {
+		return MovementViolation{Type: MovementViolationSpeed, From: from, To: to, Value: horizontal, Limit: limit}, false
+	}
+
+	if p.collidesWithNewBlock(w, from, to) {
+		return MovementViolation{Type: MovementViolationNoClip, From: from, To: to}, false
+	}
+
+	_, levitating := p.Effect(effect.Levitation{})
+	if p.Gliding() || levitating || m.velocity > 0.01 || p.onGroundAt(w, to) || p.inLiquid(w, to) || p.climbing(w, to) {
+		m.airborne, m.airStartY, m.airTicks = false, to[1], 0
+		return MovementViolation{}, true
+	}
+	if !m.airborne {
+		m.airborne, m.airStartY, m.airTicks = true, from[1], 0
+	}
+	if heightLimit := jumpHeightLimit + float64(jumpBoost)*0.75; to[1]-m.airStartY > heightLimit {
+		return MovementViolation{Type: MovementViolationFly, From: from, To: to, Value: to[1] - m.airStartY, Limit: heightLimit}, false
+	}
+	if delta[1] >= -0.01 {
+		m.airTicks++
+		if tickLimit := airTickLimit + jumpBoost*4; m.airTicks > tickLimit {
+			return MovementViolation{Type: MovementViolationFly, From: from, To: to, Value: float64(m.airTicks), Limit: float64(tickLimit)}, false
+		}
+	}
+	return MovementViolation{}, true
+}
+
+// resetMovementValidation resets the state of the validation of the movement of the player to the position passed,
+// such as after it was teleported.
+func (p *Player) resetMovementValidation(pos mgl64.Vec3) {
+	p.movement.mu.Lock()
+	defer p.movement.mu.Unlock()
+	p.movement.airborne, p.movement.airStartY, p.movement.airTicks = false, pos[1], 0
+}
+
+// allowVelocity allows the player to move faster than its speed and stay in the air, because its velocity was set to
+// the velocity passed.
+func (p *Player) allowVelocity(velocity mgl64.Vec3) {
+	p.movement.mu.Lock()
+	defer p.movement.mu.Unlock()
+	p.movement.velocity = math.Max(p.movement.velocity, velocity.Len())
+}
+
+// tickMovementValidation reduces the velocity allowance of the player every tick.
+func (p *Player) tickMovementValidation() {
+	p.movement.mu.Lock()
+	defer p.movement.mu.Unlock()
+	if p.movement.velocity *= velocityDecay; p.movement.velocity < 0.01 {
+		p.movement.velocity = 0
+	}
+}
+
+// onSlipperyBlock checks if the player at the position passed is standing on a block with a friction higher than
+// that of normal blocks, such as ice.
+func (p *Player) onSlipperyBlock(w *world.World, pos mgl64.Vec3) bool {
+	f, ok := w.Block(cube.PosFromVec3(pos).Side(cube.FaceDown)).(block.Frictional)
+	return ok && f.Friction() > 0.6
+}
+
+// inLiquid checks if the player at the position passed is in a liquid.
+func (p *Player) inLiquid(w *world.World, pos mgl64.Vec3) bool {
+	_, feet := w.Liquid(cube.PosFromVec3(pos))
+	_, head := w.Liquid(cube.PosFromVec3(pos.Add(mgl64.Vec3{0, p.EyeHeight()})))
+	return feet || head
+}
+
+// climbing checks if the player at the position passed is on a block that may be climbed, such as a ladder.
+func (p *Player) climbing(w *world.World, pos mgl64.Vec3) bool {
+	_, ok := w.Block(cube.PosFromVec3(pos)).(block.Ladder)
+	return ok
+}
+
+// collidesWithNewBlock checks if the bounding box of the player at the position to intersects with the model of a
+// block that it did not already intersect with at the position from.
+func (p *Player) collidesWithNewBlock(w *world.World, from, to mgl64.Vec3) bool {
+	// The bounding box is shrunk slightly so that standing against or on top of a block is not a collision.
+	bbox := p.Type().BBox(p).Grow(-0.05)
+	before, after := bbox.Translate(from), bbox.Translate(to)
+
+	for _, box := range w.BlockBBoxes(after) {
+		if box.IntersectsWith(after) && !box.IntersectsWith(before) {
+			return true
+		}
+	}
+	return false
+}
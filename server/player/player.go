@@ -15,10 +15,13 @@ import (
 	"github.com/df-mc/dragonfly/server/block/model"
 	"github.com/df-mc/dragonfly/server/cmd"
 	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/entity/effect"
 	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/i18n"
 	"github.com/df-mc/dragonfly/server/internal/sliceutil"
 	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/item/creative"
 	"github.com/df-mc/dragonfly/server/item/enchantment"
 	"github.com/df-mc/dragonfly/server/item/inventory"
 	"github.com/df-mc/dragonfly/server/player/bossbar"
@@ -28,6 +31,7 @@ import (
 	"github.com/df-mc/dragonfly/server/player/skin"
 	"github.com/df-mc/dragonfly/server/player/title"
 	"github.com/df-mc/dragonfly/server/session"
+	"github.com/df-mc/dragonfly/server/text"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/particle"
 	"github.com/df-mc/dragonfly/server/world/sound"
@@ -55,8 +59,23 @@ type Player struct {
 	// s holds the session of the player. This field should not be used directly, but instead,
 	// Player.session() should be called.
 	s atomic.Value[*session.Session]
-	// h holds the current Handler of the player. It may be changed at any time by calling the Handle method.
-	h atomic.Value[Handler]
+	// h holds the Handlers of the player. The Handler set using the Handle method is held alongside the Handlers
+	// added using the Subscribe method.
+	h *bus
+	// hMu guards handler, the Handler set using the Handle method, and removeH, which removes it from h again.
+	hMu     sync.Mutex
+	handler Handler
+	removeH func()
+	// controller holds the Controller of a player without a session. It may be set by calling the Control method.
+	controller atomic.Value[Controller]
+	// chatChannel holds the chat that the player writes messages in. If nil, chat.Global is used.
+	chatChannel atomic.Value[*chat.Chat]
+
+	// titleMu guards titleUntil and titleQueue. titleUntil is the time at which the title currently shown has
+	// faded out and titleQueue holds the titles queued using QueueTitle.
+	titleMu    sync.Mutex
+	titleUntil time.Time
+	titleQueue []title.Title
 
 	inv, offHand, enderChest *inventory.Inventory
 	armour                   *inventory.Armour
@@ -65,6 +84,8 @@ type Player struct {
 	sneaking, sprinting, swimming, gliding, flying,
 	invisible, immobile, onGround, usingItem atomic.Bool
 	usingSince atomic.Int64
+	// blocking holds if the player was blocking with a shield when it was last ticked.
+	blocking atomic.Bool
 
 	glideTicks   atomic.Int64
 	fireTicks    atomic.Int64
@@ -79,17 +100,27 @@ type Player struct {
 	// lastTickedWorld holds the world that the player was in, in the last tick.
 	lastTickedWorld *world.World
 
-	speed      atomic.Float64
+	attributes *attribute.Set
 	health     *entity.HealthManager
 	experience *entity.ExperienceManager
 	effects    *entity.EffectManager
+	scheduler  *entity.Scheduler
+	metadata   world.Metadata
 
 	lastXPPickup  atomic.Value[time.Time]
 	immunityTicks atomic.Int64
 
-	deathMu        sync.Mutex
-	deathPos       *mgl64.Vec3
-	deathDimension world.Dimension
+	// locationMu guards lastDeath, the Location at which the player last died, and lastTeleport, the Location that
+	// the player was at before it was last teleported.
+	locationMu   sync.Mutex
+	lastDeath    *Location
+	lastTeleport *Location
+
+	// sleepMu guards sleepPos, the position of the bed that the player is sleeping in, and bedSpawn, the position of
+	// the bed that the player respawns at.
+	sleepMu  sync.Mutex
+	sleepPos *cube.Pos
+	bedSpawn *cube.Pos
 
 	enchantSeed atomic.Int64
 
@@ -100,9 +131,27 @@ type Player struct {
 	breaking          atomic.Bool
 	breakingPos       atomic.Value[cube.Pos]
 	lastBreakDuration time.Duration
+	// breakProgress is the fraction of the block at breakingPos that has been broken so far. It is increased every
+	// tick according to the break time of the block at that moment and must be close to 1 when the player finishes
+	// breaking the block.
+	breakProgress atomic.Float64
+	// breakStart is the time at which the player started breaking the block at breakingPos. It is used next to
+	// breakProgress, so that players are not kicked back when the server ticks slower than the client.
+	breakStart atomic.Value[time.Time]
 
 	breakParticleCounter atomic.Uint32
 
+	// movement holds the state used to validate the movement of the player, as specified by its MovementPolicy.
+	movement movementState
+
+	inPortal, awaitPortalExit atomic.Bool
+	portalTicks               atomic.Int64
+	travelling                atomic.Bool
+
+	// seatMu guards seat, which holds the seat entity that the player is currently seated on, if any.
+	seatMu sync.Mutex
+	seat   *entity.Ent
+
 	hunger *hungerManager
 }
 
@@ -123,13 +172,14 @@ func New(name string, skin skin.Skin, pos mgl64.Vec3) *Player {
 		armour:            inventory.NewArmour(p.broadcastArmour),
 		hunger:            newHungerManager(),
 		health:            entity.NewHealthManager(20, 20),
+		attributes:        attribute.NewSet(p.updateAttribute),
 		experience:        entity.NewExperienceManager(),
 		effects:           entity.NewEffectManager(),
+		scheduler:         entity.NewScheduler(),
 		gameMode:          *atomic.NewValue[world.GameMode](world.GameModeSurvival),
-		h:                 *atomic.NewValue[Handler](NopHandler{}),
+		h:                 &bus{},
 		name:              name,
 		skin:              *atomic.NewValue(skin),
-		speed:             *atomic.NewFloat64(0.1),
 		nameTag:           *atomic.NewValue(name),
 		heldSlot:          atomic.NewUint32(0),
 		locale:            language.BritishEnglish,
@@ -239,7 +289,7 @@ func (p *Player) SetSkin(skin skin.Skin) {
 		return
 	}
 	ctx := event.C()
-	if p.Handler().HandleSkinChange(ctx, &skin); ctx.Cancelled() {
+	if p.h.HandleSkinChange(ctx, &skin); ctx.Cancelled() {
 		p.session().ViewSkin(p)
 		return
 	}
@@ -255,13 +305,28 @@ func (p *Player) Locale() language.Tag {
 }
 
 // Handle changes the current Handler of the player. As a result, events called by the player will call
-// handlers of the Handler passed.
-// Handle sets the player's Handler to NopHandler if nil is passed.
+// handlers of the Handler passed. The Handler is called with event.PriorityNormal, alongside the Handlers added
+// using Subscribe.
+// Handle removes the current Handler of the player if nil is passed.
 func (p *Player) Handle(h Handler) {
-	if h == nil {
-		h = NopHandler{}
+	p.hMu.Lock()
+	defer p.hMu.Unlock()
+	if p.removeH != nil {
+		p.removeH()
+		p.removeH = nil
+	}
+	p.handler = h
+	if h != nil {
+		p.removeH = p.h.Add(h, event.PriorityNormal)
 	}
-	p.h.Store(h)
+}
+
+// Subscribe adds a Handler to the player that is called for events of the player in addition to the Handler set
+// using Handle and any other Handlers subscribed. Handlers are called in the order of their event.Priority, so that
+// Handlers with a higher event.Priority may override the outcome of an event. The function returned removes the
+// Handler from the player again.
+func (p *Player) Subscribe(h Handler, priority event.Priority) (unsubscribe func()) {
+	return p.h.Add(h, priority)
 }
 
 // Message sends a formatted message to the player. The message is formatted following the rules of
@@ -276,6 +341,18 @@ func (p *Player) Messagef(f string, a ...any) {
 	p.session().SendMessage(fmt.Sprintf(f, a...))
 }
 
+// Messaget sends a translated message to the player. If the key passed is translated in the server-side
+// translations of i18n.Global, the message is translated into the locale of the player there. Otherwise, the key is
+// sent to the client, which translates it using its own language files, such as for
+// 'commands.generic.unknown'. The arguments passed replace the placeholders in the message.
+func (p *Player) Messaget(key string, a ...any) {
+	if msg, ok := i18n.Global.Translate(p.locale, key, a...); ok {
+		p.session().SendMessage(msg)
+		return
+	}
+	p.session().SendTranslation(key, i18n.Args(a...))
+}
+
 // SendPopup sends a formatted popup to the player. The popup is shown above the hotbar of the player and
 // overwrites/is overwritten by the name of the item equipped.
 // The popup is formatted following the rules of fmt.Sprintln without a newline at the end.
@@ -301,6 +378,12 @@ func (p *Player) SendToast(title, message string) {
 	p.session().SendToast(title, message)
 }
 
+// ResendRecipes sends all crafting recipes currently registered to the player again, so that recipes registered
+// after the player joined may be used by it.
+func (p *Player) ResendRecipes() {
+	p.session().SendRecipes()
+}
+
 // ResetFallDistance resets the player's fall distance.
 func (p *Player) ResetFallDistance() {
 	p.fallDistance.Store(0)
@@ -315,17 +398,72 @@ func (p *Player) FallDistance() float64 {
 // and the text it shows.
 // If non-empty, the subtitle is shown in a smaller font below the title. The same counts for the action text
 // of the title, which is shown in a font similar to that of a tip/popup.
+// If a title is currently shown, it is replaced by the title passed without fading in again, so that titles sent
+// in quick succession, such as those of a countdown, do not flicker. QueueTitle may be used to show a title after
+// the current one instead.
 func (p *Player) SendTitle(t title.Title) {
-	p.session().SetTitleDurations(t.FadeInDuration(), t.Duration(), t.FadeOutDuration())
+	p.titleMu.Lock()
+	defer p.titleMu.Unlock()
+	p.showTitle(t)
+}
+
+// QueueTitle queues a title to be shown to the player once the title currently shown, and all titles queued
+// before it, have faded out. If no title is currently shown, the title is shown immediately.
+func (p *Player) QueueTitle(t title.Title) {
+	p.titleMu.Lock()
+	defer p.titleMu.Unlock()
+	if len(p.titleQueue) == 0 && time.Now().After(p.titleUntil) {
+		p.showTitle(t)
+		return
+	}
+	p.titleQueue = append(p.titleQueue, t)
+}
+
+// RemoveTitle removes the title currently shown to the player and all titles queued using QueueTitle.
+func (p *Player) RemoveTitle() {
+	p.titleMu.Lock()
+	defer p.titleMu.Unlock()
+	p.titleQueue, p.titleUntil = nil, time.Time{}
+	p.session().ClearTitle()
+}
+
+// SendActionBar sends an action bar message to the player. The message is shown above the hotbar of the player,
+// in a font similar to that of a tip/popup. The message is formatted following the rules of fmt.Sprintln without
+// a newline at the end.
+func (p *Player) SendActionBar(a ...any) {
+	p.session().SendActionBarMessage(format(a))
+}
+
+// showTitle shows a title to the player. The subtitle is sent before the title itself, as the client only shows
+// a subtitle set before the title. p.titleMu must be held when showTitle is called.
+func (p *Player) showTitle(t title.Title) {
+	s := p.session()
 	if t.Text() != "" || t.Subtitle() != "" {
-		p.session().SendTitle(t.Text())
-		if t.Subtitle() != "" {
-			p.session().SendSubtitle(t.Subtitle())
+		now, fadeIn := time.Now(), t.FadeInDuration()
+		if now.Before(p.titleUntil) {
+			// A title is still shown, so replace it without fading in again to prevent it from flickering.
+			fadeIn = 0
 		}
+		s.SetTitleDurations(fadeIn, t.Duration(), t.FadeOutDuration())
+		s.SendSubtitle(t.Subtitle())
+		s.SendTitle(t.Text())
+		p.titleUntil = now.Add(fadeIn + t.Duration() + t.FadeOutDuration())
 	}
 	if t.ActionText() != "" {
-		p.session().SendActionBarMessage(t.ActionText())
+		s.SendActionBarMessage(t.ActionText())
+	}
+}
+
+// tickTitles shows the next title queued using QueueTitle once the title currently shown has faded out.
+func (p *Player) tickTitles() {
+	p.titleMu.Lock()
+	defer p.titleMu.Unlock()
+	if len(p.titleQueue) == 0 || time.Now().Before(p.titleUntil) {
+		return
 	}
+	t := p.titleQueue[0]
+	p.titleQueue = p.titleQueue[1:]
+	p.showTitle(t)
 }
 
 // SendScoreboard sends a scoreboard to the player. The scoreboard will be present indefinitely until removed
@@ -354,15 +492,67 @@ func (p *Player) RemoveBossBar() {
 	p.session().RemoveBossBar()
 }
 
-// Chat writes a message in the global chat (chat.Global). The message is prefixed with the name of the
-// player and is formatted following the rules of fmt.Sprintln.
+// Chat writes a message in the chat channel of the player, which is the global chat (chat.Global) unless
+// changed using SetChatChannel. The message is formatted following the rules of fmt.Sprintln and is passed
+// through the filters of chat.Global and of the channel. It is then formatted using the Formatter of the channel,
+// which prefixes it with the name of the player by default. Players muted by the chat.Muter set using
+// chat.SetMuter cannot chat.
 func (p *Player) Chat(msg ...any) {
-	message := format(msg)
+	message, channel := format(msg), p.ChatChannel()
+	if reason, muted := chat.Muted(p.UUID()); muted {
+		p.Message(reason)
+		return
+	}
+	if err := chat.Global.Filter(p.name, &message); err != nil {
+		p.Message(text.Colourf("<red>%v</red>", err))
+		return
+	}
+	if channel != chat.Global {
+		if err := channel.Filter(p.name, &message); err != nil {
+			p.Message(text.Colourf("<red>%v</red>", err))
+			return
+		}
+	}
+	e := &ChatEvent{Channel: channel, Message: message, Recipients: map[chat.Subscriber]struct{}{}}
+	for _, r := range channel.Recipients() {
+		e.Recipients[r] = struct{}{}
+	}
+	if channel == WorldChat {
+		for _, ent := range p.World().Entities() {
+			if r, ok := ent.(chat.Subscriber); ok {
+				e.Recipients[r] = struct{}{}
+			}
+		}
+	} else if radius, ok := LocalRadius(channel); ok {
+		for _, ent := range p.World().EntitiesWithin(p.Type().BBox(p).Translate(p.Position()).Grow(radius), nil) {
+			if r, ok := ent.(chat.Subscriber); ok && ent.Position().Sub(p.Position()).Len() <= radius {
+				e.Recipients[r] = struct{}{}
+			}
+		}
+	}
 	ctx := event.C()
-	if p.Handler().HandleChat(ctx, &message); ctx.Cancelled() {
+	if p.h.HandleChat(ctx, e); ctx.Cancelled() {
 		return
 	}
-	_, _ = fmt.Fprintf(chat.Global, "<%v> %v\n", p.name, message)
+	line := channel.Format(p.name, e.Message)
+	for r := range e.Recipients {
+		r.Message(line)
+	}
+}
+
+// SetChatChannel changes the chat channel that the player writes messages in using Chat. If nil is passed, the
+// player writes in the global chat (chat.Global). The player keeps receiving messages of the channels it is
+// subscribed to, regardless of the channel it writes in.
+func (p *Player) SetChatChannel(c *chat.Chat) {
+	p.chatChannel.Store(c)
+}
+
+// ChatChannel returns the chat channel that the player writes messages in, as set using SetChatChannel.
+func (p *Player) ChatChannel() *chat.Chat {
+	if c := p.chatChannel.Load(); c != nil {
+		return c
+	}
+	return chat.Global
 }
 
 // ExecuteCommand executes a command passed as the player. If the command could not be found, or if the usage
@@ -381,7 +571,7 @@ func (p *Player) ExecuteCommand(commandLine string) {
 		return
 	}
 	ctx := event.C()
-	if p.Handler().HandleCommandExecution(ctx, command, args[1:]); ctx.Cancelled() {
+	if p.h.HandleCommandExecution(ctx, command, args[1:]); ctx.Cancelled() {
 		return
 	}
 	command.Execute(strings.Join(args[1:], " "), p)
@@ -396,7 +586,7 @@ func (p *Player) Transfer(address string) error {
 	}
 
 	ctx := event.C()
-	if p.Handler().HandleTransfer(ctx, addr); ctx.Cancelled() {
+	if p.h.HandleTransfer(ctx, addr); ctx.Cancelled() {
 		return nil
 	}
 	p.session().Transfer(addr.IP, addr.Port)
@@ -427,6 +617,18 @@ func (p *Player) HideCoordinates() {
 	p.session().EnableCoordinates(false)
 }
 
+// SetViewDistance limits the view distance of the player to r chunks. Chunks further away than the view distance
+// are not sent to the player, even if it requested a higher view distance itself. The view distance is always
+// limited by the maximum chunk radius of the server. If r is 0 or lower, the limit is removed.
+func (p *Player) SetViewDistance(r int) {
+	p.session().SetViewDistance(r)
+}
+
+// ViewDistance returns the radius in chunks around the player in which chunks are sent to it.
+func (p *Player) ViewDistance() int {
+	return p.session().ViewDistance()
+}
+
 // EnableInstantRespawn enables the vanilla instant respawn for the player.
 func (p *Player) EnableInstantRespawn() {
 	p.session().EnableInstantRespawn(true)
@@ -462,16 +664,16 @@ func (p *Player) ScoreTag() string {
 }
 
 // SetSpeed sets the speed of the player. The value passed is the blocks/tick speed that the player will then
-// obtain.
+// obtain. SetSpeed changes the base value of the attribute.MovementSpeed of the player, so that modifiers, such as
+// those of sprinting or the Speed effect, still apply on top of the speed passed.
 func (p *Player) SetSpeed(speed float64) {
-	p.speed.Store(speed)
-	p.session().SendSpeed(speed)
+	p.attributes.SetBase(attribute.MovementSpeed, speed)
 }
 
 // Speed returns the speed of the player, returning a value that indicates the blocks/tick speed. The default
 // speed of a player is 0.1.
 func (p *Player) Speed() float64 {
-	return p.speed.Load()
+	return p.attributes.Value(attribute.MovementSpeed)
 }
 
 // Health returns the current health of the player. It will always be lower than Player.MaxHealth().
@@ -486,11 +688,34 @@ func (p *Player) MaxHealth() float64 {
 }
 
 // SetMaxHealth sets the maximum health of the player. If the current health of the player is higher than the
-// new maximum health, the health is set to the new maximum.
-// SetMaxHealth panics if the max health passed is 0 or lower.
+// new maximum health, the health is set to the new maximum. SetMaxHealth changes the base value of the
+// attribute.MaxHealth of the player, so that modifiers, such as that of the Health Boost effect, still apply.
 func (p *Player) SetMaxHealth(health float64) {
-	p.health.SetMaxHealth(health)
-	p.session().SendHealth(p.health)
+	p.attributes.SetBase(attribute.MaxHealth, health)
+}
+
+// Attributes returns the attribute.Set of the Player, which holds attributes such as its maximum health, movement
+// speed and attack damage. Modifiers may be added to these attributes to change their values, after which the
+// new values are sent to the player.
+func (p *Player) Attributes() *attribute.Set {
+	return p.attributes
+}
+
+// Metadata returns the Metadata of the Player, which may be used to store values with the player. Persistent values
+// are saved with the player data and are available again when the player joins the next time.
+func (p *Player) Metadata() *world.Metadata {
+	return &p.metadata
+}
+
+// updateAttribute is called when the value of an attribute of the player changes. It applies the new value where
+// needed and sends it to the player.
+func (p *Player) updateAttribute(a attribute.Attribute, value float64) {
+	if a == attribute.MaxHealth {
+		p.health.SetMaxHealth(value)
+		p.session().SendHealth(p.health)
+		return
+	}
+	p.session().SendAttribute(a, value)
 }
 
 // addHealth adds health to the player's current health.
@@ -509,7 +734,7 @@ func (p *Player) Heal(health float64, source world.HealingSource) {
 		return
 	}
 	ctx := event.C()
-	if p.Handler().HandleHeal(ctx, &health, source); ctx.Cancelled() {
+	if p.h.HandleHeal(ctx, &health, source); ctx.Cancelled() {
 		return
 	}
 	p.addHealth(health)
@@ -551,7 +776,9 @@ func (p *Player) fall(distance float64) {
 	if dmg < 0.5 {
 		return
 	}
-	p.Hurt(math.Ceil(dmg), entity.FallDamageSource{})
+	if dmg = w.FallDamage(math.Ceil(dmg), distance); dmg > 0 {
+		p.Hurt(dmg, entity.FallDamageSource{})
+	}
 }
 
 // Hurt hurts the player for a given amount of damage. The source passed
@@ -568,12 +795,19 @@ func (p *Player) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
 	}
 	immunity := time.Second / 2
 	ctx := event.C()
-	if p.Handler().HandleHurt(ctx, &dmg, &immunity, src); ctx.Cancelled() {
+	if p.h.HandleHurt(ctx, &dmg, &immunity, src); ctx.Cancelled() {
+		return 0, false
+	}
+	if p.World().Events().HandleEntityHurt(ctx, p, &dmg, src); ctx.Cancelled() {
 		return 0, false
 	}
 	if dmg < 0 {
 		return 0, true
 	}
+	if p.blockWithShield(dmg, src) {
+		return 0, false
+	}
+	p.Wake()
 
 	totalDamage := p.FinalDamageFrom(dmg, src)
 	damageLeft := totalDamage
@@ -617,12 +851,101 @@ func (p *Player) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
 	}
 
 	p.SetAttackImmunity(immunity)
-	if p.Dead() {
+	if p.Dead() && !p.useTotem(src) {
 		p.kill(src)
 	}
 	return totalDamage, true
 }
 
+// Blocking checks if the player is currently blocking with a shield. A player blocks if it is sneaking while holding
+// an item.Shield in either hand and the shield is not on cooldown, for example after being hit by an axe.
+func (p *Player) Blocking() bool {
+	if !p.Sneaking() || p.HasCooldown(item.Shield{}) {
+		return false
+	}
+	mainHand, offHand := p.HeldItems()
+	_, mainShield := mainHand.Item().(item.Shield)
+	_, offShield := offHand.Item().(item.Shield)
+	return mainShield || offShield
+}
+
+// blockWithShield attempts to block the damage from the source passed using the shield held by the player. Only
+// attacks and projectiles coming from in front of the player may be blocked. If the damage was blocked, the shield
+// is damaged and true is returned. Attacks with an axe disable the shield for five seconds.
+func (p *Player) blockWithShield(dmg float64, src world.DamageSource) bool {
+	if !p.Blocking() {
+		return false
+	}
+	var origin world.Entity
+	switch s := src.(type) {
+	case entity.AttackDamageSource:
+		origin = s.Attacker
+	case entity.ProjectileDamageSource:
+		origin = s.Projectile
+	}
+	if origin == nil {
+		return false
+	}
+	diff := origin.Position().Sub(p.Position())
+	diff[1] = 0
+	if diff.Len() != 0 && diff.Normalize().Dot(p.Rotation().Vec3()) < 0 {
+		// The damage came from behind the player.
+		return false
+	}
+
+	mainHand, offHand := p.HeldItems()
+	if dmg >= 3 {
+		if _, ok := mainHand.Item().(item.Shield); ok {
+			mainHand = p.damageItem(mainHand, 1+int(math.Floor(dmg)))
+		} else {
+			offHand = p.damageItem(offHand, 1+int(math.Floor(dmg)))
+		}
+		p.SetHeldItems(mainHand, offHand)
+	}
+	p.World().PlaySound(p.Position(), sound.ShieldBlock{})
+
+	if u, ok := origin.(item.User); ok {
+		held, _ := u.HeldItems()
+		if _, ok := held.Item().(item.Axe); ok {
+			p.SetCooldown(item.Shield{}, time.Second*5)
+			p.updateState()
+		}
+	}
+	return true
+}
+
+// useTotem attempts to save the player from dying using a totem held in either hand. If the player holds a totem, it
+// is consumed, the player is healed to a single health point and is given Regeneration, Absorption and Fire
+// Resistance, after which true is returned. Totems cannot save the player from damage by the void.
+func (p *Player) useTotem(src world.DamageSource) bool {
+	if _, ok := src.(entity.VoidDamageSource); ok {
+		return false
+	}
+	mainHand, offHand := p.HeldItems()
+	if _, ok := offHand.Item().(item.Totem); ok {
+		offHand = p.subtractItem(offHand, 1)
+	} else if _, ok := mainHand.Item().(item.Totem); ok {
+		mainHand = p.subtractItem(mainHand, 1)
+	} else {
+		return false
+	}
+	p.SetHeldItems(mainHand, offHand)
+	p.addHealth(1 - p.Health())
+
+	for _, e := range p.Effects() {
+		p.RemoveEffect(e.Type())
+	}
+	p.AddEffect(effect.New(effect.Regeneration{}, 2, time.Second*40))
+	p.AddEffect(effect.New(effect.Absorption{}, 2, time.Second*5))
+	p.AddEffect(effect.New(effect.FireResistance{}, 1, time.Second*40))
+
+	for _, viewer := range p.viewers() {
+		viewer.ViewEntityAction(p, entity.TotemUseAction{})
+	}
+	p.World().PlaySound(p.Position(), sound.Totem{})
+	return true
+}
+
 // FinalDamageFrom resolves the final damage received by the player if it is attacked by the source passed
 // with the damage passed. FinalDamageFrom takes into account things such as the armour worn and the
 // enchantments on the individual pieces.
@@ -679,7 +1002,8 @@ func (p *Player) knockBack(src mgl64.Vec3, force, height float64) {
 	}
 	velocity[1] = height
 
-	p.SetVelocity(velocity.Mul(1 - p.Armour().KnockBackResistance()))
+	resistance := p.attributes.ValueWith(attribute.KnockBackResistance, attribute.Modifier{ID: "armour", Amount: p.Armour().KnockBackResistance()})
+	p.SetVelocity(velocity.Mul(1 - resistance))
 }
 
 // AttackImmune checks if the player is currently immune to entity attacks, meaning it was recently attacked.
@@ -759,6 +1083,12 @@ func (p *Player) Effects() []effect.Effect {
 	return p.effects.Effects()
 }
 
+// Scheduler returns the entity.Scheduler of the Player, which may be used to run entity.Tasks such as damage or
+// healing over time on the Player. All Tasks are cancelled when the Player dies.
+func (p *Player) Scheduler() *entity.Scheduler {
+	return p.scheduler
+}
+
 // BeaconAffected ...
 func (*Player) BeaconAffected() bool {
 	return true
@@ -770,14 +1100,18 @@ func (p *Player) Exhaust(points float64) {
 	if !p.GameMode().AllowsTakingDamage() || p.World().Difficulty().FoodRegenerates() {
 		return
 	}
+	ctx := event.C()
+	if p.h.HandleExhaust(ctx, &points); ctx.Cancelled() || points <= 0 {
+		return
+	}
 	before := p.hunger.Food()
 	p.hunger.exhaust(points)
 	if after := p.hunger.Food(); before != after {
 		// Temporarily set the food level back so that it hasn't yet changed once the event is handled.
 		p.hunger.SetFood(before)
 
-		ctx := event.C()
-		if p.Handler().HandleFoodLoss(ctx, before, &after); ctx.Cancelled() {
+		ctx = event.C()
+		if p.h.HandleFoodLoss(ctx, before, &after); ctx.Cancelled() {
 			return
 		}
 		p.hunger.SetFood(after)
@@ -795,41 +1129,35 @@ func (p *Player) Dead() bool {
 	return p.Health() <= mgl64.Epsilon
 }
 
-// DeathPosition returns the last position the player was at when they died. If the player has never died, the third
-// return value will be false.
-func (p *Player) DeathPosition() (mgl64.Vec3, world.Dimension, bool) {
-	p.deathMu.Lock()
-	defer p.deathMu.Unlock()
-	if p.deathPos == nil {
-		return mgl64.Vec3{}, nil, false
-	}
-	return *p.deathPos, p.deathDimension, true
-}
-
 // kill kills the player, clearing its inventories and resetting it to its base state.
 func (p *Player) kill(src world.DamageSource) {
+	p.Wake()
 	for _, viewer := range p.viewers() {
 		viewer.ViewEntityAction(p, entity.DeathAction{})
 	}
 
 	p.addHealth(-p.MaxHealth())
 
-	keepInv := false
-	p.Handler().HandleDeath(src, &keepInv)
+	w := p.World()
+	loc := p.currentLocation(w)
+	keepInv, msg := w.BoolGameRule(world.GameRuleKeepInventory), p.deathMessage(src)
+	p.h.HandleDeath(src, &keepInv, &msg)
+	if w.BoolGameRule(world.GameRuleShowDeathMessages) {
+		broadcastDeathMessage(msg)
+	}
 	p.StopSneaking()
 	p.StopSprinting()
+	p.dismount()
 
-	w, pos := p.World(), p.Position()
 	if !keepInv {
 		p.dropContents()
 	}
+	p.session().SendRespawnSearching(w.PlayerSpawn(p.UUID()).Vec3Middle())
 	for _, e := range p.Effects() {
 		p.RemoveEffect(e.Type())
 	}
-
-	p.deathMu.Lock()
-	defer p.deathMu.Unlock()
-	p.deathPos, p.deathDimension = &pos, w.Dimension()
+	p.scheduler.Clear()
+	p.recordLocation(LocationDeath, loc)
 
 	// Wait a little before removing the entity. The client displays a death animation while the player is dying.
 	time.AfterFunc(time.Millisecond*1100, func() {
@@ -851,7 +1179,7 @@ func (p *Player) kill(src world.DamageSource) {
 func (p *Player) dropContents() {
 	w, pos := p.World(), p.Position()
 	for _, orb := range entity.NewExperienceOrbs(pos, int(math.Min(float64(p.experience.Level()*7), 100))) {
-		orb.SetVelocity(mgl64.Vec3{(rand.Float64()*0.2 - 0.1) * 2, rand.Float64() * 0.4, (rand.Float64()*0.2 - 0.1) * 2})
+		orb.SetVelocity(mgl64.Vec3{(w.Rand().Float64()*0.2 - 0.1) * 2, w.Rand().Float64() * 0.4, (w.Rand().Float64()*0.2 - 0.1) * 2})
 		w.AddEntity(orb)
 	}
 	p.experience.Reset()
@@ -863,7 +1191,7 @@ func (p *Player) dropContents() {
 			continue
 		}
 		ent := entity.NewItem(it, pos)
-		ent.SetVelocity(mgl64.Vec3{rand.Float64()*0.2 - 0.1, 0.2, rand.Float64()*0.2 - 0.1})
+		ent.SetVelocity(mgl64.Vec3{w.Rand().Float64()*0.2 - 0.1, 0.2, w.Rand().Float64()*0.2 - 0.1})
 		w.AddEntity(ent)
 	}
 }
@@ -885,14 +1213,24 @@ func (p *Player) Respawn() {
 	// always bring us back to the overworld.
 	w = w.PortalDestination(w.Dimension())
 	pos := w.PlayerSpawn(p.UUID()).Vec3Middle()
+	if bedPos, ok := p.BedSpawn(); ok && w.Dimension() == world.Overworld {
+		if bedSpawn, ok := bedRespawnPosition(w, bedPos); ok {
+			pos = bedSpawn
+		} else {
+			p.ResetBedSpawn()
+			p.Messaget("tile.bed.notValid")
+		}
+	}
 
-	p.Handler().HandleRespawn(&pos, &w)
+	p.h.HandleRespawn(&pos, &w)
+	pos = p.safePosition(w, pos)
 
 	w.AddEntity(p)
-	p.Teleport(pos)
+	p.teleportTo(w, pos, false)
 	p.session().SendRespawn(pos)
 
 	p.SetVisible()
+	p.SetAttackImmunity(respawnImmunity)
 }
 
 // StartSprinting makes a player start sprinting, increasing the speed of the player by 30% and making
@@ -903,18 +1241,21 @@ func (p *Player) StartSprinting() {
 		return
 	}
 	ctx := event.C()
-	if p.Handler().HandleToggleSprint(ctx, true); ctx.Cancelled() {
+	if p.h.HandleToggleSprint(ctx, true); ctx.Cancelled() {
 		return
 	}
 	if !p.sprinting.CAS(false, true) {
 		return
 	}
 	p.StopSneaking()
-	p.SetSpeed(p.Speed() * 1.3)
+	p.attributes.AddModifier(attribute.MovementSpeed, sprintModifier)
 
 	p.updateState()
 }
 
+// sprintModifier is the attribute.Modifier added to the movement speed of a player while it is sprinting.
+var sprintModifier = attribute.Modifier{ID: "sprinting", Amount: 0.3, Operation: attribute.OperationMultiplyTotal}
+
 // Sprinting checks if the player is currently sprinting.
 func (p *Player) Sprinting() bool {
 	return p.sprinting.Load()
@@ -923,13 +1264,13 @@ func (p *Player) Sprinting() bool {
 // StopSprinting makes a player stop sprinting, setting back the speed of the player to its original value.
 func (p *Player) StopSprinting() {
 	ctx := event.C()
-	if p.Handler().HandleToggleSprint(ctx, false); ctx.Cancelled() {
+	if p.h.HandleToggleSprint(ctx, false); ctx.Cancelled() {
 		return
 	}
 	if !p.sprinting.CAS(true, false) {
 		return
 	}
-	p.SetSpeed(p.Speed() / 1.3)
+	p.attributes.RemoveModifier(attribute.MovementSpeed, sprintModifier.ID)
 
 	p.updateState()
 }
@@ -939,7 +1280,7 @@ func (p *Player) StopSprinting() {
 // If the player is sprinting while StartSneaking is called, the sprinting is stopped.
 func (p *Player) StartSneaking() {
 	ctx := event.C()
-	if p.Handler().HandleToggleSneak(ctx, true); ctx.Cancelled() {
+	if p.h.HandleToggleSneak(ctx, true); ctx.Cancelled() {
 		return
 	}
 	if !p.sneaking.CAS(false, true) {
@@ -960,7 +1301,7 @@ func (p *Player) Sneaking() bool {
 // will not do anything.
 func (p *Player) StopSneaking() {
 	ctx := event.C()
-	if p.Handler().HandleToggleSneak(ctx, false); ctx.Cancelled() {
+	if p.h.HandleToggleSneak(ctx, false); ctx.Cancelled() {
 		return
 	}
 	if !p.sneaking.CAS(true, false) {
@@ -1047,7 +1388,7 @@ func (p *Player) Jump() {
 		return
 	}
 
-	p.Handler().HandleJump()
+	p.h.HandleJump()
 	if p.OnGround() {
 		jumpVel := 0.42
 		if e, ok := p.Effect(effect.JumpBoost{}); ok {
@@ -1062,6 +1403,106 @@ func (p *Player) Jump() {
 	}
 }
 
+// Sit seats the player at the position passed, by making it ride an invisible seat entity created using
+// entity.NewSeat. The player remains seated until Stand is called, which happens when the player sneaks. The player
+// is also dismounted when it teleports, dies, changes world or quits. If the player was already seated, it is moved
+// to the new seat.
+func (p *Player) Sit(pos mgl64.Vec3) {
+	w := p.World()
+	if w == nil || p.Dead() {
+		return
+	}
+	p.dismount()
+	p.StopSneaking()
+	p.StopSprinting()
+	p.StopGliding()
+
+	seat := entity.NewSeat(pos, p)
+	p.seatMu.Lock()
+	p.seat = seat
+	p.seatMu.Unlock()
+
+	p.teleport(pos)
+	for _, v := range p.viewers() {
+		v.ViewEntityState(p)
+	}
+	w.AddEntity(seat)
+}
+
+// SitOn seats the player on top of the block at the position passed using Sit. The player is seated on the lowest
+// top surface of the model of the block that is not covered by another part of it, so that it sits on the lower
+// step of stairs, but on top of upside-down stairs.
+func (p *Player) SitOn(pos cube.Pos) {
+	w := p.World()
+	if w == nil {
+		return
+	}
+	p.Sit(pos.Vec3Middle().Add(mgl64.Vec3{0, seatHeight(w.Block(pos).Model().BBox(pos, w))}))
+}
+
+// seatHeight returns the height above the bottom of a block with the model boxes passed that a player sits at.
+func seatHeight(boxes []cube.BBox) float64 {
+	height, found := 0.0, false
+	for _, box := range boxes {
+		if top := box.Max().Y(); !covered(box, boxes) && (!found || top < height) {
+			height, found = top, true
+		}
+	}
+	return height
+}
+
+// covered checks if the top surface of the box passed is fully covered by any of the boxes passed.
+func covered(box cube.BBox, boxes []cube.BBox) bool {
+	const epsilon = 1e-4
+	for _, o := range boxes {
+		if o != box && o.Min().Y() >= box.Max().Y()-epsilon &&
+			o.Min().X() <= box.Min().X()+epsilon && o.Max().X() >= box.Max().X()-epsilon &&
+			o.Min().Z() <= box.Min().Z()+epsilon && o.Max().Z() >= box.Max().Z()-epsilon {
+			return true
+		}
+	}
+	return false
+}
+
+// Stand makes the player stand up from the seat it is seated on, leaving the player at the position of the seat.
+// Stand does nothing if the player is not seated.
+func (p *Player) Stand() {
+	seat, ok := p.Seat()
+	if !ok {
+		return
+	}
+	sameWorld := seat.World() == p.World()
+	p.dismount()
+	if w := p.World(); sameWorld && w != nil {
+		p.teleport(p.safePosition(w, seat.Position()))
+	}
+}
+
+// Seat returns the seat entity that the player is currently seated on. False is returned if the player is not
+// seated.
+func (p *Player) Seat() (*entity.Ent, bool) {
+	p.seatMu.Lock()
+	defer p.seatMu.Unlock()
+	return p.seat, p.seat != nil
+}
+
+// dismount removes the player from the seat it is seated on, if any, and closes the seat. Unlike Stand, the
+// position of the player is not changed.
+func (p *Player) dismount() {
+	p.seatMu.Lock()
+	seat := p.seat
+	p.seat = nil
+	p.seatMu.Unlock()
+	if seat == nil {
+		return
+	}
+	for _, v := range p.viewers() {
+		v.ViewEntityDismount(p, seat)
+		v.ViewEntityState(p)
+	}
+	_ = seat.Close()
+}
+
 // SetInvisible sets the player invisible, so that other players will not be able to see it.
 func (p *Player) SetInvisible() {
 	if !p.invisible.CAS(false, true) {
@@ -1192,6 +1633,7 @@ func (p *Player) SetGameMode(mode world.GameMode) {
 	} else if !previous.Visible() {
 		p.SetVisible()
 	}
+	p.updateState()
 }
 
 // GameMode returns the current game mode assigned to the player. If not changed, the game mode returned will
@@ -1201,6 +1643,18 @@ func (p *Player) GameMode() world.GameMode {
 	return p.gameMode.Load()
 }
 
+// SetCreativeMenu changes the creative.Menu shown to the Player in the creative inventory, for example to hide
+// items from players of a certain rank. By default, players are shown creative.Default. The Player is only able to
+// take items from the creative inventory that are in the Menu.
+func (p *Player) SetCreativeMenu(m *creative.Menu) {
+	p.session().SendCreativeMenu(m)
+}
+
+// CreativeMenu returns the creative.Menu shown to the Player in the creative inventory.
+func (p *Player) CreativeMenu() *creative.Menu {
+	return p.session().CreativeMenu()
+}
+
 // HasCooldown returns true if the item passed has an active cooldown, meaning it currently cannot be used again. If the
 // world.Item passed is nil, HasCooldown always returns false.
 func (p *Player) HasCooldown(item world.Item) bool {
@@ -1247,7 +1701,7 @@ func (p *Player) UseItem() {
 	if p.HasCooldown(i.Item()) {
 		return
 	}
-	if p.Handler().HandleItemUse(ctx); ctx.Cancelled() {
+	if p.h.HandleItemUse(ctx); ctx.Cancelled() {
 		return
 	}
 	i, left = p.HeldItems()
@@ -1275,8 +1729,7 @@ func (p *Player) UseItem() {
 		// We only swing the player's arm if the item held actually does something. If it doesn't, there is no
 		// reason to swing the arm.
 		p.SwingArm()
-		p.SetHeldItems(p.subtractItem(p.damageItem(i, useCtx.Damage), useCtx.CountSub), left)
-		p.addNewItem(useCtx)
+		p.handleUseContext(useCtx)
 	case item.Consumable:
 		if c, ok := usable.(interface{ CanConsume() bool }); ok && !c.CanConsume() {
 			p.ReleaseItem()
@@ -1303,7 +1756,7 @@ func (p *Player) UseItem() {
 		}
 
 		ctx = event.C()
-		if p.Handler().HandleItemConsume(ctx, i); ctx.Cancelled() {
+		if p.h.HandleItemConsume(ctx, i); ctx.Cancelled() {
 			// Consuming was cancelled, but the client will continue consuming the next item.
 			p.usingSince.Store(time.Now().UnixNano())
 			return
@@ -1357,7 +1810,8 @@ func (p *Player) canRelease() bool {
 	return true
 }
 
-// handleUseContext handles the item.UseContext after the item has been used.
+// handleUseContext handles the item.UseContext after the item has been used, regardless of what it was used on: The
+// item held is damaged and subtracted from, new items are added and consumed items are removed.
 func (p *Player) handleUseContext(ctx *item.UseContext) {
 	i, left := p.HeldItems()
 
@@ -1392,26 +1846,25 @@ func (p *Player) UseItemOnBlock(pos cube.Pos, face cube.Face, clickPos mgl64.Vec
 		p.resendBlocks(pos, w, face)
 		return
 	}
+	if p.spawnProtected(w, pos) {
+		p.resendBlocks(pos, w, face)
+		return
+	}
 	ctx := event.C()
-	if p.Handler().HandleItemUseOnBlock(ctx, pos, face, clickPos); ctx.Cancelled() {
+	if p.h.HandleItemUseOnBlock(ctx, pos, face, clickPos); ctx.Cancelled() {
 		p.resendBlocks(pos, w, face)
 		return
 	}
 	i, left := p.HeldItems()
 	b := w.Block(pos)
-	if act, ok := b.(block.Activatable); ok {
-		// If a player is sneaking, it will not activate the block clicked, unless it is not holding any
-		// items, in which case the block will be activated as usual.
-		if !p.Sneaking() || i.Empty() {
-			p.SwingArm()
-
-			// The block was activated: Blocks such as doors must always have precedence over the item being
-			// used.
-			if useCtx := p.useContext(); act.Activate(pos, face, p.World(), p, useCtx) {
-				p.SetHeldItems(p.subtractItem(p.damageItem(i, useCtx.Damage), useCtx.CountSub), left)
-				p.addNewItem(useCtx)
-				return
-			}
+	if act, ok := b.(block.Activatable); ok && p.activates(i) {
+		p.SwingArm()
+
+		// The block was activated: Blocks such as doors must always have precedence over the item being
+		// used.
+		if useCtx := p.useContext().OnBlock(pos, face, clickPos); act.Activate(pos, face, p.World(), p, useCtx) {
+			p.handleUseContext(useCtx)
+			return
 		}
 	}
 	if i.Empty() {
@@ -1420,13 +1873,16 @@ func (p *Player) UseItemOnBlock(pos cube.Pos, face cube.Face, clickPos mgl64.Vec
 	switch ib := i.Item().(type) {
 	case item.UsableOnBlock:
 		// The item does something when used on a block.
-		useCtx := p.useContext()
+		if p.HasCooldown(i.Item()) {
+			p.resendBlocks(pos, w, face)
+			return
+		}
+		useCtx := p.useContext().OnBlock(pos, face, clickPos)
 		if !ib.UseOnBlock(pos, face, clickPos, p.World(), p, useCtx) {
 			return
 		}
 		p.SwingArm()
-		p.SetHeldItems(p.subtractItem(p.damageItem(i, useCtx.Damage), useCtx.CountSub), left)
-		p.addNewItem(useCtx)
+		p.handleUseContext(useCtx)
 	case world.Block:
 		// The item IS a block, meaning it is being placed.
 		replacedPos := pos
@@ -1435,6 +1891,7 @@ func (p *Player) UseItemOnBlock(pos cube.Pos, face cube.Face, clickPos mgl64.Vec
 			replacedPos = pos.Side(face)
 		}
 		if replaceable, ok := w.Block(replacedPos).(block.Replaceable); !ok || !replaceable.ReplaceableBy(ib) || replacedPos.OutOfBounds(w.Range()) {
+			p.resendBlocks(replacedPos, w, cube.Faces()...)
 			return
 		}
 		if !p.placeBlock(replacedPos, ib, false) || p.GameMode().CreativeInventory() {
@@ -1452,21 +1909,28 @@ func (p *Player) UseItemOnEntity(e world.Entity) bool {
 		return false
 	}
 	ctx := event.C()
-	if p.Handler().HandleItemUseOnEntity(ctx, e); ctx.Cancelled() {
+	if p.h.HandleItemUseOnEntity(ctx, e); ctx.Cancelled() {
 		return false
 	}
-	i, left := p.HeldItems()
+	i, _ := p.HeldItems()
+	useCtx := p.useContext().OnEntity(e)
+	if in, ok := e.(interface {
+		Interact(user item.User, held item.Stack, ctx *item.UseContext) bool
+	}); ok && in.Interact(p, i, useCtx) {
+		// The entity itself handled the interaction, for example an animal being fed.
+		p.SwingArm()
+		p.handleUseContext(useCtx)
+		return true
+	}
 	usable, ok := i.Item().(item.UsableOnEntity)
-	if !ok {
+	if !ok || p.HasCooldown(i.Item()) {
 		return true
 	}
-	useCtx := p.useContext()
 	if !usable.UseOnEntity(e, e.World(), p, useCtx) {
 		return true
 	}
 	p.SwingArm()
-	p.SetHeldItems(p.subtractItem(p.damageItem(i, useCtx.Damage), useCtx.CountSub), left)
-	p.addNewItem(useCtx)
+	p.handleUseContext(useCtx)
 	return true
 }
 
@@ -1487,7 +1951,7 @@ func (p *Player) AttackEntity(e world.Entity) bool {
 	)
 
 	ctx := event.C()
-	if p.Handler().HandleAttackEntity(ctx, e, &force, &height, &critical); ctx.Cancelled() {
+	if p.h.HandleAttackEntity(ctx, e, &force, &height, &critical); ctx.Cancelled() {
 		return false
 	}
 	p.SwingArm()
@@ -1501,13 +1965,9 @@ func (p *Player) AttackEntity(e world.Entity) bool {
 		return true
 	}
 
-	dmg := i.AttackDamage()
-	if strength, ok := p.Effect(effect.Strength{}); ok {
-		dmg += dmg * effect.Strength{}.Multiplier(strength.Level())
-	}
-	if weakness, ok := p.Effect(effect.Weakness{}); ok {
-		dmg -= dmg * effect.Weakness{}.Multiplier(weakness.Level())
-	}
+	// The damage of the item held is added to the attack damage of the player, so that modifiers such as those of
+	// the Strength and Weakness effects also apply to it.
+	dmg := p.attributes.ValueWith(attribute.AttackDamage, attribute.Modifier{ID: "held_item", Amount: i.AttackDamage() - attribute.AttackDamage.Default()})
 	if s, ok := i.Enchantment(enchantment.Sharpness{}); ok {
 		dmg += (enchantment.Sharpness{}).Addend(s.Level())
 	}
@@ -1559,6 +2019,11 @@ func (p *Player) StartBreaking(pos cube.Pos, face cube.Face) {
 	w := p.World()
 	if _, air := w.Block(pos).(block.Air); air || !p.canReach(pos.Vec3Centre()) {
 		// The block was either out of range or air, so it can't be broken by the player.
+		p.resendBlocks(pos, w)
+		return
+	}
+	if p.spawnProtected(w, pos) {
+		p.resendBlocks(pos, w, cube.Faces()...)
 		return
 	}
 	if _, ok := w.Block(pos.Side(face)).(block.Fire); ok {
@@ -1578,7 +2043,7 @@ func (p *Player) StartBreaking(pos cube.Pos, face cube.Face) {
 	p.breakingPos.Store(pos)
 
 	ctx := event.C()
-	if p.Handler().HandleStartBreak(ctx, pos); ctx.Cancelled() {
+	if p.h.HandleStartBreak(ctx, pos); ctx.Cancelled() {
 		return
 	}
 	if punchable, ok := w.Block(pos).(block.Punchable); ok {
@@ -1586,12 +2051,17 @@ func (p *Player) StartBreaking(pos cube.Pos, face cube.Face) {
 	}
 
 	p.breaking.Store(true)
+	p.breakProgress.Store(0)
+	p.breakStart.Store(time.Now())
 	p.SwingArm()
 
 	if p.GameMode().CreativeInventory() {
 		return
 	}
 	p.lastBreakDuration = p.breakTime(pos)
+	if p.lastBreakDuration <= 0 {
+		p.breakProgress.Store(1)
+	}
 	for _, viewer := range p.viewers() {
 		viewer.ViewBlockAction(pos, block.StartCrackAction{BreakTime: p.lastBreakDuration})
 	}
@@ -1629,13 +2099,48 @@ func (p *Player) breakTime(pos cube.Pos) time.Duration {
 func (p *Player) FinishBreaking() {
 	pos := p.breakingPos.Load()
 	if !p.breaking.Load() {
-		p.resendBlock(pos, p.World())
+		p.resendBlocks(pos, p.World(), cube.Faces()...)
 		return
 	}
 	p.AbortBreaking()
+	if !p.GameMode().CreativeInventory() && !p.brokenEnough(pos) {
+		// The player finished breaking the block faster than possible.
+		p.resendBlocks(pos, p.World(), cube.Faces()...)
+		return
+	}
 	p.BreakBlock(pos)
 }
 
+// breakGraceTicks is the amount of ticks that a player may finish breaking a block earlier than the break time of
+// the block allows. This accounts for differences in the timing of the client and the server.
+const breakGraceTicks = 2
+
+// tickBreaking increases the progress of the player breaking the block at the position passed by one tick.
+func (p *Player) tickBreaking(pos cube.Pos) {
+	if breakTime := p.breakTime(pos); breakTime > 0 {
+		p.breakProgress.Add(float64(time.Second/20) / float64(breakTime))
+		return
+	}
+	p.breakProgress.Store(1)
+}
+
+// brokenEnough checks if the player has been breaking the block at the position passed for long enough to break
+// it, allowing for breakGraceTicks ticks of difference. The block is broken enough if either the progress counted
+// in ticks or the time passed since the player started breaking it is sufficient, so that a world ticking slower
+// than usual does not prevent players from breaking blocks.
+func (p *Player) brokenEnough(pos cube.Pos) bool {
+	held, _ := p.HeldItems()
+	breakTime := p.breakTime(pos)
+	if breakTime <= 0 || block.BreaksInstantly(p.World().Block(pos), held) {
+		return true
+	}
+	grace := breakGraceTicks * time.Second / 20
+	if time.Since(p.breakStart.Load()) >= breakTime-grace {
+		return true
+	}
+	return p.breakProgress.Load()+float64(grace)/float64(breakTime) >= 1
+}
+
 // AbortBreaking makes the player stop breaking the block it is currently breaking, or returns immediately
 // if the player isn't breaking anything.
 // Unlike FinishBreaking, AbortBreaking does not stop the animation.
@@ -1704,15 +2209,21 @@ func (p *Player) placeBlock(pos cube.Pos, b world.Block, ignoreBBox bool) bool {
 		p.resendBlocks(pos, w, cube.Faces()...)
 		return false
 	}
+	if p.spawnProtected(w, pos) {
+		p.resendBlocks(pos, w, cube.Faces()...)
+		return false
+	}
+	if !p.GameMode().CreativeInventory() && !block.BuildAllowed(pos, w) {
+		p.resendBlocks(pos, w, cube.Faces()...)
+		return false
+	}
 
 	ctx := event.C()
-	if p.Handler().HandleBlockPlace(ctx, pos, b); ctx.Cancelled() {
+	if p.h.HandleBlockPlace(ctx, pos, b); ctx.Cancelled() {
 		p.resendBlocks(pos, w, cube.Faces()...)
 		return false
 	}
-	w.SetBlock(pos, b, nil)
-	w.PlaySound(pos.Vec3(), sound.BlockPlace{Block: b})
-	p.SwingArm()
+	block.PlaceWithFeedback(w, pos, b, p)
 	return true
 }
 
@@ -1749,11 +2260,19 @@ func (p *Player) BreakBlock(pos cube.Pos) {
 		return
 	}
 	if !p.canReach(pos.Vec3Centre()) || !p.GameMode().AllowsEditing() {
-		p.resendBlocks(pos, w)
+		p.resendBlocks(pos, w, cube.Faces()...)
 		return
 	}
 	if _, breakable := b.(block.Breakable); !breakable && !p.GameMode().CreativeInventory() {
-		p.resendBlocks(pos, w)
+		p.resendBlocks(pos, w, cube.Faces()...)
+		return
+	}
+	if !p.GameMode().CreativeInventory() && !block.BuildAllowed(pos, w) {
+		p.resendBlocks(pos, w, cube.Faces()...)
+		return
+	}
+	if p.spawnProtected(w, pos) {
+		p.resendBlocks(pos, w, cube.Faces()...)
 		return
 	}
 	held, _ := p.HeldItems()
@@ -1761,19 +2280,17 @@ func (p *Player) BreakBlock(pos cube.Pos) {
 
 	xp := 0
 	if breakable, ok := b.(block.Breakable); ok && !p.GameMode().CreativeInventory() {
-		xp = breakable.BreakInfo().XPDrops.RandomValue()
+		xp = breakable.BreakInfo().XPDrops.RandomValue(w.Rand())
 	}
 
 	ctx := event.C()
-	if p.Handler().HandleBlockBreak(ctx, pos, &drops, &xp); ctx.Cancelled() {
-		p.resendBlocks(pos, w)
+	if p.h.HandleBlockBreak(ctx, pos, &drops, &xp); ctx.Cancelled() {
+		p.resendBlocks(pos, w, cube.Faces()...)
 		return
 	}
 	held, left := p.HeldItems()
 
-	p.SwingArm()
-	w.SetBlock(pos, nil, nil)
-	w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: b})
+	block.BreakWithFeedback(w, pos, p)
 
 	if breakable, ok := b.(block.Breakable); ok {
 		info := breakable.BreakInfo()
@@ -1781,13 +2298,13 @@ func (p *Player) BreakBlock(pos cube.Pos) {
 			info.BreakHandler(pos, w, p)
 		}
 		for _, orb := range entity.NewExperienceOrbs(pos.Vec3Centre(), xp) {
-			orb.SetVelocity(mgl64.Vec3{(rand.Float64()*0.2 - 0.1) * 2, rand.Float64() * 0.4, (rand.Float64()*0.2 - 0.1) * 2})
+			orb.SetVelocity(mgl64.Vec3{(w.Rand().Float64()*0.2 - 0.1) * 2, w.Rand().Float64() * 0.4, (w.Rand().Float64()*0.2 - 0.1) * 2})
 			w.AddEntity(orb)
 		}
 	}
 	for _, drop := range drops {
 		ent := entity.NewItem(drop, pos.Vec3Centre())
-		ent.SetVelocity(mgl64.Vec3{rand.Float64()*0.2 - 0.1, 0.2, rand.Float64()*0.2 - 0.1})
+		ent.SetVelocity(mgl64.Vec3{w.Rand().Float64()*0.2 - 0.1, 0.2, w.Rand().Float64()*0.2 - 0.1})
 		w.AddEntity(ent)
 	}
 
@@ -1808,18 +2325,23 @@ func (p *Player) drops(held item.Stack, b world.Block) []item.Stack {
 	}
 	var drops []item.Stack
 	if container, ok := b.(block.Container); ok {
+		inv := container.Inventory()
+		if c, ok := b.(block.Chest); ok {
+			// Only drop the items of the half of a double chest that was broken.
+			inv = c.SingleInventory()
+		}
 		// If the block is a container, it should drop its inventory contents regardless whether the
 		// player is in creative mode or not.
-		drops = container.Inventory().Items()
+		drops = inv.Items()
 		if breakable, ok := b.(block.Breakable); ok && !p.GameMode().CreativeInventory() {
 			if breakable.BreakInfo().Harvestable(t) {
-				drops = append(drops, breakable.BreakInfo().Drops(t, held.Enchantments())...)
+				drops = append(drops, breakable.BreakInfo().Drops(t, held.Enchantments(), p.World().Rand())...)
 			}
 		}
-		container.Inventory().Clear()
+		inv.Clear()
 	} else if breakable, ok := b.(block.Breakable); ok && !p.GameMode().CreativeInventory() {
 		if breakable.BreakInfo().Harvestable(t) {
-			drops = breakable.BreakInfo().Drops(t, held.Enchantments())
+			drops = breakable.BreakInfo().Drops(t, held.Enchantments(), p.World().Rand())
 		}
 	} else if it, ok := b.(world.Item); ok && !p.GameMode().CreativeInventory() {
 		drops = []item.Stack{item.NewStack(it, 1)}
@@ -1852,7 +2374,7 @@ func (p *Player) PickBlock(pos cube.Pos) {
 	}
 
 	ctx := event.C()
-	if p.Handler().HandleBlockPick(ctx, pos, b); ctx.Cancelled() {
+	if p.h.HandleBlockPick(ctx, pos, b); ctx.Cancelled() {
 		return
 	}
 	_, offhand := p.HeldItems()
@@ -1882,12 +2404,82 @@ func (p *Player) PickBlock(pos cube.Pos) {
 
 // Teleport teleports the player to a target position in the world. Unlike Move, it immediately changes the
 // position of the player, rather than showing an animation.
+// If the player would suffocate in a block at the position passed, it is instead teleported to the closest safe
+// position found using world.FindSafePosition, if any.
 func (p *Player) Teleport(pos mgl64.Vec3) {
+	p.TeleportTo(p.World(), pos)
+}
+
+// teleportChunkRadius is the radius in chunks around the destination of a teleport that is loaded before the player
+// is moved there.
+const teleportChunkRadius = 2
+
+// TeleportTo teleports the player to a target position in the world passed, which may be a world other than the
+// one the player is currently in. The chunks around the target position are loaded before the player is moved and
+// the player is dismounted from any entity it is riding. Like Teleport, the player is teleported to the closest safe
+// position if it would suffocate at the position passed. Handler.HandleTeleport is called before the player is
+// teleported and may cancel it, after which Handler.HandleTeleported is called. The location of the player before
+// teleporting may be obtained using LastTeleportPos afterwards.
+func (p *Player) TeleportTo(w *world.World, pos mgl64.Vec3) {
+	p.teleportTo(w, pos, true)
+}
+
+// teleportTo teleports the player to a target position in the world passed, as described in TeleportTo. If record is
+// true, the location of the player before teleporting is recorded as its last teleport location.
+func (p *Player) teleportTo(w *world.World, pos mgl64.Vec3, record bool) {
+	before, from := p.World(), p.Position()
+	if w == nil {
+		return
+	}
+	w.LoadChunks(pos, teleportChunkRadius)
+	pos = p.safePosition(w, pos)
 	ctx := event.C()
-	if p.Handler().HandleTeleport(ctx, pos); ctx.Cancelled() {
+	if p.h.HandleTeleport(ctx, w, pos); ctx.Cancelled() {
 		return
 	}
+	p.dismount()
+	if w != before {
+		w.AddEntity(p)
+	}
+	if record && before != nil {
+		p.recordLocation(LocationTeleport, Location{Position: from, Rotation: p.Rotation(), Dimension: before.Dimension()})
+	}
 	p.teleport(pos)
+	p.h.HandleTeleported(before, from)
+}
+
+// safePosition returns the position passed if the player would not suffocate in a block when at that position in
+// the world passed. If it would, the closest safe position found using world.FindSafePosition is returned instead, or
+// the position passed if none could be found.
+func (p *Player) safePosition(w *world.World, pos mgl64.Vec3) mgl64.Vec3 {
+	if w == nil || !p.GameMode().HasCollision() || !p.obstructedAt(w, pos) {
+		return pos
+	}
+	if safe, ok := w.FindSafePosition(cube.PosFromVec3(pos)); ok {
+		return safe.Vec3Middle()
+	}
+	return pos
+}
+
+// obstructedAt checks if the bounding box of the player at the position passed intersects with the model of any
+// block in the world passed.
+func (p *Player) obstructedAt(w *world.World, pos mgl64.Vec3) bool {
+	// The bounding box is shrunk slightly so that standing against or on top of a block is not an obstruction.
+	bbox := p.Type().BBox(p).Grow(-0.05).Translate(pos)
+	min, max := cube.PosFromVec3(bbox.Min()), cube.PosFromVec3(bbox.Max())
+	for x := min[0]; x <= max[0]; x++ {
+		for y := min[1]; y <= max[1]; y++ {
+			for z := min[2]; z <= max[2]; z++ {
+				blockPos := cube.Pos{x, y, z}
+				for _, box := range w.Block(blockPos).Model().BBox(blockPos, w) {
+					if box.Translate(blockPos.Vec3()).IntersectsWith(bbox) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
 }
 
 // teleport teleports the player to a target position in the world. It does not call the Handler of the
@@ -1899,6 +2491,7 @@ func (p *Player) teleport(pos mgl64.Vec3) {
 	p.pos.Store(pos)
 	p.vel.Store(mgl64.Vec3{})
 	p.ResetFallDistance()
+	p.resetMovementValidation(pos)
 }
 
 // Move moves the player from one position to another in the world, by adding the delta passed to the current
@@ -1908,9 +2501,9 @@ func (p *Player) Move(deltaPos mgl64.Vec3, deltaYaw, deltaPitch float64) {
 	if p.Dead() || (deltaPos.ApproxEqual(mgl64.Vec3{}) && mgl64.FloatEqual(deltaYaw, 0) && mgl64.FloatEqual(deltaPitch, 0)) {
 		return
 	}
-	if p.immobile.Load() {
+	if _, seated := p.Seat(); seated || p.immobile.Load() {
 		if mgl64.FloatEqual(deltaYaw, 0) && mgl64.FloatEqual(deltaPitch, 0) {
-			// If only the position was changed, don't continue with the movement when immobile.
+			// If only the position was changed, don't continue with the movement when immobile or seated.
 			return
 		}
 		// Still update rotation if it was changed.
@@ -1922,12 +2515,15 @@ func (p *Player) Move(deltaPos mgl64.Vec3, deltaYaw, deltaPitch float64) {
 		yaw, pitch            = p.Rotation().Elem()
 		res, resYaw, resPitch = pos.Add(deltaPos), yaw + deltaYaw, pitch + deltaPitch
 	)
+	if !p.validateMovement(w, pos, res) {
+		return
+	}
 	ctx := event.C()
-	if p.Handler().HandleMove(ctx, res, resYaw, resPitch); ctx.Cancelled() {
+	if p.h.HandleMove(ctx, res, resYaw, resPitch); ctx.Cancelled() {
 		if p.session() != session.Nop && pos.ApproxEqual(p.Position()) {
 			// The position of the player was changed and the event cancelled. This means we still need to notify the
 			// player of this movement change.
-			p.teleport(pos)
+			p.session().CorrectMovement(pos, p.OnGround())
 		}
 		return
 	}
@@ -2000,6 +2596,7 @@ func (p *Player) SetVelocity(velocity mgl64.Vec3) {
 		p.vel.Store(velocity)
 		return
 	}
+	p.allowVelocity(velocity)
 	for _, v := range p.viewers() {
 		v.ViewEntityVelocity(p, velocity)
 	}
@@ -2022,7 +2619,7 @@ func (p *Player) Collect(s item.Stack) int {
 		return 0
 	}
 	ctx := event.C()
-	if p.Handler().HandleItemPickup(ctx, &s); ctx.Cancelled() {
+	if p.h.HandleItemPickup(ctx, &s); ctx.Cancelled() {
 		return 0
 	}
 	n, _ := p.Inventory().AddItem(s)
@@ -2047,7 +2644,7 @@ func (p *Player) ResetEnchantmentSeed() {
 // AddExperience adds experience to the player.
 func (p *Player) AddExperience(amount int) int {
 	ctx := event.C()
-	if p.Handler().HandleExperienceGain(ctx, &amount); ctx.Cancelled() {
+	if p.h.HandleExperienceGain(ctx, &amount); ctx.Cancelled() {
 		return 0
 	}
 	before := p.experience.Level()
@@ -2075,6 +2672,14 @@ func (p *Player) ExperienceLevel() int {
 // SetExperienceLevel sets the experience level of the player. The level must have a value between 0 and 2,147,483,647,
 // otherwise the method panics.
 func (p *Player) SetExperienceLevel(level int) {
+	ctx := event.C()
+	if p.h.HandleExperienceLevelChange(ctx, p.experience.Level(), &level); ctx.Cancelled() {
+		return
+	}
+	if level < 0 {
+		// Handlers may assign any value to the level, so make sure it is not negative before setting it.
+		level = 0
+	}
 	p.experience.SetLevel(level)
 	p.session().SendExperience(p.experience)
 }
@@ -2101,7 +2706,7 @@ func (p *Player) CollectExperience(value int) bool {
 	if time.Since(p.lastXPPickup.Load()) < time.Millisecond*100 {
 		return false
 	}
-	value = p.mendItems(value)
+	value = p.mendItems(p.World().Experience(value))
 	p.lastXPPickup.Store(time.Now())
 	if value > 0 {
 		return p.AddExperience(value) > 0
@@ -2133,7 +2738,7 @@ func (p *Player) mendItems(xp int) int {
 	if length == 0 {
 		return xp
 	}
-	foundItem := mendingItems[rand.Intn(length)]
+	foundItem := mendingItems[p.World().Rand().Intn(length)]
 	repairAmount := math.Min(float64(foundItem.MaxDurability()-foundItem.Durability()), float64(xp*2))
 	repairedItem := foundItem.WithDurability(foundItem.Durability() + int(repairAmount))
 	if repairAmount >= 2 {
@@ -2161,7 +2766,7 @@ func (p *Player) Drop(s item.Stack) int {
 	e.SetVelocity(p.Rotation().Vec3().Mul(0.4))
 
 	ctx := event.C()
-	if p.Handler().HandleItemDrop(ctx, e); ctx.Cancelled() {
+	if p.h.HandleItemDrop(ctx, e); ctx.Cancelled() {
 		return 0
 	}
 	p.World().AddEntity(e)
@@ -2206,12 +2811,21 @@ func (p *Player) Latency() time.Duration {
 
 // Tick ticks the entity, performing actions such as checking if the player is still breaking a block.
 func (p *Player) Tick(w *world.World, current int64) {
+	if c := p.controller.Load(); c != nil && p.session() == session.Nop {
+		c.Tick(p, current)
+	}
+	p.tickTitles()
 	if p.Dead() {
 		return
 	}
 	if p.lastTickedWorld != w {
-		p.Handler().HandleChangeWorld(p.lastTickedWorld, w)
+		p.h.HandleChangeWorld(p.lastTickedWorld, w)
+	}
+	if seat, ok := p.Seat(); ok && seat.World() != w {
+		// The player either changed world or its seat was closed.
+		p.dismount()
 	}
+	p.tickSleep(w)
 	p.lastTickedWorld = w
 	if _, ok := w.Liquid(cube.PosFromVec3(p.Position())); !ok {
 		p.StopSwimming()
@@ -2230,10 +2844,21 @@ func (p *Player) Tick(w *world.World, current int64) {
 		}
 	}
 
+	p.tickMovementValidation()
 	p.checkBlockCollisions(p.vel.Load(), w)
 	p.onGround.Store(p.checkOnGround(w))
+	if p.breaking.Load() {
+		p.tickBreaking(p.breakingPos.Load())
+	}
+	p.tickPortal(w)
 
 	p.effects.Tick(p)
+	p.scheduler.Tick(p)
+	if blocking := p.Blocking(); p.blocking.Swap(blocking) != blocking {
+		// Whether the player is blocking also depends on its held items and the cooldown of shields, which change
+		// without the state of the player being updated.
+		p.updateState()
+	}
 
 	p.tickFood(w)
 	p.tickAirSupply(w)
@@ -2286,10 +2911,87 @@ func (p *Player) Tick(w *world.World, current int64) {
 	}
 }
 
+// EnterPortal is called every tick that the player is inside a portal leading to the Dimension passed. Once the
+// player has been inside the portal for long enough, it is transported to the destination world of the portal.
+func (p *Player) EnterPortal(world.Dimension) {
+	p.inPortal.Store(true)
+}
+
+// tickPortal ticks the time that the player has spent inside a nether portal, transporting it to the destination world
+// of the portal once it has been inside it for four seconds, or immediately if the player cannot take damage. A player
+// that has just been transported must leave the portal before it can travel again.
+func (p *Player) tickPortal(w *world.World) {
+	if !p.inPortal.CAS(true, false) {
+		p.portalTicks.Store(0)
+		p.awaitPortalExit.Store(false)
+		return
+	}
+	if p.awaitPortalExit.Load() {
+		return
+	}
+	if p.portalTicks.Inc() < 80 && p.GameMode().AllowsTakingDamage() {
+		return
+	}
+	p.portalTicks.Store(0)
+	p.awaitPortalExit.Store(true)
+	p.travelThroughPortal(w)
+}
+
+// portalSearchChunks is the radius in chunks around the destination of a nether portal in the Overworld that is
+// loaded and searched for a portal to link to.
+const portalSearchChunks = 4
+
+// travelThroughPortal transports the player through a nether portal to the destination world of the portal. The
+// position of the player is scaled between the Overworld and the Nether and the player is moved to the closest
+// portal in the destination world. A new portal is created if none could be found. Because searching for a portal
+// requires loading many chunks, the search is done on a separate goroutine and the player is transported once it
+// has finished. Nothing happens if the portal has no destination or if the player is already travelling.
+func (p *Player) travelThroughPortal(w *world.World) {
+	dest := w.PortalDestination(world.Nether)
+	if dest == w || !p.travelling.CAS(false, true) {
+		return
+	}
+	pos := p.Position()
+	if w.Dimension() == world.Nether {
+		pos = mgl64.Vec3{pos[0] * 8, pos[1], pos[2] * 8}
+	} else if dest.Dimension() == world.Nether {
+		pos = mgl64.Vec3{pos[0] / 8, pos[1], pos[2] / 8}
+	}
+	target, r := cube.PosFromVec3(pos), dest.Range()
+	target[1] = int(math.Max(float64(r[0]), math.Min(float64(target[1]), float64(r[1]))))
+
+	// Portals in the Nether are linked to portals within a smaller radius, as distances in the Nether are eight times
+	// shorter than in the Overworld. The radius searched is limited to the chunks loaded for the search.
+	radius := portalSearchChunks * 16
+	if dest.Dimension() == world.Nether {
+		radius = 16
+	}
+	axis := p.Rotation().Direction().Face().Axis().RotateLeft()
+	go func() {
+		defer p.travelling.Store(false)
+		dest.LoadChunks(target.Vec3Middle(), radius>>4)
+		portal, ok := block.FindNetherPortal(dest, target, radius)
+		found := true
+		if !ok {
+			portal, found = block.NetherPortalSite(dest, target, axis)
+		}
+		<-dest.Exec(func() {
+			if !ok {
+				block.BuildNetherPortal(dest, portal, axis, !found)
+			}
+		})
+		if p.World() != w {
+			// The player left the world while the portal was searched for, for example because it disconnected.
+			return
+		}
+		p.TeleportTo(dest, portal.Vec3Middle())
+	}()
+}
+
 // tickAirSupply tick's the player's air supply, consuming it when underwater, and replenishing it when out of water.
 func (p *Player) tickAirSupply(w *world.World) {
 	if !p.canBreathe(w) {
-		if r, ok := p.Armour().Helmet().Enchantment(enchantment.Respiration{}); ok && rand.Float64() <= (enchantment.Respiration{}).Chance(r.Level()) {
+		if r, ok := p.Armour().Helmet().Enchantment(enchantment.Respiration{}); ok && w.Rand().Float64() <= (enchantment.Respiration{}).Chance(r.Level()) {
 			// Respiration grants a chance to avoid drowning damage every tick.
 			return
 		}
@@ -2441,24 +3143,7 @@ func (p *Player) checkBlockCollisions(vel mgl64.Vec3, w *world.World) {
 
 	p.checkEntityInsiders(w, entityBBox)
 
-	grown := entityBBox.Extend(vel).Grow(0.25)
-	min, max := grown.Min(), grown.Max()
-	minX, minY, minZ := int(math.Floor(min[0])), int(math.Floor(min[1])), int(math.Floor(min[2]))
-	maxX, maxY, maxZ := int(math.Ceil(max[0])), int(math.Ceil(max[1])), int(math.Ceil(max[2]))
-
-	// A prediction of one BBox per block, plus an additional 2, in case
-	blocks := make([]cube.BBox, 0, (maxX-minX)*(maxY-minY)*(maxZ-minZ)+2)
-	for y := minY; y <= maxY; y++ {
-		for x := minX; x <= maxX; x++ {
-			for z := minZ; z <= maxZ; z++ {
-				pos := cube.Pos{x, y, z}
-				boxes := w.Block(pos).Model().BBox(pos, w)
-				for _, box := range boxes {
-					blocks = append(blocks, box.Translate(pos.Vec3()))
-				}
-			}
-		}
-	}
+	blocks := w.BlockBBoxes(entityBBox.Extend(vel).Grow(0.25))
 
 	// epsilon is the epsilon used for thresholds for change used for change in position and velocity.
 	const epsilon = 0.001
@@ -2517,22 +3202,18 @@ func (p *Player) checkEntityInsiders(w *world.World, entityBBox cube.BBox) {
 
 // checkOnGround checks if the player is currently considered to be on the ground.
 func (p *Player) checkOnGround(w *world.World) bool {
-	box := p.Type().BBox(p).Translate(p.Position())
+	return p.onGroundAt(w, p.Position())
+}
 
-	b := box.Grow(1)
+// onGroundAt checks if the player would be considered to be on the ground at the position passed.
+func (p *Player) onGroundAt(w *world.World, position mgl64.Vec3) bool {
+	box := p.Type().BBox(p).Translate(position)
 
-	min, max := cube.PosFromVec3(b.Min()), cube.PosFromVec3(b.Max())
-	for x := min[0]; x <= max[0]; x++ {
-		for z := min[2]; z <= max[2]; z++ {
-			for y := min[1]; y < max[1]; y++ {
-				pos := cube.Pos{x, y, z}
-				boxList := w.Block(pos).Model().BBox(pos, w)
-				for _, bb := range boxList {
-					if bb.GrowVec3(mgl64.Vec3{0, 0.05}).Translate(pos.Vec3()).IntersectsWith(box) {
-						return true
-					}
-				}
-			}
+	// Blocks in the layer above the grown box are not taken into account, as they are above the player.
+	min, max := box.Min(), box.Max()
+	for _, bb := range w.BlockBBoxes(cube.Box(min[0]-1, min[1]-1, min[2]-1, max[0]+1, max[1], max[2]+1)) {
+		if bb.GrowVec3(mgl64.Vec3{0, 0.05}).IntersectsWith(box) {
+			return true
 		}
 	}
 	return false
@@ -2573,6 +3254,12 @@ func (p *Player) PlaySound(sound world.Sound) {
 	p.session().PlaySound(sound)
 }
 
+// PlaySoundAt plays a sound at the position passed that only this Player can hear. Unlike World.PlaySound, it is
+// not broadcast to players around it.
+func (p *Player) PlaySoundAt(pos mgl64.Vec3, sound world.Sound) {
+	p.session().ViewSound(pos, sound)
+}
+
 // ShowParticle shows a particle that only this Player can see. Unlike World.AddParticle, it is not broadcast
 // to players around it.
 func (p *Player) ShowParticle(pos mgl64.Vec3, particle world.Particle) {
@@ -2600,19 +3287,20 @@ func (p *Player) EditSign(pos cube.Pos, frontText, backText string) error {
 		return nil
 	}
 
+	e := &SignEditEvent{Pos: pos, FrontSide: frontText != sign.Front.Text, OldText: sign.Back.Text, NewText: backText}
+	if e.FrontSide {
+		e.OldText, e.NewText = sign.Front.Text, frontText
+	}
 	ctx := event.C()
-	if frontText != sign.Front.Text {
-		if p.Handler().HandleSignEdit(ctx, true, sign.Front.Text, frontText); ctx.Cancelled() {
-			return nil
-		}
-		sign.Front.Text = frontText
-		sign.Front.Owner = p.XUID()
+	if p.h.HandleSignEdit(ctx, e); ctx.Cancelled() {
+		// Send the sign back to the player so that the text it typed is reverted.
+		w.ResendBlock(pos, p.session())
+		return nil
+	}
+	if e.FrontSide {
+		sign.Front.Text, sign.Front.Owner = e.NewText, p.XUID()
 	} else {
-		if p.Handler().HandleSignEdit(ctx, false, sign.Back.Text, backText); ctx.Cancelled() {
-			return nil
-		}
-		sign.Back.Text = backText
-		sign.Back.Owner = p.XUID()
+		sign.Back.Text, sign.Back.Owner = e.NewText, p.XUID()
 	}
 	w.SetBlock(pos, sign, nil)
 	return nil
@@ -2628,7 +3316,7 @@ func (p *Player) TurnLecternPage(pos cube.Pos, page int) error {
 	}
 
 	ctx := event.C()
-	if p.Handler().HandleLecternPageTurn(ctx, pos, lectern.Page, &page); ctx.Cancelled() {
+	if p.h.HandleLecternPageTurn(ctx, pos, lectern.Page, &page); ctx.Cancelled() {
 		return nil
 	}
 
@@ -2670,7 +3358,7 @@ func (p *Player) PunchAir() {
 		return
 	}
 	ctx := event.C()
-	if p.Handler().HandlePunchAir(ctx); ctx.Cancelled() {
+	if p.h.HandlePunchAir(ctx); ctx.Cancelled() {
 		return
 	}
 	p.SwingArm()
@@ -2685,14 +3373,17 @@ func (p *Player) damageItem(s item.Stack, d int) item.Stack {
 		return s
 	}
 	ctx := event.C()
-	if p.Handler().HandleItemDamage(ctx, s, d); ctx.Cancelled() {
+	if p.h.HandleItemDamage(ctx, s, d); ctx.Cancelled() {
 		return s
 	}
 	if e, ok := s.Enchantment(enchantment.Unbreaking{}); ok {
-		d = (enchantment.Unbreaking{}).Reduce(s.Item(), e.Level(), d)
+		d = (enchantment.Unbreaking{}).Reduce(s.Item(), e.Level(), d, p.World().Rand())
 	}
+	it := s.Item()
 	if s = s.Damage(d); s.Empty() {
-		p.World().PlaySound(p.Position(), sound.ItemBreak{})
+		w := p.World()
+		w.PlaySound(p.Position(), sound.ItemBreak{})
+		w.AddParticle(entity.EyePosition(p), particle.ItemBreak{Item: it})
 	}
 	return s
 }
@@ -2744,6 +3435,41 @@ func (p *Player) canReach(pos mgl64.Vec3) bool {
 	return eyes.Sub(pos).Len() <= survivalRange && !p.Dead()
 }
 
+// SpawnProtectionExemptPermission is the permission node that exempts a player from the spawn protection of a
+// world, so that it may build and interact with blocks near the spawn. See world.Config.SpawnProtection.
+const SpawnProtectionExemptPermission = "spawnprotection.exempt"
+
+// spawnProtected checks if the player is prevented from building at or interacting with the block at the position
+// passed, because it lies within the spawn protection of the world. Players are only exempted from spawn protection
+// if cmd.Permissions were set and the player has the SpawnProtectionExemptPermission node.
+func (p *Player) spawnProtected(w *world.World, pos cube.Pos) bool {
+	if !w.SpawnProtected(pos) {
+		return false
+	}
+	return !cmd.HasPermissions() || !cmd.Permitted(p, SpawnProtectionExemptPermission)
+}
+
+// ChangeDifficultyPermission is the permission node that a player must have to change the difficulty of its world
+// from the settings screen of its client. See Player.ChangeDifficulty.
+const ChangeDifficultyPermission = "difficulty.change"
+
+// ChangeDifficulty changes the difficulty of the world that the player is in, as requested by the client from its
+// settings screen. The difficulty is only changed if cmd.Permissions were set and the player has the
+// ChangeDifficultyPermission node, unless Handler.HandleChangeDifficulty uncancels the change. If the difficulty is
+// not changed, the current difficulty of the world is sent back to the client.
+func (p *Player) ChangeDifficulty(d world.Difficulty) {
+	w := p.World()
+	ctx := event.C()
+	if !cmd.HasPermissions() || !cmd.Permitted(p, ChangeDifficultyPermission) {
+		ctx.Cancel()
+	}
+	if p.h.HandleChangeDifficulty(ctx, &d); ctx.Cancelled() {
+		p.session().ViewDifficulty(w.Difficulty())
+		return
+	}
+	w.SetDifficulty(d)
+}
+
 // Disconnect closes the player and removes it from the world.
 // Disconnect, unlike Close, allows a custom message to be passed to show to the player when it is
 // disconnected. The message is formatted following the rules of fmt.Sprintln without a newline at the end.
@@ -2771,7 +3497,13 @@ func (p *Player) close(msg string) {
 	if p.Dead() && p.session() != nil {
 		p.Respawn()
 	}
-	p.h.Swap(NopHandler{}).HandleQuit()
+	p.dismount()
+	p.Wake()
+	p.h.HandleQuit()
+	p.h.Clear()
+	if c := p.controller.Load(); c != nil {
+		c.Close(p)
+	}
 
 	if s := p.s.Swap(nil); s != nil {
 		s.Disconnect(msg)
@@ -2789,7 +3521,7 @@ func (p *Player) load(data Data) {
 	p.yaw.Store(data.Yaw)
 	p.pitch.Store(data.Pitch)
 
-	p.health.SetMaxHealth(data.MaxHealth)
+	p.attributes.SetBase(attribute.MaxHealth, data.MaxHealth)
 	p.health.AddHealth(data.Health - p.Health())
 	p.session().SendHealth(p.health)
 
@@ -2820,6 +3552,13 @@ func (p *Player) load(data Data) {
 	for slot, stack := range data.EnderChestInventory {
 		_ = p.enderChest.SetItem(slot, stack)
 	}
+	p.metadata.DecodeNBT(data.Metadata)
+	if data.BedSpawn != nil {
+		p.SetBedSpawn(*data.BedSpawn)
+	}
+	p.locationMu.Lock()
+	p.lastDeath, p.lastTeleport = data.LastDeath, data.LastTeleport
+	p.locationMu.Unlock()
 }
 
 // loadInventory loads all the data associated with the player inventory.
@@ -2840,7 +3579,7 @@ func (p *Player) Data() Data {
 	p.hunger.mu.RLock()
 	defer p.hunger.mu.RUnlock()
 
-	return Data{
+	data := Data{
 		UUID:            p.UUID(),
 		Username:        p.Name(),
 		Position:        p.Position(),
@@ -2848,7 +3587,7 @@ func (p *Player) Data() Data {
 		Yaw:             yaw,
 		Pitch:           pitch,
 		Health:          p.Health(),
-		MaxHealth:       p.MaxHealth(),
+		MaxHealth:       p.attributes.Base(attribute.MaxHealth),
 		Hunger:          p.hunger.foodLevel,
 		Experience:      p.Experience(),
 		EnchantmentSeed: p.EnchantmentSeed(),
@@ -2873,7 +3612,18 @@ func (p *Player) Data() Data {
 		FireTicks:           p.fireTicks.Load(),
 		FallDistance:        p.fallDistance.Load(),
 		World:               p.World(),
+		Metadata:            p.metadata.EncodeNBT(),
 	}
+	if pos, ok := p.BedSpawn(); ok {
+		data.BedSpawn = &pos
+	}
+	if loc, ok := p.LastDeathPos(); ok {
+		data.LastDeath = &loc
+	}
+	if loc, ok := p.LastTeleportPos(); ok {
+		data.LastTeleport = &loc
+	}
+	return data
 }
 
 // session returns the network session of the player. If it has one, it is returned. If not, a no-op session
@@ -2897,7 +3647,11 @@ func (p *Player) useContext() *item.UseContext {
 		}
 		return nil
 	}
+	held, _ := p.HeldItems()
 	return &item.UseContext{
+		User:  p,
+		World: p.World(),
+		Item:  held,
 		SwapHeldWithArmour: func(i int) {
 			src, dst, srcInv, dstInv := int(p.heldSlot.Load()), i, p.inv, p.armour.Inventory()
 			srcIt, _ := srcInv.Item(src)
@@ -2925,9 +3679,22 @@ func (p *Player) useContext() *item.UseContext {
 	}
 }
 
-// Handler returns the Handler of the player.
+// Handler returns the Handler of the player set using Handle. NopHandler is returned if no Handler was set. Handlers
+// added using Subscribe are not returned.
 func (p *Player) Handler() Handler {
-	return p.h.Load()
+	p.hMu.Lock()
+	defer p.hMu.Unlock()
+	if p.handler == nil {
+		return NopHandler{}
+	}
+	return p.handler
+}
+
+// Control sets the Controller of the player. The Controller is ticked every tick and may be used to control a player
+// that has no session, such as a fake player. Control has no effect on players that have a session. Passing nil
+// removes the current Controller.
+func (p *Player) Control(c Controller) {
+	p.controller.Store(c)
 }
 
 // broadcastItems broadcasts the items held to viewers.
@@ -2959,26 +3726,27 @@ func (p *Player) viewers() []world.Viewer {
 	return viewers
 }
 
-// resendBlocks resends blocks in a world.World at the cube.Pos passed and the block next to it at the cube.Face passed.
+// activates checks if the player activates an Activatable block when clicking it while holding the item passed.
+// Like in vanilla, a sneaking player holding an item will not activate blocks such as chests, furnaces and
+// crafting tables, so that blocks may be placed against them and items may be used on them instead. A player
+// not holding any item always activates the block clicked.
+func (p *Player) activates(held item.Stack) bool {
+	return !p.Sneaking() || held.Empty()
+}
+
+// resendBlocks resends blocks in a world.World at the cube.Pos passed and the blocks next to it at the cube.Faces
+// passed, along with the items held by the player. It is called when an action of the player was rejected, so that
+// the client does not keep the changes that it predicted.
 func (p *Player) resendBlocks(pos cube.Pos, w *world.World, faces ...cube.Face) {
-	if p.session() == session.Nop {
+	s := p.session()
+	if s == session.Nop {
 		return
 	}
-	p.resendBlock(pos, w)
+	w.ResendBlock(pos, s)
 	for _, f := range faces {
-		p.resendBlock(pos.Side(f), w)
-	}
-}
-
-// resendBlock resends the block at a cube.Pos in the world.World passed.
-func (p *Player) resendBlock(pos cube.Pos, w *world.World) {
-	b := w.Block(pos)
-	p.session().ViewBlockUpdate(pos, b, 0)
-	if _, ok := b.(world.Liquid); !ok {
-		if liq, ok := w.Liquid(pos); ok {
-			p.session().ViewBlockUpdate(pos, liq, 1)
-		}
+		w.ResendBlock(pos.Side(f), s)
 	}
+	s.ResendHeldItems()
 }
 
 // format is a utility function to format a list of values to have spaces between them, but no newline at the
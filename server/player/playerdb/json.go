@@ -1,11 +1,13 @@
 package playerdb
 
 import (
+	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/player"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
 	"time"
 )
 
@@ -37,6 +39,10 @@ func (p *Provider) fromJson(d jsonData, lookupWorld func(world.Dimension) *world
 		Inventory:           dataToInv(d.Inventory),
 		EnderChestInventory: make([]item.Stack, 27),
 		World:               lookupWorld(dim),
+		BedSpawn:            d.BedSpawn,
+		LastDeath:           dataToLocation(d.LastDeath),
+		LastTeleport:        dataToLocation(d.LastTeleport),
+		Metadata:            decodeMetadata(d.Metadata),
 	}
 	decodeItems(d.EnderChestInventory, data.EnderChestInventory)
 	return data
@@ -70,9 +76,55 @@ func (p *Provider) toJson(d player.Data) jsonData {
 		Inventory:           invToData(d.Inventory),
 		EnderChestInventory: encodeItems(d.EnderChestInventory),
 		Dimension:           uint8(dim),
+		BedSpawn:            d.BedSpawn,
+		LastDeath:           locationToData(d.LastDeath),
+		LastTeleport:        locationToData(d.LastTeleport),
+		Metadata:            encodeMetadata(d.Metadata),
 	}
 }
 
+// locationToData converts a player.Location to its JSON representation. Nil is returned if loc is nil.
+func locationToData(loc *player.Location) *jsonLocation {
+	if loc == nil {
+		return nil
+	}
+	dim, _ := world.DimensionID(loc.Dimension)
+	return &jsonLocation{Position: loc.Position, Yaw: loc.Rotation.Yaw(), Pitch: loc.Rotation.Pitch(), Dimension: uint8(dim)}
+}
+
+// dataToLocation converts a location encoded using locationToData back to a player.Location.
+func dataToLocation(data *jsonLocation) *player.Location {
+	if data == nil {
+		return nil
+	}
+	dim, ok := world.DimensionByID(int(data.Dimension))
+	if !ok {
+		return nil
+	}
+	return &player.Location{Position: data.Position, Rotation: cube.Rotation{data.Yaw, data.Pitch}, Dimension: dim}
+}
+
+// encodeMetadata encodes the persistent metadata of a player to NBT. Nil is returned if the player has no metadata.
+func encodeMetadata(m map[string]any) []byte {
+	if len(m) == 0 {
+		return nil
+	}
+	b, err := nbt.MarshalEncoding(m, nbt.LittleEndian)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// decodeMetadata decodes the persistent metadata of a player encoded using encodeMetadata.
+func decodeMetadata(b []byte) map[string]any {
+	var m map[string]any
+	if len(b) == 0 || nbt.UnmarshalEncoding(b, &m, nbt.LittleEndian) != nil {
+		return nil
+	}
+	return m
+}
+
 type jsonData struct {
 	UUID                             string
 	Username                         string
@@ -93,6 +145,15 @@ type jsonData struct {
 	FireTicks                        int64
 	FallDistance                     float64
 	Dimension                        uint8
+	BedSpawn                         *cube.Pos
+	LastDeath, LastTeleport          *jsonLocation
+	Metadata                         []byte
+}
+
+type jsonLocation struct {
+	Position   mgl64.Vec3
+	Yaw, Pitch float64
+	Dimension  uint8
 }
 
 type jsonInventoryData struct {
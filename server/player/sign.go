@@ -0,0 +1,19 @@
+package player
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+)
+
+// SignEditEvent is an edit of the text on one side of a sign by a Player. It is passed to Handler.HandleSignEdit,
+// which may change the text written to the sign, for example to filter it.
+type SignEditEvent struct {
+	// Pos is the position of the sign edited.
+	Pos cube.Pos
+	// FrontSide specifies if the front side of the sign was edited. If false, the back side was edited.
+	FrontSide bool
+	// OldText is the text on the side of the sign before the edit.
+	OldText string
+	// NewText is the text on the side of the sign after the edit. It may be changed to change the text written to
+	// the sign.
+	NewText string
+}
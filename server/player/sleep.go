@@ -0,0 +1,126 @@
+package player
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// Sleep makes the player sleep in the bed with its head at the position passed. Sleep does nothing if the player is
+// already sleeping or if there is no bed at the position. The player is woken up by calling Wake, when it is hurt
+// or when the night is skipped because all players in the world are sleeping.
+func (p *Player) Sleep(pos cube.Pos) {
+	w := p.World()
+	b, ok := w.Block(pos).(block.Bed)
+	if !ok || !b.Head || p.Dead() {
+		return
+	}
+	p.sleepMu.Lock()
+	if p.sleepPos != nil {
+		p.sleepMu.Unlock()
+		return
+	}
+	p.sleepPos = &pos
+	p.sleepMu.Unlock()
+
+	p.StopSprinting()
+	p.StopSneaking()
+	b.Occupied = true
+	w.SetBlock(pos, b, nil)
+	p.updateState()
+}
+
+// Sleeping returns the position of the head of the bed that the player is sleeping in and true if the player is
+// currently sleeping.
+func (p *Player) Sleeping() (cube.Pos, bool) {
+	p.sleepMu.Lock()
+	defer p.sleepMu.Unlock()
+	if p.sleepPos == nil {
+		return cube.Pos{}, false
+	}
+	return *p.sleepPos, true
+}
+
+// Wake wakes the player up if it is sleeping, making it leave its bed.
+func (p *Player) Wake() {
+	p.sleepMu.Lock()
+	pos := p.sleepPos
+	p.sleepPos = nil
+	p.sleepMu.Unlock()
+	if pos == nil {
+		return
+	}
+
+	w := p.World()
+	if b, ok := w.Block(*pos).(block.Bed); ok && b.Occupied {
+		b.Occupied = false
+		w.SetBlock(*pos, b, nil)
+	}
+	for _, v := range p.viewers() {
+		v.ViewEntityAction(p, entity.WakeUpAction{})
+	}
+	p.updateState()
+}
+
+// tickSleep wakes the player up if the bed that it is sleeping in was removed.
+func (p *Player) tickSleep(w *world.World) {
+	if pos, ok := p.Sleeping(); ok {
+		if _, ok := w.Block(pos).(block.Bed); !ok {
+			p.Wake()
+		}
+	}
+}
+
+// SetBedSpawn sets the position of the head of the bed that the player respawns at. When the player respawns and the
+// bed is missing or obstructed, the player respawns at the spawn of the world instead.
+func (p *Player) SetBedSpawn(pos cube.Pos) {
+	p.sleepMu.Lock()
+	defer p.sleepMu.Unlock()
+	p.bedSpawn = &pos
+}
+
+// BedSpawn returns the position of the head of the bed that the player respawns at and true, if the bed spawn of
+// the player was set.
+func (p *Player) BedSpawn() (cube.Pos, bool) {
+	p.sleepMu.Lock()
+	defer p.sleepMu.Unlock()
+	if p.bedSpawn == nil {
+		return cube.Pos{}, false
+	}
+	return *p.bedSpawn, true
+}
+
+// ResetBedSpawn resets the bed spawn of the player, so that it respawns at the spawn of the world.
+func (p *Player) ResetBedSpawn() {
+	p.sleepMu.Lock()
+	defer p.sleepMu.Unlock()
+	p.bedSpawn = nil
+}
+
+// bedRespawnPosition returns the position that a player with its bed spawn at the position passed respawns at in
+// the world passed. False is returned if the bed is missing or if there is no room to respawn next to it.
+func bedRespawnPosition(w *world.World, pos cube.Pos) (mgl64.Vec3, bool) {
+	b, ok := w.Block(pos).(block.Bed)
+	if !ok || !b.Head {
+		return mgl64.Vec3{}, false
+	}
+	free := func(pos cube.Pos) bool {
+		return len(w.Block(pos).Model().BBox(pos, w)) == 0
+	}
+	for _, part := range []cube.Pos{pos.Side(b.Facing.Opposite().Face()), pos} {
+		for _, y := range []int{0, 1, -1} {
+			for x := -1; x <= 1; x++ {
+				for z := -1; z <= 1; z++ {
+					candidate, below := part.Add(cube.Pos{x, y, z}), part.Add(cube.Pos{x, y - 1, z})
+					if !free(candidate) || !free(candidate.Side(cube.FaceUp)) || !w.Block(below).Model().FaceSolid(below, cube.FaceUp, w) {
+						continue
+					}
+					return candidate.Vec3Middle(), true
+				}
+			}
+		}
+	}
+	return mgl64.Vec3{}, false
+}
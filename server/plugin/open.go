@@ -0,0 +1,29 @@
+//go:build (linux || darwin || freebsd) && cgo
+
+package plugin
+
+import (
+	"fmt"
+	goplugin "plugin"
+)
+
+// open opens the Go plugin at the path passed and calls its New function to obtain the Plugin it holds.
+func open(path string) (Plugin, error) {
+	pl, err := goplugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	sym, err := pl.Lookup("New")
+	if err != nil {
+		return nil, err
+	}
+	f, ok := sym.(func() Plugin)
+	if !ok {
+		return nil, fmt.Errorf("symbol New has type %T, expected func() plugin.Plugin", sym)
+	}
+	p := f()
+	if p == nil {
+		return nil, fmt.Errorf("New returned a nil plugin")
+	}
+	return p, nil
+}
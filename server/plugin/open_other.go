@@ -0,0 +1,13 @@
+//go:build !((linux || darwin || freebsd) && cgo)
+
+package plugin
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// open returns an error: Go plugins are only supported on Linux, macOS and FreeBSD with cgo enabled.
+func open(string) (Plugin, error) {
+	return nil, fmt.Errorf("go plugins are not supported on %v/%v without cgo", runtime.GOOS, runtime.GOARCH)
+}
@@ -0,0 +1,223 @@
+// Package plugin implements loading of plugins that extend a server. Plugins may be compiled into the server and
+// registered with a Manager directly, or compiled separately as Go plugins (using `go build -buildmode=plugin`) and
+// discovered in a folder when the server starts. Panics in the Enable and Disable methods of a plugin are recovered,
+// so that a single faulty plugin does not bring down the entire server.
+package plugin
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server"
+	"github.com/sirupsen/logrus"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Plugin is a plugin that extends a server. Go plugins must export a function with the signature
+// `func New() plugin.Plugin` that returns the Plugin to load.
+type Plugin interface {
+	// Name returns the name of the Plugin. The name of each Plugin loaded by a Manager must be unique.
+	Name() string
+	// Enable is called when the Plugin is enabled, after the server was created. The server passed may be used to
+	// access worlds and players and to subscribe handlers. If an error is returned, the Plugin is not enabled.
+	Enable(srv *server.Server) error
+	// Disable is called when the Plugin is disabled, generally when the server is shutting down. The Plugin should
+	// remove any handlers it subscribed and stop any goroutines it started.
+	Disable() error
+}
+
+// Config holds the settings of a Manager. Calling Config.New() creates a Manager.
+type Config struct {
+	// Log is the Logger used to report plugins being enabled and disabled and errors that occur while doing so.
+	Log server.Logger
+	// Folder is the folder that Load discovers Go plugins in. Every file with the .so extension is loaded. If empty,
+	// Load does not discover any plugins.
+	Folder string
+}
+
+// New creates a Manager for the server passed using the settings in the Config.
+func (conf Config) New(srv *server.Server) *Manager {
+	if conf.Log == nil {
+		conf.Log = logrus.New()
+	}
+	return &Manager{conf: conf, srv: srv}
+}
+
+// Manager manages the plugins of a server, enabling and disabling them and recovering any panics that occur in the
+// process. A Manager is safe for concurrent use.
+type Manager struct {
+	conf Config
+	srv  *server.Server
+
+	// stateMu serialises calls to Enable and Disable. mu is not held while plugins are enabled or disabled, so that
+	// plugins may look up other plugins in their Enable and Disable methods.
+	stateMu sync.Mutex
+	mu      sync.Mutex
+	plugins []*entry
+}
+
+// entry is a Plugin registered with a Manager.
+type entry struct {
+	p       Plugin
+	enabled bool
+}
+
+// Register registers a Plugin with the Manager, so that it is enabled the next time Enable is called. Register may
+// be used for plugins that are compiled into the server. An error is returned if a Plugin with the same name was
+// already registered.
+func (m *Manager) Register(p Plugin) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.plugins {
+		if strings.EqualFold(e.p.Name(), p.Name()) {
+			return fmt.Errorf("register plugin %v: plugin with the same name already registered", p.Name())
+		}
+	}
+	m.plugins = append(m.plugins, &entry{p: p})
+	return nil
+}
+
+// Load discovers all Go plugins in the folder set in the Config and registers them with the Manager. The folder is
+// created if it does not yet exist. Plugins that could not be loaded are logged and skipped. An error is only
+// returned if the folder could not be read.
+func (m *Manager) Load() error {
+	if m.conf.Folder == "" {
+		return nil
+	}
+	if err := os.MkdirAll(m.conf.Folder, 0777); err != nil {
+		return fmt.Errorf("load plugins: create folder: %w", err)
+	}
+	files, err := os.ReadDir(m.conf.Folder)
+	if err != nil {
+		return fmt.Errorf("load plugins: read folder: %w", err)
+	}
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".so" {
+			continue
+		}
+		path := filepath.Join(m.conf.Folder, f.Name())
+		var (
+			p   Plugin
+			err error
+		)
+		if m.srv.CrashReporter().Catch("plugin", func() map[string]any { return map[string]any{"file": path} }, func() {
+			p, err = open(path)
+		}) {
+			continue
+		}
+		if err == nil {
+			err = m.Register(p)
+		}
+		if err != nil {
+			m.conf.Log.Errorf("load plugin %v: %v", f.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Enable enables all plugins registered that are not yet enabled, in the order in which they were registered. Plugins
+// that return an error or panic while being enabled are logged and remain disabled.
+func (m *Manager) Enable() {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	for _, e := range m.entries() {
+		if m.enabled(e) {
+			continue
+		}
+		var err error
+		if m.catch(e.p, func() { err = e.p.Enable(m.srv) }) {
+			continue
+		}
+		if err != nil {
+			m.conf.Log.Errorf("enable plugin %v: %v", e.p.Name(), err)
+			continue
+		}
+		m.setEnabled(e, true)
+		m.conf.Log.Infof("Enabled plugin %v.", e.p.Name())
+	}
+}
+
+// Disable disables all plugins that are enabled, in the reverse order in which they were enabled. Plugins that return
+// an error or panic while being disabled are logged, but are considered disabled regardless.
+func (m *Manager) Disable() {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	entries := m.entries()
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if !m.enabled(e) {
+			continue
+		}
+		m.setEnabled(e, false)
+
+		var err error
+		if m.catch(e.p, func() { err = e.p.Disable() }) {
+			continue
+		}
+		if err != nil {
+			m.conf.Log.Errorf("disable plugin %v: %v", e.p.Name(), err)
+			continue
+		}
+		m.conf.Log.Infof("Disabled plugin %v.", e.p.Name())
+	}
+}
+
+// Plugins returns all plugins that are currently enabled, sorted by name.
+func (m *Manager) Plugins() []Plugin {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	plugins := make([]Plugin, 0, len(m.plugins))
+	for _, e := range m.plugins {
+		if e.enabled {
+			plugins = append(plugins, e.p)
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name() < plugins[j].Name()
+	})
+	return plugins
+}
+
+// Plugin looks up an enabled Plugin by its name, case-insensitively. If found, the Plugin is returned and the bool
+// returned is true.
+func (m *Manager) Plugin(name string) (Plugin, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.plugins {
+		if e.enabled && strings.EqualFold(e.p.Name(), name) {
+			return e.p, true
+		}
+	}
+	return nil, false
+}
+
+// entries returns a copy of the list of plugins registered.
+func (m *Manager) entries() []*entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*entry(nil), m.plugins...)
+}
+
+// enabled checks if the plugin of the entry passed is enabled.
+func (m *Manager) enabled(e *entry) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return e.enabled
+}
+
+// setEnabled changes the enabled state of the plugin of the entry passed.
+func (m *Manager) setEnabled(e *entry, enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e.enabled = enabled
+}
+
+// catch calls f, recovering any panic that occurs using the crash.Reporter of the server. If a panic was recovered,
+// catch returns true.
+func (m *Manager) catch(p Plugin, f func()) bool {
+	return m.srv.CrashReporter().Catch("plugin", func() map[string]any {
+		return map[string]any{"plugin": p.Name()}
+	}, f)
+}
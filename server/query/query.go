@@ -0,0 +1,252 @@
+// Package query implements the UT3 (GameSpy 4) query protocol, which server list sites and launchers use to fetch
+// the MOTD, player count, player names, version and plugins of a server over UDP. The information advertised is
+// the server.Status of the server, which may be changed using server.Server.SetStatusFunc.
+package query
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Logger is used to log errors that occur while answering queries.
+type Logger interface {
+	Errorf(format string, a ...any)
+}
+
+// Config holds the settings of a Listener. Calling Config.New() creates a Listener and starts answering queries.
+type Config struct {
+	// Server is the Server whose Status is advertised. Server must not be nil.
+	Server *server.Server
+	// Address is the UDP address that the Listener listens on for queries. If left empty, Address is set to
+	// ':19133'.
+	Address string
+	// HostIP and HostPort are the IP and port that players connect to, which are advertised in full queries. If
+	// HostPort is left as 0, HostPort is set to 19132. If HostIP is left empty, it is set to '0.0.0.0'.
+	HostIP   string
+	HostPort int
+	// Log is the Logger used to log errors. If nil, errors are not logged.
+	Log Logger
+}
+
+const (
+	// typeHandshake is the type of packets requesting a challenge token.
+	typeHandshake = 0x09
+	// typeStat is the type of packets requesting the basic or full status of the server.
+	typeStat = 0x00
+	// tokenInterval is the interval at which the secret that challenge tokens are derived from is changed.
+	tokenInterval = time.Second * 30
+)
+
+// magic is the prefix of every packet sent by clients.
+var magic = []byte{0xfe, 0xfd}
+
+// Listener answers queries sent to a UDP address.
+type Listener struct {
+	conf Config
+	conn net.PacketConn
+
+	mu sync.Mutex
+	// secret and previous are the current and previous secrets that challenge tokens are derived from. A token is
+	// only valid for the address it was sent to, so that the Listener cannot be used to reflect large responses to
+	// spoofed addresses. Clients must send a token derived from one of the secrets with stat requests.
+	secret, previous [32]byte
+	changed          time.Time
+
+	done chan struct{}
+}
+
+// New creates a Listener using the settings in the Config and starts answering queries. Close must be called to stop
+// the Listener.
+func (conf Config) New() (*Listener, error) {
+	if conf.Server == nil {
+		return nil, errors.New("new query listener: server must not be nil")
+	}
+	if conf.Address == "" {
+		conf.Address = ":19133"
+	}
+	if conf.HostIP == "" {
+		conf.HostIP = "0.0.0.0"
+	}
+	if conf.HostPort == 0 {
+		conf.HostPort = 19132
+	}
+	conn, err := net.ListenPacket("udp", conf.Address)
+	if err != nil {
+		return nil, fmt.Errorf("new query listener: %w", err)
+	}
+	l := &Listener{conf: conf, conn: conn, done: make(chan struct{})}
+	l.secret, l.changed = newSecret(), time.Now()
+	go l.run()
+	return l, nil
+}
+
+// Addr returns the address that the Listener listens on.
+func (l *Listener) Addr() net.Addr {
+	return l.conn.LocalAddr()
+}
+
+// Close stops answering queries.
+func (l *Listener) Close() error {
+	err := l.conn.Close()
+	<-l.done
+	return err
+}
+
+// run reads and answers queries until the Listener is closed.
+func (l *Listener) run() {
+	defer close(l.done)
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := l.conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			l.errorf("query: %v", err)
+			continue
+		}
+		if resp := l.handle(buf[:n], addr); resp != nil {
+			if _, err := l.conn.WriteTo(resp, addr); err != nil && !errors.Is(err, net.ErrClosed) {
+				l.errorf("query: %v", err)
+			}
+		}
+	}
+}
+
+// handle handles a single packet sent by addr and returns the response to send, or nil if the packet is invalid.
+func (l *Listener) handle(data []byte, addr net.Addr) []byte {
+	if len(data) < 7 || !bytes.HasPrefix(data, magic) {
+		return nil
+	}
+	typ, session := data[2], data[3:7]
+	resp := bytes.NewBuffer(nil)
+	resp.WriteByte(typ)
+	resp.Write(session)
+
+	switch typ {
+	case typeHandshake:
+		resp.WriteString(strconv.Itoa(int(l.token(addr))))
+		resp.WriteByte(0)
+	case typeStat:
+		if len(data) < 11 || !l.validToken(addr, int32(binary.BigEndian.Uint32(data[7:11]))) {
+			return nil
+		}
+		s := l.conf.Server.Status()
+		if len(data) >= 15 {
+			// A full stat request is padded with four extra bytes.
+			l.writeFull(resp, s)
+		} else {
+			l.writeBasic(resp, s)
+		}
+	default:
+		return nil
+	}
+	return resp.Bytes()
+}
+
+// writeBasic writes the response to a basic stat request to buf.
+func (l *Listener) writeBasic(buf *bytes.Buffer, s server.Status) {
+	for _, v := range []string{s.MOTD, s.GameType, s.Map, strconv.Itoa(s.PlayerCount), strconv.Itoa(s.MaxPlayers)} {
+		writeString(buf, v)
+	}
+	_ = binary.Write(buf, binary.LittleEndian, uint16(l.conf.HostPort))
+	writeString(buf, l.conf.HostIP)
+}
+
+// writeFull writes the response to a full stat request to buf.
+func (l *Listener) writeFull(buf *bytes.Buffer, s server.Status) {
+	buf.WriteString("splitnum\x00\x80\x00")
+	plugins := "Dragonfly " + s.Version
+	if len(s.Plugins) > 0 {
+		plugins += ": " + strings.Join(s.Plugins, "; ")
+	}
+	for _, kv := range [][2]string{
+		{"hostname", s.MOTD},
+		{"gametype", s.GameType},
+		{"game_id", "MINECRAFTPE"},
+		{"version", s.Version},
+		{"server_engine", "Dragonfly"},
+		{"plugins", plugins},
+		{"map", s.Map},
+		{"numplayers", strconv.Itoa(s.PlayerCount)},
+		{"maxplayers", strconv.Itoa(s.MaxPlayers)},
+		{"whitelist", onOff(s.Whitelist)},
+		{"hostip", l.conf.HostIP},
+		{"hostport", strconv.Itoa(l.conf.HostPort)},
+	} {
+		writeString(buf, kv[0])
+		writeString(buf, kv[1])
+	}
+	buf.WriteString("\x00\x01player_\x00\x00")
+	for _, name := range s.Players {
+		writeString(buf, name)
+	}
+	buf.WriteByte(0)
+}
+
+// token returns the challenge token for the address passed, derived from the current secret. The secret is changed
+// if it is older than tokenInterval.
+func (l *Listener) token(addr net.Addr) int32 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if time.Since(l.changed) >= tokenInterval {
+		l.previous, l.secret, l.changed = l.secret, newSecret(), time.Now()
+	}
+	return deriveToken(l.secret, addr)
+}
+
+// validToken checks if the token passed was derived for the address passed from the current or previous secret.
+func (l *Listener) validToken(addr net.Addr, token int32) bool {
+	if token == l.token(addr) {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return token == deriveToken(l.previous, addr)
+}
+
+// errorf logs an error using the Logger of the Listener, if set.
+func (l *Listener) errorf(format string, a ...any) {
+	if l.conf.Log != nil {
+		l.conf.Log.Errorf(format, a...)
+	}
+}
+
+// newSecret returns a new random secret to derive challenge tokens from.
+func newSecret() (secret [32]byte) {
+	_, _ = rand.Read(secret[:])
+	return secret
+}
+
+// deriveToken derives the challenge token for the address passed from a secret, using an HMAC of the address. Tokens
+// are kept below 10 million, like in vanilla, so that clients that parse it into a small integer do not fail.
+func deriveToken(secret [32]byte, addr net.Addr) int32 {
+	h := hmac.New(sha256.New, secret[:])
+	h.Write([]byte(addr.String()))
+	return int32(binary.BigEndian.Uint32(h.Sum(nil)) % 10_000_000)
+}
+
+// onOff returns 'on' if b is true and 'off' if it is false.
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// writeString writes a null-terminated string to buf.
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteString(s)
+	buf.WriteByte(0)
+}
@@ -0,0 +1,249 @@
+package server
+
+import (
+	"context"
+	"github.com/df-mc/atomic"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/df-mc/dragonfly/server/text"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"golang.org/x/exp/slices"
+	"io"
+	"sync"
+	"time"
+)
+
+// QueueHandler handles events related to the join queue of a Server. Players are placed in the join queue if they
+// join while the Server is full and Config.JoinQueueSize is not 0.
+type QueueHandler interface {
+	// HandleQueueJoin handles a player joining the join queue at a position, starting at 1. ctx.Cancel() may be
+	// called to disconnect the player instead.
+	HandleQueueJoin(ctx *event.Context, d login.IdentityData, position int)
+	// HandleQueueLeave handles a player leaving the join queue. admitted is true if the player left the queue
+	// because it was allowed to join the Server and false if it disconnected while waiting.
+	HandleQueueLeave(d login.IdentityData, admitted bool)
+}
+
+// Compile time check to make sure NopQueueHandler implements QueueHandler.
+var _ QueueHandler = NopQueueHandler{}
+
+// NopQueueHandler implements the QueueHandler interface but does not execute any code when an event is called. The
+// default QueueHandler of a Server is set to NopQueueHandler.
+type NopQueueHandler struct{}
+
+func (NopQueueHandler) HandleQueueJoin(*event.Context, login.IdentityData, int) {}
+func (NopQueueHandler) HandleQueueLeave(login.IdentityData, bool)               {}
+
+// joinQueue keeps track of the amount of players online and holds the players that are waiting for a slot to
+// become available.
+type joinQueue struct {
+	max, size int
+
+	mu      sync.Mutex
+	online  int
+	entries []*queueEntry
+}
+
+// queueEntry is a player waiting in a joinQueue. admit is closed once the player is admitted to the server.
+type queueEntry struct {
+	admit chan struct{}
+}
+
+// join attempts to take a slot in the joinQueue. If a slot is free, or if bypass is true, nil is returned and the
+// player may join right away. If no slot is free, the player is added to the queue and a queueEntry is returned.
+// If the queue itself is also full, join returns false.
+func (q *joinQueue) join(bypass bool) (*queueEntry, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if bypass || (len(q.entries) == 0 && q.online < q.max) {
+		q.online++
+		return nil, true
+	}
+	if len(q.entries) >= q.size {
+		return nil, false
+	}
+	e := &queueEntry{admit: make(chan struct{})}
+	q.entries = append(q.entries, e)
+	return e, true
+}
+
+// position returns the position of the queueEntry in the queue, starting at 1. If the entry is no longer in the
+// queue, 0 is returned.
+func (q *joinQueue) position(e *queueEntry) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Index(q.entries, e) + 1
+}
+
+// leave removes a queueEntry from the queue. If the entry was already admitted, the slot it took is released.
+func (q *joinQueue) leave(e *queueEntry) {
+	q.mu.Lock()
+	if i := slices.Index(q.entries, e); i != -1 {
+		q.entries = slices.Delete(q.entries, i, i+1)
+		q.mu.Unlock()
+		return
+	}
+	q.mu.Unlock()
+	q.release()
+}
+
+// queueSlot is a slot in a joinQueue held by the connection of a single player. It is released at most once, no
+// matter how often release is called.
+type queueSlot struct {
+	q    *joinQueue
+	once sync.Once
+}
+
+// release releases the slot in the joinQueue. Calling release on a nil *queueSlot is a no-op.
+func (s *queueSlot) release() {
+	if s != nil {
+		s.once.Do(s.q.release)
+	}
+}
+
+// release releases a slot taken by a player and admits players from the queue for as long as slots are free.
+func (q *joinQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.online--
+	for q.online < q.max && len(q.entries) > 0 {
+		e := q.entries[0]
+		q.entries = q.entries[1:]
+		q.online++
+		close(e.admit)
+	}
+}
+
+// awaitQueue makes the session.Conn passed wait in the join queue of the Server until a slot becomes available. The
+// session.Conn returned should be used for the player from then on, and the queueSlot returned must be released
+// once the player leaves. If the connection was closed or disconnected while waiting, awaitQueue returns false and
+// no slot is held.
+func (srv *Server) awaitQueue(ctx context.Context, conn session.Conn, l Listener) (session.Conn, *queueSlot, bool) {
+	d := conn.IdentityData()
+	bypass := srv.conf.QueueBypass != nil && srv.conf.QueueBypass(d)
+	e, ok := srv.queue.join(bypass)
+	if !ok {
+		_ = l.Disconnect(conn, "Server is full.")
+		return nil, nil, false
+	} else if e == nil {
+		return conn, &queueSlot{q: srv.queue}, true
+	}
+
+	evCtx := event.C()
+	if srv.conf.QueueHandler.HandleQueueJoin(evCtx, d, srv.queue.position(e)); evCtx.Cancelled() {
+		srv.queue.leave(e)
+		_ = l.Disconnect(conn, "Server is full.")
+		return nil, nil, false
+	}
+	qc := newQueuedConn(conn)
+	t := time.NewTicker(time.Second * 2)
+	defer t.Stop()
+	for {
+		if pos := srv.queue.position(e); pos != 0 {
+			_ = qc.WritePacket(&packet.SetTitle{
+				ActionType: packet.TitleActionSetActionBar,
+				Text:       text.Colourf("<yellow>The server is full. Position in queue: %v</yellow>", pos),
+			})
+		}
+		select {
+		case <-e.admit:
+			qc.discard.Store(false)
+			srv.conf.QueueHandler.HandleQueueLeave(d, true)
+			return qc.admitted(), &queueSlot{q: srv.queue}, true
+		case <-qc.closed:
+			srv.queue.leave(e)
+			srv.conf.QueueHandler.HandleQueueLeave(d, false)
+			return nil, nil, false
+		case <-ctx.Done():
+			srv.queue.leave(e)
+			srv.conf.QueueHandler.HandleQueueLeave(d, false)
+			_ = l.Disconnect(conn, srv.conf.ShutdownMessage)
+			return nil, nil, false
+		case <-t.C:
+		}
+	}
+}
+
+// queuedConn wraps around a session.Conn of a player in the join queue. It continuously reads packets from the
+// connection so that incoming packets don't pile up while the player is waiting. Packets read while the player
+// is in the queue are discarded.
+type queuedConn struct {
+	session.Conn
+
+	discard atomic.Bool
+	packets chan packet.Packet
+	closed  chan struct{}
+	err     error
+
+	once sync.Once
+	done chan struct{}
+}
+
+// newQueuedConn creates a queuedConn wrapping around the session.Conn passed and starts reading packets from it.
+func newQueuedConn(conn session.Conn) *queuedConn {
+	c := &queuedConn{Conn: conn, packets: make(chan packet.Packet, 16), closed: make(chan struct{}), done: make(chan struct{})}
+	c.discard.Store(true)
+	go c.read()
+	return c
+}
+
+// read continuously reads packets from the underlying session.Conn until it is closed.
+func (c *queuedConn) read() {
+	for {
+		pk, err := c.Conn.ReadPacket()
+		if err != nil {
+			c.err = err
+			close(c.closed)
+			return
+		}
+		if c.discard.Load() {
+			continue
+		}
+		select {
+		case c.packets <- pk:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// ReadPacket reads the next packet read from the underlying session.Conn.
+func (c *queuedConn) ReadPacket() (packet.Packet, error) {
+	select {
+	case pk := <-c.packets:
+		return pk, nil
+	case <-c.closed:
+		return nil, c.err
+	}
+}
+
+// Close closes the underlying session.Conn and stops reading packets from it.
+func (c *queuedConn) Close() error {
+	c.once.Do(func() {
+		close(c.done)
+	})
+	return c.Conn.Close()
+}
+
+// admitted returns the session.Conn that should be used for the player once it is admitted from the queue. If the
+// underlying session.Conn can write encoded packets directly, the session.Conn returned can as well, so that the
+// session may still use its encoder for the player.
+func (c *queuedConn) admitted() session.Conn {
+	if w, ok := c.Conn.(io.Writer); ok {
+		return writerQueuedConn{queuedConn: c, w: w}
+	}
+	return c
+}
+
+// writerQueuedConn is a queuedConn of which the underlying session.Conn implements io.Writer. Write calls are
+// forwarded to the underlying session.Conn.
+type writerQueuedConn struct {
+	*queuedConn
+	w io.Writer
+}
+
+// Write writes b to the underlying session.Conn.
+func (c writerQueuedConn) Write(b []byte) (int, error) {
+	return c.w.Write(b)
+}
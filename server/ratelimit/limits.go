@@ -0,0 +1,39 @@
+package ratelimit
+
+// Config holds the Limit of every network subsystem of a server. Calling Config.New() creates Limits.
+type Config struct {
+	// Login limits the connections accepted per IP address.
+	Login Limit
+	// Chat limits the chat messages sent per player, by identity UUID.
+	Chat Limit
+	// Command limits the commands executed per player, by identity UUID.
+	Command Limit
+	// Interaction limits the interactions of a player with blocks, items and entities, by identity UUID.
+	Interaction Limit
+}
+
+// Limits holds the Limiter of every network subsystem of a server. Limiters of which the Limit has no rate are nil
+// and allow all actions.
+type Limits struct {
+	Login, Chat, Command, Interaction *Limiter
+}
+
+// New creates Limits using the settings in the Config.
+func (conf Config) New() *Limits {
+	return &Limits{
+		Login:       conf.Login.New(),
+		Chat:        conf.Chat.New(),
+		Command:     conf.Command.New(),
+		Interaction: conf.Interaction.New(),
+	}
+}
+
+// Stats returns the Stats of every Limiter of the Limits, by name of its subsystem, such as 'login' or 'chat'.
+func (l *Limits) Stats() map[string]Stats {
+	return map[string]Stats{
+		"login":       l.Login.Stats(),
+		"chat":        l.Chat.Stats(),
+		"command":     l.Command.Stats(),
+		"interaction": l.Interaction.Stats(),
+	}
+}
@@ -0,0 +1,136 @@
+// Package ratelimit implements token bucket rate limiting with a bucket per key, such as an IP address, XUID or
+// session. The Limits of a server are shared by its network subsystems, so that logins, chat messages, commands and
+// interactions of clients are limited consistently and may be configured and monitored in one place.
+package ratelimit
+
+import (
+	"github.com/df-mc/atomic"
+	"sync"
+	"time"
+)
+
+// Limit holds the settings of a Limiter.
+type Limit struct {
+	// Rate is the amount of tokens added to every bucket per second, which is the sustained rate at which actions
+	// are allowed. If 0 or lower, the Limiter allows all actions.
+	Rate float64
+	// Burst is the maximum amount of tokens that a bucket holds, which is the amount of actions allowed in quick
+	// succession. If 0 or lower, Burst is set to 1.
+	Burst int
+}
+
+// New creates a Limiter using the settings of the Limit. If the Rate of the Limit is 0 or lower, nil is returned,
+// which is a Limiter that allows all actions.
+func (limit Limit) New() *Limiter {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+	return &Limiter{rate: limit.Rate, burst: float64(limit.Burst), buckets: map[string]*bucket{}, swept: time.Now()}
+}
+
+// Limiter is a token bucket rate limiter with a bucket for every key. Every action takes a token from the bucket
+// of its key, and tokens are added back to buckets at a fixed rate. Actions are rejected if the bucket of their key
+// is empty. A nil *Limiter allows all actions. A Limiter is safe for concurrent use.
+type Limiter struct {
+	rate, burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+
+	allowed, rejected atomic.Uint64
+}
+
+// bucket holds the tokens left for a key and the time at which they were last updated.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// sweepInterval is the interval at which buckets that are full are removed from a Limiter.
+const sweepInterval = time.Minute
+
+// Allow takes a token from the bucket of the key passed and reports if the action was allowed. False is returned if
+// the bucket was empty.
+func (l *Limiter) Allow(key string) bool {
+	return l.AllowN(key, 1)
+}
+
+// AllowN takes n tokens from the bucket of the key passed and reports if the action was allowed. If the bucket
+// holds fewer than n tokens, no tokens are taken and false is returned.
+func (l *Limiter) AllowN(key string, n int) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.swept) >= sweepInterval {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.last = now
+	if b.tokens < float64(n) {
+		l.rejected.Inc()
+		return false
+	}
+	b.tokens -= float64(n)
+	l.allowed.Inc()
+	return true
+}
+
+// Reset refills the bucket of the key passed, for example after a player was verified to not be abusive.
+func (l *Limiter) Reset(key string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.buckets, key)
+}
+
+// Stats holds statistics of a Limiter.
+type Stats struct {
+	// Allowed and Rejected are the amounts of actions that were allowed and rejected by the Limiter.
+	Allowed, Rejected uint64
+	// Buckets is the amount of keys of which the bucket is currently not full.
+	Buckets int
+}
+
+// Stats returns the current Stats of the Limiter. A nil *Limiter returns empty Stats.
+func (l *Limiter) Stats() Stats {
+	if l == nil {
+		return Stats{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return Stats{Allowed: l.allowed.Load(), Rejected: l.rejected.Load(), Buckets: len(l.buckets)}
+}
+
+// refill returns the tokens that the bucket passed holds at the time passed.
+func (l *Limiter) refill(b *bucket, now time.Time) float64 {
+	tokens := b.tokens + now.Sub(b.last).Seconds()*l.rate
+	if tokens > l.burst {
+		return l.burst
+	}
+	return tokens
+}
+
+// sweep removes all buckets that are full at the time passed, as these behave the same as new buckets.
+func (l *Limiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+	l.swept = now
+}
@@ -0,0 +1,149 @@
+package rcon
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"errors"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/permission"
+	"github.com/df-mc/dragonfly/server/text"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// Compile time checks to make sure client implements cmd.Source and permission.GroupHolder.
+var (
+	_ cmd.Source             = (*client)(nil)
+	_ permission.GroupHolder = (*client)(nil)
+)
+
+// client is a client connected to a Listener. A client implements cmd.Source, so that commands sent by it are
+// executed by the client itself.
+type client struct {
+	l    *Listener
+	conn net.Conn
+
+	out strings.Builder
+}
+
+// handle reads packets from the client until its connection is closed. The client must authenticate using the
+// first packet it sends.
+func (c *client) handle() {
+	addr := c.conn.RemoteAddr()
+	r := bufio.NewReader(c.conn)
+	if !c.authenticate(r) {
+		return
+	}
+	c.l.infof("RCON client %v authenticated.", addr)
+	for {
+		pk, err := readPacket(r)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				c.l.errorf("rcon client %v: %v", addr, err)
+			}
+			return
+		}
+		switch pk.typ {
+		case typeExecCommand:
+			if err := c.respond(pk.id, c.execute(pk.body)); err != nil {
+				return
+			}
+		case typeResponseValue:
+			// Clients may send an empty packet after a command and wait for it to be mirrored to find the end of a
+			// response that is split over multiple packets.
+			if err := writePacket(c.conn, packet{id: pk.id, typ: typeResponseValue}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// authenticate reads the first packet of the client and checks if it holds the correct password. If the client
+// does not authenticate within Config.AuthTimeout, or sends an incorrect password, false is returned.
+func (c *client) authenticate(r *bufio.Reader) bool {
+	_ = c.conn.SetReadDeadline(time.Now().Add(c.l.conf.AuthTimeout))
+	pk, err := readPacket(r)
+	if err != nil || pk.typ != typeAuth {
+		return false
+	}
+	_ = c.conn.SetReadDeadline(time.Time{})
+
+	if subtle.ConstantTimeCompare([]byte(pk.body), []byte(c.l.conf.Password)) != 1 {
+		c.l.infof("RCON client %v failed to authenticate.", c.conn.RemoteAddr())
+		// Delay the response to slow down guessing the password.
+		time.Sleep(time.Second)
+		_ = writePacket(c.conn, packet{id: -1, typ: typeAuthResponse})
+		return false
+	}
+	return writePacket(c.conn, packet{id: pk.id, typ: typeAuthResponse}) == nil
+}
+
+// execute executes a line as a command and returns its output.
+func (c *client) execute(line string) string {
+	line = strings.TrimPrefix(strings.TrimSpace(line), "/")
+	if line == "" {
+		return ""
+	}
+	c.l.infof("RCON client %v executed command: /%v", c.conn.RemoteAddr(), line)
+
+	name, args, _ := strings.Cut(line, " ")
+	command, ok := cmd.ByAlias(name)
+	if !ok {
+		return "Unknown command: " + name + ". Please check that the command exists and that you have permission to use it."
+	}
+	c.out.Reset()
+	command.Execute(args, c)
+	return strings.TrimSuffix(c.out.String(), "\n")
+}
+
+// respond sends the output passed to the client in response to the packet with the ID passed, splitting it over
+// multiple packets if it is too long to fit in one.
+func (c *client) respond(id int32, output string) error {
+	for {
+		n := len(output)
+		if n > maxBodySize {
+			n = maxBodySize
+		}
+		if err := writePacket(c.conn, packet{id: id, typ: typeResponseValue, body: output[:n]}); err != nil {
+			return err
+		}
+		if output = output[n:]; output == "" {
+			return nil
+		}
+	}
+}
+
+// Name returns the name of the client, which is always 'Rcon'.
+func (c *client) Name() string {
+	return "Rcon"
+}
+
+// Position returns the position of the client, which is always the origin.
+func (c *client) Position() mgl64.Vec3 {
+	return mgl64.Vec3{}
+}
+
+// World returns the default world of the Server of the Listener.
+func (c *client) World() *world.World {
+	return c.l.conf.Server.World()
+}
+
+// SendCommandOutput writes the messages and errors of the cmd.Output passed to the output of the command currently
+// being executed, removing their formatting codes.
+func (c *client) SendCommandOutput(o *cmd.Output) {
+	for _, m := range o.Messages() {
+		c.out.WriteString(text.Clean(m) + "\n")
+	}
+	for _, e := range o.Errors() {
+		c.out.WriteString(text.Clean(e.Error()) + "\n")
+	}
+}
+
+// PermissionGroups returns the permission groups set in the Config of the Listener.
+func (c *client) PermissionGroups() []string {
+	return c.l.conf.Groups
+}
@@ -0,0 +1,72 @@
+package rcon
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	// typeResponseValue is the type of packets holding the output of a command.
+	typeResponseValue int32 = 0
+	// typeExecCommand is the type of packets holding a command to execute. Responses to authentication packets
+	// share the same type.
+	typeExecCommand  int32 = 2
+	typeAuthResponse       = typeExecCommand
+	// typeAuth is the type of packets holding the password of a client.
+	typeAuth int32 = 3
+)
+
+const (
+	// maxPacketSize is the maximum size of packets sent by clients, excluding the length field.
+	maxPacketSize = 4096
+	// maxBodySize is the maximum size of the body of a packet sent to clients. Longer output is split over multiple
+	// packets.
+	maxBodySize = 4096
+	// headerSize is the size of a packet, excluding the length field, without its body.
+	headerSize = 10
+)
+
+// packet is a packet of the RCON protocol. Every packet is prefixed with its little-endian int32 length and holds
+// an ID chosen by the client, the type of the packet and a null-terminated body, followed by an empty string.
+type packet struct {
+	id, typ int32
+	body    string
+}
+
+// readPacket reads a single packet from the bufio.Reader passed.
+func readPacket(r *bufio.Reader) (packet, error) {
+	var n int32
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return packet{}, err
+	}
+	if n < headerSize || n > maxPacketSize {
+		return packet{}, fmt.Errorf("invalid packet size %v", n)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return packet{}, err
+	}
+	pk := packet{
+		id:  int32(binary.LittleEndian.Uint32(data)),
+		typ: int32(binary.LittleEndian.Uint32(data[4:])),
+	}
+	body := data[8 : len(data)-2]
+	if data[len(data)-2] != 0 || data[len(data)-1] != 0 {
+		return packet{}, fmt.Errorf("packet body is not null-terminated")
+	}
+	pk.body = string(body)
+	return pk, nil
+}
+
+// writePacket writes the packet passed to the io.Writer passed.
+func writePacket(w io.Writer, pk packet) error {
+	data := make([]byte, 4+headerSize+len(pk.body))
+	binary.LittleEndian.PutUint32(data, uint32(headerSize+len(pk.body)))
+	binary.LittleEndian.PutUint32(data[4:], uint32(pk.id))
+	binary.LittleEndian.PutUint32(data[8:], uint32(pk.typ))
+	copy(data[12:], pk.body)
+	_, err := w.Write(data)
+	return err
+}
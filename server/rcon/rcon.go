@@ -0,0 +1,163 @@
+// Package rcon implements the RCON remote console protocol, which allows admin tools to run commands on a server
+// over TCP. Clients authenticate using a password, after which every command they send is executed through the
+// command framework, the output being sent back to the client.
+package rcon
+
+import (
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server"
+	"net"
+	"sync"
+	"time"
+)
+
+// Logger is used to log connecting clients, the commands they execute and errors.
+type Logger interface {
+	Infof(format string, a ...any)
+	Errorf(format string, a ...any)
+}
+
+// Config holds the settings of a Listener. Calling Config.New() creates a Listener and starts accepting clients.
+type Config struct {
+	// Server is the Server that commands are run on. Server must not be nil.
+	Server *server.Server
+	// Address is the address that the Listener listens on for clients. If left empty, Address is set to ':25575'.
+	Address string
+	// Password is the password that clients must send to authenticate. Password must not be empty.
+	Password string
+	// MaxConnections is the maximum number of clients connected at the same time. Clients connecting once the
+	// limit is reached are disconnected immediately. If left as 0, MaxConnections is set to 5.
+	MaxConnections int
+	// AuthTimeout is the time that clients have to authenticate after connecting before they are disconnected. If
+	// left as 0, AuthTimeout is set to 10 seconds.
+	AuthTimeout time.Duration
+	// Groups holds the names of the permission groups that clients are a member of once authenticated, as checked
+	// by a permission.Manager. If empty, clients may execute all commands, like the console.
+	Groups []string
+	// Log is the Logger used to log clients and the commands they execute. If nil, nothing is logged.
+	Log Logger
+}
+
+// Listener listens for RCON clients and executes the commands that they send.
+type Listener struct {
+	conf Config
+	l    net.Listener
+
+	mu      sync.Mutex
+	clients map[*client]struct{}
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// New creates a Listener using the settings in the Config and starts accepting clients. Close must be called to
+// stop the Listener and disconnect all clients.
+func (conf Config) New() (*Listener, error) {
+	if conf.Server == nil {
+		return nil, errors.New("new rcon listener: server must not be nil")
+	}
+	if conf.Password == "" {
+		return nil, errors.New("new rcon listener: password must not be empty")
+	}
+	if conf.Address == "" {
+		conf.Address = ":25575"
+	}
+	if conf.MaxConnections <= 0 {
+		conf.MaxConnections = 5
+	}
+	if conf.AuthTimeout <= 0 {
+		conf.AuthTimeout = time.Second * 10
+	}
+	l, err := net.Listen("tcp", conf.Address)
+	if err != nil {
+		return nil, fmt.Errorf("new rcon listener: %w", err)
+	}
+	rl := &Listener{conf: conf, l: l, clients: make(map[*client]struct{})}
+	rl.wg.Add(1)
+	go rl.run()
+	return rl, nil
+}
+
+// Addr returns the address that the Listener listens on.
+func (l *Listener) Addr() net.Addr {
+	return l.l.Addr()
+}
+
+// Close stops accepting clients and disconnects all clients currently connected.
+func (l *Listener) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	err := l.l.Close()
+	for c := range l.clients {
+		_ = c.conn.Close()
+	}
+	l.mu.Unlock()
+
+	l.wg.Wait()
+	return err
+}
+
+// run accepts clients until the Listener is closed.
+func (l *Listener) run() {
+	defer l.wg.Done()
+	for {
+		conn, err := l.l.Accept()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return
+		}
+		c := &client{l: l, conn: conn}
+		if !l.add(c) {
+			l.infof("RCON client %v disconnected: too many connections", conn.RemoteAddr())
+			_ = conn.Close()
+			continue
+		}
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			defer l.remove(c)
+			c.handle()
+		}()
+	}
+}
+
+// add adds a client to the Listener. If the Listener is closed or the maximum number of connections is reached,
+// false is returned.
+func (l *Listener) add(c *client) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed || len(l.clients) >= l.conf.MaxConnections {
+		return false
+	}
+	l.clients[c] = struct{}{}
+	return true
+}
+
+// remove removes a client from the Listener and closes its connection.
+func (l *Listener) remove(c *client) {
+	l.mu.Lock()
+	delete(l.clients, c)
+	l.mu.Unlock()
+	_ = c.conn.Close()
+}
+
+// infof logs a message using the Logger of the Listener, if set.
+func (l *Listener) infof(format string, a ...any) {
+	if l.conf.Log != nil {
+		l.conf.Log.Infof(format, a...)
+	}
+}
+
+// errorf logs an error using the Logger of the Listener, if set.
+func (l *Listener) errorf(format string, a ...any) {
+	if l.conf.Log != nil {
+		l.conf.Log.Errorf(format, a...)
+	}
+}
@@ -0,0 +1,68 @@
+package reload
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"strings"
+)
+
+// Commands returns the /reload command, which reloads all targets of the Manager passed, or a single target if
+// its name is passed, and reports the changes found. The commands may be registered using cmd.Register. allow is
+// called to check if a cmd.Source may execute the commands. If nil and no cmd.Permissions were set using
+// cmd.SetPermissions, the commands may only be executed by sources that are not players, such as the console. If
+// nil and cmd.Permissions were set, the permission node of each command ('command.reload') decides which sources
+// may execute it.
+func Commands(m *Manager, allow func(src cmd.Source) bool) []cmd.Command {
+	if allow == nil {
+		allow = func(src cmd.Source) bool {
+			_, ok := src.(*player.Player)
+			return !ok || cmd.HasPermissions()
+		}
+	}
+	return []cmd.Command{
+		cmd.New("reload", "Reloads resource packs, recipes, permissions and configuration.", nil,
+			reloadCommand{m: m, allow: allow},
+		),
+	}
+}
+
+// reloadCommand implements the /reload [target] command.
+type reloadCommand struct {
+	m      *Manager
+	allow  func(src cmd.Source) bool
+	Target cmd.Optional[string] `cmd:"target"`
+}
+
+// Allow ...
+func (c reloadCommand) Allow(src cmd.Source) bool {
+	return c.allow(src)
+}
+
+// Run ...
+func (c reloadCommand) Run(_ cmd.Source, o *cmd.Output) {
+	var names []string
+	if t, ok := c.Target.Load(); ok {
+		names = []string{t}
+	}
+	results, err := c.m.Reload(names...)
+	if err != nil {
+		o.Errorf("Unknown target. Targets: %v", strings.Join(c.m.Targets(), ", "))
+		return
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			o.Errorf("%v: %v", r.Target, r.Err)
+			continue
+		}
+		if len(r.Report.Applied) == 0 && len(r.Report.RestartRequired) == 0 {
+			o.Printf("%v: no changes.", r.Target)
+			continue
+		}
+		for _, change := range r.Report.Applied {
+			o.Printf("%v: %v", r.Target, change)
+		}
+		for _, change := range r.Report.RestartRequired {
+			o.Printf("%v: %v (requires a restart)", r.Target, change)
+		}
+	}
+}
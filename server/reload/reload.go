@@ -0,0 +1,93 @@
+// Package reload implements reloading parts of a server at runtime, such as resource packs, recipes, permissions and
+// configuration, and the /reload command to do so.
+package reload
+
+import (
+	"fmt"
+	"golang.org/x/exp/slices"
+	"strings"
+	"sync"
+)
+
+// Report describes the changes found when reloading a target.
+type Report struct {
+	// Applied holds descriptions of the changes that were applied immediately, such as 'added group admin'.
+	Applied []string
+	// RestartRequired holds descriptions of the changes that were found, but that only take effect once the server
+	// is restarted.
+	RestartRequired []string
+}
+
+// Func reloads a target and returns a Report of the changes found. If an error is returned, the target should be
+// left unchanged.
+type Func func() (Report, error)
+
+// Result is the result of reloading a single target using Manager.Reload.
+type Result struct {
+	// Target is the name of the target that was reloaded.
+	Target string
+	// Report holds the changes found when reloading the target.
+	Report Report
+	// Err is the error returned when reloading the target, if any.
+	Err error
+}
+
+// Manager holds the targets that may be reloaded by name. Targets are reloaded in the order in which they were
+// registered. A Manager is safe for concurrent use. Use New to create a Manager.
+type Manager struct {
+	mu      sync.Mutex
+	names   []string
+	targets map[string]Func
+}
+
+// New creates an empty Manager.
+func New() *Manager {
+	return &Manager{targets: make(map[string]Func)}
+}
+
+// Register registers a Func to reload the target with the name passed. If a target with the same name was already
+// registered, its Func is replaced.
+func (m *Manager) Register(name string, f Func) {
+	name = strings.ToLower(name)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.targets[name]; !ok {
+		m.names = append(m.names, name)
+	}
+	m.targets[name] = f
+}
+
+// Targets returns the names of all registered targets, in the order in which they were registered.
+func (m *Manager) Targets() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.names)
+}
+
+// Reload reloads the targets with the names passed, or all targets if no names are passed, and returns the Result
+// of every target. An error is returned without reloading anything if one of the names is not a registered target.
+func (m *Manager) Reload(names ...string) ([]Result, error) {
+	m.mu.Lock()
+	if names = slices.Clone(names); len(names) == 0 {
+		names = slices.Clone(m.names)
+	}
+	funcs := make([]Func, 0, len(names))
+	for i, name := range names {
+		names[i] = strings.ToLower(name)
+		f, ok := m.targets[names[i]]
+		if !ok {
+			m.mu.Unlock()
+			return nil, fmt.Errorf("reload: unknown target %v", name)
+		}
+		funcs = append(funcs, f)
+	}
+	m.mu.Unlock()
+
+	results := make([]Result, 0, len(names))
+	for i, f := range funcs {
+		report, err := f()
+		results = append(results, Result{Target: names[i], Report: report, Err: err})
+	}
+	return results, nil
+}
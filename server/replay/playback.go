@@ -0,0 +1,167 @@
+package replay
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/player/chat"
+	"github.com/df-mc/dragonfly/server/player/skin"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"sync"
+	"time"
+)
+
+// PlaybackConfig holds the settings of a Playback. Calling PlaybackConfig.New() creates a Playback and starts
+// playing a Replay.
+type PlaybackConfig struct {
+	// World is the world to play the Replay back in. World must not be nil.
+	World *world.World
+	// Offset is added to all positions in the Replay, so that it may be played back in a copy of the recorded area
+	// at a different location.
+	Offset mgl64.Vec3
+	// Speed is the speed at which the Replay is played back. If 0, the Replay is played back at its original speed.
+	Speed float64
+	// Chat is the chat.Chat that recorded chat messages are written to. If nil, chat messages are not played back.
+	Chat *chat.Chat
+}
+
+// New creates a Playback of the Replay passed and starts playing it back in the background.
+func (conf PlaybackConfig) New(rep *Replay) *Playback {
+	if conf.Speed == 0 {
+		conf.Speed = 1
+	}
+	p := &Playback{
+		conf:     conf,
+		rep:      rep,
+		entities: make(map[uint64]world.Entity),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go p.play()
+	return p
+}
+
+// Playback plays back a Replay in a world. Players in the Replay are re-enacted by fake players without a session,
+// while other entities are re-enacted by a mob of the same entity type without any goals. Entities of types that are
+// not registered in the world are not re-enacted.
+type Playback struct {
+	conf PlaybackConfig
+	rep  *Replay
+
+	entities map[uint64]world.Entity
+
+	once       sync.Once
+	stop, done chan struct{}
+}
+
+// Stop stops the Playback and removes all fake entities that it created. Stop blocks until the Playback has stopped.
+func (p *Playback) Stop() {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+}
+
+// Done returns a channel that is closed once the Playback has finished or was stopped.
+func (p *Playback) Done() <-chan struct{} {
+	return p.done
+}
+
+// play plays back all Records in the Replay, waiting until the Offset of each Record has passed.
+func (p *Playback) play() {
+	defer close(p.done)
+	defer p.removeEntities()
+
+	start := time.Now()
+	for _, rec := range p.rep.Records {
+		if wait := time.Duration(float64(rec.Offset)/p.conf.Speed) - time.Since(start); wait > 0 {
+			t := time.NewTimer(wait)
+			select {
+			case <-t.C:
+			case <-p.stop:
+				t.Stop()
+				return
+			}
+		}
+		select {
+		case <-p.stop:
+			return
+		default:
+			p.apply(rec)
+		}
+	}
+}
+
+// apply re-enacts the action of a single Record.
+func (p *Playback) apply(rec Record) {
+	w, pos := p.conf.World, rec.Pos.Add(p.conf.Offset)
+	switch rec.Kind {
+	case KindBlock:
+		b, ok := world.BlockByName(rec.Block, rec.Properties)
+		if !ok {
+			return
+		}
+		blockPos := rec.BlockPos.Add(p.offsetPos())
+		if rec.Layer == 1 {
+			liq, _ := b.(world.Liquid)
+			w.SetLiquid(blockPos, liq)
+			return
+		}
+		w.SetBlock(blockPos, b, nil)
+	case KindSpawn:
+		if e := p.spawn(rec, pos); e != nil {
+			p.entities[rec.Entity] = e
+			w.AddEntity(e)
+			if m, ok := e.(*entity.Mob); ok {
+				m.Teleport(pos, rec.Rot)
+			}
+		}
+	case KindMove:
+		switch e := p.entities[rec.Entity].(type) {
+		case *player.Player:
+			e.Move(pos.Sub(e.Position()), rec.Rot.Yaw()-e.Rotation().Yaw(), rec.Rot.Pitch()-e.Rotation().Pitch())
+		case *entity.Mob:
+			e.Teleport(pos, rec.Rot)
+		}
+	case KindDespawn:
+		if e, ok := p.entities[rec.Entity]; ok {
+			delete(p.entities, rec.Entity)
+			_ = e.Close()
+		}
+	case KindChat:
+		if p.conf.Chat != nil {
+			_, _ = p.conf.Chat.WriteString(rec.Message)
+		}
+	}
+}
+
+// spawn creates the fake entity that re-enacts the entity of a KindSpawn Record. Nil is returned if the entity type
+// of the Record is not registered in the world.
+func (p *Playback) spawn(rec Record, pos mgl64.Vec3) world.Entity {
+	if rec.Type == "minecraft:player" {
+		fake := player.New(rec.Name, skin.New(64, 64), pos)
+		fake.SetGameMode(world.GameModeCreative)
+		return fake
+	}
+	t, ok := p.conf.World.EntityRegistry().Lookup(rec.Type)
+	if !ok {
+		return nil
+	}
+	m := entity.MobConfig{FallDamageImmune: true}.New(t, pos)
+	m.SetNameTag(rec.Name)
+	return m
+}
+
+// offsetPos returns the Offset of the PlaybackConfig as a block position.
+func (p *Playback) offsetPos() cube.Pos {
+	return cube.PosFromVec3(p.conf.Offset)
+}
+
+// removeEntities removes all fake entities created by the Playback that are still in the world.
+func (p *Playback) removeEntities() {
+	for id, e := range p.entities {
+		delete(p.entities, id)
+		_ = e.Close()
+	}
+}
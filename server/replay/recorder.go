@@ -0,0 +1,189 @@
+package replay
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/player/chat"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecorderConfig holds the settings of a Recorder. Calling RecorderConfig.New() creates a Recorder and starts
+// recording.
+type RecorderConfig struct {
+	// World is the world to record actions in. World must not be nil.
+	World *world.World
+	// Centre is the centre of the area to record.
+	Centre mgl64.Vec3
+	// Radius is the radius in chunks around Centre that is recorded. If 0, a radius of 4 chunks is used.
+	Radius int
+	// Chat is the chat.Chat whose messages are recorded. If nil, no chat messages are recorded.
+	Chat *chat.Chat
+}
+
+// New creates a Recorder that writes a replay to the io.Writer passed. The Recorder records all actions in the area
+// set in the RecorderConfig until Close is called. Only changes are recorded: A replay should be played back in a
+// copy of the area in the state that it had when the recording started.
+func (conf RecorderConfig) New(w io.Writer) (*Recorder, error) {
+	if conf.Radius == 0 {
+		conf.Radius = 4
+	}
+	gw := gzip.NewWriter(w)
+	r := &Recorder{
+		conf:    conf,
+		gw:      gw,
+		enc:     gob.NewEncoder(gw),
+		start:   time.Now(),
+		ids:     make(map[world.Entity]uint64),
+		closing: make(chan struct{}),
+	}
+	if err := r.enc.Encode(Header{Version: version, Start: r.start, Centre: conf.Centre}); err != nil {
+		return nil, fmt.Errorf("new recorder: encode header: %w", err)
+	}
+	r.l = world.NewLoader(conf.Radius, conf.World, r)
+	r.l.Move(conf.Centre)
+	if conf.Chat != nil {
+		conf.Chat.Subscribe(r)
+	}
+	r.wg.Add(1)
+	go r.load()
+	return r, nil
+}
+
+// Recorder records the actions in an area of a world into a replay. A Recorder implements world.Viewer to view the
+// area it records and chat.Subscriber to record chat messages.
+type Recorder struct {
+	world.NopViewer
+	conf RecorderConfig
+
+	l       *world.Loader
+	closing chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+
+	mu     sync.Mutex
+	gw     *gzip.Writer
+	enc    *gob.Encoder
+	err    error
+	closed bool
+	start  time.Time
+	ids    map[world.Entity]uint64
+	nextID uint64
+}
+
+// load keeps loading the chunks in the area recorded by the Recorder, so that the Recorder views the entities and
+// block changes in it.
+func (r *Recorder) load() {
+	defer r.wg.Done()
+	t := time.NewTicker(time.Second / 20)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			r.l.Load(4)
+		case <-r.closing:
+			return
+		}
+	}
+}
+
+// ViewEntity records the entity passed appearing in the recorded area.
+func (r *Recorder) ViewEntity(e world.Entity) {
+	name := ""
+	if n, ok := e.(interface{ Name() string }); ok {
+		name = n.Name()
+	} else if n, ok := e.(interface{ NameTag() string }); ok {
+		name = n.NameTag()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	r.ids[e] = r.nextID
+	r.write(Record{Kind: KindSpawn, Entity: r.nextID, Type: e.Type().EncodeEntity(), Name: name, Pos: e.Position(), Rot: e.Rotation()})
+}
+
+// HideEntity records the entity passed leaving the recorded area.
+func (r *Recorder) HideEntity(e world.Entity) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id, ok := r.ids[e]; ok {
+		delete(r.ids, e)
+		r.write(Record{Kind: KindDespawn, Entity: id})
+	}
+}
+
+// ViewEntityMovement records the movement of the entity passed.
+func (r *Recorder) ViewEntityMovement(e world.Entity, pos mgl64.Vec3, rot cube.Rotation, _ bool) {
+	r.move(e, pos, rot)
+}
+
+// ViewEntityTeleport records the teleportation of the entity passed.
+func (r *Recorder) ViewEntityTeleport(e world.Entity, pos mgl64.Vec3) {
+	r.move(e, pos, e.Rotation())
+}
+
+// move records the entity passed moving to a position with a rotation.
+func (r *Recorder) move(e world.Entity, pos mgl64.Vec3, rot cube.Rotation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id, ok := r.ids[e]; ok {
+		r.write(Record{Kind: KindMove, Entity: id, Pos: pos, Rot: rot})
+	}
+}
+
+// ViewBlockUpdate records the block at the position passed being changed.
+func (r *Recorder) ViewBlockUpdate(pos cube.Pos, b world.Block, layer int) {
+	name, properties := b.EncodeBlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.write(Record{Kind: KindBlock, BlockPos: pos, Block: name, Properties: properties, Layer: layer})
+}
+
+// Message records a message sent in the chat.Chat set in the RecorderConfig.
+func (r *Recorder) Message(a ...any) {
+	msg := strings.TrimSuffix(fmt.Sprintln(a...), "\n")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.write(Record{Kind: KindChat, Message: msg})
+}
+
+// write writes a Record to the replay, setting its Offset. If writing fails, the error is stored and returned by
+// Close, and no further Records are written. write must be called with r.mu held.
+func (r *Recorder) write(rec Record) {
+	if r.err != nil || r.closed {
+		return
+	}
+	rec.Offset = time.Since(r.start)
+	if err := r.enc.Encode(rec); err != nil {
+		r.err = fmt.Errorf("encode record: %w", err)
+	}
+}
+
+// Close stops the recording and flushes the replay to the io.Writer passed to RecorderConfig.New. The io.Writer is
+// not closed. An error is returned if writing any part of the replay failed.
+func (r *Recorder) Close() error {
+	r.once.Do(func() {
+		close(r.closing)
+		r.wg.Wait()
+		if r.conf.Chat != nil {
+			r.conf.Chat.Unsubscribe(r)
+		}
+		_ = r.l.Close()
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.closed = true
+		if err := r.gw.Close(); err != nil && r.err == nil {
+			r.err = fmt.Errorf("flush replay: %w", err)
+		}
+	})
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
@@ -0,0 +1,115 @@
+// Package replay implements recording of the actions that happen in an area of a world, such as block changes,
+// entity movement and chat, into a replay file. Replays may be played back in a world afterwards, re-enacting the
+// recorded actions with fake entities, for example to review the behaviour of a player or to spectate past games.
+package replay
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/go-gl/mathgl/mgl64"
+	"io"
+	"time"
+)
+
+// version is the version of the replay format written by a Recorder. Replays with a different version cannot be
+// read.
+const version = 1
+
+// Kind is the kind of action held by a Record.
+type Kind uint8
+
+const (
+	// KindBlock is the Kind of Record holding a block being changed.
+	KindBlock Kind = iota
+	// KindSpawn is the Kind of Record holding an entity appearing in the recorded area.
+	KindSpawn
+	// KindMove is the Kind of Record holding an entity moving.
+	KindMove
+	// KindDespawn is the Kind of Record holding an entity leaving the recorded area or being removed.
+	KindDespawn
+	// KindChat is the Kind of Record holding a chat message.
+	KindChat
+)
+
+// Header holds information on a replay as a whole. It is written at the start of a replay file.
+type Header struct {
+	// Version is the version of the replay format.
+	Version int
+	// Start is the time at which the recording of the replay started.
+	Start time.Time
+	// Centre is the centre of the area that was recorded.
+	Centre mgl64.Vec3
+}
+
+// Record is a single action captured by a Recorder. Only the fields relevant to the Kind of the Record are set.
+type Record struct {
+	// Offset is the time between the start of the recording and the action.
+	Offset time.Duration
+	// Kind is the Kind of action that the Record holds.
+	Kind Kind
+	// Entity is the ID of the entity that a KindSpawn, KindMove or KindDespawn Record applies to. IDs are unique
+	// within a replay only.
+	Entity uint64
+	// Type is the name of the entity type of a KindSpawn Record, such as 'minecraft:player'.
+	Type string
+	// Name is the name of the player or the name tag of the entity of a KindSpawn Record.
+	Name string
+	// Pos and Rot are the position and rotation of the entity of a KindSpawn or KindMove Record.
+	Pos mgl64.Vec3
+	Rot cube.Rotation
+	// BlockPos, Block, Properties and Layer describe the block set by a KindBlock Record. Block and Properties are
+	// the name and properties of the block as returned by its EncodeBlock method.
+	BlockPos   cube.Pos
+	Block      string
+	Properties map[string]any
+	Layer      int
+	// Message is the message of a KindChat Record.
+	Message string
+}
+
+// Replay is a replay read using Read. It may be played back in a world using a PlaybackConfig.
+type Replay struct {
+	Header
+	// Records holds all actions in the Replay, ordered by their Offset.
+	Records []Record
+}
+
+// Duration returns the duration of the Replay, which is the Offset of its last Record.
+func (r *Replay) Duration() time.Duration {
+	if len(r.Records) == 0 {
+		return 0
+	}
+	return r.Records[len(r.Records)-1].Offset
+}
+
+// Read reads a Replay written by a Recorder from the io.Reader passed.
+func Read(r io.Reader) (*Replay, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("read replay: %w", err)
+	}
+	defer gr.Close()
+
+	rep, dec := &Replay{}, gob.NewDecoder(gr)
+	if err := dec.Decode(&rep.Header); err != nil {
+		return nil, fmt.Errorf("read replay: decode header: %w", err)
+	}
+	if rep.Version != version {
+		return nil, fmt.Errorf("read replay: unsupported version %v", rep.Version)
+	}
+	for {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				// A replay that was not closed properly is cut off at the end, but the records read up to that point
+				// are still usable.
+				return rep, nil
+			}
+			return nil, fmt.Errorf("read replay: decode record: %w", err)
+		}
+		rep.Records = append(rep.Records, rec)
+	}
+}
@@ -0,0 +1,387 @@
+package script
+
+import (
+	"context"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	lua "github.com/yuin/gopher-lua"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	playerType = "player"
+	worldType  = "world"
+)
+
+// script is a single Lua script loaded by an Engine.
+type script struct {
+	e    *Engine
+	name string
+	mod  time.Time
+
+	// mu guards the Lua state, which may only be used by one goroutine at a time.
+	mu     sync.Mutex
+	l      *lua.LState
+	funcs  map[string][]*lua.LFunction
+	closed bool
+	// deferred holds functions that must be called after the script function currently running returns, because
+	// they may call back into the script.
+	deferred []func()
+}
+
+// call calls all functions the script registered for the event passed with the arguments passed. Errors returned
+// by the functions are logged.
+func (s *script) call(ev string, args ...any) (cancel bool, ret lua.LValue) {
+	ret = lua.LNil
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return false, ret
+	}
+	values := make([]lua.LValue, len(args))
+	for i, arg := range args {
+		values[i] = s.value(arg)
+	}
+	for _, fn := range s.funcs[ev] {
+		var err error
+		if s.e.catch(s.name, func() {
+			s.run(func() { err = s.l.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: true}, values...) })
+		}) {
+			continue
+		}
+		if err != nil {
+			s.e.conf.Log.Errorf("script %v: %v: %v", s.name, ev, err)
+			continue
+		}
+		switch r := s.l.Get(-1).(type) {
+		case lua.LBool:
+			cancel = cancel || !bool(r)
+		case lua.LString, lua.LNumber:
+			ret = r
+		}
+		s.l.Pop(1)
+	}
+	deferred := s.deferred
+	s.deferred = nil
+	s.mu.Unlock()
+
+	for _, f := range deferred {
+		f()
+	}
+	return cancel, ret
+}
+
+// run calls f, which runs Lua code in the state of the script. The Lua code is stopped with an error if it runs
+// longer than the Timeout set in the Config of the Engine.
+func (s *script) run(f func()) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.e.conf.Timeout)
+	defer cancel()
+	s.l.SetContext(ctx)
+	defer s.l.RemoveContext()
+	f()
+}
+
+// close closes the Lua state of the script. Functions registered by the script are no longer called after close
+// returns.
+func (s *script) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.funcs = nil
+	s.l.Close()
+}
+
+// value converts a Go value to a Lua value.
+func (s *script) value(v any) lua.LValue {
+	switch v := v.(type) {
+	case *player.Player:
+		return s.userData(v, playerType)
+	case *world.World:
+		return s.userData(v, worldType)
+	case string:
+		return lua.LString(v)
+	case float64:
+		return lua.LNumber(v)
+	case int:
+		return lua.LNumber(v)
+	case int32:
+		return lua.LNumber(v)
+	case uint8:
+		return lua.LNumber(v)
+	case bool:
+		return lua.LBool(v)
+	}
+	return lua.LNil
+}
+
+// userData wraps the value passed in a user data with the metatable of the type passed.
+func (s *script) userData(v any, typ string) lua.LValue {
+	if v == nil {
+		return lua.LNil
+	}
+	ud := s.l.NewUserData()
+	ud.Value = v
+	s.l.SetMetatable(ud, s.l.GetTypeMetatable(typ))
+	return ud
+}
+
+// register registers the globals and types available to the script.
+func (s *script) register() {
+	l := s.l
+	l.SetGlobal("on", l.NewFunction(s.on))
+	l.SetGlobal("log", l.NewFunction(s.log))
+	l.SetGlobal("server", l.SetFuncs(l.NewTable(), map[string]lua.LGFunction{
+		"players":   s.serverPlayers,
+		"player":    s.serverPlayer,
+		"broadcast": s.serverBroadcast,
+		"world":     s.serverWorld,
+	}))
+
+	s.registerType(playerType, map[string]lua.LGFunction{
+		"name":       s.playerName,
+		"message":    s.playerMessage,
+		"position":   s.playerPosition,
+		"teleport":   s.playerTeleport,
+		"health":     s.playerHealth,
+		"world":      s.playerWorld,
+		"disconnect": s.playerDisconnect,
+	})
+	s.registerType(worldType, map[string]lua.LGFunction{
+		"name":      s.worldName,
+		"block":     s.worldBlock,
+		"set_block": s.worldSetBlock,
+		"spawn":     s.worldSpawn,
+		"time":      s.worldTime,
+		"set_time":  s.worldSetTime,
+		"game_time": s.worldGameTime,
+	})
+}
+
+// registerType registers a user data type with the methods passed. User data of the type are equal if the values
+// they wrap are equal.
+func (s *script) registerType(typ string, methods map[string]lua.LGFunction) {
+	mt := s.l.NewTypeMetatable(typ)
+	s.l.SetField(mt, "__index", s.l.SetFuncs(s.l.NewTable(), methods))
+	s.l.SetField(mt, "__eq", s.l.NewFunction(func(l *lua.LState) int {
+		l.Push(lua.LBool(l.CheckUserData(1).Value == l.CheckUserData(2).Value))
+		return 1
+	}))
+}
+
+// on implements the on(event, fn) global.
+func (s *script) on(l *lua.LState) int {
+	ev, fn := l.CheckString(1), l.CheckFunction(2)
+	switch ev {
+	case "join", "quit", "chat", "move", "block_break", "block_place", "hurt", "death":
+		s.funcs[ev] = append(s.funcs[ev], fn)
+	default:
+		l.ArgError(1, fmt.Sprintf("unknown event %q", ev))
+	}
+	return 0
+}
+
+// log implements the log(...) global.
+func (s *script) log(l *lua.LState) int {
+	s.e.conf.Log.Infof("[%v] %v", s.name, text(l, 1))
+	return 0
+}
+
+// serverPlayers implements the server.players() function.
+func (s *script) serverPlayers(l *lua.LState) int {
+	t := l.NewTable()
+	for _, p := range s.e.srv.Players() {
+		t.Append(s.value(p))
+	}
+	l.Push(t)
+	return 1
+}
+
+// serverPlayer implements the server.player(name) function.
+func (s *script) serverPlayer(l *lua.LState) int {
+	if p, ok := s.e.srv.PlayerByName(l.CheckString(1)); ok {
+		l.Push(s.value(p))
+		return 1
+	}
+	l.Push(lua.LNil)
+	return 1
+}
+
+// serverBroadcast implements the server.broadcast(...) function.
+func (s *script) serverBroadcast(l *lua.LState) int {
+	msg := text(l, 1)
+	for _, p := range s.e.srv.Players() {
+		p.Message(msg)
+	}
+	return 0
+}
+
+// serverWorld implements the server.world() function.
+func (s *script) serverWorld(l *lua.LState) int {
+	l.Push(s.value(s.e.srv.World()))
+	return 1
+}
+
+// playerName implements the player:name() method.
+func (s *script) playerName(l *lua.LState) int {
+	l.Push(lua.LString(checkPlayer(l).Name()))
+	return 1
+}
+
+// playerMessage implements the player:message(...) method.
+func (s *script) playerMessage(l *lua.LState) int {
+	checkPlayer(l).Message(text(l, 2))
+	return 0
+}
+
+// playerPosition implements the player:position() method, which returns the x, y and z coordinates of the player.
+func (s *script) playerPosition(l *lua.LState) int {
+	pos := checkPlayer(l).Position()
+	l.Push(lua.LNumber(pos[0]))
+	l.Push(lua.LNumber(pos[1]))
+	l.Push(lua.LNumber(pos[2]))
+	return 3
+}
+
+// playerTeleport implements the player:teleport(x, y, z) method.
+func (s *script) playerTeleport(l *lua.LState) int {
+	p := checkPlayer(l)
+	p.Teleport(mgl64.Vec3{float64(l.CheckNumber(2)), float64(l.CheckNumber(3)), float64(l.CheckNumber(4))})
+	return 0
+}
+
+// playerHealth implements the player:health() method.
+func (s *script) playerHealth(l *lua.LState) int {
+	l.Push(lua.LNumber(checkPlayer(l).Health()))
+	return 1
+}
+
+// playerWorld implements the player:world() method.
+func (s *script) playerWorld(l *lua.LState) int {
+	l.Push(s.value(checkPlayer(l).World()))
+	return 1
+}
+
+// playerDisconnect implements the player:disconnect(...) method. The player is disconnected once the function
+// currently running returns, because disconnecting calls the quit functions of the script.
+func (s *script) playerDisconnect(l *lua.LState) int {
+	p, msg := checkPlayer(l), text(l, 2)
+	s.deferred = append(s.deferred, func() {
+		p.Disconnect(msg)
+	})
+	return 0
+}
+
+// worldName implements the world:name() method.
+func (s *script) worldName(l *lua.LState) int {
+	l.Push(lua.LString(checkWorld(l).Name()))
+	return 1
+}
+
+// worldBlock implements the world:block(x, y, z) method, which returns the name and properties of the block at a
+// position.
+func (s *script) worldBlock(l *lua.LState) int {
+	name, properties := checkWorld(l).Block(checkPos(l, 2)).EncodeBlock()
+	t := l.NewTable()
+	for k, v := range properties {
+		t.RawSetString(k, s.value(v))
+	}
+	l.Push(lua.LString(name))
+	l.Push(t)
+	return 2
+}
+
+// worldSetBlock implements the world:set_block(x, y, z, name[, properties]) method. Numeric properties are set as
+// 32-bit integers and boolean properties as bytes. set_block returns false if no block with the name and
+// properties passed exists.
+func (s *script) worldSetBlock(l *lua.LState) int {
+	w, pos, name := checkWorld(l), checkPos(l, 2), l.CheckString(5)
+	var properties map[string]any
+	if t := l.OptTable(6, nil); t != nil {
+		properties = map[string]any{}
+		t.ForEach(func(k, v lua.LValue) {
+			switch v := v.(type) {
+			case lua.LNumber:
+				properties[k.String()] = int32(v)
+			case lua.LBool:
+				properties[k.String()] = bool(v)
+			default:
+				properties[k.String()] = v.String()
+			}
+		})
+	}
+	if !strings.Contains(name, ":") {
+		name = "minecraft:" + name
+	}
+	b, ok := world.BlockByName(name, properties)
+	if ok {
+		w.SetBlock(pos, b, nil)
+	}
+	l.Push(lua.LBool(ok))
+	return 1
+}
+
+// worldSpawn implements the world:spawn() method, which returns the x, y and z coordinates of the spawn of the
+// world.
+func (s *script) worldSpawn(l *lua.LState) int {
+	pos := checkWorld(l).Spawn()
+	l.Push(lua.LNumber(pos[0]))
+	l.Push(lua.LNumber(pos[1]))
+	l.Push(lua.LNumber(pos[2]))
+	return 3
+}
+
+// worldTime implements the world:time() method.
+func (s *script) worldTime(l *lua.LState) int {
+	l.Push(lua.LNumber(checkWorld(l).Time()))
+	return 1
+}
+
+// worldSetTime implements the world:set_time(time) method.
+func (s *script) worldSetTime(l *lua.LState) int {
+	checkWorld(l).SetTime(l.CheckInt(2))
+	return 0
+}
+
+// worldGameTime implements the world:game_time() method.
+func (s *script) worldGameTime(l *lua.LState) int {
+	l.Push(lua.LNumber(checkWorld(l).GameTime()))
+	return 1
+}
+
+// checkPlayer checks if the first argument passed is a player and returns it.
+func checkPlayer(l *lua.LState) *player.Player {
+	if p, ok := l.CheckUserData(1).Value.(*player.Player); ok {
+		return p
+	}
+	l.ArgError(1, "player expected")
+	return nil
+}
+
+// checkWorld checks if the first argument passed is a world and returns it.
+func checkWorld(l *lua.LState) *world.World {
+	if w, ok := l.CheckUserData(1).Value.(*world.World); ok {
+		return w
+	}
+	l.ArgError(1, "world expected")
+	return nil
+}
+
+// checkPos checks if the three arguments starting at n are numbers and returns them as a block position.
+func checkPos(l *lua.LState, n int) cube.Pos {
+	return cube.PosFromVec3(mgl64.Vec3{float64(l.CheckNumber(n)), float64(l.CheckNumber(n + 1)), float64(l.CheckNumber(n + 2))})
+}
+
+// text joins all arguments starting at n with spaces, similarly to the print function of Lua.
+func text(l *lua.LState, n int) string {
+	parts := make([]string, 0, l.GetTop())
+	for i := n; i <= l.GetTop(); i++ {
+		parts = append(parts, l.Get(i).String())
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,75 @@
+package script
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	lua "github.com/yuin/gopher-lua"
+	"time"
+)
+
+// handler is a player.Handler that dispatches the events of a player to the scripts of an Engine.
+type handler struct {
+	player.NopHandler
+	e *Engine
+	p *player.Player
+}
+
+// HandleMove ...
+func (h *handler) HandleMove(ctx *event.Context, newPos mgl64.Vec3, _, _ float64) {
+	if cancel, _ := h.e.fire("move", h.p, newPos[0], newPos[1], newPos[2]); cancel {
+		ctx.Cancel()
+	}
+}
+
+// HandleChat ...
+func (h *handler) HandleChat(ctx *event.Context, e *player.ChatEvent) {
+	cancel, ret := h.e.fire("chat", h.p, e.Message)
+	if cancel {
+		ctx.Cancel()
+	}
+	if s, ok := ret.(lua.LString); ok {
+		e.Message = string(s)
+	}
+}
+
+// HandleBlockBreak ...
+func (h *handler) HandleBlockBreak(ctx *event.Context, pos cube.Pos, _ *[]item.Stack, _ *int) {
+	name, _ := h.p.World().Block(pos).EncodeBlock()
+	if cancel, _ := h.e.fire("block_break", h.p, pos[0], pos[1], pos[2], name); cancel {
+		ctx.Cancel()
+	}
+}
+
+// HandleBlockPlace ...
+func (h *handler) HandleBlockPlace(ctx *event.Context, pos cube.Pos, b world.Block) {
+	name, _ := b.EncodeBlock()
+	if cancel, _ := h.e.fire("block_place", h.p, pos[0], pos[1], pos[2], name); cancel {
+		ctx.Cancel()
+	}
+}
+
+// HandleHurt ...
+func (h *handler) HandleHurt(ctx *event.Context, damage *float64, _ *time.Duration, _ world.DamageSource) {
+	cancel, ret := h.e.fire("hurt", h.p, *damage)
+	if cancel {
+		ctx.Cancel()
+	}
+	if n, ok := ret.(lua.LNumber); ok {
+		*damage = float64(n)
+	}
+}
+
+// HandleDeath ...
+func (h *handler) HandleDeath(world.DamageSource, *bool, *player.DeathMessage) {
+	h.e.fire("death", h.p)
+}
+
+// HandleQuit ...
+func (h *handler) HandleQuit() {
+	h.e.fire("quit", h.p)
+	h.e.unsubscribe(h.p)
+}
@@ -0,0 +1,340 @@
+// Package script implements a Lua scripting engine for a server. Scripts are loaded from a folder and may listen to
+// player events and interact with players, worlds and blocks without the server having to be recompiled. Scripts
+// are reloaded automatically when the files they were loaded from change.
+//
+// Each script runs in its own Lua state and may use the following globals:
+//
+//	on(event, fn)            -- Registers fn to be called when event occurs.
+//	log(...)                 -- Logs a message using the Logger of the Engine.
+//	server.players()         -- Returns a table of all online players.
+//	server.player(name)      -- Returns the online player with the name passed, or nil.
+//	server.broadcast(...)    -- Sends a message to all online players.
+//	server.world()           -- Returns the default world of the server.
+//
+// Players have the methods name, message, position, teleport, health, world and disconnect. Worlds have the methods
+// name, block, set_block, spawn, time, set_time and game_time. The events that may be listened to are join, quit,
+// chat, move, block_break, block_place, hurt and death. Returning false from a function registered for any event
+// other than join, quit and death cancels the event. Returning a string from a chat function replaces the message
+// and returning a number from a hurt function replaces the damage dealt.
+//
+// Scripts only have access to the base, table, string and math libraries of Lua, without functions that load other
+// files. Scripts that run longer than the Timeout in the Config are stopped with an error.
+package script
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/sirupsen/logrus"
+	lua "github.com/yuin/gopher-lua"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Config holds the settings of an Engine. Calling Config.New() creates an Engine.
+type Config struct {
+	// Log is the Logger used to report scripts being loaded and errors that occur while running them.
+	Log server.Logger
+	// Folder is the folder that scripts are loaded from. Every file with the .lua extension is loaded as a script.
+	// If empty, no scripts are loaded.
+	Folder string
+	// ReloadInterval is the interval at which the Folder is checked for scripts that were added, changed or
+	// removed. If zero, the Folder is checked every second. If negative, scripts are never reloaded.
+	ReloadInterval time.Duration
+	// Timeout is the maximum duration that running a script when it is loaded, or calling one of the functions it
+	// registered, may take. Scripts that run longer are stopped with an error. If zero, Timeout is one second.
+	Timeout time.Duration
+}
+
+// New creates an Engine for the server passed using the settings in the Config. Load must be called to start
+// running scripts.
+func (conf Config) New(srv *server.Server) *Engine {
+	if conf.Log == nil {
+		conf.Log = logrus.New()
+	}
+	if conf.ReloadInterval == 0 {
+		conf.ReloadInterval = time.Second
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = time.Second
+	}
+	return &Engine{conf: conf, srv: srv, scripts: map[string]*script{}, failed: map[string]time.Time{}, players: map[*player.Player]func(){}, closing: make(chan struct{})}
+}
+
+// Engine runs the Lua scripts of a server and dispatches player events to them. An Engine is safe for concurrent
+// use.
+type Engine struct {
+	conf Config
+	srv  *server.Server
+
+	once    sync.Once
+	closing chan struct{}
+
+	// reloading ensures only one reload runs at a time, so that scripts may be loaded without holding mu.
+	reloading sync.Mutex
+
+	mu      sync.Mutex
+	scripts map[string]*script
+	failed  map[string]time.Time
+	players map[*player.Player]func()
+}
+
+// Load loads all scripts in the folder set in the Config and starts watching the folder for changes. The folder is
+// created if it does not yet exist. Players that are already online start being handled by the scripts loaded.
+// Scripts that could not be loaded are logged and skipped. An error is only returned if the folder could not be
+// read.
+func (e *Engine) Load() error {
+	if e.conf.Folder == "" {
+		return nil
+	}
+	if err := os.MkdirAll(e.conf.Folder, 0777); err != nil {
+		return fmt.Errorf("load scripts: create folder: %w", err)
+	}
+	if err := e.reload(); err != nil {
+		return err
+	}
+	for _, p := range e.srv.Players() {
+		e.subscribe(p)
+	}
+	if e.conf.ReloadInterval > 0 {
+		go e.watch()
+	}
+	return nil
+}
+
+// HandleJoin makes the scripts of the Engine handle the events of the player passed and calls the join functions
+// of all scripts. HandleJoin may be passed to server.Server.Accept, or be called from the function passed to it.
+func (e *Engine) HandleJoin(p *player.Player) {
+	e.subscribe(p)
+	e.fire("join", p)
+}
+
+// Close stops watching the script folder, stops handling the events of players and closes all scripts.
+func (e *Engine) Close() error {
+	e.once.Do(func() {
+		close(e.closing)
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		for p, unsubscribe := range e.players {
+			unsubscribe()
+			delete(e.players, p)
+		}
+		for path, s := range e.scripts {
+			s.close()
+			delete(e.scripts, path)
+		}
+	})
+	return nil
+}
+
+// subscribe subscribes a handler to the player passed that dispatches its events to the scripts of the Engine.
+func (e *Engine) subscribe(p *player.Player) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.players[p]; ok {
+		return
+	}
+	select {
+	case <-e.closing:
+		return
+	default:
+	}
+	e.players[p] = p.Subscribe(&handler{e: e, p: p}, event.PriorityNormal)
+}
+
+// unsubscribe stops handling the events of the player passed.
+func (e *Engine) unsubscribe(p *player.Player) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if unsubscribe, ok := e.players[p]; ok {
+		unsubscribe()
+		delete(e.players, p)
+	}
+}
+
+// watch checks the script folder for changes every ReloadInterval until the Engine is closed.
+func (e *Engine) watch() {
+	t := time.NewTicker(e.conf.ReloadInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := e.reload(); err != nil {
+				e.conf.Log.Errorf("%v", err)
+			}
+		case <-e.closing:
+			return
+		}
+	}
+}
+
+// reload loads scripts that were added to the script folder, reloads scripts of which the file was modified and
+// closes scripts of which the file was removed. If a modified script fails to load, the previous version of it
+// keeps running.
+func (e *Engine) reload() error {
+	files, err := os.ReadDir(e.conf.Folder)
+	if err != nil {
+		return fmt.Errorf("load scripts: read folder: %w", err)
+	}
+	found := make(map[string]time.Time, len(files))
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".lua" {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		found[filepath.Join(e.conf.Folder, f.Name())] = info.ModTime()
+	}
+
+	e.reloading.Lock()
+	defer e.reloading.Unlock()
+
+	e.mu.Lock()
+	select {
+	case <-e.closing:
+		e.mu.Unlock()
+		return nil
+	default:
+	}
+	for path, s := range e.scripts {
+		if _, ok := found[path]; !ok {
+			s.close()
+			delete(e.scripts, path)
+			e.conf.Log.Infof("Unloaded script %v.", filepath.Base(path))
+		}
+	}
+	for path := range e.failed {
+		if _, ok := found[path]; !ok {
+			delete(e.failed, path)
+		}
+	}
+	for path, mod := range found {
+		if old, ok := e.scripts[path]; ok && old.mod.Equal(mod) || e.failed[path].Equal(mod) {
+			delete(found, path)
+		}
+	}
+	e.mu.Unlock()
+
+	// Scripts are run without holding mu, so that events may still be handled by the scripts already loaded while
+	// new ones are loading.
+	loaded := make(map[string]*script, len(found))
+	for path, mod := range found {
+		s, err := e.load(path, mod)
+		if err != nil {
+			// Keep running the previous version of the script, if any, and don't attempt to load the file again
+			// until it is changed.
+			e.conf.Log.Errorf("load script %v: %v", filepath.Base(path), err)
+		}
+		loaded[path] = s
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	select {
+	case <-e.closing:
+		for _, s := range loaded {
+			if s != nil {
+				s.close()
+			}
+		}
+		return nil
+	default:
+	}
+	for path, s := range loaded {
+		if s == nil {
+			e.failed[path] = found[path]
+			continue
+		}
+		delete(e.failed, path)
+		old, ok := e.scripts[path]
+		e.scripts[path] = s
+		if ok {
+			old.close()
+			e.conf.Log.Infof("Reloaded script %v.", filepath.Base(path))
+		} else {
+			e.conf.Log.Infof("Loaded script %v.", filepath.Base(path))
+		}
+	}
+	return nil
+}
+
+// load creates a new Lua state for the script at the path passed and runs it.
+func (e *Engine) load(path string, mod time.Time) (*script, error) {
+	s := &script{e: e, name: filepath.Base(path), mod: mod, l: newState(), funcs: map[string][]*lua.LFunction{}}
+	s.register()
+
+	var err error
+	if e.catch(s.name, func() {
+		s.run(func() { err = s.l.DoFile(path) })
+	}) {
+		err = fmt.Errorf("panic while running script")
+	}
+	if err != nil {
+		s.l.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// newState creates a new Lua state with only the base, table, string and math libraries opened. Functions of the
+// base library that load files or modules are removed, so that scripts cannot access the file system.
+func newState() *lua.LState {
+	l := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range []struct {
+		name string
+		f    lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		l.Push(l.NewFunction(lib.f))
+		l.Push(lua.LString(lib.name))
+		l.Call(1, 0)
+	}
+	for _, name := range []string{"dofile", "loadfile", "require", "module"} {
+		l.SetGlobal(name, lua.LNil)
+	}
+	return l
+}
+
+// fire calls the functions registered for the event passed in all scripts, in the order of the names of the
+// scripts. If any of the functions returned false, fire returns true. The last string or number returned by any
+// of the functions is returned as well.
+func (e *Engine) fire(ev string, args ...any) (cancel bool, ret lua.LValue) {
+	e.mu.Lock()
+	scripts := make([]*script, 0, len(e.scripts))
+	for _, s := range e.scripts {
+		scripts = append(scripts, s)
+	}
+	e.mu.Unlock()
+	sort.Slice(scripts, func(i, j int) bool {
+		return scripts[i].name < scripts[j].name
+	})
+
+	ret = lua.LNil
+	for _, s := range scripts {
+		c, r := s.call(ev, args...)
+		cancel = cancel || c
+		if r != lua.LNil {
+			ret = r
+		}
+	}
+	return cancel, ret
+}
+
+// catch calls f, recovering any panic that occurs using the crash.Reporter of the server. If a panic was recovered,
+// catch returns true.
+func (e *Engine) catch(name string, f func()) bool {
+	return e.srv.CrashReporter().Catch("script", func() map[string]any {
+		return map[string]any{"script": name}
+	}, f)
+}
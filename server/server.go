@@ -7,13 +7,22 @@ import (
 	"encoding/base64"
 	"fmt"
 	"github.com/df-mc/atomic"
+	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/crash"
+	"github.com/df-mc/dragonfly/server/internal/blockinternal"
 	"github.com/df-mc/dragonfly/server/internal/iteminternal"
 	"github.com/df-mc/dragonfly/server/internal/sliceutil"
-	_ "github.com/df-mc/dragonfly/server/item" // Imported for maintaining correct initialisation order.
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/item/category"
+	"github.com/df-mc/dragonfly/server/item/creative"
+	"github.com/df-mc/dragonfly/server/item/recipe"
 	"github.com/df-mc/dragonfly/server/player"
 	"github.com/df-mc/dragonfly/server/player/skin"
+	"github.com/df-mc/dragonfly/server/ratelimit"
+	"github.com/df-mc/dragonfly/server/reload"
 	"github.com/df-mc/dragonfly/server/session"
+	"github.com/df-mc/dragonfly/server/text"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/go-gl/mathgl/mgl64"
@@ -23,10 +32,11 @@ import (
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
-	"github.com/sandertv/gophertunnel/minecraft/text"
-	"github.com/sirupsen/logrus"
+	"github.com/sandertv/gophertunnel/minecraft/resource"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"math/rand"
+	"net"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -35,6 +45,7 @@ import (
 	"sync"
 	"syscall"
 	"time"
+	_ "unsafe" // Imported for compiler directives.
 )
 
 // Server implements a Dragonfly server. It runs the main server loop and
@@ -44,13 +55,29 @@ type Server struct {
 
 	once    sync.Once
 	started atomic.Bool
+	// closing is true once Close was called. closed is closed once the server
+	// has finished shutting down.
+	closing atomic.Bool
+	closed  chan struct{}
 
 	world, nether, end *world.World
 
-	customItems []protocol.ItemComponentEntry
+	customItems      []protocol.ItemComponentEntry
+	customBlocks     []protocol.BlockEntry
+	customBlockItems []protocol.ItemEntry
+
+	// statusFunc is the StatusFunc set using SetStatusFunc.
+	statusFunc atomic.Value[StatusFunc]
+
+	// limits holds the rate limiters shared by the listeners and sessions of
+	// the server.
+	limits *ratelimit.Limits
 
 	listeners []Listener
 	incoming  chan *session.Session
+	// queue is the join queue of the server. It is nil if Config.JoinQueueSize
+	// is 0 or if no maximum player count is set.
+	queue *joinQueue
 
 	pmu sync.RWMutex
 	// p holds a map of all players currently connected to the server. When they
@@ -111,6 +138,11 @@ func (srv *Server) Accept(f HandleFunc) bool {
 	srv.pmu.Unlock()
 
 	s.Start()
+	if srv.closing.Load() {
+		// The player joined while the server was shutting down, after the
+		// other players were already disconnected.
+		p.Disconnect(text.Colourf("<yellow>%v</yellow>", srv.conf.ShutdownMessage))
+	}
 	return true
 }
 
@@ -133,6 +165,90 @@ func (srv *Server) End() *world.World {
 	return srv.end
 }
 
+// CrashReporter returns the crash.Reporter used to recover panics in the
+// Server. A crash.Handler may be attached to it by calling its Handle method,
+// for example to forward crash reports to an alerting integration.
+func (srv *Server) CrashReporter() *crash.Reporter {
+	return srv.conf.CrashReporter
+}
+
+// RateLimits returns the rate limiters that limit the rates at which clients
+// may log in, chat, execute commands and interact, as configured using
+// Config.RateLimits. The Stats of the limiters may be used for monitoring.
+func (srv *Server) RateLimits() *ratelimit.Limits {
+	return srv.limits
+}
+
+// AddResources adds resource packs that players are requested to download
+// when joining the server, in addition to the packs in Config.Resources.
+// Encrypted packs must have their content key set using
+// resource.Pack.WithContentKey. AddResources must be called before Listen. An
+// error is returned if the server is already listening or if a pack with the
+// same UUID was already added.
+func (srv *Server) AddResources(packs ...*resource.Pack) error {
+	if srv.started.Load() {
+		return fmt.Errorf("add resources: server is already listening")
+	}
+	for _, pack := range packs {
+		if slices.ContainsFunc(srv.conf.Resources, func(existing *resource.Pack) bool {
+			return existing.UUID() == pack.UUID()
+		}) {
+			return fmt.Errorf("add resources: pack %v with UUID %v already added", pack.Name(), pack.UUID())
+		}
+		srv.conf.Resources = append(srv.conf.Resources, pack)
+	}
+	return nil
+}
+
+// Resources returns the resource packs that players are requested to download
+// when joining the server.
+func (srv *Server) Resources() []*resource.Pack {
+	return slices.Clone(srv.conf.Resources)
+}
+
+// ReloadResources replaces the resource packs that players are requested to
+// download when joining the server with the packs passed, for example after
+// reading them from the resources folder again. The reload.Report returned
+// lists the packs that were added, removed or updated. Resource packs are sent
+// by the Listeners, which cannot change their packs once listening, so changes
+// made after Listen was called are reported as requiring a restart and are
+// not applied.
+func (srv *Server) ReloadResources(packs ...*resource.Pack) reload.Report {
+	var changes []string
+	for _, pack := range packs {
+		i := slices.IndexFunc(srv.conf.Resources, func(existing *resource.Pack) bool {
+			return existing.UUID() == pack.UUID()
+		})
+		if i == -1 {
+			changes = append(changes, fmt.Sprintf("added pack %v v%v", pack.Name(), pack.Version()))
+		} else if existing := srv.conf.Resources[i]; existing.Version() != pack.Version() || existing.Checksum() != pack.Checksum() {
+			changes = append(changes, fmt.Sprintf("updated pack %v v%v to v%v", pack.Name(), existing.Version(), pack.Version()))
+		}
+	}
+	for _, existing := range srv.conf.Resources {
+		if !slices.ContainsFunc(packs, func(pack *resource.Pack) bool {
+			return existing.UUID() == pack.UUID()
+		}) {
+			changes = append(changes, fmt.Sprintf("removed pack %v v%v", existing.Name(), existing.Version()))
+		}
+	}
+	if srv.started.Load() {
+		return reload.Report{RestartRequired: changes}
+	}
+	srv.conf.Resources = slices.Clone(packs)
+	return reload.Report{Applied: changes}
+}
+
+// ReloadRecipes sends all crafting recipes currently registered to all players
+// online, so that recipes registered after players joined may be used by them.
+func (srv *Server) ReloadRecipes() reload.Report {
+	players := srv.Players()
+	for _, p := range players {
+		p.ResendRecipes()
+	}
+	return reload.Report{Applied: []string{fmt.Sprintf("sent %v recipes to %v players", len(recipe.Recipes()), len(players))}}
+}
+
 // MaxPlayerCount returns the maximum amount of players that are allowed to
 // play on the server at the same time. Players trying to join when the server
 // is full will be refused to enter. If the config has a maximum player count
@@ -188,19 +304,35 @@ func (srv *Server) CloseOnProgramEnd() {
 	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		<-c
-		if err := srv.Close(); err != nil {
+		if err := srv.Close(context.Background()); err != nil {
 			srv.conf.Log.Errorf("close server: %v", err)
 		}
 	}()
 }
 
-// Close closes the server, making any call to Run/Accept cancel immediately.
-func (srv *Server) Close() error {
+// Close shuts down the server, making any call to Accept return false once
+// finished. The server shuts down in a fixed order: All players are
+// disconnected with the Config.ShutdownMessage and their data is saved, after
+// which all changed chunks of the worlds are saved and the player provider,
+// the worlds and their providers and finally the listeners are closed.
+// Close blocks until the server has shut down or until the context passed is
+// done, in which case the context's error is returned and the server
+// continues shutting down in the background. Calling Close more than once
+// waits for the same shutdown to finish.
+func (srv *Server) Close(ctx context.Context) error {
 	if !srv.started.Load() {
 		panic("server not yet running")
 	}
-	srv.once.Do(srv.close)
-	return nil
+	srv.once.Do(func() {
+		srv.closing.Store(true)
+		go srv.close()
+	})
+	select {
+	case <-srv.closed:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("close server: %w", ctx.Err())
+	}
 }
 
 // close stops the server, storing player and world data to disk when
@@ -208,6 +340,7 @@ func (srv *Server) Close() error {
 func (srv *Server) close() {
 	srv.conf.Log.Infof("Server shutting down...")
 	defer srv.conf.Log.Infof("Server stopped.")
+	defer close(srv.closed)
 
 	srv.conf.Log.Debugf("Disconnecting players...")
 	for _, p := range srv.Players() {
@@ -215,6 +348,11 @@ func (srv *Server) close() {
 	}
 	srv.pwg.Wait()
 
+	srv.conf.Log.Debugf("Saving worlds...")
+	for _, w := range []*world.World{srv.end, srv.nether, srv.world} {
+		w.Save()
+	}
+
 	srv.conf.Log.Debugf("Closing player provider...")
 	if err := srv.conf.PlayerProvider.Close(); err != nil {
 		srv.conf.Log.Errorf("Error while closing player provider: %v", err)
@@ -260,11 +398,21 @@ func (srv *Server) listen(l Listener) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			if host, _, err := net.SplitHostPort(c.RemoteAddr().String()); err == nil && !srv.limits.Login.Allow(host) {
+				_ = c.WritePacket(&packet.Disconnect{Message: "You are logging in too quickly. Please try again later."})
+				_ = c.Close()
+				return
+			}
 			if msg, ok := srv.conf.Allower.Allow(c.RemoteAddr(), c.IdentityData(), c.ClientData()); !ok {
 				_ = c.WritePacket(&packet.Disconnect{HideDisconnectionScreen: msg == "", Message: msg})
 				_ = c.Close()
 				return
 			}
+			if msg, ok := srv.verifyLogin(c, l); !ok {
+				_ = c.WritePacket(&packet.Disconnect{Message: msg})
+				_ = c.Close()
+				return
+			}
 			srv.finaliseConn(ctx, c, l)
 		}()
 	}
@@ -274,6 +422,7 @@ func (srv *Server) listen(l Listener) {
 // connections from players.
 func (srv *Server) startListening() {
 	srv.makeItemComponents()
+	srv.makeBlockEntries()
 
 	srv.wg.Add(len(srv.conf.Listeners))
 	for _, lf := range srv.conf.Listeners {
@@ -291,7 +440,7 @@ func (srv *Server) startListening() {
 // at startup
 func (srv *Server) makeItemComponents() {
 	custom := world.CustomItems()
-	srv.customItems = make([]protocol.ItemComponentEntry, len(custom))
+	srv.customItems = make([]protocol.ItemComponentEntry, 0, len(custom))
 
 	for _, it := range custom {
 		name, _ := it.EncodeItem()
@@ -302,6 +451,56 @@ func (srv *Server) makeItemComponents() {
 	}
 }
 
+// registerCreativeItems adds the item forms of all custom items and custom
+// blocks registered to the default creative menu, unless they were already
+// added using creative.RegisterItem. Items are grouped by the group of their
+// category.Category.
+func registerCreativeItems() {
+	add := func(it world.Item, c category.Category) {
+		name, _ := it.EncodeItem()
+		if slices.ContainsFunc(creative.Items(), func(s item.Stack) bool {
+			n, _ := s.Item().EncodeItem()
+			return n == name
+		}) {
+			return
+		}
+		group := c.Group()
+		if group == "none" {
+			group = ""
+		}
+		creative.Default().Add(item.NewStack(it, 1), group)
+	}
+	for _, it := range world.CustomItems() {
+		add(it, it.Category())
+	}
+	for _, b := range world.CustomBlocks() {
+		add(b, b.Properties().Category)
+	}
+}
+
+// makeBlockEntries initializes the server's block palette entries and the
+// item entries of their item forms using the registered custom blocks. The
+// entries are created only once at startup.
+func (srv *Server) makeBlockEntries() {
+	for _, b := range world.CustomBlocks() {
+		name, _ := b.EncodeBlock()
+		if slices.ContainsFunc(srv.customBlocks, func(entry protocol.BlockEntry) bool {
+			return entry.Name == name
+		}) {
+			continue
+		}
+		rid, _, _ := world.ItemRuntimeID(b)
+		srv.customBlocks = append(srv.customBlocks, protocol.BlockEntry{
+			Name:       name,
+			Properties: blockinternal.Properties(b, 255-rid),
+		})
+		srv.customBlockItems = append(srv.customBlockItems, protocol.ItemEntry{
+			Name:      name,
+			RuntimeID: int16(rid),
+		})
+	}
+}
+
 // wait awaits the closing of all Listeners added to the Server through a call
 // to listen and closed the players channel once that happens.
 func (srv *Server) wait() {
@@ -335,16 +534,33 @@ func (srv *Server) finaliseConn(ctx context.Context, conn session.Conn, l Listen
 		return
 	}
 	_ = conn.WritePacket(&packet.ItemComponent{Items: srv.customItems})
+	var slot *queueSlot
+	if srv.queue != nil {
+		var ok bool
+		if conn, slot, ok = srv.awaitQueue(ctx, conn, l); !ok {
+			return
+		}
+	}
+	spawned := false
+	defer func() {
+		if !spawned {
+			// The player never spawned, so the session will never release the slot it took in the queue.
+			slot.release()
+		}
+	}()
 	if p, ok := srv.Player(id); ok {
 		p.Disconnect("Logged in from another location.")
 	}
-	srv.incoming <- srv.createPlayer(id, conn, playerData)
+	s := srv.createPlayer(id, conn, playerData, slot)
+	spawned = true
+	srv.incoming <- s
 }
 
 // defaultGameData returns a minecraft.GameData as sent for a new player. It
 // may later be modified if the player was saved in the player provider of the
 // server.
 func (srv *Server) defaultGameData() minecraft.GameData {
+	difficulty, _ := world.DifficultyID(srv.world.Difficulty())
 	return minecraft.GameData{
 		// We set these IDs to 1, because that's how the session will treat them.
 		EntityUniqueID:  1,
@@ -354,20 +570,18 @@ func (srv *Server) defaultGameData() minecraft.GameData {
 		BaseGameVersion: protocol.CurrentVersion,
 
 		Time:       int64(srv.world.Time()),
-		Difficulty: 2,
+		Difficulty: int32(difficulty),
 
 		PlayerGameMode:    packet.GameTypeCreative,
 		PlayerPermissions: packet.PermissionLevelMember,
 		PlayerPosition:    vec64To32(srv.world.Spawn().Vec3Centre().Add(mgl64.Vec3{0, 1.62})),
 
-		Items:     srv.itemEntries(),
-		GameRules: []protocol.GameRule{{Name: "naturalregeneration", Value: false}},
+		Items:        srv.itemEntries(),
+		CustomBlocks: srv.customBlocks,
+		GameRules:    gameRules(srv.world.GameRules()),
 
 		ServerAuthoritativeInventory: true,
-		PlayerMovementSettings: protocol.PlayerMovementSettings{
-			MovementType:                     protocol.PlayerMovementModeServer,
-			ServerAuthoritativeBlockBreaking: true,
-		},
+		PlayerMovementSettings:       srv.conf.MovementMode.PlayerMovementSettings(),
 	}
 }
 
@@ -412,40 +626,80 @@ func (srv *Server) handleSessionClose(c session.Controllable) {
 		return
 	}
 
-	if err := srv.conf.PlayerProvider.Save(p.UUID(), p.Data()); err != nil {
-		srv.conf.Log.Errorf("Error while saving data: %v", err)
-	}
+	srv.conf.CrashReporter.Catch("player provider", func() map[string]any {
+		return map[string]any{"player": p.Name(), "uuid": p.UUID().String()}
+	}, func() {
+		if err := srv.conf.PlayerProvider.Save(p.UUID(), p.Data()); err != nil {
+			srv.conf.Log.Errorf("Error while saving data: %v", err)
+		}
+	})
 	srv.pwg.Done()
 }
 
 // createPlayer creates a new player instance using the UUID and connection
-// passed.
-func (srv *Server) createPlayer(id uuid.UUID, conn session.Conn, data *player.Data) *session.Session {
-	w, gm, pos := srv.world, srv.world.DefaultGameMode(), srv.world.Spawn().Vec3Middle()
+// passed. The queueSlot passed, if not nil, is released when the session of
+// the player is closed.
+func (srv *Server) createPlayer(id uuid.UUID, conn session.Conn, data *player.Data, slot *queueSlot) *session.Session {
+	w, gm, pos := srv.world, srv.world.DefaultGameMode(), srv.scatterSpawn(srv.world)
 	if data != nil {
 		w, gm, pos = data.World, data.GameMode, data.Position
 	}
-	s := session.New(conn, srv.conf.MaxChunkRadius, srv.conf.Log, srv.conf.JoinMessage, srv.conf.QuitMessage)
+	logger := withField(subsystemLog(srv.conf.Log, "session"), "player", conn.IdentityData().DisplayName)
+	s := session.Config{
+		MaxChunkRadius:  srv.conf.MaxChunkRadius,
+		ChunksPerTick:   srv.conf.ChunksPerTick,
+		EntitiesPerTick: srv.conf.EntitiesPerTick,
+		AntiXray:        srv.conf.AntiXray,
+		MovementMode:    srv.conf.MovementMode,
+		MaxBandwidth:    srv.conf.MaxBandwidth,
+		FlushRate:       srv.conf.FlushRate,
+		Log:             logger,
+		CrashReporter:   srv.conf.CrashReporter,
+		JoinMessage:     srv.conf.JoinMessage,
+		QuitMessage:     srv.conf.QuitMessage,
+		Limits:          srv.limits,
+	}.New(conn)
 	p := player.NewWithSession(conn.IdentityData().DisplayName, conn.IdentityData().XUID, id, srv.parseSkin(conn.ClientData()), s, pos, data)
+	p.SetMovementPolicy(srv.conf.MovementPolicy)
 
-	s.Spawn(p, pos, w, gm, srv.handleSessionClose)
+	s.Spawn(p, pos, w, gm, func(c session.Controllable) {
+		slot.release()
+		srv.handleSessionClose(c)
+	})
 	srv.pwg.Add(1)
 	return s
 }
 
+// scatterSpawn returns the position that a player joining the server for the
+// first time is spawned at in the world passed. If a spawn radius is set, a
+// random position within it on top of the highest block of its column is
+// selected, granted that the position is safe as defined by
+// world.SafePosition. If no such position is found, the spawn of the world is
+// returned.
+func (srv *Server) scatterSpawn(w *world.World) mgl64.Vec3 {
+	spawn := w.Spawn()
+	r := srv.conf.SpawnRadius
+	if r <= 0 {
+		return spawn.Vec3Middle()
+	}
+	const attempts = 16
+	for i := 0; i < attempts; i++ {
+		x, z := spawn[0]+w.Rand().Intn(r*2+1)-r, spawn[2]+w.Rand().Intn(r*2+1)-r
+		if pos := (cube.Pos{x, w.HighestBlock(x, z) + 1, z}); w.SafePosition(pos) {
+			return pos.Vec3Middle()
+		}
+	}
+	return spawn.Vec3Middle()
+}
+
 // createWorld loads a world of the server with a specific dimension, ending
 // the program if the world could not be loaded. The layers passed are used to
 // create a generator.Flat that is used as generator for the world.
 func (srv *Server) createWorld(dim world.Dimension, nether, end **world.World) *world.World {
-	logger := srv.conf.Log
-	if v, ok := logger.(interface {
-		WithField(key string, field any) *logrus.Entry
-	}); ok {
-		// Add a dimension field to be able to distinguish between the different
-		// dimensions in the log. Dimensions implement fmt.Stringer so we can
-		// just fmt.Sprint them for a readable name.
-		logger = v.WithField("dimension", strings.ToLower(fmt.Sprint(dim)))
-	}
+	// Add a dimension field to be able to distinguish between the different
+	// dimensions in the log. Dimensions implement fmt.Stringer so we can just
+	// fmt.Sprint them for a readable name.
+	logger := withField(subsystemLog(srv.conf.Log, "world"), "dimension", strings.ToLower(fmt.Sprint(dim)))
 	logger.Debugf("Loading world...")
 
 	conf := world.Config{
@@ -454,8 +708,14 @@ func (srv *Server) createWorld(dim world.Dimension, nether, end **world.World) *
 		Provider:        srv.conf.WorldProvider,
 		Generator:       srv.conf.Generator(dim),
 		RandomTickSpeed: srv.conf.RandomTickSpeed,
+		TickBudget:      srv.conf.TickBudget,
+		SaveInterval:    srv.conf.SaveInterval,
 		ReadOnly:        srv.conf.ReadOnlyWorld,
 		Entities:        srv.conf.Entities,
+		CrashReporter:   srv.conf.CrashReporter,
+		Rules:           srv.conf.Rules[dim],
+
+		RegenerateCorruptChunks: srv.conf.RegenerateCorruptChunks,
 		PortalDestination: func(dim world.Dimension) *world.World {
 			if dim == world.Nether {
 				return *nether
@@ -465,6 +725,13 @@ func (srv *Server) createWorld(dim world.Dimension, nether, end **world.World) *
 			return nil
 		},
 	}
+	if dim == world.Overworld {
+		conf.SpawnProtection = srv.conf.SpawnProtection
+	}
+	if srv.conf.RandSeed != 0 {
+		id, _ := world.DimensionID(dim)
+		conf.RandSource = rand.NewSource(srv.conf.RandSeed + int64(id))
+	}
 	w := conf.New()
 	logger.Infof(`Opened world "%v".`, w.Name())
 	return w
@@ -515,6 +782,23 @@ func (srv *Server) registerTargetFunc() {
 	})
 }
 
+// gameRules converts the values of the game rules of a world to a list of
+// protocol.GameRule as sent in the StartGame packet. Natural regeneration is
+// always disabled, as players regenerate health server side.
+func gameRules(values map[string]any) []protocol.GameRule {
+	rules := []protocol.GameRule{{Name: "naturalregeneration", Value: false}}
+	for name, v := range values {
+		if n, ok := v.(int); ok {
+			if n < 0 {
+				n = 0
+			}
+			v = uint32(n)
+		}
+		rules = append(rules, protocol.GameRule{Name: name, Value: v})
+	}
+	return rules
+}
+
 // vec64To32 converts a mgl64.Vec3 to a mgl32.Vec3.
 func vec64To32(vec3 mgl64.Vec3) mgl32.Vec3 {
 	return mgl32.Vec3{float32(vec3[0]), float32(vec3[1]), float32(vec3[2])}
@@ -531,6 +815,7 @@ func (srv *Server) itemEntries() []protocol.ItemEntry {
 			RuntimeID: int16(rid),
 		})
 	}
+	entries = append(entries, srv.customBlockItems...)
 	for _, it := range world.CustomItems() {
 		name, _ := it.EncodeItem()
 		rid, _, _ := world.ItemRuntimeID(it)
@@ -594,3 +879,8 @@ func init() {
 
 	_ = nbt.Unmarshal(itemRuntimeIDData, &itemRuntimeIDs)
 }
+
+// noinspection ALL
+//
+//go:linkname world_finaliseBlockRegistry github.com/df-mc/dragonfly/server/world.finaliseBlockRegistry
+func world_finaliseBlockRegistry()
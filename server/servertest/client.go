@@ -0,0 +1,261 @@
+package servertest
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"sync"
+	"time"
+)
+
+// Client is a minimal headless Minecraft client connected to a server. It reads all packets sent to it in the
+// background, which may be waited for using Expect. A Client also tracks its own position and inventory, so that it
+// can move around and break blocks like a regular client would.
+type Client struct {
+	conn *minecraft.Conn
+
+	mu      sync.Mutex
+	packets []packet.Packet
+	cursor  int
+	notify  chan struct{}
+	err     error
+
+	pos        mgl64.Vec3
+	yaw, pitch float64
+	tick       uint64
+	inv        [9]protocol.ItemInstance
+	heldSlot   int
+}
+
+// Dial connects a new Client with the name passed to the server listening on the address passed. Dial blocks until
+// the Client has spawned in the world of the server or until 30 seconds pass, whichever comes first. The server must
+// have authentication disabled.
+func Dial(addr, name string) (*Client, error) {
+	conn, err := minecraft.Dialer{IdentityData: login.IdentityData{DisplayName: name}}.DialTimeout("raknet", addr, time.Second*30)
+	if err != nil {
+		return nil, fmt.Errorf("dial %v: %w", addr, err)
+	}
+	if err := conn.DoSpawnTimeout(time.Second * 30); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("spawn %v: %w", name, err)
+	}
+	data := conn.GameData()
+	c := &Client{
+		conn:   conn,
+		notify: make(chan struct{}),
+		pos:    vec32To64(data.PlayerPosition.Sub(mgl32.Vec3{0, 1.62})),
+		yaw:    float64(data.Yaw),
+		pitch:  float64(data.Pitch),
+	}
+	go c.read()
+	return c, nil
+}
+
+// Conn returns the underlying minecraft.Conn of the Client. It may be used to write packets that the Client has no
+// methods for. Packets should not be read from the minecraft.Conn directly: Expect should be used instead.
+func (c *Client) Conn() *minecraft.Conn {
+	return c.conn
+}
+
+// Name returns the name that the Client joined with.
+func (c *Client) Name() string {
+	return c.conn.IdentityData().DisplayName
+}
+
+// Position returns the current position of the Client. The position is updated when the Client moves and when it is
+// teleported by the server.
+func (c *Client) Position() mgl64.Vec3 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pos
+}
+
+// WritePacket writes a packet to the server and flushes it immediately.
+func (c *Client) WritePacket(pk packet.Packet) error {
+	if err := c.conn.WritePacket(pk); err != nil {
+		return err
+	}
+	return c.conn.Flush()
+}
+
+// Move moves the Client to the position passed, keeping its current rotation. The server may reject the movement, in
+// which case it teleports the Client back.
+func (c *Client) Move(pos mgl64.Vec3) error {
+	return c.MoveRotate(pos, c.rotation())
+}
+
+// MoveRotate moves the Client to the position passed and changes its yaw and pitch to the values passed.
+func (c *Client) MoveRotate(pos mgl64.Vec3, rot [2]float64) error {
+	c.mu.Lock()
+	c.pos, c.yaw, c.pitch = pos, rot[0], rot[1]
+	pk := c.authInput()
+	c.mu.Unlock()
+	return c.WritePacket(pk)
+}
+
+// BreakBlock breaks the block at the position passed, using the item currently held by the Client. The block is
+// broken instantly, regardless of the time it would normally take to break it.
+func (c *Client) BreakBlock(pos cube.Pos) error {
+	c.mu.Lock()
+	pk := c.authInput()
+	pk.InputData |= packet.InputFlagPerformItemInteraction
+	pk.ItemInteractionData = protocol.UseItemTransactionData{
+		ActionType:    protocol.UseItemActionBreakBlock,
+		BlockPosition: protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])},
+		HotBarSlot:    int32(c.heldSlot),
+		HeldItem:      c.inv[c.heldSlot],
+		Position:      vec64To32(c.pos.Add(mgl64.Vec3{0, 1.62})),
+	}
+	c.mu.Unlock()
+	return c.WritePacket(pk)
+}
+
+// Chat sends a chat message with the text passed to the server. Messages starting with a slash are sent as commands.
+func (c *Client) Chat(message string) error {
+	if len(message) > 0 && message[0] == '/' {
+		return c.WritePacket(&packet.CommandRequest{
+			CommandLine:   message,
+			CommandOrigin: protocol.CommandOrigin{Origin: protocol.CommandOriginPlayer},
+		})
+	}
+	return c.WritePacket(&packet.Text{TextType: packet.TextTypeChat, SourceName: c.Name(), Message: message, XUID: c.conn.IdentityData().XUID})
+}
+
+// Expect waits until a packet for which match returns true is received, or until the timeout passed expires. Packets
+// are matched in the order they were received, starting after the last packet returned by Expect, so that packets are
+// never matched twice. Packets that do not match are skipped. An error is returned if the timeout expires or if the
+// connection of the Client is closed before a matching packet is received.
+func (c *Client) Expect(timeout time.Duration, match func(pk packet.Packet) bool) (packet.Packet, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		c.mu.Lock()
+		for ; c.cursor < len(c.packets); c.cursor++ {
+			if pk := c.packets[c.cursor]; match(pk) {
+				c.cursor++
+				c.mu.Unlock()
+				return pk, nil
+			}
+		}
+		notify, err := c.notify, c.err
+		c.mu.Unlock()
+
+		if err != nil {
+			return nil, fmt.Errorf("expect packet: %w", err)
+		}
+		select {
+		case <-notify:
+		case <-deadline.C:
+			return nil, fmt.Errorf("expect packet: no matching packet received within %v", timeout)
+		}
+	}
+}
+
+// Expect waits until a packet of type T is received by the Client passed for which match returns true, or until the
+// timeout passed expires. match may be nil to return the first packet of type T. Expect otherwise behaves like
+// Client.Expect.
+func Expect[T packet.Packet](c *Client, timeout time.Duration, match func(pk T) bool) (T, error) {
+	pk, err := c.Expect(timeout, func(pk packet.Packet) bool {
+		v, ok := pk.(T)
+		return ok && (match == nil || match(v))
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return pk.(T), nil
+}
+
+// Packets returns all packets received by the Client so far, in the order that they were received.
+func (c *Client) Packets() []packet.Packet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]packet.Packet(nil), c.packets...)
+}
+
+// Close disconnects the Client from the server.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// read reads packets from the connection of the Client until it is closed, storing them so that they may be
+// matched by Expect.
+func (c *Client) read() {
+	for {
+		pk, err := c.conn.ReadPacket()
+		c.mu.Lock()
+		if err != nil {
+			c.err = err
+			close(c.notify)
+			c.mu.Unlock()
+			return
+		}
+		c.handlePacket(pk)
+		c.packets = append(c.packets, pk)
+		close(c.notify)
+		c.notify = make(chan struct{})
+		c.mu.Unlock()
+	}
+}
+
+// handlePacket updates the state of the Client using a packet received from the server. It must be called while
+// holding c.mu.
+func (c *Client) handlePacket(pk packet.Packet) {
+	switch pk := pk.(type) {
+	case *packet.MovePlayer:
+		if pk.EntityRuntimeID == c.conn.GameData().EntityRuntimeID {
+			c.pos = vec32To64(pk.Position.Sub(mgl32.Vec3{0, 1.62}))
+			c.yaw, c.pitch = float64(pk.Yaw), float64(pk.Pitch)
+		}
+	case *packet.InventoryContent:
+		if pk.WindowID == protocol.WindowIDInventory {
+			for i := 0; i < len(c.inv) && i < len(pk.Content); i++ {
+				c.inv[i] = pk.Content[i]
+			}
+		}
+	case *packet.InventorySlot:
+		if pk.WindowID == protocol.WindowIDInventory && pk.Slot < uint32(len(c.inv)) {
+			c.inv[pk.Slot] = pk.NewItem
+		}
+	case *packet.MobEquipment:
+		if pk.EntityRuntimeID == c.conn.GameData().EntityRuntimeID && int(pk.HotBarSlot) < len(c.inv) {
+			c.heldSlot = int(pk.HotBarSlot)
+		}
+	}
+}
+
+// rotation returns the current yaw and pitch of the Client.
+func (c *Client) rotation() [2]float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return [2]float64{c.yaw, c.pitch}
+}
+
+// authInput creates a packet.PlayerAuthInput holding the current position and rotation of the Client. It must be
+// called while holding c.mu.
+func (c *Client) authInput() *packet.PlayerAuthInput {
+	c.tick++
+	return &packet.PlayerAuthInput{
+		Pitch:    float32(c.pitch),
+		Yaw:      float32(c.yaw),
+		HeadYaw:  float32(c.yaw),
+		Position: vec64To32(c.pos.Add(mgl64.Vec3{0, 1.62})),
+		PlayMode: packet.PlayModeNormal,
+		Tick:     c.tick,
+	}
+}
+
+// vec32To64 converts a mgl32.Vec3 to a mgl64.Vec3.
+func vec32To64(vec3 mgl32.Vec3) mgl64.Vec3 {
+	return mgl64.Vec3{float64(vec3[0]), float64(vec3[1]), float64(vec3[2])}
+}
+
+// vec64To32 converts a mgl64.Vec3 to a mgl32.Vec3.
+func vec64To32(vec3 mgl64.Vec3) mgl32.Vec3 {
+	return mgl32.Vec3{float32(vec3[0]), float32(vec3[1]), float32(vec3[2])}
+}
@@ -0,0 +1,82 @@
+// Package servertest provides utilities for end-to-end testing of a server. It can start a Server listening on a
+// local address and connect headless clients to it, which may move around, break blocks and wait for packets sent to
+// them by the server.
+package servertest
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"net"
+)
+
+// Server is a server.Server that listens on a random port of the loopback interface, so that Clients may connect to
+// it using Dial.
+type Server struct {
+	*server.Server
+	addr net.Addr
+}
+
+// NewServer creates a Server using the server.Config passed and starts listening on a random port of the loopback
+// interface. Authentication is disabled for the Server and any listeners in the server.Config are replaced. Players
+// joining are accepted automatically, after which f is called for each of them. f may be nil. An error is returned if
+// the Server could not start listening.
+func NewServer(conf server.Config, f server.HandleFunc) (*Server, error) {
+	l, err := minecraft.ListenConfig{
+		MaximumPlayers:         conf.MaxPlayers,
+		AuthenticationDisabled: true,
+		ResourcePacks:          conf.Resources,
+		TexturePacksRequired:   conf.ResourcesRequired,
+	}.Listen("raknet", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("create minecraft listener: %w", err)
+	}
+	conf.AuthDisabled = true
+	conf.Listeners = []func(conf server.Config) (server.Listener, error){
+		func(server.Config) (server.Listener, error) {
+			return listener{l}, nil
+		},
+	}
+	s := &Server{Server: conf.New(), addr: l.Addr()}
+	s.Listen()
+	go func() {
+		for s.Accept(f) {
+			// Keep accepting players until the server is closed.
+		}
+	}()
+	return s, nil
+}
+
+// Addr returns the address that the Server is listening on. It may be passed to Dial to connect a Client to the
+// Server. An empty string is returned if the Server is not listening on any address.
+func (s *Server) Addr() string {
+	if s.addr == nil {
+		return ""
+	}
+	return s.addr.String()
+}
+
+// Dial connects a new Client with the name passed to the Server. It is a shorthand for Dial(s.Addr(), name).
+func (s *Server) Dial(name string) (*Client, error) {
+	return Dial(s.Addr(), name)
+}
+
+// listener is a server.Listener implementation that wraps around a minecraft.Listener.
+type listener struct {
+	*minecraft.Listener
+}
+
+// Accept blocks until the next connection is established and returns it.
+func (l listener) Accept() (session.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return conn.(session.Conn), nil
+}
+
+// Disconnect disconnects a connection from the listener with a reason.
+func (l listener) Disconnect(conn session.Conn, reason string) error {
+	return l.Listener.Disconnect(conn.(*minecraft.Conn), reason)
+}
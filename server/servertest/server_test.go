@@ -0,0 +1,51 @@
+package servertest_test
+
+import (
+	"context"
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/servertest"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChat(t *testing.T) {
+	log := logrus.New()
+	log.Level = logrus.ErrorLevel
+
+	joined := make(chan *player.Player, 1)
+	srv, err := servertest.NewServer(server.Config{Log: log}, func(p *player.Player) {
+		joined <- p
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	defer srv.Close(context.Background())
+
+	c, err := srv.Dial("Tester")
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer c.Close()
+
+	select {
+	case p := <-joined:
+		if p.Name() != "Tester" {
+			t.Fatalf("joined player has name %q, expected %q", p.Name(), "Tester")
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatal("player was not accepted by the server")
+	}
+
+	if err := c.Chat("Hello <red>world</red>"); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if _, err := servertest.Expect(c, time.Second*10, func(pk *packet.Text) bool {
+		return strings.Contains(pk.Message, "Hello <red>world</red>")
+	}); err != nil {
+		t.Fatalf("chat message was not received: %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package session
+
+import (
+	"encoding/binary"
+	"github.com/cespare/xxhash"
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"sync"
+)
+
+// AntiXrayMode is a mode of the anti-xray engine of a Session. The anti-xray engine obfuscates ores that are not
+// exposed to any non-solid blocks in chunks sent to the client, so that modified clients or resource packs cannot
+// be used to find them. Obfuscated blocks are revealed to the client as soon as a block next to them is updated.
+type AntiXrayMode int
+
+const (
+	// AntiXrayDisabled disables the anti-xray engine. Chunks are sent to the client without modification.
+	AntiXrayDisabled AntiXrayMode = iota
+	// AntiXrayHide replaces ores that are not exposed with the block they are found in, such as stone, deepslate
+	// or netherrack.
+	AntiXrayHide
+	// AntiXrayRandom replaces both ores and the blocks they are found in with random ores if they are not exposed.
+	// This makes it impossible to tell real ores apart from fake ones, at the cost of chunks taking up more
+	// bandwidth.
+	AntiXrayRandom
+)
+
+// Valid checks if the AntiXrayMode is one of the modes defined above.
+func (m AntiXrayMode) Valid() bool {
+	return m >= AntiXrayDisabled && m <= AntiXrayRandom
+}
+
+// antiXrayTable holds the runtime IDs of blocks relevant to the anti-xray engine.
+type antiXrayTable struct {
+	// hosts maps the runtime ID of an ore to the runtime ID of the block that it is found in.
+	hosts map[uint32]uint32
+	// ores maps the runtime ID of a host block to the runtime IDs of all ores found in it.
+	ores map[uint32][]uint32
+	// opaque holds, indexed by runtime ID, if a block fully blocks the view through it.
+	opaque []bool
+}
+
+var (
+	antiXrayOnce sync.Once
+	antiXray     antiXrayTable
+)
+
+// antiXrayTables returns the antiXrayTable used by the anti-xray engine. The table is created the first time the
+// function is called, at which point all blocks must have been registered.
+func antiXrayTables() antiXrayTable {
+	antiXrayOnce.Do(func() {
+		t := antiXrayTable{hosts: map[uint32]uint32{}, ores: map[uint32][]uint32{}}
+		add := func(host world.Block, ores ...world.Block) {
+			hostRID := world.BlockRuntimeID(host)
+			for _, ore := range ores {
+				oreRID := world.BlockRuntimeID(ore)
+				t.hosts[oreRID] = hostRID
+				t.ores[hostRID] = append(t.ores[hostRID], oreRID)
+			}
+		}
+		for _, o := range block.OreTypes() {
+			var host world.Block = block.Stone{}
+			if o == block.DeepslateOre() {
+				host = block.Deepslate{Axis: cube.Y}
+			}
+			add(host, block.CoalOre{Type: o}, block.CopperOre{Type: o}, block.DiamondOre{Type: o}, block.EmeraldOre{Type: o},
+				block.GoldOre{Type: o}, block.IronOre{Type: o}, block.LapisOre{Type: o})
+		}
+		add(block.Netherrack{}, block.NetherGoldOre{}, block.NetherQuartzOre{}, block.AncientDebris{})
+		for rid := uint32(0); ; rid++ {
+			b, ok := world.BlockByRuntimeID(rid)
+			if !ok {
+				break
+			}
+			d, ok := b.(block.LightDiffuser)
+			t.opaque = append(t.opaque, !ok || d.LightDiffusionLevel() == 15)
+		}
+		antiXray = t
+	})
+	return antiXray
+}
+
+// encodeChunk encodes the chunk at the position passed for sending it over the network, obfuscating all of its
+// sub chunks according to the AntiXrayMode of the Session. The data returned is shared with other sessions viewing
+// the chunk and must not be modified.
+func (s *Session) encodeChunk(pos world.ChunkPos, c *chunk.Chunk) chunk.SerialisedData {
+	d := chunk.SerialisedData{SubChunks: make([][]byte, len(c.Sub()))}
+	for i := range c.Sub() {
+		d.SubChunks[i] = s.encodeSubChunk(pos, c, int16(i))
+	}
+	d.Biomes = s.c.World().EncodedBiomes(pos, c)
+	return d
+}
+
+// encodeSubChunk encodes the sub chunk at index ind of the chunk at the position passed for sending it over the
+// network. The sub chunk is obfuscated according to the AntiXrayMode of the Session. Encoded sub chunks are cached
+// by the world.World of the Session, so that sessions with the same AntiXrayMode share the same encoding. The data
+// returned must therefore not be modified.
+func (s *Session) encodeSubChunk(pos world.ChunkPos, c *chunk.Chunk, ind int16) []byte {
+	if s.antiXray == AntiXrayDisabled {
+		return s.c.World().EncodedSubChunk(pos, c, ind, int(AntiXrayDisabled), nil)
+	}
+	return s.c.World().EncodedSubChunk(pos, c, ind, int(s.antiXray), func() []byte {
+		sub, ok := s.antiXray.obfuscate(pos, c, ind)
+		if !ok {
+			return chunk.EncodeSubChunk(c, chunk.NetworkEncoding, int(ind))
+		}
+		return chunk.EncodeSub(sub, c.Range(), chunk.NetworkEncoding, int(ind))
+	})
+}
+
+// obfuscate returns a copy of the sub chunk at index ind of the chunk at the position passed, with all blocks that
+// are not exposed obfuscated. If no blocks needed to be obfuscated, obfuscate returns false.
+func (m AntiXrayMode) obfuscate(pos world.ChunkPos, c *chunk.Chunk, ind int16) (*chunk.SubChunk, bool) {
+	sub := c.Sub()[ind]
+	if sub.Empty() {
+		return nil, false
+	}
+	t, baseY := antiXrayTables(), c.SubY(ind)
+
+	var obfuscated *chunk.SubChunk
+	for x := uint8(0); x < 16; x++ {
+		for y := uint8(0); y < 16; y++ {
+			for z := uint8(0); z < 16; z++ {
+				rid := sub.Block(x, y, z, 0)
+				replacement, ok := m.replacement(t, rid, cube.Pos{int(pos[0])<<4 | int(x), int(baseY) + int(y), int(pos[1])<<4 | int(z)})
+				if !ok || replacement == rid || t.exposed(c, x, baseY+int16(y), z) {
+					continue
+				}
+				if obfuscated == nil {
+					obfuscated = sub.Clone()
+				}
+				obfuscated.SetBlock(x, y, z, 0, replacement)
+			}
+		}
+	}
+	return obfuscated, obfuscated != nil
+}
+
+// replacement returns the runtime ID of the block that the block with the runtime ID passed should be replaced with
+// if it is not exposed. If the block should not be obfuscated at all, replacement returns false.
+func (m AntiXrayMode) replacement(t antiXrayTable, rid uint32, pos cube.Pos) (uint32, bool) {
+	host, ok := t.hosts[rid]
+	if m == AntiXrayHide || !ok && len(t.ores[rid]) == 0 {
+		return host, ok
+	}
+	if !ok {
+		host = rid
+	}
+	// The ore is chosen based on the position of the block so that the same chunk is always obfuscated the same way,
+	// which keeps the blob cache of the client effective.
+	var b [12]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(pos[0]))
+	binary.LittleEndian.PutUint32(b[4:], uint32(pos[1]))
+	binary.LittleEndian.PutUint32(b[8:], uint32(pos[2]))
+
+	ores := t.ores[host]
+	return ores[xxhash.Sum64(b[:])%uint64(len(ores))], true
+}
+
+// exposed checks if the block at the position passed in the chunk is next to any block that is not opaque. Blocks at
+// the horizontal edges of a chunk are always considered exposed.
+func (t antiXrayTable) exposed(c *chunk.Chunk, x uint8, y int16, z uint8) bool {
+	if x == 0 || x == 15 || z == 0 || z == 15 || y <= int16(c.Range()[0]) || y >= int16(c.Range()[1]) {
+		return true
+	}
+	return !t.isOpaque(c.Block(x-1, y, z, 0)) || !t.isOpaque(c.Block(x+1, y, z, 0)) ||
+		!t.isOpaque(c.Block(x, y-1, z, 0)) || !t.isOpaque(c.Block(x, y+1, z, 0)) ||
+		!t.isOpaque(c.Block(x, y, z-1, 0)) || !t.isOpaque(c.Block(x, y, z+1, 0))
+}
+
+// isOpaque checks if the block with the runtime ID passed fully blocks the view through it.
+func (t antiXrayTable) isOpaque(rid uint32) bool {
+	return rid < uint32(len(t.opaque)) && t.opaque[rid]
+}
+
+// revealAround queues the blocks around the position passed to be revealed to the client. This is done when a block
+// is updated, as the blocks around it might have been obfuscated while having become exposed.
+func (s *Session) revealAround(pos cube.Pos) {
+	if s.antiXray == AntiXrayDisabled {
+		return
+	}
+	s.revealMu.Lock()
+	s.reveal = append(s.reveal, pos)
+	s.revealMu.Unlock()
+}
+
+// revealBlocks sends the actual blocks around positions queued using revealAround to the client if they could have
+// been obfuscated.
+func (s *Session) revealBlocks() {
+	s.revealMu.Lock()
+	queue := s.reveal
+	s.reveal = nil
+	s.revealMu.Unlock()
+	if len(queue) == 0 {
+		return
+	}
+
+	t, w := antiXrayTables(), s.c.World()
+	revealed := make(map[cube.Pos]struct{}, len(queue)*6)
+	for _, pos := range queue {
+		for _, face := range cube.Faces() {
+			side := pos.Side(face)
+			if _, ok := revealed[side]; ok || side.OutOfBounds(w.Range()) {
+				continue
+			}
+			revealed[side] = struct{}{}
+
+			rid := world.BlockRuntimeID(w.Block(side))
+			if _, ore := t.hosts[rid]; ore || (s.antiXray == AntiXrayRandom && len(t.ores[rid]) != 0) {
+				s.writePacket(&packet.UpdateBlock{
+					Position:          protocol.BlockPos{int32(side[0]), int32(side[1]), int32(side[2])},
+					NewBlockRuntimeID: rid,
+					Flags:             packet.BlockUpdateNetwork,
+				})
+			}
+		}
+	}
+}
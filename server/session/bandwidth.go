@@ -0,0 +1,106 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"sync"
+	"time"
+)
+
+// bandwidth tracks the amount of bytes sent to a connection every second, so that low priority traffic, such as
+// chunks and particles, may be held back when a budget is exceeded. This keeps more important packets, such as those
+// related to movement and combat, responsive on connections with limited bandwidth.
+type bandwidth struct {
+	// budget is the maximum amount of bytes per second that may be sent before low priority traffic is held back. If
+	// budget is 0 or lower, no bandwidth is tracked at all.
+	budget int
+
+	mu               sync.Mutex
+	windowStart      time.Time
+	sent, lastSecond int
+}
+
+// track tracks the size of the packet passed as being sent to the connection.
+func (b *bandwidth) track(pk packet.Packet) {
+	if b == nil || b.budget <= 0 {
+		return
+	}
+	b.add(packetSize(pk))
+}
+
+// add tracks n bytes as being sent to the connection.
+func (b *bandwidth) add(n int) {
+	if b == nil || b.budget <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	b.sent += n
+}
+
+// exceeded checks if the budget of the bandwidth was exceeded in the current second. If this is the case, low priority
+// traffic should not be sent.
+func (b *bandwidth) exceeded() bool {
+	if b == nil || b.budget <= 0 {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	return b.sent >= b.budget
+}
+
+// bytesPerSecond returns the amount of bytes that were sent in the last full second. If no budget is set, 0 is
+// always returned.
+func (b *bandwidth) bytesPerSecond() int {
+	if b == nil || b.budget <= 0 {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	return b.lastSecond
+}
+
+// advance starts a new window if the current one has lasted for a second or longer. advance must be called with
+// b.mu locked.
+func (b *bandwidth) advance() {
+	if now := time.Now(); now.Sub(b.windowStart) >= time.Second {
+		if now.Sub(b.windowStart) >= time.Second*2 {
+			// No packets were sent in the previous second at all.
+			b.sent = 0
+		}
+		b.windowStart, b.lastSecond, b.sent = now, b.sent, 0
+	}
+}
+
+// estimatedPacketSize is the size in bytes assumed for packets of which the size is not known without encoding them.
+const estimatedPacketSize = 32
+
+// packetSize returns the size in bytes of the packet passed once encoded, excluding compression and encryption. It is
+// used only for packets that are not written through the encoder of a Session, which reports the exact size of every
+// packet it writes. packetSize never encodes packets: Sizes of packets carrying chunk data are computed directly from
+// the data, and other packets, which are generally small, are assumed to be estimatedPacketSize bytes.
+func packetSize(pk packet.Packet) int {
+	var buf [64]byte
+	if b, ok := appendPacket(buf[:0], pk); ok {
+		return len(b)
+	}
+	switch pk := pk.(type) {
+	case *packet.LevelChunk:
+		return len(pk.RawPayload) + len(pk.BlobHashes)*8 + 16
+	case *packet.SubChunk:
+		n := 16
+		for _, entry := range pk.SubChunkEntries {
+			n += len(entry.RawPayload) + len(entry.HeightMapData) + 12
+		}
+		return n
+	case *packet.ClientCacheMissResponse:
+		n := 4
+		for _, blob := range pk.Blobs {
+			n += len(blob.Payload) + 8
+		}
+		return n
+	}
+	return estimatedPacketSize
+}
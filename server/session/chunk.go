@@ -46,7 +46,7 @@ func (s *Session) ViewSubChunks(center world.SubChunkPos, offsets []protocol.Sub
 			continue
 		}
 		col.Lock()
-		entries = append(entries, s.subChunkEntry(offset, ind, col, transaction))
+		entries = append(entries, s.subChunkEntry(world.ChunkPos{center.X() + int32(offset[0]), center.Z() + int32(offset[2])}, offset, ind, col, transaction))
 		col.Unlock()
 	}
 	if s.conn.ClientCacheEnabled() && len(transaction) > 0 {
@@ -63,7 +63,7 @@ func (s *Session) ViewSubChunks(center world.SubChunkPos, offsets []protocol.Sub
 	})
 }
 
-func (s *Session) subChunkEntry(offset protocol.SubChunkOffset, ind int16, col *world.Column, transaction map[uint64]struct{}) protocol.SubChunkEntry {
+func (s *Session) subChunkEntry(pos world.ChunkPos, offset protocol.SubChunkOffset, ind int16, col *world.Column, transaction map[uint64]struct{}) protocol.SubChunkEntry {
 	chunkMap := col.Chunk.HeightMap()
 	subMapType, subMap := byte(protocol.HeightMapDataHasData), make([]int8, 256)
 	higher, lower := true, true
@@ -96,7 +96,7 @@ func (s *Session) subChunkEntry(offset protocol.SubChunkOffset, ind int16, col *
 		}
 	}
 
-	serialisedSubChunk := chunk.EncodeSubChunk(col.Chunk, chunk.NetworkEncoding, int(ind))
+	serialisedSubChunk := s.encodeSubChunk(pos, col.Chunk, ind)
 
 	blockEntityBuf := bytes.NewBuffer(nil)
 	enc := nbt.NewEncoderWithEncoding(blockEntityBuf, nbt.NetworkLittleEndian)
@@ -110,7 +110,7 @@ func (s *Session) subChunkEntry(offset protocol.SubChunkOffset, ind int16, col *
 
 	entry := protocol.SubChunkEntry{
 		Result:        protocol.SubChunkResultSuccess,
-		RawPayload:    append(serialisedSubChunk, blockEntityBuf.Bytes()...),
+		RawPayload:    append(append([]byte(nil), serialisedSubChunk...), blockEntityBuf.Bytes()...),
 		HeightMapType: subMapType,
 		HeightMapData: subMap,
 		Offset:        offset,
@@ -130,7 +130,7 @@ func (s *Session) subChunkEntry(offset protocol.SubChunkOffset, ind int16, col *
 // data that the client doesn't yet have will be sent over the network.
 func (s *Session) sendBlobHashes(pos world.ChunkPos, c *chunk.Chunk, blockEntities map[cube.Pos]world.Block) {
 	if subChunkRequests {
-		biomes := chunk.EncodeBiomes(c, chunk.NetworkEncoding)
+		biomes := s.c.World().EncodedBiomes(pos, c)
 		if hash := xxhash.Sum64(biomes); s.trackBlob(hash, biomes) {
 			s.writePacket(&packet.LevelChunk{
 				SubChunkCount:   protocol.SubChunkRequestModeLimited,
@@ -145,7 +145,7 @@ func (s *Session) sendBlobHashes(pos world.ChunkPos, c *chunk.Chunk, blockEntiti
 	}
 
 	var (
-		data   = chunk.Encode(c, chunk.NetworkEncoding)
+		data   = s.encodeChunk(pos, c)
 		count  = uint32(len(data.SubChunks))
 		blobs  = append(data.SubChunks, data.Biomes)
 		hashes = make([]uint64, len(blobs))
@@ -196,12 +196,12 @@ func (s *Session) sendNetworkChunk(pos world.ChunkPos, c *chunk.Chunk, blockEnti
 			SubChunkCount:   protocol.SubChunkRequestModeLimited,
 			Position:        protocol.ChunkPos(pos),
 			HighestSubChunk: c.HighestFilledSubChunk(),
-			RawPayload:      append(chunk.EncodeBiomes(c, chunk.NetworkEncoding), 0),
+			RawPayload:      append(append([]byte(nil), s.c.World().EncodedBiomes(pos, c)...), 0),
 		})
 		return
 	}
 
-	data := chunk.Encode(c, chunk.NetworkEncoding)
+	data := s.encodeChunk(pos, c)
 	chunkBuf := bytes.NewBuffer(nil)
 	for _, s := range data.SubChunks {
 		_, _ = chunkBuf.Write(s)
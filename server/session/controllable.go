@@ -37,6 +37,7 @@ type Controllable interface {
 	ExecuteCommand(commandLine string)
 	GameMode() world.GameMode
 	SetGameMode(mode world.GameMode)
+	ChangeDifficulty(d world.Difficulty)
 	Effects() []effect.Effect
 
 	UseItem()
@@ -59,6 +60,8 @@ type Controllable interface {
 	Respawn()
 	Dead() bool
 
+	Wake()
+
 	StartSneaking()
 	Sneaking() bool
 	StopSneaking()
@@ -75,6 +78,7 @@ type Controllable interface {
 	Gliding() bool
 	StopGliding()
 	Jump()
+	Stand()
 
 	StartBreaking(pos cube.Pos, face cube.Face)
 	ContinueBreaking(face cube.Face)
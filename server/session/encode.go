@@ -0,0 +1,228 @@
+package session
+
+import (
+	"encoding/binary"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"math"
+	"sync"
+)
+
+// encoderBufferSize is the size of the buffers that an encoder encodes packets into.
+const encoderBufferSize = 4096
+
+// rawWriter is implemented by Conns that can write packets that were already encoded, such as *minecraft.Conn. The
+// byte slice passed must hold the header of the packet followed by its payload.
+type rawWriter interface {
+	Write(b []byte) (n int, err error)
+}
+
+// encoder encodes the packets that are sent most often, such as movement and block updates, without going through
+// packet.Packet.Marshal. Encoding these packets with Marshal allocates a protocol.Writer and a copy of the encoded
+// packet for every packet sent, which, with many players and entities, makes up a large part of the CPU time of a
+// server. An encoder instead appends packets to a buffer that is shared by all packets of a Session and replaced
+// only once it is full. Other packets are encoded into the same buffer using packet.Packet.Marshal, so that the size
+// of every packet written is known without encoding it twice.
+type encoder struct {
+	// w is the rawWriter that encoded packets are written to. If nil, the encoder is disabled and packets must be
+	// written using Conn.WritePacket.
+	w rawWriter
+	// shieldID is the runtime ID of the shield item, which is needed to encode item stacks.
+	shieldID int32
+
+	mu sync.Mutex
+	// buf holds the packets encoded. The Conn holds on to packets written until they are flushed, so buf is never
+	// written to again below its length. Instead, a new buffer is allocated once buf is full.
+	buf []byte
+}
+
+// newEncoder returns an encoder that writes packets to the Conn passed. The encoder is disabled if the Conn cannot
+// write encoded packets or if it might use a different protocol than the one implemented by the encoder: Packets
+// encoded by an encoder would otherwise bypass the conversion to older protocols.
+func newEncoder(conn Conn) *encoder {
+	w, ok := conn.(rawWriter)
+	if !ok || conn.ClientData().GameVersion != protocol.CurrentVersion {
+		return &encoder{}
+	}
+	shieldID, _, _ := world.ItemRuntimeID(item.Shield{})
+	return &encoder{w: w, shieldID: shieldID}
+}
+
+// write encodes the packet passed and writes it to the Conn of the encoder. The amount of bytes written is returned.
+// If the encoder is disabled, write returns false and the packet must be written using Conn.WritePacket.
+func (e *encoder) write(pk packet.Packet) (int, bool) {
+	if e == nil || e.w == nil {
+		return 0, false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if cap(e.buf)-len(e.buf) < 128 {
+		e.buf = make([]byte, 0, encoderBufferSize)
+	}
+	start := len(e.buf)
+	buf, ok := appendPacket(e.buf, pk)
+	if !ok {
+		// No fast path for this packet, so marshal it into the buffer instead. Appending to the buffer never
+		// changes the packets already written to it, even if it is grown.
+		w := &appendWriter{b: appendVaruint32(e.buf, pk.ID())}
+		pk.Marshal(protocol.NewWriter(w, e.shieldID))
+		buf = w.b
+	}
+	e.buf = buf
+	b := e.buf[start:len(e.buf):len(e.buf)]
+	_, _ = e.w.Write(b)
+	return len(b), true
+}
+
+// appendWriter implements io.Writer and io.ByteWriter by appending all data written to a byte slice.
+type appendWriter struct{ b []byte }
+
+// Write ...
+func (w *appendWriter) Write(p []byte) (int, error) {
+	w.b = append(w.b, p...)
+	return len(p), nil
+}
+
+// WriteByte ...
+func (w *appendWriter) WriteByte(c byte) error {
+	w.b = append(w.b, c)
+	return nil
+}
+
+// appendPacket appends the header and payload of the packet passed to b if it is of one of the types that have a
+// fast path. If not, b is returned unchanged and the bool returned is false.
+func appendPacket(b []byte, pk packet.Packet) ([]byte, bool) {
+	switch pk := pk.(type) {
+	case *packet.MovePlayer:
+		return appendMovePlayer(b, pk), true
+	case *packet.MoveActorAbsolute:
+		return appendMoveActorAbsolute(b, pk), true
+	case *packet.MoveActorDelta:
+		return appendMoveActorDelta(b, pk), true
+	case *packet.LevelSoundEvent:
+		return appendLevelSoundEvent(b, pk), true
+	case *packet.UpdateBlock:
+		return appendUpdateBlock(b, pk), true
+	}
+	return b, false
+}
+
+// appendMovePlayer appends a packet.MovePlayer to b.
+func appendMovePlayer(b []byte, pk *packet.MovePlayer) []byte {
+	b = appendVaruint32(b, packet.IDMovePlayer)
+	b = appendVaruint64(b, pk.EntityRuntimeID)
+	b = appendVec3(b, pk.Position)
+	b = appendFloat32(b, pk.Pitch)
+	b = appendFloat32(b, pk.Yaw)
+	b = appendFloat32(b, pk.HeadYaw)
+	b = append(b, pk.Mode, boolByte(pk.OnGround))
+	b = appendVaruint64(b, pk.RiddenEntityRuntimeID)
+	if pk.Mode == packet.MoveModeTeleport {
+		b = binary.LittleEndian.AppendUint32(b, uint32(pk.TeleportCause))
+		b = binary.LittleEndian.AppendUint32(b, uint32(pk.TeleportSourceEntityType))
+	}
+	return appendVaruint64(b, pk.Tick)
+}
+
+// appendMoveActorAbsolute appends a packet.MoveActorAbsolute to b.
+func appendMoveActorAbsolute(b []byte, pk *packet.MoveActorAbsolute) []byte {
+	b = appendVaruint32(b, packet.IDMoveActorAbsolute)
+	b = appendVaruint64(b, pk.EntityRuntimeID)
+	b = append(b, pk.Flags)
+	b = appendVec3(b, pk.Position)
+	return append(b, byteFloat(pk.Rotation[0]), byteFloat(pk.Rotation[1]), byteFloat(pk.Rotation[2]))
+}
+
+// appendMoveActorDelta appends a packet.MoveActorDelta to b. Only the fields of which the flag is set are written.
+func appendMoveActorDelta(b []byte, pk *packet.MoveActorDelta) []byte {
+	b = appendVaruint32(b, packet.IDMoveActorDelta)
+	b = appendVaruint64(b, pk.EntityRuntimeID)
+	b = binary.LittleEndian.AppendUint16(b, pk.Flags)
+	if pk.Flags&packet.MoveActorDeltaFlagHasX != 0 {
+		b = appendFloat32(b, pk.Position[0])
+	}
+	if pk.Flags&packet.MoveActorDeltaFlagHasY != 0 {
+		b = appendFloat32(b, pk.Position[1])
+	}
+	if pk.Flags&packet.MoveActorDeltaFlagHasZ != 0 {
+		b = appendFloat32(b, pk.Position[2])
+	}
+	if pk.Flags&packet.MoveActorDeltaFlagHasRotX != 0 {
+		b = append(b, byteFloat(pk.Rotation[0]))
+	}
+	if pk.Flags&packet.MoveActorDeltaFlagHasRotY != 0 {
+		b = append(b, byteFloat(pk.Rotation[1]))
+	}
+	if pk.Flags&packet.MoveActorDeltaFlagHasRotZ != 0 {
+		b = append(b, byteFloat(pk.Rotation[2]))
+	}
+	return b
+}
+
+// appendLevelSoundEvent appends a packet.LevelSoundEvent to b.
+func appendLevelSoundEvent(b []byte, pk *packet.LevelSoundEvent) []byte {
+	b = appendVaruint32(b, packet.IDLevelSoundEvent)
+	b = appendVaruint32(b, pk.SoundType)
+	b = appendVec3(b, pk.Position)
+	b = appendVarint32(b, pk.ExtraData)
+	b = appendVaruint32(b, uint32(len(pk.EntityType)))
+	b = append(b, pk.EntityType...)
+	return append(b, boolByte(pk.BabyMob), boolByte(pk.DisableRelativeVolume))
+}
+
+// appendUpdateBlock appends a packet.UpdateBlock to b.
+func appendUpdateBlock(b []byte, pk *packet.UpdateBlock) []byte {
+	b = appendVaruint32(b, packet.IDUpdateBlock)
+	b = appendVarint32(b, pk.Position[0])
+	b = appendVaruint32(b, uint32(pk.Position[1]))
+	b = appendVarint32(b, pk.Position[2])
+	b = appendVaruint32(b, pk.NewBlockRuntimeID)
+	b = appendVaruint32(b, pk.Flags)
+	return appendVaruint32(b, pk.Layer)
+}
+
+// appendVaruint32 appends a uint32 as 1-5 bytes to b.
+func appendVaruint32(b []byte, x uint32) []byte {
+	for x >= 0x80 {
+		b = append(b, byte(x)|0x80)
+		x >>= 7
+	}
+	return append(b, byte(x))
+}
+
+// appendVarint32 appends a zigzag encoded int32 as 1-5 bytes to b.
+func appendVarint32(b []byte, x int32) []byte {
+	ux := uint32(x) << 1
+	if x < 0 {
+		ux = ^ux
+	}
+	return appendVaruint32(b, ux)
+}
+
+// appendVaruint64 appends a uint64 as 1-10 bytes to b.
+func appendVaruint64(b []byte, x uint64) []byte {
+	for x >= 0x80 {
+		b = append(b, byte(x)|0x80)
+		x >>= 7
+	}
+	return append(b, byte(x))
+}
+
+// appendFloat32 appends a little endian float32 to b.
+func appendFloat32(b []byte, x float32) []byte {
+	return binary.LittleEndian.AppendUint32(b, math.Float32bits(x))
+}
+
+// appendVec3 appends an mgl32.Vec3 as 3 little endian float32s to b.
+func appendVec3(b []byte, x mgl32.Vec3) []byte {
+	return appendFloat32(appendFloat32(appendFloat32(b, x[0]), x[1]), x[2])
+}
+
+// byteFloat converts a rotation in degrees to the single byte it is encoded as.
+func byteFloat(x float32) byte {
+	return byte(x / (360.0 / 256.0))
+}
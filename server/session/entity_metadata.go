@@ -1,15 +1,18 @@
 package session
 
 import (
+	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/entity"
 	"github.com/df-mc/dragonfly/server/entity/effect"
 	"github.com/df-mc/dragonfly/server/internal/nbtconv"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/item/potion"
 	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl32"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"math"
+	"reflect"
 	"time"
 )
 
@@ -27,142 +30,217 @@ func (s *Session) parseEntityMetadata(e world.Entity) protocol.EntityMetadata {
 
 	m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagHasGravity)
 	m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagClimb)
-	if g, ok := e.Type().(glint); ok && g.Glint() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagEnchanted)
-	}
-	if _, ok := e.Type().(entity.LingeringPotionType); ok {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagLingering)
-	}
+	s.addSpecificMetadata(e.Type(), m)
 	s.addSpecificMetadata(e, m)
 	if ent, ok := e.(*entity.Ent); ok {
 		s.addSpecificMetadata(ent.Behaviour(), m)
+	} else if mob, ok := e.(*entity.Mob); ok && mob.Behaviour() != nil {
+		s.addSpecificMetadata(mob.Behaviour(), m)
 	}
 	return m
 }
 
-func (s *Session) addSpecificMetadata(e any, m protocol.EntityMetadata) {
-	if sn, ok := e.(sneaker); ok && sn.Sneaking() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagSneaking)
-	}
-	if sp, ok := e.(sprinter); ok && sp.Sprinting() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagSprinting)
+// storeEntityMetadata stores the metadata passed as the metadata last sent to the client for the entity passed.
+func (s *Session) storeEntityMetadata(e world.Entity, m protocol.EntityMetadata) {
+	s.entityMutex.Lock()
+	defer s.entityMutex.Unlock()
+	if _, ok := s.entityRuntimeIDs[e]; ok {
+		s.entityMetadata[e] = m
 	}
-	if sw, ok := e.(swimmer); ok && sw.Swimming() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagSwimming)
+}
+
+// entityMetadataChanges parses the metadata of an entity and returns the metadata that changed since it was last sent
+// to the client. All metadata is returned if none was sent before. Keys that were sent before but are no longer
+// present, such as a name tag that was removed, are reset to the zero value of their type, as the client keeps
+// metadata that is not sent. The bool returned is false if nothing changed or
+// if the entity is not currently spawned to the client.
+func (s *Session) entityMetadataChanges(e world.Entity) (protocol.EntityMetadata, bool) {
+	m := s.parseEntityMetadata(e)
+
+	s.entityMutex.Lock()
+	_, spawned := s.entityRuntimeIDs[e]
+	_, hidden := s.hiddenEntities[e]
+	if !spawned || hidden {
+		s.entityMutex.Unlock()
+		return nil, false
 	}
-	if gl, ok := e.(glider); ok && gl.Gliding() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagGliding)
+	prev, ok := s.entityMetadata[e]
+	s.entityMetadata[e] = m
+	s.entityMutex.Unlock()
+
+	if !ok {
+		return m, true
 	}
-	if b, ok := e.(breather); ok {
-		m[protocol.EntityDataKeyAirSupply] = int16(b.AirSupply().Milliseconds() / 50)
-		m[protocol.EntityDataKeyAirSupplyMax] = int16(b.MaxAirSupply().Milliseconds() / 50)
-		if b.Breathing() {
-			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagBreathing)
+	changes := protocol.EntityMetadata{}
+	for k, v := range m {
+		if pv, ok := prev[k]; !ok || !reflect.DeepEqual(pv, v) {
+			changes[k] = v
 		}
 	}
-	if i, ok := e.(invisible); ok && i.Invisible() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagInvisible)
-	}
-	if i, ok := e.(immobile); ok && i.Immobile() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagNoAI)
-	}
-	if o, ok := e.(onFire); ok && o.OnFireDuration() > 0 {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagOnFire)
-	}
-	if u, ok := e.(using); ok && u.UsingItem() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagUsingItem)
-	}
-	if c, ok := e.(arrow); ok && c.Critical() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagCritical)
-	}
-	if g, ok := e.(gameMode); ok {
-		if g.GameMode().HasCollision() {
-			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagHasCollision)
-		}
-		if !g.GameMode().Visible() {
-			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagInvisible)
+	for k, pv := range prev {
+		if _, ok := m[k]; !ok {
+			changes[k] = reflect.Zero(reflect.TypeOf(pv)).Interface()
 		}
 	}
-	if o, ok := e.(orb); ok {
-		m[protocol.EntityDataKeyValue] = int32(o.Experience())
+	return changes, len(changes) > 0
+}
+
+// addSpecificMetadata adds the metadata of all metadataFields that apply to e to m.
+func (s *Session) addSpecificMetadata(e any, m protocol.EntityMetadata) {
+	for _, f := range metadataFields {
+		f(s, e, m)
 	}
-	if f, ok := e.(firework); ok {
-		m[protocol.EntityDataKeyDisplayTileRuntimeID] = nbtconv.WriteItem(item.NewStack(f.Firework(), 1), false)
-		if o, ok := e.(owned); ok && f.Attached() {
-			m[protocol.EntityDataKeyCustomDisplay] = int64(s.entityRuntimeID(o.Owner()))
+}
+
+// metadataField encodes a part of the state of an entity into its entity metadata. A metadataField does nothing if
+// the entity passed does not have the state that it encodes.
+type metadataField func(s *Session, e any, m protocol.EntityMetadata)
+
+// metadataFields holds all metadataFields used to encode the state of an entity, in the order that they are applied.
+// Entity types implementing the interfaces used by these fields have their metadata encoded automatically, so adding
+// state to the metadata of entities only requires adding a field here.
+var metadataFields = []metadataField{
+	flagField(protocol.EntityDataFlagSneaking, sneaker.Sneaking),
+	flagField(protocol.EntityDataFlagSprinting, sprinter.Sprinting),
+	flagField(protocol.EntityDataFlagBlocking, blocker.Blocking),
+	flagField(protocol.EntityDataFlagSwimming, swimmer.Swimming),
+	flagField(protocol.EntityDataFlagGliding, glider.Gliding),
+	valueField(protocol.EntityDataKeyAirSupply, func(b breather) any { return int16(b.AirSupply().Milliseconds() / 50) }),
+	valueField(protocol.EntityDataKeyAirSupplyMax, func(b breather) any { return int16(b.MaxAirSupply().Milliseconds() / 50) }),
+	flagField(protocol.EntityDataFlagBreathing, breather.Breathing),
+	flagField(protocol.EntityDataFlagInvisible, invisible.Invisible),
+	flagField(protocol.EntityDataFlagNoAI, immobile.Immobile),
+	flagField(protocol.EntityDataFlagOnFire, func(o onFire) bool { return o.OnFireDuration() > 0 }),
+	flagField(protocol.EntityDataFlagUsingItem, using.UsingItem),
+	flagField(protocol.EntityDataFlagCritical, arrow.Critical),
+	flagField(protocol.EntityDataFlagHasCollision, func(g gameMode) bool { return g.GameMode().HasCollision() }),
+	flagField(protocol.EntityDataFlagInvisible, func(g gameMode) bool { return !g.GameMode().Visible() }),
+	valueField(protocol.EntityDataKeyValue, func(o orb) any { return int32(o.Experience()) }),
+	func(s *Session, e any, m protocol.EntityMetadata) {
+		if f, ok := e.(firework); ok {
+			m[protocol.EntityDataKeyDisplayTileRuntimeID] = nbtconv.WriteItem(item.NewStack(f.Firework(), 1), false)
+			if o, ok := e.(owned); ok && f.Attached() {
+				m[protocol.EntityDataKeyCustomDisplay] = int64(s.entityRuntimeID(o.Owner()))
+			}
+		} else if o, ok := e.(owned); ok {
+			m[protocol.EntityDataKeyOwner] = int64(s.entityRuntimeID(o.Owner()))
 		}
-	} else if o, ok := e.(owned); ok {
-		m[protocol.EntityDataKeyOwner] = int64(s.entityRuntimeID(o.Owner()))
-	}
-	if sc, ok := e.(scaled); ok {
-		m[protocol.EntityDataKeyScale] = float32(sc.Scale())
-	}
-	if t, ok := e.(tnt); ok {
-		m[protocol.EntityDataKeyFuseTime] = int32(t.Fuse().Milliseconds() / 50)
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagIgnited)
-	}
-	if n, ok := e.(named); ok {
-		m[protocol.EntityDataKeyName] = n.NameTag()
-		m[protocol.EntityDataKeyAlwaysShowNameTag] = uint8(1)
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagAlwaysShowName)
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagShowName)
-	}
-	if sc, ok := e.(scoreTag); ok {
-		m[protocol.EntityDataKeyScore] = sc.ScoreTag()
-	}
-	if c, ok := e.(areaEffectCloud); ok {
-		m[protocol.EntityDataKeyDataRadius] = float32(c.Radius())
-
-		// We purposely fill these in with invalid values to disable the client-sided shrinking of the cloud.
-		m[protocol.EntityDataKeyDataDuration] = int32(math.MaxInt32)
-		m[protocol.EntityDataKeyDataChangeOnPickup] = float32(math.SmallestNonzeroFloat32)
-		m[protocol.EntityDataKeyDataChangeRate] = float32(math.SmallestNonzeroFloat32)
-
-		colour, am := effect.ResultingColour(c.Effects())
-		m[protocol.EntityDataKeyEffectColor] = nbtconv.Int32FromRGBA(colour)
-		if am {
-			m[protocol.EntityDataKeyEffectAmbience] = byte(1)
-		} else {
-			m[protocol.EntityDataKeyEffectAmbience] = byte(0)
+	},
+	valueField(protocol.EntityDataKeyScale, func(sc scaled) any { return float32(sc.Scale()) }),
+	valueField(protocol.EntityDataKeyFuseTime, func(t tnt) any { return int32(t.Fuse().Milliseconds() / 50) }),
+	flagField(protocol.EntityDataFlagIgnited, func(tnt) bool { return true }),
+	valueField(protocol.EntityDataKeyName, func(n named) any { return n.NameTag() }),
+	valueField(protocol.EntityDataKeyAlwaysShowNameTag, func(named) any { return uint8(1) }),
+	flagField(protocol.EntityDataFlagAlwaysShowName, func(named) bool { return true }),
+	flagField(protocol.EntityDataFlagShowName, func(named) bool { return true }),
+	valueField(protocol.EntityDataKeyScore, func(sc scoreTag) any { return sc.ScoreTag() }),
+	func(_ *Session, e any, m protocol.EntityMetadata) {
+		if c, ok := e.(areaEffectCloud); ok {
+			m[protocol.EntityDataKeyDataRadius] = float32(c.Radius())
+
+			// We purposely fill these in with invalid values to disable the client-sided shrinking of the cloud.
+			m[protocol.EntityDataKeyDataDuration] = int32(math.MaxInt32)
+			m[protocol.EntityDataKeyDataChangeOnPickup] = float32(math.SmallestNonzeroFloat32)
+			m[protocol.EntityDataKeyDataChangeRate] = float32(math.SmallestNonzeroFloat32)
+
+			setEffectColour(m, c.Effects())
 		}
-	}
-	if l, ok := e.(living); ok && s.c == e {
-		deathPos, deathDimension, died := l.DeathPosition()
-		if died {
-			dim, _ := world.DimensionID(deathDimension)
-			m[protocol.EntityDataKeyPlayerLastDeathPosition] = vec64To32(deathPos)
-			m[protocol.EntityDataKeyPlayerLastDeathDimension] = int32(dim)
+	},
+	func(s *Session, e any, m protocol.EntityMetadata) {
+		if l, ok := e.(living); ok && s.c == e {
+			deathPos, deathDimension, died := l.DeathPosition()
+			if died {
+				dim, _ := world.DimensionID(deathDimension)
+				m[protocol.EntityDataKeyPlayerLastDeathPosition] = vec64To32(deathPos)
+				m[protocol.EntityDataKeyPlayerLastDeathDimension] = int32(dim)
+			}
+			m[protocol.EntityDataKeyPlayerHasDied] = boolByte(died)
 		}
-		m[protocol.EntityDataKeyPlayerHasDied] = boolByte(died)
-	}
-	if p, ok := e.(splash); ok {
-		m[protocol.EntityDataKeyAuxValueData] = int16(p.Potion().Uint8())
-		if tip := p.Potion().Uint8(); tip > 4 {
-			m[protocol.EntityDataKeyCustomDisplay] = tip + 1
+	},
+	func(_ *Session, e any, m protocol.EntityMetadata) {
+		if p, ok := e.(splash); ok {
+			m[protocol.EntityDataKeyAuxValueData] = int16(p.Potion().Uint8())
+			if tip := p.Potion().Uint8(); tip > 4 {
+				m[protocol.EntityDataKeyCustomDisplay] = tip + 1
+			}
 		}
-	}
-	if eff, ok := e.(effectBearer); ok && len(eff.Effects()) > 0 {
-		visibleEffects := make([]effect.Effect, 0, len(eff.Effects()))
-		for _, ef := range eff.Effects() {
-			if !ef.ParticlesHidden() {
-				visibleEffects = append(visibleEffects, ef)
+	},
+	func(_ *Session, e any, m protocol.EntityMetadata) {
+		if eff, ok := e.(effectBearer); ok && len(eff.Effects()) > 0 {
+			visibleEffects := make([]effect.Effect, 0, len(eff.Effects()))
+			for _, ef := range eff.Effects() {
+				if !ef.ParticlesHidden() {
+					visibleEffects = append(visibleEffects, ef)
+				}
+			}
+			if len(visibleEffects) > 0 {
+				setEffectColour(m, visibleEffects)
+			}
+		}
+	},
+	valueField(protocol.EntityDataKeyVariant, func(v variable) any { return v.Variant() }),
+	valueField(protocol.EntityDataKeyMarkVariant, func(mv markVariable) any { return mv.MarkVariant() }),
+	flagField(protocol.EntityDataFlagBaby, baby.Baby),
+	flagField(protocol.EntityDataFlagInLove, inLove.InLove),
+	flagField(protocol.EntityDataFlagSheared, sheared.Sheared),
+	valueField(protocol.EntityDataKeyColorIndex, func(c coloured) any { return c.Colour().Uint8() }),
+	flagField(protocol.EntityDataFlagIgnited, ignitable.Ignited),
+	flagField(protocol.EntityDataFlagEnchanted, glint.Glint),
+	flagField(protocol.EntityDataFlagLingering, func(entity.LingeringPotionType) bool { return true }),
+	func(_ *Session, e any, m protocol.EntityMetadata) {
+		if r, ok := e.(seated); ok {
+			if _, ok := r.Seat(); ok {
+				m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagRiding)
+				m[protocol.EntityDataKeySeatOffset] = mgl32.Vec3{0, seatOffset}
 			}
 		}
-		if len(visibleEffects) > 0 {
-			colour, am := effect.ResultingColour(visibleEffects)
-			m[protocol.EntityDataKeyEffectColor] = nbtconv.Int32FromRGBA(colour)
-			if am {
-				m[protocol.EntityDataKeyEffectAmbience] = byte(1)
-			} else {
-				m[protocol.EntityDataKeyEffectAmbience] = byte(0)
+	},
+	func(_ *Session, e any, m protocol.EntityMetadata) {
+		if s, ok := e.(sleeper); ok {
+			if pos, ok := s.Sleeping(); ok {
+				m[protocol.EntityDataKeyBedPosition] = protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])}
+				m.SetFlag(protocol.EntityDataKeyPlayerFlags, playerFlagSleeping)
 			}
 		}
+	},
+}
+
+// playerFlagSleeping is the index of the bit in the player flags of an entity that is set while the entity is
+// sleeping in a bed.
+const playerFlagSleeping = 1
+
+// seatOffset is the vertical offset of riders from the seat they are seated on. The legs of riders are lowered
+// by this offset, so that they appear to be sitting on top of the position of the seat.
+const seatOffset = -0.6
+
+// flagField returns a metadataField that sets a flag if the entity implements T and f returns true for it.
+func flagField[T any](flag uint8, f func(T) bool) metadataField {
+	return func(_ *Session, e any, m protocol.EntityMetadata) {
+		if v, ok := e.(T); ok && f(v) && !m.Flag(protocol.EntityDataKeyFlags, flag) {
+			m.SetFlag(protocol.EntityDataKeyFlags, flag)
+		}
 	}
-	if v, ok := e.(variable); ok {
-		m[protocol.EntityDataKeyVariant] = v.Variant()
+}
+
+// valueField returns a metadataField that sets the metadata key passed to the value returned by f if the entity
+// implements T.
+func valueField[T any](key uint32, f func(T) any) metadataField {
+	return func(_ *Session, e any, m protocol.EntityMetadata) {
+		if v, ok := e.(T); ok {
+			m[key] = f(v)
+		}
 	}
-	if mv, ok := e.(markVariable); ok {
-		m[protocol.EntityDataKeyMarkVariant] = mv.MarkVariant()
+}
+
+// setEffectColour sets the effect colour and ambience of the metadata passed to those resulting from the effects
+// passed.
+func setEffectColour(m protocol.EntityMetadata, effects []effect.Effect) {
+	colour, am := effect.ResultingColour(effects)
+	m[protocol.EntityDataKeyEffectColor] = nbtconv.Int32FromRGBA(colour)
+	if am {
+		m[protocol.EntityDataKeyEffectAmbience] = byte(1)
+	} else {
+		m[protocol.EntityDataKeyEffectAmbience] = byte(0)
 	}
 }
 
@@ -170,6 +248,10 @@ type sneaker interface {
 	Sneaking() bool
 }
 
+type blocker interface {
+	Blocking() bool
+}
+
 type sprinter interface {
 	Sprinting() bool
 }
@@ -269,3 +351,31 @@ type variable interface {
 type markVariable interface {
 	MarkVariant() int32
 }
+
+type baby interface {
+	Baby() bool
+}
+
+type inLove interface {
+	InLove() bool
+}
+
+type sheared interface {
+	Sheared() bool
+}
+
+type coloured interface {
+	Colour() item.Colour
+}
+
+type ignitable interface {
+	Ignited() bool
+}
+
+type seated interface {
+	Seat() (*entity.Ent, bool)
+}
+
+type sleeper interface {
+	Sleeping() (cube.Pos, bool)
+}
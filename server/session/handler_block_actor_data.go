@@ -38,6 +38,12 @@ func (b BlockActorDataHandler) handleSign(pk *packet.BlockActorData, pos cube.Po
 		s.log.Debugf("sign block actor data for position without sign %v", pos)
 		return nil
 	}
+	if opened := s.openedSign.Load(); opened == nil || *opened != pos {
+		// The client may only edit signs for which the sign editor was opened by the server.
+		s.log.Debugf("sign block actor data for sign %v that was not opened", pos)
+		s.c.World().ResendBlock(pos, s)
+		return nil
+	}
 
 	frontText, err := b.textFromNBTData(pk.NBTData, true)
 	if err != nil {
@@ -2,6 +2,7 @@ package session
 
 import (
 	"fmt"
+	"github.com/df-mc/dragonfly/server/text"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
@@ -19,6 +20,10 @@ func (h *CommandRequestHandler) Handle(p packet.Packet, s *Session) error {
 	}
 
 	h.origin = pk.CommandOrigin
+	if !s.limits.Command.Allow(s.conn.IdentityData().Identity) {
+		s.SendMessage(text.Colourf("<red>You are executing commands too quickly.</red>"))
+		return nil
+	}
 	s.c.ExecuteCommand(pk.CommandLine)
 	return nil
 }
@@ -34,11 +34,19 @@ func (h *InventoryTransactionHandler) Handle(p packet.Packet, s *Session) error
 		if err := s.UpdateHeldSlot(int(data.HotBarSlot), stackToItem(data.HeldItem.Stack)); err != nil {
 			return err
 		}
+		if !s.limits.Interaction.Allow(s.conn.IdentityData().Identity) {
+			h.resendInventories(s)
+			return nil
+		}
 		return h.handleUseItemOnEntityTransaction(data, s)
 	case *protocol.UseItemTransactionData:
 		if err := s.UpdateHeldSlot(int(data.HotBarSlot), stackToItem(data.HeldItem.Stack)); err != nil {
 			return err
 		}
+		if !s.limits.Interaction.Allow(s.conn.IdentityData().Identity) {
+			h.resendInventories(s)
+			return nil
+		}
 		return h.handleUseItemTransaction(data, s)
 	case *protocol.ReleaseItemTransactionData:
 		if err := s.UpdateHeldSlot(int(data.HotBarSlot), stackToItem(data.HeldItem.Stack)); err != nil {
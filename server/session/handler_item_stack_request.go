@@ -160,6 +160,9 @@ func (h *ItemStackRequestHandler) handleTransfer(from, to protocol.StackRequestS
 	if dest.Empty() {
 		dest = i.Grow(-math.MaxInt32)
 	}
+	if err := h.verifyOffHand(to, i, s); err != nil {
+		return err
+	}
 
 	invA, _ := s.invByID(int32(from.ContainerID))
 	invB, _ := s.invByID(int32(to.ContainerID))
@@ -184,6 +187,12 @@ func (h *ItemStackRequestHandler) handleSwap(a *protocol.SwapStackRequestAction,
 	}
 	i, _ := h.itemInSlot(a.Source, s)
 	dest, _ := h.itemInSlot(a.Destination, s)
+	if err := h.verifyOffHand(a.Destination, i, s); err != nil {
+		return err
+	}
+	if err := h.verifyOffHand(a.Source, dest, s); err != nil {
+		return err
+	}
 
 	invA, _ := s.invByID(int32(a.Source.ContainerID))
 	invB, _ := s.invByID(int32(a.Destination.ContainerID))
@@ -204,6 +213,18 @@ func (h *ItemStackRequestHandler) handleSwap(a *protocol.SwapStackRequestAction,
 	return nil
 }
 
+// verifyOffHand checks if the item stack passed may be put in the slot passed. An error is returned if the slot is
+// the off-hand slot and the item cannot be held in the off hand.
+func (h *ItemStackRequestHandler) verifyOffHand(slot protocol.StackRequestSlotInfo, i item.Stack, s *Session) error {
+	if inv, _ := s.invByID(int32(slot.ContainerID)); inv != s.offHand || i.Empty() {
+		return nil
+	}
+	if o, ok := i.Item().(item.OffHand); !ok || !o.OffHand() {
+		return fmt.Errorf("client tried putting %v in the off hand, but it cannot be held in the off hand", i)
+	}
+	return nil
+}
+
 // collectRewards checks if the source inventory has rewards for the player, for example, experience rewards when
 // smelting. If it does, it will drop the rewards at the player's location.
 func (h *ItemStackRequestHandler) collectRewards(s *Session, inv *inventory.Inventory, slot int) {
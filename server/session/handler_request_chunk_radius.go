@@ -10,14 +10,6 @@ type RequestChunkRadiusHandler struct{}
 // Handle ...
 func (*RequestChunkRadiusHandler) Handle(p packet.Packet, s *Session) error {
 	pk := p.(*packet.RequestChunkRadius)
-
-	if pk.ChunkRadius > s.maxChunkRadius {
-		pk.ChunkRadius = s.maxChunkRadius
-	}
-	s.chunkRadius = pk.ChunkRadius
-
-	s.chunkLoader.ChangeRadius(int(pk.ChunkRadius))
-
-	s.writePacket(&packet.ChunkRadiusUpdated{ChunkRadius: s.chunkRadius})
+	s.requestChunkRadius(pk.ChunkRadius)
 	return nil
 }
@@ -0,0 +1,21 @@
+package session
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// SetDifficultyHandler handles the SetDifficulty packet.
+type SetDifficultyHandler struct{}
+
+// Handle ...
+func (SetDifficultyHandler) Handle(p packet.Packet, s *Session) error {
+	pk := p.(*packet.SetDifficulty)
+	d, ok := world.DifficultyByID(int(pk.Difficulty))
+	if !ok {
+		return fmt.Errorf("unknown difficulty %v", pk.Difficulty)
+	}
+	s.c.ChangeDifficulty(d)
+	return nil
+}
@@ -16,7 +16,7 @@ const (
 // handleSmithing handles a CraftRecipe stack request action made using a smithing table.
 func (h *ItemStackRequestHandler) handleSmithing(a *protocol.CraftRecipeStackRequestAction, s *Session) error {
 	// First, check the recipe and ensure it is valid for the smithing table.
-	craft, ok := s.recipes[a.RecipeNetworkID]
+	craft, ok := s.recipes.Load()[a.RecipeNetworkID]
 	if !ok {
 		return fmt.Errorf("recipe with network id %v does not exist", a.RecipeNetworkID)
 	}
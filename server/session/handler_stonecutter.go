@@ -11,7 +11,7 @@ const stonecutterInputSlot = 0x03
 
 // handleStonecutting handles a CraftRecipe stack request action made using a stonecutter.
 func (h *ItemStackRequestHandler) handleStonecutting(a *protocol.CraftRecipeStackRequestAction, s *Session) error {
-	craft, ok := s.recipes[a.RecipeNetworkID]
+	craft, ok := s.recipes.Load()[a.RecipeNetworkID]
 	if !ok {
 		return fmt.Errorf("recipe with network id %v does not exist", a.RecipeNetworkID)
 	}
@@ -2,6 +2,7 @@ package session
 
 import (
 	"fmt"
+	"github.com/df-mc/dragonfly/server/text"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
@@ -21,6 +22,10 @@ func (TextHandler) Handle(p packet.Packet, s *Session) error {
 	if pk.XUID != s.conn.IdentityData().XUID {
 		return fmt.Errorf("XUID must be equal to player's XUID")
 	}
+	if !s.limits.Chat.Allow(s.conn.IdentityData().Identity) {
+		s.SendMessage(text.Colourf("<red>You are sending messages too quickly.</red>"))
+		return nil
+	}
 	s.c.Chat(pk.Message)
 	return nil
 }
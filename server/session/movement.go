@@ -0,0 +1,65 @@
+package session
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// MovementMode is the mode in which the server authorises the movement of players. In both modes, the client sends
+// its input and position every tick using the PlayerAuthInput packet, which the server processes and validates, and
+// breaks blocks through the same packet. The modes differ in the way movement rejected by the server is corrected.
+type MovementMode int
+
+const (
+	// MovementModeServer corrects rejected movement by teleporting the player back to the position held by the
+	// server. This is the default mode.
+	MovementModeServer MovementMode = iota
+	// MovementModeServerWithRewind corrects rejected movement using the CorrectPlayerMovePrediction packet, which
+	// holds the tick of the movement corrected. The client rewinds to that tick and replays the input it sent after
+	// it, resulting in smoother corrections than teleporting.
+	MovementModeServerWithRewind
+)
+
+// rewindHistorySize is the number of ticks of movement that the client keeps to rewind in
+// MovementModeServerWithRewind.
+const rewindHistorySize = 40
+
+// PlayerMovementSettings returns the protocol.PlayerMovementSettings sent to the client in the StartGame packet
+// for the MovementMode.
+func (m MovementMode) PlayerMovementSettings() protocol.PlayerMovementSettings {
+	if m == MovementModeServerWithRewind {
+		return protocol.PlayerMovementSettings{
+			MovementType:                     protocol.PlayerMovementModeServerWithRewind,
+			RewindHistorySize:                rewindHistorySize,
+			ServerAuthoritativeBlockBreaking: true,
+		}
+	}
+	return protocol.PlayerMovementSettings{
+		MovementType:                     protocol.PlayerMovementModeServer,
+		ServerAuthoritativeBlockBreaking: true,
+	}
+}
+
+// CorrectMovement corrects the position of the client to the position passed after the server rejected its
+// movement. Depending on the MovementMode of the Session, the client is either teleported or made to rewind to the
+// last tick of input it sent. Movement received before the client has processed the correction is ignored.
+func (s *Session) CorrectMovement(pos mgl64.Vec3, onGround bool) {
+	if s == Nop {
+		return
+	}
+	if s.movementMode != MovementModeServerWithRewind {
+		s.ViewEntityTeleport(s.c, pos)
+		return
+	}
+	s.chunkLoader.Move(pos)
+	s.teleportPos.Store(&pos)
+
+	input := s.inputPos.Load()
+	s.writePacket(&packet.CorrectPlayerMovePrediction{
+		Position: vec64To32(pos.Add(entityOffset(s.c))),
+		Delta:    vec64To32(input.Sub(pos)),
+		OnGround: onGround,
+		Tick:     s.inputTick.Load(),
+	})
+}
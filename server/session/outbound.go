@@ -0,0 +1,75 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// queuedPacket is a packet queued to be written to the Conn of a Session during the next flush.
+type queuedPacket struct {
+	pk packet.Packet
+	// low specifies if the packet is in the low priority lane. Packets in this lane are held back while the
+	// bandwidth budget of the Session is exceeded.
+	low bool
+}
+
+// lowPriority checks if the packet passed belongs in the low priority lane. This lane holds the packets that make up
+// most of the traffic of a Session but are not needed for the game to stay responsive, such as chunks, block updates
+// and particles. Block updates are in the same lane as chunks, so that they are never sent before the chunk they
+// apply to.
+func lowPriority(pk packet.Packet) bool {
+	switch pk.(type) {
+	case *packet.LevelChunk, *packet.SubChunk, *packet.ClientCacheMissResponse, *packet.NetworkChunkPublisherUpdate,
+		*packet.UpdateBlock, *packet.UpdateBlockSynced, *packet.UpdateSubChunkBlocks, *packet.BlockActorData,
+		*packet.LevelEvent, *packet.SpawnParticleEffect:
+		return true
+	}
+	return false
+}
+
+// barrier checks if the packet passed requires all packets queued before it to be written, regardless of their
+// lane. Changing dimension is a barrier, so that chunks of the old dimension are never sent after it.
+func barrier(pk packet.Packet) bool {
+	_, ok := pk.(*packet.ChangeDimension)
+	return ok
+}
+
+// queuePacket queues a packet to be written to the Conn during the next flush. If the Session has no flush rate,
+// the packet is written immediately.
+func (s *Session) queuePacket(pk packet.Packet) {
+	if s.flushRate <= 0 {
+		s.sendPacket(pk)
+		return
+	}
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	s.queue = append(s.queue, queuedPacket{pk: pk, low: lowPriority(pk)})
+	s.queueBarrier = s.queueBarrier || barrier(pk)
+}
+
+// flush writes all packets queued to the Conn of the Session and flushes it, so that they are sent over the
+// network in a single batch. Packets are written in the order in which they were queued, except if the bandwidth
+// budget of the Session is exceeded: Packets in the low priority lane are then held back until the next flush, unless
+// force is true or a barrier packet was queued.
+func (s *Session) flush(force bool) {
+	if s == Nop {
+		return
+	}
+	s.queueMu.Lock()
+	hold := !force && !s.queueBarrier && s.bandwidth.exceeded()
+	kept := s.queue[:0]
+	for _, q := range s.queue {
+		if hold && q.low {
+			kept = append(kept, q)
+			continue
+		}
+		s.sendPacket(q.pk)
+	}
+	for i := len(kept); i < len(s.queue); i++ {
+		// Clear the packets no longer queued so that they can be garbage collected.
+		s.queue[i] = queuedPacket{}
+	}
+	s.queue, s.queueBarrier = kept, false
+	s.queueMu.Unlock()
+
+	_ = s.conn.Flush()
+}
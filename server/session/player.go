@@ -6,6 +6,7 @@ import (
 	"github.com/df-mc/atomic"
 	"github.com/df-mc/dragonfly/server/block"
 	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/entity/effect"
 	"github.com/df-mc/dragonfly/server/internal/nbtconv"
 	"github.com/df-mc/dragonfly/server/item"
@@ -57,6 +58,8 @@ func (s *Session) closeCurrentContainer() {
 	b := w.Block(pos)
 	if container, ok := b.(block.Container); ok {
 		container.RemoveViewer(s, w, pos)
+		// The items in the container may have been changed while it was opened.
+		w.BlockEntityChanged(pos)
 	} else if enderChest, ok := b.(block.EnderChest); ok {
 		enderChest.RemoveViewer(w, pos)
 	}
@@ -91,12 +94,31 @@ func (s *Session) SendRespawn(pos mgl64.Vec3) {
 	})
 }
 
-// sendRecipes sends the current crafting recipes to the session.
-func (s *Session) sendRecipes() {
-	recipes := make([]protocol.Recipe, 0, len(recipe.Recipes()))
-	for index, i := range recipe.Recipes() {
+// SendRespawnSearching lets the client know that the Controllable entity of the session died and that the position
+// passed is the position it will likely respawn at. The client requests to respawn using a Respawn packet once the
+// respawn button on the death screen is pressed.
+func (s *Session) SendRespawnSearching(pos mgl64.Vec3) {
+	if s == Nop {
+		return
+	}
+	s.writePacket(&packet.Respawn{
+		Position:        vec64To32(pos.Add(entityOffset(s.c))),
+		State:           packet.RespawnStateSearchingForSpawn,
+		EntityRuntimeID: selfEntityRuntimeID,
+	})
+}
+
+// SendRecipes sends the crafting recipes currently registered to the session, replacing all recipes previously sent.
+// SendRecipes may be called after recipes were registered to make them available to a player already online.
+func (s *Session) SendRecipes() {
+	if s == Nop {
+		return
+	}
+	all := recipe.Recipes()
+	recipes, networkIDs := make([]protocol.Recipe, 0, len(all)), make(map[uint32]recipe.Recipe, len(all))
+	for index, i := range all {
 		networkID := uint32(index) + 1
-		s.recipes[networkID] = i
+		networkIDs[networkID] = i
 
 		switch i := i.(type) {
 		case recipe.Shapeless:
@@ -132,6 +154,7 @@ func (s *Session) sendRecipes() {
 			})
 		}
 	}
+	s.recipes.Store(networkIDs)
 	s.writePacket(&packet.CraftingData{Recipes: recipes, ClearRecipes: true})
 }
 
@@ -261,21 +284,23 @@ func (s *Session) Disconnect(message string) {
 			HideDisconnectionScreen: message == "",
 			Message:                 message,
 		})
-		_ = s.conn.Flush()
+		s.flush(true)
 	}
 }
 
-// SendSpeed sends the speed of the player in an UpdateAttributes packet, so that it is updated client-side.
-func (s *Session) SendSpeed(speed float64) {
+// SendAttribute sends the value of an attribute of the player in an UpdateAttributes packet, so that it is updated
+// client-side.
+func (s *Session) SendAttribute(a attribute.Attribute, value float64) {
 	s.writePacket(&packet.UpdateAttributes{
 		EntityRuntimeID: selfEntityRuntimeID,
 		Attributes: []protocol.Attribute{{
 			AttributeValue: protocol.AttributeValue{
-				Name:  "minecraft:movement",
-				Value: float32(speed),
-				Max:   math.MaxFloat32,
+				Name:  a.Name(),
+				Value: float32(value),
+				Max:   float32(a.Max()),
+				Min:   float32(a.Min()),
 			},
-			Default: 0.1,
+			Default: float32(a.Default()),
 		}},
 	})
 }
@@ -399,6 +424,19 @@ func (s *Session) sendAbilities() {
 	}})
 }
 
+// ResendHeldItems sends the items held in the main hand and off hand to the player again. It is used to revert
+// changes that the client predicted for these items, such as the count of a block being placed, if the action was
+// rejected.
+func (s *Session) ResendHeldItems() {
+	if s == Nop {
+		return
+	}
+	slot := int(s.heldSlot.Load())
+	mainHand, _ := s.inv.Item(slot)
+	s.sendItem(mainHand, slot, protocol.WindowIDInventory)
+	s.sendInv(s.offHand, protocol.WindowIDOffHand)
+}
+
 // SendHealth sends the health and max health to the player.
 func (s *Session) SendHealth(health *entity.HealthManager) {
 	s.writePacket(&packet.UpdateAttributes{
@@ -473,16 +511,17 @@ func (s *Session) EnableInstantRespawn(enable bool) {
 	s.sendGameRules([]protocol.GameRule{{Name: "doimmediaterespawn", Value: enable}})
 }
 
-// addToPlayerList adds the player of a session to the player list of this session. It will be shown in the
+// addToPlayerList adds a Controllable to the player list of this session. It will be shown in the
 // in-game pause menu screen.
-func (s *Session) addToPlayerList(session *Session) {
-	c := session.c
-
-	runtimeID := uint64(1)
+func (s *Session) addToPlayerList(c Controllable) {
 	s.entityMutex.Lock()
-	if session != s {
-		s.currentEntityRuntimeID += 1
-		runtimeID = s.currentEntityRuntimeID
+	runtimeID, ok := s.entityRuntimeIDs[world.Entity(c)]
+	if !ok {
+		runtimeID = selfEntityRuntimeID
+		if c != s.c {
+			s.currentEntityRuntimeID += 1
+			runtimeID = s.currentEntityRuntimeID
+		}
 	}
 	s.entityRuntimeIDs[c] = runtimeID
 	s.entities[runtimeID] = c
@@ -542,14 +581,13 @@ func skinToProtocol(s skin.Skin) protocol.Skin {
 	}
 }
 
-// removeFromPlayerList removes the player of a session from the player list of this session. It will no
-// longer be shown in the in-game pause menu screen.
-func (s *Session) removeFromPlayerList(session *Session) {
-	c := session.c
-
+// removeFromPlayerList removes a Controllable from the player list of this session. It will no longer be shown
+// in the in-game pause menu screen.
+func (s *Session) removeFromPlayerList(c Controllable) {
 	s.entityMutex.Lock()
 	delete(s.entities, s.entityRuntimeIDs[c])
 	delete(s.entityRuntimeIDs, c)
+	delete(s.entityMetadata, c)
 	s.entityMutex.Unlock()
 
 	s.writePacket(&packet.PlayerList{
@@ -790,16 +828,46 @@ func stacksToIngredientItems(inputs []item.Stack) []protocol.ItemDescriptorCount
 	return items
 }
 
-// creativeItems returns all creative inventory items as protocol item stacks.
-func creativeItems() []protocol.CreativeItem {
-	it := make([]protocol.CreativeItem, 0, len(creative.Items()))
-	for index, i := range creative.Items() {
+// SendCreativeMenu sends the items of the creative.Menu passed to the client as the content of its creative
+// inventory. Later changes to the menu are only shown to the client once SendCreativeMenu is called again.
+func (s *Session) SendCreativeMenu(m *creative.Menu) {
+	if s == Nop {
+		return
+	}
+	items := m.Items()
+	s.creativeMu.Lock()
+	s.creativeMenu, s.creativeItems = m, items
+	s.creativeMu.Unlock()
+
+	it := make([]protocol.CreativeItem, 0, len(items))
+	for index, i := range items {
 		it = append(it, protocol.CreativeItem{
 			CreativeItemNetworkID: uint32(index) + 1,
 			Item:                  deleteDamage(stackFromItem(i)),
 		})
 	}
-	return it
+	s.writePacket(&packet.CreativeContent{Items: it})
+}
+
+// CreativeMenu returns the creative.Menu last sent to the client using SendCreativeMenu. If none was sent yet,
+// creative.Default is returned.
+func (s *Session) CreativeMenu() *creative.Menu {
+	s.creativeMu.Lock()
+	defer s.creativeMu.Unlock()
+	if s.creativeMenu == nil {
+		return creative.Default()
+	}
+	return s.creativeMenu
+}
+
+// creativeItem returns the creative inventory item with the network ID passed, as last sent to the client.
+func (s *Session) creativeItem(networkID uint32) (item.Stack, bool) {
+	s.creativeMu.Lock()
+	defer s.creativeMu.Unlock()
+	if networkID == 0 || int(networkID) > len(s.creativeItems) {
+		return item.Stack{}, false
+	}
+	return s.creativeItems[networkID-1], true
 }
 
 // deleteDamage strips the damage from a protocol item.
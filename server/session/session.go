@@ -7,12 +7,17 @@ import (
 	"github.com/df-mc/atomic"
 	"github.com/df-mc/dragonfly/server/block"
 	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/crash"
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/internal/sliceutil"
 	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/item/creative"
 	"github.com/df-mc/dragonfly/server/item/inventory"
 	"github.com/df-mc/dragonfly/server/item/recipe"
 	"github.com/df-mc/dragonfly/server/player/chat"
 	"github.com/df-mc/dragonfly/server/player/form"
+	"github.com/df-mc/dragonfly/server/ratelimit"
+	"github.com/df-mc/dragonfly/server/text"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/sandertv/gophertunnel/minecraft"
@@ -20,7 +25,6 @@ import (
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
-	"github.com/sandertv/gophertunnel/minecraft/text"
 	"io"
 	"net"
 	"sync"
@@ -31,6 +35,7 @@ import (
 // of abstraction over direct packets. A Session basically 'controls' an entity.
 type Session struct {
 	log            Logger
+	crash          *crash.Reporter
 	once, connOnce sync.Once
 
 	c        Controllable
@@ -44,10 +49,26 @@ type Session struct {
 	currentScoreboard atomic.Value[string]
 	currentLines      atomic.Value[[]string]
 
-	chunkLoader                 *world.Loader
-	chunkRadius, maxChunkRadius int32
+	chunkLoader *world.Loader
+	// radiusMu guards requestedRadius, viewDistance and chunkRadius. requestedRadius is the chunk radius requested
+	// by the client and viewDistance the limit set using SetViewDistance. chunkRadius is the chunk radius in which
+	// chunks are sent, which is the lowest of the two and maxChunkRadius.
+	radiusMu                                                   sync.Mutex
+	requestedRadius, viewDistance, chunkRadius, maxChunkRadius int32
+	// chunksPerTick is the maximum number of chunks sent to the client every tick.
+	chunksPerTick int
+	// published is true if a NetworkChunkPublisherUpdate was sent to the client. publishedPos and publishedRadius
+	// are the chunk position and radius of the last one sent.
+	published       bool
+	publishedPos    world.ChunkPos
+	publishedRadius int32
 
 	teleportPos atomic.Value[*mgl64.Vec3]
+	// movementMode is the MovementMode in which the movement of the client is authorised. inputTick and inputPos
+	// hold the tick and position of the last PlayerAuthInput packet received, which are used to correct movement.
+	movementMode MovementMode
+	inputTick    atomic.Uint64
+	inputPos     atomic.Value[mgl64.Vec3]
 
 	entityMutex sync.RWMutex
 	// currentEntityRuntimeID holds the runtime ID assigned to the last entity. It is incremented for every
@@ -57,6 +78,13 @@ type Session struct {
 	entityRuntimeIDs map[world.Entity]uint64
 	entities         map[uint64]world.Entity
 	hiddenEntities   map[world.Entity]struct{}
+	// entityMetadata holds the entity metadata last sent to the client for each entity, so that only metadata that
+	// changed needs to be sent.
+	entityMetadata map[world.Entity]protocol.EntityMetadata
+	// spawns holds the entities waiting to be spawned to the client. Up to entitiesPerTick of these entities are
+	// spawned every tick. If entitiesPerTick is 0 or lower, entities are spawned immediately instead.
+	spawns          *spawnQueue
+	entitiesPerTick int
 
 	// heldSlot is the slot in the inventory that the controllable is holding.
 	heldSlot                     *atomic.Uint32
@@ -70,17 +98,42 @@ type Session struct {
 	openedContainerID              atomic.Uint32
 	openedWindow                   atomic.Value[*inventory.Inventory]
 	openedPos                      atomic.Value[cube.Pos]
+	openedSign                     atomic.Value[*cube.Pos]
 	swingingArm                    atomic.Bool
 
-	recipes map[uint32]recipe.Recipe
+	recipes atomic.Value[map[uint32]recipe.Recipe]
 
 	blobMu                sync.Mutex
 	blobs                 map[uint64][]byte
 	openChunkTransactions []map[uint64]struct{}
 	invOpened             bool
 
+	antiXray AntiXrayMode
+	revealMu sync.Mutex
+	reveal   []cube.Pos
+
+	bandwidth *bandwidth
+	enc       *encoder
+
+	// flushRate is the interval at which packets queued are written to the Conn. If 0 or lower, packets are
+	// written immediately.
+	flushRate    time.Duration
+	queueMu      sync.Mutex
+	queue        []queuedPacket
+	queueBarrier bool
+
 	joinMessage, quitMessage string
 
+	// creativeMenu is the creative.Menu shown to the client. creativeItems holds the items of the menu at the time
+	// it was last sent, which are the items that the client refers to by their network IDs.
+	creativeMu    sync.Mutex
+	creativeMenu  *creative.Menu
+	creativeItems []item.Stack
+
+	// limits holds the rate limiters that limit the rates at which the client may chat, execute commands and
+	// interact. Limiters that are nil do not limit the client.
+	limits *ratelimit.Limits
+
 	closeBackground chan struct{}
 }
 
@@ -128,6 +181,19 @@ var Nop = &Session{}
 var sessions []*Session
 var sessionMu sync.Mutex
 
+// listed holds all Controllables without a Session that were added to the player list using AddToPlayerList.
+// listed is protected by sessionMu.
+var listed []Controllable
+
+// packetsReceived and packetsSent count the packets received from and sent to all sessions.
+var packetsReceived, packetsSent atomic.Uint64
+
+// PacketCounts returns the total amount of packets received from and sent to the clients of all sessions since the
+// program was started. The rates at which these grow may be used to monitor the network load of a server.
+func PacketCounts() (received, sent uint64) {
+	return packetsReceived.Load(), packetsSent.Load()
+}
+
 // selfEntityRuntimeID is the entity runtime (or unique) ID of the controllable that the session holds.
 const selfEntityRuntimeID = 1
 
@@ -135,16 +201,62 @@ const selfEntityRuntimeID = 1
 // must therefore always be 1.
 var errSelfRuntimeID = errors.New("invalid entity runtime ID: runtime ID for self must always be 1")
 
-// New returns a new session using a controllable entity. The session will control this entity using the
-// packets that it receives.
-// New takes the connection from which to accept packets. It will start handling these packets after a call to
+// Config holds the settings of a Session. Calling Config.New() creates a Session for a Conn.
+type Config struct {
+	// MaxChunkRadius is the maximum chunk radius that the client may request. Larger requests are lowered to this
+	// radius.
+	MaxChunkRadius int
+	// ChunksPerTick is the maximum amount of chunks sent to the client every tick, starting with the chunks closest
+	// to the player. If 0 or lower, 4 chunks are sent every tick.
+	ChunksPerTick int
+	// EntitiesPerTick is the maximum amount of entities entering the view of the player that are spawned every tick,
+	// starting with the entities closest to the player. If 0, 32 entities are spawned every tick. If lower than 0,
+	// entities are spawned immediately.
+	EntitiesPerTick int
+	// AntiXray is the AntiXrayMode used to obfuscate blocks in chunks sent to the client. If the AntiXrayMode is not
+	// valid, the anti-xray engine is disabled.
+	AntiXray AntiXrayMode
+	// MovementMode is the MovementMode in which the movement of the client is handled.
+	MovementMode MovementMode
+	// MaxBandwidth is the maximum amount of bytes per second sent to the client before low priority traffic, such
+	// as chunks and particles, is held back. If 0 or lower, the bandwidth is not limited.
+	MaxBandwidth int
+	// FlushRate is the rate at which packets queued by the Session are written to the connection in a single batch.
+	// If 0 or lower, packets are written to the connection immediately.
+	FlushRate time.Duration
+	// Log is the Logger that errors of the Session are logged to.
+	Log Logger
+	// CrashReporter is the crash.Reporter that panics during the handling of packets are reported to.
+	CrashReporter *crash.Reporter
+	// JoinMessage and QuitMessage are the messages broadcast when the player of the Session joins or leaves. If
+	// empty, no message is broadcast.
+	JoinMessage, QuitMessage string
+	// Limits are the ratelimit.Limits that the chat messages, commands and interactions of the client are limited
+	// with. The Limits are shared with other sessions. If nil, the client is not rate limited.
+	Limits *ratelimit.Limits
+}
+
+// New returns a new session for the Conn passed using the settings in the Config. The session will control a
+// controllable entity using the packets that it receives. It will start handling these packets after a call to
 // Session.Spawn().
-func New(conn Conn, maxChunkRadius int, log Logger, joinMessage, quitMessage string) *Session {
+func (conf Config) New(conn Conn) *Session {
 	r := conn.ChunkRadius()
-	if r > maxChunkRadius {
-		r = maxChunkRadius
+	if r > conf.MaxChunkRadius {
+		r = conf.MaxChunkRadius
 		_ = conn.WritePacket(&packet.ChunkRadiusUpdated{ChunkRadius: int32(r)})
 	}
+	if conf.ChunksPerTick <= 0 {
+		conf.ChunksPerTick = 4
+	}
+	if conf.EntitiesPerTick == 0 {
+		conf.EntitiesPerTick = 32
+	}
+	if conf.Limits == nil {
+		conf.Limits = &ratelimit.Limits{}
+	}
+	if !conf.AntiXray.Valid() {
+		conf.AntiXray = AntiXrayDisabled
+	}
 
 	s := &Session{}
 	*s = Session{
@@ -155,15 +267,27 @@ func New(conn Conn, maxChunkRadius int, log Logger, joinMessage, quitMessage str
 		entityRuntimeIDs:       map[world.Entity]uint64{},
 		entities:               map[uint64]world.Entity{},
 		hiddenEntities:         map[world.Entity]struct{}{},
+		entityMetadata:         map[world.Entity]protocol.EntityMetadata{},
+		spawns:                 newSpawnQueue(),
+		entitiesPerTick:        conf.EntitiesPerTick,
 		blobs:                  map[uint64][]byte{},
+		requestedRadius:        int32(conn.ChunkRadius()),
 		chunkRadius:            int32(r),
-		maxChunkRadius:         int32(maxChunkRadius),
+		maxChunkRadius:         int32(conf.MaxChunkRadius),
+		chunksPerTick:          conf.ChunksPerTick,
+		antiXray:               conf.AntiXray,
+		movementMode:           conf.MovementMode,
+		bandwidth:              &bandwidth{budget: conf.MaxBandwidth},
+		enc:                    newEncoder(conn),
+		flushRate:              conf.FlushRate,
 		conn:                   conn,
-		log:                    log,
+		log:                    conf.Log,
+		crash:                  conf.CrashReporter,
 		currentEntityRuntimeID: 1,
 		heldSlot:               atomic.NewUint32(0),
-		joinMessage:            joinMessage,
-		quitMessage:            quitMessage,
+		joinMessage:            conf.JoinMessage,
+		quitMessage:            conf.QuitMessage,
+		limits:                 conf.Limits,
 		openedWindow:           *atomic.NewValue(inventory.New(1, nil)),
 	}
 
@@ -176,16 +300,12 @@ func New(conn Conn, maxChunkRadius int, log Logger, joinMessage, quitMessage str
 func (s *Session) Spawn(c Controllable, pos mgl64.Vec3, w *world.World, gm world.GameMode, onStop func(controllable Controllable)) {
 	s.onStop = onStop
 	s.c = c
-	s.recipes = make(map[uint32]recipe.Recipe)
 	s.entityRuntimeIDs[c] = selfEntityRuntimeID
 	s.entities[selfEntityRuntimeID] = c
 
-	s.chunkLoader = world.NewLoader(int(s.chunkRadius), w, s)
+	s.chunkLoader = world.NewLoader(s.ViewDistance(), w, s)
 	s.chunkLoader.Move(pos)
-	s.writePacket(&packet.NetworkChunkPublisherUpdate{
-		Position: protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])},
-		Radius:   uint32(s.chunkRadius) << 4,
-	})
+	s.publishChunks(pos, true)
 
 	s.sendAvailableEntities(w)
 
@@ -193,22 +313,22 @@ func (s *Session) Spawn(c Controllable, pos mgl64.Vec3, w *world.World, gm world
 
 	world_add(c, w)
 	s.c.SetGameMode(gm)
-	s.SendSpeed(0.1)
+	s.SendAttribute(attribute.MovementSpeed, s.c.Speed())
 	for _, e := range s.c.Effects() {
 		s.SendEffect(e)
 	}
 
 	chat.Global.Subscribe(c)
 	if s.joinMessage != "" {
-		_, _ = fmt.Fprintln(chat.Global, text.Colourf("<yellow>%v</yellow>", fmt.Sprintf(s.joinMessage, s.conn.IdentityData().DisplayName)))
+		_, _ = fmt.Fprintln(chat.Global, text.Colourf("<yellow>"+s.joinMessage+"</yellow>", s.conn.IdentityData().DisplayName))
 	}
 
 	s.sendInv(s.inv, protocol.WindowIDInventory)
 	s.sendInv(s.ui, protocol.WindowIDUI)
 	s.sendInv(s.offHand, protocol.WindowIDOffHand)
 	s.sendInv(s.armour.Inventory(), protocol.WindowIDArmour)
-	s.writePacket(&packet.CreativeContent{Items: creativeItems()})
-	s.sendRecipes()
+	s.SendCreativeMenu(s.CreativeMenu())
+	s.SendRecipes()
 }
 
 // Start makes the session start handling incoming packets from the client.
@@ -257,10 +377,11 @@ func (s *Session) close() {
 	s.closePlayerList()
 	s.entityMutex.Lock()
 	s.entityRuntimeIDs, s.entities = map[world.Entity]uint64{}, map[uint64]world.Entity{}
+	s.entityMetadata = map[world.Entity]protocol.EntityMetadata{}
 	s.entityMutex.Unlock()
 
 	if s.quitMessage != "" {
-		_, _ = fmt.Fprintln(chat.Global, text.Colourf("<yellow>%v</yellow>", fmt.Sprintf(s.quitMessage, s.conn.IdentityData().DisplayName)))
+		_, _ = fmt.Fprintln(chat.Global, text.Colourf("<yellow>"+s.quitMessage+"</yellow>", s.conn.IdentityData().DisplayName))
 	}
 	chat.Global.Unsubscribe(s.c)
 }
@@ -269,6 +390,7 @@ func (s *Session) close() {
 // eventually.
 func (s *Session) CloseConnection() {
 	s.connOnce.Do(func() {
+		s.flush(true)
 		_ = s.conn.Close()
 		s.closeBackground <- struct{}{}
 	})
@@ -284,6 +406,12 @@ func (s *Session) Latency() time.Duration {
 	return s.conn.Latency()
 }
 
+// BytesPerSecond returns the amount of bytes sent to the connection in the last second. BytesPerSecond only returns
+// a value other than 0 if the session was created with a bandwidth budget.
+func (s *Session) BytesPerSecond() int {
+	return s.bandwidth.bytesPerSecond()
+}
+
 // ClientData returns the login.ClientData of the underlying *minecraft.Conn.
 func (s *Session) ClientData() login.ClientData {
 	return s.conn.ClientData()
@@ -309,7 +437,14 @@ func (s *Session) handlePackets() {
 		if err != nil {
 			return
 		}
-		if err := s.handlePacket(pk); err != nil {
+		packetsReceived.Inc()
+		if s.crash.Catch("session", s.crashContext(pk), func() { err = s.handlePacket(pk) }) {
+			// Handling the packet resulted in a panic. Only this session is affected, so we disconnect the
+			// player rather than crashing the server.
+			s.Disconnect(crashMessage)
+			return
+		}
+		if err != nil {
 			// An error occurred during the handling of a packet. Print the error and stop handling any more
 			// packets.
 			s.log.Debugf("failed processing packet from %v (%v): %v\n", s.conn.RemoteAddr(), s.c.Name(), err)
@@ -318,6 +453,30 @@ func (s *Session) handlePackets() {
 	}
 }
 
+// crashMessage is the message shown to a player disconnected because of a panic in its session.
+const crashMessage = "An internal server error occurred."
+
+// crashContext returns a function that returns the context of a crash that occurred in the Session while handling
+// the packet passed. If pk is nil, the crash did not occur while handling a packet.
+func (s *Session) crashContext(pk packet.Packet) func() map[string]any {
+	return func() map[string]any {
+		m := map[string]any{
+			"player":  s.c.Name(),
+			"uuid":    s.c.UUID().String(),
+			"xuid":    s.c.XUID(),
+			"address": s.conn.RemoteAddr().String(),
+		}
+		if pk != nil {
+			m["packet"] = fmt.Sprintf("%T", pk)
+		}
+		if w := s.c.World(); w != nil {
+			m["world"], m["dimension"] = w.Name(), fmt.Sprint(w.Dimension())
+			m["position"] = s.c.Position()
+		}
+		return m
+	}
+}
+
 // background performs background tasks of the Session. This includes chunk sending and automatic command updating.
 // background returns when the Session's connection is closed using CloseConnection.
 func (s *Session) background() {
@@ -325,26 +484,47 @@ func (s *Session) background() {
 		t                 = time.NewTicker(time.Second / 20)
 		r                 = s.sendAvailableCommands()
 		enums, enumValues = s.enums()
-		ok                bool
+		ok, crashed       bool
 		i                 int
+		flush             <-chan time.Time
 	)
 	defer t.Stop()
+	if s.flushRate > 0 {
+		f := time.NewTicker(s.flushRate)
+		defer f.Stop()
+		flush = f.C
+	}
 
 	for {
 		select {
+		case <-flush:
+			s.flush(false)
 		case <-t.C:
-			s.sendChunks()
-
-			if i++; i%20 == 0 {
-				// Enum resending happens relatively often and frequent updates are more important than with full
-				// command changes. Those are generally only related to permission changes, which doesn't happen often.
-				s.resendEnums(enums, enumValues)
+			if crashed {
+				// The background tasks panicked before. Wait for the connection to close.
+				continue
 			}
-			if i%100 == 0 {
-				// Try to resend commands only every 5 seconds.
-				if r, ok = s.resendCommands(r); ok {
-					enums, enumValues = s.enums()
+			crashed = s.crash.Catch("session background", s.crashContext(nil), func() {
+				s.sendChunks()
+				s.spawnEntities()
+				s.revealBlocks()
+
+				if i++; i%20 == 0 {
+					// Enum resending happens relatively often and frequent updates are more important than with full
+					// command changes. Those are generally only related to permission changes, which doesn't happen often.
+					s.resendEnums(enums, enumValues)
 				}
+				if i%100 == 0 {
+					// Try to resend commands only every 5 seconds.
+					if r, ok = s.resendCommands(r); ok {
+						enums, enumValues = s.enums()
+					}
+				}
+			})
+			if crashed {
+				// Closing the connection makes handlePackets return, which closes the Session.
+				s.Disconnect(crashMessage)
+				_ = s.conn.Close()
 			}
 		case <-s.closeBackground:
 			return
@@ -352,27 +532,30 @@ func (s *Session) background() {
 	}
 }
 
-// sendChunks sends the next up to 4 chunks to the connection. What chunks are loaded depends on the connection of
-// the chunk loader and the chunks that were previously loaded.
+// sendChunks sends the next up to chunksPerTick chunks to the connection. What chunks are loaded depends on the
+// connection of the chunk loader and the chunks that were previously loaded.
 func (s *Session) sendChunks() {
 	pos := s.c.Position()
 	s.chunkLoader.Move(pos)
-	s.writePacket(&packet.NetworkChunkPublisherUpdate{
-		Position: protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])},
-		Radius:   uint32(s.chunkRadius) << 4,
-	})
-
-	const maxChunkTransactions = 8
-
+	switched := false
 	if w := s.c.World(); s.chunkLoader.World() != w && w != nil {
 		s.handleWorldSwitch(w)
+		switched = true
 	}
+	s.publishChunks(pos, switched)
+
+	// Clients with the client cache enabled may have at most two ticks worth of chunks waiting for blobs.
+	maxChunkTransactions := s.chunksPerTick * 2
 
 	s.blobMu.Lock()
 	toLoad := maxChunkTransactions - len(s.openChunkTransactions)
 	s.blobMu.Unlock()
-	if toLoad > 4 {
-		toLoad = 4
+	if toLoad > s.chunksPerTick {
+		toLoad = s.chunksPerTick
+	}
+	if s.bandwidth.exceeded() {
+		// The bandwidth budget of the session was exceeded, so hold back new chunks until the next second.
+		return
 	}
 	s.chunkLoader.Load(toLoad)
 }
@@ -467,6 +650,7 @@ func (s *Session) registerHandlers() {
 		packet.IDRequestAbility:        &RequestAbilityHandler{},
 		packet.IDRequestChunkRadius:    &RequestChunkRadiusHandler{},
 		packet.IDRespawn:               &RespawnHandler{},
+		packet.IDSetDifficulty:         &SetDifficultyHandler{},
 		packet.IDSubChunkRequest:       &SubChunkRequestHandler{},
 		packet.IDText:                  &TextHandler{},
 		packet.IDTickSync:              nil,
@@ -485,11 +669,23 @@ func (s *Session) handleInterfaceUpdate(slot int, _, item item.Stack) {
 	}
 }
 
-// writePacket writes a packet to the session's connection if it is not Nop.
+// writePacket writes a packet to the session's connection if it is not Nop. The packet is queued until the next
+// flush if the session has a flush rate.
 func (s *Session) writePacket(pk packet.Packet) {
 	if s == Nop {
 		return
 	}
+	s.queuePacket(pk)
+}
+
+// sendPacket writes a packet to the Conn of the Session immediately.
+func (s *Session) sendPacket(pk packet.Packet) {
+	packetsSent.Inc()
+	if n, ok := s.enc.write(pk); ok {
+		s.bandwidth.add(n)
+		return
+	}
+	s.bandwidth.track(pk)
 	_ = s.conn.WritePacket(pk)
 }
 
@@ -501,11 +697,14 @@ func (s *Session) initPlayerList() {
 	for _, session := range sessions {
 		// AddStack the player of the session to all sessions currently open, and add the players of all sessions
 		// currently open to the player list of the new session.
-		session.addToPlayerList(s)
+		session.addToPlayerList(s.c)
 		if s != session {
-			s.addToPlayerList(session)
+			s.addToPlayerList(session.c)
 		}
 	}
+	for _, c := range listed {
+		s.addToPlayerList(c)
+	}
 	sessionMu.Unlock()
 }
 
@@ -515,12 +714,51 @@ func (s *Session) closePlayerList() {
 	sessionMu.Lock()
 	for _, session := range sessions {
 		// Remove the player of the session from the player list of all other sessions.
-		session.removeFromPlayerList(s)
+		session.removeFromPlayerList(s.c)
 	}
 	sessions = sliceutil.DeleteVal(sessions, s)
 	sessionMu.Unlock()
 }
 
+// AddToPlayerList adds a Controllable that is not controlled by a Session, such as a fake player, to the player
+// list of all sessions, including sessions opened afterwards. The Controllable is shown in the player list until
+// RemoveFromPlayerList is called.
+func AddToPlayerList(c Controllable) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	if listedIndex(c) != -1 {
+		return
+	}
+	listed = append(listed, c)
+	for _, s := range sessions {
+		s.addToPlayerList(c)
+	}
+}
+
+// RemoveFromPlayerList removes a Controllable added using AddToPlayerList from the player list of all sessions.
+func RemoveFromPlayerList(c Controllable) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	i := listedIndex(c)
+	if i == -1 {
+		return
+	}
+	listed = append(listed[:i], listed[i+1:]...)
+	for _, s := range sessions {
+		s.removeFromPlayerList(c)
+	}
+}
+
+// listedIndex returns the index of a Controllable in listed, or -1 if it was not added using AddToPlayerList.
+func listedIndex(c Controllable) int {
+	for i, other := range listed {
+		if other == c {
+			return i
+		}
+	}
+	return -1
+}
+
 // actorIdentifier represents the structure of an actor identifier sent over the network.
 type actorIdentifier struct {
 	// ID is a unique namespaced identifier for the entity.
@@ -0,0 +1,114 @@
+package session
+
+import (
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"sort"
+	"sync"
+)
+
+// spawnQueue holds the entities waiting to be spawned to a Session. Entities entering the view of a player are not
+// spawned right away, but at most entitiesPerTick every tick, closest to the player first. This prevents a burst of
+// packets, and the client hitching as a result, when many entities enter the view at once, for example after
+// teleporting or changing worlds.
+type spawnQueue struct {
+	mu sync.Mutex
+	// pending holds the entities waiting to be spawned.
+	pending map[world.Entity]struct{}
+	// spawning holds the entities taken from pending that are being spawned. If one of these entities is hidden
+	// before spawning it finished, its value is set to true, so that it can be removed right after.
+	spawning map[world.Entity]bool
+}
+
+// newSpawnQueue returns a new, empty spawnQueue.
+func newSpawnQueue() *spawnQueue {
+	return &spawnQueue{pending: map[world.Entity]struct{}{}, spawning: map[world.Entity]bool{}}
+}
+
+// add queues the entity passed to be spawned.
+func (q *spawnQueue) add(e world.Entity) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending[e] = struct{}{}
+}
+
+// remove removes the entity passed from the queue. True is returned if the entity was still waiting to be spawned,
+// in which case it was never spawned to the client. If the entity is being spawned at the same time, it is marked so
+// that spawnEntities removes it again once it has been spawned.
+func (q *spawnQueue) remove(e world.Entity) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.pending[e]; ok {
+		delete(q.pending, e)
+		return true
+	}
+	if _, ok := q.spawning[e]; ok {
+		q.spawning[e] = true
+	}
+	return false
+}
+
+// take takes up to n of the entities waiting to be spawned from the queue, closest to the position passed first.
+// Every entity returned must be passed to done once it has been spawned.
+func (q *spawnQueue) take(pos mgl64.Vec3, n int) []world.Entity {
+	q.mu.Lock()
+	entities := make([]world.Entity, 0, len(q.pending))
+	for e := range q.pending {
+		entities = append(entities, e)
+	}
+	q.mu.Unlock()
+	if len(entities) == 0 {
+		return nil
+	}
+
+	if len(entities) > n {
+		// The positions of the entities are read without holding the lock, because the entities may hold their own
+		// lock while hiding themselves from viewers.
+		dist := make(map[world.Entity]float64, len(entities))
+		for _, e := range entities {
+			dist[e] = e.Position().Sub(pos).LenSqr()
+		}
+		sort.Slice(entities, func(i, j int) bool {
+			return dist[entities[i]] < dist[entities[j]]
+		})
+		entities = entities[:n]
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	taken := entities[:0]
+	for _, e := range entities {
+		// Entities may have been removed from the queue while the lock was released.
+		if _, ok := q.pending[e]; ok {
+			delete(q.pending, e)
+			q.spawning[e] = false
+			taken = append(taken, e)
+		}
+	}
+	return taken
+}
+
+// done marks the entity passed, previously returned by take, as spawned. True is returned if the entity was hidden
+// while it was being spawned, in which case it must be removed from the client again.
+func (q *spawnQueue) done(e world.Entity) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	hidden := q.spawning[e]
+	delete(q.spawning, e)
+	return hidden
+}
+
+// spawnEntities spawns up to entitiesPerTick of the entities waiting to be spawned to the Session, closest to the
+// player first. All packets are written in the same tick, so that they are sent to the client in a single batch.
+func (s *Session) spawnEntities() {
+	for _, e := range s.spawns.take(s.c.Position(), s.entitiesPerTick) {
+		if !s.entityHidden(e) {
+			s.spawnEntity(e)
+			s.ViewEntityItems(e)
+			s.ViewEntityArmour(e)
+		}
+		if s.spawns.done(e) {
+			s.HideEntity(e)
+		}
+	}
+}
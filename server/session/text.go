@@ -15,6 +15,17 @@ func (s *Session) SendMessage(message string) {
 	})
 }
 
+// SendTranslation sends a message that the client translates using its own language files. The key passed is the
+// translation key of the message and the parameters replace the placeholders in it.
+func (s *Session) SendTranslation(key string, parameters []string) {
+	s.writePacket(&packet.Text{
+		TextType:         packet.TextTypeTranslation,
+		NeedsTranslation: true,
+		Message:          "%" + key,
+		Parameters:       parameters,
+	})
+}
+
 // SendTip ...
 func (s *Session) SendTip(message string) {
 	s.writePacket(&packet.Text{
@@ -157,6 +168,11 @@ func (s *Session) SendSubtitle(text string) {
 	s.writePacket(&packet.SetTitle{ActionType: packet.TitleActionSetSubtitle, Text: text})
 }
 
+// ClearTitle ...
+func (s *Session) ClearTitle() {
+	s.writePacket(&packet.SetTitle{ActionType: packet.TitleActionClear})
+}
+
 // SendActionBarMessage ...
 func (s *Session) SendActionBarMessage(text string) {
 	s.writePacket(&packet.SetTitle{ActionType: packet.TitleActionSetActionBar, Text: text})
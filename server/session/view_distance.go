@@ -0,0 +1,82 @@
+package session
+
+import (
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"math"
+)
+
+// SetViewDistance limits the chunk radius of the client to r chunks, regardless of the chunk radius that the client
+// requests itself. The chunk radius is always limited by the maximum chunk radius of the server, too. If r is 0 or
+// lower, the limit is removed and the chunk radius requested by the client is used.
+func (s *Session) SetViewDistance(r int) {
+	if s == Nop {
+		return
+	}
+	s.radiusMu.Lock()
+	s.viewDistance = int32(r)
+	s.radiusMu.Unlock()
+	s.updateChunkRadius(false)
+}
+
+// ViewDistance returns the chunk radius in which chunks are currently sent to the client. It is the lowest of the
+// chunk radius requested by the client, the limit set using SetViewDistance and the maximum chunk radius of the
+// server.
+func (s *Session) ViewDistance() int {
+	if s == Nop {
+		return 0
+	}
+	s.radiusMu.Lock()
+	defer s.radiusMu.Unlock()
+	return int(s.chunkRadius)
+}
+
+// requestChunkRadius changes the chunk radius requested by the client and updates the chunk radius used to send
+// chunks accordingly.
+func (s *Session) requestChunkRadius(r int32) {
+	s.radiusMu.Lock()
+	s.requestedRadius = r
+	s.radiusMu.Unlock()
+	s.updateChunkRadius(true)
+}
+
+// updateChunkRadius calculates the chunk radius used to send chunks to the client and updates the chunk loader if
+// it changed. The client is notified of the chunk radius if it changed or if always is true.
+func (s *Session) updateChunkRadius(always bool) {
+	s.radiusMu.Lock()
+	r := s.requestedRadius
+	if s.viewDistance > 0 && s.viewDistance < r {
+		r = s.viewDistance
+	}
+	if r > s.maxChunkRadius {
+		r = s.maxChunkRadius
+	}
+	changed := r != s.chunkRadius
+	s.chunkRadius = r
+	s.radiusMu.Unlock()
+
+	if changed && s.chunkLoader != nil {
+		s.chunkLoader.ChangeRadius(int(r))
+	}
+	if changed || always {
+		s.writePacket(&packet.ChunkRadiusUpdated{ChunkRadius: r})
+	}
+}
+
+// publishChunks sends a NetworkChunkPublisherUpdate to the client if the chunk that it is in or its chunk radius
+// changed since the last one was sent, or if force is true. The client only keeps the chunks within the radius of
+// the last update loaded and unloads chunks out of range.
+func (s *Session) publishChunks(pos mgl64.Vec3, force bool) {
+	r := int32(s.ViewDistance())
+	chunkPos := world.ChunkPos{int32(math.Floor(pos[0])) >> 4, int32(math.Floor(pos[2])) >> 4}
+	if !force && s.published && chunkPos == s.publishedPos && r == s.publishedRadius {
+		return
+	}
+	s.published, s.publishedPos, s.publishedRadius = true, chunkPos, r
+	s.writePacket(&packet.NetworkChunkPublisherUpdate{
+		Position: protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])},
+		Radius:   uint32(r) << 4,
+	})
+}
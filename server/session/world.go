@@ -55,6 +55,17 @@ func (s *Session) ViewEntity(e world.Entity) {
 	if s.entityHidden(e) {
 		return
 	}
+	if s.entitiesPerTick > 0 {
+		// The entity is spawned during one of the next ticks, along with other entities entering the view of the
+		// player. See spawnEntities.
+		s.spawns.add(e)
+		return
+	}
+	s.spawnEntity(e)
+}
+
+// spawnEntity spawns the entity passed to the client right away and assigns it a runtime ID.
+func (s *Session) spawnEntity(e world.Entity) {
 	var runtimeID uint64
 
 	_, controllable := e.(Controllable)
@@ -72,6 +83,9 @@ func (s *Session) ViewEntity(e world.Entity) {
 
 	yaw, pitch := e.Rotation().Elem()
 	metadata := s.parseEntityMetadata(e)
+	// Only store the metadata after the entity was spawned, so that no changes are sent before that.
+	defer s.storeEntityMetadata(e, metadata)
+	defer s.viewEntityLink(e)
 
 	id := e.Type().EncodeEntity()
 	switch v := e.(type) {
@@ -85,6 +99,12 @@ func (s *Session) ViewEntity(e world.Entity) {
 				break
 			}
 		}
+		for _, c := range listed {
+			if c.UUID() == v.UUID() {
+				actualPlayer = true
+				break
+			}
+		}
 		sessionMu.Unlock()
 		if !actualPlayer {
 			s.writePacket(&packet.PlayerList{ActionType: packet.PlayerListActionAdd, Entries: []protocol.PlayerListEntry{{
@@ -131,7 +151,7 @@ func (s *Session) ViewEntity(e world.Entity) {
 				EntityMetadata:  metadata,
 			})
 			return
-		case entity.TextType:
+		case entity.TextType, entity.SeatType:
 			metadata[protocol.EntityDataKeyVariant] = int32(world.BlockRuntimeID(block.Air{}))
 		case entity.FallingBlockType:
 			metadata[protocol.EntityDataKeyVariant] = int32(world.BlockRuntimeID(v.Behaviour().(*entity.FallingBlockBehaviour).Block()))
@@ -159,9 +179,49 @@ func (s *Session) ViewEntity(e world.Entity) {
 	})
 }
 
+// viewEntityLink links the entity passed to the seat it is seated on, or the rider seated on it, if both entities
+// are spawned to the Session.
+func (s *Session) viewEntityLink(e world.Entity) {
+	rider, seat := e, world.Entity(nil)
+	if r, ok := e.(seated); ok {
+		if st, ok := r.Seat(); ok {
+			seat = st
+		}
+	} else if ent, ok := e.(*entity.Ent); ok {
+		if b, ok := ent.Behaviour().(*entity.SeatBehaviour); ok {
+			if r, ok := b.Rider().(seated); ok {
+				if st, ok := r.Seat(); ok && st == ent {
+					rider, seat = b.Rider(), ent
+				}
+			}
+		}
+	}
+	if seat == nil || !s.entitySpawned(rider) || !s.entitySpawned(seat) {
+		return
+	}
+	s.writePacket(&packet.SetActorLink{EntityLink: protocol.EntityLink{
+		RiddenEntityUniqueID: int64(s.entityRuntimeID(seat)),
+		RiderEntityUniqueID:  int64(s.entityRuntimeID(rider)),
+		Type:                 protocol.EntityLinkPassenger,
+	}})
+}
+
+// ViewEntityDismount ...
+func (s *Session) ViewEntityDismount(e, vehicle world.Entity) {
+	if !s.entitySpawned(e) || !s.entitySpawned(vehicle) {
+		return
+	}
+	s.writePacket(&packet.SetActorLink{EntityLink: protocol.EntityLink{
+		RiddenEntityUniqueID: int64(s.entityRuntimeID(vehicle)),
+		RiderEntityUniqueID:  int64(s.entityRuntimeID(e)),
+		Type:                 protocol.EntityLinkRemove,
+		Immediate:            true,
+	}})
+}
+
 // ViewEntityGameMode ...
 func (s *Session) ViewEntityGameMode(e world.Entity) {
-	if s.entityHidden(e) {
+	if !s.entitySpawned(e) {
 		return
 	}
 	c, ok := e.(Controllable)
@@ -179,6 +239,10 @@ func (s *Session) HideEntity(e world.Entity) {
 	if s.entityRuntimeID(e) == selfEntityRuntimeID {
 		return
 	}
+	if s.spawns.remove(e) {
+		// The entity was never spawned to the client, so there is nothing to remove.
+		return
+	}
 
 	s.entityMutex.Lock()
 	id, ok := s.entityRuntimeIDs[e]
@@ -186,6 +250,7 @@ func (s *Session) HideEntity(e world.Entity) {
 		delete(s.entityRuntimeIDs, e)
 		delete(s.entities, id)
 	}
+	delete(s.entityMetadata, e)
 	s.entityMutex.Unlock()
 	if !ok {
 		// The entity was already removed some other way. We don't need to send a packet.
@@ -197,7 +262,7 @@ func (s *Session) HideEntity(e world.Entity) {
 // ViewEntityMovement ...
 func (s *Session) ViewEntityMovement(e world.Entity, pos mgl64.Vec3, rot cube.Rotation, onGround bool) {
 	id := s.entityRuntimeID(e)
-	if id == selfEntityRuntimeID || s.entityHidden(e) {
+	if id == selfEntityRuntimeID || !s.entitySpawned(e) {
 		return
 	}
 
@@ -215,7 +280,7 @@ func (s *Session) ViewEntityMovement(e world.Entity, pos mgl64.Vec3, rot cube.Ro
 
 // ViewEntityVelocity ...
 func (s *Session) ViewEntityVelocity(e world.Entity, velocity mgl64.Vec3) {
-	if s.entityHidden(e) {
+	if !s.entitySpawned(e) {
 		return
 	}
 	s.writePacket(&packet.SetActorMotion{
@@ -240,7 +305,7 @@ func (s *Session) ViewTime(time int) {
 // ViewEntityTeleport ...
 func (s *Session) ViewEntityTeleport(e world.Entity, position mgl64.Vec3) {
 	id := s.entityRuntimeID(e)
-	if s.entityHidden(e) {
+	if !s.entitySpawned(e) {
 		return
 	}
 
@@ -273,7 +338,7 @@ func (s *Session) ViewEntityTeleport(e world.Entity, position mgl64.Vec3) {
 // ViewEntityItems ...
 func (s *Session) ViewEntityItems(e world.Entity) {
 	runtimeID := s.entityRuntimeID(e)
-	if runtimeID == selfEntityRuntimeID || s.entityHidden(e) {
+	if runtimeID == selfEntityRuntimeID || !s.entitySpawned(e) {
 		// Don't view the items of the entity if the entity is the Controllable entity of the session.
 		return
 	}
@@ -300,7 +365,7 @@ func (s *Session) ViewEntityItems(e world.Entity) {
 // ViewEntityArmour ...
 func (s *Session) ViewEntityArmour(e world.Entity) {
 	runtimeID := s.entityRuntimeID(e)
-	if runtimeID == selfEntityRuntimeID || s.entityHidden(e) {
+	if runtimeID == selfEntityRuntimeID || !s.entitySpawned(e) {
 		// Don't view the items of the entity if the entity is the Controllable entity of the session.
 		return
 	}
@@ -334,6 +399,10 @@ func (s *Session) ViewItemCooldown(item world.Item, duration time.Duration) {
 
 // ViewParticle ...
 func (s *Session) ViewParticle(pos mgl64.Vec3, p world.Particle) {
+	if s.bandwidth.exceeded() {
+		// Particles are purely cosmetic, so don't send them if the bandwidth budget was exceeded.
+		return
+	}
 	switch pa := p.(type) {
 	case particle.DragonEggTeleport:
 		xSign, ySign, zSign := 0, 0, 0
@@ -420,6 +489,13 @@ func (s *Session) ViewParticle(pos mgl64.Vec3, p world.Particle) {
 			EventData: (rid << 16) | int32(meta),
 			Position:  vec64To32(pos),
 		})
+	case particle.ItemBreak:
+		rid, meta, _ := world.ItemRuntimeID(pa.Item)
+		s.writePacket(&packet.LevelEvent{
+			EventType: packet.LevelEventParticleLegacyEvent | 14,
+			EventData: (rid << 16) | int32(meta),
+			Position:  vec64To32(pos),
+		})
 	case particle.Splash:
 		if (pa.Colour == color.RGBA{}) {
 			pa.Colour, _ = effect.ResultingColour(nil)
@@ -518,6 +594,14 @@ func (s *Session) playSound(pos mgl64.Vec3, t world.Sound, disableRelative bool)
 		return
 	case sound.Explosion:
 		pk.SoundType = packet.SoundEventExplode
+	case sound.ShieldBlock:
+		pk.SoundType = packet.SoundEventShieldBlock
+	case sound.Totem:
+		s.writePacket(&packet.LevelEvent{
+			EventType: packet.LevelEventSoundTotemUsed,
+			Position:  vec64To32(pos),
+		})
+		return
 	case sound.Thunder:
 		pk.SoundType, pk.EntityType = packet.SoundEventThunder, "minecraft:lightning_bolt"
 	case sound.Click:
@@ -666,6 +750,10 @@ func (s *Session) playSound(pos mgl64.Vec3, t world.Sound, disableRelative bool)
 		pk.SoundType = packet.SoundEventBarrelClose
 	case sound.BarrelOpen:
 		pk.SoundType = packet.SoundEventBarrelOpen
+	case sound.PistonExtend:
+		pk.SoundType = packet.SoundEventPistonOut
+	case sound.PistonRetract:
+		pk.SoundType = packet.SoundEventPistonIn
 	case sound.BlockBreaking:
 		pk.SoundType, pk.ExtraData = packet.SoundEventHit, int32(world.BlockRuntimeID(so.Block))
 	case sound.ItemBreak:
@@ -773,6 +861,11 @@ func (s *Session) PlaySound(t world.Sound) {
 	s.playSound(entity.EyePosition(s.c), t, true)
 }
 
+// Position ...
+func (s *Session) Position() mgl64.Vec3 {
+	return s.c.Position()
+}
+
 // ViewSound ...
 func (s *Session) ViewSound(pos mgl64.Vec3, soundType world.Sound) {
 	s.playSound(pos, soundType, false)
@@ -780,6 +873,7 @@ func (s *Session) ViewSound(pos mgl64.Vec3, soundType world.Sound) {
 
 // OpenSign ...
 func (s *Session) OpenSign(pos cube.Pos, frontSide bool) {
+	s.openedSign.Store(&pos)
 	blockPos := protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])}
 	s.writePacket(&packet.OpenSign{
 		Position:  blockPos,
@@ -823,6 +917,9 @@ func (s *Session) ViewBlockUpdate(pos cube.Pos, b world.Block, layer int) {
 		Flags:             packet.BlockUpdateNetwork,
 		Layer:             uint32(layer),
 	})
+	if layer == 0 {
+		s.revealAround(pos)
+	}
 	if v, ok := b.(world.NBTer); ok {
 		NBTData := v.EncodeNBT()
 		NBTData["x"], NBTData["y"], NBTData["z"] = int32(pos.X()), int32(pos.Y()), int32(pos.Z())
@@ -877,6 +974,16 @@ func (s *Session) ViewEntityAction(e world.Entity, a world.EntityAction) {
 			EventType:       packet.ActorEventShake,
 			EventData:       int32(act.Duration.Milliseconds() / 50),
 		})
+	case entity.TotemUseAction:
+		s.writePacket(&packet.ActorEvent{
+			EntityRuntimeID: s.entityRuntimeID(e),
+			EventType:       packet.ActorEventTalismanActivate,
+		})
+	case entity.WakeUpAction:
+		s.writePacket(&packet.Animate{
+			ActionType:      packet.AnimateActionStopSleep,
+			EntityRuntimeID: s.entityRuntimeID(e),
+		})
 	case entity.FireworkExplosionAction:
 		s.writePacket(&packet.ActorEvent{
 			EntityRuntimeID: s.entityRuntimeID(e),
@@ -908,10 +1015,12 @@ func (s *Session) ViewEntityAction(e world.Entity, a world.EntityAction) {
 
 // ViewEntityState ...
 func (s *Session) ViewEntityState(e world.Entity) {
-	s.writePacket(&packet.SetActorData{
-		EntityRuntimeID: s.entityRuntimeID(e),
-		EntityMetadata:  s.parseEntityMetadata(e),
-	})
+	if m, ok := s.entityMetadataChanges(e); ok {
+		s.writePacket(&packet.SetActorData{
+			EntityRuntimeID: s.entityRuntimeID(e),
+			EntityMetadata:  m,
+		})
+	}
 }
 
 // ViewEntityAnimation ...
@@ -996,6 +1105,8 @@ func (s *Session) openNormalContainer(b block.Container, pos cube.Pos) {
 		containerType = protocol.ContainerTypeBlastFurnace
 	case block.Smoker:
 		containerType = protocol.ContainerTypeSmoker
+	case block.Hopper:
+		containerType = protocol.ContainerTypeHopper
 	}
 
 	s.writePacket(&packet.ContainerOpen{
@@ -1109,6 +1220,28 @@ func (s *Session) ViewWeather(raining, thunder bool) {
 	s.writePacket(pk)
 }
 
+// ViewDifficulty ...
+func (s *Session) ViewDifficulty(d world.Difficulty) {
+	id, _ := world.DifficultyID(d)
+	s.writePacket(&packet.SetDifficulty{Difficulty: uint32(id)})
+}
+
+// ViewGameRules ...
+func (s *Session) ViewGameRules(rules map[string]any) {
+	gameRules := make([]protocol.GameRule, 0, len(rules))
+	for name, v := range rules {
+		if n, ok := v.(int); ok {
+			// Integer game rules are sent as unsigned integers, so negative values cannot be sent.
+			if n < 0 {
+				n = 0
+			}
+			v = uint32(n)
+		}
+		gameRules = append(gameRules, protocol.GameRule{Name: name, Value: v})
+	}
+	s.sendGameRules(gameRules)
+}
+
 // nextWindowID produces the next window ID for a new window. It is an int of 1-99.
 func (s *Session) nextWindowID() byte {
 	if s.openedWindowID.CAS(99, 1) {
@@ -1138,6 +1271,15 @@ func (s *Session) entityRuntimeID(e world.Entity) uint64 {
 	return id
 }
 
+// entitySpawned checks if the entity passed is currently spawned to the Session and not hidden from it.
+func (s *Session) entitySpawned(e world.Entity) bool {
+	s.entityMutex.RLock()
+	_, spawned := s.entityRuntimeIDs[e]
+	_, hidden := s.hiddenEntities[e]
+	s.entityMutex.RUnlock()
+	return spawned && !hidden
+}
+
 // entityFromRuntimeID attempts to return an entity by its runtime ID. False is returned if no entity with the
 // ID could be found.
 func (s *Session) entityFromRuntimeID(id uint64) (world.Entity, bool) {
@@ -0,0 +1,118 @@
+// Package spawn implements the /setworldspawn and /spawnpoint commands, which change the position that players
+// spawn at when joining a world or respawning after death.
+package spawn
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// Commands returns the /setworldspawn and /spawnpoint commands. The commands may be registered using cmd.Register.
+// allow is called to check if a cmd.Source may execute the commands. If nil and no cmd.Permissions were set using
+// cmd.SetPermissions, the commands may only be executed by sources that are not players, such as the console. If nil
+// and cmd.Permissions were set, the permission node of each command (such as 'command.spawnpoint') decides which
+// sources may execute it.
+func Commands(allow func(src cmd.Source) bool) []cmd.Command {
+	if allow == nil {
+		allow = func(src cmd.Source) bool {
+			_, ok := src.(*player.Player)
+			return !ok || cmd.HasPermissions()
+		}
+	}
+	return []cmd.Command{
+		cmd.New("setworldspawn", "Sets the spawn of the world.", nil, setWorldSpawnCommand{allow: allow}),
+		cmd.New("spawnpoint", "Sets the position that a player respawns at.", nil, spawnPointCommand{allow: allow}),
+	}
+}
+
+// setWorldSpawnCommand implements the /setworldspawn command.
+type setWorldSpawnCommand struct {
+	allow func(src cmd.Source) bool
+
+	Position cmd.Optional[mgl64.Vec3] `cmd:"position"`
+}
+
+// Run ...
+func (c setWorldSpawnCommand) Run(src cmd.Source, o *cmd.Output) {
+	w := src.World()
+	if w == nil {
+		o.Error("The spawn can only be set by a source that is in a world.")
+		return
+	}
+	pos, ok := position(src, c.Position)
+	if !ok {
+		o.Error("A position must be passed to set the spawn of the world.")
+		return
+	}
+	if pos.OutOfBounds(w.Range()) {
+		o.Errorf("The position %v is outside of the world.", pos)
+		return
+	}
+	w.SetSpawn(pos)
+	o.Printf("Set the spawn of %v to %v.", w.Name(), pos)
+}
+
+// Allow ...
+func (c setWorldSpawnCommand) Allow(src cmd.Source) bool {
+	return c.allow(src)
+}
+
+// spawnPointCommand implements the /spawnpoint command.
+type spawnPointCommand struct {
+	allow func(src cmd.Source) bool
+
+	Targets  cmd.Optional[[]cmd.Target] `cmd:"player"`
+	Position cmd.Optional[mgl64.Vec3]   `cmd:"position"`
+}
+
+// Run ...
+func (c spawnPointCommand) Run(src cmd.Source, o *cmd.Output) {
+	targets, ok := c.Targets.Load()
+	if !ok {
+		if _, isPlayer := src.(*player.Player); !isPlayer {
+			o.Error("A player must be passed to set the spawn point of.")
+			return
+		}
+		targets = []cmd.Target{src}
+	}
+	for _, t := range targets {
+		p, ok := t.(*player.Player)
+		if !ok {
+			continue
+		}
+		pos, ok := c.Position.Load()
+		if !ok {
+			pos = p.Position()
+		}
+		// Players always respawn in the world that a portal of their current dimension leads back to, which is
+		// the overworld for the default worlds, so the spawn point is set in that world.
+		w := p.World()
+		if w == nil {
+			o.Errorf("%v is not in a world.", p.Name())
+			continue
+		}
+		w = w.PortalDestination(w.Dimension())
+		spawn := cube.PosFromVec3(pos)
+		w.SetPlayerSpawn(p.UUID(), spawn)
+		o.Printf("Set the spawn point of %v to %v.", p.Name(), spawn)
+	}
+}
+
+// Allow ...
+func (c spawnPointCommand) Allow(src cmd.Source) bool {
+	return c.allow(src)
+}
+
+// position returns the block position passed to a command, or the position of the source if it is a player and no
+// position was passed.
+func position(src cmd.Source, pos cmd.Optional[mgl64.Vec3]) (cube.Pos, bool) {
+	if v, ok := pos.Load(); ok {
+		return cube.PosFromVec3(v), true
+	}
+	if p, ok := src.(*player.Player); ok {
+		return cube.PosFromVec3(p.Position()), true
+	}
+	return cube.Pos{}, false
+}
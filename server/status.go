@@ -2,21 +2,94 @@ package server
 
 import (
 	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
 )
 
-// statusProvider handles the way the server shows up in the server list. The
-// online players and maximum players are not changeable from outside the
-// server, but the server name may be changed at any time.
+// Status holds the information about a Server that is advertised in the
+// server list of clients and to server list sites using the query protocol.
+type Status struct {
+	// MOTD is the message of the day, shown as the name of the server in the
+	// server list. It defaults to Config.Name.
+	MOTD string
+	// PlayerCount is the amount of players shown as online and MaxPlayers the
+	// maximum amount of players shown.
+	PlayerCount, MaxPlayers int
+	// Players holds the names of the players shown as online. Players are only
+	// advertised through the query protocol.
+	Players []string
+	// Version is the Minecraft version that the server runs on.
+	Version string
+	// Map is the name of the world that players join.
+	Map string
+	// GameType is the type of game advertised through the query protocol. It
+	// defaults to 'SMP'.
+	GameType string
+	// Plugins holds the names of the plugins advertised through the query
+	// protocol. It is empty by default.
+	Plugins []string
+	// Whitelist specifies if only whitelisted players may join. It defaults
+	// to the state of the whitelist of the Allower of the Server, if it has
+	// one, and is only advertised through the query protocol.
+	Whitelist bool
+}
+
+// whitelister is implemented by Allowers that have a whitelist, such as
+// moderation.Manager.
+type whitelister interface {
+	WhitelistEnabled() bool
+}
+
+// StatusFunc is a function that may be passed to Server.SetStatusFunc to
+// change the Status of a Server before it is advertised.
+type StatusFunc func(s *Status)
+
+// SetStatusFunc sets a function that is called with the Status of the Server
+// every time it is advertised, so that it may be changed dynamically, for
+// example to show a different MOTD or to add the names of plugins. Passing nil
+// removes the function.
+func (srv *Server) SetStatusFunc(f StatusFunc) {
+	srv.statusFunc.Store(f)
+}
+
+// Status returns the Status of the Server that is advertised in the server
+// list and through the query protocol, after passing it to the function set
+// using SetStatusFunc.
+func (srv *Server) Status() Status {
+	players := srv.Players()
+	s := Status{
+		MOTD:        srv.conf.Name,
+		PlayerCount: len(players),
+		MaxPlayers:  srv.MaxPlayerCount(),
+		Players:     make([]string, 0, len(players)),
+		Version:     protocol.CurrentVersion,
+		Map:         srv.World().Name(),
+		GameType:    "SMP",
+	}
+	for _, p := range players {
+		s.Players = append(s.Players, p.Name())
+	}
+	if w, ok := srv.conf.Allower.(whitelister); ok {
+		s.Whitelist = w.WhitelistEnabled()
+	}
+	if f := srv.statusFunc.Load(); f != nil {
+		f(&s)
+	}
+	return s
+}
+
+// statusProvider handles the way the server shows up in the server list,
+// using the Status returned by the status function passed.
 type statusProvider struct {
-	name string
+	status func() Status
 }
 
-// ServerStatus returns the player count, max players and the server's name as
-// a minecraft.ServerStatus.
-func (s statusProvider) ServerStatus(playerCount, maxPlayers int) minecraft.ServerStatus {
+// ServerStatus returns the MOTD, player count and max players of the Status
+// of the server as a minecraft.ServerStatus.
+func (s statusProvider) ServerStatus(int, int) minecraft.ServerStatus {
+	status := s.status()
 	return minecraft.ServerStatus{
-		ServerName:  s.name,
-		PlayerCount: playerCount,
-		MaxPlayers:  maxPlayers,
+		ServerName:  status.MOTD,
+		PlayerCount: status.PlayerCount,
+		MaxPlayers:  status.MaxPlayers,
 	}
 }
@@ -0,0 +1,115 @@
+// Package text implements utilities for formatting text shown to players, such as chat messages, titles and the
+// text of forms. Text is formatted using the formatting codes of Minecraft: Bedrock Edition, which may be written
+// directly using the constants in this package or using HTML-like tags through Colourf.
+package text
+
+import (
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Formatting codes that may be used to colour and format text. A formatting code applies to all text that follows
+// it, until a Reset code is found.
+const (
+	Black      = "§0"
+	DarkBlue   = "§1"
+	DarkGreen  = "§2"
+	DarkAqua   = "§3"
+	DarkRed    = "§4"
+	DarkPurple = "§5"
+	Orange     = "§6"
+	Grey       = "§7"
+	DarkGrey   = "§8"
+	Blue       = "§9"
+	Green      = "§a"
+	Aqua       = "§b"
+	Red        = "§c"
+	Purple     = "§d"
+	Yellow     = "§e"
+	White      = "§f"
+	DarkYellow = "§g"
+	Quartz     = "§h"
+	Iron       = "§i"
+	Netherite  = "§j"
+	Obfuscated = "§k"
+	Bold       = "§l"
+	Redstone   = "§m"
+	Copper     = "§n"
+	Italic     = "§o"
+	Gold       = "§p"
+	Emerald    = "§q"
+	Reset      = "§r"
+	Diamond    = "§s"
+	Lapis      = "§t"
+	Amethyst   = "§u"
+)
+
+// codes matches all formatting codes in a string.
+var codes = regexp.MustCompile("§[0-9a-u]")
+
+// Colourf formats the format string passed using HTML-like tags, after substituting the values passed in it
+// following the rules of fmt.Sprintf. Unlike the values passed, tags in the format string are not shown, but instead
+// colour and format the text within them, for example:
+//
+//	text.Colourf("<red>Hello <bold>%v</bold>!</red>", name)
+//
+// Available tags are black, dark-blue, dark-green, dark-aqua, dark-red, dark-purple, gold, grey, dark-grey, blue,
+// green, aqua, red, purple, yellow, white, dark-yellow, quartz, iron, netherite, redstone, copper, gold, emerald,
+// diamond, lapis, amethyst, obfuscated, bold (b) and italic (i).
+// String values and values implementing fmt.Stringer are escaped using Escape before substituting them, so that tags
+// in them, such as in messages written by players, are shown as is. Other values are passed on unchanged, so that
+// verbs such as %.2f and %x keep working. The string returned always ends with formatting reset.
+func Colourf(format string, a ...any) string {
+	args := make([]any, len(a))
+	for i, v := range a {
+		switch v := v.(type) {
+		case string:
+			args[i] = Escape(v)
+		case fmt.Stringer:
+			args[i] = Escape(v.String())
+		default:
+			args[i] = v
+		}
+	}
+	return Terminate(text.Colourf(format, args...))
+}
+
+// Escape escapes the HTML-like tags in the string passed, so that the string may be used in the format string of
+// Colourf without its tags being turned into formatting codes.
+func Escape(s string) string {
+	return html.EscapeString(s)
+}
+
+// Clean removes all formatting codes from the string passed.
+func Clean(s string) string {
+	return codes.ReplaceAllString(s, "")
+}
+
+// Terminate appends a Reset code to the string passed if the formatting of the string would otherwise carry over
+// to text that follows it, such as when a player name with formatting codes is followed by a message.
+func Terminate(s string) string {
+	loc := codes.FindAllStringIndex(s, -1)
+	if len(loc) == 0 || s[loc[len(loc)-1][0]:loc[len(loc)-1][1]] == Reset {
+		return s
+	}
+	return s + Reset
+}
+
+// ANSI converts all formatting codes in the values passed to ANSI escape codes, so that they may be displayed in a
+// terminal. The values are separated by spaces, like with fmt.Sprintln, but no newline is added.
+func ANSI(a ...any) string {
+	return text.ANSI(a...)
+}
+
+// Join joins the strings passed using the separator passed, terminating each string using Terminate so that the
+// formatting of one string does not carry over to the next.
+func Join(s []string, sep string) string {
+	terminated := make([]string, len(s))
+	for i, v := range s {
+		terminated[i] = Terminate(v)
+	}
+	return strings.Join(terminated, sep)
+}
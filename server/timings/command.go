@@ -0,0 +1,138 @@
+// Package timings implements the /timings command, which reports the time spent by the worlds of a server ticking
+// each type of block and entity, so that the content causing a server to lag may be identified.
+package timings
+
+import (
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"time"
+)
+
+// Commands returns the /timings command, which records and reports the timings of the worlds of the Server passed.
+// The commands may be registered using cmd.Register. allow is called to check if a cmd.Source may execute the
+// commands. If nil and no cmd.Permissions were set using cmd.SetPermissions, the commands may only be executed by
+// sources that are not players, such as the console. If nil and cmd.Permissions were set, the permission node of
+// each command ('command.timings') decides which sources may execute it.
+func Commands(srv *server.Server, allow func(src cmd.Source) bool) []cmd.Command {
+	if allow == nil {
+		allow = func(src cmd.Source) bool {
+			_, ok := src.(*player.Player)
+			return !ok || cmd.HasPermissions()
+		}
+	}
+	c := command{srv: srv, allow: allow}
+	return []cmd.Command{
+		cmd.New("timings", "Records the time spent ticking blocks and entities.", nil,
+			timingsOnCommand{command: c},
+			timingsOffCommand{command: c},
+			timingsResetCommand{command: c},
+			timingsReportCommand{command: c},
+		),
+	}
+}
+
+// reportLimit is the maximum number of timings listed per world by /timings report.
+const reportLimit = 10
+
+// command holds the fields shared by all commands returned by Commands.
+type command struct {
+	srv   *server.Server
+	allow func(src cmd.Source) bool
+}
+
+// Allow ...
+func (c command) Allow(src cmd.Source) bool {
+	return c.allow(src)
+}
+
+// worlds returns the worlds of the server.
+func (c command) worlds() []*world.World {
+	return []*world.World{c.srv.World(), c.srv.Nether(), c.srv.End()}
+}
+
+// timingsOnCommand implements the /timings on command.
+type timingsOnCommand struct {
+	command
+	On cmd.SubCommand `cmd:"on"`
+}
+
+// Run ...
+func (c timingsOnCommand) Run(_ cmd.Source, o *cmd.Output) {
+	for _, w := range c.worlds() {
+		w.EnableTimings(true)
+	}
+	o.Print("Enabled timings.")
+}
+
+// timingsOffCommand implements the /timings off command.
+type timingsOffCommand struct {
+	command
+	Off cmd.SubCommand `cmd:"off"`
+}
+
+// Run ...
+func (c timingsOffCommand) Run(_ cmd.Source, o *cmd.Output) {
+	for _, w := range c.worlds() {
+		w.EnableTimings(false)
+	}
+	o.Print("Disabled timings.")
+}
+
+// timingsResetCommand implements the /timings reset command.
+type timingsResetCommand struct {
+	command
+	Reset cmd.SubCommand `cmd:"reset"`
+}
+
+// Run ...
+func (c timingsResetCommand) Run(_ cmd.Source, o *cmd.Output) {
+	for _, w := range c.worlds() {
+		w.ResetTimings()
+	}
+	o.Print("Reset timings.")
+}
+
+// timingsReportCommand implements the /timings report command.
+type timingsReportCommand struct {
+	command
+	Report cmd.SubCommand `cmd:"report"`
+}
+
+// Run ...
+func (c timingsReportCommand) Run(_ cmd.Source, o *cmd.Output) {
+	reported := false
+	for _, w := range c.worlds() {
+		timings, since := w.Timings()
+		if since.IsZero() {
+			continue
+		}
+		reported = true
+		elapsed := time.Since(since)
+
+		o.Printf("%v (%v recorded over %v):", w.Name(), total(timings).Round(time.Millisecond), elapsed.Round(time.Second))
+		if len(timings) > reportLimit {
+			timings = timings[:reportLimit]
+		}
+		for _, t := range timings {
+			kind := "block"
+			if t.Entity {
+				kind = "entity"
+			}
+			o.Printf("  %v %v: %v total, %v avg, %v ticks, %.2f%% of time", kind, t.Name, t.Total.Round(time.Microsecond), t.Average(), t.Count, float64(t.Total)/float64(elapsed)*100)
+		}
+	}
+	if !reported {
+		o.Error("Timings have not been enabled. Use /timings on to start recording.")
+	}
+}
+
+// total returns the sum of the time spent in all timings passed.
+func total(timings []world.Timing) time.Duration {
+	var d time.Duration
+	for _, t := range timings {
+		d += t.Total
+	}
+	return d
+}
@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"time"
+)
+
+// Kind is the kind of event that an Event was created for.
+type Kind string
+
+const (
+	// KindJoin is the Kind of Event created when a player joins the server.
+	KindJoin Kind = "join"
+	// KindQuit is the Kind of Event created when a player leaves the server.
+	KindQuit Kind = "quit"
+	// KindChat is the Kind of Event created when a player sends a chat message.
+	KindChat Kind = "chat"
+	// KindBlockBreak is the Kind of Event created when a player breaks a block.
+	KindBlockBreak Kind = "block_break"
+	// KindBlockPlace is the Kind of Event created when a player places a block.
+	KindBlockPlace Kind = "block_place"
+)
+
+// Kinds returns all kinds of events that may be exported.
+func Kinds() []Kind {
+	return []Kind{KindJoin, KindQuit, KindChat, KindBlockBreak, KindBlockPlace}
+}
+
+// Event is an event of a player exported by an Exporter. Events are serialised as JSON objects, omitting the fields
+// that are not relevant for the Kind of the Event.
+type Event struct {
+	// Kind is the kind of the Event.
+	Kind Kind `json:"kind"`
+	// Time is the time at which the Event occurred.
+	Time time.Time `json:"time"`
+	// Player and UUID are the name and UUID of the player that caused the Event.
+	Player string `json:"player"`
+	UUID   string `json:"uuid"`
+	// World is the name of the world that the player was in.
+	World string `json:"world,omitempty"`
+	// Pos is the position of the block broken or placed for KindBlockBreak and KindBlockPlace events.
+	Pos *cube.Pos `json:"pos,omitempty"`
+	// Block is the name of the block broken or placed for KindBlockBreak and KindBlockPlace events.
+	Block string `json:"block,omitempty"`
+	// Message is the message sent for KindChat events.
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// handler is the player.Handler subscribed to every player passed to Exporter.HandleJoin. It is called with
+// event.PriorityMonitor, so that only events that were not cancelled by other handlers are exported, in the state
+// in which they were left by those handlers.
+type handler struct {
+	player.NopHandler
+	e *Exporter
+	p *player.Player
+}
+
+// HandleChat ...
+func (h *handler) HandleChat(ctx *event.Context, e *player.ChatEvent) {
+	if ctx.Cancelled() {
+		return
+	}
+	ev := h.e.event(KindChat, h.p)
+	ev.Message = e.Message
+	h.e.Export(ev)
+}
+
+// HandleBlockBreak ...
+func (h *handler) HandleBlockBreak(ctx *event.Context, pos cube.Pos, _ *[]item.Stack, _ *int) {
+	if ctx.Cancelled() {
+		return
+	}
+	h.e.Export(h.blockEvent(KindBlockBreak, pos, h.p.World().Block(pos)))
+}
+
+// HandleBlockPlace ...
+func (h *handler) HandleBlockPlace(ctx *event.Context, pos cube.Pos, b world.Block) {
+	if ctx.Cancelled() {
+		return
+	}
+	h.e.Export(h.blockEvent(KindBlockPlace, pos, b))
+}
+
+// HandleQuit ...
+func (h *handler) HandleQuit() {
+	h.e.Export(h.e.event(KindQuit, h.p))
+}
+
+// blockEvent creates an Event of the Kind passed for the block at the position passed.
+func (h *handler) blockEvent(k Kind, pos cube.Pos, b world.Block) Event {
+	ev := h.e.event(k, h.p)
+	ev.Pos = &pos
+	ev.Block, _ = b.EncodeBlock()
+	return ev
+}
@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Sink is a destination that batches of Events are sent to, such as an HTTP endpoint or a message queue. Send is
+// only ever called from a single goroutine at a time.
+type Sink interface {
+	// Send sends a batch of Events to the Sink. If an error is returned, the batch is retried later, so Send should
+	// not return an error if the batch was (partially) accepted and retrying it would duplicate Events.
+	Send(ctx context.Context, events []Event) error
+}
+
+// HTTPSink is a Sink that sends batches of Events as a JSON array in the body of a POST request to a URL.
+type HTTPSink struct {
+	// URL is the URL that batches are posted to.
+	URL string
+	// Client is the http.Client used to send requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+	// Header holds additional headers set on every request, such as those used for authorisation.
+	Header http.Header
+}
+
+// Send posts the events passed to the URL of the HTTPSink. An error is returned if the request failed or the
+// response had a status code other than 2xx.
+func (s HTTPSink) Send(ctx context.Context, events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("encode events: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	for k, v := range s.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post events: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("post events: unexpected status %v", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+// Package webhook implements exporting events of players, such as block changes, chat messages and joins, to
+// external services like logging, analytics or anti-grief pipelines. Events are serialised as JSON and sent in
+// batches to a Sink, such as an HTTP endpoint or a message queue, retrying batches that could not be delivered.
+package webhook
+
+import (
+	"context"
+	"errors"
+	"github.com/df-mc/atomic"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/ratelimit"
+	"sync"
+	"time"
+)
+
+// Logger is used to report batches of events that could not be delivered.
+type Logger interface {
+	Errorf(format string, a ...any)
+}
+
+// Config holds the settings of an Exporter. Calling Config.New() creates an Exporter and starts sending events.
+type Config struct {
+	// Sink is the Sink that batches of events are sent to. Sink must not be nil.
+	Sink Sink
+	// Log is the Logger used to report batches that could not be delivered. If nil, errors are not reported.
+	Log Logger
+	// Kinds holds the kinds of events that are exported. If empty, events of all kinds are exported.
+	Kinds []Kind
+	// BatchSize is the maximum number of events sent in a single batch. A batch is sent as soon as it is full. If
+	// left as 0, BatchSize is set to 100.
+	BatchSize int
+	// FlushInterval is the maximum time that an event waits before the batch holding it is sent, even if the batch
+	// is not full. If left as 0, FlushInterval is set to 5 seconds.
+	FlushInterval time.Duration
+	// QueueSize is the maximum number of events waiting to be sent. Events created while the queue is full, such
+	// as when the Sink is unreachable, are dropped. If left as 0, QueueSize is set to 10000.
+	QueueSize int
+	// MaxRetries is the number of times a batch is retried if sending it fails, waiting twice as long before every
+	// next attempt. The batch is dropped once all retries failed. If left as 0, MaxRetries is set to 3. Setting it to
+	// -1 or lower disables retries.
+	MaxRetries int
+	// Timeout is the maximum time that a single attempt to send a batch may take. If left as 0, Timeout is set to
+	// 10 seconds.
+	Timeout time.Duration
+	// CloseTimeout is the maximum time that Close spends sending the events that are still queued. Events that could
+	// not be sent within this time are dropped. If left as 0, CloseTimeout is set to 30 seconds.
+	CloseTimeout time.Duration
+}
+
+// Exporter exports events of players to a Sink. Players must be passed to Exporter.HandleJoin when joining the
+// server for their events to be exported.
+type Exporter struct {
+	conf  Config
+	kinds map[Kind]struct{}
+
+	queue   chan Event
+	closing chan struct{}
+	done    chan struct{}
+	once    sync.Once
+
+	// dropped counts the events dropped because the queue was full since it was last reported. Reports are limited
+	// by dropLog, so that a full queue does not flood the Logger.
+	dropped atomic.Int64
+	dropLog *ratelimit.Limiter
+}
+
+// New creates an Exporter using the settings in the Config and starts sending batches of events to its Sink. Close
+// must be called to send the remaining events and stop the Exporter.
+func (conf Config) New() (*Exporter, error) {
+	if conf.Sink == nil {
+		return nil, errors.New("new exporter: sink must not be nil")
+	}
+	if conf.BatchSize <= 0 {
+		conf.BatchSize = 100
+	}
+	if conf.FlushInterval <= 0 {
+		conf.FlushInterval = time.Second * 5
+	}
+	if conf.QueueSize <= 0 {
+		conf.QueueSize = 10000
+	}
+	if conf.MaxRetries == 0 {
+		conf.MaxRetries = 3
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = time.Second * 10
+	}
+	if conf.CloseTimeout <= 0 {
+		conf.CloseTimeout = time.Second * 30
+	}
+	kinds := conf.Kinds
+	if len(kinds) == 0 {
+		kinds = Kinds()
+	}
+	e := &Exporter{
+		conf:    conf,
+		kinds:   make(map[Kind]struct{}, len(kinds)),
+		queue:   make(chan Event, conf.QueueSize),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+		dropLog: ratelimit.Limit{Rate: 1 / dropLogInterval.Seconds(), Burst: 1}.New(),
+	}
+	for _, k := range kinds {
+		e.kinds[k] = struct{}{}
+	}
+	go e.run()
+	return e, nil
+}
+
+// HandleJoin starts exporting the events of the player passed and exports its joining. HandleJoin may be passed to
+// server.Server.Accept, or called from the function passed to it.
+func (e *Exporter) HandleJoin(p *player.Player) {
+	e.Export(e.event(KindJoin, p))
+	p.Subscribe(&handler{e: e, p: p}, event.PriorityMonitor)
+}
+
+// Export queues the Event passed to be sent to the Sink, if its Kind is exported. If the queue is full or the
+// Exporter was closed, the Event is dropped.
+func (e *Exporter) Export(ev Event) {
+	if _, ok := e.kinds[ev.Kind]; !ok {
+		return
+	}
+	select {
+	case <-e.closing:
+	case e.queue <- ev:
+	default:
+		e.dropped.Inc()
+		if e.dropLog.Allow("") {
+			e.errorf("webhook: queue full, dropped %v events", e.dropped.Swap(0))
+		}
+	}
+}
+
+// dropLogInterval is the minimum interval between two reports of events dropped because the queue was full.
+const dropLogInterval = time.Second * 10
+
+// Close stops the Exporter, sending the events that are still queued before returning. Close returns once all
+// events were sent or dropped, or after the CloseTimeout set in the Config passed, whichever comes first.
+func (e *Exporter) Close() error {
+	e.once.Do(func() {
+		close(e.closing)
+		t := time.NewTimer(e.conf.CloseTimeout)
+		defer t.Stop()
+		select {
+		case <-e.done:
+		case <-t.C:
+			e.errorf("webhook: closed before all queued events were sent")
+		}
+	})
+	return nil
+}
+
+// event creates an Event of the Kind passed for the player passed.
+func (e *Exporter) event(k Kind, p *player.Player) Event {
+	ev := Event{Kind: k, Time: time.Now(), Player: p.Name(), UUID: p.UUID().String()}
+	if w := p.World(); w != nil {
+		ev.World = w.Name()
+	}
+	return ev
+}
+
+// run collects queued events into batches and sends them until the Exporter is closed.
+func (e *Exporter) run() {
+	defer close(e.done)
+	t := time.NewTicker(e.conf.FlushInterval)
+	defer t.Stop()
+
+	ctx := context.Background()
+	batch := make([]Event, 0, e.conf.BatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			e.send(ctx, batch)
+			batch = make([]Event, 0, e.conf.BatchSize)
+		}
+	}
+	for {
+		select {
+		case ev := <-e.queue:
+			if batch = append(batch, ev); len(batch) >= e.conf.BatchSize {
+				flush()
+			}
+		case <-t.C:
+			flush()
+		case <-e.closing:
+			// Send the remaining events, but stop once the CloseTimeout passes so that closing is not delayed
+			// indefinitely by a Sink that is unreachable.
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(context.Background(), e.conf.CloseTimeout)
+			defer cancel()
+			for {
+				if ctx.Err() != nil {
+					if n := len(batch) + len(e.queue); n > 0 {
+						e.errorf("webhook: dropping %v events that could not be sent before closing", n)
+					}
+					return
+				}
+				select {
+				case ev := <-e.queue:
+					if batch = append(batch, ev); len(batch) >= e.conf.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// send sends a batch of events to the Sink, retrying it with an exponential back-off if sending fails. Sending
+// stops once the context.Context passed is cancelled.
+func (e *Exporter) send(ctx context.Context, batch []Event) {
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		sendCtx, cancel := context.WithTimeout(ctx, e.conf.Timeout)
+		err := e.conf.Sink.Send(sendCtx, batch)
+		cancel()
+		if err == nil {
+			return
+		}
+		if attempt >= e.conf.MaxRetries || ctx.Err() != nil {
+			e.errorf("webhook: dropping batch of %v events after %v attempts: %v", len(batch), attempt+1, err)
+			return
+		}
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-e.closing:
+			// The Exporter is closing: Retry once more without waiting, so that closing is not delayed.
+			sendCtx, cancel := context.WithTimeout(ctx, e.conf.Timeout)
+			if err := e.conf.Sink.Send(sendCtx, batch); err != nil {
+				e.errorf("webhook: dropping batch of %v events: %v", len(batch), err)
+			}
+			cancel()
+			return
+		}
+	}
+}
+
+// errorf reports an error using the Logger of the Exporter, if set.
+func (e *Exporter) errorf(format string, a ...any) {
+	if e.conf.Log != nil {
+		e.conf.Log.Errorf(format, a...)
+	}
+}
@@ -0,0 +1,71 @@
+package world
+
+import (
+	"github.com/df-mc/worldupgrader/blockupgrader"
+	"github.com/df-mc/worldupgrader/itemupgrader"
+	"strings"
+)
+
+var (
+	// blockAliases holds the names of blocks indexed by the aliases registered using RegisterBlockAlias.
+	blockAliases = map[string]string{}
+	// itemAliases holds the names and metadata values of items indexed by the aliases registered using
+	// RegisterItemAlias.
+	itemAliases = map[string]itemHash{}
+)
+
+// RegisterBlockAlias registers an alias for the name of a block, such as a legacy numeric ID or a name used by an
+// older version of the game. BlockByName resolves aliases if no block with the name passed to it exists, so that
+// worlds, commands and configuration files referring to blocks by their alias still resolve.
+// Blocks renamed by Minecraft itself are resolved without registering an alias. Like RegisterBlock, RegisterBlockAlias
+// must be called before the server is started.
+func RegisterBlockAlias(alias, name string) {
+	blockAliases[normaliseName(alias)] = name
+}
+
+// RegisterItemAlias registers an alias for the name and metadata value of an item, such as a legacy numeric ID or a
+// name used by an older version of the game. ItemByName resolves aliases if no item with the name passed to it
+// exists, so that worlds, commands and configuration files referring to items by their alias still resolve.
+// Items renamed by Minecraft itself are resolved without registering an alias. Like RegisterItem, RegisterItemAlias
+// must be called before the server is started.
+func RegisterItemAlias(alias, name string, meta int16) {
+	itemAliases[normaliseName(alias)] = itemHash{name: name, meta: meta}
+}
+
+// resolveBlockAlias returns the current name and properties of a block referred to using an alias, a name without
+// namespace or a name and properties used by an older version of the game.
+func resolveBlockAlias(name string, properties map[string]any) (string, map[string]any) {
+	name = normaliseName(name)
+	if n, ok := blockAliases[name]; ok {
+		name = n
+	}
+	// The properties are copied, as upgrading a state modifies its properties. Upgrading a state through all known
+	// versions leaves states that are already up-to-date unchanged.
+	props := make(map[string]any, len(properties))
+	for k, v := range properties {
+		props[k] = v
+	}
+	state := blockupgrader.Upgrade(blockupgrader.BlockState{Name: name, Properties: props})
+	return state.Name, state.Properties
+}
+
+// resolveItemAlias returns the current name and metadata value of an item referred to using an alias, a name without
+// namespace or a name and metadata value used by an older version of the game.
+func resolveItemAlias(name string, meta int16) (string, int16) {
+	name = normaliseName(name)
+	if h, ok := itemAliases[name]; ok {
+		return h.name, h.meta
+	}
+	it := itemupgrader.Upgrade(itemupgrader.ItemMeta{Name: name, Meta: meta})
+	return it.Name, it.Meta
+}
+
+// normaliseName lower-cases the name passed and adds the 'minecraft:' namespace to it if it has none and is not
+// numeric, so that 'Stone' and 'minecraft:stone' refer to the same block or item.
+func normaliseName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || strings.ContainsRune(name, ':') || strings.Trim(name, "0123456789") == "" {
+		return name
+	}
+	return "minecraft:" + name
+}
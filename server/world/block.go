@@ -55,10 +55,16 @@ var hashes = intintmap.New(7000, 0.999)
 
 // RegisterBlock registers the Block passed. The EncodeBlock method will be used to encode and decode the
 // block passed. RegisterBlock panics if the block properties returned were not valid, existing properties.
+// States of a CustomBlock are registered automatically, and must be registered before the server is created.
 func RegisterBlock(b Block) {
 	name, properties := b.EncodeBlock()
 	h := stateHash{name: name, properties: hashProperties(properties)}
 
+	if c, ok := b.(CustomBlock); ok {
+		if _, ok := stateRuntimeIDs[h]; !ok {
+			registerCustomBlock(c, name, properties)
+		}
+	}
 	rid, ok := stateRuntimeIDs[h]
 	if !ok {
 		// We assume all blocks must have all their states registered beforehand. Vanilla blocks will have
@@ -68,12 +74,14 @@ func RegisterBlock(b Block) {
 	if _, ok := blocks[rid].(unknownBlock); !ok {
 		panic(fmt.Sprintf("block with name and properties %v {%#v} already registered", name, properties))
 	}
-	hash := int64(b.Hash())
-	if other, ok := hashes.Get(hash); ok {
-		panic(fmt.Sprintf("block %#v with hash %v already registered by %#v", b, hash, blocks[other]))
+	if h := b.Hash(); h != math.MaxUint64 {
+		hash := int64(h)
+		if other, ok := hashes.Get(hash); ok {
+			panic(fmt.Sprintf("block %#v with hash %v already registered by %#v", b, hash, blocks[other]))
+		}
+		hashes.Put(hash, int64(rid))
 	}
 	blocks[rid] = b
-	hashes.Put(hash, int64(rid))
 
 	if diffuser, ok := b.(lightDiffuser); ok {
 		chunk.FilteringBlocks[rid] = diffuser.LightDiffusionLevel()
@@ -131,12 +139,16 @@ func BlockByRuntimeID(rid uint32) (Block, bool) {
 	return blocks[rid], true
 }
 
-// BlockByName attempts to return a Block by its name and properties. If not found, the bool returned is
-// false.
+// BlockByName attempts to return a Block by its name and properties. If no block with the name and properties
+// exists, the name is resolved as an alias registered using RegisterBlockAlias, a name without namespace or a name
+// used by an older version of the game. If still not found, the bool returned is false.
 func BlockByName(name string, properties map[string]any) (Block, bool) {
 	rid, ok := stateRuntimeIDs[stateHash{name: name, properties: hashProperties(properties)}]
 	if !ok {
-		return nil, false
+		name, properties = resolveBlockAlias(name, properties)
+		if rid, ok = stateRuntimeIDs[stateHash{name: name, properties: hashProperties(properties)}]; !ok {
+			return nil, false
+		}
 	}
 	return blocks[rid], true
 }
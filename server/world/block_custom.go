@@ -0,0 +1,234 @@
+package world
+
+import (
+	"fmt"
+	"github.com/brentp/intintmap"
+	"github.com/df-mc/dragonfly/server/block/customblock"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+)
+
+// CustomBlock represents a block that is non-vanilla and requires a resource pack and extra steps to show it to the
+// client. Every state of a CustomBlock, that is, every combination of the values of its properties, must be
+// registered using RegisterBlock before the server is created. Properties of custom blocks must be of the types
+// bool, int32 or string. The first state registered of a CustomBlock is registered as its item form automatically,
+// so RegisterItem must not be called for it.
+type CustomBlock interface {
+	Block
+	Item
+	// Name is the name that will be displayed on the block to all clients.
+	Name() string
+	// Properties returns the customblock.Properties of the block, which are sent to the client to define what the
+	// block looks like.
+	Properties() customblock.Properties
+}
+
+// customBlockIDOffset is the ID of the first custom block registered. Custom blocks are given IDs past those of
+// vanilla blocks, and item runtime IDs derived from those IDs.
+const customBlockIDOffset = 1000
+
+var (
+	// customBlocks holds all states of custom blocks registered, in the order that they were registered in.
+	customBlocks []CustomBlock
+	// customBlockNames holds the names of all custom blocks registered, in the order that they were registered in.
+	customBlockNames []string
+	// blockRegistryFinalised is true once finaliseBlockRegistry was called, after which no more custom blocks may
+	// be registered.
+	blockRegistryFinalised bool
+)
+
+// CustomBlocks returns all states of custom blocks registered using RegisterBlock.
+func CustomBlocks() []CustomBlock {
+	return customBlocks
+}
+
+// registerCustomBlock registers the state of a custom block, so that it may be registered by RegisterBlock like a
+// vanilla block. The first state registered of a custom block is also registered as its item form.
+func registerCustomBlock(b CustomBlock, name string, properties map[string]any) {
+	if blockRegistryFinalised {
+		panic(fmt.Sprintf("custom block %v must be registered before the server is created", name))
+	}
+	if !strings.Contains(name, ":") || strings.HasPrefix(name, "minecraft:") {
+		panic(fmt.Sprintf("custom block %v must have a namespace other than minecraft", name))
+	}
+	for k, v := range properties {
+		switch v.(type) {
+		case bool, int32, string:
+		default:
+			panic(fmt.Sprintf("invalid custom block property type %T for property %v of %v", v, k, name))
+		}
+	}
+	registerBlockState(blockState{Name: name, Properties: properties})
+	customBlocks = append(customBlocks, b)
+
+	if slices.Contains(customBlockNames, name) {
+		return
+	}
+	rid := int32(255 - (customBlockIDOffset + len(customBlockNames)))
+	itemNamesToRuntimeIDs[name], itemRuntimeIDsToNames[rid] = rid, name
+	customBlockNames = append(customBlockNames, name)
+	RegisterItem(b)
+}
+
+// finaliseBlockRegistry sorts the states of custom blocks into the block palette and rebuilds the lookups of
+// runtime IDs. Clients sort the palette by the FNV-1 hash of the names of blocks and order the states of a block by
+// the values of its properties, so the runtime IDs of vanilla blocks change if custom blocks are registered.
+// finaliseBlockRegistry is called by the server when it is created, before any runtime IDs are used.
+func finaliseBlockRegistry() {
+	if blockRegistryFinalised {
+		return
+	}
+	blockRegistryFinalised = true
+	if len(customBlocks) == 0 {
+		return
+	}
+	nameHashes := make([]uint64, len(blocks))
+	permutations := make([]int, len(blocks))
+	values := customBlockValues()
+	for rid, b := range blocks {
+		name, properties := b.EncodeBlock()
+		h := fnv.New64()
+		_, _ = h.Write([]byte(name))
+		nameHashes[rid] = h.Sum64()
+		if v, ok := values[name]; ok {
+			permutations[rid] = permutationIndex(v, properties)
+		}
+	}
+	for name, v := range values {
+		count := 1
+		for _, p := range v {
+			count *= len(p.Values)
+		}
+		if registered := len(blockPropertiesOf(name)); registered != count {
+			panic(fmt.Sprintf("custom block %v has %v states, but only %v were registered", name, count, registered))
+		}
+	}
+	order := make([]int, len(blocks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if nameHashes[a] != nameHashes[b] {
+			return nameHashes[a] < nameHashes[b]
+		}
+		return permutations[a] < permutations[b]
+	})
+	blocks = reorder(blocks, order)
+	nbtBlocks = reorder(nbtBlocks, order)
+	randomTickBlocks = reorder(randomTickBlocks, order)
+	liquidBlocks = reorder(liquidBlocks, order)
+	liquidDisplacingBlocks = reorder(liquidDisplacingBlocks, order)
+	chunk.FilteringBlocks = reorder(chunk.FilteringBlocks, order)
+	chunk.LightBlocks = reorder(chunk.LightBlocks, order)
+
+	stateRuntimeIDs = make(map[stateHash]uint32, len(blocks))
+	hashes = intintmap.New(7000, 0.999)
+	for i, b := range blocks {
+		rid := uint32(i)
+		name, properties := b.EncodeBlock()
+		stateRuntimeIDs[stateHash{name: name, properties: hashProperties(properties)}] = rid
+		if name == "minecraft:air" {
+			airRID = rid
+		}
+		if _, ok := b.(unknownBlock); ok {
+			continue
+		}
+		if h := b.Hash(); h != math.MaxUint64 {
+			hashes.Put(int64(h), int64(rid))
+		}
+	}
+}
+
+// CustomBlockProperty is a property of a custom block, as found in the states of the block registered.
+type CustomBlockProperty struct {
+	// Name is the name of the property.
+	Name string
+	// Values holds all values that the property has in the states of the block registered, sorted.
+	Values []any
+}
+
+// customBlockValues returns the properties of all custom blocks registered, by name of the block.
+func customBlockValues() map[string][]CustomBlockProperty {
+	m := make(map[string][]CustomBlockProperty, len(customBlockNames))
+	for _, name := range customBlockNames {
+		m[name] = CustomBlockProperties(name)
+	}
+	return m
+}
+
+// CustomBlockProperties returns the properties of the custom block with the name passed, sorted by name.
+func CustomBlockProperties(name string) []CustomBlockProperty {
+	all := map[string][]any{}
+	for _, properties := range blockPropertiesOf(name) {
+		for k, v := range properties {
+			if indexOf(all[k], v) == -1 {
+				all[k] = append(all[k], v)
+			}
+		}
+	}
+	keys := maps.Keys(all)
+	slices.Sort(keys)
+	props := make([]CustomBlockProperty, 0, len(keys))
+	for _, k := range keys {
+		v := all[k]
+		sort.Slice(v, func(i, j int) bool {
+			switch a := v[i].(type) {
+			case bool:
+				return !a && v[j].(bool)
+			case int32:
+				return a < v[j].(int32)
+			case string:
+				return a < v[j].(string)
+			}
+			return false
+		})
+		props = append(props, CustomBlockProperty{Name: k, Values: v})
+	}
+	return props
+}
+
+// blockPropertiesOf returns the properties of all states registered of the custom block with the name passed.
+func blockPropertiesOf(name string) []map[string]any {
+	var states []map[string]any
+	for _, b := range customBlocks {
+		if n, properties := b.EncodeBlock(); n == name {
+			states = append(states, properties)
+		}
+	}
+	return states
+}
+
+// permutationIndex returns the index of the properties passed among all combinations of the values of the
+// properties of a block, where the first property varies the slowest.
+func permutationIndex(props []CustomBlockProperty, properties map[string]any) int {
+	index := 0
+	for _, p := range props {
+		index = index*len(p.Values) + indexOf(p.Values, properties[p.Name])
+	}
+	return index
+}
+
+// indexOf returns the index of the value passed in the slice passed, or -1 if the slice does not contain it.
+func indexOf(s []any, v any) int {
+	for i, w := range s {
+		if w == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// reorder returns a slice holding the elements of the slice passed in the order passed.
+func reorder[T any](s []T, order []int) []T {
+	sorted := make([]T, len(s))
+	for i, j := range order {
+		sorted[i] = s[j]
+	}
+	return sorted
+}
@@ -0,0 +1,75 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// bus is a Handler that passes the events of a World on to multiple Handlers, ordered by their event.Priority.
+type bus struct {
+	event.Listeners[Handler]
+}
+
+// Compile time check to make sure bus implements Handler.
+var _ Handler = (*bus)(nil)
+
+// HandleLiquidFlow ...
+func (hs *bus) HandleLiquidFlow(ctx *event.Context, from, into cube.Pos, liquid Liquid, replaced Block) {
+	hs.Call(ctx, func(h Handler) { h.HandleLiquidFlow(ctx, from, into, liquid, replaced) })
+}
+
+// HandleLiquidDecay ...
+func (hs *bus) HandleLiquidDecay(ctx *event.Context, pos cube.Pos, before, after Liquid) {
+	hs.Call(ctx, func(h Handler) { h.HandleLiquidDecay(ctx, pos, before, after) })
+}
+
+// HandleLiquidHarden ...
+func (hs *bus) HandleLiquidHarden(ctx *event.Context, hardenedPos cube.Pos, liquidHardened, otherLiquid, newBlock Block) {
+	hs.Call(ctx, func(h Handler) { h.HandleLiquidHarden(ctx, hardenedPos, liquidHardened, otherLiquid, newBlock) })
+}
+
+// HandleSound ...
+func (hs *bus) HandleSound(ctx *event.Context, s Sound, pos mgl64.Vec3) {
+	hs.Call(ctx, func(h Handler) { h.HandleSound(ctx, s, pos) })
+}
+
+// HandleFireSpread ...
+func (hs *bus) HandleFireSpread(ctx *event.Context, from, to cube.Pos) {
+	hs.Call(ctx, func(h Handler) { h.HandleFireSpread(ctx, from, to) })
+}
+
+// HandleBlockBurn ...
+func (hs *bus) HandleBlockBurn(ctx *event.Context, pos cube.Pos) {
+	hs.Call(ctx, func(h Handler) { h.HandleBlockBurn(ctx, pos) })
+}
+
+// HandleEntitySpawn ...
+func (hs *bus) HandleEntitySpawn(e Entity) {
+	hs.Call(nil, func(h Handler) { h.HandleEntitySpawn(e) })
+}
+
+// HandleEntityDespawn ...
+func (hs *bus) HandleEntityDespawn(e Entity) {
+	hs.Call(nil, func(h Handler) { h.HandleEntityDespawn(e) })
+}
+
+// HandleEntityHurt ...
+func (hs *bus) HandleEntityHurt(ctx *event.Context, e Entity, damage *float64, src DamageSource) {
+	hs.Call(ctx, func(h Handler) { h.HandleEntityHurt(ctx, e, damage, src) })
+}
+
+// HandleProjectileHit ...
+func (hs *bus) HandleProjectileHit(ctx *event.Context, projectile Entity, pos mgl64.Vec3, target Entity, blockPos cube.Pos) {
+	hs.Call(ctx, func(h Handler) { h.HandleProjectileHit(ctx, projectile, pos, target, blockPos) })
+}
+
+// HandleExplosion ...
+func (hs *bus) HandleExplosion(ctx *event.Context, position mgl64.Vec3, entities *[]Entity, blocks *[]cube.Pos, itemDropChance *float64, spawnFire *bool) {
+	hs.Call(ctx, func(h Handler) { h.HandleExplosion(ctx, position, entities, blocks, itemDropChance, spawnFire) })
+}
+
+// HandleClose ...
+func (hs *bus) HandleClose() {
+	hs.Call(nil, func(h Handler) { h.HandleClose() })
+}
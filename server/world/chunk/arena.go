@@ -0,0 +1,148 @@
+package chunk
+
+import (
+	"sort"
+	"sync"
+	"unsafe"
+)
+
+// arenaSlots is the amount of index slices that a single arena holds.
+const arenaSlots = 64
+
+// arena is a single large allocation that index slices of one size are allocated from. Allocating the indices of
+// PalettedStorages from arenas rather than as separate slices strongly reduces the amount of objects that the
+// garbage collector has to track when many chunks are loaded.
+type arena struct {
+	buf []uint32
+	// free holds the slots in buf that are not currently in use.
+	free []uint8
+}
+
+// base returns the address of the first uint32 in the buffer of the arena.
+func (a *arena) base() uintptr {
+	return uintptr(unsafe.Pointer(&a.buf[0]))
+}
+
+// arenaClass holds all arenas that index slices of one size are allocated from.
+type arenaClass struct {
+	// arenas holds all arenas of the class, sorted by the address of their buffer so that the arena that a slice
+	// was allocated from can be found using a binary search.
+	arenas []*arena
+}
+
+var (
+	// arenaMu guards arenaClasses.
+	arenaMu sync.Mutex
+	// arenaClasses holds an arenaClass for every size of index slices allocated, indexed by that size.
+	arenaClasses = map[int]*arenaClass{}
+)
+
+// ArenaStats holds statistics on the arenas that the block storages of chunks using Chunk.UseArena are allocated
+// from.
+type ArenaStats struct {
+	// Arenas is the amount of arenas currently allocated.
+	Arenas int
+	// Bytes is the combined size of all arenas in bytes.
+	Bytes int
+	// Used is the amount of block storages currently allocated from arenas.
+	Used int
+	// Free is the amount of block storages that may still be allocated from the arenas currently allocated.
+	Free int
+}
+
+// Arenas returns statistics on the arenas that block storages are currently allocated from.
+func Arenas() ArenaStats {
+	arenaMu.Lock()
+	defer arenaMu.Unlock()
+
+	var stats ArenaStats
+	for _, c := range arenaClasses {
+		for _, a := range c.arenas {
+			stats.Arenas++
+			stats.Bytes += len(a.buf) * uint32ByteSize
+			stats.Used += arenaSlots - len(a.free)
+			stats.Free += len(a.free)
+		}
+	}
+	return stats
+}
+
+// allocIndices allocates a zeroed slice of n uint32s from an arena. A new arena is created if all arenas for slices
+// of length n are full.
+func allocIndices(n int) []uint32 {
+	if n == 0 {
+		return []uint32{}
+	}
+	arenaMu.Lock()
+	defer arenaMu.Unlock()
+
+	c, ok := arenaClasses[n]
+	if !ok {
+		c = &arenaClass{}
+		arenaClasses[n] = c
+	}
+	var a *arena
+	for _, candidate := range c.arenas {
+		if len(candidate.free) > 0 {
+			a = candidate
+			break
+		}
+	}
+	if a == nil {
+		a = &arena{buf: make([]uint32, n*arenaSlots), free: make([]uint8, arenaSlots)}
+		for i := range a.free {
+			a.free[i] = uint8(arenaSlots - 1 - i)
+		}
+		i := sort.Search(len(c.arenas), func(i int) bool { return c.arenas[i].base() > a.base() })
+		c.arenas = append(c.arenas, nil)
+		copy(c.arenas[i+1:], c.arenas[i:])
+		c.arenas[i] = a
+	}
+	slot := int(a.free[len(a.free)-1])
+	a.free = a.free[:len(a.free)-1]
+
+	s := a.buf[slot*n : (slot+1)*n : (slot+1)*n]
+	for i := range s {
+		s[i] = 0
+	}
+	return s
+}
+
+// freeIndices returns a slice allocated using allocIndices to its arena, so that it may be allocated again. The
+// slice must not be used after calling freeIndices. Slices that were not allocated from an arena are ignored. If all
+// slots of an arena are free after calling freeIndices, the arena is released, unless it is the only arena of its
+// size.
+func freeIndices(s []uint32) {
+	if len(s) == 0 {
+		return
+	}
+	arenaMu.Lock()
+	defer arenaMu.Unlock()
+
+	c, ok := arenaClasses[len(s)]
+	if !ok {
+		return
+	}
+	addr := uintptr(unsafe.Pointer(&s[0]))
+	i := sort.Search(len(c.arenas), func(i int) bool { return c.arenas[i].base() > addr }) - 1
+	if i < 0 {
+		return
+	}
+	a := c.arenas[i]
+	offset := (addr - a.base()) / uint32ByteSize
+	if offset >= uintptr(len(a.buf)) {
+		return
+	}
+	slot := uint8(offset / uintptr(len(s)))
+	for _, free := range a.free {
+		if free == slot {
+			// The slot was already freed, so freeing it again would hand it out twice.
+			return
+		}
+	}
+	a.free = append(a.free, slot)
+
+	if len(a.free) == arenaSlots && len(c.arenas) > 1 {
+		c.arenas = append(c.arenas[:i], c.arenas[i+1:]...)
+	}
+}
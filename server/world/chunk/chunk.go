@@ -20,8 +20,13 @@ type Chunk struct {
 	// sub holds all sub chunks part of the chunk. The pointers held by the array are nil if no sub chunk is
 	// allocated at the indices.
 	sub []*SubChunk
-	// biomes is an array of biome IDs. There is one biome ID for every column in the chunk.
+	// biomes holds a PalettedStorage of biome IDs for every sub chunk. Like blocks, biomes are stored in three
+	// dimensions, so that every block in the chunk may have a different biome.
 	biomes []*PalettedStorage
+	// arena is true if the block storages of the chunk are allocated from arenas.
+	arena bool
+	// version is incremented every time a block or biome in the chunk is changed.
+	version uint64
 }
 
 // New initialises a new chunk and returns it, so that it may be used.
@@ -47,6 +52,13 @@ func (chunk *Chunk) Range() cube.Range {
 	return chunk.r
 }
 
+// Version returns a number that changes every time a block or biome in the chunk is changed through the methods of
+// the Chunk, so that data derived from the chunk, such as its encoding, may be cached until the chunk changes.
+// Changes made directly to the sub chunks returned by Sub do not change the version.
+func (chunk *Chunk) Version() uint64 {
+	return chunk.version
+}
+
 // Sub returns a list of all sub chunks present in the chunk.
 func (chunk *Chunk) Sub() []*SubChunk {
 	return chunk.sub
@@ -73,16 +85,39 @@ func (chunk *Chunk) SetBlock(x uint8, y int16, z uint8, layer uint8, block uint3
 	}
 	sub.Layer(layer).Set(x, uint8(y), z, block)
 	chunk.recalculateHeightMap = true
+	chunk.version++
 }
 
-// Biome returns the biome ID at a specific column in the chunk.
+// Biome returns the biome ID at a specific x, y and z in the chunk.
 func (chunk *Chunk) Biome(x uint8, y int16, z uint8) uint32 {
 	return chunk.biomes[chunk.SubIndex(y)].At(x, uint8(y), z)
 }
 
-// SetBiome sets the biome ID at a specific column in the chunk.
+// SetBiome sets the biome ID at a specific x, y and z in the chunk.
 func (chunk *Chunk) SetBiome(x uint8, y int16, z uint8, biome uint32) {
 	chunk.biomes[chunk.SubIndex(y)].Set(x, uint8(y), z, biome)
+	chunk.version++
+}
+
+// SetBiomeColumn sets the biome ID at a specific x and z in the chunk for every y in the range of the chunk, like
+// biomes were set in versions of the game that did not have 3D biomes.
+func (chunk *Chunk) SetBiomeColumn(x, z uint8, biome uint32) {
+	for y := chunk.r.Min(); y <= chunk.r.Max(); y++ {
+		chunk.biomes[chunk.SubIndex(int16(y))].Set(x, uint8(y), z, biome)
+	}
+	chunk.version++
+}
+
+// FillBiome sets the biome ID of the entire sub chunk at the index passed. Filling a sub chunk is much faster than
+// setting the biome of every block in it and results in a smaller storage.
+func (chunk *Chunk) FillBiome(index int16, biome uint32) {
+	arena := chunk.biomes[index].arena
+	chunk.biomes[index].release()
+	chunk.biomes[index] = emptyStorage(biome)
+	if arena {
+		chunk.biomes[index].useArena()
+	}
+	chunk.version++
 }
 
 // Light returns the light level at a specific position in the chunk.
@@ -171,6 +206,49 @@ func (chunk *Chunk) Compact() {
 	}
 }
 
+// UseArena moves all block and biome storages of the Chunk into arenas: large allocations shared by the storages
+// of many chunks, which are not tracked individually by the garbage collector. Storages created or resized for the
+// Chunk afterwards are allocated from arenas as well. This reduces the impact of garbage collection when many chunks
+// are loaded, but the memory of the Chunk is only reclaimed once Free is called. The statistics of all arenas may be
+// obtained using Arenas.
+func (chunk *Chunk) UseArena() {
+	if chunk.arena {
+		return
+	}
+	chunk.arena = true
+	for _, sub := range chunk.sub {
+		sub.arena = true
+		for _, storage := range sub.storages {
+			storage.useArena()
+		}
+	}
+	for _, storage := range chunk.biomes {
+		storage.useArena()
+	}
+}
+
+// Free returns the storages of a Chunk on which UseArena was called to their arenas and empties the Chunk. Free
+// should be called once a Chunk is no longer used, such as when it is unloaded after being saved. Free does nothing
+// if UseArena was not called.
+func (chunk *Chunk) Free() {
+	if !chunk.arena {
+		return
+	}
+	chunk.arena = false
+	for _, sub := range chunk.sub {
+		for _, storage := range sub.storages {
+			storage.release()
+		}
+		sub.storages, sub.arena = nil, false
+	}
+	for i, storage := range chunk.biomes {
+		storage.release()
+		chunk.biomes[i] = emptyStorage(0)
+	}
+	chunk.recalculateHeightMap = true
+	chunk.version++
+}
+
 // SubChunk finds the correct SubChunk in the Chunk by a Y value.
 func (chunk *Chunk) SubChunk(y int16) *SubChunk {
 	return chunk.sub[chunk.SubIndex(y)]
@@ -19,6 +19,9 @@ func NetworkDecode(air uint32, data []byte, count int, r cube.Range) (*Chunk, er
 		buf = bytes.NewBuffer(data)
 		err error
 	)
+	if count < 0 || count > len(c.sub) {
+		return nil, fmt.Errorf("invalid sub chunk count %v: must be in range 0 <= count <= %v", count, len(c.sub))
+	}
 	for i := 0; i < count; i++ {
 		index := uint8(i)
 		c.sub[index], err = decodeSubChunk(buf, c, &index, NetworkEncoding)
@@ -57,6 +60,9 @@ func DiskDecode(data SerialisedData, r cube.Range) (*Chunk, error) {
 	}
 
 	c := New(air, r)
+	if len(data.SubChunks) > len(c.sub) {
+		return nil, fmt.Errorf("too many sub chunks: found %v, but range %v holds at most %v", len(data.SubChunks), r, len(c.sub))
+	}
 
 	err := decodeBiomes(bytes.NewBuffer(data.Biomes), c, DiskEncoding)
 	if err != nil {
@@ -106,7 +112,11 @@ func decodeSubChunk(buf *bytes.Buffer, c *Chunk, index *byte, e Encoding) (*SubC
 			}
 			// The index as written here isn't the actual index of the sub-chunk within the chunk. Rather, it is the Y
 			// value of the sub-chunk. This means that we need to translate it to an index.
-			*index = uint8(int8(uIndex) - int8(c.r[0]>>4))
+			i := int(int8(uIndex)) - c.r[0]>>4
+			if i < 0 || i >= len(c.sub) {
+				return nil, fmt.Errorf("sub-chunk Y %v out of range %v", int8(uIndex), c.r)
+			}
+			*index = uint8(i)
 		}
 		sub.storages = make([]*PalettedStorage, storageCount)
 
@@ -159,8 +169,11 @@ func decodePalettedStorage(buf *bytes.Buffer, e Encoding, pe paletteEncoding) (*
 	if blockSize == 0x7f {
 		return nil, nil
 	}
-
 	size := paletteSize(blockSize)
+	if !size.valid() {
+		return nil, fmt.Errorf("invalid paletted storage size %v %T", blockSize, pe)
+	}
+
 	uint32Count := size.uint32s()
 
 	uint32s := make([]uint32, uint32Count)
@@ -174,6 +187,16 @@ func decodePalettedStorage(buf *bytes.Buffer, e Encoding, pe paletteEncoding) (*
 		// Explicitly don't use the binary package to greatly improve performance of reading the uint32s.
 		uint32s[i] = uint32(data[i*4]) | uint32(data[i*4+1])<<8 | uint32(data[i*4+2])<<16 | uint32(data[i*4+3])<<24
 	}
-	p, err := e.decodePalette(buf, paletteSize(blockSize), pe)
-	return newPalettedStorage(uint32s, p), err
+	p, err := e.decodePalette(buf, size, pe)
+	if err != nil {
+		return nil, err
+	}
+	if p.needsResize() {
+		return nil, fmt.Errorf("invalid palette %T: %v values do not fit in a paletted storage (size=%v)", pe, p.Len(), blockSize)
+	}
+	storage := newPalettedStorage(uint32s, p)
+	if err := storage.validate(); err != nil {
+		return nil, fmt.Errorf("invalid paletted storage (size=%v) %T: %w", blockSize, pe, err)
+	}
+	return storage, nil
 }
@@ -2,6 +2,7 @@ package chunk
 
 import (
 	"bytes"
+	"github.com/df-mc/dragonfly/server/block/cube"
 	"sync"
 )
 
@@ -59,14 +60,20 @@ func Encode(c *Chunk, e Encoding) SerialisedData {
 // EncodeSubChunk encodes a sub-chunk from a chunk into bytes. An Encoding may be passed to encode either for network or
 // disk purposed, the most notable difference being that the network encoding generally uses varints and no NBT.
 func EncodeSubChunk(c *Chunk, e Encoding, ind int) []byte {
+	return EncodeSub(c.sub[ind], c.r, e, ind)
+}
+
+// EncodeSub encodes a SubChunk into bytes as if it was the sub-chunk at index ind of a chunk with the cube.Range
+// passed. Unlike EncodeSubChunk, EncodeSub may be used to encode a SubChunk that is not part of a Chunk, such as a
+// modified copy of one.
+func EncodeSub(s *SubChunk, r cube.Range, e Encoding, ind int) []byte {
 	buf := pool.Get().(*bytes.Buffer)
 	defer func() {
 		buf.Reset()
 		pool.Put(buf)
 	}()
 
-	s := c.sub[ind]
-	_, _ = buf.Write([]byte{SubChunkVersion, byte(len(s.storages)), uint8(ind + (c.r[0] >> 4))})
+	_, _ = buf.Write([]byte{SubChunkVersion, byte(len(s.storages)), uint8(ind + (r[0] >> 4))})
 	for _, storage := range s.storages {
 		encodePalettedStorage(buf, storage, nil, e, BlockPaletteEncoding)
 	}
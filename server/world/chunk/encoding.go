@@ -59,6 +59,9 @@ func (blockPaletteEncoding) encode(buf *bytes.Buffer, v uint32) {
 }
 func (blockPaletteEncoding) decode(buf *bytes.Buffer) (uint32, error) {
 	var m map[string]any
+	if _, err := CheckNBT(buf.Bytes()); err != nil {
+		return 0, fmt.Errorf("error decoding block palette entry: %w", err)
+	}
 	if err := nbt.NewDecoderWithEncoding(buf, nbt.LittleEndian).Decode(&m); err != nil {
 		return 0, fmt.Errorf("error decoding block palette entry: %w", err)
 	}
@@ -121,6 +124,9 @@ func (diskEncoding) decodePalette(buf *bytes.Buffer, blockSize paletteSize, e pa
 		if err := binary.Read(buf, binary.LittleEndian, &paletteCount); err != nil {
 			return nil, fmt.Errorf("error reading palette entry count: %w", err)
 		}
+		if paletteCount > maxPaletteLen {
+			return nil, fmt.Errorf("invalid palette entry count %v: must be at most %v", paletteCount, maxPaletteLen)
+		}
 	}
 
 	var err error
@@ -155,7 +161,7 @@ func (networkEncoding) decodePalette(buf *bytes.Buffer, blockSize paletteSize, _
 		if err := protocol.Varint32(buf, &paletteCount); err != nil {
 			return nil, fmt.Errorf("error reading palette entry count: %w", err)
 		}
-		if paletteCount <= 0 {
+		if paletteCount <= 0 || paletteCount > maxPaletteLen {
 			return nil, fmt.Errorf("invalid palette entry count %v", paletteCount)
 		}
 	}
@@ -0,0 +1,81 @@
+package chunk_test
+
+import (
+	"bytes"
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"testing"
+)
+
+var testRange = cube.Range{-64, 319}
+
+// testChunk returns a chunk filled with a couple of different blocks and biomes, so that its encoding holds
+// palettes with more than one entry.
+func testChunk() *chunk.Chunk {
+	air := world.BlockRuntimeID(block.Air{})
+	stone := world.BlockRuntimeID(block.Stone{})
+	dirt := world.BlockRuntimeID(block.Dirt{})
+	c := chunk.New(air, testRange)
+	for x := uint8(0); x < 16; x++ {
+		for z := uint8(0); z < 16; z++ {
+			for y := int16(-64); y < 4; y++ {
+				c.SetBlock(x, y, z, 0, stone)
+			}
+			c.SetBlock(x, 4, z, 0, dirt)
+			c.SetBiomeColumn(x, z, uint32(x%3))
+		}
+	}
+	return c
+}
+
+func FuzzDiskDecode(f *testing.F) {
+	data := chunk.Encode(testChunk(), chunk.DiskEncoding)
+	f.Add(data.Biomes, data.SubChunks[0], data.SubChunks[len(data.SubChunks)-1])
+	f.Add([]byte{}, []byte{}, []byte{})
+	f.Fuzz(func(t *testing.T, biomes, first, last []byte) {
+		sub := make([][]byte, (testRange.Height()>>4)+1)
+		sub[0], sub[len(sub)-1] = first, last
+		_, _ = chunk.DiskDecode(chunk.SerialisedData{Biomes: biomes, SubChunks: sub}, testRange)
+	})
+}
+
+func FuzzNetworkDecode(f *testing.F) {
+	c := testChunk()
+	data := chunk.Encode(c, chunk.NetworkEncoding)
+	var buf bytes.Buffer
+	for _, sub := range data.SubChunks {
+		buf.Write(sub)
+	}
+	buf.Write(data.Biomes)
+	f.Add(buf.Bytes(), len(data.SubChunks))
+	f.Add([]byte{}, 0)
+	air := world.BlockRuntimeID(block.Air{})
+	f.Fuzz(func(t *testing.T, data []byte, count int) {
+		_, _ = chunk.NetworkDecode(air, data, count, testRange)
+	})
+}
+
+func FuzzCheckNBT(f *testing.F) {
+	for _, b := range []world.Block{block.NewChest(), block.Sign{}, block.Furnace{}} {
+		nbter := b.(world.NBTer)
+		var buf bytes.Buffer
+		_ = nbt.NewEncoderWithEncoding(&buf, nbt.LittleEndian).Encode(nbter.EncodeNBT())
+		f.Add(buf.Bytes())
+	}
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		n, err := chunk.CheckNBT(data)
+		if err != nil {
+			return
+		}
+		if n > len(data) || n > chunk.MaxNBTSize {
+			t.Fatalf("nbt size %v exceeds data of %v bytes", n, len(data))
+		}
+		// Data accepted by CheckNBT must be safe to decode, so this must not panic.
+		var m map[string]any
+		_ = nbt.NewDecoderWithEncoding(bytes.NewReader(data[:n]), nbt.LittleEndian).Decode(&m)
+	})
+}
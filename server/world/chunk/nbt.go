@@ -0,0 +1,177 @@
+package chunk
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// MaxNBTDepth is the maximum depth of nested compound and list tags in NBT read from chunk data.
+	MaxNBTDepth = 64
+	// MaxNBTSize is the maximum size in bytes of a single NBT value read from chunk data, such as a block palette
+	// entry, block entity or entity.
+	MaxNBTSize = 4 << 20
+)
+
+// CheckNBT checks if data starts with a valid little endian NBT value, such as those found in block palettes and in
+// the block entities and entities stored with chunks. The NBT value may be nested at most MaxNBTDepth levels deep and
+// be at most MaxNBTSize bytes in size, and the lengths of all strings, lists and arrays in it must fit in data.
+// CheckNBT returns the size of the NBT value in bytes. An error is returned if the value is invalid, in which case
+// it must not be decoded, as decoding it could allocate excessive amounts of memory or panic.
+func CheckNBT(data []byte) (int, error) {
+	c := nbtChecker{data: data}
+	if len(c.data) > MaxNBTSize {
+		c.data = c.data[:MaxNBTSize]
+	}
+	t, err := c.byte()
+	if err != nil {
+		return 0, err
+	}
+	if t != nbtEnd {
+		if err := c.string(); err != nil {
+			return 0, err
+		}
+		if err := c.payload(t, 0); err != nil {
+			return 0, err
+		}
+	}
+	return c.off, nil
+}
+
+// Tag types of NBT values.
+const (
+	nbtEnd byte = iota
+	nbtByte
+	nbtShort
+	nbtInt
+	nbtLong
+	nbtFloat
+	nbtDouble
+	nbtByteArray
+	nbtString
+	nbtList
+	nbtCompound
+	nbtIntArray
+	nbtLongArray
+)
+
+// nbtChecker walks through little endian NBT data without decoding it, checking the lengths of all values in it.
+type nbtChecker struct {
+	data []byte
+	off  int
+}
+
+// payload checks the payload of a tag of the type passed, nested depth levels deep.
+func (c *nbtChecker) payload(t byte, depth int) error {
+	switch t {
+	case nbtByte:
+		return c.skip(1)
+	case nbtShort:
+		return c.skip(2)
+	case nbtInt, nbtFloat:
+		return c.skip(4)
+	case nbtLong, nbtDouble:
+		return c.skip(8)
+	case nbtString:
+		return c.string()
+	case nbtByteArray, nbtIntArray, nbtLongArray:
+		n, err := c.length()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case nbtIntArray:
+			n *= 4
+		case nbtLongArray:
+			n *= 8
+		}
+		return c.skip(n)
+	case nbtList:
+		if depth >= MaxNBTDepth {
+			return fmt.Errorf("nbt nested deeper than %v levels", MaxNBTDepth)
+		}
+		elem, err := c.byte()
+		if err != nil {
+			return err
+		}
+		n, err := c.length()
+		if err != nil {
+			return err
+		}
+		if n > 0 && elem == nbtEnd {
+			return fmt.Errorf("nbt list of %v elements of type TAG_End", n)
+		}
+		for i := 0; i < n; i++ {
+			if err := c.payload(elem, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case nbtCompound:
+		if depth >= MaxNBTDepth {
+			return fmt.Errorf("nbt nested deeper than %v levels", MaxNBTDepth)
+		}
+		for {
+			t, err := c.byte()
+			if err != nil {
+				return err
+			}
+			if t == nbtEnd {
+				return nil
+			}
+			if err := c.string(); err != nil {
+				return err
+			}
+			if err := c.payload(t, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("unknown nbt tag type %v at offset %v", t, c.off)
+}
+
+// byte reads a single byte.
+func (c *nbtChecker) byte() (byte, error) {
+	if c.off >= len(c.data) {
+		return 0, c.overrun(1)
+	}
+	c.off++
+	return c.data[c.off-1], nil
+}
+
+// string skips a string prefixed by its length as a uint16.
+func (c *nbtChecker) string() error {
+	if len(c.data)-c.off < 2 {
+		return c.overrun(2)
+	}
+	n := int(binary.LittleEndian.Uint16(c.data[c.off:]))
+	c.off += 2
+	return c.skip(n)
+}
+
+// length reads the length of a list or array, which is an int32 that may not be negative.
+func (c *nbtChecker) length() (int, error) {
+	if len(c.data)-c.off < 4 {
+		return 0, c.overrun(4)
+	}
+	n := int32(binary.LittleEndian.Uint32(c.data[c.off:]))
+	c.off += 4
+	if n < 0 {
+		return 0, fmt.Errorf("negative nbt length %v at offset %v", n, c.off-4)
+	}
+	return int(n), nil
+}
+
+// skip skips n bytes.
+func (c *nbtChecker) skip(n int) error {
+	if n < 0 || len(c.data)-c.off < n {
+		return c.overrun(n)
+	}
+	c.off += n
+	return nil
+}
+
+// overrun returns an error for n bytes that could not be read because the data, or MaxNBTSize, was exceeded.
+func (c *nbtChecker) overrun(n int) error {
+	return fmt.Errorf("nbt value exceeds data: cannot read %v bytes at offset %v of %v", n, c.off, len(c.data))
+}
@@ -96,6 +96,15 @@ func (palette *Palette) increaseSize() {
 	palette.size = sizes[offsets[palette.size]+1]
 }
 
+// maxPaletteLen is the maximum number of values that a Palette can hold: A PalettedStorage holds 4096 values, so
+// there can never be more unique values than that.
+const maxPaletteLen = 4096
+
+// valid checks if the paletteSize is one of the sizes that a Palette can have.
+func (p paletteSize) valid() bool {
+	return int(p) < len(offsets) && (p == 0 || offsets[p] != 0)
+}
+
 // padded returns true if the Palette size is 3, 5 or 6.
 func (p paletteSize) padded() bool {
 	return p == 3 || p == 5 || p == 6
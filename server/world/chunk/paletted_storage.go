@@ -2,6 +2,7 @@ package chunk
 
 import (
 	"bytes"
+	"fmt"
 	"reflect"
 	"unsafe"
 )
@@ -38,6 +39,8 @@ type PalettedStorage struct {
 	// indices contains all indices in the PalettedStorage. This slice has a variable size, but may not be changed
 	// unless the whole PalettedStorage is resized, including the Palette.
 	indices []uint32
+	// arena is true if indices is allocated from an arena. If true, indices must be freed when it is replaced.
+	arena bool
 }
 
 // newPalettedStorage creates a new block storage using the uint32 slice as the indices and the palette passed.
@@ -60,6 +63,16 @@ func emptyStorage(v uint32) *PalettedStorage {
 	return newPalettedStorage([]uint32{}, newPalette(0, []uint32{v}))
 }
 
+// clone returns a copy of the PalettedStorage and its Palette. The indices of the copy are never allocated from an
+// arena.
+func (storage *PalettedStorage) clone() *PalettedStorage {
+	indices := make([]uint32, len(storage.indices))
+	copy(indices, storage.indices)
+	values := make([]uint32, len(storage.palette.values))
+	copy(values, storage.palette.values)
+	return newPalettedStorage(indices, newPalette(storage.palette.size, values))
+}
+
 // Palette returns the Palette of the PalettedStorage.
 func (storage *PalettedStorage) Palette() *Palette {
 	return storage.palette
@@ -110,6 +123,29 @@ func (storage *PalettedStorage) addNew(v uint32) int16 {
 	return index
 }
 
+// validate checks if all indices in the PalettedStorage point to a value in its Palette. An error is returned if
+// this is not the case, which can only happen if the PalettedStorage was decoded from malformed data.
+func (storage *PalettedStorage) validate() error {
+	l := storage.palette.Len()
+	if l == 0 {
+		return fmt.Errorf("palette is empty")
+	}
+	if l >= 1<<storage.bitsPerIndex {
+		// Every index that fits in the bits per index is valid.
+		return nil
+	}
+	for x := byte(0); x < 16; x++ {
+		for y := byte(0); y < 16; y++ {
+			for z := byte(0); z < 16; z++ {
+				if i := storage.paletteIndex(x, y, z); int(i) >= l {
+					return fmt.Errorf("palette index %v at %v, %v, %v out of range: palette has %v values", i, x, y, z, l)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // paletteIndex looks up the Palette index at a given x, y and z value in the PalettedStorage. This palette
 // index is not the value at this offset, but merely an index in the Palette pointing to a value.
 func (storage *PalettedStorage) paletteIndex(x, y, z byte) uint16 {
@@ -149,7 +185,7 @@ func (storage *PalettedStorage) resize(newPaletteSize paletteSize) {
 	}
 	// Construct a new storage and set all values in there manually. We can't easily do this in a better
 	// way, because all values will be at a different index with a different length.
-	newStorage := newPalettedStorage(make([]uint32, newPaletteSize.uint32s()), storage.palette)
+	newStorage := newPalettedStorage(storage.allocIndices(newPaletteSize.uint32s()), storage.palette)
 	for x := byte(0); x < 16; x++ {
 		for y := byte(0); y < 16; y++ {
 			for z := byte(0); z < 16; z++ {
@@ -158,7 +194,7 @@ func (storage *PalettedStorage) resize(newPaletteSize paletteSize) {
 		}
 	}
 	// Set the new storage.
-	*storage = *newStorage
+	storage.replace(newStorage)
 }
 
 // compact clears unused indexes in the palette by scanning for usages in the PalettedStorage. This is a
@@ -185,7 +221,7 @@ func (storage *PalettedStorage) compact() {
 	// Construct a new storage and set all values in there manually. We can't easily do this in a better
 	// way, because all values will be at a different index with a different length.
 	size := paletteSizeFor(len(newRuntimeIDs))
-	newStorage := newPalettedStorage(make([]uint32, size.uint32s()), newPalette(size, newRuntimeIDs))
+	newStorage := newPalettedStorage(storage.allocIndices(size.uint32s()), newPalette(size, newRuntimeIDs))
 
 	for x := byte(0); x < 16; x++ {
 		for y := byte(0); y < 16; y++ {
@@ -196,5 +232,45 @@ func (storage *PalettedStorage) compact() {
 			}
 		}
 	}
+	storage.replace(newStorage)
+}
+
+// allocIndices allocates a slice of n uint32s for the indices of a PalettedStorage that replaces this one. The
+// slice is allocated from an arena if the PalettedStorage uses one.
+func (storage *PalettedStorage) allocIndices(n int) []uint32 {
+	if storage.arena {
+		return allocIndices(n)
+	}
+	return make([]uint32, n)
+}
+
+// replace replaces the PalettedStorage with the PalettedStorage passed, of which the indices were allocated using
+// allocIndices. If the PalettedStorage uses an arena, its current indices are freed.
+func (storage *PalettedStorage) replace(newStorage *PalettedStorage) {
+	if storage.arena {
+		freeIndices(storage.indices)
+		newStorage.arena = true
+	}
 	*storage = *newStorage
 }
+
+// useArena moves the indices of the PalettedStorage into an arena. Indices allocated for the PalettedStorage
+// afterwards are also allocated from an arena, until release is called.
+func (storage *PalettedStorage) useArena() {
+	if storage.arena {
+		return
+	}
+	indices := allocIndices(len(storage.indices))
+	copy(indices, storage.indices)
+	*storage = *newPalettedStorage(indices, storage.palette)
+	storage.arena = true
+}
+
+// release frees the indices of the PalettedStorage if they were allocated from an arena. The PalettedStorage must not
+// be used after calling release.
+func (storage *PalettedStorage) release() {
+	if storage.arena {
+		freeIndices(storage.indices)
+		storage.indices, storage.indicesStart, storage.arena = nil, nil, false
+	}
+}
@@ -7,6 +7,8 @@ type SubChunk struct {
 	storages   []*PalettedStorage
 	blockLight []uint8
 	skyLight   []uint8
+	// arena is true if the storages of the SubChunk are allocated from arenas.
+	arena bool
 }
 
 // NewSubChunk creates a new sub chunk. All sub chunks should be created through this function
@@ -14,6 +16,16 @@ func NewSubChunk(air uint32) *SubChunk {
 	return &SubChunk{air: air}
 }
 
+// Clone returns a copy of the blocks in the SubChunk. The light levels of the SubChunk are not copied. The copy does
+// not share any memory with the SubChunk, so it may be modified freely.
+func (sub *SubChunk) Clone() *SubChunk {
+	cp := &SubChunk{air: sub.air, storages: make([]*PalettedStorage, len(sub.storages))}
+	for i, storage := range sub.storages {
+		cp.storages[i] = storage.clone()
+	}
+	return cp
+}
+
 // Empty checks if the SubChunk is considered empty. This is the case if the SubChunk has 0 block storages or if it has
 // a single one that is completely filled with air.
 func (sub *SubChunk) Empty() bool {
@@ -26,7 +38,9 @@ func (sub *SubChunk) Layer(layer uint8) *PalettedStorage {
 	for uint8(len(sub.storages)) <= layer {
 		// Keep appending to storages until the requested layer is achieved. Makes working with new layers
 		// much easier.
-		sub.storages = append(sub.storages, emptyStorage(sub.air))
+		storage := emptyStorage(sub.air)
+		storage.arena = sub.arena
+		sub.storages = append(sub.storages, storage)
 	}
 	return sub.storages[layer]
 }
@@ -96,6 +110,7 @@ func (sub *SubChunk) compact() {
 		storage.compact()
 		if len(storage.palette.values) == 1 && storage.palette.values[0] == sub.air {
 			// If the palette has only air in it, it means the storage is empty, so we can ignore it.
+			storage.release()
 			continue
 		}
 		newStorages = append(newStorages, storage)
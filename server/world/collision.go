@@ -0,0 +1,181 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+)
+
+// fullBlockBox is the collision box of a full block, relative to the position of the block.
+var fullBlockBox = cube.Box(0, 0, 0, 1, 1, 1)
+
+// collisionShapes holds the collision boxes of all blocks in a sub chunk, so that entity physics and movement
+// validation do not need to look up the block and its model at every position they test. Blocks with a full
+// collision box, which make up most of the blocks in a world, are stored in a bitset. The boxes of all other blocks
+// with collision are stored by their index in the sub chunk.
+type collisionShapes struct {
+	full  [64]uint64
+	other map[uint16][]cube.BBox
+}
+
+// shapeIndex returns the index of the position passed in the collisionShapes of its sub chunk.
+func shapeIndex(pos cube.Pos) uint16 {
+	return uint16(pos[0]&0xf)<<8 | uint16(pos[2]&0xf)<<4 | uint16(pos[1]&0xf)
+}
+
+// add adds the collision boxes of the block at the position passed, relative to that position.
+func (s *collisionShapes) add(pos cube.Pos, boxes []cube.BBox) {
+	ind := shapeIndex(pos)
+	if len(boxes) == 1 && boxes[0] == fullBlockBox {
+		s.full[ind>>6] |= 1 << (ind & 63)
+		return
+	}
+	if s.other == nil {
+		s.other = make(map[uint16][]cube.BBox)
+	}
+	s.other[ind] = boxes
+}
+
+// appendBoxes appends the collision boxes of the block at the position passed to dst, translated to the position of
+// the block.
+func (s *collisionShapes) appendBoxes(dst []cube.BBox, pos cube.Pos) []cube.BBox {
+	ind := shapeIndex(pos)
+	if s.full[ind>>6]&(1<<(ind&63)) != 0 {
+		return append(dst, fullBlockBox.Translate(pos.Vec3()))
+	}
+	for _, box := range s.other[ind] {
+		dst = append(dst, box.Translate(pos.Vec3()))
+	}
+	return dst
+}
+
+// BlockBBoxes returns the collision boxes of all blocks at the block positions that the cube.BBox passed spans,
+// translated to the positions of the blocks. This is the same as calling Block.Model().BBox() for every position
+// and translating the boxes, but the boxes of every sub chunk are computed once and kept until a block in or next
+// to the sub chunk changes, which makes BlockBBoxes much cheaper when called every tick, such as for entity
+// physics. Chunks that are not yet loaded are loaded, or generated if they could not be found in the world save.
+func (w *World) BlockBBoxes(box cube.BBox) []cube.BBox {
+	if w == nil {
+		return nil
+	}
+	min, max := cube.PosFromVec3(box.Min()), cube.PosFromVec3(box.Max())
+	r := w.Range()
+	min[1], max[1] = maxInt(min[1], r[0]), minInt(max[1], r[1])
+
+	boxes := make([]cube.BBox, 0, (max[0]-min[0]+1)*(max[2]-min[2]+1)*(max[1]-min[1]+1))
+	for chunkX := min[0] >> 4; chunkX <= max[0]>>4; chunkX++ {
+		for chunkZ := min[2] >> 4; chunkZ <= max[2]>>4; chunkZ++ {
+			chunkPos := ChunkPos{int32(chunkX), int32(chunkZ)}
+			for subY := min[1] >> 4; subY <= max[1]>>4; subY++ {
+				shapes := w.collisionShapes(chunkPos, int16(subY-r[0]>>4))
+				if shapes.other == nil && shapes.full == ([64]uint64{}) {
+					continue
+				}
+				for x := maxInt(min[0], chunkX<<4); x <= minInt(max[0], chunkX<<4+15); x++ {
+					for z := maxInt(min[2], chunkZ<<4); z <= minInt(max[2], chunkZ<<4+15); z++ {
+						for y := maxInt(min[1], subY<<4); y <= minInt(max[1], subY<<4+15); y++ {
+							boxes = shapes.appendBoxes(boxes, cube.Pos{x, y, z})
+						}
+					}
+				}
+			}
+		}
+	}
+	return boxes
+}
+
+// collisionShapes returns the collisionShapes of the sub chunk at the index passed in the chunk at the position
+// passed, computing them if they were not yet computed since the sub chunk last changed.
+func (w *World) collisionShapes(pos ChunkPos, ind int16) *collisionShapes {
+	c := w.chunk(pos)
+	if shapes, ok := c.collisions[ind]; ok {
+		c.Unlock()
+		return shapes
+	}
+	if c.Sub()[ind].Empty() {
+		c.Unlock()
+		return &collisionShapes{}
+	}
+	gen := c.collisionGen
+	c.Unlock()
+
+	// The models of blocks may look at the blocks around them, so the chunk cannot be locked while computing
+	// their boxes. If the chunk changes in the meantime, the shapes computed are not stored.
+	shapes := &collisionShapes{}
+	baseX, baseY, baseZ := int(pos[0])<<4, (int(ind)+w.Range()[0]>>4)<<4, int(pos[1])<<4
+	for x := baseX; x < baseX+16; x++ {
+		for z := baseZ; z < baseZ+16; z++ {
+			for y := baseY; y < baseY+16; y++ {
+				blockPos := cube.Pos{x, y, z}
+				if boxes := w.Block(blockPos).Model().BBox(blockPos, w); len(boxes) != 0 {
+					shapes.add(blockPos, boxes)
+				}
+			}
+		}
+	}
+
+	c = w.chunk(pos)
+	if c.collisionGen == gen {
+		if c.collisions == nil {
+			c.collisions = make(map[int16]*collisionShapes)
+		}
+		c.collisions[ind] = shapes
+	}
+	c.Unlock()
+	return shapes
+}
+
+// invalidateCollisions drops the collisionShapes of the sub chunks holding the block at the Y value passed and the
+// blocks directly above and below it. The Column must be locked.
+func (c *Column) invalidateCollisions(y int) {
+	c.collisionGen++
+	if len(c.collisions) == 0 {
+		return
+	}
+	for _, dy := range [...]int{-1, 0, 1} {
+		if cy := y + dy; cy >= c.Range()[0] && cy <= c.Range()[1] {
+			delete(c.collisions, c.SubIndex(int16(cy)))
+		}
+	}
+}
+
+// clearCollisions drops all collisionShapes of the Column. The Column must be locked.
+func (c *Column) clearCollisions() {
+	c.collisionGen++
+	c.collisions = nil
+}
+
+// invalidateNeighbourCollisions drops the collisionShapes of the sub chunks holding the blocks horizontally next to
+// the position passed that are in a different chunk, because the models of blocks such as fences depend on the
+// blocks next to them. Chunks that are not loaded are not loaded by invalidateNeighbourCollisions.
+func (w *World) invalidateNeighbourCollisions(pos cube.Pos) {
+	chunkPos := chunkPosFromBlockPos(pos)
+	for _, face := range cube.HorizontalFaces() {
+		neighbour := pos.Side(face)
+		if neighbourPos := chunkPosFromBlockPos(neighbour); neighbourPos != chunkPos {
+			w.withLoadedColumn(neighbourPos, func(c *Column) {
+				c.invalidateCollisions(neighbour[1])
+			})
+		}
+	}
+}
+
+// clearCollisionsAround drops the collisionShapes of all loaded chunks between the chunk positions passed and of the
+// chunks directly around them.
+func (w *World) clearCollisionsAround(min, max ChunkPos) {
+	for x := min[0] - 1; x <= max[0]+1; x++ {
+		for z := min[1] - 1; z <= max[1]+1; z++ {
+			w.withLoadedColumn(ChunkPos{x, z}, (*Column).clearCollisions)
+		}
+	}
+}
+
+// withLoadedColumn calls f with the Column at the position passed locked, if it is currently loaded.
+func (w *World) withLoadedColumn(pos ChunkPos, f func(c *Column)) {
+	w.chunkMu.Lock()
+	c, ok := w.chunks[pos]
+	w.chunkMu.Unlock()
+	if ok {
+		c.Lock()
+		f(c)
+		c.Unlock()
+	}
+}
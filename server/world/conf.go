@@ -1,8 +1,8 @@
 package world
 
 import (
-	"github.com/df-mc/atomic"
 	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/crash"
 	"github.com/sirupsen/logrus"
 	"math/rand"
 	"time"
@@ -28,17 +28,54 @@ type Config struct {
 	Generator Generator
 	// ReadOnly specifies if the World should be read-only, meaning no new data will be written to the Provider.
 	ReadOnly bool
+	// RegenerateCorruptChunks specifies if chunks that the Provider fails to load because their data is corrupt
+	// (see CorruptColumnError) should be generated again using the Generator. The corrupt data is overwritten once
+	// the chunk is saved. If false, corrupt chunks are left empty and are not saved, so that the data may be
+	// recovered by other means.
+	RegenerateCorruptChunks bool
 	// RandomTickSpeed specifies the rate at which blocks should be ticked in the World. By default, each sub chunk has
 	// 3 blocks randomly ticked per sub chunk, so the default value is 3. Setting this value to -1 or lower will stop
-	// random ticking altogether, while setting it higher results in faster ticking.
+	// random ticking altogether, while setting it higher results in faster ticking. The rate is multiplied by the
+	// value of GameRuleRandomTickSpeed, which is 1 by default.
 	RandomTickSpeed int
+	// TickBudget is the amount of time that the World may spend on a single tick, including the loading, generation
+	// and lighting of new chunks for its Loaders. Once the budget of a tick is used up, Loaders stop loading new
+	// chunks until the next tick, so that players exploring new areas do not slow down the World for everyone.
+	// Chunks already in memory are not affected, and at least one new chunk is loaded every tick so that loading
+	// never stalls completely. If set to 0, the budget is 50ms, the length of a single tick. Setting TickBudget to
+	// -1 or lower disables the throttling of chunk loading.
+	TickBudget time.Duration
 	// RandSource is the rand.Source used for generation of random numbers in a World, such as when selecting blocks to
 	// tick or when deciding where to strike lightning. If set to nil, `rand.NewSource(time.Now().Unix())` will be used
 	// to generate a new source.
+	// All randomness in the World, such as random ticks, block and entity drops and the AI of mobs, is derived from
+	// RandSource through World.Rand. Setting RandSource to a source with a fixed seed therefore makes a World behave
+	// the same every time a scenario is played out, which is useful for tests and replays.
 	RandSource rand.Source
+	// Seed is the seed of the World, from which World.PositionRand derives random sources that are keyed by a
+	// position. Generators typically use the same seed to create their noise.Sources. If set to 0, a seed is taken
+	// from RandSource.
+	Seed int64
 	// Entities is an EntityRegistry with all entity types registered that may
 	// be added to the World.
 	Entities EntityRegistry
+	// CrashReporter is the crash.Reporter used to recover panics that occur while ticking the World or saving its
+	// chunks. An entity that panics while being ticked is closed, so that the rest of the World keeps running. If set
+	// to nil, a crash.Reporter that logs to Log will be used.
+	CrashReporter *crash.Reporter
+	// SpawnProtection is the radius in blocks of the area around the spawn of the World in which players may not
+	// build or interact with blocks, unless exempted. The area extends SpawnProtection blocks horizontally from
+	// the spawn in every direction and covers the full height of the World. If set to 0 or lower, the spawn is not
+	// protected. The radius may be changed later using World.SetSpawnProtection.
+	SpawnProtection int
+	// SaveInterval is the interval at which chunks of the World that were changed are written to the Provider using
+	// World.Save, so that changes are not lost if the program is terminated unexpectedly. If set to 0, changed
+	// chunks are saved every 5 minutes. Setting SaveInterval to -1 or lower disables saving chunks periodically, in
+	// which case they are only saved when they are unloaded or the World is closed.
+	SaveInterval time.Duration
+	// Rules holds expressions that tune the gameplay of the World, such as the damage dealt by falling. Expressions
+	// that are not set are not used. The Rules may be changed later using World.SetRules.
+	Rules Rules
 }
 
 // Logger is a logger implementation that may be passed to the Log field of Config. World will send errors and debug
@@ -48,6 +85,13 @@ type Logger interface {
 	Debugf(format string, a ...any)
 }
 
+// fieldLogger is a Logger that supports adding fields to the entries it logs, such as a logrus.Logger. If the
+// Logger of a World is a fieldLogger, entries logged by the World hold the name of the World and, where relevant,
+// the position of the chunk concerned.
+type fieldLogger interface {
+	WithField(key string, value any) *logrus.Entry
+}
+
 // New creates a new World using the Config conf. The World returned will start ticking as soon as a viewer is added
 // to it and is otherwise ready for use.
 func (conf Config) New() *World {
@@ -63,29 +107,60 @@ func (conf Config) New() *World {
 	if conf.Generator == nil {
 		conf.Generator = NopGenerator{}
 	}
+	if conf.CrashReporter == nil {
+		conf.CrashReporter = &crash.Reporter{Log: conf.Log}
+	}
 	if conf.RandomTickSpeed == 0 {
 		conf.RandomTickSpeed = 3
 	}
+	if conf.TickBudget == 0 {
+		conf.TickBudget = time.Second / 20
+	}
+	if conf.SaveInterval == 0 {
+		conf.SaveInterval = time.Minute * 5
+	}
 	if conf.RandSource == nil {
 		conf.RandSource = rand.NewSource(time.Now().Unix())
 	}
+	if conf.Seed == 0 {
+		conf.Seed = conf.RandSource.Int63()
+	}
 	s := conf.Provider.Settings()
 	w := &World{
 		scheduledUpdates: make(map[cube.Pos]int64),
 		entities:         make(map[Entity]ChunkPos),
 		viewers:          make(map[*Loader]Viewer),
 		chunks:           make(map[ChunkPos]*Column),
+		spawned:          make(map[Entity]SpawnCategory),
+		spawnRules:       DefaultSpawnRules(),
 		closing:          make(chan struct{}),
-		handler:          *atomic.NewValue[Handler](NopHandler{}),
-		r:                rand.New(conf.RandSource),
-		advance:          s.ref.Inc() == 1,
+		handler:          &bus{},
+		r:                rand.New(&lockedSource{src: conf.RandSource}),
 		conf:             conf,
 		ra:               conf.Dim.Range(),
-		set:              s,
 	}
+	w.set.Store(s)
+	s.Lock()
+	if s.advancer == nil {
+		s.advancer = w
+	}
+	s.Unlock()
 	w.weather, w.ticker = weather{w: w}, ticker{w: w}
+	if l, ok := conf.Log.(fieldLogger); ok {
+		w.conf.Log = l.WithField("world", w.Name())
+	}
+	w.prov.Store(conf.Provider)
+	w.spawnProtection.Store(int32(conf.SpawnProtection))
+	w.rules.Store(conf.Rules)
+	if w.advancing() {
+		w.loadGameRules(conf.Provider)
+	}
+	w.updateSnapshot()
 
 	go w.tickLoop()
 	go w.chunkCacheJanitor()
+	if conf.SaveInterval > 0 && !conf.ReadOnly {
+		go w.autoSave()
+	}
 	return w
 }
@@ -0,0 +1,199 @@
+package diff
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"golang.org/x/exp/maps"
+)
+
+// column holds the serialised data of a world.Column kept in memory by a Provider, along with hashes of the data of
+// the same column in the Base, which are used to find the data that differs from the Base.
+type column struct {
+	sub                     [][]byte
+	biomes                  []byte
+	entities, blockEntities []byte
+
+	hasBase                         bool
+	baseSub                         [][sha256.Size]byte
+	baseBiomes                      [sha256.Size]byte
+	baseEntities, baseBlockEntities [sha256.Size]byte
+}
+
+// setBase sets the current data of the column as the data of the column in the Base.
+func (c *column) setBase() {
+	c.hasBase = true
+	c.baseSub = make([][sha256.Size]byte, len(c.sub))
+	for i, sub := range c.sub {
+		c.baseSub[i] = sha256.Sum256(sub)
+	}
+	c.baseBiomes = sha256.Sum256(c.biomes)
+	c.baseEntities, c.baseBlockEntities = sha256.Sum256(c.entities), sha256.Sum256(c.blockEntities)
+}
+
+// apply applies the differences passed to the column.
+func (c *column) apply(d columnDiff) {
+	if len(c.sub) < d.SubChunkCount {
+		c.sub = append(c.sub, make([][]byte, d.SubChunkCount-len(c.sub))...)
+	}
+	for i, sub := range d.SubChunks {
+		if i >= 0 && i < len(c.sub) {
+			c.sub[i] = sub
+		}
+	}
+	if d.Biomes != nil {
+		c.biomes = d.Biomes
+	}
+	c.entities, c.blockEntities = d.Entities, d.BlockEntities
+}
+
+// diff returns the differences between the column and the column in the Base. If the column does not differ from
+// the Base, false is returned.
+func (c *column) diff(k key) (columnDiff, bool) {
+	d := columnDiff{Pos: k.pos, Dim: k.dim, SubChunkCount: len(c.sub), SubChunks: make(map[int][]byte)}
+	for i, sub := range c.sub {
+		if !c.hasBase || i >= len(c.baseSub) || sha256.Sum256(sub) != c.baseSub[i] {
+			d.SubChunks[i] = sub
+		}
+	}
+	if !c.hasBase || sha256.Sum256(c.biomes) != c.baseBiomes {
+		d.Biomes = c.biomes
+	}
+	changed := len(d.SubChunks) > 0 || d.Biomes != nil ||
+		sha256.Sum256(c.entities) != c.baseEntities || sha256.Sum256(c.blockEntities) != c.baseBlockEntities
+	d.Entities, d.BlockEntities = c.entities, c.blockEntities
+	return d, changed
+}
+
+// encodeColumn serialises the world.Column passed into the column.
+func (p *Provider) encodeColumn(c *column, col *world.Column) {
+	data := chunk.Encode(col.Chunk, chunk.DiskEncoding)
+	c.sub, c.biomes = data.SubChunks, data.Biomes
+	c.entities = p.encodeEntities(col.Entities)
+	c.blockEntities = p.encodeBlockEntities(col.BlockEntities)
+}
+
+// decodeColumn decodes the column passed into a new world.Column.
+func (p *Provider) decodeColumn(c *column, r cube.Range) (*world.Column, error) {
+	ch, err := chunk.DiskDecode(chunk.SerialisedData{SubChunks: c.sub, Biomes: c.biomes}, r)
+	if err != nil {
+		return nil, err
+	}
+	col := &world.Column{Chunk: ch}
+	if col.Entities, err = p.decodeEntities(c.entities); err != nil {
+		return nil, err
+	}
+	if col.BlockEntities, err = p.decodeBlockEntities(c.blockEntities, ch); err != nil {
+		return nil, err
+	}
+	return col, nil
+}
+
+// encodeEntities encodes the entities passed that are saved with their column to NBT.
+func (p *Provider) encodeEntities(entities []world.Entity) []byte {
+	buf := bytes.NewBuffer(nil)
+	enc := nbt.NewEncoderWithEncoding(buf, nbt.LittleEndian)
+	for _, e := range entities {
+		t, ok := e.Type().(world.SaveableEntityType)
+		if !ok {
+			continue
+		}
+		x := t.EncodeNBT(e)
+		x["identifier"] = t.EncodeEntity()
+		world.EncodeEntityMetadata(e, x)
+		if err := enc.Encode(x); err != nil {
+			p.conf.Log.Errorf("store entities: error encoding NBT: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// encodeBlockEntities encodes the block entities passed to NBT.
+func (p *Provider) encodeBlockEntities(blockEntities map[cube.Pos]world.Block) []byte {
+	buf := bytes.NewBuffer(nil)
+	enc := nbt.NewEncoderWithEncoding(buf, nbt.LittleEndian)
+	for pos, b := range blockEntities {
+		n, ok := b.(world.NBTer)
+		if !ok {
+			continue
+		}
+		data := n.EncodeNBT()
+		data["x"], data["y"], data["z"] = int32(pos[0]), int32(pos[1]), int32(pos[2])
+		if err := enc.Encode(data); err != nil {
+			p.conf.Log.Errorf("store block entities: error encoding NBT: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// decodeEntities decodes entities encoded using encodeEntities.
+func (p *Provider) decodeEntities(data []byte) ([]world.Entity, error) {
+	var entities []world.Entity
+
+	buf := bytes.NewBuffer(data)
+	dec := nbt.NewDecoderWithEncoding(buf, nbt.LittleEndian)
+
+	var m map[string]any
+	for buf.Len() != 0 {
+		maps.Clear(m)
+		if err := dec.Decode(&m); err != nil {
+			return nil, fmt.Errorf("decode nbt: %w", err)
+		}
+		name, _ := m["identifier"].(string)
+		t, ok := p.conf.Entities.Lookup(name)
+		if !ok {
+			p.conf.Log.Errorf("entity %v was not registered (%v)", name, m)
+			continue
+		}
+		if s, ok := t.(world.SaveableEntityType); ok {
+			if v := s.DecodeNBT(m); v != nil {
+				world.DecodeEntityMetadata(v, m)
+				entities = append(entities, v)
+			}
+		}
+	}
+	return entities, nil
+}
+
+// decodeBlockEntities decodes block entities encoded using encodeBlockEntities. The chunk passed is used to find
+// the blocks that the block entities belong to.
+func (p *Provider) decodeBlockEntities(data []byte, c *chunk.Chunk) (map[cube.Pos]world.Block, error) {
+	blockEntities := make(map[cube.Pos]world.Block)
+
+	buf := bytes.NewBuffer(data)
+	dec := nbt.NewDecoderWithEncoding(buf, nbt.LittleEndian)
+
+	var m map[string]any
+	for buf.Len() != 0 {
+		maps.Clear(m)
+		if err := dec.Decode(&m); err != nil {
+			return blockEntities, fmt.Errorf("decode nbt: %w", err)
+		}
+		x, _ := m["x"].(int32)
+		y, _ := m["y"].(int32)
+		z, _ := m["z"].(int32)
+		pos := cube.Pos{int(x), int(y), int(z)}
+		if pos.OutOfBounds(c.Range()) {
+			p.conf.Log.Errorf("block entity at %v is outside of the world range", pos)
+			continue
+		}
+
+		id := c.Block(uint8(pos[0]), int16(pos[1]), uint8(pos[2]), 0)
+		b, ok := world.BlockByRuntimeID(id)
+		if !ok {
+			p.conf.Log.Errorf("no block registered with runtime id %v", id)
+			continue
+		}
+		nbter, ok := b.(world.NBTer)
+		if !ok {
+			p.conf.Log.Errorf("block %#v has nbt but does not implement world.nbter", b)
+			continue
+		}
+		blockEntities[pos] = nbter.DecodeNBT(m).(world.Block)
+	}
+	return blockEntities, nil
+}
@@ -0,0 +1,347 @@
+// Package diff implements a world.Provider that keeps a world in memory while it is in use and persists only the
+// differences between the world and a base world snapshot, such as a template island. Sub chunks, biomes and
+// settings that are identical to those of the base are not stored, which keeps the data of worlds that differ little
+// from their base small. This makes the Provider suitable for servers holding many small worlds derived from a
+// single base world, such as SkyBlock islands.
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"os"
+	"sync"
+	"time"
+)
+
+// Logger is a logger implementation that may be passed to the Log field of Config. The Provider will send errors and
+// debug messages to this Logger when appropriate.
+type Logger interface {
+	Errorf(format string, a ...any)
+	Debugf(format string, a ...any)
+}
+
+// Config holds the settings of a Provider. Calling Config.New() opens a Provider.
+type Config struct {
+	// Base is the world.Provider holding the base world that differences are computed against. Base is only ever
+	// read from and is not closed when the Provider is closed, so that a single Base may be shared by many
+	// Providers. Base should usually be opened in read-only mode. If nil, an empty world is used as base.
+	Base world.Provider
+	// Path is the path of the file that the differences with the Base are written to. If the file exists when the
+	// Provider is opened, the differences in it are applied to the Base. If left empty, the differences are never
+	// written and are discarded when the Provider is closed, which is useful for temporary copies of the Base.
+	Path string
+	// Log is the Logger that will be used to log errors and debug messages to. If set to nil, a Logrus logger will be
+	// used.
+	Log Logger
+	// Interval is the interval at which the differences with the Base are written to Path, if the world changed since
+	// they were last written. If left as 0, Interval is set to 5 minutes. If negative, the differences are only
+	// written when Provider.Save or Provider.Close is called.
+	Interval time.Duration
+	// Entities is an EntityRegistry with all entity types registered that may be stored in the Provider. Entities
+	// will default to entity.DefaultRegistry.
+	Entities world.EntityRegistry
+}
+
+// Provider is a world.Provider that keeps the data of a world in memory and periodically writes the differences
+// between the world and a base world to a file.
+type Provider struct {
+	conf  Config
+	set   *world.Settings
+	rules map[string]any
+
+	mu sync.Mutex
+	// columns holds the full, serialised data of all columns that were loaded or stored since the Provider was
+	// opened. pending holds the differences read from Path for columns that were not yet loaded.
+	columns map[key]*column
+	pending map[key]columnDiff
+	spawns  map[uuid.UUID]cube.Pos
+	// changed is true if the world changed since the differences were last written.
+	changed bool
+
+	saveMu  sync.Mutex
+	closing chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// key is the key of a column in a Provider.
+type key struct {
+	pos world.ChunkPos
+	dim int
+}
+
+// New opens a Provider using the settings in the Config, reading the differences with the Base from Config.Path if
+// the file exists. Close must be called to write the remaining changes and stop the Provider.
+func (conf Config) New() (*Provider, error) {
+	if conf.Base == nil {
+		conf.Base = world.NopProvider{}
+	}
+	if conf.Log == nil {
+		conf.Log = logrus.New()
+	}
+	if conf.Interval == 0 {
+		conf.Interval = time.Minute * 5
+	}
+	if len(conf.Entities.Types()) == 0 {
+		conf.Entities = entity.DefaultRegistry
+	}
+	p := &Provider{
+		conf:    conf,
+		columns: make(map[key]*column),
+		pending: make(map[key]columnDiff),
+		spawns:  make(map[uuid.UUID]cube.Pos),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	var s snapshot
+	if conf.Path != "" {
+		var err error
+		if s, err = readSnapshot(conf.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("open diff provider: %w", err)
+		}
+	}
+	p.set = copySettings(conf.Base.Settings())
+	if s.Settings != nil {
+		s.Settings.apply(p.set)
+	}
+	p.rules = conf.Base.GameRules()
+	if s.GameRules != nil {
+		p.rules = s.GameRules
+	}
+	for _, d := range s.Columns {
+		p.pending[key{pos: d.Pos, dim: d.Dim}] = d
+	}
+	for id, pos := range s.Spawns {
+		p.spawns[id] = pos
+	}
+	if conf.Interval > 0 && conf.Path != "" {
+		go p.autoSave()
+	} else {
+		close(p.done)
+	}
+	return p, nil
+}
+
+// Settings returns the settings of the world, which are those of the Base with the differences applied.
+func (p *Provider) Settings() *world.Settings {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.set
+}
+
+// SaveSettings stores the settings passed in memory, to be written with the next differences.
+func (p *Provider) SaveSettings(s *world.Settings) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.set = s
+	p.changed = true
+}
+
+// GameRules returns the values of the game rules of the world, which are those of the Base unless they were saved
+// since.
+func (p *Provider) GameRules() map[string]any {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.rules
+}
+
+// SaveGameRules stores the values of the game rules passed in memory, to be written with the next differences.
+func (p *Provider) SaveGameRules(rules map[string]any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rules = rules
+	p.changed = true
+}
+
+// LoadPlayerSpawnPosition loads the spawn position of the player with the UUID passed. If it was not changed since
+// the Provider was created, it is loaded from the Base.
+func (p *Provider) LoadPlayerSpawnPosition(id uuid.UUID) (pos cube.Pos, exists bool, err error) {
+	p.mu.Lock()
+	pos, ok := p.spawns[id]
+	p.mu.Unlock()
+	if ok {
+		return pos, true, nil
+	}
+	return p.conf.Base.LoadPlayerSpawnPosition(id)
+}
+
+// SavePlayerSpawnPosition stores the spawn position of the player with the UUID passed in memory, to be written with
+// the next differences.
+func (p *Provider) SavePlayerSpawnPosition(id uuid.UUID, pos cube.Pos) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.spawns[id] = pos
+	p.changed = true
+	return nil
+}
+
+// LoadColumn loads the world.Column at a position and dimension. Columns are kept in memory once loaded. If no column
+// at that position exists in the Provider or the Base, errors.Is(err, leveldb.ErrNotFound) equals true. If the data
+// of the column is malformed, the error returned is a *world.CorruptColumnError.
+func (p *Provider) LoadColumn(pos world.ChunkPos, dim world.Dimension) (*world.Column, error) {
+	k, err := p.key(pos, dim)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, ok := p.columns[k]
+	if !ok {
+		if c, err = p.loadBase(pos, dim); err != nil {
+			return nil, fmt.Errorf("load column %v (%v): %w", pos, dim, err)
+		}
+		d, hasDiff := p.pending[k]
+		if hasDiff {
+			delete(p.pending, k)
+			c.apply(d)
+		}
+		if c.sub == nil {
+			// Neither the Base nor the differences hold the column.
+			return nil, leveldb.ErrNotFound
+		}
+		p.columns[k] = c
+	}
+	col, err := p.decodeColumn(c, dim.Range())
+	if err != nil {
+		return nil, &world.CorruptColumnError{Pos: pos, Dim: dim, Err: err}
+	}
+	return col, nil
+}
+
+// StoreColumn stores the world.Column passed in memory, to be written with the next differences if it differs from
+// the column in the Base.
+func (p *Provider) StoreColumn(pos world.ChunkPos, dim world.Dimension, col *world.Column) error {
+	k, err := p.key(pos, dim)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, ok := p.columns[k]
+	if !ok {
+		// The column was never loaded from the Provider, which may happen if the Base was changed or the column was
+		// stored without first being loaded, so its hashes still need to be computed.
+		if c, err = p.loadBase(pos, dim); err != nil {
+			return fmt.Errorf("store column %v (%v): %w", pos, dim, err)
+		}
+		delete(p.pending, k)
+		p.columns[k] = c
+	}
+	p.encodeColumn(c, col)
+	p.changed = true
+	return nil
+}
+
+// Save writes the differences between the world and the Base to Config.Path if the world changed since they were
+// last written. The file is replaced atomically, so that it is never left partially written. If Config.Path is empty,
+// Save does nothing.
+func (p *Provider) Save() error {
+	if p.conf.Path == "" {
+		return nil
+	}
+	p.saveMu.Lock()
+	defer p.saveMu.Unlock()
+
+	p.mu.Lock()
+	if !p.changed {
+		p.mu.Unlock()
+		return nil
+	}
+	set := p.set
+	s := snapshot{
+		GameRules: p.rules,
+		Spawns:    make(map[uuid.UUID]cube.Pos, len(p.spawns)),
+		Columns:   make([]columnDiff, 0, len(p.columns)+len(p.pending)),
+	}
+	for id, pos := range p.spawns {
+		s.Spawns[id] = pos
+	}
+	for _, d := range p.pending {
+		s.Columns = append(s.Columns, d)
+	}
+	for k, c := range p.columns {
+		if d, ok := c.diff(k); ok {
+			s.Columns = append(s.Columns, d)
+		}
+	}
+	p.changed = false
+	p.mu.Unlock()
+
+	// The settings are only locked once the Provider is unlocked, as the World may hold the lock of the settings
+	// while calling SaveSettings.
+	s.Settings = newSettings(set)
+	if err := writeSnapshot(p.conf.Path, s); err != nil {
+		p.mu.Lock()
+		p.changed = true
+		p.mu.Unlock()
+		return fmt.Errorf("save diff: %w", err)
+	}
+	p.conf.Log.Debugf("Saved differences of %v columns to %v.", len(s.Columns), p.conf.Path)
+	return nil
+}
+
+// Close stops writing differences periodically and writes the remaining differences to Config.Path, or discards them
+// if Config.Path is empty. The Base of the Provider is not closed.
+func (p *Provider) Close() error {
+	p.once.Do(func() {
+		close(p.closing)
+	})
+	<-p.done
+	if p.conf.Path == "" {
+		// The differences are discarded, so the memory holding them may be released.
+		p.mu.Lock()
+		p.columns, p.pending = make(map[key]*column), make(map[key]columnDiff)
+		p.mu.Unlock()
+	}
+	return p.Save()
+}
+
+// autoSave runs until the Provider is closed, calling Save every Config.Interval.
+func (p *Provider) autoSave() {
+	defer close(p.done)
+	t := time.NewTicker(p.conf.Interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if err := p.Save(); err != nil {
+				p.conf.Log.Errorf("%v", err)
+			}
+		case <-p.closing:
+			return
+		}
+	}
+}
+
+// key returns the key of the column at the position and dimension passed.
+func (p *Provider) key(pos world.ChunkPos, dim world.Dimension) (key, error) {
+	id, ok := world.DimensionID(dim)
+	if !ok {
+		return key{}, fmt.Errorf("unknown dimension %v", dim)
+	}
+	return key{pos: pos, dim: id}, nil
+}
+
+// loadBase loads the column at the position and dimension passed from the Base. If the Base does not hold the
+// column, a column without data is returned.
+func (p *Provider) loadBase(pos world.ChunkPos, dim world.Dimension) (*column, error) {
+	col, err := p.conf.Base.LoadColumn(pos, dim)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return &column{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("load base: %w", err)
+	}
+	c := &column{}
+	p.encodeColumn(c, col)
+	c.setBase()
+	return c, nil
+}
@@ -0,0 +1,147 @@
+package diff
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/google/uuid"
+	"os"
+	"path/filepath"
+)
+
+// snapshotVersion is the version of the format of files written by a Provider.
+const snapshotVersion = 1
+
+// snapshot holds all differences between a world and its Base. It is written to files gob encoded and gzip
+// compressed.
+type snapshot struct {
+	Version   int
+	Settings  *settings
+	GameRules map[string]any
+	Spawns    map[uuid.UUID]cube.Pos
+	Columns   []columnDiff
+}
+
+// columnDiff holds the differences of a single column with the same column in the Base. SubChunks holds only the
+// sub chunks that differ from the Base, by index, and Biomes is nil if the biomes do not differ. Entities and
+// BlockEntities are always stored in full.
+type columnDiff struct {
+	Pos                     world.ChunkPos
+	Dim                     int
+	SubChunkCount           int
+	SubChunks               map[int][]byte
+	Biomes                  []byte
+	Entities, BlockEntities []byte
+}
+
+// settings holds the fields of world.Settings that are stored in a snapshot.
+type settings struct {
+	Name                        string
+	Spawn                       cube.Pos
+	Time, RainTime, ThunderTime int64
+	TimeCycle, WeatherCycle     bool
+	Raining, Thundering         bool
+	CurrentTick                 int64
+	DefaultGameMode, Difficulty int
+	TickRange                   int32
+}
+
+// newSettings creates settings holding the fields of the world.Settings passed.
+func newSettings(s *world.Settings) *settings {
+	s.Lock()
+	defer s.Unlock()
+	mode, _ := world.GameModeID(s.DefaultGameMode)
+	diff, _ := world.DifficultyID(s.Difficulty)
+	return &settings{
+		Name:            s.Name,
+		Spawn:           s.Spawn,
+		Time:            s.Time,
+		RainTime:        s.RainTime,
+		ThunderTime:     s.ThunderTime,
+		TimeCycle:       s.TimeCycle,
+		WeatherCycle:    s.WeatherCycle,
+		Raining:         s.Raining,
+		Thundering:      s.Thundering,
+		CurrentTick:     s.CurrentTick,
+		DefaultGameMode: mode,
+		Difficulty:      diff,
+		TickRange:       s.TickRange,
+	}
+}
+
+// apply sets the fields of the world.Settings passed to those of the settings.
+func (set *settings) apply(s *world.Settings) {
+	s.Lock()
+	defer s.Unlock()
+	s.Name, s.Spawn = set.Name, set.Spawn
+	s.Time, s.RainTime, s.ThunderTime = set.Time, set.RainTime, set.ThunderTime
+	s.TimeCycle, s.WeatherCycle = set.TimeCycle, set.WeatherCycle
+	s.Raining, s.Thundering = set.Raining, set.Thundering
+	s.CurrentTick, s.TickRange = set.CurrentTick, set.TickRange
+	if mode, ok := world.GameModeByID(set.DefaultGameMode); ok {
+		s.DefaultGameMode = mode
+	}
+	if diff, ok := world.DifficultyByID(set.Difficulty); ok {
+		s.Difficulty = diff
+	}
+}
+
+// copySettings returns a new world.Settings with the same fields as the world.Settings passed, so that the Settings
+// of a Base shared by many Providers are not changed.
+func copySettings(s *world.Settings) *world.Settings {
+	c := &world.Settings{}
+	newSettings(s).apply(c)
+	return c
+}
+
+// readSnapshot reads a snapshot from the file at the path passed.
+func readSnapshot(path string) (snapshot, error) {
+	var s snapshot
+	f, err := os.Open(path)
+	if err != nil {
+		return s, err
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return s, fmt.Errorf("read %v: %w", path, err)
+	}
+	if err := gob.NewDecoder(r).Decode(&s); err != nil {
+		return s, fmt.Errorf("read %v: %w", path, err)
+	}
+	if s.Version != snapshotVersion {
+		return s, fmt.Errorf("read %v: unsupported version %v", path, s.Version)
+	}
+	return s, nil
+}
+
+// writeSnapshot writes the snapshot passed to the file at the path passed. The snapshot is first written to a
+// temporary file, which then replaces the file at the path, so that the file is never left partially written.
+func writeSnapshot(path string, s snapshot) error {
+	s.Version = snapshotVersion
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	w := gzip.NewWriter(f)
+	if err := gob.NewEncoder(w).Encode(s); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
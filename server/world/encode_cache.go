@@ -0,0 +1,97 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"sync"
+)
+
+// encodeKey identifies a network encoded part of a chunk in an encodedChunk.
+type encodeKey struct {
+	// ind is the index of the sub chunk encoded, or biomeIndex for the biomes of the chunk.
+	ind int16
+	// variant distinguishes between different encodings of the same sub chunk, such as those obfuscated by an
+	// anti-xray engine.
+	variant int
+}
+
+// biomeIndex is the index used in an encodeKey for the encoded biomes of a chunk.
+const biomeIndex = -1
+
+// encodedChunk holds the network encoded parts of a chunk and the chunk and version they were encoded from.
+type encodedChunk struct {
+	c       *chunk.Chunk
+	version uint64
+	data    map[encodeKey][]byte
+}
+
+// encodeCache holds the network encoded sub chunks and biomes of the chunks in a World, so that a chunk viewed by
+// many players is only encoded once every time it changes, rather than once for every viewer.
+type encodeCache struct {
+	mu sync.Mutex
+	m  map[ChunkPos]*encodedChunk
+}
+
+// load returns the data cached for the key passed if it was encoded from the current version of the chunk at the
+// position passed. If not, encode is called and the data it returns is cached. All data cached for the chunk is
+// dropped once the chunk changes.
+func (e *encodeCache) load(pos ChunkPos, k encodeKey, c *chunk.Chunk, encode func() []byte) []byte {
+	version := c.Version()
+	e.mu.Lock()
+	if e.m == nil {
+		e.m = make(map[ChunkPos]*encodedChunk)
+	}
+	enc, ok := e.m[pos]
+	if !ok || enc.c != c || enc.version != version {
+		enc = &encodedChunk{c: c, version: version, data: make(map[encodeKey][]byte)}
+		e.m[pos] = enc
+	}
+	if data, ok := enc.data[k]; ok {
+		e.mu.Unlock()
+		return data
+	}
+	e.mu.Unlock()
+
+	data := encode()
+	e.mu.Lock()
+	enc.data[k] = data
+	e.mu.Unlock()
+	return data
+}
+
+// forget removes all data cached for the chunk at the position passed.
+func (e *encodeCache) forget(pos ChunkPos) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.m, pos)
+}
+
+// EncodedSubChunk returns the sub chunk at index ind of the chunk at the position passed, encoded using
+// chunk.NetworkEncoding. The encoding is cached, so that it is shared between all viewers of the chunk until a block
+// in it changes. variant identifies the way in which the sub chunk is encoded: Viewers that encode sub chunks
+// differently, such as by obfuscating blocks, must pass a different variant and an encode function that returns
+// their encoding. If encode is nil, chunk.EncodeSubChunk is used. The chunk must be locked while calling
+// EncodedSubChunk, and the data returned must not be modified.
+func (w *World) EncodedSubChunk(pos ChunkPos, c *chunk.Chunk, ind int16, variant int, encode func() []byte) []byte {
+	if encode == nil {
+		encode = func() []byte {
+			return chunk.EncodeSubChunk(c, chunk.NetworkEncoding, int(ind))
+		}
+	}
+	if w == nil {
+		return encode()
+	}
+	return w.encoded.load(pos, encodeKey{ind: ind, variant: variant}, c, encode)
+}
+
+// EncodedBiomes returns the biomes of the chunk at the position passed, encoded using chunk.NetworkEncoding. Like
+// EncodedSubChunk, the encoding is cached until a biome in the chunk changes. The chunk must be locked while calling
+// EncodedBiomes, and the data returned must not be modified.
+func (w *World) EncodedBiomes(pos ChunkPos, c *chunk.Chunk) []byte {
+	encode := func() []byte {
+		return chunk.EncodeBiomes(c, chunk.NetworkEncoding)
+	}
+	if w == nil {
+		return encode()
+	}
+	return w.encoded.load(pos, encodeKey{ind: biomeIndex}, c, encode)
+}
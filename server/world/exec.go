@@ -0,0 +1,114 @@
+package world
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// Exec queues the function passed to be run on the goroutine that ticks the World, at the start of the next tick.
+// Exec is the entry point for code that runs on goroutines of its own, such as plugins handling a network request or
+// timer, and needs to change the World: All functions passed to Exec run one after another, in the order in which
+// they were queued, and never concurrently with the tick of the World itself.
+// The channel returned is closed once the function has run. Functions queued before the World is closed are run
+// while it is closing. Functions passed after the World was closed are not run, and the channel returned is closed
+// immediately.
+// Exec must not be waited on from the goroutine that ticks the World, such as from a block or entity tick, as the
+// function will not run until that tick has finished.
+func (w *World) Exec(f func()) <-chan struct{} {
+	done := make(chan struct{})
+	if w == nil {
+		close(done)
+		return done
+	}
+	w.execMu.Lock()
+	defer w.execMu.Unlock()
+	if w.execClosed {
+		close(done)
+		return done
+	}
+	w.execQueue = append(w.execQueue, execTask{f: f, done: done})
+	return done
+}
+
+// execTask is a function queued using World.Exec.
+type execTask struct {
+	f    func()
+	done chan struct{}
+}
+
+// runExec runs all functions queued using World.Exec. If closing is true, the World no longer accepts new functions.
+func (w *World) runExec(closing bool) {
+	w.execMu.Lock()
+	tasks := w.execQueue
+	w.execQueue, w.execClosed = nil, closing
+	w.execMu.Unlock()
+	if len(tasks) == 0 {
+		return
+	}
+
+	set := w.settings()
+	set.Lock()
+	ctx := w.ticker.crashContext(set.CurrentTick, nil)
+	set.Unlock()
+	for _, task := range tasks {
+		w.conf.CrashReporter.Catch("world exec", ctx, task.f)
+		close(task.done)
+	}
+}
+
+// Snapshot holds a read-only copy of the state of a World at the end of a tick. A Snapshot may be read from any
+// goroutine without locking, making it useful for frequent queries, such as the time of the World or the positions
+// of entities, from goroutines other than the one that ticks the World. The state held may be out of date by at most
+// one tick, or longer if the World is not being ticked, because no players are in it.
+type Snapshot struct {
+	// Tick is the current tick of the World, as returned by World.GameTime.
+	Tick int64
+	// Time is the time of the World, as returned by World.Time.
+	Time int
+	// Raining and Thundering specify if it was raining or thundering in the World.
+	Raining, Thundering bool
+
+	positions map[Entity]mgl64.Vec3
+}
+
+// Position returns the position of the Entity passed at the time of the Snapshot. If the Entity was not in the World,
+// false is returned.
+func (s Snapshot) Position(e Entity) (mgl64.Vec3, bool) {
+	pos, ok := s.positions[e]
+	return pos, ok
+}
+
+// Entities returns all entities that were in the World at the time of the Snapshot.
+func (s Snapshot) Entities() []Entity {
+	entities := make([]Entity, 0, len(s.positions))
+	for e := range s.positions {
+		entities = append(entities, e)
+	}
+	return entities
+}
+
+// Snapshot returns the Snapshot of the World taken at the end of the last tick. Unlike most other methods of World,
+// Snapshot does not acquire any locks and may therefore be called as often as needed from any goroutine.
+func (w *World) Snapshot() Snapshot {
+	if w == nil {
+		return Snapshot{}
+	}
+	if s := w.snapshot.Load(); s != nil {
+		return *s
+	}
+	return Snapshot{}
+}
+
+// updateSnapshot takes a new Snapshot of the World and stores it, so that it is returned by World.Snapshot.
+func (w *World) updateSnapshot() {
+	set := w.settings()
+	set.Lock()
+	s := &Snapshot{Tick: set.CurrentTick, Time: int(set.Time), Raining: set.Raining, Thundering: set.Thundering && set.Raining}
+	set.Unlock()
+
+	entities := w.Entities()
+	s.positions = make(map[Entity]mgl64.Vec3, len(entities))
+	for _, e := range entities {
+		s.positions[e] = e.Position()
+	}
+	w.snapshot.Store(s)
+}
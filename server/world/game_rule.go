@@ -0,0 +1,245 @@
+package world
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GameRule is a rule that changes the gameplay of a World, such as whether players keep their items when they die.
+// Game rules are identified by their name and are either a BoolGameRule or an IntGameRule. The values of the game
+// rules of a World are stored with its Settings, saved by its Provider and sent to the players viewing the World.
+type GameRule interface {
+	// Name returns the name of the GameRule, such as 'keepinventory'. Names are all lowercase.
+	Name() string
+	// Default returns the value of the GameRule in a World that has not changed it. It is a bool for a BoolGameRule
+	// and an int for an IntGameRule.
+	Default() any
+}
+
+// BoolGameRule is a GameRule that is either enabled or disabled.
+type BoolGameRule struct {
+	name string
+	def  bool
+}
+
+// Name ...
+func (r BoolGameRule) Name() string {
+	return r.name
+}
+
+// Default ...
+func (r BoolGameRule) Default() any {
+	return r.def
+}
+
+// IntGameRule is a GameRule that holds a number.
+type IntGameRule struct {
+	name string
+	def  int
+}
+
+// Name ...
+func (r IntGameRule) Name() string {
+	return r.name
+}
+
+// Default ...
+func (r IntGameRule) Default() any {
+	return r.def
+}
+
+var (
+	// GameRuleDoDaylightCycle specifies if the time of the World advances. It is the same as Settings.TimeCycle.
+	GameRuleDoDaylightCycle = registerGameRule(BoolGameRule{name: "dodaylightcycle", def: true})
+	// GameRuleDoWeatherCycle specifies if the weather of the World changes. It is the same as Settings.WeatherCycle.
+	GameRuleDoWeatherCycle = registerGameRule(BoolGameRule{name: "doweathercycle", def: true})
+	// GameRuleDoFireTick specifies if fire spreads and burns out.
+	GameRuleDoFireTick = registerGameRule(BoolGameRule{name: "dofiretick", def: true})
+	// GameRuleDoMobSpawning specifies if mobs spawn naturally in the World.
+	GameRuleDoMobSpawning = registerGameRule(BoolGameRule{name: "domobspawning", def: true})
+	// GameRuleDoImmediateRespawn specifies if players respawn immediately after dying, without the death screen
+	// being shown.
+	GameRuleDoImmediateRespawn = registerGameRule(BoolGameRule{name: "doimmediaterespawn"})
+	// GameRuleFallDamage specifies if entities take damage from falling.
+	GameRuleFallDamage = registerGameRule(BoolGameRule{name: "falldamage", def: true})
+	// GameRuleKeepInventory specifies if players keep their items and experience when they die, rather than
+	// dropping them.
+	GameRuleKeepInventory = registerGameRule(BoolGameRule{name: "keepinventory"})
+	// GameRuleMobGriefing specifies if mobs are able to change blocks, such as creepers destroying blocks when they
+	// explode.
+	GameRuleMobGriefing = registerGameRule(BoolGameRule{name: "mobgriefing", def: true})
+	// GameRuleShowCoordinates specifies if the coordinates of players are shown on their screen.
+	GameRuleShowCoordinates = registerGameRule(BoolGameRule{name: "showcoordinates"})
+	// GameRuleShowDeathMessages specifies if a message is sent to all players in the chat when a player dies.
+	GameRuleShowDeathMessages = registerGameRule(BoolGameRule{name: "showdeathmessages", def: true})
+	// GameRuleRandomTickSpeed is the speed at which blocks are randomly ticked. The amount of blocks ticked per sub
+	// chunk every tick is the value of the rule multiplied by Config.RandomTickSpeed. Setting it to 0 stops random
+	// ticking altogether.
+	GameRuleRandomTickSpeed = registerGameRule(IntGameRule{name: "randomtickspeed", def: 1})
+)
+
+// gameRules holds all game rules by their names.
+var gameRules = map[string]GameRule{}
+
+// registerGameRule registers the GameRule passed so that it may be found using GameRuleByName.
+func registerGameRule[R GameRule](r R) R {
+	if _, ok := gameRules[r.Name()]; ok {
+		panic("cannot register the same game rule twice: " + r.Name())
+	}
+	gameRules[r.Name()] = r
+	return r
+}
+
+// GameRuleByName looks up a GameRule by its name, such as 'keepinventory'. The name is not case-sensitive. If no
+// game rule with the name exists, false is returned.
+func GameRuleByName(name string) (GameRule, bool) {
+	r, ok := gameRules[strings.ToLower(name)]
+	return r, ok
+}
+
+// GameRules returns a list of all game rules, sorted by their names.
+func GameRules() []GameRule {
+	rules := make([]GameRule, 0, len(gameRules))
+	for _, r := range gameRules {
+		rules = append(rules, r)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name() < rules[j].Name()
+	})
+	return rules
+}
+
+// validGameRuleValue checks if the value passed is of the type of the values of the GameRule passed.
+func validGameRuleValue(r GameRule, v any) bool {
+	switch r.(type) {
+	case BoolGameRule:
+		_, ok := v.(bool)
+		return ok
+	case IntGameRule:
+		_, ok := v.(int)
+		return ok
+	}
+	return false
+}
+
+// gameRule returns the value of the GameRule passed in s. s must be locked by the caller.
+func (s *Settings) gameRule(r GameRule) any {
+	switch r {
+	case GameRuleDoDaylightCycle:
+		return s.TimeCycle
+	case GameRuleDoWeatherCycle:
+		return s.WeatherCycle
+	}
+	if v, ok := s.gameRules[r.Name()]; ok {
+		return v
+	}
+	return r.Default()
+}
+
+// setGameRule sets the value of the GameRule passed in s. The value must be valid for the GameRule. s must be locked
+// by the caller.
+func (s *Settings) setGameRule(r GameRule, v any) {
+	switch r {
+	case GameRuleDoDaylightCycle:
+		s.TimeCycle = v.(bool)
+		return
+	case GameRuleDoWeatherCycle:
+		s.WeatherCycle = v.(bool)
+		return
+	}
+	if s.gameRules == nil {
+		s.gameRules = make(map[string]any)
+	}
+	s.gameRules[r.Name()] = v
+}
+
+// gameRuleValues returns the values of all game rules in s by their names. s must be locked by the caller.
+func (s *Settings) gameRuleValues() map[string]any {
+	values := make(map[string]any, len(gameRules))
+	for name, r := range gameRules {
+		values[name] = s.gameRule(r)
+	}
+	return values
+}
+
+// GameRule returns the value of the GameRule passed in the World. The value is a bool for a BoolGameRule and an int
+// for an IntGameRule. BoolGameRule and IntGameRule may be used to get the value with the right type.
+func (w *World) GameRule(r GameRule) any {
+	if w == nil {
+		return r.Default()
+	}
+	set := w.settings()
+	set.Lock()
+	defer set.Unlock()
+	return set.gameRule(r)
+}
+
+// BoolGameRule returns the value of the BoolGameRule passed in the World.
+func (w *World) BoolGameRule(r BoolGameRule) bool {
+	return w.GameRule(r).(bool)
+}
+
+// IntGameRule returns the value of the IntGameRule passed in the World.
+func (w *World) IntGameRule(r IntGameRule) int {
+	return w.GameRule(r).(int)
+}
+
+// GameRules returns the values of all game rules in the World, by their names.
+func (w *World) GameRules() map[string]any {
+	if w == nil {
+		return nil
+	}
+	set := w.settings()
+	set.Lock()
+	defer set.Unlock()
+	return set.gameRuleValues()
+}
+
+// SetGameRule changes the value of the GameRule passed in the World and sends the new value to all viewers of the
+// World. The value must be a bool for a BoolGameRule and an int for an IntGameRule. If it is not, an error is
+// returned.
+func (w *World) SetGameRule(r GameRule, v any) error {
+	if !validGameRuleValue(r, v) {
+		return fmt.Errorf("invalid value %v (%T) for game rule %v", v, v, r.Name())
+	}
+	if w == nil {
+		return nil
+	}
+	set := w.settings()
+	set.Lock()
+	set.setGameRule(r, v)
+	set.Unlock()
+
+	viewers, _ := w.allViewers()
+	for _, viewer := range viewers {
+		viewer.ViewGameRules(map[string]any{r.Name(): v})
+	}
+	return nil
+}
+
+// loadGameRules sets the values of the game rules in the Settings of the World to those loaded from the Provider
+// passed. Values of unknown game rules or of the wrong type are ignored.
+func (w *World) loadGameRules(p Provider) {
+	values := p.GameRules()
+	set := w.settings()
+	set.Lock()
+	defer set.Unlock()
+	for name, v := range values {
+		r, ok := GameRuleByName(name)
+		if !ok || !validGameRuleValue(r, v) || r == GameRuleDoDaylightCycle || r == GameRuleDoWeatherCycle {
+			// The values of the day light and weather cycle rules are loaded as part of the Settings.
+			continue
+		}
+		set.setGameRule(r, v)
+	}
+}
+
+// saveGameRules saves the values of all game rules in the Settings of the World using the Provider passed.
+func (w *World) saveGameRules(p Provider) {
+	set := w.settings()
+	set.Lock()
+	values := set.gameRuleValues()
+	set.Unlock()
+	p.SaveGameRules(values)
+}
@@ -39,6 +39,22 @@ type Handler interface {
 	HandleEntitySpawn(e Entity)
 	// HandleEntityDespawn handles an entity being despawned from a World through a call to World.RemoveEntity.
 	HandleEntityDespawn(e Entity)
+	// HandleEntityHurt handles an Entity in the World being hurt by a DamageSource. It is called when players and
+	// mobs are hurt, after the handler of the entity itself (if any) has handled the damage. Other entities that are
+	// able to take damage should call it on World.Events from their Hurt method as well.
+	// ctx.Cancel() may be called to cancel the damage being dealt to the entity. The damage dealt may be changed
+	// by assigning to *damage.
+	HandleEntityHurt(ctx *event.Context, e Entity, damage *float64, src DamageSource)
+	// HandleProjectileHit handles a projectile Entity hitting a target at a position. If an Entity was hit, target is
+	// that Entity. If a block was hit, target is nil and blockPos holds the position of the block.
+	// ctx.Cancel() may be called to prevent the projectile from affecting the target. The projectile is still removed
+	// from the World, unless it survives block collisions and hit a block.
+	HandleProjectileHit(ctx *event.Context, projectile Entity, pos mgl64.Vec3, target Entity, blockPos cube.Pos)
+	// HandleExplosion handles an explosion at a position in the World. The entities within range of the explosion and the
+	// positions of all blocks that will be destroyed by it are passed and may be changed by assigning to *entities and
+	// *blocks. itemDropChance is the chance, from 0 to 1, that a destroyed block drops its items, and spawnFire
+	// decides if the explosion randomly starts fires. ctx.Cancel() may be called to cancel the explosion entirely.
+	HandleExplosion(ctx *event.Context, position mgl64.Vec3, entities *[]Entity, blocks *[]cube.Pos, itemDropChance *float64, spawnFire *bool)
 	// HandleClose handles the World being closed. HandleClose may be used as a moment to finish code running on other
 	// goroutines that operates on the World specifically. HandleClose is called directly before the World stops
 	// ticking and before any chunks are saved to disk.
@@ -48,17 +64,20 @@ type Handler interface {
 // Compile time check to make sure NopHandler implements Handler.
 var _ Handler = (*NopHandler)(nil)
 
-// NopHandler implements the Handler interface but does not execute any code when an event is called. The
-// default Handler of worlds is set to NopHandler.
+// NopHandler implements the Handler interface but does not execute any code when an event is called.
 // Users may embed NopHandler to avoid having to implement each method.
 type NopHandler struct{}
 
-func (NopHandler) HandleLiquidFlow(*event.Context, cube.Pos, cube.Pos, Liquid, Block) {}
-func (NopHandler) HandleLiquidDecay(*event.Context, cube.Pos, Liquid, Liquid)         {}
-func (NopHandler) HandleLiquidHarden(*event.Context, cube.Pos, Block, Block, Block)   {}
-func (NopHandler) HandleSound(*event.Context, Sound, mgl64.Vec3)                      {}
-func (NopHandler) HandleFireSpread(*event.Context, cube.Pos, cube.Pos)                {}
-func (NopHandler) HandleBlockBurn(*event.Context, cube.Pos)                           {}
-func (NopHandler) HandleEntitySpawn(Entity)                                           {}
-func (NopHandler) HandleEntityDespawn(Entity)                                         {}
-func (NopHandler) HandleClose()                                                       {}
+func (NopHandler) HandleLiquidFlow(*event.Context, cube.Pos, cube.Pos, Liquid, Block)       {}
+func (NopHandler) HandleLiquidDecay(*event.Context, cube.Pos, Liquid, Liquid)               {}
+func (NopHandler) HandleLiquidHarden(*event.Context, cube.Pos, Block, Block, Block)         {}
+func (NopHandler) HandleSound(*event.Context, Sound, mgl64.Vec3)                            {}
+func (NopHandler) HandleFireSpread(*event.Context, cube.Pos, cube.Pos)                      {}
+func (NopHandler) HandleBlockBurn(*event.Context, cube.Pos)                                 {}
+func (NopHandler) HandleEntitySpawn(Entity)                                                 {}
+func (NopHandler) HandleEntityDespawn(Entity)                                               {}
+func (NopHandler) HandleEntityHurt(*event.Context, Entity, *float64, DamageSource)          {}
+func (NopHandler) HandleProjectileHit(*event.Context, Entity, mgl64.Vec3, Entity, cube.Pos) {}
+func (NopHandler) HandleExplosion(*event.Context, mgl64.Vec3, *[]Entity, *[]cube.Pos, *float64, *bool) {
+}
+func (NopHandler) HandleClose() {}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/df-mc/dragonfly/server/item/category"
 	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"golang.org/x/exp/slices"
 	"image"
 )
 
@@ -17,7 +18,9 @@ type Item interface {
 }
 
 // CustomItem represents an item that is non-vanilla and requires a resource pack and extra steps to show it
-// to the client.
+// to the client. Custom items must be registered using RegisterItem before the server is created. The components
+// sent to the client, such as the maximum count, durability and food properties, are derived from the interfaces
+// of the item package that the CustomItem implements.
 type CustomItem interface {
 	Item
 	// Name is the name that will be displayed on the item to all clients.
@@ -38,10 +41,16 @@ func RegisterItem(item Item) {
 	if _, ok := items[h]; ok {
 		panic(fmt.Sprintf("item registered with name %v and meta %v already exists", name, meta))
 	}
-	if c, ok := item.(CustomItem); ok {
-		nextRID := int32(len(itemNamesToRuntimeIDs))
-		itemRuntimeIDsToNames[nextRID] = name
-		itemNamesToRuntimeIDs[name] = nextRID
+	if c, ok := item.(CustomItem); ok && !slices.Contains(customBlockNames, name) {
+		if blockRegistryFinalised {
+			panic(fmt.Sprintf("custom item %v must be registered before the server is created", name))
+		}
+		if _, ok := itemNamesToRuntimeIDs[name]; ok {
+			panic(fmt.Sprintf("custom item %v has the same name as an existing item", name))
+		}
+		itemRuntimeIDsToNames[nextCustomItemRID] = name
+		itemNamesToRuntimeIDs[name] = nextCustomItemRID
+		nextCustomItemRID++
 
 		customItems = append(customItems, c)
 	}
@@ -69,6 +78,9 @@ var (
 	itemRuntimeIDsToNames = map[int32]string{}
 	// itemNamesToRuntimeIDs holds a map to translate item string IDs to runtime IDs.
 	itemNamesToRuntimeIDs = map[string]int32{}
+	// nextCustomItemRID is the runtime ID assigned to the next custom item registered. It is one higher than the
+	// highest runtime ID of vanilla items, so that the runtime IDs of custom items never overlap with those.
+	nextCustomItemRID int32
 )
 
 // init reads all item entries from the resource JSON, and sets the according values in the runtime ID maps.
@@ -81,11 +93,25 @@ func init() {
 	for name, rid := range m {
 		itemNamesToRuntimeIDs[name] = rid
 		itemRuntimeIDsToNames[rid] = name
+		if rid >= nextCustomItemRID {
+			nextCustomItemRID = rid + 1
+		}
 	}
 }
 
-// ItemByName attempts to return an item by a name and a metadata value.
+// ItemByName attempts to return an item by a name and a metadata value. If no item with the name exists, the name
+// is resolved as an alias registered using RegisterItemAlias, a name without namespace or a name used by an older
+// version of the game.
 func ItemByName(name string, meta int16) (Item, bool) {
+	it, ok := itemByName(name, meta)
+	if !ok {
+		it, ok = itemByName(resolveItemAlias(name, meta))
+	}
+	return it, ok
+}
+
+// itemByName attempts to return an item by a name and a metadata value, without resolving aliases.
+func itemByName(name string, meta int16) (Item, bool) {
 	it, ok := items[itemHash{name: name, meta: meta}]
 	if !ok {
 		// Also try obtaining the item with a metadata value of 0, for cases with durability.
@@ -0,0 +1,104 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+)
+
+// LightEmission returns the light level, from 0-15, that the Block passed emits, as used when calculating the light
+// of the World. Blocks that do not implement block.LightEmitter emit no light.
+func LightEmission(b Block) uint8 {
+	return chunk.LightBlocks[BlockRuntimeID(b)]
+}
+
+// LightDiffusion returns the amount of light levels, from 0-15, that the Block passed subtracts from light passing
+// through it, as used when calculating the light of the World. Blocks that do not implement block.LightDiffuser
+// block all light and have a diffusion level of 15.
+func LightDiffusion(b Block) uint8 {
+	return chunk.FilteringBlocks[BlockRuntimeID(b)]
+}
+
+// Blocks reads the blocks at the positions passed and returns them in the same order. Blocks is more efficient than
+// calling World.Block for every position, as positions in the same chunk that directly follow each other share a
+// single lookup of the chunk. Positions should therefore be grouped by chunk where possible.
+func (w *World) Blocks(positions []cube.Pos) []Block {
+	blocks := make([]Block, len(positions))
+	for i := range blocks {
+		blocks[i] = air()
+	}
+	w.batch(positions, func(i int, pos cube.Pos, c *Column) {
+		rid := c.Block(uint8(pos[0]), int16(pos[1]), uint8(pos[2]), 0)
+		if nbtBlocks[rid] {
+			if nbtB, ok := c.BlockEntities[pos]; ok {
+				blocks[i] = nbtB
+				return
+			}
+		}
+		blocks[i], _ = BlockByRuntimeID(rid)
+	})
+	return blocks
+}
+
+// Lights returns the light levels at the positions passed in the same order, as World.Light would return for each
+// of them. Like Blocks, positions in the same chunk that directly follow each other share a single lookup of the
+// chunk.
+func (w *World) Lights(positions []cube.Pos) []uint8 {
+	levels := w.aboveRangeLight(positions)
+	w.batch(positions, func(i int, pos cube.Pos, c *Column) {
+		levels[i] = c.Light(uint8(pos[0]), int16(pos[1]), uint8(pos[2]))
+	})
+	return levels
+}
+
+// SkyLights returns the skylight levels at the positions passed in the same order, as World.SkyLight would return
+// for each of them. Like Blocks, positions in the same chunk that directly follow each other share a single lookup
+// of the chunk.
+func (w *World) SkyLights(positions []cube.Pos) []uint8 {
+	levels := w.aboveRangeLight(positions)
+	w.batch(positions, func(i int, pos cube.Pos, c *Column) {
+		levels[i] = c.SkyLight(uint8(pos[0]), int16(pos[1]), uint8(pos[2]))
+	})
+	return levels
+}
+
+// aboveRangeLight returns a slice of light levels for the positions passed, with a level of 15 for positions above
+// the range of the World, which always receive full skylight.
+func (w *World) aboveRangeLight(positions []cube.Pos) []uint8 {
+	levels := make([]uint8, len(positions))
+	if w == nil {
+		return levels
+	}
+	for i, pos := range positions {
+		if pos[1] > w.Range()[1] {
+			levels[i] = 15
+		}
+	}
+	return levels
+}
+
+// batch calls f for every position passed that is within the range of the World, along with its index and the
+// locked Column holding it. The Column is only looked up again once a position in a different chunk is reached.
+func (w *World) batch(positions []cube.Pos, f func(i int, pos cube.Pos, c *Column)) {
+	if w == nil {
+		return
+	}
+	var (
+		c       *Column
+		current ChunkPos
+	)
+	for i, pos := range positions {
+		if pos.OutOfBounds(w.Range()) {
+			continue
+		}
+		if chunkPos := chunkPosFromBlockPos(pos); c == nil || chunkPos != current {
+			if c != nil {
+				c.Unlock()
+			}
+			c, current = w.chunk(chunkPos), chunkPos
+		}
+		f(i, pos, c)
+	}
+	if c != nil {
+		c.Unlock()
+	}
+}
@@ -3,6 +3,7 @@ package world
 import (
 	"github.com/go-gl/mathgl/mgl64"
 	"math"
+	"sort"
 	"sync"
 )
 
@@ -14,8 +15,12 @@ type Loader struct {
 	w      *World
 	viewer Viewer
 
-	mu        sync.RWMutex
-	pos       ChunkPos
+	mu  sync.RWMutex
+	pos ChunkPos
+	// vec is the exact position that the Loader was last moved to. moved is false if the Loader was never moved, in
+	// which case vec is not a position in the World yet.
+	vec       mgl64.Vec3
+	moved     bool
 	loadQueue []ChunkPos
 	loaded    map[ChunkPos]*Column
 
@@ -64,6 +69,7 @@ func (l *Loader) Move(pos mgl64.Vec3) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	l.vec, l.moved = pos, true
 	chunkPos := chunkPosFromVec3(pos)
 	if chunkPos == l.pos {
 		return
@@ -75,7 +81,8 @@ func (l *Loader) Move(pos mgl64.Vec3) {
 
 // Load loads n chunks around the centre of the chunk, starting with the middle and working outwards. For
 // every chunk loaded, the Viewer passed through construction in New has its ViewChunk method called.
-// Load does nothing for n <= 0.
+// Load does nothing for n <= 0. Load may load fewer than n chunks if the World has used up its Config.TickBudget
+// for the current tick. The remaining chunks are loaded during later calls to Load.
 func (l *Loader) Load(n int) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -89,7 +96,10 @@ func (l *Loader) Load(n int) {
 		}
 
 		pos := l.loadQueue[0]
-		c := l.w.chunk(pos)
+		c, ok := l.w.chunkWithinBudget(pos)
+		if !ok {
+			break
+		}
 
 		l.viewer.ViewChunk(pos, c.Chunk, c.BlockEntities)
 		l.w.addViewer(c, l)
@@ -154,47 +164,56 @@ func (l *Loader) world(new *World) {
 // and should therefore be removed.
 func (l *Loader) evictUnused() {
 	for pos := range l.loaded {
-		diffX, diffZ := pos[0]-l.pos[0], pos[1]-l.pos[1]
-		dist := math.Sqrt(float64(diffX*diffX) + float64(diffZ*diffZ))
-		if int(dist) > l.r {
+		if !l.withinRadius(pos) {
 			delete(l.loaded, pos)
 			l.w.removeViewer(pos, l)
 		}
 	}
 }
 
+// withinRadius checks if the ChunkPos passed is within the chunk radius of the loader.
+func (l *Loader) withinRadius(pos ChunkPos) bool {
+	diffX, diffZ := pos[0]-l.pos[0], pos[1]-l.pos[1]
+	return int(math.Sqrt(float64(diffX*diffX)+float64(diffZ*diffZ))) <= l.r
+}
+
 // populateLoadQueue populates the load queue of the loader. This method is called once to create the order in
-// which chunks around the position the loader is now in should be loaded. Chunks are ordered to be loaded
-// from the middle outwards.
+// which chunks around the position the loader is now in should be loaded. Chunks are ordered in square rings
+// around the chunk that the loader is in, and the chunks within each ring are ordered by their distance to that
+// chunk, so that the chunks closest to the loader are loaded first.
 func (l *Loader) populateLoadQueue() {
-	// We'll first load the chunk positions to load in a map indexed by the distance to the center (basically,
-	// what precedence it should have), and put them in the loadQueue in that order.
-	queue := map[int32][]ChunkPos{}
-
-	r := int32(l.r)
-	for x := -r; x <= r; x++ {
-		for z := -r; z <= r; z++ {
-			distance := math.Sqrt(float64(x*x) + float64(z*z))
-			chunkDistance := int32(math.Round(distance))
-			if chunkDistance > r {
-				// The chunk was outside the chunk radius.
-				continue
-			}
-			pos := ChunkPos{x + l.pos[0], z + l.pos[1]}
-			if _, ok := l.loaded[pos]; ok {
-				// The chunk was already loaded, so we don't need to do anything.
-				continue
-			}
-			if m, ok := queue[chunkDistance]; ok {
-				queue[chunkDistance] = append(m, pos)
-				continue
+	l.loadQueue = l.loadQueue[:0]
+	l.enqueue(l.pos)
+
+	// Each ring is walked starting at its corner with the lowest X and Z, moving along its four edges.
+	directions := [4][2]int32{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+	for ring := int32(1); ring <= int32(l.r); ring++ {
+		start := len(l.loadQueue)
+		x, z := -ring, -ring
+		for _, d := range directions {
+			for i := int32(0); i < ring*2; i++ {
+				l.enqueue(ChunkPos{l.pos[0] + x, l.pos[1] + z})
+				x, z = x+d[0], z+d[1]
 			}
-			queue[chunkDistance] = []ChunkPos{pos}
 		}
+		queued := l.loadQueue[start:]
+		sort.SliceStable(queued, func(i, j int) bool {
+			return l.distanceSquared(queued[i]) < l.distanceSquared(queued[j])
+		})
 	}
+}
 
-	l.loadQueue = l.loadQueue[:0]
-	for i := int32(0); i < r; i++ {
-		l.loadQueue = append(l.loadQueue, queue[i]...)
+// distanceSquared returns the squared distance between the ChunkPos passed and the chunk that the loader is in.
+func (l *Loader) distanceSquared(pos ChunkPos) int32 {
+	diffX, diffZ := pos[0]-l.pos[0], pos[1]-l.pos[1]
+	return diffX*diffX + diffZ*diffZ
+}
+
+// enqueue adds the ChunkPos passed to the load queue if it is within the chunk radius of the loader and not yet
+// loaded.
+func (l *Loader) enqueue(pos ChunkPos) {
+	if _, ok := l.loaded[pos]; ok || !l.withinRadius(pos) {
+		return
 	}
+	l.loadQueue = append(l.loadQueue, pos)
 }
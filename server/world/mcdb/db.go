@@ -47,6 +47,16 @@ func (db *DB) SaveSettings(s *world.Settings) {
 	db.ldat.PutSettings(s)
 }
 
+// GameRules returns the values of the game rules stored in the level.dat.
+func (db *DB) GameRules() map[string]any {
+	return db.ldat.GameRules()
+}
+
+// SaveGameRules saves the values of the game rules passed to the level.dat.
+func (db *DB) SaveGameRules(rules map[string]any) {
+	db.ldat.PutGameRules(rules)
+}
+
 // playerData holds the fields that indicate where player data is stored for a player with a specific UUID.
 type playerData struct {
 	UUID         string `nbt:"MsaId"`
@@ -132,7 +142,8 @@ func (db *DB) SavePlayerSpawnPosition(id uuid.UUID, pos cube.Pos) error {
 
 // LoadColumn reads a world.Column from the DB at a position and dimension in
 // the DB. If no column at that position exists, errors.Is(err,
-// leveldb.ErrNotFound) equals true.
+// leveldb.ErrNotFound) equals true. If the data of the column is malformed,
+// the error returned is a *world.CorruptColumnError.
 func (db *DB) LoadColumn(pos world.ChunkPos, dim world.Dimension) (*world.Column, error) {
 	k := dbKey{pos: pos, dim: dim}
 	col, err := db.column(k)
@@ -156,9 +167,10 @@ func (db *DB) column(k dbKey) (*world.Column, error) {
 		db.conf.Log.Debugf("column %v (%v): unsupported chunk version %v, trying to load anyway", k.pos, k.dim, ver)
 	}
 	cdata.Biomes, err = db.biomes(k)
-	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+	legacyBiomes := errors.Is(err, leveldb.ErrNotFound)
+	if err != nil && !legacyBiomes {
 		// Some chunks still use 2D chunk data and might not have this field, in
-		// which case we can just move on.
+		// which case the 2D biomes are read after decoding the chunk.
 		return nil, fmt.Errorf("read biomes: %w", err)
 	}
 	cdata.SubChunks, err = db.subChunks(k)
@@ -167,7 +179,12 @@ func (db *DB) column(k dbKey) (*world.Column, error) {
 	}
 	col.Chunk, err = chunk.DiskDecode(cdata, k.dim.Range())
 	if err != nil {
-		return nil, fmt.Errorf("decode chunk data: %w", err)
+		return nil, k.corrupt(fmt.Errorf("decode chunk data: %w", err))
+	}
+	if legacyBiomes {
+		if err := db.biomes2D(k, col.Chunk); err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+			return nil, fmt.Errorf("read 2D biomes: %w", err)
+		}
 	}
 	col.Entities, err = db.entities(k)
 	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
@@ -182,6 +199,12 @@ func (db *DB) column(k dbKey) (*world.Column, error) {
 	return col, nil
 }
 
+// corrupt wraps the error passed, which occurred while decoding the data of
+// the column at the key, into a *world.CorruptColumnError.
+func (k dbKey) corrupt(err error) error {
+	return &world.CorruptColumnError{Pos: k.pos, Dim: k.dim, Err: err}
+}
+
 func (db *DB) version(k dbKey) (byte, error) {
 	p, err := db.ldb.Get(k.Sum(keyVersion), nil)
 	switch err {
@@ -196,7 +219,7 @@ func (db *DB) version(k dbKey) (byte, error) {
 		fallthrough
 	case nil:
 		if n := len(p); n != 1 {
-			return 0, fmt.Errorf("expected 1 version byte, found %v", n)
+			return 0, k.corrupt(fmt.Errorf("expected 1 version byte, found %v", n))
 		}
 		return p[0], nil
 	}
@@ -210,11 +233,29 @@ func (db *DB) biomes(k dbKey) ([]byte, error) {
 	// The first 512 bytes is a heightmap (16*16 int16s), the biomes follow. We
 	// calculate a heightmap on startup so the heightmap is discarded.
 	if n := len(biomes); n <= 512 {
-		return nil, fmt.Errorf("expected at least 513 bytes for 3D data, got %v", n)
+		return nil, k.corrupt(fmt.Errorf("expected at least 513 bytes for 3D data, got %v", n))
 	}
 	return biomes[512:], nil
 }
 
+// biomes2D reads the biomes of a chunk saved before the game stored biomes in
+// three dimensions and sets them for every Y of the chunk passed.
+func (db *DB) biomes2D(k dbKey, c *chunk.Chunk) error {
+	data, err := db.ldb.Get(k.Sum(key2DData), nil)
+	if err != nil {
+		return err
+	}
+	// Like the 3D data, the first 512 bytes is a heightmap. It is followed by
+	// one biome ID byte for every column, indexed by (z << 4) | x.
+	if n := len(data); n != 768 {
+		return k.corrupt(fmt.Errorf("expected 768 bytes for 2D data, got %v", n))
+	}
+	for i, b := range data[512:] {
+		c.SetBiomeColumn(uint8(i&15), uint8(i>>4), uint32(b))
+	}
+	return nil
+}
+
 func (db *DB) subChunks(k dbKey) ([][]byte, error) {
 	r := k.dim.Range()
 	sub := make([][]byte, (r.Height()>>4)+1)
@@ -247,8 +288,11 @@ func (db *DB) entities(k dbKey) ([]world.Entity, error) {
 	var m map[string]any
 	for buf.Len() != 0 {
 		maps.Clear(m)
+		if _, err := chunk.CheckNBT(buf.Bytes()); err != nil {
+			return nil, k.corrupt(fmt.Errorf("decode nbt: %w", err))
+		}
 		if err := dec.Decode(&m); err != nil {
-			return nil, fmt.Errorf("decode nbt: %w", err)
+			return nil, k.corrupt(fmt.Errorf("decode nbt: %w", err))
 		}
 		id, ok := m["identifier"]
 		if !ok {
@@ -263,6 +307,7 @@ func (db *DB) entities(k dbKey) ([]world.Entity, error) {
 		}
 		if s, ok := t.(world.SaveableEntityType); ok {
 			if v := s.DecodeNBT(m); v != nil {
+				world.DecodeEntityMetadata(v, m)
 				entities = append(entities, v)
 			}
 		}
@@ -284,10 +329,17 @@ func (db *DB) blockEntities(k dbKey, c *chunk.Chunk) (map[cube.Pos]world.Block,
 	var m map[string]any
 	for buf.Len() != 0 {
 		maps.Clear(m)
+		if _, err := chunk.CheckNBT(buf.Bytes()); err != nil {
+			return blockEntities, k.corrupt(fmt.Errorf("decode nbt: %w", err))
+		}
 		if err := dec.Decode(&m); err != nil {
-			return blockEntities, fmt.Errorf("decode nbt: %w", err)
+			return blockEntities, k.corrupt(fmt.Errorf("decode nbt: %w", err))
 		}
 		pos := blockPosFromNBT(m)
+		if (world.ChunkPos{int32(pos[0] >> 4), int32(pos[2] >> 4)}) != k.pos || pos.OutOfBounds(k.dim.Range()) {
+			db.conf.Log.Errorf("block entity at %v is outside of column %v (%v)", pos, k.pos, k.dim)
+			continue
+		}
 
 		id := c.Block(uint8(pos[0]), int16(pos[1]), uint8(pos[2]), 0)
 		b, ok := world.BlockByRuntimeID(id)
@@ -367,6 +419,7 @@ func (db *DB) storeEntities(batch *leveldb.Batch, k dbKey, entities []world.Enti
 		}
 		x := t.EncodeNBT(e)
 		x["identifier"] = t.EncodeEntity()
+		world.EncodeEntityMetadata(e, x)
 		if err := enc.Encode(x); err != nil {
 			db.conf.Log.Errorf("store entities: error encoding NBT: %w", err)
 		}
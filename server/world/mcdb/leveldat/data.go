@@ -257,3 +257,48 @@ func (d *Data) PutSettings(s *world.Settings) {
 	difficulty, _ := world.DifficultyID(s.Difficulty)
 	d.Difficulty = int32(difficulty)
 }
+
+// GameRules returns the values of the game rules stored in d by their names.
+func (d *Data) GameRules() map[string]any {
+	rules := make(map[string]any, len(d.boolGameRules())+1)
+	for name, v := range d.boolGameRules() {
+		rules[name] = *v
+	}
+	rules["randomtickspeed"] = int(d.RandomTickSpeed)
+	return rules
+}
+
+// PutGameRules updates d with the values of the game rules passed by their names. Game rules that are not stored in
+// d are ignored.
+func (d *Data) PutGameRules(rules map[string]any) {
+	fields := d.boolGameRules()
+	for name, v := range rules {
+		switch v := v.(type) {
+		case bool:
+			if field, ok := fields[name]; ok {
+				*field = v
+			}
+		case int:
+			if name == "randomtickspeed" {
+				d.RandomTickSpeed = int32(v)
+			}
+		}
+	}
+}
+
+// boolGameRules returns pointers to the fields of d holding the values of bool game rules, by the names of the game
+// rules.
+func (d *Data) boolGameRules() map[string]*bool {
+	return map[string]*bool{
+		"dodaylightcycle":    &d.DoDayLightCycle,
+		"doweathercycle":     &d.DoWeatherCycle,
+		"dofiretick":         &d.DoFireTick,
+		"domobspawning":      &d.DoMobSpawning,
+		"doimmediaterespawn": &d.DoImmediateRespawn,
+		"falldamage":         &d.FallDamage,
+		"keepinventory":      &d.KeepInventory,
+		"mobgriefing":        &d.MobGriefing,
+		"showcoordinates":    &d.ShowCoordinates,
+		"showdeathmessages":  &d.ShowDeathMessages,
+	}
+}
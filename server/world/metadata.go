@@ -0,0 +1,150 @@
+package world
+
+import (
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"golang.org/x/exp/maps"
+	"sort"
+	"sync"
+)
+
+// Metadata is a key/value store that plugins may use to attach values to an entity or player, without having to
+// keep maps keyed by the entity or its UUID themselves. Values are either transient, meaning they are lost when the
+// entity is unloaded or the player leaves the server, or persistent, meaning they are saved with the entity or the
+// player data and are available again once it is loaded. A zero Metadata is empty and ready for use. Metadata is
+// safe for concurrent use.
+type Metadata struct {
+	mu     sync.Mutex
+	values map[string]metadataValue
+}
+
+// metadataValue is a value stored in Metadata, together with whether it is persistent.
+type metadataValue struct {
+	v          any
+	persistent bool
+}
+
+// MetadataHolder represents an Entity that holds Metadata. All entities in the entity package, as well as players,
+// implement MetadataHolder.
+type MetadataHolder interface {
+	Entity
+	// Metadata returns the Metadata of the entity.
+	Metadata() *Metadata
+}
+
+// Set stores a transient value under the key passed, replacing any value previously stored under it. Transient
+// values are not saved and are lost when the entity is unloaded or the player leaves the server.
+func (m *Metadata) Set(key string, v any) {
+	m.set(key, metadataValue{v: v})
+}
+
+// SetPersistent stores a persistent value under the key passed, replacing any value previously stored under it.
+// Persistent values are saved with the entity or player data, so the value must be encodable to NBT: Supported are
+// bool, uint8, int16, int32, int64, float32, float64, string, slices and arrays of these types and map[string]any
+// and structs holding them. Note that int and uint values are not supported. An error is returned if the value
+// cannot be encoded, in which case nothing is stored.
+func (m *Metadata) SetPersistent(key string, v any) error {
+	if _, err := nbt.MarshalEncoding(map[string]any{key: v}, nbt.LittleEndian); err != nil {
+		return fmt.Errorf("set persistent metadata %v: %w", key, err)
+	}
+	m.set(key, metadataValue{v: v, persistent: true})
+	return nil
+}
+
+// set stores the metadataValue passed under a key.
+func (m *Metadata) set(key string, v metadataValue) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.values == nil {
+		m.values = make(map[string]metadataValue)
+	}
+	m.values[key] = v
+}
+
+// Value returns the value stored under the key passed. If no value was stored under the key, false is returned.
+// Persistent values loaded from saved data have the types that they were decoded as from NBT, which means structs
+// are returned as map[string]any and slices as []any.
+func (m *Metadata) Value(key string) (any, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.values[key]
+	return v.v, ok
+}
+
+// Persistent checks if the value stored under the key passed is persistent. If no value is stored under the key,
+// Persistent returns false.
+func (m *Metadata) Persistent(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.values[key].persistent
+}
+
+// Delete deletes the value stored under the key passed, if any.
+func (m *Metadata) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.values, key)
+}
+
+// Keys returns the keys of all values stored in the Metadata in sorted order.
+func (m *Metadata) Keys() []string {
+	m.mu.Lock()
+	keys := maps.Keys(m.values)
+	m.mu.Unlock()
+
+	sort.Strings(keys)
+	return keys
+}
+
+// EncodeNBT returns a map holding all persistent values of the Metadata, so that it may be saved as NBT. Transient
+// values are not included.
+func (m *Metadata) EncodeNBT() map[string]any {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data := make(map[string]any)
+	for key, v := range m.values {
+		if v.persistent {
+			data[key] = v.v
+		}
+	}
+	return data
+}
+
+// DecodeNBT stores all values in the map passed as persistent values, such as those previously returned by
+// EncodeNBT. Values stored under the same keys are replaced.
+func (m *Metadata) DecodeNBT(data map[string]any) {
+	for key, v := range data {
+		m.set(key, metadataValue{v: v, persistent: true})
+	}
+}
+
+// MetadataValue returns the value of type T stored under the key passed in the Metadata. If no value was stored
+// under the key or if the value stored is not of type T, false is returned.
+func MetadataValue[T any](m *Metadata, key string) (T, bool) {
+	v, _ := m.Value(key)
+	t, ok := v.(T)
+	return t, ok
+}
+
+// entityMetadataKey is the key in the NBT data of an entity under which its persistent Metadata is saved.
+const entityMetadataKey = "dragonflyMetadata"
+
+// EncodeEntityMetadata adds the persistent Metadata of the Entity passed to its NBT data m, if the Entity is a
+// MetadataHolder. EncodeEntityMetadata is used by Providers when saving entities.
+func EncodeEntityMetadata(e Entity, m map[string]any) {
+	if h, ok := e.(MetadataHolder); ok {
+		if data := h.Metadata().EncodeNBT(); len(data) != 0 {
+			m[entityMetadataKey] = data
+		}
+	}
+}
+
+// DecodeEntityMetadata loads the persistent Metadata saved in the NBT data m of the Entity passed, if the Entity is
+// a MetadataHolder. DecodeEntityMetadata is used by Providers when loading entities.
+func DecodeEntityMetadata(e Entity, m map[string]any) {
+	if h, ok := e.(MetadataHolder); ok {
+		if data, ok := m[entityMetadataKey].(map[string]any); ok {
+			h.Metadata().DecodeNBT(data)
+		}
+	}
+}
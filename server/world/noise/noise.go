@@ -0,0 +1,88 @@
+// Package noise implements seeded noise functions, such as Perlin and simplex noise, and deterministic random
+// sources keyed by a position and salt, for use by world generators and decorators. All functions in this package
+// produce the same values for the same seeds on every platform, so that worlds generated using them are
+// reproducible.
+package noise
+
+import (
+	"math/rand"
+)
+
+// Source is a source of coherent noise in two and three dimensions. Values returned are roughly in the range
+// [-1, 1].
+type Source interface {
+	// Noise2D returns the noise value at the X and Z coordinates passed.
+	Noise2D(x, z float64) float64
+	// Noise3D returns the noise value at the X, Y and Z coordinates passed.
+	Noise3D(x, y, z float64) float64
+}
+
+// NewSource returns a rand.Source64 seeded with the seed passed. Unlike the rand.Source returned by rand.NewSource,
+// it is cheap to create, which makes it suitable to be created for every chunk or position.
+func NewSource(seed int64) rand.Source64 {
+	return &splitMix{state: uint64(seed)}
+}
+
+// PositionalSource returns a rand.Source64 seeded using the seed of a world, the position and the salt passed. The
+// salt is typically unique for every feature placed by a generator, so that features at the same position do not
+// use the same random numbers. Sources with the same seed, position and salt always produce the same values.
+func PositionalSource(seed int64, x, y, z int, salt int64) rand.Source64 {
+	return NewSource(int64(Hash(seed, x, y, z, salt)))
+}
+
+// Hash returns a well distributed hash of the seed, position and salt passed.
+func Hash(seed int64, x, y, z int, salt int64) uint64 {
+	h := mix(uint64(seed) ^ uint64(salt)*0x9e3779b97f4a7c15)
+	h = mix(h ^ uint64(x)*0xbf58476d1ce4e5b9)
+	h = mix(h ^ uint64(y)*0x94d049bb133111eb)
+	return mix(h ^ uint64(z)*0x9e3779b97f4a7c15)
+}
+
+// splitMix is a rand.Source64 implementing the SplitMix64 algorithm.
+type splitMix struct {
+	state uint64
+}
+
+// Uint64 ...
+func (s *splitMix) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	return mix(s.state)
+}
+
+// Int63 ...
+func (s *splitMix) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// Seed ...
+func (s *splitMix) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+// mix is the finaliser of SplitMix64, which scrambles the bits of the value passed.
+func mix(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// permutation returns a permutation table of 512 entries, holding the numbers 0-255 shuffled using the seed passed,
+// repeated twice so that indices do not need to be wrapped.
+func permutation(seed int64) [512]int {
+	var p [512]int
+	perm := rand.New(NewSource(seed)).Perm(256)
+	for i := range p {
+		p[i] = perm[i&255]
+	}
+	return p
+}
+
+// fade is the quintic fade curve used to smooth the interpolation between gradients.
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// lerp linearly interpolates between a and b by t.
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
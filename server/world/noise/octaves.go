@@ -0,0 +1,56 @@
+package noise
+
+import (
+	"math/rand"
+)
+
+// Octaves is a Source that combines multiple octaves of another Source, each with a higher frequency and a lower
+// amplitude than the previous, producing noise with both large and small features. It may be created using
+// NewOctaves.
+type Octaves struct {
+	sources                 []Source
+	persistence, lacunarity float64
+}
+
+// NewOctaves creates Octaves combining the amount of octaves passed. Every octave is a Source created by calling
+// newSource with a seed derived from the seed passed, such as NewPerlin or NewSimplex. The amplitude of every octave
+// is persistence times that of the previous octave, and its frequency is lacunarity times that of the previous
+// octave. Common values are 0.5 for persistence and 2 for lacunarity.
+func NewOctaves[S Source](seed int64, octaves int, persistence, lacunarity float64, newSource func(seed int64) S) *Octaves {
+	if octaves < 1 {
+		octaves = 1
+	}
+	r := rand.New(NewSource(seed))
+	o := &Octaves{sources: make([]Source, octaves), persistence: persistence, lacunarity: lacunarity}
+	for i := range o.sources {
+		o.sources[i] = newSource(r.Int63())
+	}
+	return o
+}
+
+// Noise2D ...
+func (o *Octaves) Noise2D(x, z float64) float64 {
+	return o.combine(func(src Source, freq float64) float64 {
+		return src.Noise2D(x*freq, z*freq)
+	})
+}
+
+// Noise3D ...
+func (o *Octaves) Noise3D(x, y, z float64) float64 {
+	return o.combine(func(src Source, freq float64) float64 {
+		return src.Noise3D(x*freq, y*freq, z*freq)
+	})
+}
+
+// combine sums the values returned by f for every octave, weighted by their amplitude, and normalises the result
+// so that it is in the same range as the values of a single octave.
+func (o *Octaves) combine(f func(src Source, freq float64) float64) float64 {
+	sum, max, amp, freq := 0.0, 0.0, 1.0, 1.0
+	for _, src := range o.sources {
+		sum += f(src, freq) * amp
+		max += amp
+		amp *= o.persistence
+		freq *= o.lacunarity
+	}
+	return sum / max
+}
@@ -0,0 +1,64 @@
+package noise
+
+import (
+	"math"
+)
+
+// Perlin is a Source producing improved Perlin noise. It may be created using NewPerlin.
+type Perlin struct {
+	p [512]int
+}
+
+// NewPerlin creates a Perlin noise Source using the seed passed.
+func NewPerlin(seed int64) *Perlin {
+	return &Perlin{p: permutation(seed)}
+}
+
+// Noise2D ...
+func (n *Perlin) Noise2D(x, z float64) float64 {
+	return n.Noise3D(x, 0, z)
+}
+
+// Noise3D ...
+func (n *Perlin) Noise3D(x, y, z float64) float64 {
+	fx, fy, fz := math.Floor(x), math.Floor(y), math.Floor(z)
+	xi, yi, zi := int(fx)&255, int(fy)&255, int(fz)&255
+	x, y, z = x-fx, y-fy, z-fz
+	u, v, w := fade(x), fade(y), fade(z)
+
+	p := &n.p
+	a, b := p[xi]+yi, p[xi+1]+yi
+	aa, ab, ba, bb := p[a]+zi, p[a+1]+zi, p[b]+zi, p[b+1]+zi
+
+	return lerp(w,
+		lerp(v,
+			lerp(u, grad(p[aa], x, y, z), grad(p[ba], x-1, y, z)),
+			lerp(u, grad(p[ab], x, y-1, z), grad(p[bb], x-1, y-1, z)),
+		),
+		lerp(v,
+			lerp(u, grad(p[aa+1], x, y, z-1), grad(p[ba+1], x-1, y, z-1)),
+			lerp(u, grad(p[ab+1], x, y-1, z-1), grad(p[bb+1], x-1, y-1, z-1)),
+		),
+	)
+}
+
+// grad returns the dot product of the gradient selected by the hash passed and the vector (x, y, z).
+func grad(hash int, x, y, z float64) float64 {
+	h := hash & 15
+	u, v := y, z
+	if h < 8 {
+		u = x
+	}
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
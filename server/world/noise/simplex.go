@@ -0,0 +1,108 @@
+package noise
+
+import (
+	"math"
+)
+
+// Simplex is a Source producing simplex noise, which has fewer directional artifacts than Perlin noise and is
+// cheaper to compute in three dimensions. It may be created using NewSimplex.
+type Simplex struct {
+	p [512]int
+}
+
+// NewSimplex creates a Simplex noise Source using the seed passed.
+func NewSimplex(seed int64) *Simplex {
+	return &Simplex{p: permutation(seed)}
+}
+
+// gradients holds the gradients used by Simplex, which are the midpoints of the edges of a cube.
+var gradients = [12][3]float64{
+	{1, 1, 0}, {-1, 1, 0}, {1, -1, 0}, {-1, -1, 0},
+	{1, 0, 1}, {-1, 0, 1}, {1, 0, -1}, {-1, 0, -1},
+	{0, 1, 1}, {0, -1, 1}, {0, 1, -1}, {0, -1, -1},
+}
+
+var (
+	f2, g2 = 0.5 * (math.Sqrt(3) - 1), (3 - math.Sqrt(3)) / 6
+	f3, g3 = 1.0 / 3, 1.0 / 6
+)
+
+// Noise2D ...
+func (n *Simplex) Noise2D(x, z float64) float64 {
+	s := (x + z) * f2
+	i, j := math.Floor(x+s), math.Floor(z+s)
+	t := (i + j) * g2
+	x0, z0 := x-(i-t), z-(j-t)
+
+	i1, j1 := 0, 1
+	if x0 > z0 {
+		i1, j1 = 1, 0
+	}
+	x1, z1 := x0-float64(i1)+g2, z0-float64(j1)+g2
+	x2, z2 := x0-1+2*g2, z0-1+2*g2
+
+	p := &n.p
+	ii, jj := int(i)&255, int(j)&255
+	return 70 * (corner2D(p[ii+p[jj]]%12, x0, z0) +
+		corner2D(p[ii+i1+p[jj+j1]]%12, x1, z1) +
+		corner2D(p[ii+1+p[jj+1]]%12, x2, z2))
+}
+
+// Noise3D ...
+func (n *Simplex) Noise3D(x, y, z float64) float64 {
+	s := (x + y + z) * f3
+	i, j, k := math.Floor(x+s), math.Floor(y+s), math.Floor(z+s)
+	t := (i + j + k) * g3
+	x0, y0, z0 := x-(i-t), y-(j-t), z-(k-t)
+
+	var i1, j1, k1, i2, j2, k2 int
+	if x0 >= y0 {
+		if y0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 1, 0
+		} else if x0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 0, 1
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 1, 0, 1
+		}
+	} else {
+		if y0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 0, 1, 1
+		} else if x0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 0, 1, 1
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 1, 1, 0
+		}
+	}
+	x1, y1, z1 := x0-float64(i1)+g3, y0-float64(j1)+g3, z0-float64(k1)+g3
+	x2, y2, z2 := x0-float64(i2)+2*g3, y0-float64(j2)+2*g3, z0-float64(k2)+2*g3
+	x3, y3, z3 := x0-1+3*g3, y0-1+3*g3, z0-1+3*g3
+
+	p := &n.p
+	ii, jj, kk := int(i)&255, int(j)&255, int(k)&255
+	return 32 * (corner3D(p[ii+p[jj+p[kk]]]%12, x0, y0, z0) +
+		corner3D(p[ii+i1+p[jj+j1+p[kk+k1]]]%12, x1, y1, z1) +
+		corner3D(p[ii+i2+p[jj+j2+p[kk+k2]]]%12, x2, y2, z2) +
+		corner3D(p[ii+1+p[jj+1+p[kk+1]]]%12, x3, y3, z3))
+}
+
+// corner2D returns the contribution of a corner of a 2D simplex with the gradient passed.
+func corner2D(gradient int, x, z float64) float64 {
+	t := 0.5 - x*x - z*z
+	if t < 0 {
+		return 0
+	}
+	t *= t
+	g := gradients[gradient]
+	return t * t * (g[0]*x + g[1]*z)
+}
+
+// corner3D returns the contribution of a corner of a 3D simplex with the gradient passed.
+func corner3D(gradient int, x, y, z float64) float64 {
+	t := 0.6 - x*x - y*y - z*z
+	if t < 0 {
+		return 0
+	}
+	t *= t
+	g := gradients[gradient]
+	return t * t * (g[0]*x + g[1]*y + g[2]*z)
+}
@@ -0,0 +1,71 @@
+package noise
+
+import (
+	"math"
+)
+
+// Voronoi is a Source producing Voronoi (cellular) noise. Space is divided into cells around randomly placed
+// points, and every position takes the value of the cell that it is in. Voronoi noise is commonly used to divide
+// a world into regions, such as biomes. It may be created using NewVoronoi.
+type Voronoi struct {
+	seed int64
+}
+
+// NewVoronoi creates a Voronoi noise Source using the seed passed.
+func NewVoronoi(seed int64) Voronoi {
+	return Voronoi{seed: seed}
+}
+
+// Noise2D returns the value of the cell that the X and Z coordinates passed are in.
+func (v Voronoi) Noise2D(x, z float64) float64 {
+	value, _ := v.Cell2D(x, z)
+	return value
+}
+
+// Noise3D returns the value of the cell that the X, Y and Z coordinates passed are in.
+func (v Voronoi) Noise3D(x, y, z float64) float64 {
+	value, _ := v.Cell3D(x, y, z)
+	return value
+}
+
+// Cell2D returns the value of the cell that the X and Z coordinates passed are in, in the range [-1, 1], and the
+// distance from the coordinates to the point of that cell. Every cell has a size of roughly 1x1.
+func (v Voronoi) Cell2D(x, z float64) (value, distance float64) {
+	cx, cz := int(math.Floor(x)), int(math.Floor(z))
+	distance = math.Inf(1)
+	for dx := -1; dx <= 1; dx++ {
+		for dz := -1; dz <= 1; dz++ {
+			h := Hash(v.seed, cx+dx, 0, cz+dz, 0)
+			px, pz := float64(cx+dx)+unit(h), float64(cz+dz)+unit(h>>21)
+			if d := math.Hypot(px-x, pz-z); d < distance {
+				distance, value = d, unit(h>>42)*2-1
+			}
+		}
+	}
+	return value, distance
+}
+
+// Cell3D returns the value of the cell that the X, Y and Z coordinates passed are in, in the range [-1, 1], and the
+// distance from the coordinates to the point of that cell. Every cell has a size of roughly 1x1x1.
+func (v Voronoi) Cell3D(x, y, z float64) (value, distance float64) {
+	cx, cy, cz := int(math.Floor(x)), int(math.Floor(y)), int(math.Floor(z))
+	distance = math.Inf(1)
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			for dz := -1; dz <= 1; dz++ {
+				h := Hash(v.seed, cx+dx, cy+dy, cz+dz, 0)
+				px, py, pz := float64(cx+dx)+unit(h), float64(cy+dy)+unit(h>>16), float64(cz+dz)+unit(h>>32)
+				dxf, dyf, dzf := px-x, py-y, pz-z
+				if d := math.Sqrt(dxf*dxf + dyf*dyf + dzf*dzf); d < distance {
+					distance, value = d, unit(h>>48)*2-1
+				}
+			}
+		}
+	}
+	return value, distance
+}
+
+// unit converts the lowest 16 bits of the hash passed to a value in the range [0, 1).
+func unit(h uint64) float64 {
+	return float64(h&0xffff) / 0x10000
+}
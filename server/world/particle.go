@@ -4,6 +4,11 @@ import (
 	"github.com/go-gl/mathgl/mgl64"
 )
 
+// EffectRange is the distance in blocks within which viewers are shown particles added using World.AddParticle and
+// hear sounds played using World.PlaySound. Viewers further away would not notice them, so sending them would only
+// waste bandwidth. The range of a sound may be changed by implementing RangedSound.
+const EffectRange = 64.0
+
 // Particle represents a particle that may be added to the world. These particles are then rendered client-
 // side, with the server having no control over it after sending.
 type Particle interface {
@@ -1,6 +1,9 @@
 package particle
 
-import "image/color"
+import (
+	"github.com/df-mc/dragonfly/server/world"
+	"image/color"
+)
 
 // HugeExplosion is a particle shown when TNT or a creeper explodes.
 type HugeExplosion struct{ particle }
@@ -14,6 +17,14 @@ type SnowballPoof struct{ particle }
 // EggSmash is a particle shown when an egg smashes on something.
 type EggSmash struct{ particle }
 
+// ItemBreak is a particle shown when an item, such as a tool, breaks after running out of durability.
+type ItemBreak struct {
+	// Item is the item that broke.
+	Item world.Item
+
+	particle
+}
+
 // Splash is a particle that shows up when a splash potion is splashed.
 type Splash struct {
 	particle
@@ -1,6 +1,7 @@
 package world
 
 import (
+	"fmt"
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/goleveldb/leveldb"
 	"github.com/google/uuid"
@@ -15,6 +16,11 @@ type Provider interface {
 	Settings() *Settings
 	// SaveSettings saves the settings of a World.
 	SaveSettings(*Settings)
+	// GameRules loads the values of the game rules of a World by their names. Values are a bool or an int, depending
+	// on the GameRule. Game rules not present in the map returned have their default value.
+	GameRules() map[string]any
+	// SaveGameRules saves the values of the game rules of a World by their names.
+	SaveGameRules(map[string]any)
 
 	// LoadPlayerSpawnPosition loads the player spawn point if found, otherwise an error will be returned.
 	LoadPlayerSpawnPosition(uuid uuid.UUID) (pos cube.Pos, exists bool, err error)
@@ -23,7 +29,8 @@ type Provider interface {
 	SavePlayerSpawnPosition(uuid uuid.UUID, pos cube.Pos) error
 	// LoadColumn reads a world.Column from the DB at a position and dimension
 	// in the DB. If no column at that position exists, errors.Is(err,
-	// leveldb.ErrNotFound) equals true.
+	// leveldb.ErrNotFound) equals true. If the column exists, but its data is
+	// malformed, the error returned is a *CorruptColumnError.
 	LoadColumn(pos ChunkPos, dim Dimension) (*Column, error)
 	// StoreColumn stores a world.Column at a position and dimension in the DB.
 	// An error is returned if storing was unsuccessful.
@@ -48,6 +55,8 @@ func (n NopProvider) Settings() *Settings {
 	return n.Set
 }
 func (NopProvider) SaveSettings(*Settings)                          {}
+func (NopProvider) GameRules() map[string]any                       { return nil }
+func (NopProvider) SaveGameRules(map[string]any)                    {}
 func (NopProvider) LoadColumn(ChunkPos, Dimension) (*Column, error) { return nil, leveldb.ErrNotFound }
 func (NopProvider) StoreColumn(ChunkPos, Dimension, *Column) error  { return nil }
 func (NopProvider) LoadPlayerSpawnPosition(uuid.UUID) (cube.Pos, bool, error) {
@@ -55,3 +64,25 @@ func (NopProvider) LoadPlayerSpawnPosition(uuid.UUID) (cube.Pos, bool, error) {
 }
 func (NopProvider) SavePlayerSpawnPosition(uuid.UUID, cube.Pos) error { return nil }
 func (NopProvider) Close() error                                      { return nil }
+
+// CorruptColumnError is returned by Provider.LoadColumn if the data of a column was found, but could not be decoded
+// because it is malformed. Any other error returned, apart from leveldb.ErrNotFound, means reading the data failed,
+// for example because of an IO error. Callers may use errors.As to check for a CorruptColumnError and decide to
+// generate the column again, which discards the malformed data once the column is saved.
+type CorruptColumnError struct {
+	// Pos and Dim are the position and dimension of the column that is corrupt.
+	Pos ChunkPos
+	Dim Dimension
+	// Err is the error that occurred while decoding the data of the column.
+	Err error
+}
+
+// Error ...
+func (err *CorruptColumnError) Error() string {
+	return fmt.Sprintf("column %v (%v) is corrupt: %v", err.Pos, err.Dim, err.Err)
+}
+
+// Unwrap returns the error that occurred while decoding the data of the column.
+func (err *CorruptColumnError) Unwrap() error {
+	return err.Err
+}
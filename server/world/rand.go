@@ -0,0 +1,41 @@
+package world
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// lockedSource is a rand.Source that may be used concurrently by multiple goroutines. It wraps around the
+// rand.Source set in the Config of a World.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+// Int63 ...
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+// Uint64 ...
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if src, ok := s.src.(rand.Source64); ok {
+		return src.Uint64()
+	}
+	return uint64(s.src.Int63())>>31 | uint64(s.src.Int63())<<32
+}
+
+// Seed ...
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// nopRand is the rand.Rand returned by World.Rand if the World is nil.
+var nopRand = rand.New(&lockedSource{src: rand.NewSource(time.Now().UnixNano())})
@@ -0,0 +1,113 @@
+package world
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/expr"
+	"math"
+)
+
+// Rules holds expressions that tune the gameplay of a World, such as the damage dealt by falling or by mobs. Each
+// expression is evaluated with a set of variables, of which 'damage' or 'amount' holds the value that would be used
+// if the expression was not set. Expressions that are nil are not used. See the expr package for the syntax of the
+// expressions.
+type Rules struct {
+	// FallDamage is evaluated to find the damage dealt to entities hitting the ground after falling. The variables
+	// 'damage', the damage dealt by default, and 'distance', the distance fallen in blocks, may be used.
+	FallDamage *expr.Expr
+	// Experience is evaluated to find the experience gained by players collecting experience orbs. The variable
+	// 'amount', the experience held by the orb, may be used.
+	Experience *expr.Expr
+	// MobDamage is evaluated to find the damage dealt by mobs attacking other entities. The variables 'damage', the
+	// damage dealt in normal difficulty, and 'difficulty', the ID of the difficulty of the World, may be used. If
+	// set, the damage is no longer scaled by the difficulty of the World unless the expression does so.
+	MobDamage *expr.Expr
+}
+
+// RuleSource holds the source of the expressions of Rules, for example as read from a configuration file. Empty
+// strings represent expressions that are not set. Calling RuleSource.Rules parses the expressions into Rules.
+type RuleSource struct {
+	FallDamage string
+	Experience string
+	MobDamage  string
+}
+
+// Rules parses the expressions of the RuleSource into Rules, checking that each expression only uses the variables
+// available to it. An error is returned if one of the expressions could not be parsed.
+func (src RuleSource) Rules() (r Rules, err error) {
+	if r.FallDamage, err = parseRule("fall damage", src.FallDamage, "damage", "distance"); err != nil {
+		return r, err
+	}
+	if r.Experience, err = parseRule("experience", src.Experience, "amount"); err != nil {
+		return r, err
+	}
+	r.MobDamage, err = parseRule("mob damage", src.MobDamage, "damage", "difficulty")
+	return r, err
+}
+
+// parseRule parses the expression of the rule with the name passed. If s is empty, nil is returned.
+func parseRule(name, s string, vars ...string) (*expr.Expr, error) {
+	if s == "" {
+		return nil, nil
+	}
+	e, err := expr.Parse(s, vars...)
+	if err != nil {
+		return nil, fmt.Errorf("parse %v rule: %w", name, err)
+	}
+	return e, nil
+}
+
+// Rules returns the Rules of the World, as set using Config.Rules or SetRules.
+func (w *World) Rules() Rules {
+	if w == nil {
+		return Rules{}
+	}
+	return w.rules.Load()
+}
+
+// SetRules changes the Rules of the World. The new Rules are used immediately.
+func (w *World) SetRules(r Rules) {
+	if w == nil {
+		return
+	}
+	w.rules.Store(r)
+}
+
+// FallDamage returns the damage dealt to an entity hitting the ground after falling the distance passed, if it
+// would be dealt the damage passed by default. The FallDamage expression of the Rules of the World is used if set. If
+// GameRuleFallDamage is disabled, no damage is dealt.
+func (w *World) FallDamage(dmg, distance float64) float64 {
+	if !w.BoolGameRule(GameRuleFallDamage) {
+		return 0
+	}
+	return w.Rules().FallDamage.EvalOr(dmg, expr.Vars{"damage": dmg, "distance": distance})
+}
+
+// Experience returns the experience gained by a player collecting an experience orb holding the amount passed. The
+// Experience expression of the Rules of the World is used if set.
+func (w *World) Experience(amount int) int {
+	return int(w.Rules().Experience.EvalOr(float64(amount), expr.Vars{"amount": float64(amount)}))
+}
+
+// MobDamage returns the damage dealt by a mob attacking another entity, if it would deal the damage passed in
+// normal difficulty. The MobDamage expression of the Rules of the World is used if set. Otherwise, the damage is
+// scaled by the Difficulty of the World, so that mobs deal no damage in peaceful difficulty, less damage in easy
+// difficulty and more damage in hard difficulty.
+func (w *World) MobDamage(dmg float64) float64 {
+	d := w.Difficulty()
+	diff, _ := DifficultyID(d)
+	return w.Rules().MobDamage.EvalOr(difficultyMobDamage(d, dmg), expr.Vars{"damage": dmg, "difficulty": float64(diff)})
+}
+
+// difficultyMobDamage returns the damage dealt by mobs in a World with the Difficulty passed, if they deal the damage
+// passed in normal difficulty.
+func difficultyMobDamage(d Difficulty, dmg float64) float64 {
+	switch d {
+	case DifficultyPeaceful:
+		return 0
+	case DifficultyEasy:
+		return math.Min(dmg/2+1, dmg)
+	case DifficultyHard:
+		return dmg * 1.5
+	}
+	return dmg
+}
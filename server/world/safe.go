@@ -0,0 +1,79 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+)
+
+const (
+	// safeSearchRadius is the horizontal distance in blocks from the position passed to FindSafePosition within
+	// which safe positions are searched for.
+	safeSearchRadius = 8
+	// safeSearchHeight is the vertical distance in blocks from the position passed to FindSafePosition within which
+	// safe positions are searched for.
+	safeSearchHeight = 16
+)
+
+// SafePosition checks if a player standing with its feet at the position passed would be safe: The blocks at its
+// feet and head must be passable, so that it does not suffocate, the block below its feet must have a solid top face
+// to stand on, and none of these blocks may be lava.
+func (w *World) SafePosition(pos cube.Pos) bool {
+	if w == nil || pos[1]-1 < w.Range()[0] || pos[1]+1 > w.Range()[1] {
+		return false
+	}
+	below := pos.Side(cube.FaceDown)
+	if w.lava(below) || !w.Block(below).Model().FaceSolid(below, cube.FaceUp, w) {
+		return false
+	}
+	return w.passable(pos) && w.passable(pos.Side(cube.FaceUp))
+}
+
+// FindSafePosition searches for the safe position closest to the position passed, as defined by SafePosition. The
+// position passed is checked first, after which positions above and below it and then in columns further away are
+// checked, up to 8 blocks away horizontally and 16 blocks vertically. If no safe position is found, FindSafePosition
+// returns false. FindSafePosition is used to prevent players from suffocating or burning after being teleported,
+// respawned or transported through a portal.
+func (w *World) FindSafePosition(near cube.Pos) (cube.Pos, bool) {
+	if w == nil {
+		return near, false
+	}
+	for r := 0; r <= safeSearchRadius; r++ {
+		for x := near[0] - r; x <= near[0]+r; x++ {
+			for z := near[2] - r; z <= near[2]+r; z++ {
+				if x != near[0]-r && x != near[0]+r && z != near[2]-r && z != near[2]+r {
+					// Only the ring at distance r is checked, as the columns closer were already checked.
+					continue
+				}
+				if pos, ok := w.findSafeInColumn(cube.Pos{x, near[1], z}); ok {
+					return pos, true
+				}
+			}
+		}
+	}
+	return near, false
+}
+
+// findSafeInColumn searches for the safe position closest to the position passed in its column, alternating between
+// positions above and below it.
+func (w *World) findSafeInColumn(near cube.Pos) (cube.Pos, bool) {
+	for dy := 0; dy <= safeSearchHeight; dy++ {
+		if pos := near.Add(cube.Pos{0, dy}); w.SafePosition(pos) {
+			return pos, true
+		}
+		if pos := near.Sub(cube.Pos{0, dy}); dy != 0 && w.SafePosition(pos) {
+			return pos, true
+		}
+	}
+	return near, false
+}
+
+// passable checks if an entity may occupy the block at the position passed: The block has no collision box and is
+// not lava.
+func (w *World) passable(pos cube.Pos) bool {
+	return !w.lava(pos) && len(w.Block(pos).Model().BBox(pos, w)) == 0
+}
+
+// lava checks if the block at the position passed is lava.
+func (w *World) lava(pos cube.Pos) bool {
+	l, ok := w.Liquid(pos)
+	return ok && l.LiquidType() == "lava"
+}
@@ -0,0 +1,75 @@
+package world
+
+import (
+	"fmt"
+	"golang.org/x/exp/maps"
+	"time"
+)
+
+// Save writes all loaded chunks that changed since they were loaded or last saved, and the settings of the World,
+// to the Provider of the World. Chunks are otherwise only written to the Provider once they are unloaded or the
+// World is closed, so Save may be used to make sure that no changes are lost if the program is terminated. A chunk
+// is considered changed if any of its blocks, biomes or block entities were changed, or if entities that are saved
+// moved in or out of it. Save does nothing if the World is read-only.
+func (w *World) Save() {
+	if w == nil || w.conf.ReadOnly {
+		return
+	}
+	w.chunkMu.Lock()
+	columns := maps.Clone(w.chunks)
+	w.chunkMu.Unlock()
+
+	saved := 0
+	for pos, c := range columns {
+		pos, c := pos, c
+		w.conf.CrashReporter.Catch("world provider", func() map[string]any {
+			return map[string]any{"world": w.Name(), "dimension": fmt.Sprint(w.Dimension()), "chunk": pos}
+		}, func() {
+			if w.saveModified(pos, c) {
+				saved++
+			}
+		})
+	}
+	if w.advancing() {
+		set := w.settings()
+		set.Lock()
+		w.provider().SaveSettings(set)
+		set.Unlock()
+		w.saveGameRules(w.provider())
+	}
+	w.conf.Log.Debugf("Saved %v/%v loaded chunks.", saved, len(columns))
+}
+
+// saveModified writes the Column passed to the Provider of the World if it changed since it was loaded or last
+// saved, without unloading it. saveModified returns true if the Column was written.
+func (w *World) saveModified(pos ChunkPos, c *Column) bool {
+	c.Lock()
+	defer c.Unlock()
+	if c.unloaded || !c.modified {
+		return false
+	}
+	c.Compact()
+	if err := w.provider().StoreColumn(pos, w.conf.Dim, c); err != nil {
+		w.chunkLog(pos).Errorf("save chunk: %v", err)
+		return false
+	}
+	c.modified = false
+	return true
+}
+
+// autoSave runs until the World is closed, calling Save every Config.SaveInterval.
+func (w *World) autoSave() {
+	t := time.NewTicker(w.conf.SaveInterval)
+	defer t.Stop()
+
+	w.running.Add(1)
+	for {
+		select {
+		case <-t.C:
+			w.Save()
+		case <-w.closing:
+			w.running.Done()
+			return
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package world
 
 import (
-	"github.com/df-mc/atomic"
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"sync"
 )
@@ -10,13 +9,16 @@ import (
 // Settings to multiple worlds created using New, in which case the Settings are synchronised between the worlds.
 type Settings struct {
 	sync.Mutex
-	ref atomic.Int32
+	// advancer is the World that advances the time and weather of the Settings every tick and saves them to its
+	// Provider. If nil, the next World using the Settings that is ticked becomes the advancer.
+	advancer *World
 
 	// Name is the display name of the World.
 	Name string
 	// Spawn is the spawn position of the World. New players that join the world will be spawned here.
 	Spawn cube.Pos
-	// Time is the current time of the World. It advances every tick if TimeCycle is set to true.
+	// Time is the current time of the World, which decides if it is day or night. It advances every tick if TimeCycle
+	// is set to true.
 	Time int64
 	// TimeCycle specifies if the time should advance every tick. If set to false, time won't change.
 	TimeCycle bool
@@ -30,8 +32,9 @@ type Settings struct {
 	Thundering bool
 	// WeatherCycle specifies if weather should be enabled in this world. If set to false, weather will be disabled.
 	WeatherCycle bool
-	// CurrentTick is the current tick of the world. This is similar to the Time, except that it has no visible effect
-	// to the client. It can also not be changed through commands and will only ever go up.
+	// CurrentTick is the current tick of the world, also known as its game time. This is similar to the Time, except
+	// that it has no visible effect to the client. It can also not be changed through commands and will only ever go
+	// up, even if TimeCycle is set to false.
 	CurrentTick int64
 	// DefaultGameMode is the GameMode assigned to players that join the World for the first time.
 	DefaultGameMode GameMode
@@ -41,6 +44,10 @@ type Settings struct {
 	// TickRange is the radius in chunks around a Viewer that has its blocks and entities ticked when the world is
 	// ticked. If set to 0, blocks and entities will never be ticked.
 	TickRange int32
+
+	// gameRules holds the values of the game rules of the World by their names, except for those stored in other
+	// fields, such as TimeCycle. Game rules without a value have their default value.
+	gameRules map[string]any
 }
 
 // defaultSettings returns the default Settings for a new World.
@@ -54,3 +61,16 @@ func defaultSettings() *Settings {
 		TickRange:       6,
 	}
 }
+
+// copyFrom copies all values of the Settings passed into s, except for CurrentTick, which only ever goes up. The
+// Settings passed are locked while doing so, but s must already be locked by the caller.
+func (s *Settings) copyFrom(o *Settings) {
+	if s == o {
+		return
+	}
+	o.Lock()
+	defer o.Unlock()
+	s.Name, s.Spawn, s.Time, s.TimeCycle = o.Name, o.Spawn, o.Time, o.TimeCycle
+	s.RainTime, s.Raining, s.ThunderTime, s.Thundering, s.WeatherCycle = o.RainTime, o.Raining, o.ThunderTime, o.Thundering, o.WeatherCycle
+	s.DefaultGameMode, s.Difficulty, s.TickRange = o.DefaultGameMode, o.Difficulty, o.TickRange
+}
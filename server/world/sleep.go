@@ -0,0 +1,60 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+)
+
+// Sleeper represents an entity that is able to sleep in a bed, such as a player. Once all Sleepers in a World have
+// been sleeping for a while, the night is skipped.
+type Sleeper interface {
+	Entity
+	// Sleep makes the Sleeper go to sleep in the bed at the position passed.
+	Sleep(pos cube.Pos)
+	// Sleeping returns the position of the bed that the Sleeper is sleeping in and true if it is sleeping.
+	Sleeping() (cube.Pos, bool)
+	// Wake wakes the Sleeper up if it is sleeping.
+	Wake()
+}
+
+// sleepDuration is the number of ticks that all Sleepers in a World must sleep for before the night is skipped.
+const sleepDuration = 100
+
+// tickSleepers skips the night if all Sleepers in the World have been sleeping for at least sleepDuration ticks.
+// Afterwards, the weather is cleared and all Sleepers are woken up. Sleepers that are not visible, such as players in
+// spectator mode, are not required to sleep.
+func (t ticker) tickSleepers() {
+	if !t.w.conf.Dim.TimeCycle() {
+		return
+	}
+	var sleepers []Sleeper
+	for _, e := range t.w.Entities() {
+		s, ok := e.(Sleeper)
+		if !ok {
+			continue
+		}
+		if g, ok := e.(interface{ GameMode() GameMode }); ok && !g.GameMode().Visible() {
+			continue
+		}
+		if _, sleeping := s.Sleeping(); !sleeping {
+			t.w.sleepTicks = 0
+			return
+		}
+		sleepers = append(sleepers, s)
+	}
+	if len(sleepers) == 0 {
+		t.w.sleepTicks = 0
+		return
+	}
+	if t.w.sleepTicks++; t.w.sleepTicks < sleepDuration {
+		return
+	}
+	t.w.sleepTicks = 0
+
+	tim := t.w.Time()
+	t.w.SetTime(tim + 24000 - tim%24000)
+	t.w.StopThundering()
+	t.w.StopRaining()
+	for _, s := range sleepers {
+		s.Wake()
+	}
+}
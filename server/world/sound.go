@@ -9,3 +9,11 @@ type Sound interface {
 	// is called with the sound.
 	Play(w *World, pos mgl64.Vec3)
 }
+
+// RangedSound represents a Sound that may be heard from a different distance than other sounds. By default, sounds
+// are only played to viewers within EffectRange blocks of the sound.
+type RangedSound interface {
+	Sound
+	// Range returns the maximum distance in blocks from which the sound may be heard.
+	Range() float64
+}
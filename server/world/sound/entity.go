@@ -1,5 +1,7 @@
 package sound
 
+import "math"
+
 // Attack is a sound played when an entity, most notably a player, attacks another entity.
 type Attack struct {
 	// Damage specifies if the attack actually dealt damage to the other entity. If set to false, the sound
@@ -32,9 +34,15 @@ type Pop struct{ sound }
 // Explosion is a sound played when an explosion happens, such as from a creeper or TNT.
 type Explosion struct{ sound }
 
-// Thunder is a sound played when lightning strikes the ground.
+// Thunder is a sound played when lightning strikes the ground. Thunder may be heard by all viewers of the position
+// of the lightning.
 type Thunder struct{ sound }
 
+// Range ...
+func (Thunder) Range() float64 {
+	return math.MaxFloat64
+}
+
 // LevelUp is a sound played for a player whenever they level up.
 type LevelUp struct{ sound }
 
@@ -58,3 +66,9 @@ type FireworkBlast struct{ sound }
 
 // FireworkTwinkle is a sound played when a firework explodes and should twinkle.
 type FireworkTwinkle struct{ sound }
+
+// ShieldBlock is a sound played when an entity blocks an attack using a shield.
+type ShieldBlock struct{ sound }
+
+// Totem is a sound played when a totem saves an entity from death.
+type Totem struct{ sound }
@@ -0,0 +1,349 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"math/rand"
+)
+
+// SpawnCategory is a category of mobs that spawn naturally in a World. Every category has its own CategoryRules,
+// such as the maximum amount of mobs of the category that may be spawned.
+type SpawnCategory int
+
+const (
+	// SpawnCategoryHostile is the category of mobs that attack players, such as zombies. Hostile mobs do not spawn
+	// if the difficulty of the World is DifficultyPeaceful, and hostile mobs spawned before are despawned.
+	SpawnCategoryHostile SpawnCategory = iota
+	// SpawnCategoryPassive is the category of mobs that do not attack players, such as cows and pigs.
+	SpawnCategoryPassive
+	// SpawnCategoryAmbient is the category of mobs that only exist for decoration, such as bats.
+	SpawnCategoryAmbient
+	// SpawnCategoryWater is the category of mobs that spawn in water, such as squids. Unlike the mobs of other
+	// categories, these mobs spawn inside water rather than on top of solid blocks.
+	SpawnCategoryWater
+)
+
+// SpawnEntry is an entry in a spawn pool. It describes a mob that may be spawned naturally and how.
+type SpawnEntry struct {
+	// Category is the SpawnCategory of the mob. The CategoryRules of the category decide when and where the mob
+	// may spawn.
+	Category SpawnCategory
+	// Weight is the weight of the entry in the pool. Entries with a higher weight are picked more often than those
+	// with a lower weight. Entries with a weight of 0 or lower are never picked.
+	Weight int
+	// MinGroupSize and MaxGroupSize are the minimum and maximum amount of mobs that are spawned together. If both
+	// are 0, a single mob is spawned.
+	MinGroupSize, MaxGroupSize int
+	// New creates the mob at the position passed. New must not be nil.
+	New func(pos mgl64.Vec3, w *World) Entity
+	// Condition is an additional condition that must be met for the mob to spawn at a block position, such as the
+	// block below being grass. If nil, the mob may spawn at any position that meets the CategoryRules.
+	Condition func(pos cube.Pos, w *World) bool
+}
+
+// CategoryRules holds the rules for spawning and despawning mobs of a SpawnCategory.
+type CategoryRules struct {
+	// Disabled specifies if mobs of the category should not be spawned at all.
+	Disabled bool
+	// Cap is the maximum amount of naturally spawned mobs of the category per player in the World.
+	Cap int
+	// Interval is the interval in ticks at which spawning mobs of the category is attempted. Intervals of 0 or
+	// lower disable spawning of the category.
+	Interval int64
+	// MinLight and MaxLight are the minimum and maximum light level at which mobs of the category may spawn. The
+	// light of the sky is lowered during the night when checking this.
+	MinLight, MaxLight uint8
+	// MinDistance is the minimum distance from any player that mobs of the category spawn at.
+	MinDistance float64
+	// DespawnDistance is the distance from the nearest player beyond which mobs of the category despawn right
+	// away. If 0, mobs are never despawned right away.
+	DespawnDistance float64
+	// RandomDespawnDistance is the distance from the nearest player beyond which mobs of the category have a
+	// chance of 1/800 to despawn every tick. If 0, mobs are never despawned randomly.
+	RandomDespawnDistance float64
+	// Persistent specifies if mobs of the category should never despawn, regardless of the distance to players.
+	Persistent bool
+}
+
+// SpawnRules holds the rules for spawning mobs naturally in a World. SpawnRules may be changed using
+// World.SetSpawnRules. The default SpawnRules of a World are returned by DefaultSpawnRules.
+type SpawnRules struct {
+	// Disabled disables natural spawning and despawning of mobs altogether.
+	Disabled bool
+	// Categories holds the CategoryRules of each SpawnCategory. Mobs of categories without CategoryRules are never
+	// spawned.
+	Categories map[SpawnCategory]CategoryRules
+	// Pools holds the spawn pools of biomes. If no pool is present for a biome, DefaultPool is used instead.
+	Pools map[Biome][]SpawnEntry
+	// DefaultPool is the spawn pool of biomes that do not have a pool in Pools.
+	DefaultPool []SpawnEntry
+}
+
+// DefaultSpawnRules returns the default SpawnRules of a World. The CategoryRules returned are similar to those
+// of vanilla, but the spawn pools are empty, so that mobs must be added to them explicitly.
+func DefaultSpawnRules() SpawnRules {
+	return SpawnRules{
+		Categories: map[SpawnCategory]CategoryRules{
+			SpawnCategoryHostile: {Cap: 70, Interval: 1, MaxLight: 7, MinDistance: 24, DespawnDistance: 128, RandomDespawnDistance: 32},
+			SpawnCategoryPassive: {Cap: 10, Interval: 400, MinLight: 9, MaxLight: 15, MinDistance: 24, Persistent: true},
+			SpawnCategoryAmbient: {Cap: 15, Interval: 1, MaxLight: 3, MinDistance: 24, DespawnDistance: 128, RandomDespawnDistance: 32},
+			SpawnCategoryWater:   {Cap: 5, Interval: 1, MaxLight: 15, MinDistance: 24, DespawnDistance: 128, RandomDespawnDistance: 32},
+		},
+		Pools: map[Biome][]SpawnEntry{},
+	}
+}
+
+// Pool returns the spawn pool of the Biome passed, or DefaultPool if no pool was set for it.
+func (r SpawnRules) Pool(b Biome) []SpawnEntry {
+	if pool, ok := r.Pools[b]; ok {
+		return pool
+	}
+	return r.DefaultPool
+}
+
+// pick picks a random SpawnEntry of the SpawnCategory passed from the spawn pool of the Biome passed, taking the
+// weights of the entries into account. If the pool holds no entries of the category, false is returned.
+func (r SpawnRules) pick(b Biome, cat SpawnCategory, rand *rand.Rand) (SpawnEntry, bool) {
+	var total int
+	pool := r.Pool(b)
+	for _, e := range pool {
+		if e.Category == cat && e.Weight > 0 {
+			total += e.Weight
+		}
+	}
+	if total == 0 {
+		return SpawnEntry{}, false
+	}
+	n := rand.Intn(total)
+	for _, e := range pool {
+		if e.Category != cat || e.Weight <= 0 {
+			continue
+		}
+		if n -= e.Weight; n < 0 {
+			return e, true
+		}
+	}
+	return SpawnEntry{}, false
+}
+
+// SpawnRules returns the SpawnRules currently used to spawn mobs naturally in the World.
+func (w *World) SpawnRules() SpawnRules {
+	if w == nil {
+		return SpawnRules{Disabled: true}
+	}
+	w.spawnMu.Lock()
+	defer w.spawnMu.Unlock()
+	return w.spawnRules
+}
+
+// SetSpawnRules changes the SpawnRules used to spawn mobs naturally in the World. Mobs already spawned are
+// despawned following the new rules.
+func (w *World) SetSpawnRules(r SpawnRules) {
+	if w == nil {
+		return
+	}
+	w.spawnMu.Lock()
+	defer w.spawnMu.Unlock()
+	w.spawnRules = r
+}
+
+// tickMobSpawning spawns mobs naturally around the loaders passed and despawns naturally spawned mobs that are too
+// far away from them, following the SpawnRules of the World.
+func (t ticker) tickMobSpawning(loaders []*Loader, tick int64) {
+	rules := t.w.SpawnRules()
+	if rules.Disabled || len(loaders) == 0 {
+		return
+	}
+	positions := make([]mgl64.Vec3, 0, len(loaders))
+	for _, l := range loaders {
+		l.mu.RLock()
+		if l.moved {
+			// Loaders that were never moved have no position yet, so mobs should not be spawned around them.
+			positions = append(positions, l.vec)
+		}
+		l.mu.RUnlock()
+	}
+	if len(positions) == 0 {
+		return
+	}
+	counts := t.despawnMobs(rules, positions)
+
+	r := t.w.tickRange()
+	if r == 0 || !t.w.BoolGameRule(GameRuleDoMobSpawning) {
+		return
+	}
+	for cat, cr := range rules.Categories {
+		if cr.Disabled || cr.Interval <= 0 || tick%cr.Interval != 0 || counts[cat] >= cr.Cap*len(positions) {
+			continue
+		}
+		if cat == SpawnCategoryHostile && t.w.Difficulty() == DifficultyPeaceful {
+			continue
+		}
+		for _, pos := range positions {
+			counts[cat] += t.spawnGroup(rules, cat, cr, pos, positions, r)
+		}
+	}
+}
+
+// spawnGroup attempts to spawn a group of mobs of the SpawnCategory passed in a random column within r chunks of
+// the position passed. The amount of mobs spawned is returned.
+func (t ticker) spawnGroup(rules SpawnRules, cat SpawnCategory, cr CategoryRules, centre mgl64.Vec3, positions []mgl64.Vec3, r int) int {
+	chunkPos := chunkPosFromVec3(centre)
+	chunkPos[0] += int32(t.w.r.Intn(r*2+1) - r)
+	chunkPos[1] += int32(t.w.r.Intn(r*2+1) - r)
+
+	t.w.chunkMu.Lock()
+	_, loaded := t.w.chunks[chunkPos]
+	t.w.chunkMu.Unlock()
+	if !loaded {
+		// Never spawn mobs in chunks that are not loaded, as this would require loading them.
+		return 0
+	}
+	x, z := int(chunkPos[0])<<4+t.w.r.Intn(16), int(chunkPos[1])<<4+t.w.r.Intn(16)
+	minY, maxY := t.w.Range().Min(), t.w.HighestBlock(x, z)+1
+	if maxY <= minY {
+		return 0
+	}
+	pos := cube.Pos{x, minY + t.w.r.Intn(maxY-minY+1), z}
+
+	entry, ok := rules.pick(t.w.Biome(pos), cat, t.w.r)
+	if !ok {
+		return 0
+	}
+	size := entry.MinGroupSize
+	if entry.MaxGroupSize > entry.MinGroupSize {
+		size += t.w.r.Intn(entry.MaxGroupSize - entry.MinGroupSize + 1)
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	var spawned int
+	for i := 0; i < size; i++ {
+		p := pos.Add(cube.Pos{t.w.r.Intn(5) - 2, 0, t.w.r.Intn(5) - 2})
+		if p.OutOfBounds(t.w.Range()) || !t.w.canSpawn(p, cat, cr, entry, positions) {
+			continue
+		}
+		e := entry.New(p.Vec3Middle(), t.w)
+		t.w.AddEntity(e)
+
+		t.w.spawnMu.Lock()
+		t.w.spawned[e] = cat
+		t.w.spawnMu.Unlock()
+		spawned++
+	}
+	return spawned
+}
+
+// canSpawn checks if a mob of the SpawnEntry passed may spawn at a block position.
+func (w *World) canSpawn(pos cube.Pos, cat SpawnCategory, cr CategoryRules, entry SpawnEntry, positions []mgl64.Vec3) bool {
+	if dist := nearest(pos.Vec3Middle(), positions); dist < cr.MinDistance || (cr.DespawnDistance > 0 && dist > cr.DespawnDistance) {
+		return false
+	}
+	if l := w.spawnLight(pos); l < cr.MinLight || l > cr.MaxLight {
+		return false
+	}
+	if cat == SpawnCategoryWater {
+		if l, ok := w.Liquid(pos); !ok || l.LiquidType() != "water" {
+			return false
+		}
+	} else {
+		if !w.Block(pos.Side(cube.FaceDown)).Model().FaceSolid(pos.Side(cube.FaceDown), cube.FaceUp, w) {
+			return false
+		}
+		for _, p := range []cube.Pos{pos, pos.Side(cube.FaceUp)} {
+			if _, ok := w.Liquid(p); ok || len(w.Block(p).Model().BBox(p, w)) != 0 {
+				return false
+			}
+		}
+	}
+	return entry.Condition == nil || entry.Condition(pos, w)
+}
+
+// spawnLight returns the light level at a position used to check if mobs may spawn there. Unlike World.Light, the
+// light of the sky is lowered depending on the time of the World.
+func (w *World) spawnLight(pos cube.Pos) uint8 {
+	var blockLight uint8
+	sky, l := w.SkyLight(pos), w.Light(pos)
+	if l > sky {
+		// The light is higher than the skylight, so it must be coming from a block emitting light.
+		blockLight = l
+	}
+	if d := skyDarkening(w.DayTime()); sky > d {
+		sky -= d
+	} else {
+		sky = 0
+	}
+	if blockLight > sky {
+		return blockLight
+	}
+	return sky
+}
+
+// skyDarkening returns the amount that the light of the sky is lowered by at a time of the day.
+func skyDarkening(t int) uint8 {
+	switch t %= 24000; {
+	case t < 12000:
+		return 0
+	case t < 13000:
+		return uint8((t - 12000) * 11 / 1000)
+	case t < 23000:
+		return 11
+	default:
+		return uint8((24000 - t) * 11 / 1000)
+	}
+}
+
+// despawnMobs despawns naturally spawned mobs that are too far away from all positions passed, following the
+// SpawnRules passed. The amount of naturally spawned mobs of each SpawnCategory left is returned.
+func (t ticker) despawnMobs(rules SpawnRules, positions []mgl64.Vec3) map[SpawnCategory]int {
+	t.w.spawnMu.Lock()
+	spawned := make(map[Entity]SpawnCategory, len(t.w.spawned))
+	for e, cat := range t.w.spawned {
+		if w, _ := OfEntity(e); w != t.w {
+			// The entity was removed from the World or moved to another World, so it is no longer ours to despawn.
+			delete(t.w.spawned, e)
+			continue
+		}
+		spawned[e] = cat
+	}
+	t.w.spawnMu.Unlock()
+
+	counts := make(map[SpawnCategory]int, len(rules.Categories))
+	hostile := t.w.Difficulty() != DifficultyPeaceful
+	for e, cat := range spawned {
+		cr := rules.Categories[cat]
+		if cat == SpawnCategoryHostile && !hostile {
+			// Hostile mobs are removed right away once the difficulty no longer allows them.
+			t.w.spawnMu.Lock()
+			delete(t.w.spawned, e)
+			t.w.spawnMu.Unlock()
+
+			_ = e.Close()
+			continue
+		}
+		if !cr.Persistent {
+			dist := nearest(e.Position(), positions)
+			if (cr.DespawnDistance > 0 && dist > cr.DespawnDistance) || (cr.RandomDespawnDistance > 0 && dist > cr.RandomDespawnDistance && t.w.r.Intn(800) == 0) {
+				t.w.spawnMu.Lock()
+				delete(t.w.spawned, e)
+				t.w.spawnMu.Unlock()
+
+				_ = e.Close()
+				continue
+			}
+		}
+		counts[cat]++
+	}
+	return counts
+}
+
+// nearest returns the distance from the position passed to the nearest of the positions passed.
+func nearest(pos mgl64.Vec3, positions []mgl64.Vec3) float64 {
+	dist := math.MaxFloat64
+	for _, p := range positions {
+		dist = math.Min(dist, p.Sub(pos).Len())
+	}
+	return dist
+}
@@ -0,0 +1,33 @@
+package world
+
+import "time"
+
+// Stats holds statistics about a World that may be used to monitor its performance.
+type Stats struct {
+	// Ticks is the number of ticks performed since the World was created and TickTime the total time spent on them.
+	Ticks    uint64
+	TickTime time.Duration
+	// LastTick is the time spent on the last tick performed.
+	LastTick time.Duration
+	// LoadedChunks is the number of chunks currently loaded.
+	LoadedChunks int
+	// Entities is the number of entities currently in the World.
+	Entities int
+}
+
+// Stats returns the current Stats of the World.
+func (w *World) Stats() Stats {
+	if w == nil {
+		return Stats{}
+	}
+	s := Stats{Ticks: w.ticks.Load(), TickTime: w.tickTime.Load(), LastTick: w.lastTick.Load()}
+
+	w.chunkMu.Lock()
+	s.LoadedChunks = len(w.chunks)
+	w.chunkMu.Unlock()
+
+	w.entityMu.RLock()
+	s.Entities = len(w.entities)
+	w.entityMu.RUnlock()
+	return s
+}
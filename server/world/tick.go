@@ -1,10 +1,12 @@
 package world
 
 import (
+	"fmt"
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/internal/sliceutil"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
+	"io"
 	"math/rand"
 	"time"
 )
@@ -34,25 +36,46 @@ func (t ticker) tickLoop() {
 
 // tick performs a tick on the World and updates the time, weather, blocks and entities that require updates.
 func (t ticker) tick() {
+	start := time.Now()
+	t.w.tickWork.Store(0)
+	t.w.tickLoaded.Store(false)
+	defer func() {
+		// Chunks are loaded by Loaders concurrently with the tick, so the time spent on the tick itself is added to
+		// the time spent loading chunks to check if the budget of the tick was used up.
+		t.w.tickWork.Add(time.Since(start))
+		t.w.updateSnapshot()
+
+		d := time.Since(start)
+		t.w.ticks.Inc()
+		t.w.tickTime.Add(d)
+		t.w.lastTick.Store(d)
+	}()
+	t.w.runExec(false)
+
 	viewers, loaders := t.w.allViewers()
 
-	t.w.set.Lock()
-	if len(viewers) == 0 && t.w.set.CurrentTick != 0 {
-		t.w.set.Unlock()
+	set := t.w.settings()
+	set.Lock()
+	if len(viewers) == 0 && set.CurrentTick != 0 {
+		set.Unlock()
 		return
 	}
-	if t.w.advance {
-		t.w.set.CurrentTick++
-		if t.w.set.TimeCycle {
-			t.w.set.Time++
+	if set.advancer == nil {
+		// The World advancing the Settings was closed or changed its Provider, so this World takes over.
+		set.advancer = t.w
+	}
+	if set.advancer == t.w {
+		set.CurrentTick++
+		if set.TimeCycle {
+			set.Time++
 		}
-		if t.w.set.WeatherCycle {
-			t.w.advanceWeather()
+		if set.WeatherCycle {
+			t.w.advanceWeather(set)
 		}
 	}
 
-	rain, thunder, tick, tim := t.w.set.Raining, t.w.set.Thundering && t.w.set.Raining, t.w.set.CurrentTick, int(t.w.set.Time)
-	t.w.set.Unlock()
+	rain, thunder, tick, tim := set.Raining, set.Thundering && set.Raining, set.CurrentTick, int(set.Time)
+	set.Unlock()
 
 	if tick%20 == 0 {
 		for _, viewer := range viewers {
@@ -69,9 +92,31 @@ func (t ticker) tick() {
 	}
 
 	t.tickEntities(tick)
-	t.tickBlocksRandomly(loaders, tick)
-	t.tickScheduledBlocks(tick)
-	t.performNeighbourUpdates()
+	t.tickSleepers()
+
+	// Panics in the following phases are recovered, so that a single broken block does not crash the server. The
+	// remaining updates of the phase are skipped for this tick.
+	rep, ctx := t.w.conf.CrashReporter, t.crashContext(tick, nil)
+	rep.Catch("world tick", ctx, func() { t.tickMobSpawning(loaders, tick) })
+	rep.Catch("world tick", ctx, func() { t.tickBlocksRandomly(loaders, tick) })
+	rep.Catch("world tick", ctx, func() { t.tickScheduledBlocks(tick) })
+	rep.Catch("world tick", ctx, t.performNeighbourUpdates)
+}
+
+// crashContext returns a function that returns the context of a crash that occurred in the World during the tick
+// passed. If e is not nil, information on the entity is included.
+func (t ticker) crashContext(tick int64, e Entity) func() map[string]any {
+	return func() map[string]any {
+		m := map[string]any{"world": t.w.Name(), "dimension": fmt.Sprint(t.w.Dimension()), "tick": tick}
+		if e != nil {
+			m["entity_type"] = e.Type().EncodeEntity()
+			m["entity_position"] = e.Position()
+			if n, ok := e.(interface{ Name() string }); ok {
+				m["entity_name"] = n.Name()
+			}
+		}
+		return m
+	}
 }
 
 // tickScheduledBlocks executes scheduled block updates in chunks that are currently loaded.
@@ -88,11 +133,15 @@ func (t ticker) tickScheduledBlocks(tick int64) {
 
 	for _, pos := range positions {
 		if ticker, ok := t.w.Block(pos).(ScheduledTicker); ok {
+			start := t.w.timings.start()
 			ticker.ScheduledTick(pos, t.w, t.w.r)
+			t.w.timings.block(ticker, start)
 		}
 		if liquid, ok := t.w.additionalLiquid(pos); ok {
 			if ticker, ok := liquid.(ScheduledTicker); ok {
+				start := t.w.timings.start()
 				ticker.ScheduledTick(pos, t.w, t.w.r)
+				t.w.timings.block(ticker, start)
 			}
 		}
 	}
@@ -108,11 +157,15 @@ func (t ticker) performNeighbourUpdates() {
 	for _, update := range positions {
 		pos, changedNeighbour := update.pos, update.neighbour
 		if ticker, ok := t.w.Block(pos).(NeighbourUpdateTicker); ok {
+			start := t.w.timings.start()
 			ticker.NeighbourUpdateTick(pos, changedNeighbour, t.w)
+			t.w.timings.block(ticker, start)
 		}
 		if liquid, ok := t.w.additionalLiquid(pos); ok {
 			if ticker, ok := liquid.(NeighbourUpdateTicker); ok {
+				start := t.w.timings.start()
 				ticker.NeighbourUpdateTick(pos, changedNeighbour, t.w)
+				t.w.timings.block(ticker, start)
 			}
 		}
 	}
@@ -123,6 +176,7 @@ func (t ticker) performNeighbourUpdates() {
 func (t ticker) tickBlocksRandomly(loaders []*Loader, tick int64) {
 	var (
 		r             = int32(t.w.tickRange())
+		speed         = t.w.RandomTickSpeed()
 		g             randUint4
 		blockEntities []cube.Pos
 		randomBlocks  []cube.Pos
@@ -153,7 +207,7 @@ func (t ticker) tickBlocksRandomly(loaders []*Loader, tick int64) {
 		cx, cz := int(pos[0]<<4), int(pos[1]<<4)
 
 		// We generate up to j random positions for every sub chunk.
-		for j := 0; j < t.w.conf.RandomTickSpeed; j++ {
+		for j := 0; j < speed; j++ {
 			x, y, z := g.uint4(t.w.r), g.uint4(t.w.r), g.uint4(t.w.r)
 
 			for i, sub := range c.Sub() {
@@ -180,12 +234,18 @@ func (t ticker) tickBlocksRandomly(loaders []*Loader, tick int64) {
 
 	for _, pos := range randomBlocks {
 		if rb, ok := t.w.Block(pos).(RandomTicker); ok {
+			start := t.w.timings.start()
 			rb.RandomTick(pos, t.w, t.w.r)
+			t.w.timings.block(rb, start)
 		}
 	}
 	for _, pos := range blockEntities {
 		if tb, ok := t.w.Block(pos).(TickerBlock); ok {
+			start := t.w.timings.start()
 			tb.Tick(tick, pos, t.w)
+			t.w.timings.block(tb, start)
+			// Ticking block entities, such as furnaces and hoppers, may change their state every tick.
+			t.w.BlockEntityChanged(pos)
 		}
 	}
 }
@@ -247,7 +307,7 @@ func (t ticker) tickEntities(tick int64) {
 			// the loaders from the old chunk. We can assume they never saw the entity in the first place.
 			if old, ok := t.w.chunks[lastPos]; ok {
 				old.Lock()
-				old.Entities = sliceutil.DeleteVal(old.Entities, e)
+				old.removeEntity(e)
 				viewers = slices.Clone(old.viewers)
 				old.Unlock()
 			}
@@ -259,7 +319,7 @@ func (t ticker) tickEntities(tick int64) {
 
 	for _, move := range entitiesToMove {
 		move.after.Lock()
-		move.after.Entities = append(move.after.Entities, move.e)
+		move.after.addEntity(move.e)
 		viewersAfter := move.after.viewers
 		move.after.Unlock()
 
@@ -283,8 +343,29 @@ func (t ticker) tickEntities(tick int64) {
 		if ticker.World() == t.w {
 			// We gather entities to ticker and ticker them later, so that the lock on the entity mutex is no longer
 			// active.
-			ticker.Tick(t.w, tick)
+			e := ticker
+			if t.w.conf.CrashReporter.Catch("entity tick", t.crashContext(tick, e), func() {
+				start := t.w.timings.start()
+				e.Tick(t.w, tick)
+				t.w.timings.entity(e, start)
+			}) {
+				t.closeCrashedEntity(e)
+			}
+		}
+	}
+}
+
+// closeCrashedEntity closes an entity that panicked while being ticked, so that it does not crash the World again on
+// the next tick. Entities implementing io.Closer, such as players, are closed. Other entities are removed from the
+// World.
+func (t ticker) closeCrashedEntity(e Entity) {
+	t.w.conf.CrashReporter.Catch("entity close", t.crashContext(0, e), func() {
+		if c, ok := e.(io.Closer); ok {
+			_ = c.Close()
 		}
+	})
+	if e.World() == t.w {
+		t.w.RemoveEntity(e)
 	}
 }
 
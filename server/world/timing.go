@@ -0,0 +1,151 @@
+package world
+
+import (
+	"github.com/df-mc/atomic"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Timing holds the time spent by a World ticking a single type of block or entity since timings were enabled or
+// last reset.
+type Timing struct {
+	// Name is the name of the block or entity type, such as 'minecraft:hopper' or 'minecraft:zombie'.
+	Name string
+	// Entity specifies if Name is the name of an entity type. If false, Name is the name of a block.
+	Entity bool
+	// Total is the cumulative time spent ticking blocks or entities of this type.
+	Total time.Duration
+	// Count is the number of times a block or entity of this type was ticked.
+	Count int
+}
+
+// Average returns the average time spent on a single tick of a block or entity of the type.
+func (t Timing) Average() time.Duration {
+	if t.Count == 0 {
+		return 0
+	}
+	return t.Total / time.Duration(t.Count)
+}
+
+// timingKey identifies a Timing in a timings map.
+type timingKey struct {
+	name   string
+	entity bool
+}
+
+// timings keeps track of the time spent ticking each type of block and entity in a World.
+type timings struct {
+	enabled atomic.Bool
+
+	mu    sync.Mutex
+	since time.Time
+	m     map[timingKey]*Timing
+}
+
+// start returns the time at which ticking a block or entity started, or the zero time if timings are not enabled.
+func (t *timings) start() time.Time {
+	if !t.enabled.Load() {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// block records the time spent ticking the block passed, which started at the time passed. b is one of the ticker
+// interfaces implemented by a Block, such as RandomTicker.
+func (t *timings) block(b any, start time.Time) {
+	if start.IsZero() {
+		return
+	}
+	bl, ok := b.(Block)
+	if !ok {
+		return
+	}
+	name, _ := bl.EncodeBlock()
+	t.record(timingKey{name: name}, time.Since(start))
+}
+
+// entity records the time spent ticking the entity passed, which started at the time passed.
+func (t *timings) entity(e Entity, start time.Time) {
+	if start.IsZero() {
+		return
+	}
+	t.record(timingKey{name: e.Type().EncodeEntity(), entity: true}, time.Since(start))
+}
+
+// record adds the duration passed to the Timing with the key passed.
+func (t *timings) record(k timingKey, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.m == nil {
+		return
+	}
+	tm, ok := t.m[k]
+	if !ok {
+		tm = &Timing{Name: k.name, Entity: k.entity}
+		t.m[k] = tm
+	}
+	tm.Total += d
+	tm.Count++
+}
+
+// reset clears all timings recorded.
+func (t *timings) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.m, t.since = map[timingKey]*Timing{}, time.Now()
+}
+
+// EnableTimings enables or disables recording the time spent ticking each type of block and entity in the World.
+// Recording timings has a small cost for every block and entity ticked, so timings are disabled by default.
+// Enabling timings clears any timings recorded previously.
+func (w *World) EnableTimings(enabled bool) {
+	if w == nil {
+		return
+	}
+	if enabled && !w.timings.enabled.Load() {
+		w.timings.reset()
+	}
+	w.timings.enabled.Store(enabled)
+}
+
+// TimingsEnabled checks if the World is recording timings. See EnableTimings.
+func (w *World) TimingsEnabled() bool {
+	if w == nil {
+		return false
+	}
+	return w.timings.enabled.Load()
+}
+
+// ResetTimings clears all timings recorded by the World, so that Timings only returns the time spent ticking
+// blocks and entities from now on.
+func (w *World) ResetTimings() {
+	if w == nil {
+		return
+	}
+	w.timings.reset()
+}
+
+// Timings returns the time spent ticking each type of block and entity in the World, sorted from the type that took
+// the longest to the type that took the shortest, and the time at which recording of these timings started. If
+// timings were never enabled using EnableTimings, Timings returns no timings.
+func (w *World) Timings() ([]Timing, time.Time) {
+	if w == nil {
+		return nil, time.Time{}
+	}
+	w.timings.mu.Lock()
+	m := make([]Timing, 0, len(w.timings.m))
+	for _, t := range w.timings.m {
+		m = append(m, *t)
+	}
+	since := w.timings.since
+	w.timings.mu.Unlock()
+
+	sort.Slice(m, func(i, j int) bool {
+		if m[i].Total == m[j].Total {
+			return m[i].Name < m[j].Name
+		}
+		return m[i].Total > m[j].Total
+	})
+	return m, since
+}
@@ -8,6 +8,15 @@ import (
 	"time"
 )
 
+// PositionedViewer is a Viewer that has a position in the world, such as a player. Particles and sounds are only
+// shown to PositionedViewers close enough to them. Viewers without a position are shown all particles and sounds
+// in the chunks they view.
+type PositionedViewer interface {
+	Viewer
+	// Position returns the current position of the Viewer.
+	Position() mgl64.Vec3
+}
+
 // Viewer is a viewer in the world. It can view changes that are made in the world, such as the addition of
 // entities and the changes of blocks.
 type Viewer interface {
@@ -29,6 +38,9 @@ type Viewer interface {
 	// ViewEntityTeleport views the teleportation of an entity. The entity is immediately moved to a different
 	// target position.
 	ViewEntityTeleport(e Entity, pos mgl64.Vec3)
+	// ViewEntityDismount views an entity dismounting the vehicle passed, such as a player standing up from a seat.
+	// Entities mounting a vehicle are viewed when the vehicle is viewed using ViewEntity.
+	ViewEntityDismount(e, vehicle Entity)
 	// ViewFurnaceUpdate updates a furnace for the associated session based on previous times.
 	ViewFurnaceUpdate(prevCookTime, cookTime, prevRemainingFuelTime, remainingFuelTime, prevMaxFuelTime, maxFuelTime time.Duration)
 	// ViewChunk views the chunk passed at a particular position. It is called for every chunk loaded using
@@ -68,6 +80,11 @@ type Viewer interface {
 	ViewWorldSpawn(pos cube.Pos)
 	// ViewWeather views the weather of the world, including rain and thunder.
 	ViewWeather(raining, thunder bool)
+	// ViewGameRules views the values of the game rules passed, by their names. It is called with the values of all
+	// game rules when the viewer starts viewing the world, and with a single value every time a game rule changes.
+	ViewGameRules(rules map[string]any)
+	// ViewDifficulty views the difficulty of the world.
+	ViewDifficulty(d Difficulty)
 }
 
 // NopViewer is a Viewer implementation that does not implement any behaviour. It may be embedded by other structs to
@@ -83,6 +100,7 @@ func (NopViewer) ViewEntityGameMode(Entity)                                  {}
 func (NopViewer) ViewEntityMovement(Entity, mgl64.Vec3, cube.Rotation, bool) {}
 func (NopViewer) ViewEntityVelocity(Entity, mgl64.Vec3)                      {}
 func (NopViewer) ViewEntityTeleport(Entity, mgl64.Vec3)                      {}
+func (NopViewer) ViewEntityDismount(Entity, Entity)                          {}
 func (NopViewer) ViewChunk(ChunkPos, *chunk.Chunk, map[cube.Pos]Block)       {}
 func (NopViewer) ViewTime(int)                                               {}
 func (NopViewer) ViewEntityItems(Entity)                                     {}
@@ -98,5 +116,7 @@ func (NopViewer) ViewEmote(Entity, uuid.UUID)                                {}
 func (NopViewer) ViewSkin(Entity)                                            {}
 func (NopViewer) ViewWorldSpawn(cube.Pos)                                    {}
 func (NopViewer) ViewWeather(bool, bool)                                     {}
+func (NopViewer) ViewGameRules(map[string]any)                               {}
+func (NopViewer) ViewDifficulty(Difficulty)                                  {}
 func (NopViewer) ViewFurnaceUpdate(time.Duration, time.Duration, time.Duration, time.Duration, time.Duration, time.Duration) {
 }
@@ -29,9 +29,10 @@ func (w weather) SnowingAt(pos cube.Pos) bool {
 	if b := w.w.Biome(pos); b.Rainfall() == 0 || w.w.Temperature(pos) > 0.15 {
 		return false
 	}
-	w.w.set.Lock()
-	raining := w.w.set.Raining
-	w.w.set.Unlock()
+	set := w.w.settings()
+	set.Lock()
+	raining := set.Raining
+	set.Unlock()
 	return raining && w.w.highestObstructingBlock(pos[0], pos[2]) < pos[1]
 }
 
@@ -45,9 +46,10 @@ func (w weather) RainingAt(pos cube.Pos) bool {
 	if b := w.w.Biome(pos); b.Rainfall() == 0 || w.w.Temperature(pos) <= 0.15 {
 		return false
 	}
-	w.w.set.Lock()
-	a := w.w.set.Raining
-	w.w.set.Unlock()
+	set := w.w.settings()
+	set.Lock()
+	a := set.Raining
+	set.Unlock()
 	return a && w.w.highestObstructingBlock(pos[0], pos[2]) < pos[1]
 }
 
@@ -55,29 +57,32 @@ func (w weather) RainingAt(pos cube.Pos) bool {
 // true and if it is thundering in the world.
 func (w weather) ThunderingAt(pos cube.Pos) bool {
 	raining := w.RainingAt(pos)
-	w.w.set.Lock()
-	a := w.w.set.Thundering && raining
-	w.w.set.Unlock()
+	set := w.w.settings()
+	set.Lock()
+	a := set.Thundering && raining
+	set.Unlock()
 	return a && w.w.highestObstructingBlock(pos[0], pos[2]) < pos[1]
 }
 
 // StartRaining makes it rain in the World. The time.Duration passed will determine how long it will rain.
 func (w weather) StartRaining(dur time.Duration) {
-	w.w.set.Lock()
-	defer w.w.set.Unlock()
-	w.setRaining(true, dur)
+	set := w.w.settings()
+	set.Lock()
+	defer set.Unlock()
+	w.setRaining(set, true, dur)
 }
 
 // StopRaining makes it stop raining in the World.
 func (w weather) StopRaining() {
-	w.w.set.Lock()
-	defer w.w.set.Unlock()
+	set := w.w.settings()
+	set.Lock()
+	defer set.Unlock()
 
-	if w.w.set.Raining {
-		w.setRaining(false, time.Second*(time.Duration(w.w.r.Intn(8400)+600)))
-		if w.w.set.Thundering {
+	if set.Raining {
+		w.setRaining(set, false, time.Second*(time.Duration(w.w.r.Intn(8400)+600)))
+		if set.Thundering {
 			// Also reset thunder if it was previously thundering.
-			w.setThunder(false, time.Second*(time.Duration(w.w.r.Intn(8400)+600)))
+			w.setThunder(set, false, time.Second*(time.Duration(w.w.r.Intn(8400)+600)))
 		}
 	}
 }
@@ -86,72 +91,69 @@ func (w weather) StopRaining() {
 // StartThundering will also make it rain if it wasn't already raining. In this case the rain will, like the thunder,
 // last for the time.Duration passed.
 func (w weather) StartThundering(dur time.Duration) {
-	w.w.set.Lock()
-	defer w.w.set.Unlock()
+	set := w.w.settings()
+	set.Lock()
+	defer set.Unlock()
 
-	w.setThunder(true, dur)
-	w.setRaining(true, dur)
+	w.setThunder(set, true, dur)
+	w.setRaining(set, true, dur)
 }
 
 // StopThundering makes it stop thundering in the current world.
 func (w weather) StopThundering() {
-	w.w.set.Lock()
-	defer w.w.set.Unlock()
-	if w.w.set.Thundering && w.w.set.Raining {
-		w.setThunder(false, time.Second*(time.Duration(w.w.r.Intn(8400)+600)))
+	set := w.w.settings()
+	set.Lock()
+	defer set.Unlock()
+	if set.Thundering && set.Raining {
+		w.setThunder(set, false, time.Second*(time.Duration(w.w.r.Intn(8400)+600)))
 	}
 }
 
-// advanceWeather advances the weather counters of the World. Rain and thunder are stopped/started when the rain and
-// thunder times reach 0.
-func (w weather) advanceWeather() {
-	w.w.set.RainTime--
-	w.w.set.ThunderTime--
+// advanceWeather advances the weather counters of the Settings passed, which must be locked. Rain and thunder are
+// stopped/started when the rain and thunder times reach 0.
+func (w weather) advanceWeather(set *Settings) {
+	set.RainTime--
+	set.ThunderTime--
 
-	if w.w.set.RainTime <= 0 {
+	if set.RainTime <= 0 {
 		// Wiki: The rain counter counts down to zero, and each time it reaches zero, the rain is toggled on or off.
 		// When the rain is turned on, the counter is reset to a value between 12,000-23,999 ticks (0.5-1 game days)
 		// and when the rain is turned off it is reset to a value of 12,000-179,999 ticks (0.5-7.5 game days).
-		if w.w.set.Raining {
-			w.w.setRaining(false, time.Second*(time.Duration(w.w.r.Intn(8400)+600)))
+		if set.Raining {
+			w.setRaining(set, false, time.Second*(time.Duration(w.w.r.Intn(8400)+600)))
 		} else {
-			w.w.setRaining(true, time.Second*time.Duration(w.w.r.Intn(600)+600))
+			w.setRaining(set, true, time.Second*time.Duration(w.w.r.Intn(600)+600))
 		}
 	}
-	if w.w.set.ThunderTime <= 0 {
+	if set.ThunderTime <= 0 {
 		// Wiki: the thunder counter toggles thunder on/off when it reaches zero, but clear weather overrides the
 		// "on" state. When thunder is turned on, the thunder counter is reset to 3,600-15,999 ticks (3-13 minutes),
 		// and when thunder is turned off the counter rests to 12,000-179,999 ticks (0.5-7.5 days).
-		if w.w.set.Thundering {
-			w.w.setThunder(false, time.Second*(time.Duration(w.w.r.Intn(8400)+600)))
+		if set.Thundering {
+			w.setThunder(set, false, time.Second*(time.Duration(w.w.r.Intn(8400)+600)))
 		} else {
-			w.w.setThunder(true, time.Second*time.Duration(w.w.r.Intn(620)+180))
+			w.setThunder(set, true, time.Second*time.Duration(w.w.r.Intn(620)+180))
 		}
 	}
 }
 
-// setRaining toggles raining depending on the raining argument.
-// This does not lock the world mutex as opposed to StartRaining and StopRaining.
-func (w weather) setRaining(raining bool, x time.Duration) {
-	w.w.set.Raining = raining
-	w.w.set.RainTime = int64(x.Seconds() * 20)
+// setRaining toggles raining in the Settings passed depending on the raining argument.
+// This does not lock the Settings as opposed to StartRaining and StopRaining.
+func (w weather) setRaining(set *Settings, raining bool, x time.Duration) {
+	set.Raining = raining
+	set.RainTime = int64(x.Seconds() * 20)
 }
 
-// setThunder toggles thundering depending on the thundering argument.
-// This does not lock the world mutex as opposed to StartThundering and StopThundering.
-func (w weather) setThunder(thundering bool, x time.Duration) {
-	w.w.set.Thundering = thundering
-	w.w.set.ThunderTime = int64(x.Seconds() * 20)
+// setThunder toggles thundering in the Settings passed depending on the thundering argument.
+// This does not lock the Settings as opposed to StartThundering and StopThundering.
+func (w weather) setThunder(set *Settings, thundering bool, x time.Duration) {
+	set.Thundering = thundering
+	set.ThunderTime = int64(x.Seconds() * 20)
 }
 
 // enableWeatherCycle either enables or disables the weather cycle of the World.
 func (w weather) enableWeatherCycle(v bool) {
-	if w.w == nil {
-		return
-	}
-	w.w.set.Lock()
-	defer w.w.set.Unlock()
-	w.w.set.WeatherCycle = v
+	_ = w.w.SetGameRule(GameRuleDoWeatherCycle, v)
 }
 
 // tickLightning iterates over all loaded chunks in the World, striking lightning in each one with a 1/100,000 chance.
@@ -2,16 +2,18 @@ package world
 
 import (
 	"errors"
+	"fmt"
+	"github.com/df-mc/atomic"
 	"github.com/df-mc/goleveldb/leveldb"
 	"math/rand"
 	"sync"
 	"time"
 
-	"github.com/df-mc/atomic"
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/internal/sliceutil"
 	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/df-mc/dragonfly/server/world/noise"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/google/uuid"
 	"golang.org/x/exp/maps"
@@ -22,18 +24,28 @@ import (
 // entities and particles.
 // World generally provides a synchronised state: All entities, blocks and players usually operate in this
 // world, so World ensures that all its methods will always be safe for simultaneous calls.
+// Goroutines that are not part of the server, such as those of plugins, may use World.Exec to run code on the
+// goroutine that ticks the World, and World.Snapshot to read its state without locking.
 // A nil *World is safe to use but not functional.
 type World struct {
 	conf Config
 	ra   cube.Range
-	// advance is a bool that specifies if this World should advance the current tick, time and weather saved in the
-	// Settings struct held by the World.
-	advance bool
 
 	o sync.Once
 
-	set     *Settings
-	handler atomic.Value[Handler]
+	// set holds the Settings of the World. They may be shared with other worlds, such as other dimensions, of which
+	// only one advances the current tick, time and weather saved in them. set is replaced by SetProvider.
+	set atomic.Value[*Settings]
+	// prov holds the Provider of the World. It is initially set to Config.Provider and may be changed using
+	// SetProvider. provMu serialises calls to SetProvider.
+	prov   atomic.Value[Provider]
+	provMu sync.Mutex
+	// handler holds the Handlers of the World, including the Handler set using Handle. handlerMu guards h, the
+	// Handler set using Handle, and removeHandler, which removes it from handler again.
+	handler       *bus
+	handlerMu     sync.Mutex
+	h             Handler
+	removeHandler func()
 
 	weather
 	ticker
@@ -56,6 +68,16 @@ type World struct {
 
 	r *rand.Rand
 
+	// tickWork holds the time spent ticking the World and loading new chunks during the current tick.
+	// tickLoaded is true if a new chunk was loaded during the current tick. Both are reset at the start of every
+	// tick and are used to throttle chunk loading according to Config.TickBudget.
+	tickWork   atomic.Duration
+	tickLoaded atomic.Bool
+	// ticks, tickTime and lastTick hold the number of ticks performed, the total time spent on them and the time
+	// spent on the last tick. They are returned by World.Stats.
+	ticks              atomic.Uint64
+	tickTime, lastTick atomic.Duration
+
 	updateMu sync.Mutex
 	// scheduledUpdates is a map of tick time values indexed by the block position at which an update is
 	// scheduled. If the current tick exceeds the tick value passed, the block update will be performed
@@ -65,6 +87,32 @@ type World struct {
 
 	viewersMu sync.Mutex
 	viewers   map[*Loader]Viewer
+
+	spawnMu    sync.Mutex
+	spawnRules SpawnRules
+	// spawned holds the entities that were spawned naturally by the World and their SpawnCategory. Only these
+	// entities are counted towards the mob caps and are despawned when too far away from players.
+	spawned map[Entity]SpawnCategory
+
+	// execMu guards execQueue, which holds the functions queued using Exec, and execClosed, which is true once the
+	// World no longer accepts new functions.
+	execMu     sync.Mutex
+	execQueue  []execTask
+	execClosed bool
+	// snapshot holds the Snapshot taken at the end of the last tick.
+	snapshot atomic.Value[*Snapshot]
+	// timings holds the time spent ticking each type of block and entity, if enabled using EnableTimings.
+	timings timings
+	// spawnProtection holds the radius of the area around the spawn of the World protected from building. It is
+	// initially set to Config.SpawnProtection.
+	spawnProtection atomic.Int32
+	// rules holds the Rules of the World. It is initially set to Config.Rules.
+	rules atomic.Value[Rules]
+	// sleepTicks is the number of ticks for which all Sleepers in the World have been sleeping. It is only used by
+	// the ticker.
+	sleepTicks int
+	// encoded holds the network encoded sub chunks and biomes of loaded chunks, shared between all viewers.
+	encoded encodeCache
 }
 
 // New creates a new initialised world. The world may be used right away, but it will not be saved or loaded
@@ -79,9 +127,10 @@ func New() *World {
 // in the pause screen in-game.
 // If a provider is set, the name will be updated according to the name that it provides.
 func (w *World) Name() string {
-	w.set.Lock()
-	defer w.set.Unlock()
-	return w.set.Name
+	set := w.settings()
+	set.Lock()
+	defer set.Unlock()
+	return set.Name
 }
 
 // Dimension returns the Dimension assigned to the World in world.New. The sky colour and behaviour of a variety of
@@ -101,6 +150,43 @@ func (w *World) Range() cube.Range {
 	return w.ra
 }
 
+// Rand returns the rand.Rand of the World. All random numbers it produces are derived from the RandSource set in the
+// Config of the World, so that a World with a seeded RandSource behaves deterministically. The rand.Rand returned is
+// safe for concurrent use.
+func (w *World) Rand() *rand.Rand {
+	if w == nil {
+		return nopRand
+	}
+	return w.r
+}
+
+// Seed returns the seed of the World, as set in Config.Seed.
+func (w *World) Seed() int64 {
+	if w == nil {
+		return 0
+	}
+	return w.conf.Seed
+}
+
+// PositionRand returns a rand.Rand of which the values are derived from the seed of the World, the position and the
+// salt passed. The same position and salt always produce the same values in a World with the same seed, regardless
+// of the order in which positions are visited, which makes it suitable for decorating chunks as they are generated.
+// The salt is typically unique for every feature, so that features at the same position do not use the same random
+// numbers. The rand.Rand returned is not safe for concurrent use.
+func (w *World) PositionRand(pos cube.Pos, salt int64) *rand.Rand {
+	return rand.New(noise.PositionalSource(w.Seed(), pos[0], pos[1], pos[2], salt))
+}
+
+// RandomTickSpeed returns the amount of blocks randomly ticked per sub chunk every tick in the World. It is the value
+// of Config.RandomTickSpeed multiplied by the value of GameRuleRandomTickSpeed. A value of 0 or lower means random
+// ticking is disabled.
+func (w *World) RandomTickSpeed() int {
+	if w == nil {
+		return 0
+	}
+	return w.conf.RandomTickSpeed * w.IntGameRule(GameRuleRandomTickSpeed)
+}
+
 // EntityRegistry returns the EntityRegistry that was passed to the World's
 // Config upon construction.
 func (w *World) EntityRegistry() EntityRegistry {
@@ -248,6 +334,7 @@ func (w *World) SetBlock(pos cube.Pos, b Block, opts *SetOpts) {
 
 	c.modified = true
 	c.SetBlock(x, y, z, 0, rid)
+	c.invalidateCollisions(pos[1])
 	if nbtBlocks[rid] {
 		c.BlockEntities[pos] = b
 	} else {
@@ -284,6 +371,7 @@ func (w *World) SetBlock(pos cube.Pos, b Block, opts *SetOpts) {
 		c.Unlock()
 	}
 
+	w.invalidateNeighbourCollisions(pos)
 	for _, viewer := range viewers {
 		viewer.ViewBlockUpdate(pos, b, 0)
 	}
@@ -293,18 +381,98 @@ func (w *World) SetBlock(pos cube.Pos, b Block, opts *SetOpts) {
 	}
 }
 
-// SetBiome sets the biome at the position passed. If a chunk is not yet loaded at that position, the chunk is
-// first loaded or generated if it could not be found in the world save.
-func (w *World) SetBiome(pos cube.Pos, b Biome) {
+// BlockEntityChanged marks the chunk of the block entity at the position passed as changed, so that it is written
+// to the Provider of the World the next time it is saved. It should be called when the state of a block entity
+// changes without a call to SetBlock, such as when the items in the inventory of a container change.
+func (w *World) BlockEntityChanged(pos cube.Pos) {
 	if w == nil || pos.OutOfBounds(w.Range()) {
-		// Fast way out.
 		return
 	}
 	c := w.chunk(chunkPosFromBlockPos(pos))
-	defer c.Unlock()
+	if _, ok := c.BlockEntities[pos]; ok {
+		c.modified = true
+	}
+	c.Unlock()
+}
 
-	c.modified = true
-	c.SetBiome(uint8(pos[0]), int16(pos[1]), uint8(pos[2]), uint32(b.EncodeBiome()))
+// SetBiome sets the biome at the position passed. Biomes are stored per block, so different Y values in the same
+// column may have different biomes, such as for cave biomes. If a chunk is not yet loaded at that position, the
+// chunk is first loaded or generated if it could not be found in the world save.
+// The chunk is sent to its viewers again after every call to SetBiome, so SetBiomes should be used to change the
+// biome of many blocks at once.
+func (w *World) SetBiome(pos cube.Pos, b Biome) {
+	w.SetBiomes(pos, pos, b)
+}
+
+// SetBiomes sets the biome of all blocks in the box spanned by the positions a and b, including both positions.
+// Chunks that are not yet loaded are first loaded or generated if they could not be found in the world save. Every
+// chunk changed is sent to its viewers once, after the biomes in it were changed.
+func (w *World) SetBiomes(a, b cube.Pos, biome Biome) {
+	if w == nil {
+		return
+	}
+	for i := range a {
+		if a[i] > b[i] {
+			a[i], b[i] = b[i], a[i]
+		}
+	}
+	r := w.Range()
+	if a[1] < r.Min() {
+		a[1] = r.Min()
+	}
+	if b[1] > r.Max() {
+		b[1] = r.Max()
+	}
+	if a[1] > b[1] {
+		// Fast way out.
+		return
+	}
+	id := uint32(biome.EncodeBiome())
+	for cx := a[0] >> 4; cx <= b[0]>>4; cx++ {
+		for cz := a[2] >> 4; cz <= b[2]>>4; cz++ {
+			chunkPos := ChunkPos{int32(cx), int32(cz)}
+			minX, maxX := maxInt(a[0], cx<<4), minInt(b[0], cx<<4+15)
+			minZ, maxZ := maxInt(a[2], cz<<4), minInt(b[2], cz<<4+15)
+			// If the box covers the entire chunk horizontally, sub chunks covered vertically are filled at once.
+			full := minX&15 == 0 && maxX&15 == 15 && minZ&15 == 0 && maxZ&15 == 15
+
+			c := w.chunk(chunkPos)
+			for y := a[1]; y <= b[1]; {
+				if full && y&15 == 0 && y+15 <= b[1] {
+					c.FillBiome(c.SubIndex(int16(y)), id)
+					y += 16
+					continue
+				}
+				for x := minX; x <= maxX; x++ {
+					for z := minZ; z <= maxZ; z++ {
+						c.Chunk.SetBiome(uint8(x), int16(y), uint8(z), id)
+					}
+				}
+				y++
+			}
+			c.modified = true
+			for _, viewer := range c.viewers {
+				viewer.ViewChunk(chunkPos, c.Chunk, c.BlockEntities)
+			}
+			c.Unlock()
+		}
+	}
+}
+
+// minInt returns the smaller of the two ints passed.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// maxInt returns the larger of the two ints passed.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
 }
 
 // BuildStructure builds a Structure passed at a specific position in the world. Unlike SetBlock, it takes a
@@ -388,6 +556,7 @@ func (w *World) BuildStructure(pos cube.Pos, s Structure) {
 			}
 			c.SetBlock(0, 0, 0, 0, c.Block(0, 0, 0, 0)) // Make sure the heightmap is recalculated.
 			c.modified = true
+			c.clearCollisions()
 
 			// After setting all blocks of the structure within a single chunk, we show the new chunk to all
 			// viewers once, and unlock it.
@@ -397,6 +566,29 @@ func (w *World) BuildStructure(pos cube.Pos, s Structure) {
 			c.Unlock()
 		}
 	}
+	// Blocks next to the structure may connect to it, so their collision boxes must also be computed again.
+	w.clearCollisionsAround(ChunkPos{int32(pos[0] >> 4), int32(pos[2] >> 4)}, ChunkPos{int32(maxX >> 4), int32(maxZ >> 4)})
+}
+
+// ResendBlock sends the blocks currently at the position passed, on both layers, to the Viewer passed. ResendBlock
+// may be used to correct the blocks shown to a viewer after an action that it predicted, such as placing or breaking
+// a block, was rejected, so that the viewer does not keep seeing a block that does not exist.
+func (w *World) ResendBlock(pos cube.Pos, v Viewer) {
+	if w == nil || pos.OutOfBounds(w.Range()) {
+		return
+	}
+	b := w.Block(pos)
+
+	c := w.chunk(chunkPosFromBlockPos(pos))
+	id := c.Block(uint8(pos[0]), int16(pos[1]), uint8(pos[2]), 1)
+	c.Unlock()
+
+	secondLayer, ok := BlockByRuntimeID(id)
+	if !ok {
+		secondLayer = air()
+	}
+	v.ViewBlockUpdate(pos, b, 0)
+	v.ViewBlockUpdate(pos, secondLayer, 1)
 }
 
 // Liquid attempts to return any liquid block at the position passed. This liquid may be in the foreground or
@@ -456,6 +648,7 @@ func (w *World) SetLiquid(pos cube.Pos, b Liquid) {
 		}
 	}
 	rid := BlockRuntimeID(b)
+	c.invalidateCollisions(pos[1])
 	if w.removeLiquids(c, pos) {
 		c.SetBlock(x, y, z, 0, rid)
 		for _, v := range c.viewers {
@@ -567,14 +760,50 @@ func (w *World) SkyLight(pos cube.Pos) uint8 {
 }
 
 // Time returns the current time of the world. The time is incremented every 1/20th of a second, unless
-// World.StopTime() is called.
+// World.StopTime() is called or GameRuleDoDaylightCycle is otherwise disabled, and may be changed using SetTime.
+// Time decides if it is day or night in the world, see DayTime. Use GameTime for a clock that cannot be stopped.
 func (w *World) Time() int {
 	if w == nil {
 		return 0
 	}
-	w.set.Lock()
-	defer w.set.Unlock()
-	return int(w.set.Time)
+	set := w.settings()
+	set.Lock()
+	defer set.Unlock()
+	return int(set.Time)
+}
+
+// DayTime returns the time within the current day of the world, ranging from 0 to 23999. A value of 0 is sunrise,
+// 6000 is noon, 12000 is sunset and 18000 is midnight.
+func (w *World) DayTime() int {
+	t := w.Time() % 24000
+	if t < 0 {
+		t += 24000
+	}
+	return t
+}
+
+// Day returns the number of days that have passed in the world, based on its Time. Day is negative if the Time of
+// the world is negative.
+func (w *World) Day() int {
+	t := w.Time()
+	if t < 0 {
+		// Round towards negative infinity, so that the days before day 0 are numbered consistently with DayTime.
+		return (t - 23999) / 24000
+	}
+	return t / 24000
+}
+
+// GameTime returns the game time of the world, which is the total amount of ticks that the world has been ticked
+// for. Unlike Time, the game time advances every tick regardless of GameRuleDoDaylightCycle and cannot be changed.
+// Scheduled block updates and Tickers are timed using the game time.
+func (w *World) GameTime() int64 {
+	if w == nil {
+		return 0
+	}
+	set := w.settings()
+	set.Lock()
+	defer set.Unlock()
+	return set.CurrentTick
 }
 
 // SetTime sets the new time of the world. SetTime will always work, regardless of whether the time is stopped
@@ -583,9 +812,10 @@ func (w *World) SetTime(new int) {
 	if w == nil {
 		return
 	}
-	w.set.Lock()
-	w.set.Time = int64(new)
-	w.set.Unlock()
+	set := w.settings()
+	set.Lock()
+	set.Time = int64(new)
+	set.Unlock()
 
 	viewers, _ := w.allViewers()
 	for _, viewer := range viewers {
@@ -609,12 +839,7 @@ func (w *World) StartTime() {
 
 // enableTimeCycle enables or disables the time cycling of the World.
 func (w *World) enableTimeCycle(v bool) {
-	if w == nil {
-		return
-	}
-	w.set.Lock()
-	defer w.set.Unlock()
-	w.set.TimeCycle = v
+	_ = w.SetGameRule(GameRuleDoDaylightCycle, v)
 }
 
 // Temperature returns the temperature in the World at a specific position. Higher altitudes and different biomes
@@ -631,30 +856,67 @@ func (w *World) Temperature(pos cube.Pos) float64 {
 	return w.Biome(pos).Temperature() - float64(diff)*tempDrop
 }
 
-// AddParticle spawns a particle at a given position in the world. Viewers that are viewing the chunk will be
-// shown the particle.
+// LoadChunks loads the chunks within the radius passed, in chunks, around the position passed, generating them if
+// they were not yet generated. LoadChunks may be used to load the chunks around a position before moving an entity
+// there, so that the entity does not end up in chunks that are not yet loaded. Chunks loaded are unloaded again once
+// they are no longer viewed.
+func (w *World) LoadChunks(pos mgl64.Vec3, radius int) {
+	if w == nil {
+		return
+	}
+	centre := chunkPosFromVec3(pos)
+	for x := centre[0] - int32(radius); x <= centre[0]+int32(radius); x++ {
+		for z := centre[1] - int32(radius); z <= centre[1]+int32(radius); z++ {
+			w.chunk(ChunkPos{x, z}).Unlock()
+		}
+	}
+}
+
+// AddParticle spawns a particle at a given position in the world. Viewers that are viewing the chunk and are within
+// EffectRange of the position will be shown the particle. Player.ShowParticle may be used to show a particle to a
+// single player instead.
 func (w *World) AddParticle(pos mgl64.Vec3, p Particle) {
 	if w == nil {
 		return
 	}
 	p.Spawn(w, pos)
-	for _, viewer := range w.Viewers(pos) {
+	for _, viewer := range w.viewersWithin(pos, EffectRange) {
 		viewer.ViewParticle(pos, p)
 	}
 }
 
 // PlaySound plays a sound at a specific position in the world. Viewers of that position will be able to hear
-// the sound if they're close enough.
+// the sound if they're within EffectRange, or the range returned by the sound if it implements RangedSound.
+// Player.PlaySoundAt may be used to play a sound to a single player instead.
 func (w *World) PlaySound(pos mgl64.Vec3, s Sound) {
 	ctx := event.C()
-	if w.Handler().HandleSound(ctx, s, pos); ctx.Cancelled() {
+	if w.handler.HandleSound(ctx, s, pos); ctx.Cancelled() {
 		return
 	}
-	for _, viewer := range w.Viewers(pos) {
+	r := EffectRange
+	if ranged, ok := s.(RangedSound); ok {
+		r = ranged.Range()
+	}
+	for _, viewer := range w.viewersWithin(pos, r) {
 		viewer.ViewSound(pos, s)
 	}
 }
 
+// viewersWithin returns the viewers of the position passed that are within a distance r of it. Viewers that do
+// not implement PositionedViewer are always returned.
+func (w *World) viewersWithin(pos mgl64.Vec3, r float64) []Viewer {
+	viewers := w.Viewers(pos)
+	n := 0
+	for _, v := range viewers {
+		if pv, ok := v.(PositionedViewer); ok && pv.Position().Sub(pos).Len() > r {
+			continue
+		}
+		viewers[n] = v
+		n++
+	}
+	return viewers[:n]
+}
+
 var (
 	worldsMu sync.RWMutex
 	// entityWorlds holds a list of all entities added to a world. It may be used to look up the world that an
@@ -682,7 +944,7 @@ func (w *World) AddEntity(e Entity) {
 	w.entityMu.Unlock()
 
 	c := w.chunk(chunkPos)
-	c.Entities = append(c.Entities, e)
+	c.addEntity(e)
 	viewers := slices.Clone(c.viewers)
 	c.Unlock()
 
@@ -691,7 +953,7 @@ func (w *World) AddEntity(e Entity) {
 		showEntity(e, v)
 	}
 
-	w.Handler().HandleEntitySpawn(e)
+	w.handler.HandleEntitySpawn(e)
 }
 
 // add maps an Entity to a World in the entityWorlds map.
@@ -719,7 +981,7 @@ func (w *World) RemoveEntity(e Entity) {
 		return
 	}
 
-	w.Handler().HandleEntityDespawn(e)
+	w.handler.HandleEntityDespawn(e)
 
 	worldsMu.Lock()
 	delete(entityWorlds, e)
@@ -730,7 +992,7 @@ func (w *World) RemoveEntity(e Entity) {
 		// The chunk wasn't loaded, so we can't remove any entity from the chunk.
 		return
 	}
-	c.Entities = sliceutil.DeleteVal(c.Entities, e)
+	c.removeEntity(e)
 	viewers := slices.Clone(c.viewers)
 	c.Unlock()
 
@@ -807,9 +1069,10 @@ func (w *World) Spawn() cube.Pos {
 	if w == nil {
 		return cube.Pos{}
 	}
-	w.set.Lock()
-	s := w.set.Spawn
-	w.set.Unlock()
+	set := w.settings()
+	set.Lock()
+	s := set.Spawn
+	set.Unlock()
 	if s[1] > w.Range()[1] {
 		s[1] = w.highestObstructingBlock(s[0], s[2]) + 1
 	}
@@ -822,9 +1085,10 @@ func (w *World) SetSpawn(pos cube.Pos) {
 	if w == nil {
 		return
 	}
-	w.set.Lock()
-	w.set.Spawn = pos
-	w.set.Unlock()
+	set := w.settings()
+	set.Lock()
+	set.Spawn = pos
+	set.Unlock()
 
 	viewers, _ := w.allViewers()
 	for _, viewer := range viewers {
@@ -832,12 +1096,47 @@ func (w *World) SetSpawn(pos cube.Pos) {
 	}
 }
 
+// SpawnProtection returns the radius in blocks of the area around the spawn of the World protected from building.
+// A radius of 0 or lower means the spawn is not protected. See Config.SpawnProtection.
+func (w *World) SpawnProtection() int {
+	if w == nil {
+		return 0
+	}
+	return int(w.spawnProtection.Load())
+}
+
+// SetSpawnProtection changes the radius in blocks of the area around the spawn of the World protected from
+// building. Setting a radius of 0 or lower disables spawn protection.
+func (w *World) SetSpawnProtection(radius int) {
+	if w == nil {
+		return
+	}
+	w.spawnProtection.Store(int32(radius))
+}
+
+// SpawnProtected checks if the position passed lies within the area around the spawn of the World protected from
+// building, as set using Config.SpawnProtection or SetSpawnProtection. Only the horizontal distance from the spawn
+// is taken into account.
+func (w *World) SpawnProtected(pos cube.Pos) bool {
+	r := w.SpawnProtection()
+	if r <= 0 {
+		return false
+	}
+	set := w.settings()
+	set.Lock()
+	spawn := set.Spawn
+	set.Unlock()
+
+	dx, dz := pos[0]-spawn[0], pos[2]-spawn[2]
+	return dx >= -r && dx <= r && dz >= -r && dz <= r
+}
+
 // PlayerSpawn returns the spawn position of a player with a UUID in this World.
 func (w *World) PlayerSpawn(uuid uuid.UUID) cube.Pos {
 	if w == nil {
 		return cube.Pos{}
 	}
-	pos, exist, err := w.conf.Provider.LoadPlayerSpawnPosition(uuid)
+	pos, exist, err := w.provider().LoadPlayerSpawnPosition(uuid)
 	if err != nil {
 		w.conf.Log.Errorf("failed to get player spawn: %v", err)
 		return w.Spawn()
@@ -854,7 +1153,7 @@ func (w *World) SetPlayerSpawn(uuid uuid.UUID, pos cube.Pos) {
 	if w == nil {
 		return
 	}
-	if err := w.conf.Provider.SavePlayerSpawnPosition(uuid, pos); err != nil {
+	if err := w.provider().SavePlayerSpawnPosition(uuid, pos); err != nil {
 		w.conf.Log.Errorf("failed to set player spawn: %v", err)
 	}
 }
@@ -866,9 +1165,10 @@ func (w *World) DefaultGameMode() GameMode {
 	if w == nil {
 		return GameModeSurvival
 	}
-	w.set.Lock()
-	defer w.set.Unlock()
-	return w.set.DefaultGameMode
+	set := w.settings()
+	set.Lock()
+	defer set.Unlock()
+	return set.DefaultGameMode
 }
 
 // SetTickRange sets the range in chunks around each Viewer that will have the chunks (their blocks and entities)
@@ -877,16 +1177,18 @@ func (w *World) SetTickRange(v int) {
 	if w == nil {
 		return
 	}
-	w.set.Lock()
-	defer w.set.Unlock()
-	w.set.TickRange = int32(v)
+	set := w.settings()
+	set.Lock()
+	defer set.Unlock()
+	set.TickRange = int32(v)
 }
 
 // tickRange returns the tick range around each Viewer.
 func (w *World) tickRange() int {
-	w.set.Lock()
-	defer w.set.Unlock()
-	return int(w.set.TickRange)
+	set := w.settings()
+	set.Lock()
+	defer set.Unlock()
+	return int(set.TickRange)
 }
 
 // SetDefaultGameMode changes the default game mode of the world. When players join, they are then given that
@@ -895,9 +1197,10 @@ func (w *World) SetDefaultGameMode(mode GameMode) {
 	if w == nil {
 		return
 	}
-	w.set.Lock()
-	defer w.set.Unlock()
-	w.set.DefaultGameMode = mode
+	set := w.settings()
+	set.Lock()
+	defer set.Unlock()
+	set.DefaultGameMode = mode
 }
 
 // Difficulty returns the difficulty of the world. Properties of mobs in the world and the player's hunger
@@ -906,19 +1209,26 @@ func (w *World) Difficulty() Difficulty {
 	if w == nil {
 		return DifficultyNormal
 	}
-	w.set.Lock()
-	defer w.set.Unlock()
-	return w.set.Difficulty
+	set := w.settings()
+	set.Lock()
+	defer set.Unlock()
+	return set.Difficulty
 }
 
-// SetDifficulty changes the difficulty of a world.
+// SetDifficulty changes the difficulty of a world and sends the new difficulty to all viewers of the world.
 func (w *World) SetDifficulty(d Difficulty) {
 	if w == nil {
 		return
 	}
-	w.set.Lock()
-	defer w.set.Unlock()
-	w.set.Difficulty = d
+	set := w.settings()
+	set.Lock()
+	set.Difficulty = d
+	set.Unlock()
+
+	viewers, _ := w.allViewers()
+	for _, viewer := range viewers {
+		viewer.ViewDifficulty(d)
+	}
 }
 
 // ScheduleBlockUpdate schedules a block update at the position passed after a specific delay. If the block at
@@ -932,11 +1242,7 @@ func (w *World) ScheduleBlockUpdate(pos cube.Pos, delay time.Duration) {
 	if _, exists := w.scheduledUpdates[pos]; exists {
 		return
 	}
-	w.set.Lock()
-	t := w.set.CurrentTick
-	w.set.Unlock()
-
-	w.scheduledUpdates[pos] = t + delay.Nanoseconds()/int64(time.Second/20)
+	w.scheduledUpdates[pos] = w.GameTime() + delay.Nanoseconds()/int64(time.Second/20)
 }
 
 // doBlockUpdatesAround schedules block updates directly around and on the position passed.
@@ -967,15 +1273,33 @@ func (w *World) updateNeighbour(pos, changedNeighbour cube.Pos) {
 
 // Handle changes the current Handler of the world. As a result, events called by the world will call
 // handlers of the Handler passed.
-// Handle sets the world's Handler to NopHandler if nil is passed.
+// The Handler is called with event.PriorityNormal, alongside the Handlers added using Subscribe.
+// Handle removes the current Handler of the world if nil is passed.
 func (w *World) Handle(h Handler) {
 	if w == nil {
 		return
 	}
-	if h == nil {
-		h = NopHandler{}
+	w.handlerMu.Lock()
+	defer w.handlerMu.Unlock()
+	if w.removeHandler != nil {
+		w.removeHandler()
+		w.removeHandler = nil
+	}
+	w.h = h
+	if h != nil {
+		w.removeHandler = w.handler.Add(h, event.PriorityNormal)
 	}
-	w.handler.Store(h)
+}
+
+// Subscribe adds a Handler to the world that is called for events of the world in addition to the Handler set
+// using Handle and any other Handlers subscribed. Handlers are called in the order of their event.Priority, so that
+// Handlers with a higher event.Priority may override the outcome of an event. The function returned removes the
+// Handler from the world again.
+func (w *World) Subscribe(h Handler, priority event.Priority) (unsubscribe func()) {
+	if w == nil {
+		return func() {}
+	}
+	return w.handler.Add(h, priority)
 }
 
 // Viewers returns a list of all viewers viewing the position passed. A viewer will be assumed to be watching
@@ -1016,11 +1340,12 @@ func (w *World) Close() error {
 // close stops the World from ticking, saves all chunks to the Provider and updates the world's settings.
 func (w *World) close() {
 	// Let user code run anything that needs to be finished before the World is closed.
-	w.Handler().HandleClose()
-	w.Handle(NopHandler{})
+	w.handler.HandleClose()
+	w.handler.Clear()
 
 	close(w.closing)
 	w.running.Wait()
+	w.runExec(true)
 
 	w.conf.Log.Debugf("Saving chunks in memory to disk...")
 
@@ -1034,15 +1359,16 @@ func (w *World) close() {
 		w.saveChunk(pos, c)
 	}
 
-	w.set.ref.Dec()
-	if !w.advance {
+	set := w.settings()
+	if !w.advancing() {
 		return
 	}
 
 	if !w.conf.ReadOnly {
 		w.conf.Log.Debugf("Updating level.dat values...")
 
-		w.provider().SaveSettings(w.set)
+		w.provider().SaveSettings(set)
+		w.saveGameRules(w.provider())
 	}
 
 	w.conf.Log.Debugf("Closing provider...")
@@ -1051,6 +1377,20 @@ func (w *World) close() {
 	}
 }
 
+// settings returns the Settings currently held by the World.
+func (w *World) settings() *Settings {
+	return w.set.Load()
+}
+
+// advancing checks if the World advances the Settings it holds, and if it is therefore responsible for saving them
+// to its Provider.
+func (w *World) advancing() bool {
+	set := w.settings()
+	set.Lock()
+	defer set.Unlock()
+	return set.advancer == w
+}
+
 // allViewers returns a list of all loaders of the world, regardless of where in the world they are viewing.
 func (w *World) allViewers() ([]Viewer, []*Loader) {
 	w.viewersMu.Lock()
@@ -1070,11 +1410,14 @@ func (w *World) addWorldViewer(l *Loader) {
 	w.viewers[l] = l.viewer
 	w.viewersMu.Unlock()
 	l.viewer.ViewTime(w.Time())
-	w.set.Lock()
-	raining, thundering := w.set.Raining, w.set.Raining && w.set.Thundering
-	w.set.Unlock()
+	set := w.settings()
+	set.Lock()
+	raining, thundering := set.Raining, set.Raining && set.Thundering
+	set.Unlock()
 	l.viewer.ViewWeather(raining, thundering)
 	l.viewer.ViewWorldSpawn(w.Spawn())
+	l.viewer.ViewGameRules(w.GameRules())
+	l.viewer.ViewDifficulty(w.Difficulty())
 }
 
 // removeWorldViewer removes a viewer from the world. Should only be used while the viewer isn't viewing any chunks.
@@ -1127,16 +1470,146 @@ func (w *World) removeViewer(pos ChunkPos, loader *Loader) {
 // provider returns the provider of the world. It should always be used, rather than direct field access, in
 // order to provide synchronisation safety.
 func (w *World) provider() Provider {
-	return w.conf.Provider
+	return w.prov.Load()
+}
+
+// SetProvider changes the Provider of the World while it is running, for example to rotate the map of a World. All
+// chunks currently loaded are saved to the old Provider, unless the World is read-only, and are unloaded together
+// with the entities in them that can be saved. Entities that cannot be saved, such as players, remain in the World.
+// The settings of the World, such as its spawn position, are saved to the old Provider and replaced with a copy of
+// those of the new Provider. The World stops sharing its Settings with other worlds, such as other dimensions of the
+// old Provider, which keep using the old Settings. Finally, all viewers of the World are sent the chunks of the new
+// Provider.
+// The old Provider is returned and is not closed, so that it may be closed or used again later. If nil is passed,
+// the World will use a NopProvider.
+func (w *World) SetProvider(p Provider) (old Provider) {
+	if w == nil {
+		return nil
+	}
+	if p == nil {
+		p = NopProvider{}
+	}
+	w.provMu.Lock()
+	defer w.provMu.Unlock()
+
+	// Stop all loaders from viewing the World while the Provider is changed, so that no chunks of the old Provider
+	// are loaded again in the meantime.
+	_, loaders := w.allViewers()
+	for _, l := range loaders {
+		l.mu.Lock()
+		l.reset()
+	}
+
+	old = w.prov.Swap(p)
+	w.chunkMu.Lock()
+	w.lastChunk = nil
+	columns := maps.Clone(w.chunks)
+	maps.Clear(w.chunks)
+	w.chunkMu.Unlock()
+
+	w.updateMu.Lock()
+	maps.Clear(w.scheduledUpdates)
+	w.neighbourUpdates = nil
+	w.updateMu.Unlock()
+
+	var kept []Entity
+	for pos, c := range columns {
+		c.Lock()
+		saveable := make([]Entity, 0, len(c.Entities))
+		for _, e := range c.Entities {
+			if _, ok := e.Type().(SaveableEntityType); ok {
+				saveable = append(saveable, e)
+				continue
+			}
+			kept = append(kept, e)
+		}
+		c.Entities = saveable
+		if !w.conf.ReadOnly && (len(c.BlockEntities) > 0 || len(c.Entities) > 0 || c.modified) {
+			c.Compact()
+			if err := old.StoreColumn(pos, w.conf.Dim, c); err != nil {
+				w.chunkLog(pos).Errorf("set provider: save chunk: %v", err)
+			}
+		}
+		c.Entities = nil
+		c.Unlock()
+
+		// The saved entities are closed before the chunk is freed, so that they never outlive the chunk they were
+		// saved with.
+		for _, e := range saveable {
+			_ = e.Close()
+			w.entityMu.Lock()
+			delete(w.entities, e)
+			w.entityMu.Unlock()
+		}
+
+		c.Lock()
+		c.Chunk.Free()
+		c.unloaded = true
+		w.encoded.forget(pos)
+		c.Unlock()
+	}
+
+	prev := w.settings()
+	if w.advancing() && !w.conf.ReadOnly {
+		prev.Lock()
+		old.SaveSettings(prev)
+		prev.Unlock()
+		w.saveGameRules(old)
+	}
+	// The World gets its own copy of the Settings of the new Provider, so that other worlds sharing the previous
+	// Settings are not affected. The current tick is kept, as it only ever goes up.
+	set := &Settings{advancer: w}
+	set.copyFrom(p.Settings())
+	prev.Lock()
+	set.CurrentTick = prev.CurrentTick
+	if prev.advancer == w {
+		prev.advancer = nil
+	}
+	prev.Unlock()
+	w.set.Store(set)
+	w.loadGameRules(p)
+
+	// Entities that remain in the World are moved into the chunks of the new Provider.
+	for _, e := range kept {
+		pos := chunkPosFromVec3(e.Position())
+		w.entityMu.Lock()
+		w.entities[e] = pos
+		w.entityMu.Unlock()
+
+		c := w.chunk(pos)
+		c.addEntity(e)
+		c.Unlock()
+	}
+	for _, l := range loaders {
+		l.w.addWorldViewer(l)
+		l.populateLoadQueue()
+		l.mu.Unlock()
+	}
+	return old
 }
 
-// Handler returns the Handler of the world. It should always be used, rather than direct field access, in
-// order to provide synchronisation safety.
+// Handler returns the Handler of the world set using Handle. NopHandler is returned if no Handler was set. Handlers
+// added using Subscribe are not returned.
 func (w *World) Handler() Handler {
 	if w == nil {
 		return NopHandler{}
 	}
-	return w.handler.Load()
+	w.handlerMu.Lock()
+	defer w.handlerMu.Unlock()
+	if w.h == nil {
+		return NopHandler{}
+	}
+	return w.h
+}
+
+// Events returns a Handler that passes the events called on it on to the Handler set using Handle and all Handlers
+// added using Subscribe, in the order of their event.Priority. Events of the world, such as those called by blocks
+// and entities, should be called on the Handler returned.
+func (w *World) Events() Handler {
+	if w == nil {
+		return NopHandler{}
+	}
+	return w.handler
 }
 
 // chunkFromCache attempts to fetch a chunk at the chunk position passed from the cache. If not found, the
@@ -1180,7 +1653,7 @@ func (w *World) chunk(pos ChunkPos) *Column {
 		chunk.LightArea([]*chunk.Chunk{c.Chunk}, int(pos[0]), int(pos[1])).Fill()
 		if err != nil {
 			w.chunkMu.Unlock()
-			w.conf.Log.Errorf("load chunk: failed loading %v: %v\n", pos, err)
+			w.chunkLog(pos).Errorf("load chunk: %v", err)
 			return c
 		}
 		c.Unlock()
@@ -1195,6 +1668,30 @@ func (w *World) chunk(pos ChunkPos) *Column {
 	return c
 }
 
+// chunkWithinBudget returns the chunk at the position passed in the same way as chunk, but only if the chunk is
+// already in memory or if the Config.TickBudget of the current tick has not yet been used up. If the chunk could not
+// be loaded within the budget, nil and false are returned and the chunk should be requested again during a later
+// tick.
+func (w *World) chunkWithinBudget(pos ChunkPos) (*Column, bool) {
+	if w.conf.TickBudget < 0 {
+		return w.chunk(pos), true
+	}
+	w.chunkMu.Lock()
+	_, cached := w.chunks[pos]
+	w.chunkMu.Unlock()
+	if cached {
+		return w.chunk(pos), true
+	}
+	if w.tickLoaded.Load() && w.tickWork.Load() >= w.conf.TickBudget {
+		return nil, false
+	}
+	start := time.Now()
+	c := w.chunk(pos)
+	w.tickLoaded.Store(true)
+	w.tickWork.Add(time.Since(start))
+	return c, true
+}
+
 // setChunk sets the chunk.Chunk passed at a specific ChunkPos without replacing any entities at that
 // position.
 //
@@ -1210,6 +1707,12 @@ func (w *World) setChunk(pos ChunkPos, c *chunk.Chunk, e map[cube.Pos]Block) {
 	maps.Copy(col.BlockEntities, e)
 	if o, ok := w.chunks[pos]; ok {
 		col.viewers = o.viewers
+		o.Lock()
+		o.Chunk.Free()
+		o.Unlock()
+	}
+	if w.lastPos == pos {
+		w.lastChunk = nil
 	}
 	w.chunks[pos] = col
 }
@@ -1217,8 +1720,10 @@ func (w *World) setChunk(pos ChunkPos, c *chunk.Chunk, e map[cube.Pos]Block) {
 // loadChunk attempts to load a chunk from the provider, or generates a chunk if one doesn't currently exist.
 func (w *World) loadChunk(pos ChunkPos) (*Column, error) {
 	col, err := w.provider().LoadColumn(pos, w.conf.Dim)
+	var corrupt *CorruptColumnError
 	switch {
 	case err == nil:
+		col.Chunk.UseArena()
 		w.chunks[pos] = col
 		// Iterate through the entities twice and make sure they're added to all relevant maps. Note that this iteration
 		// happens twice to avoid having to lock both worldsMu and entityMu. This is intentional, to avoid deadlocks.
@@ -1237,9 +1742,14 @@ func (w *World) loadChunk(pos ChunkPos) (*Column, error) {
 		col.Lock()
 		w.chunkMu.Unlock()
 		return col, nil
-	case errors.Is(err, leveldb.ErrNotFound):
-		// The provider doesn't have a chunk saved at this position, so we generate a new one.
+	case errors.Is(err, leveldb.ErrNotFound), w.conf.RegenerateCorruptChunks && errors.As(err, &corrupt):
+		// The provider doesn't have a chunk saved at this position, or the chunk saved is corrupt and should be
+		// discarded, so we generate a new one.
+		if corrupt != nil {
+			w.chunkLog(pos).Errorf("load chunk: %v: generating chunk again", err)
+		}
 		col = newColumn(chunk.New(airRID, w.Range()))
+		col.Chunk.UseArena()
 		w.chunks[pos] = col
 
 		col.Lock()
@@ -1299,16 +1809,27 @@ func (w *World) spreadLight(pos ChunkPos) {
 	}
 }
 
+// chunkLog returns the Logger of the World with a field holding the ChunkPos passed, if the Logger supports fields.
+func (w *World) chunkLog(pos ChunkPos) Logger {
+	if l, ok := w.conf.Log.(fieldLogger); ok {
+		return l.WithField("chunk", pos)
+	}
+	return w.conf.Log
+}
+
 // saveChunk is called when a chunk is removed from the cache. We first compact the chunk, then we write it to
-// the provider.
+// the provider. The block storages of the chunk are freed afterwards, so the chunk must no longer be used.
 func (w *World) saveChunk(pos ChunkPos, c *Column) {
 	c.Lock()
 	if !w.conf.ReadOnly && (len(c.BlockEntities) > 0 || len(c.Entities) > 0 || c.modified) {
 		c.Compact()
 		if err := w.provider().StoreColumn(pos, w.conf.Dim, c); err != nil {
-			w.conf.Log.Errorf("save chunk: %v", err)
+			w.chunkLog(pos).Errorf("save chunk: %v", err)
 		}
 	}
+	c.Chunk.Free()
+	c.unloaded = true
+	w.encoded.forget(pos)
 	ent := c.Entities
 	c.Entities = nil
 	c.Unlock()
@@ -1344,7 +1865,10 @@ func (w *World) chunkCacheJanitor() {
 			w.chunkMu.Unlock()
 
 			for pos, c := range chunksToRemove {
-				w.saveChunk(pos, c)
+				pos, c := pos, c
+				w.conf.CrashReporter.Catch("world provider", func() map[string]any {
+					return map[string]any{"world": w.Name(), "dimension": fmt.Sprint(w.Dimension()), "chunk": pos}
+				}, func() { w.saveChunk(pos, c) })
 				delete(chunksToRemove, pos)
 			}
 		case <-w.closing:
@@ -1358,7 +1882,10 @@ func (w *World) chunkCacheJanitor() {
 // by the mutex present in the chunk.Chunk held.
 type Column struct {
 	sync.Mutex
-	modified bool
+	// modified is true if blocks, biomes or block entities in the Column were changed, or if saveable entities
+	// moved in or out of the Column, since it was loaded or last saved.
+	// unloaded is true once the Column was removed from the World and its chunk freed.
+	modified, unloaded bool
 
 	*chunk.Chunk
 	Entities      []Entity
@@ -1366,6 +1893,29 @@ type Column struct {
 
 	viewers []Viewer
 	loaders []*Loader
+
+	// collisions holds the collision boxes of blocks in sub chunks of the Column, by index of the sub chunk, as
+	// computed by World.BlockBBoxes. collisionGen is incremented every time blocks in the Column change.
+	collisions   map[int16]*collisionShapes
+	collisionGen uint64
+}
+
+// addEntity adds the Entity passed to the Column. If the Entity is saved with the Column, the Column is marked as
+// modified, so that the Entity is also saved with the Column.
+func (c *Column) addEntity(e Entity) {
+	c.Entities = append(c.Entities, e)
+	if _, ok := e.Type().(SaveableEntityType); ok {
+		c.modified = true
+	}
+}
+
+// removeEntity removes the Entity passed from the Column. If the Entity is saved with the Column, the Column is
+// marked as modified, so that the Entity is also removed from the Column once it is saved.
+func (c *Column) removeEntity(e Entity) {
+	c.Entities = sliceutil.DeleteVal(c.Entities, e)
+	if _, ok := e.Type().(SaveableEntityType); ok {
+		c.modified = true
+	}
 }
 
 // newColumn returns a new Column wrapper around the chunk.Chunk passed.
@@ -0,0 +1,121 @@
+// Package worldtime implements the /time command, which queries and changes the time of the world that the source of
+// the command is in.
+package worldtime
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Commands returns the /time command. The commands may be registered using cmd.Register. allow is called to check if
+// a cmd.Source may execute the commands. If nil and no cmd.Permissions were set using cmd.SetPermissions, the commands
+// may only be executed by sources that are not players, such as the console. If nil and cmd.Permissions were set, the
+// permission node of each command ('command.time') decides which sources may execute it.
+func Commands(allow func(src cmd.Source) bool) []cmd.Command {
+	if allow == nil {
+		allow = func(src cmd.Source) bool {
+			_, ok := src.(*player.Player)
+			return !ok || cmd.HasPermissions()
+		}
+	}
+	c := command{allow: allow}
+	return []cmd.Command{
+		cmd.New("time", "Queries or changes the time of the world.", nil,
+			timeQueryCommand{command: c},
+			timeSetCommand{command: c},
+			timeAddCommand{command: c},
+		),
+	}
+}
+
+// command holds the fields shared by all commands returned by Commands.
+type command struct {
+	allow func(src cmd.Source) bool
+}
+
+// Allow ...
+func (c command) Allow(src cmd.Source) bool {
+	return c.allow(src)
+}
+
+// world returns the world of the source passed. If the source is not in a world, an error is added to the output
+// and false is returned.
+func (command) world(src cmd.Source, o *cmd.Output) (*world.World, bool) {
+	w := src.World()
+	if w == nil {
+		o.Error("The time can only be queried or changed by a source that is in a world.")
+		return nil, false
+	}
+	return w, true
+}
+
+// timeQueryCommand implements the /time query command.
+type timeQueryCommand struct {
+	command
+	Query cmd.SubCommand `cmd:"query"`
+	Value queryValue     `cmd:"value"`
+}
+
+// Run ...
+func (c timeQueryCommand) Run(src cmd.Source, o *cmd.Output) {
+	w, ok := c.world(src, o)
+	if !ok {
+		return
+	}
+	switch c.Value {
+	case "daytime":
+		o.Printf("Daytime is %v", w.DayTime())
+	case "gametime":
+		o.Printf("Gametime is %v", w.GameTime())
+	case "day":
+		o.Printf("Day is %v", w.Day())
+	}
+}
+
+// timeSetCommand implements the /time set command.
+type timeSetCommand struct {
+	command
+	Set  cmd.SubCommand `cmd:"set"`
+	Time int            `cmd:"time"`
+}
+
+// Run ...
+func (c timeSetCommand) Run(src cmd.Source, o *cmd.Output) {
+	w, ok := c.world(src, o)
+	if !ok {
+		return
+	}
+	w.SetTime(c.Time)
+	o.Printf("Set the time to %v", c.Time)
+}
+
+// timeAddCommand implements the /time add command.
+type timeAddCommand struct {
+	command
+	Add    cmd.SubCommand `cmd:"add"`
+	Amount int            `cmd:"amount"`
+}
+
+// Run ...
+func (c timeAddCommand) Run(src cmd.Source, o *cmd.Output) {
+	w, ok := c.world(src, o)
+	if !ok {
+		return
+	}
+	w.SetTime(w.Time() + c.Amount)
+	o.Printf("Added %v to the time", c.Amount)
+}
+
+// queryValue is the value queried using /time query.
+type queryValue string
+
+// Type ...
+func (queryValue) Type() string {
+	return "TimeQuery"
+}
+
+// Options ...
+func (queryValue) Options(cmd.Source) []string {
+	return []string{"daytime", "gametime", "day"}
+}